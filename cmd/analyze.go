@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+// analyzePageSize is how many posts are requested per page while walking a timeline
+const analyzePageSize = 100
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [username]",
+	Short: "Show statistics about a timeline",
+	Long: `Walk a user's entire timeline and summarize it: post counts by type and
+year, media, engagement and the most used hashtags.
+
+The age, hashtag, language and media filters select posts the same way prune does, so
+analyze shows what a prune with those flags would be working on. Nothing is
+ever deleted.
+
+Use --platforms=twitter with the path to a downloaded Twitter/X archive zip
+(or TWITTER_ARCHIVE) to analyze historical Twitter posts, which can no longer
+be pruned through the API.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		maxAgeStr, _ := cmd.Flags().GetString("max-post-age")
+		beforeDateStr, _ := cmd.Flags().GetString("before-date")
+		withHashtagsStr, _ := cmd.Flags().GetString("with-hashtags")
+		withLanguagesStr, _ := cmd.Flags().GetString("language")
+		mediaOnly, _ := cmd.Flags().GetBool("media-only")
+		topHashtags, _ := cmd.Flags().GetInt("top-hashtags")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial,twitter) or 'all'\n")
+			os.Exit(1)
+		}
+
+		platforms, err := internal.ParseReadablePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		options := internal.PruneOptions{
+			WithHashtags:  internal.ParseHashtags(withHashtagsStr),
+			WithLanguages: internal.ParseLanguages(withLanguagesStr),
+			MediaOnly:     mediaOnly,
+		}
+		if maxAgeStr != "" {
+			duration, err := timespec.ParseDuration(maxAgeStr)
+			if err != nil {
+				fmt.Printf("Error parsing max-post-age: %v\n", err)
+				os.Exit(1)
+			}
+			options.MaxAge = &duration
+		}
+		if beforeDateStr != "" {
+			date, err := timespec.ParseDate(beforeDateStr)
+			if err != nil {
+				fmt.Printf("Error parsing before-date: %v\n", err)
+				os.Exit(1)
+			}
+			options.BeforeDate = &date
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+			if err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+
+			reader, exists := internal.GetReader(platformName)
+			if !exists {
+				fmt.Printf("Error: Unsupported platform '%s'. Supported platforms: %s\n",
+					platformName, strings.Join(internal.GetAllReadablePlatformNames(), ", "))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+
+			fmt.Printf("🔍 Reading posts from %s...\n", reader.GetPlatformName())
+			var matched []internal.Post
+			err = walkTimeline(ctx, reader, username, func(posts []internal.Post) {
+				now := clock.Now()
+				for _, post := range posts {
+					if matchesAnalyzeFilters(post, options, now) {
+						matched = append(matched, post)
+					}
+				}
+			})
+			if err != nil {
+				fmt.Printf("Error fetching posts from %s: %v\n", reader.GetPlatformName(), err)
+			}
+
+			fmt.Println()
+			if err := displayPostStats(os.Stdout, internal.AnalyzePosts(matched, topHashtags), reader.GetPlatformName()); err != nil {
+				exitWithError(err)
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+	},
+}
+
+// walkTimeline passes each page of a user's posts to visit, newest first, until the
+// timeline runs out. Pages read before an error have already been visited.
+func walkTimeline(ctx context.Context, reader internal.PostReader, username string, visit func([]internal.Post)) error {
+	cursor := ""
+	for {
+		posts, nextCursor, err := reader.FetchUserPostsPaginated(ctx, username, analyzePageSize, cursor)
+		if err != nil {
+			return err
+		}
+		visit(posts)
+		if len(posts) == 0 || nextCursor == "" || nextCursor == cursor {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// matchesAnalyzeFilters applies prune's age, hashtag, language and media selection to a post.
+// As with prune, a post matching either age criterion is selected; with neither set,
+// every post is. An after date narrows either of them to a window.
+func matchesAnalyzeFilters(post internal.Post, options internal.PruneOptions, now time.Time) bool {
+	if options.MaxAge != nil || options.BeforeDate != nil {
+		oldEnough := options.MaxAge != nil && now.Sub(post.CreatedAt) > *options.MaxAge
+		earlyEnough := options.BeforeDate != nil && post.CreatedAt.Before(*options.BeforeDate)
+		if !oldEnough && !earlyEnough {
+			return false
+		}
+	}
+	if options.AfterDate != nil && post.CreatedAt.Before(*options.AfterDate) {
+		return false
+	}
+	return options.MatchesHashtagFilter(post) && options.MatchesLanguageFilter(post) && options.MatchesMediaFilter(post)
+}
+
+func displayPostStats(w io.Writer, stats internal.PostStats, platform string) error {
+	out := newDisplayWriter(w)
+	w = out
+	if stats.Total == 0 {
+		fmt.Fprintf(w, "No matching posts found on %s\n", platform)
+		return out.err
+	}
+
+	fmt.Fprintf(w, "📊 Statistics for %s:\n\n", platform)
+	fmt.Fprintf(w, "  Total posts: %d\n", stats.Total)
+	fmt.Fprintf(w, "  Date range: %s to %s\n", stats.Oldest.Format("2006-01-02"), stats.Newest.Format("2006-01-02"))
+
+	fmt.Fprintf(w, "\n  By type:\n")
+	for _, postType := range []internal.PostType{
+		internal.PostTypeOriginal, internal.PostTypeReply, internal.PostTypeRepost,
+		internal.PostTypeQuote, internal.PostTypeLike,
+	} {
+		if count := stats.ByType[postType]; count > 0 {
+			fmt.Fprintf(w, "    %-9s %d\n", postType, count)
+		}
+	}
+
+	fmt.Fprintf(w, "\n  By year:\n")
+	years := make([]int, 0, len(stats.ByYear))
+	for year := range stats.ByYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	for _, year := range years {
+		fmt.Fprintf(w, "    %-9d %d\n", year, stats.ByYear[year])
+	}
+
+	fmt.Fprintf(w, "\n  With media: %d\n", stats.WithMedia)
+	fmt.Fprintf(w, "  Engagement: %d likes, %d reposts, %d replies\n", stats.Likes, stats.Reposts, stats.Replies)
+	if stats.MostLiked != nil {
+		fmt.Fprintf(w, "  Most liked: %d likes", stats.MostLiked.LikeCount)
+		if stats.MostLiked.URL != "" {
+			fmt.Fprintf(w, " (%s)", stats.MostLiked.URL)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(stats.TopHashtags) > 0 {
+		fmt.Fprintf(w, "\n  Top hashtags:\n")
+		for _, hashtag := range stats.TopHashtags {
+			fmt.Fprintf(w, "    #%s (%d)\n", hashtag.Hashtag, hashtag.Count)
+		}
+	}
+	return out.err
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial,twitter) or 'all' for all live platforms")
+	analyzeCmd.Flags().String("max-post-age", "", "Only include posts older than this (e.g., 30d, 1y, 24h)")
+	analyzeCmd.Flags().String("before-date", "", "Only include posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	analyzeCmd.Flags().String("with-hashtags", "", "Only include posts carrying at least one of these comma-separated hashtags")
+	analyzeCmd.Flags().String("language", "", "Only include posts in one of these comma-separated languages (e.g., en,de)")
+	analyzeCmd.Flags().Bool("media-only", false, "Only include posts with media attachments")
+	analyzeCmd.Flags().Int("top-hashtags", 10, "Number of most used hashtags to show")
+}
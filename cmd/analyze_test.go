@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestMatchesAnalyzeFilters(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	beforeDate := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recent := internal.Post{ID: "recent", CreatedAt: now.Add(-24 * time.Hour)}
+	old := internal.Post{ID: "old", CreatedAt: now.Add(-60 * 24 * time.Hour), Hashtags: []string{"Conf2019"}}
+	ancient := internal.Post{ID: "ancient", CreatedAt: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		Attachments: []internal.Attachment{{Type: "image"}}}
+
+	tests := []struct {
+		name    string
+		options internal.PruneOptions
+		post    internal.Post
+		want    bool
+	}{
+		{"no filters", internal.PruneOptions{}, recent, true},
+		{"too recent for max age", internal.PruneOptions{MaxAge: &maxAge}, recent, false},
+		{"older than max age", internal.PruneOptions{MaxAge: &maxAge}, old, true},
+		{"after before date", internal.PruneOptions{BeforeDate: &beforeDate}, old, false},
+		{"either age criterion", internal.PruneOptions{MaxAge: &maxAge, BeforeDate: &beforeDate}, old, true},
+		{"hashtag match", internal.PruneOptions{WithHashtags: []string{"conf2019"}}, old, true},
+		{"hashtag mismatch", internal.PruneOptions{WithHashtags: []string{"conf2019"}}, ancient, false},
+		{"media only with media", internal.PruneOptions{MediaOnly: true}, ancient, true},
+		{"media only without media", internal.PruneOptions{MediaOnly: true}, old, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAnalyzeFilters(tt.post, tt.options, now); got != tt.want {
+				t.Errorf("matchesAnalyzeFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,17 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
 	"github.com/spf13/cobra"
@@ -23,31 +31,43 @@ Guides you through obtaining the necessary API keys, app passwords, and access
 tokens required for authenticated operations like post deletion. Provides 
 step-by-step instructions and URLs for each platform's authentication process.
 
-Supports credential storage both as environment variables and in local config files.`,
+For Mastodon, CringeSweeper registers itself as an OAuth application on your
+instance and opens your browser to approve it, then saves the resulting token.
+For Bluesky, you can log in through your browser with OAuth in the same way, or
+use an app password. Use --no-browser to finish in the terminal instead, e.g.
+over SSH: paste the Mastodon authorization code, or the URL Bluesky redirects to.
+
+Supports credential storage both as environment variables and in local config files.
+Use --status to see which credentials are found, and add --verify to log in to
+each platform with them and show the account's post count and creation date.`,
 	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		platformsStr, _ := cmd.Flags().GetString("platforms")
 		status, _ := cmd.Flags().GetBool("status")
+		noBrowser, _ := cmd.Flags().GetBool("no-browser")
+		verify, _ := cmd.Flags().GetBool("verify")
 
 		// Handle status flag - always show all platforms when --status is used
 		if status {
-			showCredentialStatus("all")
+			showCredentialStatus(cmd.Context(), cmd.OutOrStdout(), "all", verify)
 			return
 		}
+		if verify {
+			exitWithError(fmt.Errorf("--verify can only be used with --status"))
+		}
 
 		// Determine which platforms to use
 		var platforms []string
 		var err error
 		
 		if platformsStr == "" {
-			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon) or 'all'\n")
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
 			os.Exit(1)
 		}
 		
 		platforms, err = internal.ParsePlatforms(platformsStr)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 
 		// Process each platform sequentially (auth is interactive)
@@ -73,15 +93,15 @@ Supports credential storage both as environment variables and in local config fi
 			var authErr error
 			switch platformName {
 			case "bluesky":
-				authErr = setupBlueskyAuth()
-			case "mastodon":
-				authErr = setupMastodonAuth()
+				authErr = setupBlueskyAuth(cmd.Context(), noBrowser)
+			case "mastodon", "gotosocial":
+				authErr = setupMastodonAuth(cmd.Context(), platformName, client.GetPlatformName(), noBrowser)
 			default:
 				authErr = fmt.Errorf("authentication not implemented for platform: %s", platformName)
 			}
 
 			if authErr != nil {
-				fmt.Printf("Error setting up authentication for %s: %v\n", platformName, authErr)
+				presentError(os.Stdout, fmt.Errorf("setting up authentication for %s: %w", platformName, authErr))
 				if len(platforms) > 1 {
 					fmt.Printf("Skipping %s and continuing with other platforms...\n", platformName)
 					continue
@@ -109,12 +129,25 @@ Supports credential storage both as environment variables and in local config fi
 	},
 }
 
-func setupBlueskyAuth() error {
+func setupBlueskyAuth(ctx context.Context, noBrowser bool) error {
 	fmt.Println("🔐 Bluesky Authentication Setup")
 	fmt.Println("===============================")
 	fmt.Println()
-	fmt.Println("Bluesky uses app passwords for API access.")
-	fmt.Println("You'll need to create an app password in your Bluesky settings.")
+	fmt.Println("CringeSweeper can log in to Bluesky through your browser with OAuth,")
+	fmt.Println("or use an app password created in your Bluesky settings.")
+	fmt.Println()
+
+	// Get username
+	fmt.Print("Enter your Bluesky username (e.g., user.bsky.social): ")
+	username := strings.TrimSpace(readInput())
+	if username == "" {
+		return fmt.Errorf("username is required")
+	}
+
+	fmt.Print("Log in through your browser with OAuth? Answer n to use an app password instead (y/n): ")
+	if askYesNo() {
+		return setupBlueskyOAuth(ctx, username, noBrowser)
+	}
 	fmt.Println()
 
 	fmt.Println("Steps to create an app password:")
@@ -125,13 +158,6 @@ func setupBlueskyAuth() error {
 	fmt.Println("5. Copy the generated app password")
 	fmt.Println()
 
-	// Get username
-	fmt.Print("Enter your Bluesky username (e.g., user.bsky.social): ")
-	username := strings.TrimSpace(readInput())
-	if username == "" {
-		return fmt.Errorf("username is required")
-	}
-
 	// Get app password
 	fmt.Print("Enter your app password: ")
 	appPassword := strings.TrimSpace(readInput())
@@ -139,11 +165,21 @@ func setupBlueskyAuth() error {
 		return fmt.Errorf("app password is required")
 	}
 
+	// Self-hosted accounts log in to their own PDS
+	fmt.Print("Enter your PDS if it isn't bsky.social (e.g., pds.example.com), or press Enter: ")
+	pds, err := internal.NormalizeBlueskyHost(readInput())
+	if err != nil {
+		return fmt.Errorf("invalid PDS: %w", err)
+	}
+
 	// Store credentials
 	fmt.Println()
 	fmt.Println("Setting environment variables...")
 	fmt.Printf("export BLUESKY_USER=\"%s\"\n", username)
 	fmt.Printf("export BLUESKY_PASSWORD=\"%s\"\n", appPassword)
+	if pds != "" {
+		fmt.Printf("export %s=\"%s\"\n", internal.BlueskyPDSEnvVar, pds)
+	}
 	fmt.Println()
 
 	// Optionally save to config file
@@ -156,6 +192,7 @@ func setupBlueskyAuth() error {
 			creds := &internal.Credentials{
 				Platform:    "bluesky",
 				Username:    username,
+				Instance:    pds,
 				AppPassword: appPassword,
 			}
 			if err := authManager.SaveCredentials(creds); err != nil {
@@ -171,16 +208,79 @@ func setupBlueskyAuth() error {
 	return nil
 }
 
-func setupMastodonAuth() error {
-	fmt.Println("🔐 Mastodon Authentication Setup")
-	fmt.Println("================================")
+// setupBlueskyOAuth logs in with ATProto OAuth. CringeSweeper is a loopback client, so
+// the authorization server always redirects to 127.0.0.1; without a browser on this
+// machine, the user pastes that redirect URL back in instead.
+func setupBlueskyOAuth(ctx context.Context, handle string, noBrowser bool) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start a local callback server: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	fmt.Println()
+	fmt.Println("Looking up your account's authorization server...")
+	flow, err := internal.StartBlueskyOAuth(ctx, handle, redirectURI)
+	if err != nil {
+		return fmt.Errorf("failed to start OAuth login: %w", err)
+	}
+
+	var result oauthCallbackResult
+	if noBrowser {
+		listener.Close()
+		fmt.Println()
+		fmt.Println("Open this URL in a browser, log in and approve CringeSweeper:")
+		fmt.Printf("  %s\n", flow.AuthorizeURL())
+		fmt.Println()
+		fmt.Println("Your browser will then fail to load a page on 127.0.0.1, which is expected.")
+		fmt.Print("Paste the full URL from its address bar: ")
+		result, err = parseOAuthRedirect(readInput(), flow.State())
+	} else {
+		result, err = awaitOAuthCallback(ctx, "bluesky", listener, flow.State(), flow.AuthorizeURL())
+	}
+	if err != nil {
+		return err
+	}
+
+	creds, err := flow.Exchange(ctx, result.code, result.issuer)
+	if err != nil {
+		return fmt.Errorf("failed to get tokens: %w", err)
+	}
+	fmt.Printf("✅ Authorized as @%s\n", creds.Username)
+	fmt.Println()
+
+	// OAuth tokens only live in the config file, as they are refreshed during runs
+	authManager, err := internal.NewAuthManager()
+	if err != nil {
+		return fmt.Errorf("could not create auth manager: %w", err)
+	}
+	if err := authManager.SaveCredentials(creds); err != nil {
+		return fmt.Errorf("could not save credentials: %w", err)
+	}
+	fmt.Println("✅ Credentials saved to ~/.config/cringesweeper/bluesky.json")
+	fmt.Println("💡 Server mode reads credentials from the environment, so it still needs an app password.")
+
+	return nil
+}
+
+// setupMastodonAuth logs in to Mastodon, or to a server speaking its API such as
+// GoToSocial; platform is the credentials' platform and name how it's shown
+func setupMastodonAuth(ctx context.Context, platform, name string, noBrowser bool) error {
+	fmt.Printf("🔐 %s Authentication Setup\n", name)
+	fmt.Println(strings.Repeat("=", len(name)+24))
 	fmt.Println()
-	fmt.Println("Mastodon uses OAuth2 for authentication.")
-	fmt.Println("You'll need to register an application on your Mastodon instance.")
+	fmt.Printf("%s uses OAuth2 for authentication.\n", name)
+	fmt.Println("CringeSweeper will register itself as an application on your instance,")
+	fmt.Println("then ask you to approve it in your browser.")
 	fmt.Println()
 
 	// Get instance
-	fmt.Print("Enter your Mastodon instance (e.g., mastodon.social): ")
+	example := "mastodon.social"
+	if platform == "gotosocial" {
+		example = "gts.example.org"
+	}
+	fmt.Printf("Enter your %s instance (e.g., %s): ", name, example)
 	instance := strings.TrimSpace(readInput())
 	if instance == "" {
 		return fmt.Errorf("instance is required")
@@ -191,74 +291,219 @@ func setupMastodonAuth() error {
 		instance = "https://" + instance
 	}
 
-	instanceURL := instance
-	settingsURL := fmt.Sprintf("%s/settings/applications", instanceURL)
+	instanceURL := strings.TrimSuffix(instance, "/")
 
 	fmt.Printf("Instance: %s\n", instanceURL)
 	fmt.Println()
 
-	fmt.Println("Steps to create an application:")
-	fmt.Printf("1. Go to %s\n", settingsURL)
-	fmt.Println("2. Log in to your Mastodon account")
-	fmt.Println("3. Click 'New Application'")
-	fmt.Println("4. Fill in the application details:")
-	fmt.Println("   - Application name: CringeSweeper")
-	fmt.Println("   - Application website: https://github.com/gerrowadat/cringesweeper")
-	fmt.Println("   - Redirect URI: urn:ietf:wg:oauth:2.0:oob")
-	fmt.Println("5. Required scopes: read, write")
-	fmt.Println("6. Click 'Submit'")
-	fmt.Println("7. Copy the access token from the application details")
-	fmt.Println()
+	app, code, err := authorizeMastodon(ctx, instanceURL, noBrowser)
+	if err != nil {
+		return err
+	}
 
-	// Get username
-	fmt.Print("Enter your Mastodon username (without @): ")
-	username := strings.TrimSpace(readInput())
-	if username == "" {
-		return fmt.Errorf("username is required")
+	accessToken, err := app.ExchangeCode(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to get an access token: %w", err)
 	}
 
-	// Get access token
-	fmt.Print("Enter your access token: ")
-	accessToken := strings.TrimSpace(readInput())
-	if accessToken == "" {
-		return fmt.Errorf("access token is required")
+	username, err := internal.VerifyMastodonToken(ctx, instanceURL, accessToken)
+	if err != nil {
+		return err
 	}
+	fmt.Printf("✅ Authorized as @%s\n", username)
 
 	// Store credentials
 	fmt.Println()
 	fmt.Println("Setting environment variables...")
 	fullUsername := fmt.Sprintf("%s@%s", username, strings.TrimPrefix(instanceURL, "https://"))
-	fmt.Printf("export MASTODON_USER=\"%s\"\n", fullUsername)
-	fmt.Printf("export MASTODON_INSTANCE=\"%s\"\n", instanceURL)
-	fmt.Printf("export MASTODON_ACCESS_TOKEN=\"%s\"\n", accessToken)
+	envPrefix := strings.ToUpper(platform)
+	fmt.Printf("export %s_USER=\"%s\"\n", envPrefix, fullUsername)
+	fmt.Printf("export %s_INSTANCE=\"%s\"\n", envPrefix, instanceURL)
+	fmt.Printf("export %s_ACCESS_TOKEN=\"%s\"\n", envPrefix, accessToken)
 	fmt.Println()
 
-	// Optionally save to config file
-	fmt.Print("Would you like to save these credentials to ~/.config/cringesweeper? (y/n): ")
-	if askYesNo() {
-		authManager, err := internal.NewAuthManager()
-		if err != nil {
-			fmt.Printf("Warning: Could not create auth manager: %v\n", err)
+	// The token was issued to us, so there's no reason not to keep it
+	authManager, err := internal.NewAuthManager()
+	if err != nil {
+		fmt.Printf("Warning: Could not create auth manager: %v\n", err)
+	} else {
+		creds := &internal.Credentials{
+			Platform:    platform,
+			Username:    fullUsername,
+			Instance:    instanceURL,
+			AccessToken: accessToken,
+			ExtraData: map[string]string{
+				"client_id":     app.ClientID,
+				"client_secret": app.ClientSecret,
+			},
+		}
+		if err := authManager.SaveCredentials(creds); err != nil {
+			fmt.Printf("Warning: Could not save credentials: %v\n", err)
 		} else {
-			creds := &internal.Credentials{
-				Platform:    "mastodon",
-				Username:    fullUsername,
-				Instance:    instanceURL,
-				AccessToken: accessToken,
-			}
-			if err := authManager.SaveCredentials(creds); err != nil {
-				fmt.Printf("Warning: Could not save credentials: %v\n", err)
-			} else {
-				fmt.Println("✅ Credentials saved to ~/.config/cringesweeper/mastodon.json")
-			}
+			fmt.Printf("✅ Credentials saved to ~/.config/cringesweeper/%s.json\n", platform)
 		}
 	}
 
-	fmt.Println("💡 Add the export commands to your shell profile (.bashrc, .zshrc, etc.) to persist them.")
+	fmt.Println("💡 The export commands are only needed for server mode, which reads credentials from the environment.")
 
 	return nil
 }
 
+// oauthAuthTimeout bounds how long we wait for the user to approve CringeSweeper in their browser
+const oauthAuthTimeout = 5 * time.Minute
+
+// openBrowser opens a URL in the user's browser; swapped out in tests
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// authorizeMastodon registers an app on the instance and has the user approve it,
+// returning the app and the authorization code. The code comes back to a local callback
+// server when a browser is available, or is pasted in by the user otherwise.
+func authorizeMastodon(ctx context.Context, instanceURL string, noBrowser bool) (*internal.MastodonApp, string, error) {
+	var listener net.Listener
+	redirectURI := internal.MastodonOOBRedirectURI
+	if !noBrowser {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			fmt.Printf("⚠️  Couldn't start a local callback server (%v), falling back to pasting the code\n", err)
+		} else {
+			defer listener.Close()
+			redirectURI = fmt.Sprintf("http://%s/callback", listener.Addr())
+		}
+	}
+
+	fmt.Println("Registering CringeSweeper with your instance...")
+	app, err := internal.RegisterMastodonApp(ctx, instanceURL, redirectURI)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to register application: %w", err)
+	}
+
+	state, err := internal.NewOAuthState()
+	if err != nil {
+		return nil, "", err
+	}
+	authorizeURL := app.AuthorizeURL(state)
+
+	if listener == nil {
+		fmt.Println()
+		fmt.Println("Open this URL in a browser, log in and approve CringeSweeper:")
+		fmt.Printf("  %s\n", authorizeURL)
+		fmt.Println()
+		fmt.Print("Enter the authorization code shown after approving: ")
+		code := strings.TrimSpace(readInput())
+		if code == "" {
+			return nil, "", fmt.Errorf("authorization code is required")
+		}
+		return app, code, nil
+	}
+
+	result, err := awaitOAuthCallback(ctx, "mastodon", listener, state, authorizeURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return app, result.code, nil
+}
+
+// awaitOAuthCallback opens the authorization page in the user's browser and serves the
+// redirect back to the listener, returning the code once the user approves
+func awaitOAuthCallback(ctx context.Context, platform string, listener net.Listener, state, authorizeURL string) (oauthCallbackResult, error) {
+	results := make(chan oauthCallbackResult, 1)
+	server := &http.Server{Handler: oauthCallbackHandler(state, results)}
+	go server.Serve(listener)
+	defer server.Close()
+
+	fmt.Println("Opening your browser to approve CringeSweeper...")
+	fmt.Printf("If it doesn't open, visit this URL:\n  %s\n", authorizeURL)
+	if err := openBrowser(authorizeURL); err != nil {
+		logger := internal.WithPlatform(platform)
+		logger.Debug().Err(err).Msg("Failed to open browser")
+	}
+	fmt.Println()
+	fmt.Println("Waiting for approval...")
+
+	select {
+	case result := <-results:
+		return result, result.err
+	case <-clock.After(oauthAuthTimeout):
+		return oauthCallbackResult{}, fmt.Errorf("timed out after %s waiting for approval; run with --no-browser to finish in the terminal instead", oauthAuthTimeout)
+	case <-ctx.Done():
+		return oauthCallbackResult{}, ctx.Err()
+	}
+}
+
+type oauthCallbackResult struct {
+	code   string
+	issuer string // The iss parameter, which ATProto authorization servers include
+	err    error
+}
+
+// readOAuthCallback extracts the outcome from an OAuth redirect's query. A refusal is
+// reported in the result; an error means the redirect isn't a usable response to our request.
+func readOAuthCallback(query url.Values, state string) (oauthCallbackResult, error) {
+	switch {
+	case query.Get("state") != state:
+		return oauthCallbackResult{}, fmt.Errorf("this authorization response doesn't match the request CringeSweeper made")
+	case query.Get("error") != "":
+		return oauthCallbackResult{err: fmt.Errorf("authorization was refused: %s", query.Get("error"))}, nil
+	case query.Get("code") == "":
+		return oauthCallbackResult{}, fmt.Errorf("the authorization response has no code")
+	}
+	return oauthCallbackResult{code: query.Get("code"), issuer: query.Get("iss")}, nil
+}
+
+// oauthCallbackHandler receives the redirect back from the authorization page, passing
+// on the code if the state matches the request we made
+func oauthCallbackHandler(state string, results chan<- oauthCallbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		result, err := readOAuthCallback(r.URL.Query(), state)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if result.err != nil {
+			fmt.Fprintln(w, "CringeSweeper was not authorized. You can close this window.")
+		} else {
+			fmt.Fprintln(w, "CringeSweeper is authorized. You can close this window and return to the terminal.")
+		}
+
+		// Only the first response counts
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	return mux
+}
+
+// parseOAuthRedirect reads the outcome from a redirect URL pasted in by the user
+func parseOAuthRedirect(pasted, state string) (oauthCallbackResult, error) {
+	pasted = strings.TrimSpace(pasted)
+	if pasted == "" {
+		return oauthCallbackResult{}, fmt.Errorf("the redirect URL is required")
+	}
+	redirect, err := url.Parse(pasted)
+	if err != nil {
+		return oauthCallbackResult{}, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	result, err := readOAuthCallback(redirect.Query(), state)
+	if err != nil {
+		return oauthCallbackResult{}, err
+	}
+	return result, result.err
+}
+
 func askYesNo() bool {
 	reader := bufio.NewReader(os.Stdin)
 	for {
@@ -287,81 +532,113 @@ func readInput() string {
 	return strings.TrimSpace(input)
 }
 
-func showCredentialStatus(platform string) {
+func showCredentialStatus(ctx context.Context, w io.Writer, platform string, verify bool) {
 	if platform != "all" {
 		// Show status for specific platform
-		showPlatformStatus(platform)
+		showPlatformStatus(ctx, w, platform, verify)
 		return
 	}
 
 	// Show status for all supported platforms
-	fmt.Println("📋 Credential Status Summary")
-	fmt.Println("============================")
-	fmt.Println()
+	fmt.Fprintln(w, "📋 Credential Status Summary")
+	fmt.Fprintln(w, "============================")
+	fmt.Fprintln(w)
 
 	// Get all supported platforms from the internal registry
-	supportedPlatforms := []string{"bluesky", "mastodon"}
+	supportedPlatforms := []string{"bluesky", "mastodon", "gotosocial"}
 
 	for i, p := range supportedPlatforms {
 		if i > 0 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
-		showPlatformStatus(p)
+		showPlatformStatus(ctx, w, p, verify)
 	}
 }
 
-func showPlatformStatus(platform string) {
-	fmt.Printf("Platform: %s\n", platform)
-	fmt.Printf("─────────%s\n", strings.Repeat("─", len(platform)))
+// showPlatformStatus reports the credentials found for a platform and which are in use.
+// With verify, the active credentials are also checked with a live API call.
+func showPlatformStatus(ctx context.Context, w io.Writer, platform string, verify bool) {
+	fmt.Fprintf(w, "Platform: %s\n", platform)
+	fmt.Fprintf(w, "─────────%s\n", strings.Repeat("─", len(platform)))
 
 	// Check saved credentials
 	authManager, err := internal.NewAuthManager()
 	if err != nil {
-		fmt.Printf("❌ Error accessing credential storage: %v\n", err)
+		fmt.Fprintf(w, "❌ Error accessing credential storage: %v\n", err)
 		return
 	}
 
 	creds, err := authManager.LoadCredentials(platform)
 	if err != nil {
-		fmt.Printf("❌ No saved credentials found\n")
+		fmt.Fprintf(w, "❌ No saved credentials found\n")
 	} else {
-		fmt.Printf("✅ Saved credentials found\n")
-		fmt.Printf("   Username: %s\n", creds.Username)
+		fmt.Fprintf(w, "✅ Saved credentials found\n")
+		fmt.Fprintf(w, "   Username: %s\n", creds.Username)
 		if creds.Instance != "" {
-			fmt.Printf("   Instance: %s\n", creds.Instance)
+			fmt.Fprintf(w, "   Instance: %s\n", creds.Instance)
+		}
+		if creds.UsesOAuth() {
+			fmt.Fprintf(w, "   Login: OAuth\n")
 		}
 
 		// Validate credentials
 		if err := internal.ValidateCredentials(creds); err != nil {
-			fmt.Printf("⚠️  Credentials incomplete: %v\n", err)
+			fmt.Fprintf(w, "⚠️  Credentials incomplete: %v\n", err)
 		} else {
-			fmt.Printf("✅ Credentials complete and valid\n")
+			fmt.Fprintf(w, "✅ Credentials complete and valid\n")
 		}
 	}
 
 	// Check environment variables
 	envCreds := internal.GetCredentialsFromEnv(platform)
 	if envCreds != nil {
-		fmt.Printf("✅ Environment variables found\n")
+		fmt.Fprintf(w, "✅ Environment variables found\n")
 		if err := internal.ValidateCredentials(envCreds); err != nil {
-			fmt.Printf("⚠️  Environment credentials incomplete: %v\n", err)
+			fmt.Fprintf(w, "⚠️  Environment credentials incomplete: %v\n", err)
 		}
 	} else {
-		fmt.Printf("❌ No environment variables found\n")
+		fmt.Fprintf(w, "❌ No environment variables found\n")
 	}
 
 	// Show what credentials would be used
 	finalCreds, err := internal.GetCredentialsForPlatform(platform)
 	if err != nil {
-		fmt.Printf("❌ No usable credentials available\n")
-		fmt.Printf("   Run 'cringesweeper auth --platforms=%s' to set up authentication\n", platform)
+		fmt.Fprintf(w, "❌ No usable credentials available\n")
+		fmt.Fprintf(w, "   Run 'cringesweeper auth --platforms=%s' to set up authentication\n", platform)
 	} else {
-		fmt.Printf("🎯 Active credentials: %s\n", finalCreds.Username)
+		fmt.Fprintf(w, "🎯 Active credentials: %s\n", finalCreds.Username)
+		if mismatch := internal.CheckCredentialMismatch(platform); mismatch != nil {
+			fmt.Fprintf(w, "⚠️  Environment variables are for a different account (%s) and are being ignored\n", mismatch.EnvUser)
+		}
+		if verify {
+			showVerifiedAccount(ctx, w, platform)
+		}
+	}
+}
+
+// showVerifiedAccount logs in to the platform with the active credentials and reports
+// the account the platform says they belong to
+func showVerifiedAccount(ctx context.Context, w io.Writer, platform string) {
+	account, err := verifyAccount(ctx, platform)
+	if err != nil {
+		presentError(w, fmt.Errorf("live verification failed: %w", err))
+		return
+	}
+	fmt.Fprintf(w, "✅ Verified with %s\n", platform)
+	fmt.Fprintf(w, "   Account: %s\n", account.Username)
+	fmt.Fprintf(w, "   Posts: %d\n", account.PostCount)
+	if !account.CreatedAt.IsZero() {
+		fmt.Fprintf(w, "   Created: %s\n", account.CreatedAt.Format("2006-01-02"))
+	}
+	if !account.MatchesCredentials {
+		fmt.Fprintf(w, "⚠️  The credentials are for %s, not %s\n", account.Username, account.CredentialsUsername)
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(authCmd)
-	authCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon) or 'all' for all platforms")
+	authCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
 	authCmd.Flags().Bool("status", false, "Show credential status instead of setting up authentication")
+	authCmd.Flags().Bool("verify", false, "With --status, log in to each platform to check the credentials work and show the account's post count and creation date")
+	authCmd.Flags().Bool("no-browser", false, "Print the OAuth authorization URL and paste the result back instead of opening a browser (e.g., over SSH)")
 }
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+
+	"github.com/gerrowadat/cringesweeper/internal"
 )
 
 func TestAskYesNo(t *testing.T) {
@@ -202,9 +210,7 @@ func TestShowCredentialStatus(t *testing.T) {
 				}
 			}()
 
-			// This would require capturing stdout to fully test
-			// For now we just ensure it doesn't crash
-			showCredentialStatus(tt.platform)
+			showCredentialStatus(context.Background(), io.Discard, tt.platform, false)
 		})
 	}
 }
@@ -228,11 +234,114 @@ func TestShowPlatformStatus(t *testing.T) {
 				}
 			}()
 
-			showPlatformStatus(tt.platform)
+			showPlatformStatus(context.Background(), io.Discard, tt.platform, false)
 		})
 	}
 }
 
+func TestShowPlatformStatusOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLUESKY_USER", "env.bsky.social")
+	t.Setenv("BLUESKY_PASSWORD", "app-password")
+
+	var buf bytes.Buffer
+	showPlatformStatus(context.Background(), &buf, "bluesky", false)
+	output := buf.String()
+
+	expected := []string{
+		"Platform: bluesky",
+		"❌ No saved credentials found",
+		"✅ Environment variables found",
+		"🎯 Active credentials: env.bsky.social",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestShowPlatformStatusCredentialMismatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLUESKY_USER", "env.bsky.social")
+	t.Setenv("BLUESKY_PASSWORD", "app-password")
+
+	authManager, err := internal.NewAuthManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := authManager.SaveCredentials(&internal.Credentials{Platform: "bluesky", Username: "saved.bsky.social", AppPassword: "other-password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	showPlatformStatus(context.Background(), &buf, "bluesky", false)
+	output := buf.String()
+
+	for _, want := range []string{
+		"🎯 Active credentials: saved.bsky.social",
+		"⚠️  Environment variables are for a different account (env.bsky.social) and are being ignored",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestShowPlatformStatusVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id": "1", "username": "alice", "statuses_count": 321, "created_at": "2021-06-30T08:00:00.000Z"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_USER", "alice")
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+
+	tests := []struct {
+		name  string
+		token string
+		want  []string
+	}{
+		{"working token", "good-token", []string{"✅ Verified with mastodon", "   Posts: 321", "   Created: 2021-06-30"}},
+		{"revoked token", "revoked-token", []string{"live verification failed", "401"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MASTODON_ACCESS_TOKEN", tt.token)
+
+			var buf bytes.Buffer
+			showPlatformStatus(context.Background(), &buf, "mastodon", true)
+			output := buf.String()
+
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestShowCredentialStatusAllPlatforms(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var buf bytes.Buffer
+	showCredentialStatus(context.Background(), &buf, "all", false)
+	output := buf.String()
+
+	for _, want := range []string{"Credential Status Summary", "Platform: bluesky", "Platform: mastodon"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestSetupBlueskyAuthValidation(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -340,3 +449,108 @@ func TestFullUsernameGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestOAuthCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCode   string
+		wantErr    bool
+	}{
+		{"approved", "state=s1&code=abc", http.StatusOK, "abc", false},
+		{"refused", "state=s1&error=access_denied", http.StatusOK, "", true},
+		{"wrong state", "state=other&code=abc", http.StatusBadRequest, "", false},
+		{"missing code", "state=s1", http.StatusBadRequest, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(chan oauthCallbackResult, 1)
+			recorder := httptest.NewRecorder()
+			oauthCallbackHandler("s1", results).ServeHTTP(recorder, httptest.NewRequest("GET", "/callback?"+tt.query, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			select {
+			case result := <-results:
+				if result.code != tt.wantCode || (result.err != nil) != tt.wantErr {
+					t.Errorf("Unexpected result %+v", result)
+				}
+			default:
+				if tt.wantStatus == http.StatusOK {
+					t.Error("Expected a result to be delivered")
+				}
+			}
+		})
+	}
+}
+
+func TestParseOAuthRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		pasted     string
+		wantCode   string
+		wantIssuer string
+		wantErr    bool
+	}{
+		{"approved", "http://127.0.0.1:1234/callback?state=s1&code=abc&iss=https%3A%2F%2Fbsky.social", "abc", "https://bsky.social", false},
+		{"surrounding whitespace", "  http://127.0.0.1:1234/callback?state=s1&code=abc\n", "abc", "", false},
+		{"refused", "http://127.0.0.1:1234/callback?state=s1&error=access_denied", "", "", true},
+		{"wrong state", "http://127.0.0.1:1234/callback?state=other&code=abc", "", "", true},
+		{"empty", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseOAuthRedirect(tt.pasted, "s1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if result.code != tt.wantCode || result.issuer != tt.wantIssuer {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestAuthorizeMastodonWithBrowser(t *testing.T) {
+	instance := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/apps" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"client_id": "id123", "client_secret": "secret456"}`)
+	}))
+	defer instance.Close()
+
+	// Play the part of the user approving the app in their browser
+	previous := openBrowser
+	t.Cleanup(func() { openBrowser = previous })
+	openBrowser = func(authorizeURL string) error {
+		parsed, err := url.Parse(authorizeURL)
+		if err != nil {
+			return err
+		}
+		query := parsed.Query()
+		callback := query.Get("redirect_uri") + "?code=abc&state=" + url.QueryEscape(query.Get("state"))
+		go func() {
+			if resp, err := http.Get(callback); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	app, code, err := authorizeMastodon(context.Background(), instance.URL, false)
+	if err != nil {
+		t.Fatalf("authorizeMastodon failed: %v", err)
+	}
+	if code != "abc" {
+		t.Errorf("Expected code abc, got %q", code)
+	}
+	if !strings.HasPrefix(app.RedirectURI, "http://127.0.0.1:") || !strings.HasSuffix(app.RedirectURI, "/callback") {
+		t.Errorf("Expected a loopback redirect URI, got %q", app.RedirectURI)
+	}
+}
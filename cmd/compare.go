@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// againstPrefix is the prefix of compare's flags for its second set of criteria
+const againstPrefix = "against"
+
+// pruneActionOrder is the order actions are counted and listed in
+var pruneActionOrder = []string{"delete", "redact", "unlike", "unshare"}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [username]",
+	Short: "Show how two sets of prune criteria differ in what they'd remove",
+	Long: `Dry-run a prune with two sets of criteria and show the difference: how many
+posts each would delete, unlike or unshare, and which posts only one of them
+would touch. Nothing is ever deleted.
+
+The first set is given with the usual prune flags. The second is the same,
+except for any flag given again as --against.<flag>. For example, to see what
+keeping six months of posts rather than three would save:
+
+  cringesweeper compare --platforms=bluesky --max-post-age=90d --against.max-post-age=180d
+
+Each set is a separate dry run, so without --from-index the timeline is
+walked twice.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		continueUntilEnd, _ := cmd.Flags().GetBool("continue")
+		fromIndex, _ := cmd.Flags().GetBool("from-index")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		changes := againstChanges(cmd)
+		if len(changes) == 0 {
+			exitWithError(fmt.Errorf("nothing to compare: give at least one --%s.<flag>, such as --%s.max-post-age=180d", againstPrefix, againstPrefix))
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			err := compareCriteria(ctx, cmd, platformName, argUsername, changes, continueUntilEnd, fromIndex)
+			if err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+	},
+}
+
+// compareCriteria dry-runs a prune on one platform with the command's criteria and with
+// the --against ones, and shows how they differ
+func compareCriteria(ctx context.Context, cmd *cobra.Command, platformName, argUsername string, changes []string, continueUntilEnd, fromIndex bool) error {
+	first, err := platformPruneOptions(cmd, platformName)
+	if err != nil {
+		return err
+	}
+	second, err := pruneOptionsFromFlags(platformFlags{cmd: cmd, platform: againstPrefix}, platformName)
+	if err != nil {
+		return err
+	}
+
+	username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+	if err != nil {
+		return err
+	}
+	client, exists := internal.GetClient(platformName)
+	if !exists {
+		return fmt.Errorf("unsupported platform '%s'. Supported platforms: %s",
+			platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+	}
+
+	var results []*internal.PruneResult
+	for i, options := range []internal.PruneOptions{first, second} {
+		options.DryRun = true
+		options.FromIndex = fromIndex
+		options.ContinueUntilEnd = continueUntilEnd
+		fmt.Printf("🔍 Finding posts matching criteria %s on %s...\n", []string{"A", "B"}[i], client.GetPlatformName())
+		result, err := client.PrunePosts(ctx, username, options)
+		if err != nil {
+			return fmt.Errorf("dry run on %s: %w", client.GetPlatformName(), err)
+		}
+		results = append(results, result)
+	}
+
+	fmt.Println()
+	return displayCriteriaDiff(cmd.OutOrStdout(), client.GetPlatformName(), changes, diffPruneResults(results[0], results[1]))
+}
+
+// againstChanges returns the --against flags that were given, as they'd be typed
+func againstChanges(cmd *cobra.Command) []string {
+	var changes []string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if strings.HasPrefix(flag.Name, againstPrefix+".") {
+			changes = append(changes, fmt.Sprintf("--%s=%s", strings.TrimPrefix(flag.Name, againstPrefix+"."), flag.Value.String()))
+		}
+	})
+	return changes
+}
+
+// plannedPost is a post a dry run would act on, and the action
+type plannedPost struct {
+	post   internal.Post
+	action string
+	from   string // The action under criteria A, for a post B acts on differently
+}
+
+// criteriaDiff is how the dry runs under two sets of criteria, A and B, differ
+type criteriaDiff struct {
+	countsA, countsB map[string]int // Posts each would act on, by action
+	onlyA, onlyB     []plannedPost  // Posts only one of them would act on
+	changed          []plannedPost  // Posts both act on differently, with B's action
+}
+
+// plannedActions returns what a dry run would do to each post, by post ID
+func plannedActions(result *internal.PruneResult) map[string]plannedPost {
+	planned := make(map[string]plannedPost)
+	for i, posts := range [][]internal.Post{result.PostsToDelete, result.PostsToRedact, result.PostsToUnlike, result.PostsToUnshare} {
+		for _, post := range posts {
+			planned[post.ID] = plannedPost{post: post, action: pruneActionOrder[i]}
+		}
+	}
+	return planned
+}
+
+// diffPruneResults compares the dry runs under criteria A and B. Posts are listed newest
+// first.
+func diffPruneResults(a, b *internal.PruneResult) criteriaDiff {
+	plannedA, plannedB := plannedActions(a), plannedActions(b)
+	diff := criteriaDiff{countsA: make(map[string]int), countsB: make(map[string]int)}
+	for id, planned := range plannedA {
+		diff.countsA[planned.action]++
+		other, ok := plannedB[id]
+		if !ok {
+			diff.onlyA = append(diff.onlyA, planned)
+		} else if other.action != planned.action {
+			other.from = planned.action
+			diff.changed = append(diff.changed, other)
+		}
+	}
+	for id, planned := range plannedB {
+		diff.countsB[planned.action]++
+		if _, ok := plannedA[id]; !ok {
+			diff.onlyB = append(diff.onlyB, planned)
+		}
+	}
+
+	for _, posts := range [][]plannedPost{diff.onlyA, diff.onlyB, diff.changed} {
+		sort.SliceStable(posts, func(i, j int) bool {
+			if !posts[i].post.CreatedAt.Equal(posts[j].post.CreatedAt) {
+				return posts[i].post.CreatedAt.After(posts[j].post.CreatedAt)
+			}
+			return posts[i].post.ID < posts[j].post.ID
+		})
+	}
+	return diff
+}
+
+func displayCriteriaDiff(w io.Writer, platform string, changes []string, diff criteriaDiff) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, "📊 Comparing prune criteria on %s:\n", platform)
+	fmt.Fprintf(w, "  A: the criteria given\n")
+	fmt.Fprintf(w, "  B: the same with %s\n\n", strings.Join(changes, " "))
+
+	fmt.Fprintf(w, "  %-8s %7s %7s %7s\n", "", "A", "B", "B-A")
+	totalA, totalB := 0, 0
+	for _, action := range pruneActionOrder {
+		countA, countB := diff.countsA[action], diff.countsB[action]
+		totalA += countA
+		totalB += countB
+		if countA > 0 || countB > 0 {
+			fmt.Fprintf(w, "  %-8s %7d %7d %+7d\n", action, countA, countB, countB-countA)
+		}
+	}
+	fmt.Fprintf(w, "  %-8s %7d %7d %+7d\n", "total", totalA, totalB, totalB-totalA)
+
+	if len(diff.onlyA) == 0 && len(diff.onlyB) == 0 && len(diff.changed) == 0 {
+		fmt.Fprintf(w, "\nBoth sets of criteria would act on exactly the same posts.\n")
+		return out.err
+	}
+	if err := displayPlannedPosts(w, "Only under A", diff.onlyA); err != nil {
+		return err
+	}
+	if err := displayPlannedPosts(w, "Only under B", diff.onlyB); err != nil {
+		return err
+	}
+	return displayPlannedPosts(w, "Acted on differently (A → B)", diff.changed)
+}
+
+// displayPlannedPosts lists posts under a heading, with what happens to each
+func displayPlannedPosts(w io.Writer, heading string, posts []plannedPost) error {
+	out := newDisplayWriter(w)
+	w = out
+	if len(posts) == 0 {
+		return out.err
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", heading, len(posts))
+	for _, planned := range posts {
+		action := planned.action
+		if planned.from != "" {
+			action = planned.from + " → " + action
+		}
+		fmt.Fprintf(w, "  %-18s %s  %s\n", action, planned.post.CreatedAt.Format("2006-01-02"), truncateContent(listedContent(planned.post), 60))
+	}
+	return out.err
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	compareCmd.Flags().String("max-post-age", "", "Match posts older than this (e.g., 30d, 1y, 24h)")
+	compareCmd.Flags().String("before-date", "", "Match posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	compareCmd.Flags().String("after-date", "", "Only match posts created on or after this date (YYYY-MM-DD or MM/DD/YYYY)")
+	compareCmd.Flags().Bool("preserve-selflike", false, "Don't match user's own posts that they have liked")
+	compareCmd.Flags().Bool("preserve-pinned", false, "Don't match pinned posts")
+	compareCmd.Flags().Bool("preserve-with-replies", false, "Don't match posts other people have replied to")
+	compareCmd.Flags().String("preserve-hashtags", "", "Comma-separated hashtags whose posts are never matched (e.g., #keep,#portfolio)")
+	compareCmd.Flags().String("with-hashtags", "", "Only match posts tagged with one of these comma-separated hashtags (e.g., #conf2019)")
+	compareCmd.Flags().String("preserve-language", "", "Comma-separated languages whose posts are never matched (e.g., en,de)")
+	compareCmd.Flags().String("language", "", "Only match posts in one of these comma-separated languages (e.g., en,de)")
+	compareCmd.Flags().Bool("media-only", false, "Only match posts with media attachments (images, video)")
+	compareCmd.Flags().Bool("skip-media", false, "Don't match posts with media attachments, only text posts")
+	compareCmd.Flags().Bool("with-links", false, "Only match posts containing links")
+	compareCmd.Flags().String("links-to", "", "Only match posts linking to one of these comma-separated domains or their subdomains (e.g., oldjob.com)")
+	compareCmd.Flags().Bool("replies-only", false, "Only match your replies to other people's posts")
+	compareCmd.Flags().Bool("skip-replies", false, "Don't match replies, only top-level posts")
+	compareCmd.Flags().Bool("only-sensitive", false, "Only match posts marked sensitive or behind a content warning")
+	compareCmd.Flags().Bool("preserve-cw", false, "Don't match posts behind a content warning")
+	compareCmd.Flags().String("visibility", "", "Only match posts with one of these comma-separated visibilities: public, unlisted, followers-only, direct")
+	compareCmd.Flags().Bool("preserve-direct", false, "Don't match direct messages")
+	compareCmd.Flags().String("exclude-file", "", "File of post URLs or IDs, one per line, that are never matched")
+	compareCmd.Flags().Bool("unlike-posts", false, "Also match posts you've liked, to unlike")
+	compareCmd.Flags().Bool("liked-post-age", false, "Judge likes by the age of the liked post instead of when you liked it (Bluesky)")
+	compareCmd.Flags().Bool("redact", false, "Redact matching posts instead of deleting them (Mastodon, GoToSocial)")
+	compareCmd.Flags().Bool("unshare-reposts", false, "Unshare/unrepost instead of deleting reposts")
+	compareCmd.Flags().Bool("unshare-self-reposts", false, "Also match reposts of your own posts, to unshare without touching the original")
+	compareCmd.Flags().Bool("skip-self-reposts", false, "Don't match reposts or quotes of your own posts")
+	compareCmd.Flags().Bool("only-self-reposts", false, "Only match reposts and quotes of your own posts")
+	compareCmd.Flags().Bool("delete-whole-threads", false, "When a self-thread's first post matches, also match all your replies in that thread")
+	compareCmd.Flags().Int("max-likes", 0, "Only match posts with at most this many likes")
+	compareCmd.Flags().Int("max-reposts", 0, "Only match posts with at most this many reposts")
+	compareCmd.Flags().Int("max-replies", 0, "Only match posts with at most this many replies")
+	compareCmd.Flags().Bool("continue", false, "Search the whole timeline for matching posts, not just the most recent")
+	compareCmd.Flags().Bool("from-index", false, "Select posts from the local index kept by 'cringesweeper sync' instead of walking the timeline")
+	addOverrideFlags(compareCmd, againstPrefix, "for criteria B")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestCompareAgainstOptions(t *testing.T) {
+	setCommandFlags(t, compareCmd, map[string]string{
+		"max-post-age":            "90d",
+		"preserve-pinned":         "true",
+		"against.max-post-age":    "180d",
+		"against.unshare-reposts": "true",
+	})
+
+	first, err := platformPruneOptions(compareCmd, "bluesky")
+	if err != nil {
+		t.Fatalf("platformPruneOptions() error = %v", err)
+	}
+	second, err := pruneOptionsFromFlags(platformFlags{cmd: compareCmd, platform: againstPrefix}, "bluesky")
+	if err != nil {
+		t.Fatalf("pruneOptionsFromFlags() error = %v", err)
+	}
+
+	if *first.MaxAge != 90*24*time.Hour || first.UnshareReposts {
+		t.Errorf("Expected criteria A to ignore --against, got %+v", first)
+	}
+	if *second.MaxAge != 180*24*time.Hour || !second.UnshareReposts || !second.PreservePinned {
+		t.Errorf("Expected criteria B to be A with the --against changes, got %+v", second)
+	}
+	if changes := againstChanges(compareCmd); strings.Join(changes, " ") != "--max-post-age=180d --unshare-reposts=true" {
+		t.Errorf("againstChanges() = %v", changes)
+	}
+}
+
+func TestDiffPruneResults(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	post := func(id string, d int) internal.Post {
+		return internal.Post{ID: id, CreatedAt: day(d), Content: "post " + id}
+	}
+
+	a := &internal.PruneResult{
+		PostsToDelete: []internal.Post{post("1", 1), post("2", 2), post("3", 3), post("r", 4)},
+		PostsToUnlike: []internal.Post{post("l", 5)},
+	}
+	b := &internal.PruneResult{
+		PostsToDelete:  []internal.Post{post("1", 1)},
+		PostsToUnshare: []internal.Post{post("r", 4)},
+		PostsToUnlike:  []internal.Post{post("l", 5), post("m", 6)},
+	}
+
+	diff := diffPruneResults(a, b)
+	ids := func(posts []plannedPost) string {
+		var ids []string
+		for _, planned := range posts {
+			ids = append(ids, planned.post.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+	if got := ids(diff.onlyA); got != "3,2" {
+		t.Errorf("Only under A = %s, want the newest first: 3,2", got)
+	}
+	if got := ids(diff.onlyB); got != "m" {
+		t.Errorf("Only under B = %s, want m", got)
+	}
+	if len(diff.changed) != 1 || diff.changed[0].post.ID != "r" || diff.changed[0].from != "delete" || diff.changed[0].action != "unshare" {
+		t.Errorf("Expected r to go from delete to unshare, got %+v", diff.changed)
+	}
+
+	var out bytes.Buffer
+	displayCriteriaDiff(&out, "Test", []string{"--max-post-age=180d"}, diff)
+	for _, want := range []string{
+		"B: the same with --max-post-age=180d",
+		"  delete         4       1      -3",
+		"  unlike         1       2      +1",
+		"  unshare        0       1      +1",
+		"  total          5       4      -1",
+		"Only under A (2):",
+		"Only under B (1):",
+		"delete → unshare",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	displayCriteriaDiff(&out, "Test", []string{"--preserve-pinned=true"}, diffPruneResults(b, b))
+	if !strings.Contains(out.String(), "exactly the same posts") {
+		t.Errorf("Expected identical results to be called out:\n%s", out.String())
+	}
+}
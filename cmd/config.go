@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// configFile is the --config path; empty means the optional default location
+var configFile string
+
+// skipConfigAnnotation marks commands that read the config file themselves rather than
+// taking flag values from it
+const skipConfigAnnotation = "cringesweeper.skip-config"
+
+// loadConfig applies the config file to cmd's flags. The default file is optional, but
+// one named with --config must exist.
+func loadConfig(cmd *cobra.Command) error {
+	if cmd.Annotations[skipConfigAnnotation] != "" {
+		return nil
+	}
+
+	config, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	return applyConfig(cmd, config)
+}
+
+// loadConfigFile reads the --config file, or the default one. Without a default file
+// the config is empty, so that built-in settings such as policy presets still apply.
+func loadConfigFile() (*internal.Config, error) {
+	empty := &internal.Config{Values: map[string][]string{}, Sections: map[string]map[string][]string{}}
+
+	path := configFile
+	if path == "" {
+		defaultPath, err := internal.DefaultConfigPath()
+		if err != nil {
+			return empty, nil
+		}
+		path = defaultPath
+	}
+
+	config, err := internal.LoadConfigFile(path)
+	if err != nil {
+		if configFile == "" && errors.Is(err, fs.ErrNotExist) {
+			return empty, nil
+		}
+		return nil, fmt.Errorf("failed to load config file: %w", err)
+	}
+	return config, nil
+}
+
+// applyConfig sets any of cmd's flags that weren't given on the command line from the
+// config: shared values first, then the command's own section, then the --policy preset
+// for whatever neither of them set, then sections for each platform being run as
+// --<platform>.<flag> overrides
+func applyConfig(cmd *cobra.Command, config *internal.Config) error {
+	if err := validateConfig(cmd.Root(), config); err != nil {
+		return err
+	}
+
+	values := maps.Clone(config.Values)
+	maps.Copy(values, config.Sections[cmd.Name()])
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if err := setFlagFromConfig(cmd, name, values[name]); err != nil {
+			return err
+		}
+	}
+	if err := applyPolicyPreset(cmd, config); err != nil {
+		return err
+	}
+
+	if cmd.Flags().Lookup("platforms") == nil {
+		return nil
+	}
+	platformsStr, _ := cmd.Flags().GetString("platforms")
+	platforms, err := internal.ParsePlatforms(platformsStr)
+	if err != nil {
+		return nil // Reported properly by the command itself
+	}
+	for _, platform := range platforms {
+		section := config.Sections[platform]
+		for _, name := range slices.Sorted(maps.Keys(section)) {
+			if err := setFlagFromConfig(cmd, platform+"."+name, section[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setFlagFromConfig sets one flag unless the command doesn't have it (the value is for
+// another command) or it was already given on the command line
+func setFlagFromConfig(cmd *cobra.Command, name string, values []string) error {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil || flag.Changed {
+		return nil
+	}
+
+	// Repeatable flags take each list item separately; the rest take a comma-separated list
+	if _, repeatable := flag.Value.(pflag.SliceValue); repeatable {
+		for _, value := range values {
+			if err := cmd.Flags().Set(name, value); err != nil {
+				return fmt.Errorf("invalid %s in config file: %w", name, err)
+			}
+		}
+		return nil
+	}
+	if err := cmd.Flags().Set(name, strings.Join(values, ",")); err != nil {
+		return fmt.Errorf("invalid %s in config file: %w", name, err)
+	}
+	return nil
+}
+
+// validateConfig rejects keys that no command understands, so a typo doesn't silently
+// leave a safety setting off
+func validateConfig(root *cobra.Command, config *internal.Config) error {
+	settable := func(flag *pflag.Flag) bool {
+		return flag.Name != "config" && flag.Name != "help"
+	}
+
+	allFlags := make(map[string]bool)
+	commandFlags := make(map[string]map[string]bool)
+	root.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		allFlags[flag.Name] = settable(flag)
+	})
+	for _, c := range root.Commands() {
+		flags := maps.Clone(allFlags)
+		c.Flags().VisitAll(func(flag *pflag.Flag) {
+			flags[flag.Name] = settable(flag)
+			allFlags[flag.Name] = settable(flag)
+		})
+		commandFlags[c.Name()] = flags
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(config.Values)) {
+		if !allFlags[key] {
+			return fmt.Errorf("unknown setting %q in config file", key)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Sections)) {
+		section := config.Sections[name]
+		if flags, ok := commandFlags[name]; ok {
+			for _, key := range slices.Sorted(maps.Keys(section)) {
+				if !flags[key] {
+					return fmt.Errorf("unknown setting %q for %s in config file", key, name)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(name, policySectionPrefix) {
+			if err := validatePolicySection(name, section); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := internal.SupportedPlatforms[name]; ok {
+			for _, key := range slices.Sorted(maps.Keys(section)) {
+				if !slices.Contains(platformOverridableFlags, key) {
+					return fmt.Errorf("%s can't be set per platform in config file (%s.%s)", key, name, key)
+				}
+			}
+			continue
+		}
+		return fmt.Errorf("unknown section %q in config file: use a command or platform name, or %s<name> for a policy", name, policySectionPrefix)
+	}
+	return nil
+}
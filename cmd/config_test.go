@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resetFlagsAfter restores every flag on cmd to its default when the test ends
+func resetFlagsAfter(t *testing.T, cmd *cobra.Command) {
+	t.Cleanup(func() {
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if slice, ok := flag.Value.(pflag.SliceValue); ok {
+				slice.Replace(nil)
+			} else {
+				flag.Value.Set(flag.DefValue)
+			}
+			flag.Changed = false
+		})
+	})
+}
+
+func mustParseConfig(t *testing.T, data string) *internal.Config {
+	t.Helper()
+	config, err := internal.ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	return config
+}
+
+func TestApplyConfig(t *testing.T) {
+	resetFlagsAfter(t, serverCmd)
+
+	// --max-post-age on the command line beats the config file
+	if err := serverCmd.Flags().Set("max-post-age", "7d"); err != nil {
+		t.Fatal(err)
+	}
+
+	config := mustParseConfig(t, `
+platforms: [bluesky]
+max-post-age: 30d
+preserve-pinned: true
+server:
+  port: 9090
+  prune-schedule:
+    - "0 3 * * *"
+    - mastodon=30 4 * * *
+prune:
+  continue: true
+bluesky:
+  max-likes: 2
+mastodon:
+  max-post-age: 1y
+`)
+	if err := applyConfig(serverCmd, config); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+
+	flags := serverCmd.Flags()
+	if got, _ := flags.GetString("platforms"); got != "bluesky" {
+		t.Errorf("platforms = %q", got)
+	}
+	if got, _ := flags.GetString("max-post-age"); got != "7d" {
+		t.Errorf("Command line max-post-age should win, got %q", got)
+	}
+	if got, _ := flags.GetBool("preserve-pinned"); !got {
+		t.Error("Expected preserve-pinned from the config file")
+	}
+	if got, _ := flags.GetInt("port"); got != 9090 {
+		t.Errorf("Expected port from the server section, got %d", got)
+	}
+	if got, _ := flags.GetStringArray("prune-schedule"); len(got) != 2 || got[1] != "mastodon=30 4 * * *" {
+		t.Errorf("Expected each schedule as its own value, got %q", got)
+	}
+	if got, _ := flags.GetInt("bluesky.max-likes"); got != 2 || !flags.Changed("bluesky.max-likes") {
+		t.Errorf("Expected the bluesky section as an override, got %d", got)
+	}
+	// mastodon isn't being run, so its section mustn't trip checkPlatformOverrides
+	if flags.Changed("mastodon.max-post-age") {
+		t.Error("Sections for platforms that aren't running should be skipped")
+	}
+	if err := checkPlatformOverrides(serverCmd, []string{"bluesky"}); err != nil {
+		t.Errorf("checkPlatformOverrides failed: %v", err)
+	}
+}
+
+func TestApplyConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"typo", "max-post-agee: 30d\n", `unknown setting "max-post-agee"`},
+		{"wrong command", "prune:\n  port: 80\n", `unknown setting "port" for prune`},
+		{"unknown section", "myspace:\n  max-post-age: 30d\n", `unknown section "myspace"`},
+		{"not per platform", "bluesky:\n  dry-run: true\n", "dry-run can't be set per platform"},
+		{"config key", "config: other.yaml\n", `unknown setting "config"`},
+		{"bad value", "preserve-pinned: sometimes\n", "invalid preserve-pinned in config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlagsAfter(t, pruneCmd)
+			err := applyConfig(pruneCmd, mustParseConfig(t, tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetFlagsAfter(t, pruneCmd)
+	t.Cleanup(func() { configFile = "" })
+
+	// No default file is fine
+	if err := loadConfig(pruneCmd); err != nil {
+		t.Fatalf("Expected no error without a config file, got %v", err)
+	}
+
+	// A missing --config file isn't
+	configFile = filepath.Join(home, "missing.yaml")
+	if err := loadConfig(pruneCmd); err == nil {
+		t.Error("Expected an error for a missing --config file")
+	}
+	configFile = ""
+
+	dir := filepath.Join(home, ".config", "cringesweeper")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("max-post-age: 1y\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(pruneCmd); err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if got, _ := pruneCmd.Flags().GetString("max-post-age"); got != "1y" {
+		t.Errorf("Expected max-post-age from the default config file, got %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// assumeYes is set by --yes or --force to go ahead without confirmation prompts
+var assumeYes bool
+
+// confirmGate asks before a command deletes or removes anything. Every destructive
+// command goes through one, so --yes covers them all; server mode, which is set up to
+// run unattended, is the exception.
+type confirmGate struct {
+	reader *bufio.Reader
+	w      io.Writer
+	yes    bool
+}
+
+// newConfirmGate creates a gate that shows summaries on w and reads answers from reader.
+// With --yes, it goes ahead without asking.
+func newConfirmGate(reader *bufio.Reader, w io.Writer) *confirmGate {
+	return &confirmGate{reader: reader, w: w, yes: assumeYes}
+}
+
+// Confirm shows what is about to happen and asks whether to go ahead. Anything but yes,
+// including running out of input, declines, so an unattended run without --yes never
+// changes anything.
+func (g *confirmGate) Confirm(summary string) bool {
+	if g.yes {
+		return true
+	}
+	fmt.Fprintf(g.w, "%s. Go ahead? (--yes skips this question) [y/N]: ", summary)
+	input, err := g.reader.ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(input))
+	if err != nil && answer == "" {
+		fmt.Fprintln(g.w)
+	}
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfirmGate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		yes   bool
+		want  bool
+	}{
+		{"yes", "y\n", false, true},
+		{"yes in full", "YES\n", false, true},
+		{"no", "n\n", false, false},
+		{"just enter", "\n", false, false},
+		{"end of input", "", false, false},
+		{"--yes skips the question", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			gate := newConfirmGate(bufio.NewReader(strings.NewReader(tt.input)), &out)
+			gate.yes = tt.yes
+			if got := gate.Confirm("About to delete 3 posts"); got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+			asked := strings.Contains(out.String(), "About to delete 3 posts. Go ahead?")
+			if asked == tt.yes {
+				t.Errorf("Expected to be asked: %v, got output %q", !tt.yes, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import "io"
+
+// displayWriter passes writes on to w until one fails, then drops the rest and keeps
+// the error. Display functions write through one line by line and return its error
+// once at the end, rather than checking every Fprintf.
+type displayWriter struct {
+	w   io.Writer
+	err error
+}
+
+// newDisplayWriter wraps w, or returns it as is if it's already a displayWriter, so
+// display functions calling one another stop at the same first error
+func newDisplayWriter(w io.Writer) *displayWriter {
+	if dw, ok := w.(*displayWriter); ok {
+		return dw
+	}
+	return &displayWriter{w: w}
+}
+
+func (dw *displayWriter) Write(p []byte) (int, error) {
+	if dw.err != nil {
+		return 0, dw.err
+	}
+	n, err := dw.w.Write(p)
+	if err != nil {
+		dw.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// severity is how bad a presented error is: errors need the user to change something,
+// warnings are usually temporary and worth retrying later
+type severity int
+
+const (
+	severityError severity = iota
+	severityWarning
+)
+
+func (s severity) tag() string {
+	if s == severityWarning {
+		return "WARNING"
+	}
+	return "ERROR"
+}
+
+func (s severity) color() string {
+	if s == severityWarning {
+		return "\033[1;33m"
+	}
+	return "\033[1;31m"
+}
+
+const (
+	colorReset = "\033[0m"
+	colorHint  = "\033[36m"
+)
+
+// presentation is what the user sees for an error: a severity and an optional hint on
+// what to do next
+type presentation struct {
+	severity severity
+	hint     string
+}
+
+// classifyError maps known error types to a severity and remediation hint. Errors whose
+// message already says what to do, or that we know nothing about, get no hint.
+func classifyError(err error) presentation {
+	var apiErr *internal.APIError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return presentation{severityWarning, "The run was interrupted before it finished. Re-run the same command to pick up where it left off"}
+	case errors.Is(err, internal.ErrRequestBudgetExhausted):
+		return presentation{severityWarning, "The run used up its --max-requests budget. Re-run to carry on, or raise --max-requests"}
+	case errors.Is(err, internal.ErrNotOwnAccount):
+		return presentation{severityError, "Pruning only acts on the account you're logged in as. Leave out the username, or run 'cringesweeper auth' for that account first"}
+	case errors.Is(err, internal.ErrRedactUnsupported):
+		return presentation{severityError, "Only Mastodon and GoToSocial posts can be redacted. Set --redact for those platforms alone, e.g. --mastodon.redact in server mode, or prune the others separately"}
+	case errors.Is(err, internal.ErrNoPostIndex):
+		return presentation{severityError, "Run 'cringesweeper sync' for this account first, or leave out --from-index to read the timeline from the platform"}
+	case errors.Is(err, internal.ErrNoCredentials):
+		return presentation{severity: severityError}
+	case errors.As(err, &apiErr):
+		return classifyAPIError(apiErr)
+	case errors.Is(err, context.DeadlineExceeded) || isNetworkError(err):
+		return presentation{severityWarning, "Check your network connection, or raise --http-timeout if the server is just slow"}
+	}
+	return presentation{severity: severityError}
+}
+
+func classifyAPIError(err *internal.APIError) presentation {
+	authCommand := "cringesweeper auth"
+	if err.Platform != "" {
+		authCommand += " --platforms=" + err.Platform
+	}
+
+	switch {
+	case err.StatusCode == http.StatusUnauthorized:
+		return presentation{severityError, fmt.Sprintf("Your login was rejected, it may have expired or been revoked. Run '%s' to log in again", authCommand)}
+	case err.StatusCode == http.StatusForbidden:
+		if err.Platform == "mastodon" || err.Platform == "gotosocial" {
+			return presentation{severityError, fmt.Sprintf("The access token is missing a permission it needs, usually a write scope. Create a token with read and write scopes and run '%s' to save it", authCommand)}
+		}
+		return presentation{severityError, fmt.Sprintf("The account isn't allowed to do this. Check the app password is still valid and run '%s' to replace it", authCommand)}
+	case err.StatusCode == http.StatusNotFound:
+		return presentation{severityWarning, "The post or account wasn't found; it may already have been deleted"}
+	case err.StatusCode == http.StatusTooManyRequests:
+		return presentation{severityWarning, "You're being rate limited. Wait a while before trying again, or raise --rate-limit-delay"}
+	case err.StatusCode >= 500:
+		return presentation{severityWarning, "The server is having trouble. Try again later, or raise --max-retries to ride it out"}
+	}
+	return presentation{severity: severityError}
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isCredentialError returns true if err means the platform refused our login, or there
+// was none to give it
+func isCredentialError(err error) bool {
+	var apiErr *internal.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	return errors.Is(err, internal.ErrNoCredentials)
+}
+
+// presentError prints an error with its severity and, where we have one, a hint on how
+// to fix it. Colors are used when writing to a terminal unless NO_COLOR is set.
+func presentError(w io.Writer, err error) {
+	p := classifyError(err)
+	color := useColor(w)
+
+	tag := p.severity.tag()
+	if color {
+		tag = p.severity.color() + tag + colorReset
+	}
+	fmt.Fprintf(w, "❌ %s: %v\n", tag, err)
+
+	if p.hint != "" {
+		hint := p.hint
+		if color {
+			hint = colorHint + hint + colorReset
+		}
+		fmt.Fprintf(w, "   💡 %s\n", hint)
+	}
+}
+
+// exitWithError presents err on stdout and exits
+func exitWithError(err error) {
+	presentError(os.Stdout, err)
+	os.Exit(1)
+}
+
+func useColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantSeverity severity
+		wantHint     string // Substring of the hint, or "" for no hint
+	}{
+		{"unknown", errors.New("something odd"), severityError, ""},
+		{"interrupted", fmt.Errorf("fetching posts: %w", context.Canceled), severityWarning, "Re-run"},
+		{"timeout", fmt.Errorf("fetching posts: %w", context.DeadlineExceeded), severityWarning, "--http-timeout"},
+		{"request budget", fmt.Errorf("failed to fetch posts: %w", internal.ErrRequestBudgetExhausted), severityWarning, "--max-requests"},
+		{"not own account", fmt.Errorf("%w: someone", internal.ErrNotOwnAccount), severityError, "logged in as"},
+		{"redact unsupported", internal.ErrRedactUnsupported, severityError, "can be redacted"},
+		{"no post index", fmt.Errorf("%w for me on mastodon", internal.ErrNoPostIndex), severityError, "cringesweeper sync"},
+		{"no credentials", fmt.Errorf("%w for platform bluesky", internal.ErrNoCredentials), severityError, ""},
+		{"expired login", &internal.APIError{Platform: "bluesky", StatusCode: 401}, severityError, "cringesweeper auth --platforms=bluesky"},
+		{"mastodon missing scope", fmt.Errorf("deleting: %w", &internal.APIError{Platform: "mastodon", StatusCode: 403}), severityError, "write scopes"},
+		{"bluesky forbidden", &internal.APIError{Platform: "bluesky", StatusCode: 403}, severityError, "app password"},
+		{"already gone", &internal.APIError{Platform: "mastodon", StatusCode: 404}, severityWarning, "already"},
+		{"rate limited", &internal.APIError{Platform: "mastodon", StatusCode: 429}, severityWarning, "--rate-limit-delay"},
+		{"server error", &internal.APIError{Platform: "bluesky", StatusCode: 502}, severityWarning, "--max-retries"},
+		{"bad request", &internal.APIError{Platform: "bluesky", StatusCode: 400}, severityError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			if got.severity != tt.wantSeverity {
+				t.Errorf("severity = %v, want %v", got.severity, tt.wantSeverity)
+			}
+			if tt.wantHint == "" && got.hint != "" {
+				t.Errorf("Expected no hint, got %q", got.hint)
+			}
+			if !strings.Contains(got.hint, tt.wantHint) {
+				t.Errorf("hint %q should contain %q", got.hint, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestPresentError(t *testing.T) {
+	var buf bytes.Buffer
+	presentError(&buf, fmt.Errorf("mastodon: %w", &internal.APIError{Platform: "mastodon", Operation: "statuses request", StatusCode: 401, Body: "{}"}))
+
+	want := "❌ ERROR: mastodon: statuses request failed with status 401: {}\n" +
+		"   💡 Your login was rejected, it may have expired or been revoked. Run 'cringesweeper auth --platforms=mastodon' to log in again\n"
+	if buf.String() != want {
+		t.Errorf("presentError output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// Errors with no hint are a single line, and never colored when not on a terminal
+	buf.Reset()
+	presentError(&buf, errors.New("boom"))
+	if buf.String() != "❌ ERROR: boom\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestIsCredentialError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rejected login", fmt.Errorf("fetching posts: %w", &internal.APIError{Platform: "mastodon", StatusCode: 401}), true},
+		{"no credentials", fmt.Errorf("%w for platform bluesky", internal.ErrNoCredentials), true},
+		{"forbidden", &internal.APIError{Platform: "bluesky", StatusCode: 403}, false},
+		{"server error", &internal.APIError{Platform: "bluesky", StatusCode: 502}, false},
+		{"other", errors.New("something odd"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCredentialError(tt.err); got != tt.want {
+				t.Errorf("isCredentialError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
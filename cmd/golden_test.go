@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// Run `go test ./cmd/ -update` to regenerate the golden files after a deliberate formatting change
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// assertGolden compares output against testdata/<name>.golden
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create testdata directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("Failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output does not match %s (run with -update if the change is intended)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// goldenPosts returns a fixed set of posts covering each display branch
+func goldenPosts() []internal.Post {
+	created := time.Date(2023, 6, 15, 14, 30, 0, 0, time.UTC)
+	return []internal.Post{
+		{
+			ID:          "post1",
+			Author:      "Test User",
+			Handle:      "testuser",
+			Content:     "An original post",
+			CreatedAt:   created,
+			URL:         "https://example.com/post1",
+			Type:        internal.PostTypeOriginal,
+			LikeCount:   5,
+			RepostCount: 2,
+			ReplyCount:  1,
+		},
+		{
+			ID:             "post2",
+			Author:         "testuser",
+			Handle:         "testuser",
+			Content:        "",
+			CreatedAt:      created.Add(-24 * time.Hour),
+			Type:           internal.PostTypeRepost,
+			OriginalAuthor: "Other User",
+			OriginalHandle: "otheruser",
+			OriginalPost:   &internal.Post{Content: "Something worth sharing"},
+		},
+		{
+			ID:        "post3",
+			Handle:    "testuser",
+			Content:   "A reply\nspanning lines",
+			CreatedAt: created.Add(-48 * time.Hour),
+			Type:      internal.PostTypeReply,
+			LikeCount: 1,
+		},
+		{
+			ID:        "post4",
+			Handle:    "testuser",
+			Content:   "A quote post",
+			CreatedAt: created.Add(-72 * time.Hour),
+			Type:      internal.PostTypeQuote,
+		},
+		{
+			ID:        "post5",
+			Handle:    "someone",
+			Content:   "A liked post",
+			CreatedAt: created.Add(-96 * time.Hour),
+			Type:      internal.PostTypeLike,
+		},
+	}
+}
+
+// goldenPruneResult returns a fixed prune result with one post in each category
+func goldenPruneResult() *internal.PruneResult {
+	posts := goldenPosts()
+	pinned := posts[3]
+	pinned.IsPinned = true
+	return &internal.PruneResult{
+		PostsToDelete:   []internal.Post{posts[0], posts[2]},
+		PostsToUnlike:   []internal.Post{posts[4]},
+		PostsToUnshare:  []internal.Post{posts[1]},
+		PostsPreserved:  []internal.Post{pinned},
+		DeletedCount:    2,
+		UnlikedCount:    1,
+		UnsharedCount:   1,
+		PreservedCount:  1,
+		PreserveReasons: map[string]string{pinned.ID: internal.PreserveReasonPinned},
+		ErrorsCount:     1,
+		Errors:          []string{"Failed to delete post post9: 500 Internal Server Error"},
+		Warnings:        []string{"Failed to fetch liked posts: timeout"},
+	}
+}
+
+func TestDisplayPostsGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		posts []internal.Post
+	}{
+		{"display_posts", goldenPosts()},
+		{"display_posts_empty", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			displayPosts(&buf, tt.posts, "TestPlatform")
+			assertGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestDisplaySinglePostGolden(t *testing.T) {
+	for i, post := range goldenPosts() {
+		name := "display_single_post_" + string(post.Type)
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			displaySinglePost(&buf, post, i+1)
+			assertGolden(t, name, buf.Bytes())
+		})
+	}
+}
+
+func TestDisplayPostsStreamingGolden(t *testing.T) {
+	var buf bytes.Buffer
+	displayPostsStreaming(&buf, goldenPosts())
+	assertGolden(t, "display_posts_streaming", buf.Bytes())
+}
+
+func TestDisplayVersionGolden(t *testing.T) {
+	var buf bytes.Buffer
+	displayVersion(&buf, map[string]string{"version": "v1.2.3", "commit": "abc1234", "build_time": "2023-06-15T14:30:00Z"})
+	assertGolden(t, "display_version", buf.Bytes())
+}
+
+func TestDisplayPruneResultsGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *internal.PruneResult
+		dryRun bool
+	}{
+		{"prune_results_dry_run", goldenPruneResult(), true},
+		{"prune_results", goldenPruneResult(), false},
+		{"prune_results_no_matches", &internal.PruneResult{Warnings: []string{"Repost listing truncated"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			displayPruneResults(&buf, tt.result, "TestPlatform", tt.dryRun)
+			assertGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestDisplayPostStatsGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		posts []internal.Post
+	}{
+		{"display_post_stats", goldenPosts()},
+		{"display_post_stats_empty", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			displayPostStats(&buf, internal.AnalyzePosts(tt.posts, 10), "TestPlatform")
+			assertGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestDisplayTimelineStatsGolden(t *testing.T) {
+	maxAge := 3 * 24 * time.Hour
+	criteria := describeAgeCriteria(internal.PruneOptions{MaxAge: &maxAge})
+
+	var buf bytes.Buffer
+	displayTimelineStats(&buf, internal.AnalyzePosts(goldenPosts(), 0), 2, criteria, "TestPlatform")
+	assertGolden(t, "display_timeline_stats", buf.Bytes())
+}
@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
 	"github.com/spf13/cobra"
 )
 
@@ -23,33 +26,53 @@ Use --platforms to specify multiple platforms (e.g., --platforms=bluesky,mastodo
 or --platforms=all). When multiple platforms are specified, results are shown
 grouped by platform with clear headers.
 
+Use --platforms=twitter to list posts from a downloaded Twitter/X archive zip,
+passing the path to the archive in place of the username (or setting
+TWITTER_ARCHIVE). Archives are read-only and cannot be pruned.
+
 By default, shows recent posts (typically 10 most recent). Use --continue to
 keep searching further back in time until no more posts are found. Use age
-filters like --max-post-age or --before-date to limit results to specific
-time periods.
+filters like --max-post-age, --before-date and --after-date to limit results
+to specific time periods.
 
-The username can be provided as an argument or via environment variables.`,
+The username can be provided as an argument or via environment variables.
+Any public account can be listed, not just your own: listing is read-only and
+never modifies anything. Only prune requires the target to be the account
+you are authenticated as.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
 		platformsStr, _ := cmd.Flags().GetString("platforms")
 		continueUntilEnd, _ := cmd.Flags().GetBool("continue")
 		limitStr, _ := cmd.Flags().GetString("limit")
 		maxAgeStr, _ := cmd.Flags().GetString("max-post-age")
 		beforeDateStr, _ := cmd.Flags().GetString("before-date")
+		afterDateStr, _ := cmd.Flags().GetString("after-date")
+		fromIndex, _ := cmd.Flags().GetBool("from-index")
+		outputStr, _ := cmd.Flags().GetString("output")
+		sortStr, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
 
 		// Determine which platforms to use
 		var platforms []string
 		var err error
 		
 		if platformsStr == "" {
-			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon) or 'all'\n")
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
 			os.Exit(1)
 		}
 		
-		platforms, err = internal.ParsePlatforms(platformsStr)
+		platforms, err = internal.ParseReadablePlatforms(platformsStr)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
+		}
+
+		out, err := newPostOutput(outputStr, os.Stdout, os.Stderr)
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := out.sortBy(sortStr, reverse); err != nil {
+			exitWithError(err)
 		}
 
 		// Get username with fallback priority: argument > saved credentials > environment
@@ -61,41 +84,54 @@ The username can be provided as an argument or via environment variables.`,
 		// Process each platform
 		for i, platformName := range platforms {
 			if len(platforms) > 1 {
-				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+				out.statusf("\n=== %s ===\n", strings.ToUpper(platformName))
 			}
+			out.startPlatform()
 
 			username, err := internal.GetUsernameForPlatform(platformName, argUsername)
 			if err != nil {
-				fmt.Printf("Error for %s: %v\n", platformName, err)
+				presentError(out.status, fmt.Errorf("%s: %w", platformName, err))
 				if len(platforms) > 1 {
 					continue // Skip this platform but continue with others
 				}
 				os.Exit(1)
 			}
 
-			client, exists := internal.GetClient(platformName)
+			client, exists := internal.GetReader(platformName)
 			if !exists {
-				fmt.Printf("Error: Unsupported platform '%s'. Supported platforms: %s\n", 
-					platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+				out.statusf("Error: Unsupported platform '%s'. Supported platforms: %s\n", 
+					platformName, strings.Join(internal.GetAllReadablePlatformNames(), ", "))
 				if len(platforms) > 1 {
 					continue // Skip this platform but continue with others
 				}
 				os.Exit(1)
 			}
 
+			// With --from-index, list the copy kept by sync instead of fetching
+			if fromIndex {
+				client, err = indexReader(platformName, username)
+				if err != nil {
+					presentError(out.status, fmt.Errorf("%s: %w", platformName, err))
+					if len(platforms) > 1 {
+						continue
+					}
+					os.Exit(1)
+				}
+			}
+
 			// Parse limit
 			limit := 10 // default
 			if limitStr != "" {
 				parsedLimit, err := strconv.Atoi(limitStr)
 				if err != nil {
-					fmt.Printf("Error parsing limit for %s: %v\n", platformName, err)
+					out.statusf("Error parsing limit for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
 						continue
 					}
 					os.Exit(1)
 				}
 				if parsedLimit <= 0 {
-					fmt.Printf("Error: limit must be a positive number\n")
+					out.statusf("Error: limit must be a positive number\n")
 					if len(platforms) > 1 {
 						continue
 					}
@@ -107,11 +143,12 @@ The username can be provided as an argument or via environment variables.`,
 			// Parse age filters
 			var maxAge *time.Duration
 			var beforeDate *time.Time
+			var afterDate *time.Time
 
 			if maxAgeStr != "" {
-				duration, err := parseDuration(maxAgeStr)
+				duration, err := timespec.ParseDuration(maxAgeStr)
 				if err != nil {
-					fmt.Printf("Error parsing max-post-age for %s: %v\n", platformName, err)
+					out.statusf("Error parsing max-post-age for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
 						continue
 					}
@@ -121,9 +158,9 @@ The username can be provided as an argument or via environment variables.`,
 			}
 
 			if beforeDateStr != "" {
-				date, err := parseDate(beforeDateStr)
+				date, err := timespec.ParseDate(beforeDateStr)
 				if err != nil {
-					fmt.Printf("Error parsing before-date for %s: %v\n", platformName, err)
+					out.statusf("Error parsing before-date for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
 						continue
 					}
@@ -132,78 +169,101 @@ The username can be provided as an argument or via environment variables.`,
 				beforeDate = &date
 			}
 
+			if afterDateStr != "" {
+				date, err := timespec.ParseDate(afterDateStr)
+				if err != nil {
+					out.statusf("Error parsing after-date for %s: %v\n", platformName, err)
+					if len(platforms) > 1 {
+						continue
+					}
+					os.Exit(1)
+				}
+				afterDate = &date
+			}
+
 			// Perform listing
 			if continueUntilEnd {
-				performContinuousListing(client, username, limit, maxAge, beforeDate)
+				performContinuousListing(ctx, out, client, platformName, username, limit, maxAge, beforeDate, afterDate)
 			} else {
-				performSingleListing(client, username, limit, maxAge, beforeDate)
+				performSingleListing(ctx, out, client, username, limit, maxAge, beforeDate, afterDate)
 			}
+			out.endPlatform()
 
 			// Add spacing between platforms when processing multiple
 			if len(platforms) > 1 && i < len(platforms)-1 {
-				fmt.Println() // Extra newline between platforms
+				out.statusf("\n") // Extra newline between platforms
 			}
 		}
+
+		if err := out.flush(); err != nil {
+			exitWithError(err)
+		}
 	},
 }
 
-func performSingleListing(client internal.SocialClient, username string, limit int, maxAge *time.Duration, beforeDate *time.Time) {
-	posts, err := client.FetchUserPosts(username, limit)
+func performSingleListing(ctx context.Context, out *postOutput, client internal.PostReader, username string, limit int, maxAge *time.Duration, beforeDate, afterDate *time.Time) {
+	posts, err := client.FetchUserPosts(ctx, username, limit)
 	if err != nil {
-		fmt.Printf("Error fetching posts from %s: %v\n", client.GetPlatformName(), err)
+		presentError(out.status, fmt.Errorf("fetching posts from %s: %w", client.GetPlatformName(), err))
 		os.Exit(1)
 	}
 
 	// Filter posts by age criteria if specified
-	filteredPosts := filterPostsByAge(posts, maxAge, beforeDate)
+	filteredPosts := filterPostsByAge(posts, maxAge, beforeDate, afterDate)
 	
 	if len(filteredPosts) == 0 {
-		if maxAge != nil || beforeDate != nil {
-			fmt.Println("No posts match the specified age criteria")
+		if maxAge != nil || beforeDate != nil || afterDate != nil {
+			out.statusf("No posts match the specified age criteria\n")
 		} else {
-			fmt.Println("No posts found")
+			out.statusf("No posts found\n")
 		}
 		return
 	}
 
-	fmt.Printf("Posts from %s", client.GetPlatformName())
-	if maxAge != nil || beforeDate != nil {
-		fmt.Printf(" (filtered by age criteria)")
+	out.statusf("Posts from %s", client.GetPlatformName())
+	if maxAge != nil || beforeDate != nil || afterDate != nil {
+		out.statusf(" (filtered by age criteria)")
 	}
-	fmt.Printf(":\n\n")
+	out.statusf(":\n\n")
 
-	displayPostsStreaming(filteredPosts)
+	for _, post := range filteredPosts {
+		out.post(post)
+	}
 }
 
-func performContinuousListing(client internal.SocialClient, username string, batchLimit int, maxAge *time.Duration, beforeDate *time.Time) {
+// performContinuousListing walks the timeline until it runs out or passes the age
+// criteria. Pages start at batchLimit and grow towards the platform's maximum, so deep
+// histories need fewer requests while the first results still arrive quickly.
+func performContinuousListing(ctx context.Context, out *postOutput, client internal.PostReader, platformName, username string, batchLimit int, maxAge *time.Duration, beforeDate, afterDate *time.Time) {
 	platform := client.GetPlatformName()
+	pageSizes := internal.NewPageSizeRamp(batchLimit, internal.MaxPageSize(platformName))
 	round := 1
 	totalDisplayed := 0
 	headerShown := false
 	cursor := "" // Start with empty cursor
 
-	fmt.Printf("Searching %s for posts", platform)
-	if maxAge != nil || beforeDate != nil {
-		fmt.Printf(" matching age criteria")
+	out.statusf("Searching %s for posts", platform)
+	if maxAge != nil || beforeDate != nil || afterDate != nil {
+		out.statusf(" matching age criteria")
 	}
-	fmt.Printf(" (will continue until no more posts found)...\n\n")
+	out.statusf(" (will continue until no more posts found)...\n\n")
 
 	for {
-		posts, nextCursor, err := client.FetchUserPostsPaginated(username, batchLimit, cursor)
+		posts, nextCursor, err := client.FetchUserPostsPaginated(ctx, username, pageSizes.Next(), cursor)
 		if err != nil {
-			fmt.Printf("Error in round %d: %v\n", round, err)
+			out.statusf("Error in round %d: %v\n", round, err)
 			break
 		}
 
 		// Filter posts by age criteria if specified
-		filteredPosts, shouldContinue := filterPostsByAgeWithTermination(posts, maxAge, beforeDate)
+		filteredPosts, shouldContinue := filterPostsByAgeWithTermination(posts, maxAge, beforeDate, afterDate)
 
 		if len(filteredPosts) == 0 && len(posts) == 0 {
 			if round == 1 {
-				fmt.Println("No posts found")
+				out.statusf("No posts found\n")
 			} else {
-				fmt.Printf("\nNo more posts found. Search complete after %d rounds.\n", round)
-				fmt.Printf("Total posts displayed: %d\n", totalDisplayed)
+				out.statusf("\nNo more posts found. Search complete after %d rounds.\n", round)
+				out.statusf("Total posts displayed: %d\n", totalDisplayed)
 			}
 			break
 		}
@@ -214,17 +274,17 @@ func performContinuousListing(client internal.SocialClient, username string, bat
 			if len(filteredPosts) > 0 {
 				// Show header on first batch with results
 				if !headerShown {
-					fmt.Printf("Posts from %s:\n\n", platform)
+					out.statusf("Posts from %s:\n\n", platform)
 					headerShown = true
 				}
 				// Stream the posts immediately
 				for _, post := range filteredPosts {
-					displaySinglePost(post, totalDisplayed+1)
+					out.post(post)
 					totalDisplayed++
 				}
 			}
-			fmt.Printf("\nReached age threshold. All matching posts have been displayed after %d rounds.\n", round)
-			fmt.Printf("Total posts displayed: %d\n", totalDisplayed)
+			out.statusf("\nReached age threshold. All matching posts have been displayed after %d rounds.\n", round)
+			out.statusf("Total posts displayed: %d\n", totalDisplayed)
 			break
 		}
 
@@ -232,8 +292,8 @@ func performContinuousListing(client internal.SocialClient, username string, bat
 		if len(filteredPosts) == 0 && len(posts) > 0 {
 			// Check if we have a next cursor to continue
 			if nextCursor == "" || nextCursor == cursor {
-				fmt.Printf("\nReached end of timeline. No more posts match criteria after %d rounds.\n", round)
-				fmt.Printf("Total posts displayed: %d\n", totalDisplayed)
+				out.statusf("\nReached end of timeline. No more posts match criteria after %d rounds.\n", round)
+				out.statusf("Total posts displayed: %d\n", totalDisplayed)
 				break
 			}
 			cursor = nextCursor
@@ -244,20 +304,20 @@ func performContinuousListing(client internal.SocialClient, username string, bat
 
 		// Show header on first batch with results
 		if !headerShown {
-			fmt.Printf("Posts from %s:\n\n", platform)
+			out.statusf("Posts from %s:\n\n", platform)
 			headerShown = true
 		}
 
 		// Stream the posts immediately
 		for _, post := range filteredPosts {
-			displaySinglePost(post, totalDisplayed+1)
+			out.post(post)
 			totalDisplayed++
 		}
 
 		// Check if we have a next cursor to continue
 		if nextCursor == "" || nextCursor == cursor {
-			fmt.Printf("\nReached end of timeline. Search complete after %d rounds.\n", round)
-			fmt.Printf("Total posts displayed: %d\n", totalDisplayed)
+			out.statusf("\nReached end of timeline. Search complete after %d rounds.\n", round)
+			out.statusf("Total posts displayed: %d\n", totalDisplayed)
 			break
 		}
 
@@ -269,13 +329,13 @@ func performContinuousListing(client internal.SocialClient, username string, bat
 	}
 }
 
-func filterPostsByAge(posts []internal.Post, maxAge *time.Duration, beforeDate *time.Time) []internal.Post {
-	if maxAge == nil && beforeDate == nil {
+func filterPostsByAge(posts []internal.Post, maxAge *time.Duration, beforeDate, afterDate *time.Time) []internal.Post {
+	if maxAge == nil && beforeDate == nil && afterDate == nil {
 		return posts
 	}
 
 	var filtered []internal.Post
-	now := time.Now()
+	now := clock.Now()
 
 	for _, post := range posts {
 		shouldInclude := true
@@ -294,6 +354,11 @@ func filterPostsByAge(posts []internal.Post, maxAge *time.Duration, beforeDate *
 			}
 		}
 
+		// Check after date criteria
+		if afterDate != nil && post.CreatedAt.Before(*afterDate) {
+			shouldInclude = false
+		}
+
 		if shouldInclude {
 			filtered = append(filtered, post)
 		}
@@ -304,14 +369,14 @@ func filterPostsByAge(posts []internal.Post, maxAge *time.Duration, beforeDate *
 
 // filterPostsByAgeWithTermination filters posts and returns whether we should continue fetching
 // Returns (filteredPosts, shouldContinue)
-func filterPostsByAgeWithTermination(posts []internal.Post, maxAge *time.Duration, beforeDate *time.Time) ([]internal.Post, bool) {
+func filterPostsByAgeWithTermination(posts []internal.Post, maxAge *time.Duration, beforeDate, afterDate *time.Time) ([]internal.Post, bool) {
 	// If no age criteria, return all posts and continue
-	if maxAge == nil && beforeDate == nil {
+	if maxAge == nil && beforeDate == nil && afterDate == nil {
 		return posts, true
 	}
 
 	var filtered []internal.Post
-	now := time.Now()
+	now := clock.Now()
 	shouldContinue := true
 
 	for _, post := range posts {
@@ -326,14 +391,19 @@ func filterPostsByAgeWithTermination(posts []internal.Post, maxAge *time.Duratio
 			}
 		}
 
-		// Check before date criteria
+		// Posts on or after the before date are too new, and older ones may follow
 		if beforeDate != nil {
 			if !post.CreatedAt.Before(*beforeDate) {
 				shouldInclude = false
-				postTooOld = true
 			}
 		}
 
+		// Check after date criteria
+		if afterDate != nil && post.CreatedAt.Before(*afterDate) {
+			shouldInclude = false
+			postTooOld = true
+		}
+
 		if shouldInclude {
 			filtered = append(filtered, post)
 		} else if postTooOld {
@@ -347,51 +417,64 @@ func filterPostsByAgeWithTermination(posts []internal.Post, maxAge *time.Duratio
 	return filtered, shouldContinue
 }
 
-func displayPostsStreaming(posts []internal.Post) {
+func displayPostsStreaming(w io.Writer, posts []internal.Post) error {
 	for i, post := range posts {
-		displaySinglePost(post, i+1)
+		if err := displaySinglePost(w, post, i+1); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func displaySinglePost(post internal.Post, index int) {
-	fmt.Printf("Post %d", index)
+func displaySinglePost(w io.Writer, post internal.Post, index int) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, "Post %d", index)
 
 	// Show post type indicator
 	switch post.Type {
 	case internal.PostTypeRepost:
-		fmt.Printf(" [REPOST]")
+		if post.SelfRepost {
+			fmt.Fprintf(w, " [SELF-REPOST]")
+		} else {
+			fmt.Fprintf(w, " [REPOST]")
+		}
 	case internal.PostTypeReply:
-		fmt.Printf(" [REPLY]")
+		fmt.Fprintf(w, " [REPLY]")
 	case internal.PostTypeQuote:
-		fmt.Printf(" [QUOTE]")
+		if post.SelfQuote {
+			fmt.Fprintf(w, " [SELF-QUOTE]")
+		} else {
+			fmt.Fprintf(w, " [QUOTE]")
+		}
 	case internal.PostTypeLike:
-		fmt.Printf(" [LIKE]")
+		fmt.Fprintf(w, " [LIKE]")
 	}
-	fmt.Printf(":\n")
+	fmt.Fprintf(w, ":\n")
 
-	fmt.Printf("  Author: @%s", post.Handle)
+	fmt.Fprintf(w, "  Author: @%s", post.Handle)
 	if post.Author != "" && post.Author != post.Handle {
-		fmt.Printf(" (%s)", post.Author)
+		fmt.Fprintf(w, " (%s)", post.Author)
 	}
-	fmt.Printf("\n")
+	fmt.Fprintf(w, "\n")
 
-	fmt.Printf("  Posted: %s\n", post.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "  Posted: %s\n", post.CreatedAt.Format("2006-01-02 15:04:05"))
 
 	// Handle reposts specially
 	if post.Type == internal.PostTypeRepost && post.OriginalPost != nil {
-		fmt.Printf("  Reposted from: @%s", post.OriginalHandle)
+		fmt.Fprintf(w, "  Reposted from: @%s", post.OriginalHandle)
 		if post.OriginalAuthor != "" && post.OriginalAuthor != post.OriginalHandle {
-			fmt.Printf(" (%s)", post.OriginalAuthor)
+			fmt.Fprintf(w, " (%s)", post.OriginalAuthor)
 		}
-		fmt.Printf("\n")
-		fmt.Printf("  Original content: %s\n", post.OriginalPost.Content)
+		fmt.Fprintf(w, "\n")
+		fmt.Fprintf(w, "  Original content: %s\n", post.OriginalPost.Content)
 	} else {
-		fmt.Printf("  Content: %s\n", post.Content)
+		fmt.Fprintf(w, "  Content: %s\n", post.Content)
 	}
 
 	// Show engagement metrics if available
 	if post.LikeCount > 0 || post.RepostCount > 0 || post.ReplyCount > 0 {
-		fmt.Printf("  Engagement: ")
+		fmt.Fprintf(w, "  Engagement: ")
 		var metrics []string
 		if post.LikeCount > 0 {
 			metrics = append(metrics, fmt.Sprintf("%d likes", post.LikeCount))
@@ -402,62 +485,73 @@ func displaySinglePost(post internal.Post, index int) {
 		if post.ReplyCount > 0 {
 			metrics = append(metrics, fmt.Sprintf("%d replies", post.ReplyCount))
 		}
-		fmt.Printf("%s\n", fmt.Sprintf("%v", metrics))
+		fmt.Fprintf(w, "%s\n", fmt.Sprintf("%v", metrics))
 	}
 
 	if post.URL != "" {
-		fmt.Printf("  URL: %s\n", post.URL)
+		fmt.Fprintf(w, "  URL: %s\n", post.URL)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
+	return out.err
 }
 
-func displayPosts(posts []internal.Post, platform string) {
+func displayPosts(w io.Writer, posts []internal.Post, platform string) error {
+	out := newDisplayWriter(w)
+	w = out
 	if len(posts) == 0 {
-		fmt.Println("No posts found")
-		return
+		fmt.Fprintln(w, "No posts found")
+		return out.err
 	}
 
-	fmt.Printf("Recent posts from %s:\n\n", platform)
+	fmt.Fprintf(w, "Recent posts from %s:\n\n", platform)
 
 	for i, post := range posts {
-		fmt.Printf("Post %d", i+1)
+		fmt.Fprintf(w, "Post %d", i+1)
 
 		// Show post type indicator
 		switch post.Type {
 		case internal.PostTypeRepost:
-			fmt.Printf(" [REPOST]")
+			if post.SelfRepost {
+				fmt.Fprintf(w, " [SELF-REPOST]")
+			} else {
+				fmt.Fprintf(w, " [REPOST]")
+			}
 		case internal.PostTypeReply:
-			fmt.Printf(" [REPLY]")
+			fmt.Fprintf(w, " [REPLY]")
 		case internal.PostTypeQuote:
-			fmt.Printf(" [QUOTE]")
+			if post.SelfQuote {
+				fmt.Fprintf(w, " [SELF-QUOTE]")
+			} else {
+				fmt.Fprintf(w, " [QUOTE]")
+			}
 		case internal.PostTypeLike:
-			fmt.Printf(" [LIKE]")
+			fmt.Fprintf(w, " [LIKE]")
 		}
-		fmt.Printf(":\n")
+		fmt.Fprintf(w, ":\n")
 
-		fmt.Printf("  Author: @%s", post.Handle)
+		fmt.Fprintf(w, "  Author: @%s", post.Handle)
 		if post.Author != "" && post.Author != post.Handle {
-			fmt.Printf(" (%s)", post.Author)
+			fmt.Fprintf(w, " (%s)", post.Author)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 
-		fmt.Printf("  Posted: %s\n", post.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(w, "  Posted: %s\n", post.CreatedAt.Format("2006-01-02 15:04:05"))
 
 		// Handle reposts specially
 		if post.Type == internal.PostTypeRepost && post.OriginalPost != nil {
-			fmt.Printf("  Reposted from: @%s", post.OriginalHandle)
+			fmt.Fprintf(w, "  Reposted from: @%s", post.OriginalHandle)
 			if post.OriginalAuthor != "" && post.OriginalAuthor != post.OriginalHandle {
-				fmt.Printf(" (%s)", post.OriginalAuthor)
+				fmt.Fprintf(w, " (%s)", post.OriginalAuthor)
 			}
-			fmt.Printf("\n")
-			fmt.Printf("  Original content: %s\n", post.OriginalPost.Content)
+			fmt.Fprintf(w, "\n")
+			fmt.Fprintf(w, "  Original content: %s\n", post.OriginalPost.Content)
 		} else {
-			fmt.Printf("  Content: %s\n", post.Content)
+			fmt.Fprintf(w, "  Content: %s\n", post.Content)
 		}
 
 		// Show engagement metrics if available
 		if post.LikeCount > 0 || post.RepostCount > 0 || post.ReplyCount > 0 {
-			fmt.Printf("  Engagement: ")
+			fmt.Fprintf(w, "  Engagement: ")
 			var metrics []string
 			if post.LikeCount > 0 {
 				metrics = append(metrics, fmt.Sprintf("%d likes", post.LikeCount))
@@ -468,21 +562,27 @@ func displayPosts(posts []internal.Post, platform string) {
 			if post.ReplyCount > 0 {
 				metrics = append(metrics, fmt.Sprintf("%d replies", post.ReplyCount))
 			}
-			fmt.Printf("%s\n", fmt.Sprintf("%v", metrics))
+			fmt.Fprintf(w, "%s\n", fmt.Sprintf("%v", metrics))
 		}
 
 		if post.URL != "" {
-			fmt.Printf("  URL: %s\n", post.URL)
+			fmt.Fprintf(w, "  URL: %s\n", post.URL)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
+	return out.err
 }
 
 func init() {
 	rootCmd.AddCommand(lsCmd)
-	lsCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon) or 'all' for all platforms")
-	lsCmd.Flags().String("limit", "10", "Maximum number of posts to fetch per batch")
+	lsCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial,twitter) or 'all' for all live platforms")
+	lsCmd.Flags().String("limit", "10", "Maximum number of posts to fetch per batch (with --continue, the first batch; later ones grow to the platform maximum)")
 	lsCmd.Flags().String("max-post-age", "", "Only show posts older than this (e.g., 30d, 1y, 24h)")
 	lsCmd.Flags().String("before-date", "", "Only show posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	lsCmd.Flags().String("after-date", "", "Only show posts created on or after this date (YYYY-MM-DD or MM/DD/YYYY)")
 	lsCmd.Flags().Bool("continue", false, "Continue searching and fetching posts until no more are found")
+	lsCmd.Flags().Bool("from-index", false, "List posts from the local index kept by 'cringesweeper sync' instead of fetching them")
+	lsCmd.Flags().String("output", "text", "Output format: text, table for a line per post in aligned columns, or csv for a row per post with its date, type, content, URL and engagement counts")
+	lsCmd.Flags().String("sort", "", "Sort each platform's posts by date, likes, reposts or replies, most first, once they've all been fetched")
+	lsCmd.Flags().Bool("reverse", false, "Reverse the sort order, listing the oldest or least popular posts first (sorts by date if --sort isn't given)")
 }
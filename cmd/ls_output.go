@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// Formats ls can list posts in, as given with --output
+const (
+	outputText  = "text"
+	outputTable = "table"
+	outputCSV   = "csv"
+)
+
+// outputFormats are the --output values, in the order they're documented
+var outputFormats = []string{outputText, outputTable, outputCSV}
+
+// Orders ls can sort posts in, as given with --sort. Each puts the most first: the newest
+// posts, or those with the most likes, reposts or replies.
+var sortOrders = map[string]func(a, b internal.Post) int{
+	"date":    func(a, b internal.Post) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	"likes":   func(a, b internal.Post) int { return cmp.Compare(b.LikeCount, a.LikeCount) },
+	"reposts": func(a, b internal.Post) int { return cmp.Compare(b.RepostCount, a.RepostCount) },
+	"replies": func(a, b internal.Post) int { return cmp.Compare(b.ReplyCount, a.ReplyCount) },
+}
+
+// sortBufferLimit is how many posts a sorted listing holds before writing them out. A
+// continuous listing of a larger account is sorted in batches of this many.
+var sortBufferLimit = 10000
+
+// csvHeader names the columns of --output=csv
+var csvHeader = []string{"platform", "id", "date", "type", "content", "url", "likes", "reposts", "replies"}
+
+// Widths of the --output=table columns. They're fixed rather than fitted to the posts so
+// that rows can be written as they're found.
+const (
+	tableTypeWidth    = 11
+	tableContentWidth = 60
+)
+
+// tableRowFormat lays out a row of --output=table: date, type, likes, reposts, replies
+// and content
+var tableRowFormat = fmt.Sprintf("%%-16s  %%-%ds  %%5s  %%7s  %%7s  %%s\n", tableTypeWidth)
+
+// postOutput writes the posts ls lists in the chosen format, a post at a time so that
+// continuous listings stream. Text and tables, which are read in the terminal, go to w
+// along with the messages around them; csv keeps w for the posts alone and sends the
+// messages to status, so the output can be redirected straight into a file.
+type postOutput struct {
+	format string
+	w      *displayWriter // Keeps the first error writing posts, for flush to return
+	status io.Writer
+	csv    *csv.Writer
+	count  int // Posts written for the current platform
+
+	sortName string
+	order    func(a, b internal.Post) int // Nil to write posts in the order they're found
+	sorted   []internal.Post              // Posts held to be sorted
+	batches  int                          // Sorted batches written for the current platform
+}
+
+// newPostOutput creates an output in format, writing posts to w and, for csv, messages
+// to status
+func newPostOutput(format string, w, status io.Writer) (*postOutput, error) {
+	out := &postOutput{format: strings.ToLower(strings.TrimSpace(format)), w: newDisplayWriter(w)}
+	out.status = out.w
+	switch out.format {
+	case outputText, outputTable:
+	case outputCSV:
+		out.status = status
+		out.csv = csv.NewWriter(out.w)
+		if err := out.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format %q: use one of %s", format, strings.Join(outputFormats, ", "))
+	}
+	return out, nil
+}
+
+// sortBy makes the output sort each platform's posts by one of sortOrders, or with an
+// empty name, by date when reverse is set. reverse puts the least first, so the oldest or
+// least popular posts come at the top.
+func (o *postOutput) sortBy(name string, reverse bool) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		if !reverse {
+			return nil
+		}
+		name = "date"
+	}
+	order, ok := sortOrders[name]
+	if !ok {
+		return fmt.Errorf("unknown sort order %q: use one of date, likes, reposts, replies", name)
+	}
+	o.sortName = name
+	o.order = order
+	if reverse {
+		o.order = func(a, b internal.Post) int { return order(b, a) }
+	}
+	return nil
+}
+
+// statusf writes a message about the listing, as opposed to a post in it
+func (o *postOutput) statusf(format string, args ...interface{}) {
+	fmt.Fprintf(o.status, format, args...)
+}
+
+// startPlatform begins the posts of another platform
+func (o *postOutput) startPlatform() {
+	o.count = 0
+	o.batches = 0
+}
+
+// endPlatform writes the posts of the current platform still held for sorting
+func (o *postOutput) endPlatform() {
+	if len(o.sorted) == 0 {
+		return
+	}
+	if o.batches == 0 {
+		o.statusf("\nSorted by %s:\n\n", o.sortName)
+	}
+	o.batches++
+	slices.SortStableFunc(o.sorted, o.order)
+	for _, post := range o.sorted {
+		o.write(post)
+	}
+	o.sorted = o.sorted[:0]
+}
+
+// post writes one post, or when sorting, holds it until the platform's posts are all in
+func (o *postOutput) post(post internal.Post) {
+	if o.order == nil {
+		o.write(post)
+		return
+	}
+	o.sorted = append(o.sorted, post)
+	if len(o.sorted) >= sortBufferLimit {
+		if o.batches == 0 {
+			o.statusf("\nMore than %d posts to sort: writing them in sorted batches of %d\n", sortBufferLimit, sortBufferLimit)
+		}
+		o.endPlatform()
+	}
+}
+
+// write writes a post in the output format
+func (o *postOutput) write(post internal.Post) {
+	o.count++
+	switch o.format {
+	case outputCSV:
+		o.csv.Write(csvRecord(post))
+		o.csv.Flush() // Keep streaming as posts are found
+	case outputTable:
+		if o.count == 1 {
+			fmt.Fprintf(o.w, tableRowFormat, "DATE", "TYPE", "LIKES", "REPOSTS", "REPLIES", "CONTENT")
+		}
+		writeTableRow(o.w, post)
+	default:
+		displaySinglePost(o.w, post, o.count) // o.w keeps any error for flush
+	}
+}
+
+// flush finishes the output, returning any error writing it
+func (o *postOutput) flush() error {
+	if o.csv != nil {
+		o.csv.Flush()
+	}
+	return o.w.err
+}
+
+// listedContent returns the content to list for a post. A repost shows the content of
+// the post it shares, as the text listing does.
+func listedContent(post internal.Post) string {
+	if post.Type == internal.PostTypeRepost && post.OriginalPost != nil {
+		return post.OriginalPost.Content
+	}
+	return post.Content
+}
+
+// csvRecord returns a post's row for --output=csv
+func csvRecord(post internal.Post) []string {
+	content := listedContent(post)
+	return []string{
+		post.Platform,
+		post.ID,
+		post.CreatedAt.UTC().Format(time.RFC3339),
+		string(post.Type),
+		csvSafe(content),
+		post.URL,
+		strconv.Itoa(post.LikeCount),
+		strconv.Itoa(post.RepostCount),
+		strconv.Itoa(post.ReplyCount),
+	}
+}
+
+// writeTableRow writes a post's row for --output=table, in local time like the text
+// listing and with its content cut to one line
+func writeTableRow(w io.Writer, post internal.Post) {
+	postType := string(post.Type)
+	if post.SelfRepost || post.SelfQuote {
+		postType = "self-" + postType
+	}
+	fmt.Fprintf(w, tableRowFormat,
+		post.CreatedAt.Format("2006-01-02 15:04"),
+		truncateContent(postType, tableTypeWidth),
+		strconv.Itoa(post.LikeCount),
+		strconv.Itoa(post.RepostCount),
+		strconv.Itoa(post.ReplyCount),
+		truncateContent(listedContent(post), tableContentWidth))
+}
+
+// csvSafe puts an apostrophe in front of text a spreadsheet would take for a formula,
+// such as a reply that opens with an @mention, so it's shown as written
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
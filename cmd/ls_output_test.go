@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestPostOutput_CSV(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	posts := []internal.Post{
+		{ID: "1", Platform: "bluesky", Type: internal.PostTypeOriginal, Content: "Hello, \"world\"\nsecond line", CreatedAt: created, URL: "https://example.com/1", LikeCount: 5, RepostCount: 2, ReplyCount: 1},
+		{ID: "2", Platform: "bluesky", Type: internal.PostTypeReply, Content: "@alice =SUM(A1)", CreatedAt: created, URL: "https://example.com/2"},
+		{ID: "3", Platform: "bluesky", Type: internal.PostTypeRepost, Content: "", CreatedAt: created, OriginalPost: &internal.Post{Content: "Shared post"}},
+	}
+
+	var w, status bytes.Buffer
+	out, err := newPostOutput("CSV", &w, &status)
+	if err != nil {
+		t.Fatalf("newPostOutput() error = %v", err)
+	}
+	out.statusf("Posts from Bluesky:\n")
+	for _, post := range posts {
+		out.post(post)
+	}
+	if err := out.flush(); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if status.String() != "Posts from Bluesky:\n" {
+		t.Errorf("Expected messages on status, got %q", status.String())
+	}
+	records, err := csv.NewReader(&w).ReadAll()
+	if err != nil {
+		t.Fatalf("Output isn't valid CSV: %v\n%s", err, w.String())
+	}
+	want := [][]string{
+		csvHeader,
+		{"bluesky", "1", "2025-03-01T11:30:00Z", "original", "Hello, \"world\"\nsecond line", "https://example.com/1", "5", "2", "1"},
+		{"bluesky", "2", "2025-03-01T11:30:00Z", "reply", "'@alice =SUM(A1)", "https://example.com/2", "0", "0", "0"},
+		{"bluesky", "3", "2025-03-01T11:30:00Z", "repost", "Shared post", "", "0", "0", "0"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d rows, got %d:\n%s", len(want), len(records), w.String())
+	}
+	for i := range want {
+		if !slices.Equal(records[i], want[i]) {
+			t.Errorf("Row %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestPostOutput_Text(t *testing.T) {
+	var w, status bytes.Buffer
+	out, err := newPostOutput("text", &w, &status)
+	if err != nil {
+		t.Fatalf("newPostOutput() error = %v", err)
+	}
+	out.statusf("Posts from Test:\n\n")
+	out.post(internal.Post{ID: "1", Type: internal.PostTypeOriginal, Content: "Hello world!", CreatedAt: time.Now()})
+	out.startPlatform()
+	out.post(internal.Post{ID: "2", Type: internal.PostTypeOriginal, Content: "Other platform", CreatedAt: time.Now()})
+
+	if status.Len() != 0 {
+		t.Errorf("Expected text output to keep messages with the posts, got %q on status", status.String())
+	}
+	output := w.String()
+	if !strings.HasPrefix(output, "Posts from Test:") || !strings.Contains(output, "Hello world!") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	if strings.Count(output, "Post 1:") != 2 {
+		t.Errorf("Expected numbering to restart for each platform:\n%s", output)
+	}
+}
+
+func TestPostOutput_Table(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 30, 0, 0, time.Local)
+	var w, status bytes.Buffer
+	out, err := newPostOutput("table", &w, &status)
+	if err != nil {
+		t.Fatalf("newPostOutput() error = %v", err)
+	}
+	out.post(internal.Post{Type: internal.PostTypeOriginal, Content: "Short\npost", CreatedAt: created, LikeCount: 12, RepostCount: 3})
+	out.post(internal.Post{Type: internal.PostTypeRepost, SelfRepost: true, CreatedAt: created, OriginalPost: &internal.Post{Content: strings.Repeat("long ", 20)}})
+	out.startPlatform()
+	out.post(internal.Post{Type: internal.PostTypeReply, Content: "Next platform", CreatedAt: created, ReplyCount: 1})
+
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	want := []string{
+		"DATE              TYPE         LIKES  REPOSTS  REPLIES  CONTENT",
+		"2025-03-01 12:30  original        12        3        0  Short post",
+		"2025-03-01 12:30  self-repost      0        0        0  " + strings.Repeat("long ", 11) + "lo...",
+		"DATE              TYPE         LIKES  REPOSTS  REPLIES  CONTENT",
+		"2025-03-01 12:30  reply            0        0        1  Next platform",
+	}
+	if !slices.Equal(lines, want) {
+		t.Errorf("Unexpected table:\n%s\nwant:\n%s", w.String(), strings.Join(want, "\n"))
+	}
+}
+
+func TestNewPostOutput_UnknownFormat(t *testing.T) {
+	_, err := newPostOutput("xml", &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "text, table, csv") {
+		t.Errorf("Expected an error listing the formats, got %v", err)
+	}
+}
+
+func TestPostOutput_WriteError(t *testing.T) {
+	for _, format := range outputFormats {
+		t.Run(format, func(t *testing.T) {
+			out, err := newPostOutput(format, &brokenWriter{limit: 80}, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("newPostOutput() error = %v", err)
+			}
+			for i := range 3 {
+				out.post(internal.Post{ID: strconv.Itoa(i), Content: strings.Repeat("post ", 10)})
+			}
+			if err := out.flush(); err == nil {
+				t.Error("Expected flush to return the write error")
+			}
+		})
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Hello", "Hello"},
+		{"", ""},
+		{"=1+1", "'=1+1"},
+		{"+44 phone", "'+44 phone"},
+		{"-- signed", "'-- signed"},
+		{"@alice hi", "'@alice hi"},
+		{"\tindented", "'\tindented"},
+		{"mid @mention", "mid @mention"},
+	}
+
+	for _, tt := range tests {
+		if got := csvSafe(tt.in); got != tt.want {
+			t.Errorf("csvSafe(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPostOutput_Sort(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	posts := []internal.Post{
+		{ID: "a", CreatedAt: day(3), LikeCount: 1, RepostCount: 5, ReplyCount: 0},
+		{ID: "b", CreatedAt: day(1), LikeCount: 9, RepostCount: 0, ReplyCount: 2},
+		{ID: "c", CreatedAt: day(2), LikeCount: 1, RepostCount: 1, ReplyCount: 7},
+	}
+
+	tests := []struct {
+		sort    string
+		reverse bool
+		want    []string
+	}{
+		{"", false, []string{"a", "b", "c"}},
+		{"", true, []string{"b", "c", "a"}},
+		{"date", false, []string{"a", "c", "b"}},
+		{"likes", false, []string{"b", "a", "c"}},
+		{"Likes", true, []string{"a", "c", "b"}},
+		{"reposts", false, []string{"a", "c", "b"}},
+		{"replies", true, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s reverse=%v", tt.sort, tt.reverse), func(t *testing.T) {
+			var w, status bytes.Buffer
+			out, _ := newPostOutput("csv", &w, &status)
+			if err := out.sortBy(tt.sort, tt.reverse); err != nil {
+				t.Fatalf("sortBy() error = %v", err)
+			}
+			for _, post := range posts {
+				out.post(post)
+			}
+			out.endPlatform()
+			out.flush()
+
+			records, _ := csv.NewReader(&w).ReadAll()
+			var got []string
+			for _, record := range records[1:] {
+				got = append(got, record[1])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Got posts in order %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := (&postOutput{}).sortBy("shares", false); err == nil {
+		t.Error("Expected an unknown sort order to be rejected")
+	}
+}
+
+func TestPostOutput_SortBatches(t *testing.T) {
+	limit := sortBufferLimit
+	sortBufferLimit = 2
+	t.Cleanup(func() { sortBufferLimit = limit })
+
+	var w, status bytes.Buffer
+	out, _ := newPostOutput("csv", &w, &status)
+	out.sortBy("likes", false)
+	for i, likes := range []int{1, 2, 3, 4, 5} {
+		out.post(internal.Post{ID: strconv.Itoa(i), LikeCount: likes})
+	}
+	if got := strings.Count(w.String(), "\n"); got != 5 {
+		t.Errorf("Expected two full batches written before the end, got %d lines:\n%s", got, w.String())
+	}
+	out.endPlatform()
+	out.flush()
+
+	records, _ := csv.NewReader(&w).ReadAll()
+	var got []string
+	for _, record := range records[1:] {
+		got = append(got, record[1])
+	}
+	if !slices.Equal(got, []string{"1", "0", "3", "2", "4"}) {
+		t.Errorf("Expected each batch sorted on its own, got %v", got)
+	}
+	if !strings.Contains(status.String(), "sorted batches of 2") {
+		t.Errorf("Expected a note about batching, got %q", status.String())
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -62,7 +63,7 @@ func TestDisplayPosts(t *testing.T) {
 			}
 		}()
 
-		displayPosts(posts, "TestPlatform")
+		displayPosts(io.Discard, posts, "TestPlatform")
 	})
 
 	t.Run("display empty posts", func(t *testing.T) {
@@ -72,7 +73,7 @@ func TestDisplayPosts(t *testing.T) {
 			}
 		}()
 
-		displayPosts([]internal.Post{}, "TestPlatform")
+		displayPosts(io.Discard, []internal.Post{}, "TestPlatform")
 	})
 }
 
@@ -374,7 +375,7 @@ func TestDisplayPostsWithQuotePost(t *testing.T) {
 			}
 		}()
 
-		displayPosts(posts, "TestPlatform")
+		displayPosts(io.Discard, posts, "TestPlatform")
 	})
 }
 
@@ -400,7 +401,7 @@ func TestDisplayPostsWithLikePost(t *testing.T) {
 			}
 		}()
 
-		displayPosts(posts, "TestPlatform")
+		displayPosts(io.Discard, posts, "TestPlatform")
 	})
 }
 
@@ -482,7 +483,7 @@ func TestDisplayPostsFormattingEdgeCases(t *testing.T) {
 			}
 		}()
 
-		displayPosts(posts, "TestPlatform")
+		displayPosts(io.Discard, posts, "TestPlatform")
 	})
 }
 
@@ -510,7 +511,7 @@ func TestDisplayPostsRepostWithoutOriginal(t *testing.T) {
 			}
 		}()
 
-		displayPosts(posts, "TestPlatform")
+		displayPosts(io.Discard, posts, "TestPlatform")
 	})
 }
 
@@ -569,7 +570,7 @@ func TestFilterPostsByAge(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filtered := filterPostsByAge(posts, tt.maxAge, tt.beforeDate)
+			filtered := filterPostsByAge(posts, tt.maxAge, tt.beforeDate, nil)
 			if len(filtered) != tt.expected {
 				t.Errorf("Expected %d posts, got %d", tt.expected, len(filtered))
 			}
@@ -631,7 +632,7 @@ func TestFilterPostsByAgeWithTermination(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filtered, shouldContinue := filterPostsByAgeWithTermination(posts, tt.maxAge, tt.beforeDate)
+			filtered, shouldContinue := filterPostsByAgeWithTermination(posts, tt.maxAge, tt.beforeDate, nil)
 			
 			if len(filtered) != tt.expectedPosts {
 				t.Errorf("Expected %d posts, got %d", tt.expectedPosts, len(filtered))
@@ -644,6 +645,47 @@ func TestFilterPostsByAgeWithTermination(t *testing.T) {
 	}
 }
 
+// withFakeClock swaps the command clock for a fake one for the duration of the test
+func withFakeClock(t *testing.T, now time.Time) *internal.FakeClock {
+	t.Helper()
+	fake := internal.NewFakeClock(now)
+	previous := clock
+	clock = fake
+	t.Cleanup(func() { clock = previous })
+	return fake
+}
+
+func TestFilterPostsByAgeAsTimePasses(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := withFakeClock(t, start)
+	maxAge := 24 * time.Hour
+	posts := []internal.Post{
+		{ID: "1", CreatedAt: start.Add(-1 * time.Hour)},
+		{ID: "2", CreatedAt: start.Add(-12 * time.Hour)},
+	}
+
+	if filtered := filterPostsByAge(posts, &maxAge, nil, nil); len(filtered) != 2 {
+		t.Fatalf("Expected both posts within max age at start, got %d", len(filtered))
+	}
+
+	// 13 hours later the older post crosses the 24h threshold
+	fake.Advance(13 * time.Hour)
+	filtered := filterPostsByAge(posts, &maxAge, nil, nil)
+	if len(filtered) != 1 || filtered[0].ID != "1" {
+		t.Errorf("Expected only the newer post after 13h, got %v", filtered)
+	}
+
+	filtered, shouldContinue := filterPostsByAgeWithTermination(posts, &maxAge, nil, nil)
+	if len(filtered) != 1 || shouldContinue {
+		t.Errorf("Expected termination at the older post, got %d posts and shouldContinue=%v", len(filtered), shouldContinue)
+	}
+
+	fake.Advance(24 * time.Hour)
+	if filtered := filterPostsByAge(posts, &maxAge, nil, nil); len(filtered) != 0 {
+		t.Errorf("Expected no posts within max age after 37h, got %d", len(filtered))
+	}
+}
+
 func TestDisplaySinglePost(t *testing.T) {
 	// Test that displaySinglePost doesn't panic with various post types
 	posts := []internal.Post{
@@ -699,7 +741,7 @@ func TestDisplaySinglePost(t *testing.T) {
 				}
 			}()
 			
-			displaySinglePost(post, i+1)
+			displaySinglePost(io.Discard, post, i+1)
 		})
 	}
 }
@@ -712,3 +754,38 @@ func durationPtr(d time.Duration) *time.Duration {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestFilterPostsByDateWindow(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time { return time.Date(year, month, d, 12, 0, 0, 0, time.UTC) }
+	posts := []internal.Post{
+		{ID: "2020", CreatedAt: day(2020, 3, 1)},
+		{ID: "late-2019", CreatedAt: day(2019, 11, 5)},
+		{ID: "early-2019", CreatedAt: day(2019, 1, 2)},
+		{ID: "2018", CreatedAt: day(2018, 6, 1)},
+		{ID: "2017", CreatedAt: day(2017, 6, 1)},
+	}
+	after := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ids := func(posts []internal.Post) string {
+		var ids []string
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	if got := ids(filterPostsByAge(posts, nil, &before, &after)); got != "late-2019,early-2019" {
+		t.Errorf("filterPostsByAge() = %s, want the 2019 posts", got)
+	}
+
+	// Newer posts are skipped without stopping, and the first one before the window ends the scan
+	filtered, shouldContinue := filterPostsByAgeWithTermination(posts, nil, &before, &after)
+	if got := ids(filtered); got != "late-2019,early-2019" || shouldContinue {
+		t.Errorf("filterPostsByAgeWithTermination() = %s, %v, want the 2019 posts and to stop", got, shouldContinue)
+	}
+	filtered, shouldContinue = filterPostsByAgeWithTermination(posts[:1], nil, &before, &after)
+	if len(filtered) != 0 || !shouldContinue {
+		t.Errorf("Expected a page newer than the window to be skipped but continued, got %d posts and %v", len(filtered), shouldContinue)
+	}
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+var nukeCmd = &cobra.Command{
+	Use:   "nuke [username]",
+	Short: "Wipe everything from an account: posts, replies, reposts and likes",
+	Long: `Delete every post, reply and quote on an account, undo every repost and
+unlike every like, whatever their age and whatever preservation rules prune
+would apply. On Mastodon and GoToSocial the media attached to deleted posts is
+removed with them where the server supports it; Bluesky removes media once no
+post uses it. The account itself, its profile, follows and followers are left
+alone.
+
+This can't be undone, so nuke asks more than once: a yes/no question, which
+--yes skips, and then the account name, which has to be typed out or given with
+--confirm-account even with --yes. Then, as prune does, it shows how long the
+wipe will take and asks once more.
+
+A large account takes several passes and a long time. After each pass nuke
+saves a checkpoint in ~/.config/cringesweeper/nuke and carries on until a pass
+finds nothing left. When the platform rate limits it, nuke waits for
+--rate-limit-wait before the next pass. If it's stopped, by Ctrl-C, a crash or
+--max-passes, running the same command again resumes the wipe after a single
+yes/no question, skipping everything already removed. Once an account has been
+wiped, nuke won't start on it again without --restart.
+
+Use --dry-run first to see what would be removed. Requires authentication, and
+only works on your own account. Settings in the config file don't apply to nuke:
+everything it does is given on the command line.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{skipConfigAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		rateLimitWaitStr, _ := cmd.Flags().GetString("rate-limit-wait")
+		progressIntervalStr, _ := cmd.Flags().GetString("progress-interval")
+
+		var settings nukeSettings
+		settings.dryRun, _ = cmd.Flags().GetBool("dry-run")
+		settings.confirmAccount, _ = cmd.Flags().GetString("confirm-account")
+		settings.maxPasses, _ = cmd.Flags().GetInt("max-passes")
+		settings.restart, _ = cmd.Flags().GetBool("restart")
+		settings.acceptInstanceRules, _ = cmd.Flags().GetBool("accept-instance-rules")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+		if settings.maxPasses < 1 {
+			exitWithError(fmt.Errorf("--max-passes must be at least 1"))
+		}
+
+		settings.rateLimitWait, err = timespec.ParseDuration(rateLimitWaitStr)
+		if err != nil {
+			exitWithError(fmt.Errorf("error parsing rate-limit-wait: %w", err))
+		}
+		if delayStr, _ := cmd.Flags().GetString("rate-limit-delay"); delayStr != "" {
+			settings.rateLimitDelay, err = timespec.ParseDuration(delayStr)
+			if err != nil {
+				exitWithError(fmt.Errorf("error parsing rate-limit-delay: %w", err))
+			}
+		}
+		settings.progressEvery, settings.progressInterval, err = internal.ParseProgressInterval(progressIntervalStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		gate := newConfirmGate(bufio.NewReader(os.Stdin), cmd.OutOrStdout())
+		failed := false
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			if err := runNuke(cmd.Context(), gate, platformName, argUsername, settings); err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				failed = true
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// nukeSettings are the nuke command's flags, parsed
+type nukeSettings struct {
+	dryRun              bool
+	confirmAccount      string
+	maxPasses           int
+	rateLimitWait       time.Duration
+	rateLimitDelay      time.Duration // Zero for the platform's default
+	progressEvery       int
+	progressInterval    time.Duration
+	restart             bool
+	acceptInstanceRules bool
+}
+
+// runNuke wipes, or with --dry-run previews wiping, the account on one platform
+func runNuke(ctx context.Context, gate *confirmGate, platformName, argUsername string, settings nukeSettings) error {
+	w := gate.w
+
+	username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+	if err != nil {
+		return err
+	}
+	client, exists := internal.GetClient(platformName)
+	if !exists {
+		return fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+	}
+
+	// Above all, make sure the wipe is of the account the user means
+	if mismatch := internal.CheckCredentialMismatch(platformName); mismatch != nil {
+		warnCredentialMismatch(w, mismatch)
+	}
+
+	options := internal.NukeOptions()
+	options.DryRun = settings.dryRun
+	options.Output = w
+	options.RateLimitDelay = settings.rateLimitDelay
+	if options.RateLimitDelay == 0 {
+		options.RateLimitDelay = defaultRateLimitDelay(platformName)
+	}
+	options.ProgressEvery = settings.progressEvery
+	options.ProgressInterval = settings.progressInterval
+
+	if settings.dryRun {
+		fmt.Fprintf(w, "DRY RUN: looking for everything nuke would remove from @%s on %s...\n", username, client.GetPlatformName())
+		result, err := client.PrunePosts(ctx, username, options)
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", client.GetPlatformName(), err)
+		}
+		return displayPruneResults(w, result, client.GetPlatformName(), true)
+	}
+
+	store := internal.DefaultNukeCheckpointStore()
+	if store == nil {
+		return fmt.Errorf("can't keep checkpoints without a home directory, so a wipe couldn't be resumed; not starting one")
+	}
+	checkpoint, err := store.Load(platformName, username)
+	if err != nil {
+		return err
+	}
+	if checkpoint != nil && checkpoint.Complete && !settings.restart {
+		fmt.Fprintf(w, "@%s on %s was already wiped on %s (%d removed over %d passes). Use --restart to go over it again.\n",
+			username, client.GetPlatformName(), checkpoint.UpdatedAt.Format("2006-01-02"), checkpoint.Removed(), checkpoint.Passes)
+		return nil
+	}
+
+	if err := ensureInstanceRulesAcknowledged(ctx, w, client, username, settings.acceptInstanceRules, true); err != nil {
+		return err
+	}
+
+	if checkpoint != nil && !checkpoint.Complete && !settings.restart {
+		// Carrying on with a wipe that was already confirmed only needs a yes
+		fmt.Fprintf(w, "Resuming the wipe of @%s on %s started %s: %d removed over %d passes so far.\n",
+			username, client.GetPlatformName(), checkpoint.StartedAt.Format("2006-01-02 15:04"), checkpoint.Removed(), checkpoint.Passes)
+		if checkpoint.LastError != "" {
+			fmt.Fprintf(w, "The last pass stopped with: %s\n", checkpoint.LastError)
+		}
+		if !gate.Confirm(fmt.Sprintf("About to carry on wiping @%s", username)) {
+			fmt.Fprintf(w, "Cancelled, nothing more was removed from %s.\n", client.GetPlatformName())
+			return nil
+		}
+	} else {
+		if err := printNukeWarning(w, client.GetPlatformName(), username); err != nil {
+			return err
+		}
+		if !gate.Confirm(fmt.Sprintf("About to permanently remove everything from @%s on %s", username, client.GetPlatformName())) {
+			fmt.Fprintf(w, "Cancelled, nothing was changed on %s.\n", client.GetPlatformName())
+			return nil
+		}
+		if !confirmAccountName(gate.reader, w, username, settings.confirmAccount) {
+			return fmt.Errorf("the account name given doesn't match @%s, so nothing was changed", username)
+		}
+		options.ConfirmRun = newRunPrompter(gate)
+		checkpoint = internal.NewNukeCheckpoint(platformName, username, clock.Now())
+	}
+
+	result, err := nukeAccount(ctx, w, client, username, options, checkpoint, store, settings.maxPasses, settings.rateLimitWait)
+
+	// Keep the local index, if there is one, from listing what was just deleted
+	if result.DeletedCount > 0 {
+		if err := internal.DropDeletedFromIndex(platformName, username); err != nil {
+			internal.WithPlatform(platformName).Warn().Err(err).Msg("Failed to update local post index")
+		}
+	}
+	return err
+}
+
+// nukeAccount runs wipe passes over an account until one finds nothing left to remove,
+// recording each pass in checkpoint and saving it to store. A pass the platform rate
+// limited is followed by a wait of rateLimitWait; after maxPasses it stops, leaving the
+// rest for a resumed run. It returns what the passes did between them.
+func nukeAccount(ctx context.Context, w io.Writer, client internal.SocialClient, username string, options internal.PruneOptions, checkpoint *internal.NukeCheckpoint, store *internal.NukeCheckpointStore, maxPasses int, rateLimitWait time.Duration) (*internal.PruneResult, error) {
+	platform := client.GetPlatformName()
+	total := &internal.PruneResult{}
+
+	// The estimate is only asked about before the first pass
+	declined := false
+	if confirm := options.ConfirmRun; confirm != nil {
+		options.ConfirmRun = func(name string, plan internal.PacingPlan) bool {
+			declined = !confirm(name, plan)
+			return !declined
+		}
+	}
+
+	save := func() {
+		if err := store.Save(checkpoint); err != nil {
+			internal.WithPlatform(checkpoint.Platform).Warn().Err(err).Msg("Failed to save nuke checkpoint")
+			fmt.Fprintf(w, "⚠️  Failed to save the checkpoint, a re-run will start the count again: %v\n", err)
+		}
+	}
+
+	for pass := 1; pass <= maxPasses; pass++ {
+		fmt.Fprintf(w, "\n💣 Pass %d on %s (%d removed so far)...\n", checkpoint.Passes+1, platform, checkpoint.Removed())
+		result, err := client.PrunePosts(ctx, username, options)
+		if declined {
+			return total, nil
+		}
+		if err != nil {
+			checkpoint.LastError = err.Error()
+			checkpoint.UpdatedAt = clock.Now()
+			save()
+			fmt.Fprintf(w, "Run nuke again to carry on from here.\n")
+			return total, fmt.Errorf("wiping %s: %w", platform, err)
+		}
+		options.ConfirmRun = nil
+
+		mergePruneResult(total, result)
+		checkpoint.Record(result, clock.Now())
+		acted := result.DeletedCount + result.UnlikedCount + result.UnsharedCount
+		checkpoint.Complete = acted == 0 && result.ErrorsCount == 0 && !result.StoppedEarly
+		save()
+
+		fmt.Fprintf(w, "Pass %d: %d deleted, %d unliked, %d unshared, %d errors\n",
+			checkpoint.Passes, result.DeletedCount, result.UnlikedCount, result.UnsharedCount, result.ErrorsCount)
+		if checkpoint.Complete {
+			fmt.Fprintf(w, "✅ Nothing left to remove from @%s on %s: %d deleted, %d unliked, %d unshared over %d passes\n",
+				username, platform, checkpoint.Deleted, checkpoint.Unliked, checkpoint.Unshared, checkpoint.Passes)
+			return total, nil
+		}
+		if result.StoppedEarly && acted == 0 && result.ErrorsCount == 0 {
+			break // Stopped before doing anything, as when shutting down, so another pass won't help
+		}
+
+		if result.RateLimitedCount > 0 && pass < maxPasses {
+			fmt.Fprintf(w, "⏳ %s is rate limiting requests, waiting %s before the next pass (Ctrl-C stops; run nuke again to resume)\n", platform, rateLimitWait)
+			select {
+			case <-ctx.Done():
+				checkpoint.LastError = "interrupted while waiting out a rate limit"
+				save()
+				return total, ctx.Err()
+			case <-clock.After(rateLimitWait):
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "⚠️  Stopped after %d passes with things still left on %s. Run nuke again to carry on.\n", checkpoint.Passes, platform)
+	return total, nil
+}
+
+// printNukeWarning spells out what a wipe does before the first confirmation
+func printNukeWarning(w io.Writer, platform, username string) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, "\n⚠️  WARNING: this permanently removes EVERYTHING from @%s on %s:\n", username, platform)
+	fmt.Fprintln(w, "   • every post, reply and quote, however old, pinned or popular")
+	fmt.Fprintln(w, "   • every repost and like")
+	fmt.Fprintln(w, "   • the media attached to deleted posts, where the platform allows it")
+	fmt.Fprintln(w, "   None of it can be brought back. Preservation rules and filters don't apply.")
+	fmt.Fprintln(w)
+	return out.err
+}
+
+// confirmAccountName makes the user type the account being wiped, or checks the name
+// given with --confirm-account. This is asked even with --yes, so a stray flag can
+// never start a wipe on its own.
+func confirmAccountName(reader *bufio.Reader, w io.Writer, username, given string) bool {
+	if given == "" {
+		fmt.Fprintf(w, "Type the account name (%s) to confirm: ", username)
+		input, err := reader.ReadString('\n')
+		if err != nil && strings.TrimSpace(input) == "" {
+			fmt.Fprintln(w)
+		}
+		given = input
+	}
+	return strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(given), "@"), strings.TrimPrefix(username, "@"))
+}
+
+func init() {
+	rootCmd.AddCommand(nukeCmd)
+	nukeCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	nukeCmd.Flags().Bool("dry-run", false, "Show everything that would be removed without removing anything")
+	nukeCmd.Flags().String("confirm-account", "", "The account name being wiped, instead of typing it when asked")
+	nukeCmd.Flags().Int("max-passes", 10, "Stop after this many passes over the account; run nuke again to carry on")
+	nukeCmd.Flags().String("rate-limit-wait", "15m", "How long to wait before another pass when the platform rate limits requests")
+	nukeCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 2s for Mastodon and GoToSocial, 1s for Bluesky)")
+	nukeCmd.Flags().String("progress-interval", "", "Print a progress summary every N posts and/or after a duration (e.g., 100, 30s, 100,30s) instead of a line per post")
+	nukeCmd.Flags().Bool("restart", false, "Start a new wipe even if the account was already wiped or a wipe was left unfinished")
+	nukeCmd.Flags().Bool("accept-instance-rules", false, "Acknowledge the instance's rules without prompting before the wipe")
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// rateLimitedClient holds posts to wipe and deletes at most perPass of them each run,
+// reporting the rest as rate limited
+type rateLimitedClient struct {
+	internal.SocialClient
+	posts   int
+	perPass int
+	runs    int
+}
+
+func (c *rateLimitedClient) GetPlatformName() string { return "Test" }
+
+func (c *rateLimitedClient) PrunePosts(ctx context.Context, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+	c.runs++
+	result := &internal.PruneResult{}
+	if c.posts > 0 && options.ConfirmRun != nil && !options.ConfirmRun("Test", internal.PacingPlan{Deletes: c.posts}) {
+		result.StoppedEarly = true
+		return result, nil
+	}
+	result.DeletedCount = min(c.posts, c.perPass)
+	result.ErrorsCount = c.posts - result.DeletedCount
+	result.RateLimitedCount = result.ErrorsCount
+	c.posts -= result.DeletedCount
+	return result, nil
+}
+
+func TestNukeAccount(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("passes until nothing is left", func(t *testing.T) {
+		withFakeClock(t, start)
+		store := internal.NewNukeCheckpointStoreAt(t.TempDir())
+		client := &rateLimitedClient{posts: 5, perPass: 2}
+		checkpoint := internal.NewNukeCheckpoint("test", "me", start)
+		var out bytes.Buffer
+
+		result, err := nukeAccount(context.Background(), &out, client, "me", internal.NukeOptions(), checkpoint, store, 10, 0)
+		if err != nil {
+			t.Fatalf("nukeAccount() error = %v", err)
+		}
+		// Three passes to delete the posts, and one to find nothing left
+		if client.runs != 4 || result.DeletedCount != 5 || result.RateLimitedCount != 4 {
+			t.Errorf("Expected 4 passes deleting 5 posts, got %d passes and %+v", client.runs, result)
+		}
+		if !strings.Contains(out.String(), "rate limiting requests") || !strings.Contains(out.String(), "Nothing left to remove") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+
+		saved, err := store.Load("test", "me")
+		if err != nil || saved == nil {
+			t.Fatalf("Expected a saved checkpoint, got %+v, %v", saved, err)
+		}
+		if !saved.Complete || saved.Passes != 4 || saved.Deleted != 5 {
+			t.Errorf("Expected a complete checkpoint after 4 passes, got %+v", saved)
+		}
+	})
+
+	t.Run("stops after max passes and resumes", func(t *testing.T) {
+		withFakeClock(t, start)
+		store := internal.NewNukeCheckpointStoreAt(t.TempDir())
+		client := &rateLimitedClient{posts: 5, perPass: 2}
+		checkpoint := internal.NewNukeCheckpoint("test", "me", start)
+		var out bytes.Buffer
+
+		if _, err := nukeAccount(context.Background(), &out, client, "me", internal.NukeOptions(), checkpoint, store, 2, 0); err != nil {
+			t.Fatalf("nukeAccount() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "Stopped after 2 passes") {
+			t.Errorf("Expected to be told the wipe isn't finished, got:\n%s", out.String())
+		}
+
+		resumed, _ := store.Load("test", "me")
+		if resumed == nil || resumed.Complete || resumed.Deleted != 4 {
+			t.Fatalf("Expected an unfinished checkpoint with 4 deleted, got %+v", resumed)
+		}
+		if _, err := nukeAccount(context.Background(), &out, client, "me", internal.NukeOptions(), resumed, store, 10, 0); err != nil {
+			t.Fatalf("nukeAccount() error = %v", err)
+		}
+		if saved, _ := store.Load("test", "me"); !saved.Complete || saved.Passes != 4 || saved.Deleted != 5 {
+			t.Errorf("Expected the resumed wipe to finish the totals, got %+v", saved)
+		}
+	})
+
+	t.Run("interrupted while waiting out a rate limit", func(t *testing.T) {
+		withFakeClock(t, start)
+		store := internal.NewNukeCheckpointStoreAt(t.TempDir())
+		client := &rateLimitedClient{posts: 5, perPass: 2}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var out bytes.Buffer
+
+		_, err := nukeAccount(ctx, &out, client, "me", internal.NukeOptions(), internal.NewNukeCheckpoint("test", "me", start), store, 10, time.Hour)
+		if !errors.Is(err, context.Canceled) || client.runs != 1 {
+			t.Fatalf("Expected to stop after one pass when cancelled, got %v after %d passes", err, client.runs)
+		}
+		if saved, _ := store.Load("test", "me"); saved == nil || saved.Complete || saved.LastError == "" {
+			t.Errorf("Expected the checkpoint to say why the wipe stopped, got %+v", saved)
+		}
+	})
+
+	t.Run("declining the estimate changes nothing", func(t *testing.T) {
+		withFakeClock(t, start)
+		store := internal.NewNukeCheckpointStoreAt(t.TempDir())
+		client := &rateLimitedClient{posts: 5, perPass: 2}
+		var out bytes.Buffer
+		options := internal.NukeOptions()
+		options.ConfirmRun = newRunPrompter(&confirmGate{reader: bufio.NewReader(strings.NewReader("n\n")), w: &out})
+
+		if _, err := nukeAccount(context.Background(), &out, client, "me", options, internal.NewNukeCheckpoint("test", "me", start), store, 10, 0); err != nil {
+			t.Fatalf("nukeAccount() error = %v", err)
+		}
+		if client.posts != 5 || client.runs != 1 {
+			t.Errorf("Expected one declined pass and nothing deleted, got %d runs and %d posts left", client.runs, client.posts)
+		}
+		if saved, _ := store.Load("test", "me"); saved != nil {
+			t.Errorf("Expected no checkpoint for a wipe that never started, got %+v", saved)
+		}
+	})
+}
+
+func TestConfirmAccountName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		given string
+		want  bool
+	}{
+		{"typed", "me.bsky.social\n", "", true},
+		{"typed with @ and different case", "@Me.bsky.social\n", "", true},
+		{"typed wrong", "someone.else\n", "", false},
+		{"no input", "", "", false},
+		{"given with --confirm-account", "", "me.bsky.social", true},
+		{"wrong --confirm-account", "me.bsky.social\n", "other", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := confirmAccountName(bufio.NewReader(strings.NewReader(tt.input)), &out, "me.bsky.social", tt.given); got != tt.want {
+				t.Errorf("confirmAccountName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
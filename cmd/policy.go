@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect the prune policy set in a config file",
+	Long:  `Commands for checking the prune policy that a config file sets up before it deletes anything.`,
+}
+
+var policyLintCmd = &cobra.Command{
+	Use:   "lint [config-file]",
+	Short: "Validate a config file and show the policy it gives each platform",
+	Long: `Check a config file for mistakes and print the prune policy it results in for
+each platform, after shared settings, the command's section and any platform
+sections have been combined.
+
+Besides settings that don't parse, lint reports rules that contradict each
+other, such as a hashtag that is both required for pruning and preserved, and
+settings that are silently ignored, such as a section for a platform that isn't
+in platforms.
+
+The file defaults to --config, then ~/.config/cringesweeper/config.yaml. The
+policy is worked out for the server command unless --command=prune is given.
+Exits with status 1 if any problems are found; warnings alone don't fail.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{skipConfigAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		commandName, _ := cmd.Flags().GetString("command")
+		var target *cobra.Command
+		switch commandName {
+		case "server":
+			target = serverCmd
+		case "prune":
+			target = pruneCmd
+		default:
+			exitWithError(fmt.Errorf("invalid --command %q: use server or prune", commandName))
+		}
+
+		path := configFile
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if path == "" {
+			defaultPath, err := internal.DefaultConfigPath()
+			if err != nil {
+				exitWithError(err)
+			}
+			path = defaultPath
+		}
+
+		config, err := internal.LoadConfigFile(path)
+		if err != nil {
+			exitWithError(fmt.Errorf("failed to load config file: %w", err))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Linting %s for %s\n\n", path, target.Name())
+		if problems := lintPolicy(cmd.OutOrStdout(), target, config); problems > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// lintPolicy applies the config to target's flags, then prints each platform's effective
+// policy along with anything wrong with it. It returns the number of problems found.
+func lintPolicy(w io.Writer, target *cobra.Command, config *internal.Config) int {
+	// Without a platforms setting, show what every platform would get
+	_, inValues := config.Values["platforms"]
+	_, inSection := config.Sections[target.Name()]["platforms"]
+	if !inValues && !inSection && !target.Flags().Changed("platforms") {
+		target.Flags().Set("platforms", "all")
+	}
+
+	if err := applyConfig(target, config); err != nil {
+		fmt.Fprintf(w, "❌ %v\n", err)
+		return 1
+	}
+
+	platformsStr, _ := target.Flags().GetString("platforms")
+	platforms, err := internal.ParsePlatforms(platformsStr)
+	if err != nil {
+		fmt.Fprintf(w, "❌ invalid platforms: %v\n", err)
+		return 1
+	}
+
+	problems, warnings := 0, 0
+	for _, name := range slices.Sorted(maps.Keys(config.Sections)) {
+		if _, isPlatform := internal.SupportedPlatforms[name]; !isPlatform {
+			continue
+		}
+		switch {
+		case target.Flags().Lookup(name+".max-post-age") == nil:
+			fmt.Fprintf(w, "⚠️  the %s section is ignored: %s doesn't support per-platform settings\n", name, target.Name())
+			warnings++
+		case !slices.Contains(platforms, name):
+			fmt.Fprintf(w, "⚠️  the %s section is ignored: %s isn't in platforms\n", name, name)
+			warnings++
+		}
+	}
+	if warnings > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, platform := range platforms {
+		fmt.Fprintf(w, "%s:\n", platform)
+		options, err := platformPruneOptions(target, platform)
+		if err != nil {
+			fmt.Fprintf(w, "  ❌ %v\n\n", err)
+			problems++
+			continue
+		}
+
+		for _, name := range platformOverridableFlags {
+			fmt.Fprintf(w, "  %s: %s\n", name, describePolicySetting(options, name))
+		}
+
+		platformProblems, platformWarnings := policyConflicts(options, clock.Now())
+		for _, problem := range platformProblems {
+			fmt.Fprintf(w, "  ❌ %s\n", problem)
+		}
+		for _, warning := range platformWarnings {
+			fmt.Fprintf(w, "  ⚠️  %s\n", warning)
+		}
+		problems += len(platformProblems)
+		warnings += len(platformWarnings)
+		fmt.Fprintln(w)
+	}
+
+	switch {
+	case problems > 0:
+		fmt.Fprintf(w, "❌ Found %d problem(s) and %d warning(s)\n", problems, warnings)
+	case warnings > 0:
+		fmt.Fprintf(w, "⚠️  No problems, but %d warning(s)\n", warnings)
+	default:
+		fmt.Fprintln(w, "✅ No problems found")
+	}
+	return problems
+}
+
+// describePolicySetting formats one overridable setting from the effective options
+func describePolicySetting(options internal.PruneOptions, name string) string {
+	const notSet = "not set"
+	threshold := func(value *int) string {
+		if value == nil {
+			return notSet
+		}
+		return strconv.Itoa(*value)
+	}
+	hashtags := func(tags []string) string {
+		if len(tags) == 0 {
+			return notSet
+		}
+		return "#" + strings.Join(tags, ", #")
+	}
+	list := func(values []string) string {
+		if len(values) == 0 {
+			return notSet
+		}
+		return strings.Join(values, ", ")
+	}
+
+	switch name {
+	case "max-post-age":
+		if options.MaxAge == nil {
+			return notSet
+		}
+		return timespec.Format(*options.MaxAge)
+	case "before-date":
+		if options.BeforeDate == nil {
+			return notSet
+		}
+		return options.BeforeDate.Format(time.RFC3339)
+	case "after-date":
+		if options.AfterDate == nil {
+			return notSet
+		}
+		return options.AfterDate.Format(time.RFC3339)
+	case "preserve-selflike":
+		return strconv.FormatBool(options.PreserveSelfLike)
+	case "preserve-pinned":
+		return strconv.FormatBool(options.PreservePinned)
+	case "preserve-with-replies":
+		return strconv.FormatBool(options.PreserveWithReplies)
+	case "preserve-hashtags":
+		return hashtags(options.PreserveHashtags)
+	case "with-hashtags":
+		return hashtags(options.WithHashtags)
+	case "preserve-language":
+		return list(options.PreserveLanguages)
+	case "language":
+		return list(options.WithLanguages)
+	case "media-only":
+		return strconv.FormatBool(options.MediaOnly)
+	case "skip-media":
+		return strconv.FormatBool(options.SkipMedia)
+	case "with-links":
+		return strconv.FormatBool(options.WithLinks)
+	case "links-to":
+		return list(options.LinksTo)
+	case "replies-only":
+		return strconv.FormatBool(options.RepliesOnly)
+	case "skip-replies":
+		return strconv.FormatBool(options.SkipReplies)
+	case "only-sensitive":
+		return strconv.FormatBool(options.OnlySensitive)
+	case "preserve-cw":
+		return strconv.FormatBool(options.PreserveCW)
+	case "visibility":
+		if len(options.Visibilities) == 0 {
+			return notSet
+		}
+		return strings.Join(options.Visibilities, ", ")
+	case "preserve-direct":
+		return strconv.FormatBool(options.PreserveDirect)
+	case "exclude-file":
+		if len(options.ExcludePosts) == 0 {
+			return notSet
+		}
+		return fmt.Sprintf("%d post(s)", len(options.ExcludePosts))
+	case "liked-post-age":
+		return strconv.FormatBool(options.LikedPostAge)
+	case "unlike-posts":
+		return strconv.FormatBool(options.UnlikePosts)
+	case "redact":
+		return strconv.FormatBool(options.Redact)
+	case "unshare-reposts":
+		return strconv.FormatBool(options.UnshareReposts)
+	case "unshare-self-reposts":
+		return strconv.FormatBool(options.UnshareSelfReposts)
+	case "skip-self-reposts":
+		return strconv.FormatBool(options.SkipSelfReposts)
+	case "only-self-reposts":
+		return strconv.FormatBool(options.OnlySelfReposts)
+	case "delete-whole-threads":
+		return strconv.FormatBool(options.DeleteWholeThreads)
+	case "max-likes":
+		return threshold(options.MaxLikes)
+	case "max-reposts":
+		return threshold(options.MaxReposts)
+	case "max-replies":
+		return threshold(options.MaxReplies)
+	case "rate-limit-delay":
+		return timespec.Format(options.RateLimitDelay)
+	}
+	return notSet
+}
+
+// policyConflicts finds rules that can never take effect together. Problems mean the
+// policy can't prune anything; warnings are likely to do something other than intended.
+func policyConflicts(options internal.PruneOptions, now time.Time) (problems, warnings []string) {
+	if len(options.WithHashtags) > 0 {
+		var preserved []string
+		for _, tag := range options.WithHashtags {
+			if slices.Contains(options.PreserveHashtags, tag) {
+				preserved = append(preserved, "#"+tag)
+			}
+		}
+		switch {
+		case len(preserved) == len(options.WithHashtags):
+			problems = append(problems, "every with-hashtags tag is also in preserve-hashtags, so no post can be pruned")
+		case len(preserved) > 0:
+			warnings = append(warnings, fmt.Sprintf("%s in both with-hashtags and preserve-hashtags, so posts tagged only with them are never pruned", strings.Join(preserved, ", ")))
+		}
+	}
+
+	if len(options.WithLanguages) > 0 {
+		var preserved []string
+		for _, lang := range options.WithLanguages {
+			if slices.Contains(options.PreserveLanguages, lang) {
+				preserved = append(preserved, lang)
+			}
+		}
+		switch {
+		case len(preserved) == len(options.WithLanguages):
+			problems = append(problems, "every language is also in preserve-language, so no post can be pruned")
+		case len(preserved) > 0:
+			warnings = append(warnings, fmt.Sprintf("%s in both language and preserve-language, so posts only in them are never pruned", strings.Join(preserved, ", ")))
+		}
+	}
+
+	if options.OnlySensitive && options.PreserveCW {
+		warnings = append(warnings, "only-sensitive and preserve-cw are both set: only posts marked sensitive without a content warning are pruned")
+	}
+
+	if options.PreserveDirect && slices.Contains(options.Visibilities, internal.VisibilityDirect) {
+		if len(options.Visibilities) == 1 {
+			problems = append(problems, "visibility is only direct and preserve-direct is set, so no post can be pruned")
+		} else {
+			warnings = append(warnings, "visibility includes direct but preserve-direct is set, so direct messages are never pruned")
+		}
+	}
+
+	if options.MaxAge != nil && options.BeforeDate != nil {
+		warnings = append(warnings, "max-post-age and before-date are both set: a post matching either one is pruned")
+	}
+	if options.MaxAge != nil && *options.MaxAge == 0 {
+		warnings = append(warnings, "max-post-age is 0, so every post is old enough to prune")
+	}
+	if options.BeforeDate != nil && options.BeforeDate.After(now) {
+		warnings = append(warnings, "before-date is in the future, so every post is old enough to prune")
+	}
+	if options.AfterDate != nil && options.AfterDate.After(now) {
+		problems = append(problems, "after-date is in the future, so no post can be pruned")
+	}
+	return problems, warnings
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyLintCmd)
+	policyLintCmd.Flags().String("command", "server", "Command whose policy to work out: server or prune")
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestLintPolicy(t *testing.T) {
+	resetFlagsAfter(t, serverCmd)
+	withFakeClock(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	config := mustParseConfig(t, `
+platforms: [bluesky, mastodon]
+max-post-age: 90d
+preserve-pinned: true
+preserve-hashtags: [keep]
+bluesky:
+  with-hashtags: [keep, old]
+  max-likes: 5
+mastodon:
+  max-post-age: 1y6mo
+  before-date: 2025-01-01
+`)
+
+	var buf bytes.Buffer
+	if problems := lintPolicy(&buf, serverCmd, config); problems != 0 {
+		t.Errorf("Expected no problems, got %d", problems)
+	}
+	assertGolden(t, "policy_lint", buf.Bytes())
+}
+
+func TestLintPolicyProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		config   string
+		problems int
+		contains string
+	}{
+		{
+			name:     "defaults to all platforms",
+			config:   "max-post-age: 30d\n",
+			contains: "✅ No problems found",
+		},
+		{
+			name:     "unknown setting",
+			config:   "max-post-agee: 30d\n",
+			problems: 1,
+			contains: "max-post-agee",
+		},
+		{
+			name:     "no age criterion for one platform",
+			config:   "platforms: [bluesky, mastodon]\nbluesky:\n  max-post-age: 30d\n",
+			problems: 1,
+			contains: "mastodon:\n  ❌",
+		},
+		{
+			name:     "every required hashtag preserved",
+			config:   "platforms: [bluesky]\nmax-post-age: 30d\nwith-hashtags: [old]\npreserve-hashtags: [old]\n",
+			problems: 1,
+			contains: "no post can be pruned",
+		},
+		{
+			name:     "every language preserved",
+			config:   "platforms: [bluesky]\nmax-post-age: 30d\nlanguage: [en]\npreserve-language: [en]\n",
+			problems: 1,
+			contains: "no post can be pruned",
+		},
+		{
+			name:     "only direct messages, all preserved",
+			config:   "platforms: [mastodon]\nmax-post-age: 30d\nvisibility: direct\npreserve-direct: true\n",
+			problems: 1,
+			contains: "no post can be pruned",
+		},
+		{
+			name:     "section for a platform not in use",
+			config:   "platforms: [bluesky]\nmax-post-age: 30d\nmastodon:\n  max-likes: 2\n",
+			contains: "the mastodon section is ignored: mastodon isn't in platforms",
+		},
+		{
+			name:     "platform sections ignored by prune",
+			target:   "prune",
+			config:   "platforms: [bluesky]\nmax-post-age: 30d\nbluesky:\n  max-likes: 2\n",
+			contains: "prune doesn't support per-platform settings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := serverCmd
+			if tt.target == "prune" {
+				target = pruneCmd
+			}
+			resetFlagsAfter(t, target)
+
+			var buf bytes.Buffer
+			problems := lintPolicy(&buf, target, mustParseConfig(t, tt.config))
+			if problems != tt.problems {
+				t.Errorf("Expected %d problem(s), got %d:\n%s", tt.problems, problems, buf.String())
+			}
+			if !strings.Contains(buf.String(), tt.contains) {
+				t.Errorf("Expected output to contain %q:\n%s", tt.contains, buf.String())
+			}
+		})
+	}
+}
+
+func TestPolicyConflicts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	zero := time.Duration(0)
+	month := 30 * 24 * time.Hour
+	past := now.AddDate(-1, 0, 0)
+	future := now.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name     string
+		options  internal.PruneOptions
+		problems int
+		warnings int
+	}{
+		{"plain age", internal.PruneOptions{MaxAge: &month}, 0, 0},
+		{"all hashtags preserved", internal.PruneOptions{MaxAge: &month, WithHashtags: []string{"a"}, PreserveHashtags: []string{"a", "b"}}, 1, 0},
+		{"some hashtags preserved", internal.PruneOptions{MaxAge: &month, WithHashtags: []string{"a", "c"}, PreserveHashtags: []string{"a"}}, 0, 1},
+		{"age and date", internal.PruneOptions{MaxAge: &month, BeforeDate: &past}, 0, 1},
+		{"zero age", internal.PruneOptions{MaxAge: &zero}, 0, 1},
+		{"future date", internal.PruneOptions{BeforeDate: &future}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, warnings := policyConflicts(tt.options, now)
+			if len(problems) != tt.problems || len(warnings) != tt.warnings {
+				t.Errorf("Expected %d problem(s) and %d warning(s), got %q and %q", tt.problems, tt.warnings, problems, warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+// policySectionPrefix starts the name of a config file section that changes a policy
+// preset, or defines a new one: policy.<name>
+const policySectionPrefix = "policy."
+
+// policyPreset is a named retention policy chosen with --policy: a bundle of prune
+// settings, written as they would be in a config file
+type policyPreset struct {
+	description string
+	values      map[string][]string
+}
+
+// policyPresets are the built-in presets. Each keeps to settings that can be set per
+// platform, so that they only ever decide what a prune selects and what it does to it.
+var policyPresets = map[string]policyPreset{
+	"conservative": {
+		description: "Posts over two years old that nobody liked, reposted or replied to, keeping pinned and self-liked posts and direct messages",
+		values: map[string][]string{
+			"max-post-age":      {"2y"},
+			"preserve-pinned":   {"true"},
+			"preserve-selflike": {"true"},
+			"preserve-direct":   {"true"},
+			"max-likes":         {"0"},
+			"max-reposts":       {"0"},
+			"max-replies":       {"0"},
+		},
+	},
+	"low-engagement": {
+		description: "Posts over six months old with at most two likes and no reposts, keeping pinned posts and anything with replies",
+		values: map[string][]string{
+			"max-post-age":          {"6mo"},
+			"preserve-pinned":       {"true"},
+			"preserve-with-replies": {"true"},
+			"max-likes":             {"2"},
+			"max-reposts":           {"0"},
+		},
+	},
+	"aggressive": {
+		description: "Everything over 30 days old, unsharing reposts and removing likes too, keeping only pinned posts",
+		values: map[string][]string{
+			"max-post-age":    {"30d"},
+			"preserve-pinned": {"true"},
+			"unshare-reposts": {"true"},
+			"unlike-posts":    {"true"},
+		},
+	},
+}
+
+// policyPresetValues returns the settings of the named preset: the built-in one with any
+// changes from the config file's policy.<name> section, or one defined there alone
+func policyPresetValues(name string, config *internal.Config) (map[string][]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	values := make(map[string][]string)
+	preset, builtIn := policyPresets[name]
+	if builtIn {
+		maps.Copy(values, preset.values)
+	}
+	section, inConfig := config.Sections[policySectionPrefix+name]
+	if !builtIn && !inConfig {
+		return nil, fmt.Errorf("unknown policy %q: use one of %s, or define it in the config file as a %s%s section", name, strings.Join(policyPresetNames(config), ", "), policySectionPrefix, name)
+	}
+	maps.Copy(values, section)
+	return values, nil
+}
+
+// policyPresetNames returns the names of the built-in presets and those the config
+// file defines, sorted
+func policyPresetNames(config *internal.Config) []string {
+	names := slices.Collect(maps.Keys(policyPresets))
+	for section := range config.Sections {
+		if name, ok := strings.CutPrefix(section, policySectionPrefix); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// applyPolicyPreset sets the flags of the preset named by --policy that weren't given
+// on the command line or by the config file
+func applyPolicyPreset(cmd *cobra.Command, config *internal.Config) error {
+	if cmd.Flags().Lookup("policy") == nil {
+		return nil
+	}
+	name, _ := cmd.Flags().GetString("policy")
+	if name == "" {
+		return nil
+	}
+	values, err := policyPresetValues(name, config)
+	if err != nil {
+		return err
+	}
+	for _, flag := range slices.Sorted(maps.Keys(values)) {
+		if err := setFlagFromConfig(cmd, flag, values[flag]); err != nil {
+			return fmt.Errorf("policy %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validatePolicySection rejects settings a policy.<name> section can't hold
+func validatePolicySection(name string, section map[string][]string) error {
+	for _, key := range slices.Sorted(maps.Keys(section)) {
+		if !slices.Contains(platformOverridableFlags, key) {
+			return fmt.Errorf("%s can't be part of a policy in config file (%s.%s)", key, name, key)
+		}
+	}
+	return nil
+}
+
+var policyPresetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "List the retention policies --policy can choose",
+	Long: `List the named retention policies that prune, review and server can be given
+with --policy, and the settings each one stands for.
+
+Presets include any changes made to them in the config file, under a section
+named policy.<name>; a section for a new name defines a preset of your own:
+
+  policy.conservative:
+    max-post-age: 3y
+  policy.holiday:
+    with-hashtags: "#holiday"
+    max-post-age: 1y
+
+Flags given on the command line, and settings elsewhere in the config file,
+take precedence over the preset's.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipConfigAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfigFile()
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := validateConfig(cmd.Root(), config); err != nil {
+			exitWithError(err)
+		}
+		if err := displayPolicyPresets(cmd.OutOrStdout(), config); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func displayPolicyPresets(w io.Writer, config *internal.Config) error {
+	for _, name := range policyPresetNames(config) {
+		values, err := policyPresetValues(name, config)
+		if err != nil {
+			return err
+		}
+		description := "Defined in the config file"
+		if preset, ok := policyPresets[name]; ok {
+			description = preset.description
+			if _, changed := config.Sections[policySectionPrefix+name]; changed {
+				description += " (changed in the config file)"
+			}
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, description)
+		for _, flag := range slices.Sorted(maps.Keys(values)) {
+			fmt.Fprintf(w, "  --%s=%s\n", flag, strings.Join(values[flag], ","))
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func init() {
+	policyCmd.AddCommand(policyPresetsCmd)
+}
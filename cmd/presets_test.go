@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+)
+
+func TestApplyPolicyPreset(t *testing.T) {
+	resetFlagsAfter(t, pruneCmd)
+
+	// The command line beats the config file, which beats the preset
+	if err := pruneCmd.Flags().Set("max-post-age", "1y"); err != nil {
+		t.Fatal(err)
+	}
+	config := mustParseConfig(t, `
+policy: conservative
+max-likes: 3
+policy.conservative:
+  preserve-with-replies: true
+`)
+	if err := applyConfig(pruneCmd, config); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+
+	options, err := platformPruneOptions(pruneCmd, "mastodon")
+	if err != nil {
+		t.Fatalf("platformPruneOptions failed: %v", err)
+	}
+	if *options.MaxAge != 365*24*time.Hour {
+		t.Errorf("Expected the command line max age, got %v", *options.MaxAge)
+	}
+	if options.MaxLikes == nil || *options.MaxLikes != 3 {
+		t.Errorf("Expected max-likes from the config file, got %v", options.MaxLikes)
+	}
+	if options.MaxReposts == nil || *options.MaxReposts != 0 || !options.PreservePinned || !options.PreserveDirect {
+		t.Errorf("Expected the rest of the conservative preset, got %+v", options)
+	}
+	if !options.PreserveWithReplies {
+		t.Error("Expected the config file's change to the preset")
+	}
+}
+
+func TestApplyPolicyPreset_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown", "policy: yolo\n", `unknown policy "yolo": use one of aggressive, conservative, low-engagement`},
+		{"not a criterion", "policy.mine:\n  dry-run: false\n", "dry-run can't be part of a policy"},
+		{"bad value", "policy: mine\npolicy.mine:\n  max-likes: lots\n", "policy mine: invalid max-likes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlagsAfter(t, pruneCmd)
+			err := applyConfig(pruneCmd, mustParseConfig(t, tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicyPresets_Valid(t *testing.T) {
+	empty := mustParseConfig(t, "")
+	maxAges := map[string]time.Duration{
+		"conservative":   2 * timespec.Year,
+		"low-engagement": 6 * timespec.Month,
+		"aggressive":     30 * 24 * time.Hour,
+	}
+	for name := range policyPresets {
+		t.Run(name, func(t *testing.T) {
+			resetFlagsAfter(t, serverCmd)
+			if err := serverCmd.Flags().Set("policy", name); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyConfig(serverCmd, empty); err != nil {
+				t.Fatalf("applyConfig failed: %v", err)
+			}
+			options, err := platformPruneOptions(serverCmd, "bluesky")
+			if err != nil {
+				t.Fatalf("platformPruneOptions failed: %v", err)
+			}
+			if problems, warnings := policyConflicts(options, time.Now()); len(problems)+len(warnings) > 0 {
+				t.Errorf("Expected a clean policy, got problems %v and warnings %v", problems, warnings)
+			}
+			want, ok := maxAges[name]
+			if !ok {
+				t.Fatalf("No expected max-post-age for preset %s", name)
+			}
+			if options.MaxAge == nil || *options.MaxAge != want {
+				t.Errorf("Expected max-post-age of %v, got %v", want, options.MaxAge)
+			}
+		})
+	}
+}
+
+func TestDisplayPolicyPresets(t *testing.T) {
+	config := mustParseConfig(t, `
+policy.aggressive:
+  max-post-age: 7d
+policy.holiday:
+  with-hashtags: "#holiday"
+  max-post-age: 1y
+`)
+	var out bytes.Buffer
+	if err := displayPolicyPresets(&out, config); err != nil {
+		t.Fatalf("displayPolicyPresets failed: %v", err)
+	}
+	for _, want := range []string{
+		"aggressive: Everything over 30 days old",
+		"(changed in the config file)\n  --max-post-age=7d\n",
+		"holiday: Defined in the config file\n  --max-post-age=1y\n  --with-hashtags=#holiday\n",
+		"low-engagement:",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out.String())
+		}
+	}
+}
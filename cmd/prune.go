@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
 	"github.com/spf13/cobra"
 )
 
@@ -25,41 +30,187 @@ What gets processed:
 - Posts you've reposted: Removes your repost (unrepost)
 - Posts you've liked: Removes your like (unlike) - only when --unlike-posts is used
 
+With --redact, original posts and replies on Mastodon and GoToSocial are edited
+to "[removed by cringesweeper]" instead of being deleted, so threads keep their
+shape. The earlier versions stay in the post's edit history.
+
 Posts can be processed by maximum age (e.g., older than 30 days) or before a specific 
 date. Smart preservation rules protect important content like pinned posts and 
 posts you've liked.
 
 By default, only processes recent posts (typically 100 most recent). Use --continue 
 to keep searching further back in time until no more posts match your criteria.
+With --ids-file or --id, only the posts, likes and reposts listed are looked up and
+acted on, without scanning the timeline at all.
 
 ALWAYS use --dry-run first to preview what would be processed. Actions are 
 permanent and cannot be undone. Requires authentication for the target platform.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
 		platformsStr, _ := cmd.Flags().GetString("platforms")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		preserveSelfLike, _ := cmd.Flags().GetBool("preserve-selflike")
 		preservePinned, _ := cmd.Flags().GetBool("preserve-pinned")
+		preserveWithReplies, _ := cmd.Flags().GetBool("preserve-with-replies")
+		preserveHashtagsStr, _ := cmd.Flags().GetString("preserve-hashtags")
+		withHashtagsStr, _ := cmd.Flags().GetString("with-hashtags")
+		preserveLanguagesStr, _ := cmd.Flags().GetString("preserve-language")
+		withLanguagesStr, _ := cmd.Flags().GetString("language")
+		mediaOnly, _ := cmd.Flags().GetBool("media-only")
+		skipMedia, _ := cmd.Flags().GetBool("skip-media")
+		withLinks, _ := cmd.Flags().GetBool("with-links")
+		linksToStr, _ := cmd.Flags().GetString("links-to")
+		repliesOnly, _ := cmd.Flags().GetBool("replies-only")
+		skipReplies, _ := cmd.Flags().GetBool("skip-replies")
+		onlySensitive, _ := cmd.Flags().GetBool("only-sensitive")
+		preserveCW, _ := cmd.Flags().GetBool("preserve-cw")
+		visibilityStr, _ := cmd.Flags().GetString("visibility")
+		preserveDirect, _ := cmd.Flags().GetBool("preserve-direct")
+		excludeFile, _ := cmd.Flags().GetString("exclude-file")
+		idsFile, _ := cmd.Flags().GetString("ids-file")
+		ids, _ := cmd.Flags().GetStringArray("id")
 		unlikePosts, _ := cmd.Flags().GetBool("unlike-posts")
+		likedPostAge, _ := cmd.Flags().GetBool("liked-post-age")
+		redact, _ := cmd.Flags().GetBool("redact")
 		unshareReposts, _ := cmd.Flags().GetBool("unshare-reposts")
+		unshareSelfReposts, _ := cmd.Flags().GetBool("unshare-self-reposts")
+		skipSelfReposts, _ := cmd.Flags().GetBool("skip-self-reposts")
+		onlySelfReposts, _ := cmd.Flags().GetBool("only-self-reposts")
+		deleteWholeThreads, _ := cmd.Flags().GetBool("delete-whole-threads")
+		batchWrites, _ := cmd.Flags().GetBool("batch-writes")
 		continueUntilEnd, _ := cmd.Flags().GetBool("continue")
+		fromIndex, _ := cmd.Flags().GetBool("from-index")
 		maxAgeStr, _ := cmd.Flags().GetString("max-post-age")
 		beforeDateStr, _ := cmd.Flags().GetString("before-date")
+		afterDateStr, _ := cmd.Flags().GetString("after-date")
 		rateLimitDelayStr, _ := cmd.Flags().GetString("rate-limit-delay")
+		verifyCounts, _ := cmd.Flags().GetBool("verify-counts")
+		verify, _ := cmd.Flags().GetBool("verify")
+		followUpPasses, _ := cmd.Flags().GetInt("follow-up-passes")
+		acceptInstanceRules, _ := cmd.Flags().GetBool("accept-instance-rules")
+		progressIntervalStr, _ := cmd.Flags().GetString("progress-interval")
+		maxRuntimeStr, _ := cmd.Flags().GetString("max-runtime")
+		maxRequests, _ := cmd.Flags().GetInt("max-requests")
+		maxDeletions, _ := cmd.Flags().GetInt("max-deletions")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		planOut, _ := cmd.Flags().GetString("plan-out")
+		applyPlanPath, _ := cmd.Flags().GetString("apply-plan")
+		resume, _ := cmd.Flags().GetBool("resume")
+		allowMismatch, _ := cmd.Flags().GetBool("allow-mismatch")
+		archiveDir, _ := cmd.Flags().GetString("archive-dir")
+
+		maxLikes, maxReposts, maxReplies, err := parseEngagementThresholds(cmd)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		visibilities, err := internal.ParseVisibilities(visibilityStr)
+		if err != nil {
+			exitWithError(fmt.Errorf("error parsing visibility: %w", err))
+		}
+
+		var excludePosts []string
+		if excludeFile != "" {
+			excludePosts, err = internal.ReadPostRefs(excludeFile)
+			if err != nil {
+				exitWithError(fmt.Errorf("error reading exclude-file: %w", err))
+			}
+		}
+
+		// An explicit list of posts replaces the timeline scan
+		var listedPosts []string
+		if idsFile != "" {
+			listedPosts, err = internal.ReadPostRefs(idsFile)
+			if err != nil {
+				exitWithError(fmt.Errorf("error reading ids-file: %w", err))
+			}
+			if len(listedPosts) == 0 {
+				exitWithError(fmt.Errorf("--ids-file %s doesn't list any posts", idsFile))
+			}
+		}
+		listedPosts = internal.ParsePostRefs(append(listedPosts, ids...))
+
+		progressEvery, progressInterval, err := internal.ParseProgressInterval(progressIntervalStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		// The time budget covers the whole run, not each platform
+		var deadline time.Time
+		if maxRuntimeStr != "" {
+			maxRuntime, err := timespec.ParseDuration(maxRuntimeStr)
+			if err != nil || maxRuntime == 0 {
+				exitWithError(fmt.Errorf("invalid max-runtime %q: use a positive duration such as 45m", maxRuntimeStr))
+			}
+			deadline = clock.Now().Add(maxRuntime)
+		}
+
+		// As is the request budget. Requests are counted even without a limit, for the summary.
+		if maxRequests < 0 {
+			exitWithError(fmt.Errorf("invalid max-requests %d: must be 0 (no limit) or more", maxRequests))
+		}
+		if maxDeletions < 0 {
+			exitWithError(fmt.Errorf("invalid max-deletions %d: must be 0 (no limit) or more", maxDeletions))
+		}
+		if followUpPasses < 0 {
+			exitWithError(fmt.Errorf("invalid follow-up-passes %d: must be 0 or more", followUpPasses))
+		}
+		if followUpPasses > 0 && !verify {
+			exitWithError(fmt.Errorf("--follow-up-passes only applies with --verify"))
+		}
+		if planOut != "" && !dryRun {
+			exitWithError(fmt.Errorf("--plan-out only applies with --dry-run"))
+		}
+		budget := internal.NewRequestBudget(maxRequests)
+		ctx = internal.WithRequestBudget(ctx, budget)
+
+		// And the cap on actions, which guards against criteria that match far too much
+		actions := internal.NewActionBudget(maxDeletions)
+		ctx = internal.WithActionBudget(ctx, actions)
+
+		// A plan brings its own platforms, accounts and criteria
+		if applyPlanPath != "" {
+			plan, err := internal.ReadPrunePlan(applyPlanPath)
+			if err != nil {
+				exitWithError(err)
+			}
+			run := internal.PruneOptions{BatchWrites: batchWrites, ProgressEvery: progressEvery, ProgressInterval: progressInterval, Deadline: deadline, AllowAccountMismatch: allowMismatch, Output: cmd.OutOrStdout()}
+			if archiveDir != "" {
+				run.Archive = internal.NewPostArchiveAt(archiveDir)
+			}
+			if interactive {
+				run.Confirm = newPrunePrompter(os.Stdin, cmd.OutOrStdout())
+			} else {
+				run.ConfirmRun = newRunPrompter(newConfirmGate(bufio.NewReader(os.Stdin), cmd.OutOrStdout()))
+			}
+			if err := applyPrunePlan(ctx, cmd.OutOrStdout(), plan, run, acceptInstanceRules); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
 
 		// Determine which platforms to use
 		var platforms []string
-		var err error
-		
+
 		if platformsStr == "" {
-			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon) or 'all'\n")
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
 			os.Exit(1)
 		}
-		
+
 		platforms, err = internal.ParsePlatforms(platformsStr)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
+		}
+
+		// One prompt reads stdin for the whole run, so typed-ahead answers carry across platforms
+		var confirm internal.ConfirmFunc
+		var confirmRun internal.ConfirmRunFunc
+		if interactive {
+			confirm = newPrunePrompter(os.Stdin, cmd.OutOrStdout())
+		} else if !dryRun {
+			// Without --interactive, ask once per platform after showing the estimate
+			confirmRun = newRunPrompter(newConfirmGate(bufio.NewReader(os.Stdin), cmd.OutOrStdout()))
 		}
 
 		// Get username with fallback priority: argument > saved credentials > environment
@@ -82,15 +233,34 @@ permanent and cannot be undone. Requires authentication for the target platform.
 			Errors:         []string{},
 		}
 
+		plan := &internal.PrunePlan{CreatedAt: clock.Now(), Platforms: []internal.PlatformPlan{}}
+
 		// Process each platform
 		for i, platformName := range platforms {
 			if len(platforms) > 1 {
 				fmt.Printf("\n=== PRUNING %s ===\n", strings.ToUpper(platformName))
 			}
 
+			// Don't start another platform once the time, request or action budget is spent
+			limit := ""
+			if !deadline.IsZero() && !clock.Now().Before(deadline) {
+				limit = "--max-runtime"
+			} else if budget.Exhausted() {
+				limit = "--max-requests"
+			} else if actions.Exhausted() {
+				limit = "--max-deletions"
+			}
+			if limit != "" {
+				fmt.Printf("⏱️  %s reached, skipping %s\n", limit, platformName)
+				totalResults.StoppedEarly = true
+				totalResults.AddWarning("Skipped %s after reaching %s; re-run to prune it", platformName, limit)
+				continue
+			}
+			requestsBefore := budget.Used()
+
 			username, err := internal.GetUsernameForPlatform(platformName, argUsername)
 			if err != nil {
-				fmt.Printf("Error for %s: %v\n", platformName, err)
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
 				if len(platforms) > 1 {
 					totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformName, err))
 					continue // Skip this platform but continue with others
@@ -100,7 +270,7 @@ permanent and cannot be undone. Requires authentication for the target platform.
 
 			client, exists := internal.GetClient(platformName)
 			if !exists {
-				errorMsg := fmt.Sprintf("Unsupported platform '%s'. Supported platforms: %s", 
+				errorMsg := fmt.Sprintf("Unsupported platform '%s'. Supported platforms: %s",
 					platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
 				fmt.Printf("Error: %s\n", errorMsg)
 				if len(platforms) > 1 {
@@ -110,10 +280,15 @@ permanent and cannot be undone. Requires authentication for the target platform.
 				os.Exit(1)
 			}
 
+			// Make sure nobody prunes the wrong account because saved credentials beat the environment
+			if mismatch := internal.CheckCredentialMismatch(platformName); mismatch != nil {
+				warnCredentialMismatch(cmd.OutOrStdout(), mismatch)
+			}
+
 			// Parse rate limit delay - use platform-appropriate defaults
 			var rateLimitDelay time.Duration
 			if rateLimitDelayStr != "" {
-				delay, err := parseDuration(rateLimitDelayStr)
+				delay, err := timespec.ParseDuration(rateLimitDelayStr)
 				if err != nil {
 					fmt.Printf("Error parsing rate-limit-delay for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
@@ -124,30 +299,60 @@ permanent and cannot be undone. Requires authentication for the target platform.
 				}
 				rateLimitDelay = delay
 			} else {
-				// Set platform-appropriate defaults
-				switch platformName {
-				case "mastodon":
-					rateLimitDelay = 60 * time.Second // Conservative for Mastodon's 30 DELETEs per 30 minutes
-				case "bluesky":
-					rateLimitDelay = 1 * time.Second // More permissive for Bluesky's higher limits
-				default:
-					rateLimitDelay = 5 * time.Second // Safe default for unknown platforms
-				}
+				rateLimitDelay = defaultRateLimitDelay(platformName)
 			}
 
 			// Parse options
 			options := internal.PruneOptions{
-				PreserveSelfLike: preserveSelfLike,
-				PreservePinned:   preservePinned,
-				UnlikePosts:      unlikePosts,
-				UnshareReposts:   unshareReposts,
-				DryRun:           dryRun,
-				RateLimitDelay:   rateLimitDelay,
+				PreserveSelfLike:     preserveSelfLike,
+				PreservePinned:       preservePinned,
+				PreserveWithReplies:  preserveWithReplies,
+				PreserveHashtags:     internal.ParseHashtags(preserveHashtagsStr),
+				WithHashtags:         internal.ParseHashtags(withHashtagsStr),
+				PreserveLanguages:    internal.ParseLanguages(preserveLanguagesStr),
+				WithLanguages:        internal.ParseLanguages(withLanguagesStr),
+				MediaOnly:            mediaOnly,
+				SkipMedia:            skipMedia,
+				WithLinks:            withLinks,
+				LinksTo:              internal.ParseDomains(linksToStr),
+				RepliesOnly:          repliesOnly,
+				SkipReplies:          skipReplies,
+				OnlySensitive:        onlySensitive,
+				PreserveCW:           preserveCW,
+				Visibilities:         visibilities,
+				PreserveDirect:       preserveDirect,
+				ExcludePosts:         excludePosts,
+				ListedPosts:          listedPosts,
+				UnlikePosts:          unlikePosts,
+				LikedPostAge:         likedPostAge,
+				Redact:               redact,
+				UnshareReposts:       unshareReposts,
+				UnshareSelfReposts:   unshareSelfReposts,
+				SkipSelfReposts:      skipSelfReposts,
+				OnlySelfReposts:      onlySelfReposts,
+				DeleteWholeThreads:   deleteWholeThreads,
+				BatchWrites:          batchWrites,
+				FromIndex:            fromIndex,
+				DryRun:               dryRun,
+				RateLimitDelay:       rateLimitDelay,
+				MaxLikes:             maxLikes,
+				MaxReposts:           maxReposts,
+				MaxReplies:           maxReplies,
+				ProgressEvery:        progressEvery,
+				ProgressInterval:     progressInterval,
+				Deadline:             deadline,
+				AllowAccountMismatch: allowMismatch,
+				Confirm:              confirm,
+				ConfirmRun:           confirmRun,
+				Output:               cmd.OutOrStdout(),
+			}
+			if archiveDir != "" {
+				options.Archive = internal.NewPostArchiveAt(archiveDir)
 			}
 
 			// Parse max age
 			if maxAgeStr != "" {
-				maxAge, err := parseDuration(maxAgeStr)
+				maxAge, err := timespec.ParseDuration(maxAgeStr)
 				if err != nil {
 					fmt.Printf("Error parsing max-post-age for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
@@ -161,7 +366,7 @@ permanent and cannot be undone. Requires authentication for the target platform.
 
 			// Parse before date
 			if beforeDateStr != "" {
-				beforeDate, err := parseDate(beforeDateStr)
+				beforeDate, err := timespec.ParseDate(beforeDateStr)
 				if err != nil {
 					fmt.Printf("Error parsing before-date for %s: %v\n", platformName, err)
 					if len(platforms) > 1 {
@@ -173,9 +378,26 @@ permanent and cannot be undone. Requires authentication for the target platform.
 				options.BeforeDate = &beforeDate
 			}
 
-			// Validate that at least one criteria is specified
-			if options.MaxAge == nil && options.BeforeDate == nil {
-				fmt.Printf("Error for %s: Must specify either --max-post-age or --before-date\n", platformName)
+			// Parse after date, which narrows the other criteria to a window
+			if afterDateStr != "" {
+				afterDate, err := timespec.ParseDate(afterDateStr)
+				if err != nil {
+					fmt.Printf("Error parsing after-date for %s: %v\n", platformName, err)
+					if len(platforms) > 1 {
+						totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: after-date parse error: %v", platformName, err))
+						continue
+					}
+					os.Exit(1)
+				}
+				options.AfterDate = &afterDate
+			}
+			if err := options.CheckDateRange(); err != nil {
+				exitWithError(err)
+			}
+
+			// Validate that at least one criteria is specified, unless the posts were listed
+			if options.MaxAge == nil && options.BeforeDate == nil && len(options.ListedPosts) == 0 {
+				fmt.Printf("Error for %s: Must specify either --max-post-age or --before-date (or list posts with --ids-file or --id)\n", platformName)
 				if len(platforms) > 1 {
 					totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: no age criteria specified", platformName))
 					continue
@@ -183,15 +405,48 @@ permanent and cannot be undone. Requires authentication for the target platform.
 				os.Exit(1)
 			}
 
+			// Some instances restrict bulk deletion tools, so make sure their rules were seen first
+			if !dryRun {
+				if err := ensureInstanceRulesAcknowledged(ctx, cmd.OutOrStdout(), client, username, acceptInstanceRules, true); err != nil {
+					presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+					if len(platforms) > 1 {
+						totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformName, err))
+						continue
+					}
+					os.Exit(1)
+				}
+			}
+
+			// Record the account's post count so we can sanity-check the prune afterwards
+			beforeCount := -1
+			if verifyCounts && !dryRun {
+				beforeCount = fetchPostCount(ctx, client, username)
+			}
+
+			// Keep a checkpoint as the run goes, so an interrupted run can carry on with --resume
+			if !dryRun {
+				checkpoint, err := startPruneCheckpoint(cmd.OutOrStdout(), platformName, username, options, resume)
+				if err != nil {
+					presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+					if len(platforms) > 1 {
+						totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformName, err))
+						continue
+					}
+					os.Exit(1)
+				}
+				options.Checkpoint = checkpoint
+			}
+
 			// Perform pruning for this platform
 			var result *internal.PruneResult
 			if continueUntilEnd {
-				result = performContinuousPruningWithResult(client, username, options)
+				result = performContinuousPruningWithResult(ctx, client, username, options)
 			} else {
 				var err error
-				result, err = client.PrunePosts(username, options)
+				result, err = client.PrunePosts(ctx, username, options)
 				if err != nil {
-					fmt.Printf("Error pruning posts from %s: %v\n", client.GetPlatformName(), err)
+					presentError(os.Stdout, fmt.Errorf("pruning posts from %s: %w", client.GetPlatformName(), err))
+					finishPruneCheckpoint(cmd.OutOrStdout(), options.Checkpoint, nil)
 					if len(platforms) > 1 {
 						totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformName, err))
 						continue
@@ -200,20 +455,51 @@ permanent and cannot be undone. Requires authentication for the target platform.
 				}
 			}
 
+			result.APIRequests = budget.Used() - requestsBefore
+
+			// Keep the local index, if there is one, from listing what was just deleted
+			if !dryRun && result.DeletedCount > 0 {
+				if err := internal.DropDeletedFromIndex(platformName, username); err != nil {
+					internal.WithPlatform(platformName).Warn().Err(err).Msg("Failed to update local post index")
+				}
+			}
+
 			// Display results for this platform
-			displayPruneResults(result, client.GetPlatformName(), dryRun)
+			if err := displayPruneResults(cmd.OutOrStdout(), result, client.GetPlatformName(), dryRun); err != nil {
+				exitWithError(err)
+			}
+			finishPruneCheckpoint(cmd.OutOrStdout(), options.Checkpoint, result)
+
+			if planOut != "" {
+				planOptions := options
+				planOptions.ContinueUntilEnd = continueUntilEnd
+				plan.Platforms = append(plan.Platforms, internal.NewPlatformPlan(platformName, username, planOptions, result))
+			}
+
+			// Look again for anything the run should have removed, before the count check
+			// so that it sees the follow-up passes too
+			if verify && !dryRun {
+				verifyOptions := options
+				verifyOptions.ContinueUntilEnd = continueUntilEnd
+				verifyOptions.FromIndex = false // The index can't show what's really left
+				verifyOptions.Checkpoint = nil  // The run's own checkpoint is settled by now
+				requestsBefore := budget.Used()
+				followUp, err := verifyPrune(ctx, cmd.OutOrStdout(), client, username, verifyOptions, followUpPasses)
+				if err != nil {
+					exitWithError(err)
+				}
+				if followUp != nil {
+					mergePruneResult(result, followUp)
+				}
+				result.APIRequests += budget.Used() - requestsBefore
+			}
+
+			if beforeCount >= 0 {
+				verifyPostCount(ctx, client, username, platformName, beforeCount, result)
+			}
 
 			// Add to total results
-			totalResults.PostsToDelete = append(totalResults.PostsToDelete, result.PostsToDelete...)
-			totalResults.PostsToUnlike = append(totalResults.PostsToUnlike, result.PostsToUnlike...)
-			totalResults.PostsToUnshare = append(totalResults.PostsToUnshare, result.PostsToUnshare...)
-			totalResults.PostsPreserved = append(totalResults.PostsPreserved, result.PostsPreserved...)
-			totalResults.DeletedCount += result.DeletedCount
-			totalResults.UnlikedCount += result.UnlikedCount
-			totalResults.UnsharedCount += result.UnsharedCount
-			totalResults.PreservedCount += result.PreservedCount
-			totalResults.ErrorsCount += result.ErrorsCount
-			totalResults.Errors = append(totalResults.Errors, result.Errors...)
+			mergePruneResult(totalResults, result)
 
 			// Add spacing between platforms when processing multiple
 			if len(platforms) > 1 && i < len(platforms)-1 {
@@ -224,23 +510,189 @@ permanent and cannot be undone. Requires authentication for the target platform.
 		// Show combined results if multiple platforms were processed
 		if len(platforms) > 1 {
 			fmt.Printf("\n=== COMBINED RESULTS ===\n")
-			displayPruneResults(totalResults, "All Platforms", dryRun)
+			if err := displayPruneResults(cmd.OutOrStdout(), totalResults, "All Platforms", dryRun); err != nil {
+				exitWithError(err)
+			}
+		}
+
+		if planOut != "" {
+			if err := internal.WritePrunePlan(planOut, plan); err != nil {
+				exitWithError(err)
+			}
+			actions := 0
+			for _, platformPlan := range plan.Platforms {
+				actions += len(platformPlan.Actions)
+			}
+			fmt.Printf("\n📝 Wrote %d planned action(s) to %s. Review or edit it, then run: cringesweeper prune --apply-plan=%s\n", actions, planOut, planOut)
 		}
 	},
 }
 
-func performContinuousPruningWithResult(client internal.SocialClient, username string, options internal.PruneOptions) *internal.PruneResult {
+// applyPrunePlan carries out a plan written by --plan-out, one platform at a time. run
+// holds the settings for carrying it out that aren't part of the plan. Failing to apply
+// the plan on one platform doesn't stop the others; only failing to write to w does.
+func applyPrunePlan(ctx context.Context, w io.Writer, plan *internal.PrunePlan, run internal.PruneOptions, acceptInstanceRules bool) error {
+	totalResults := &internal.PruneResult{
+		PostsToDelete:  []internal.Post{},
+		PostsToUnlike:  []internal.Post{},
+		PostsToUnshare: []internal.Post{},
+		PostsPreserved: []internal.Post{},
+		Errors:         []string{},
+	}
+
+	for _, platformPlan := range plan.Platforms {
+		if len(plan.Platforms) > 1 {
+			fmt.Fprintf(w, "\n=== APPLYING PLAN FOR %s ===\n", strings.ToUpper(platformPlan.Platform))
+		}
+		if len(platformPlan.Actions) == 0 {
+			fmt.Fprintf(w, "No planned actions for %s.\n", platformPlan.Platform)
+			continue
+		}
+
+		// ReadPrunePlan has already checked the platform is supported
+		client, _ := internal.GetClient(platformPlan.Platform)
+		if err := ensureInstanceRulesAcknowledged(ctx, w, client, platformPlan.Username, acceptInstanceRules, true); err != nil {
+			presentError(w, fmt.Errorf("%s: %w", platformPlan.Platform, err))
+			totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformPlan.Platform, err))
+			continue
+		}
+
+		requestsBefore := internal.RequestBudgetFromContext(ctx).Used()
+		result, err := applyPlatformPlan(ctx, client, platformPlan, run)
+		if err != nil {
+			presentError(w, fmt.Errorf("applying plan on %s: %w", client.GetPlatformName(), err))
+			totalResults.Errors = append(totalResults.Errors, fmt.Sprintf("%s: %v", platformPlan.Platform, err))
+			continue
+		}
+		result.APIRequests = internal.RequestBudgetFromContext(ctx).Used() - requestsBefore
+
+		if err := displayPruneResults(w, result, client.GetPlatformName(), false); err != nil {
+			return err
+		}
+		mergePruneResult(totalResults, result)
+	}
+
+	if len(plan.Platforms) > 1 {
+		fmt.Fprintf(w, "\n=== COMBINED RESULTS ===\n")
+		return displayPruneResults(w, totalResults, "All Platforms", false)
+	}
+	return nil
+}
+
+// applyPlatformPlan prunes just the posts in one platform's plan. The plan's criteria are
+// checked again, so a post that's gone, or no longer matches, is left alone and counted
+// in a warning.
+func applyPlatformPlan(ctx context.Context, client internal.SocialClient, platformPlan internal.PlatformPlan, run internal.PruneOptions) (*internal.PruneResult, error) {
+	options := platformPlan.Options
+	options.DryRun = false
+	options.BatchWrites = run.BatchWrites
+	options.ProgressEvery = run.ProgressEvery
+	options.ProgressInterval = run.ProgressInterval
+	options.Deadline = run.Deadline
+	options.AllowAccountMismatch = run.AllowAccountMismatch
+	options.Archive = run.Archive
+	options.Confirm = run.Confirm
+	options.ConfirmRun = run.ConfirmRun
+	options.Output = run.Output
+	options.OnlyPostIDs = platformPlan.PostIDs()
+	options.PlannedActions = platformPlan.PlannedActions()
+
+	result, err := client.PrunePosts(ctx, platformPlan.Username, options)
+	if err != nil {
+		return nil, err
+	}
+
+	acted := make(map[string]bool)
+	for _, posts := range [][]internal.Post{result.PostsToDelete, result.PostsToRedact, result.PostsToUnlike, result.PostsToUnshare} {
+		for _, post := range posts {
+			acted[post.ID] = true
+		}
+	}
+	missed := -result.SkippedCount
+	for id := range options.OnlyPostIDs {
+		if !acted[id] {
+			missed++
+		}
+	}
+	if missed > 0 && !result.StoppedEarly {
+		result.AddWarning("%d planned post(s) on %s weren't acted on: they're gone, now preserved, or no longer match the plan's criteria", missed, client.GetPlatformName())
+	}
+	return result, nil
+}
+
+// startPruneCheckpoint returns the checkpoint a real prune run on an account keeps, or
+// with resume, the one an interrupted run with the same criteria left behind. Without a
+// home directory for checkpoints the run goes ahead without one, unless it was to resume.
+func startPruneCheckpoint(w io.Writer, platform, username string, options internal.PruneOptions, resume bool) (*internal.PruneCheckpoint, error) {
+	store := internal.DefaultPruneCheckpointStore()
+	if store == nil {
+		if resume {
+			return nil, fmt.Errorf("--resume needs prune checkpoints, which can't be kept without a home directory")
+		}
+		return nil, nil
+	}
+
+	criteria := options.CriteriaKey()
+	if resume {
+		checkpoint, err := store.Load(platform, username)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case checkpoint == nil:
+			fmt.Fprintf(w, "No interrupted prune to resume on %s, starting from the newest post\n", platform)
+		case checkpoint.Criteria != criteria:
+			return nil, fmt.Errorf("the interrupted prune on %s used different criteria; give it the same flags to resume it, or leave out --resume to start over", platform)
+		default:
+			fmt.Fprintf(w, "▶️  Resuming the prune on %s interrupted %s, %d post(s) already done\n", platform, checkpoint.UpdatedAt.Local().Format("2006-01-02 15:04"), len(checkpoint.Completed))
+			return checkpoint, nil
+		}
+	}
+	return store.Start(platform, username, criteria), nil
+}
+
+// finishPruneCheckpoint removes the checkpoint of a run that got through everything it
+// matched, and otherwise says how to carry on. A nil result is a run that failed.
+func finishPruneCheckpoint(w io.Writer, checkpoint *internal.PruneCheckpoint, result *internal.PruneResult) {
+	if checkpoint == nil {
+		return
+	}
+	if result != nil && !result.StoppedEarly && result.ErrorsCount == 0 {
+		if err := checkpoint.Remove(); err != nil {
+			internal.WithPlatform(checkpoint.Platform).Warn().Err(err).Msg("Failed to remove finished prune checkpoint")
+		}
+		return
+	}
+	fmt.Fprintf(w, "💾 Progress on %s is checkpointed; run prune again with the same criteria and --resume to carry on from where this run stopped\n", checkpoint.Platform)
+}
+
+// defaultRateLimitDelay returns the delay between API requests on a platform when
+// --rate-limit-delay isn't given
+func defaultRateLimitDelay(platform string) time.Duration {
+	switch platform {
+	case "mastodon":
+		return 2 * time.Second // Deletes also wait out Mastodon's 30 per 30 minutes as they use it up
+	case "gotosocial":
+		return 2 * time.Second // GoToSocial allows 300 requests per 5 minutes, deletes included
+	case "bluesky":
+		return 1 * time.Second // More permissive for Bluesky's higher limits
+	default:
+		return 5 * time.Second // Safe default for unknown platforms
+	}
+}
+
+func performContinuousPruningWithResult(ctx context.Context, client internal.SocialClient, username string, options internal.PruneOptions) *internal.PruneResult {
 	platform := client.GetPlatformName()
 	fmt.Printf("Starting continuous pruning on %s (will continue until no more posts match criteria)...\n", platform)
 	if options.DryRun {
 		fmt.Println("DRY RUN MODE: No actual actions will be performed")
 	}
-	
-	// For continuous pruning, use the platform's existing PrunePosts method
-	// which already has all the deletion logic implemented correctly
-	result, err := client.PrunePosts(username, options)
+
+	// Ask the platform to walk its whole timeline rather than just the most recent page
+	options.ContinueUntilEnd = true
+	result, err := client.PrunePosts(ctx, username, options)
 	if err != nil {
-		fmt.Printf("Error during pruning: %v\n", err)
+		presentError(os.Stdout, fmt.Errorf("pruning %s: %w", platform, err))
 		return &internal.PruneResult{
 			PostsToDelete:  []internal.Post{},
 			PostsToUnlike:  []internal.Post{},
@@ -254,189 +706,530 @@ func performContinuousPruningWithResult(client internal.SocialClient, username s
 			Errors:         []string{err.Error()},
 		}
 	}
-	
-	fmt.Printf("Continuous pruning completed: %d deleted, %d unliked, %d unshared, %d preserved\n",
-		result.DeletedCount, result.UnlikedCount, result.UnsharedCount, result.PreservedCount)
-	
+
+	fmt.Printf("Continuous pruning completed: %d deleted, %d redacted, %d unliked, %d unshared, %d preserved\n",
+		result.DeletedCount, result.RedactedCount, result.UnlikedCount, result.UnsharedCount, result.PreservedCount)
+
 	return result
 }
 
+// mergePruneResult adds the posts and counts from one prune result into another
+func mergePruneResult(into, from *internal.PruneResult) {
+	into.PostsToDelete = append(into.PostsToDelete, from.PostsToDelete...)
+	into.PostsToUnlike = append(into.PostsToUnlike, from.PostsToUnlike...)
+	into.PostsToUnshare = append(into.PostsToUnshare, from.PostsToUnshare...)
+	into.PostsToRedact = append(into.PostsToRedact, from.PostsToRedact...)
+	into.PostsPreserved = append(into.PostsPreserved, from.PostsPreserved...)
+	into.DeletedCount += from.DeletedCount
+	into.UnlikedCount += from.UnlikedCount
+	into.UnsharedCount += from.UnsharedCount
+	into.RedactedCount += from.RedactedCount
+	into.PreservedCount += from.PreservedCount
+	if len(from.PreserveReasons) > 0 && into.PreserveReasons == nil {
+		into.PreserveReasons = make(map[string]string)
+	}
+	maps.Copy(into.PreserveReasons, from.PreserveReasons)
+	into.SkippedCount += from.SkippedCount
+	into.ErrorsCount += from.ErrorsCount
+	into.RateLimitedCount += from.RateLimitedCount
+	into.Errors = append(into.Errors, from.Errors...)
+	into.Warnings = append(into.Warnings, from.Warnings...)
+	into.StoppedEarly = into.StoppedEarly || from.StoppedEarly
+	into.APIRequests += from.APIRequests
+}
+
+// verifyPrune re-checks the timeline after a real run with a dry run of the same criteria,
+// and reports matching posts that are still there because an action failed or pagination
+// skipped them. With follow-up passes allowed, it prunes just those posts and checks
+// again. It returns the combined result of the follow-up passes, or nil if none ran, and
+// an error only if their results couldn't be written to w.
+func verifyPrune(ctx context.Context, w io.Writer, client internal.SocialClient, username string, options internal.PruneOptions, followUpPasses int) (*internal.PruneResult, error) {
+	platform := client.GetPlatformName()
+
+	// The check only reads, so it isn't held to the run's time limit or prompts
+	check := options
+	check.DryRun = true
+	check.Confirm = nil
+	check.Deadline = time.Time{}
+
+	var followUp *internal.PruneResult
+	for pass := 1; ; pass++ {
+		fmt.Fprintf(w, "\n🔎 Verifying %s...\n", platform)
+		found, err := client.PrunePosts(ctx, username, check)
+		if err != nil {
+			fmt.Fprintf(w, "⚠️  Could not verify %s: %v\n", platform, err)
+			return followUp, nil
+		}
+
+		var remaining []internal.Post
+		remaining = append(remaining, found.PostsToDelete...)
+		remaining = append(remaining, found.PostsToUnlike...)
+		remaining = append(remaining, found.PostsToUnshare...)
+		remaining = append(remaining, found.PostsToRedact...)
+		if len(remaining) == 0 {
+			fmt.Fprintf(w, "✅ Verified: no posts matching the criteria remain on %s\n", platform)
+			return followUp, nil
+		}
+
+		fmt.Fprintf(w, "⚠️  %d matching post(s) still on %s:\n", len(remaining), platform)
+		for _, post := range remaining {
+			fmt.Fprintf(w, "  - %s [%s] @%s - %s\n", options.ActionFor(post), post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+		}
+
+		if pass > followUpPasses {
+			if followUpPasses == 0 {
+				fmt.Fprintln(w, "   Run prune again to retry them, or pass --follow-up-passes to retry automatically.")
+			}
+			return followUp, nil
+		}
 
-func parseDuration(s string) (time.Duration, error) {
-	// First try standard Go duration parsing (handles formats like "2h30m", "1h30m45s")
-	if duration, err := time.ParseDuration(s); err == nil {
-		if duration < 0 {
-			return 0, fmt.Errorf("negative durations are not allowed")
+		fmt.Fprintf(w, "🔁 Follow-up pass %d of %d on the remaining posts\n", pass, followUpPasses)
+		retry := options
+		retry.OnlyPostIDs = make(map[string]bool, len(remaining))
+		for _, post := range remaining {
+			retry.OnlyPostIDs[post.ID] = true
 		}
-		return duration, nil
+		result, err := client.PrunePosts(ctx, username, retry)
+		if err != nil {
+			presentError(w, fmt.Errorf("follow-up pass on %s: %w", platform, err))
+			return followUp, nil
+		}
+		if err := displayPruneResults(w, result, platform, false); err != nil {
+			return followUp, err
+		}
+
+		if followUp == nil {
+			followUp = &internal.PruneResult{}
+		}
+		mergePruneResult(followUp, result)
+		if result.StoppedEarly {
+			return followUp, nil // The run's limits are spent, so leave the rest for the next run
+		}
+	}
+}
+
+// newAcknowledgementStore opens the store of instance rule acknowledgements; swapped out in tests
+var newAcknowledgementStore = internal.NewAcknowledgementStore
+
+// ensureInstanceRulesAcknowledged shows an instance's rules before the first real prune
+// against it, or after they change, and records that the operator has seen them. With
+// accept the rules are acknowledged without asking; otherwise the user is prompted if
+// interactive, and the prune is refused if not.
+func ensureInstanceRulesAcknowledged(ctx context.Context, w io.Writer, client internal.SocialClient, username string, accept, interactive bool) error {
+	provider, ok := client.(internal.InstanceRulesProvider)
+	if !ok {
+		return nil
 	}
 
-	// Support custom formats like "30d", "7d", "1y"
-	if len(s) < 2 {
-		return 0, fmt.Errorf("invalid duration format")
+	rules, err := provider.FetchInstanceRules(ctx, username)
+	if err != nil {
+		fmt.Fprintf(w, "⚠️  Could not fetch instance rules from %s, check them yourself before pruning: %v\n", client.GetPlatformName(), err)
+		return nil
 	}
 
-	unit := s[len(s)-1:]
-	valueStr := s[:len(s)-1]
-	value, err := strconv.Atoi(valueStr)
+	store, err := newAcknowledgementStore()
+	if err != nil {
+		return fmt.Errorf("failed to open acknowledgement store: %w", err)
+	}
+	acknowledged, err := store.IsAcknowledged(rules)
 	if err != nil {
-		return 0, fmt.Errorf("invalid duration value: %w", err)
+		return err
+	}
+	if acknowledged {
+		return nil
 	}
 
-	if value < 0 {
-		return 0, fmt.Errorf("negative durations are not allowed")
+	if err := displayInstanceRules(w, rules); err != nil {
+		return err
 	}
 
-	switch unit {
-	case "d":
-		return time.Duration(value) * 24 * time.Hour, nil
-	case "w":
-		return time.Duration(value) * 7 * 24 * time.Hour, nil
-	case "m":
-		return time.Duration(value) * 30 * 24 * time.Hour, nil
-	case "y":
-		return time.Duration(value) * 365 * 24 * time.Hour, nil
-	default:
-		return 0, fmt.Errorf("unsupported duration unit: %s", unit)
+	if !accept {
+		if !interactive {
+			return fmt.Errorf("rules for %s have not been acknowledged. Review them and re-run with --accept-instance-rules", rules.Instance)
+		}
+		fmt.Fprintf(w, "Does %s allow automated deletion of your posts? Continue and remember this answer? (y/n): ", rules.Instance)
+		if !askYesNo() {
+			return fmt.Errorf("rules for %s were not acknowledged", rules.Instance)
+		}
+	}
+
+	if err := store.Acknowledge(rules, internal.GetOperator(), clock.Now()); err != nil {
+		return fmt.Errorf("failed to record acknowledgement: %w", err)
+	}
+	internal.WithPlatform(rules.Platform).Info().
+		Str("instance", rules.Instance).
+		Str("operator", internal.GetOperator()).
+		Msg("Instance rules acknowledged")
+	return nil
+}
+
+// newPrunePrompter returns the Confirm func for --interactive. It shows each post on w and
+// reads the answer from r; running out of input quits, so nothing is acted on unasked.
+// Answering all or quit holds for the rest of the run, across platforms.
+func newPrunePrompter(r io.Reader, w io.Writer) internal.ConfirmFunc {
+	reader := bufio.NewReader(r)
+	var final *internal.PruneDecision
+	return func(post internal.Post, action string) internal.PruneDecision {
+		if final != nil {
+			return *final
+		}
+		decision := askPruneDecision(reader, w, post, action)
+		if decision == internal.PruneAll || decision == internal.PruneQuit {
+			final = &decision
+		}
+		return decision
 	}
 }
 
-func parseDate(s string) (time.Time, error) {
-	// Support multiple date formats
-	formats := []string{
-		"2006-01-02",
-		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05-07:00",
-		"01/02/2006",
-		"01/02/2006 15:04:05",
+// newRunPrompter returns the ConfirmRun func that asks the gate whether to go ahead with
+// each platform's run once its estimate is known
+func newRunPrompter(gate *confirmGate) internal.ConfirmRunFunc {
+	return func(platform string, plan internal.PacingPlan) bool {
+		if gate.Confirm(fmt.Sprintf("About to start on %s: %s", platform, plan)) {
+			return true
+		}
+		fmt.Fprintf(gate.w, "Cancelled, nothing was changed on %s.\n", platform)
+		return false
 	}
+}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			return t, nil
+// askPruneDecision shows post and reads an answer, asking again until it makes sense
+func askPruneDecision(reader *bufio.Reader, w io.Writer, post internal.Post, action string) internal.PruneDecision {
+	fmt.Fprintf(w, "\n[%s] @%s (%s)\n", post.CreatedAt.Format("2006-01-02"), post.Handle, post.Type)
+	fmt.Fprintf(w, "  %s\n", truncateContent(post.Content, 200))
+	if post.URL != "" {
+		fmt.Fprintf(w, "  URL: %s\n", post.URL)
+	}
+	for {
+		fmt.Fprintf(w, "%s this post? [y]es, [s]kip, [a]ll remaining, [q]uit: ", strings.ToUpper(action[:1])+action[1:])
+		input, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y", "yes":
+			return internal.PruneProceed
+		case "s", "skip", "n", "no":
+			return internal.PruneSkip
+		case "a", "all":
+			return internal.PruneAll
+		case "q", "quit":
+			return internal.PruneQuit
+		}
+		if err != nil {
+			fmt.Fprintln(w)
+			return internal.PruneQuit
 		}
+		fmt.Fprintln(w, "Please enter y, s, a or q")
 	}
+}
 
-	return time.Time{}, fmt.Errorf("unable to parse date format. Supported formats: YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, MM/DD/YYYY")
+// warnCredentialMismatch prints a prominent warning that saved credentials and environment
+// variables name different accounts, and which one is about to be used
+func warnCredentialMismatch(w io.Writer, mismatch *internal.CredentialMismatch) {
+	internal.WithPlatform(mismatch.Platform).Warn().
+		Str("saved_user", mismatch.SavedUser).
+		Str("env_user", mismatch.EnvUser).
+		Msg("Saved credentials and environment variables are for different accounts")
+
+	fmt.Fprintf(w, "\n⚠️  ACCOUNT MISMATCH on %s\n", mismatch.Platform)
+	fmt.Fprintf(w, "   Saved credentials:      %s  ← USING THIS ACCOUNT\n", mismatch.SavedUser)
+	fmt.Fprintf(w, "   Environment variables:  %s  (ignored)\n", mismatch.EnvUser)
+	fmt.Fprintf(w, "   Saved credentials take precedence. If that's the wrong account, run\n")
+	fmt.Fprintf(w, "   'cringesweeper auth --platforms=%s' to switch before pruning.\n\n", mismatch.Platform)
 }
 
-func displayPruneResults(result *internal.PruneResult, platform string, dryRun bool) {
+func displayInstanceRules(w io.Writer, rules *internal.InstanceRules) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, "📜 Before pruning on %s for the first time, review its rules.\n", rules.Instance)
+	fmt.Fprintln(w, "   Some instances restrict bulk deletion or other automated tools.")
+	fmt.Fprintln(w)
+
+	if len(rules.Rules) > 0 {
+		flagged := make(map[string]bool)
+		for _, rule := range rules.AutomationRules() {
+			flagged[rule] = true
+		}
+		for i, rule := range rules.Rules {
+			marker := "  "
+			if flagged[rule] {
+				marker = "⚠️"
+			}
+			fmt.Fprintf(w, "%s %d. %s\n", marker, i+1, rule)
+		}
+		fmt.Fprintln(w)
+	}
+	if rules.TermsURL != "" {
+		fmt.Fprintf(w, "   Full terms: %s\n\n", rules.TermsURL)
+	}
+	return out.err
+}
+
+// fetchPostCount returns the account's current post count, or -1 if the platform
+// doesn't support post counts or the lookup fails
+func fetchPostCount(ctx context.Context, client internal.SocialClient, username string) int {
+	counter, ok := client.(internal.PostCounter)
+	if !ok {
+		fmt.Printf("⚠️  %s does not report post counts, skipping count verification\n", client.GetPlatformName())
+		return -1
+	}
+
+	count, err := counter.GetPostCount(ctx, username)
+	if err != nil {
+		fmt.Printf("⚠️  Could not fetch post count from %s, skipping count verification: %v\n", client.GetPlatformName(), err)
+		return -1
+	}
+	return count
+}
+
+// verifyPostCount re-fetches the account's post count and warns if the change doesn't
+// line up with what the prune reported, which may indicate unintended deletions
+func verifyPostCount(ctx context.Context, client internal.SocialClient, username, platformName string, beforeCount int, result *internal.PruneResult) {
+	afterCount := fetchPostCount(ctx, client, username)
+	if afterCount < 0 {
+		return
+	}
+
+	check := internal.PostCountCheck{
+		Before:        beforeCount,
+		After:         afterCount,
+		ExpectedDelta: internal.ExpectedPostCountDelta(platformName, result),
+	}
+
+	fmt.Printf("Post count check: %d before, %d after (expected -%d, observed -%d)\n",
+		check.Before, check.After, check.ExpectedDelta, check.ActualDelta())
+
+	if check.IsSuspicious(internal.DefaultPostCountTolerance) {
+		internal.WithPlatform(platformName).Warn().
+			Int("before", check.Before).
+			Int("after", check.After).
+			Int("expected_delta", check.ExpectedDelta).
+			Int("discrepancy", check.Discrepancy()).
+			Msg("Post count change does not match prune results")
+		fmt.Printf("⚠️  Post count changed by %d but %d removals were expected (discrepancy %+d).\n",
+			check.ActualDelta(), check.ExpectedDelta, check.Discrepancy())
+		fmt.Println("   This may indicate unintended deletions or delayed counters on the platform - review your account.")
+	}
+}
+
+// parseEngagementThresholds reads --max-likes, --max-reposts and --max-replies, returning
+// nil for any that weren't set
+func parseEngagementThresholds(cmd *cobra.Command) (maxLikes, maxReposts, maxReplies *int, err error) {
+	return platformFlags{cmd: cmd}.engagementThresholds()
+}
+
+// platformFlags reads a command's flags for one platform, preferring a --<platform>.<flag>
+// override when one was given. With no platform it reads the plain flags.
+type platformFlags struct {
+	cmd      *cobra.Command
+	platform string
+}
+
+// name returns the flag actually in effect for name
+func (f platformFlags) name(name string) string {
+	if f.platform != "" && f.cmd.Flags().Changed(f.platform+"."+name) {
+		return f.platform + "." + name
+	}
+	return name
+}
+
+func (f platformFlags) getString(name string) string {
+	value, _ := f.cmd.Flags().GetString(f.name(name))
+	return value
+}
+
+func (f platformFlags) getBool(name string) bool {
+	value, _ := f.cmd.Flags().GetBool(f.name(name))
+	return value
+}
+
+func (f platformFlags) engagementThresholds() (maxLikes, maxReposts, maxReplies *int, err error) {
+	thresholds := make([]*int, 3)
+	for i, name := range []string{"max-likes", "max-reposts", "max-replies"} {
+		name = f.name(name)
+		if !f.cmd.Flags().Changed(name) {
+			continue
+		}
+		value, _ := f.cmd.Flags().GetInt(name)
+		if value < 0 {
+			return nil, nil, nil, fmt.Errorf("--%s must not be negative", name)
+		}
+		thresholds[i] = &value
+	}
+	return thresholds[0], thresholds[1], thresholds[2], nil
+}
+
+func displayPruneResults(w io.Writer, result *internal.PruneResult, platform string, dryRun bool) error {
+	w = newDisplayWriter(w)
 	if dryRun {
-		fmt.Printf("DRY RUN: Actions that would be performed on %s:\n\n", platform)
+		fmt.Fprintf(w, "DRY RUN: Actions that would be performed on %s:\n\n", platform)
 	} else {
-		fmt.Printf("Pruning results for %s:\n\n", platform)
+		fmt.Fprintf(w, "Pruning results for %s:\n\n", platform)
 	}
 
-	totalActions := len(result.PostsToDelete) + len(result.PostsToUnlike) + len(result.PostsToUnshare)
+	totalActions := len(result.PostsToDelete) + len(result.PostsToRedact) + len(result.PostsToUnlike) + len(result.PostsToUnshare)
 	if totalActions == 0 {
-		fmt.Println("No posts match the specified criteria.")
-		return
+		fmt.Fprintln(w, "No posts match the specified criteria.")
+		return displayPruneWarnings(w, result)
 	}
 
 	// Stream posts to be deleted
 	if len(result.PostsToDelete) > 0 {
-		fmt.Printf("Posts %s:\n", map[bool]string{true: "that would be deleted", false: "deleted"}[dryRun])
+		fmt.Fprintf(w, "Posts %s:\n", map[bool]string{true: "that would be deleted", false: "deleted"}[dryRun])
 		for i, post := range result.PostsToDelete {
 			if dryRun {
-				fmt.Printf("  🗑️  [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "  🗑️  [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+			} else {
+				fmt.Fprintf(w, "%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+			}
+			if post.URL != "" {
+				fmt.Fprintf(w, "     URL: %s\n", post.URL)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Stream posts to be redacted
+	if len(result.PostsToRedact) > 0 {
+		fmt.Fprintf(w, "Posts %s:\n", map[bool]string{true: "that would be redacted", false: "redacted"}[dryRun])
+		for i, post := range result.PostsToRedact {
+			if dryRun {
+				fmt.Fprintf(w, "  ✏️  [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			} else {
-				fmt.Printf("%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			}
 			if post.URL != "" {
-				fmt.Printf("     URL: %s\n", post.URL)
+				fmt.Fprintf(w, "     URL: %s\n", post.URL)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Stream posts to be unliked
 	if len(result.PostsToUnlike) > 0 {
-		fmt.Printf("Posts %s:\n", map[bool]string{true: "that would be unliked", false: "unliked"}[dryRun])
+		fmt.Fprintf(w, "Posts %s:\n", map[bool]string{true: "that would be unliked", false: "unliked"}[dryRun])
 		for i, post := range result.PostsToUnlike {
 			if dryRun {
-				fmt.Printf("  👎 [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "  👎 [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			} else {
-				fmt.Printf("%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			}
 			if post.URL != "" {
-				fmt.Printf("     URL: %s\n", post.URL)
+				fmt.Fprintf(w, "     URL: %s\n", post.URL)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Stream posts to be unshared
 	if len(result.PostsToUnshare) > 0 {
-		fmt.Printf("Posts %s:\n", map[bool]string{true: "that would be unshared", false: "unshared"}[dryRun])
+		fmt.Fprintf(w, "Posts %s:\n", map[bool]string{true: "that would be unshared", false: "unshared"}[dryRun])
 		for i, post := range result.PostsToUnshare {
 			if dryRun {
-				fmt.Printf("  🔄 [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "  🔄 [%s] @%s - %s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			} else {
-				fmt.Printf("%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+				fmt.Fprintf(w, "%d. [%s] @%s - %s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
 			}
 			if post.URL != "" {
-				fmt.Printf("     URL: %s\n", post.URL)
+				fmt.Fprintf(w, "     URL: %s\n", post.URL)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Show preserved posts if any
 	if len(result.PostsPreserved) > 0 {
-		fmt.Printf("Posts preserved (due to --preserve-* flags):\n")
+		fmt.Fprintf(w, "Posts preserved (due to --preserve-* flags):\n")
 		for i, post := range result.PostsPreserved {
 			reason := ""
-			if post.IsLikedByUser && post.Handle == post.Author {
-				reason = " (self-liked)"
-			}
-			if post.IsPinned {
-				reason = " (pinned)"
+			if why := result.PreserveReasons[post.ID]; why != "" {
+				reason = fmt.Sprintf(" (%s)", why)
 			}
 			if dryRun {
-				fmt.Printf("  🛡️  [%s] @%s - %s%s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60), reason)
+				fmt.Fprintf(w, "  🛡️  [%s] @%s - %s%s\n", post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60), reason)
 			} else {
-				fmt.Printf("%d. [%s] @%s - %s%s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60), reason)
+				fmt.Fprintf(w, "%d. [%s] @%s - %s%s\n", i+1, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60), reason)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Show summary
-	fmt.Printf("Summary:\n")
+	fmt.Fprintf(w, "Summary:\n")
 	if dryRun {
 		if len(result.PostsToDelete) > 0 {
-			fmt.Printf("  Would delete: %d posts\n", len(result.PostsToDelete))
+			fmt.Fprintf(w, "  Would delete: %d posts\n", len(result.PostsToDelete))
+		}
+		if len(result.PostsToRedact) > 0 {
+			fmt.Fprintf(w, "  Would redact: %d posts\n", len(result.PostsToRedact))
 		}
 		if len(result.PostsToUnlike) > 0 {
-			fmt.Printf("  Would unlike: %d posts\n", len(result.PostsToUnlike))
+			fmt.Fprintf(w, "  Would unlike: %d posts\n", len(result.PostsToUnlike))
 		}
 		if len(result.PostsToUnshare) > 0 {
-			fmt.Printf("  Would unshare: %d posts\n", len(result.PostsToUnshare))
+			fmt.Fprintf(w, "  Would unshare: %d posts\n", len(result.PostsToUnshare))
 		}
 		if len(result.PostsPreserved) > 0 {
-			fmt.Printf("  Would preserve: %d posts\n", len(result.PostsPreserved))
+			fmt.Fprintf(w, "  Would preserve: %d posts%s\n", len(result.PostsPreserved), preserveBreakdown(result))
 		}
 	} else {
 		if result.DeletedCount > 0 {
-			fmt.Printf("  Deleted: %d posts\n", result.DeletedCount)
+			fmt.Fprintf(w, "  Deleted: %d posts\n", result.DeletedCount)
+		}
+		if result.RedactedCount > 0 {
+			fmt.Fprintf(w, "  Redacted: %d posts\n", result.RedactedCount)
 		}
 		if result.UnlikedCount > 0 {
-			fmt.Printf("  Unliked: %d posts\n", result.UnlikedCount)
+			fmt.Fprintf(w, "  Unliked: %d posts\n", result.UnlikedCount)
 		}
 		if result.UnsharedCount > 0 {
-			fmt.Printf("  Unshared: %d posts\n", result.UnsharedCount)
+			fmt.Fprintf(w, "  Unshared: %d posts\n", result.UnsharedCount)
 		}
 		if result.PreservedCount > 0 {
-			fmt.Printf("  Preserved: %d posts\n", result.PreservedCount)
+			fmt.Fprintf(w, "  Preserved: %d posts%s\n", result.PreservedCount, preserveBreakdown(result))
+		}
+		if result.SkippedCount > 0 {
+			fmt.Fprintf(w, "  Skipped: %d posts\n", result.SkippedCount)
 		}
 		if result.ErrorsCount > 0 {
-			fmt.Printf("  Errors: %d\n", result.ErrorsCount)
+			fmt.Fprintf(w, "  Errors: %d\n", result.ErrorsCount)
 			for _, err := range result.Errors {
-				fmt.Printf("    - %s\n", err)
+				fmt.Fprintf(w, "    - %s\n", err)
 			}
 		}
 	}
+	if result.APIRequests > 0 {
+		fmt.Fprintf(w, "  API requests: %d\n", result.APIRequests)
+	}
+	return displayPruneWarnings(w, result)
+}
+
+// displayPruneWarnings lists non-fatal advisories, kept separate from the error count
+func displayPruneWarnings(w io.Writer, result *internal.PruneResult) error {
+	out := newDisplayWriter(w)
+	if len(result.Warnings) == 0 {
+		return out.err
+	}
+	fmt.Fprintf(out, "  Warnings: %d\n", len(result.Warnings))
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "    - %s\n", warning)
+	}
+	return out.err
+}
+
+// preserveBreakdown counts the preserved posts by why they were kept, such as
+// " (3 pinned, 1 has-replies)", or returns "" if no reasons were recorded
+func preserveBreakdown(result *internal.PruneResult) string {
+	counts := make(map[string]int)
+	for _, reason := range result.PreserveReasons {
+		counts[reason]++
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, reason := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[reason], reason))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
 }
 
 func truncateContent(content string, maxLen int) string {
@@ -453,14 +1246,74 @@ func truncateContent(content string, maxLen int) string {
 
 func init() {
 	rootCmd.AddCommand(pruneCmd)
-	pruneCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon) or 'all' for all platforms")
+	pruneCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	pruneCmd.Flags().String("policy", "", "Named retention policy to start from: conservative, low-engagement or aggressive (see 'cringesweeper policy presets'); other flags override its settings")
 	pruneCmd.Flags().String("max-post-age", "", "Delete posts older than this (e.g., 30d, 1y, 24h)")
 	pruneCmd.Flags().String("before-date", "", "Delete posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	pruneCmd.Flags().String("after-date", "", "Only delete posts created on or after this date, e.g. with --before-date for a window (YYYY-MM-DD or MM/DD/YYYY)")
 	pruneCmd.Flags().Bool("preserve-selflike", false, "Don't delete user's own posts that they have liked")
 	pruneCmd.Flags().Bool("preserve-pinned", false, "Don't delete pinned posts")
+	pruneCmd.Flags().Bool("preserve-with-replies", false, "Don't delete posts other people have replied to, so their threads stay intact")
+	pruneCmd.Flags().String("preserve-hashtags", "", "Comma-separated hashtags whose posts are never deleted (e.g., #keep,#portfolio)")
+	pruneCmd.Flags().String("with-hashtags", "", "Only prune posts tagged with one of these comma-separated hashtags (e.g., #conf2019)")
+	pruneCmd.Flags().String("preserve-language", "", "Comma-separated languages whose posts are never deleted (e.g., en,de)")
+	pruneCmd.Flags().String("language", "", "Only prune posts in one of these comma-separated languages (e.g., en,de); posts with no language set are kept")
+	pruneCmd.Flags().Bool("media-only", false, "Only prune posts with media attachments (images, video), keeping text posts")
+	pruneCmd.Flags().Bool("skip-media", false, "Don't delete posts with media attachments, only text posts")
+	pruneCmd.MarkFlagsMutuallyExclusive("media-only", "skip-media")
+	pruneCmd.Flags().Bool("with-links", false, "Only prune posts containing links")
+	pruneCmd.Flags().String("links-to", "", "Only prune posts linking to one of these comma-separated domains or their subdomains (e.g., oldjob.com)")
+	pruneCmd.Flags().Bool("replies-only", false, "Only prune your replies to other people's posts, not your own posts or the replies in your threads")
+	pruneCmd.Flags().Bool("skip-replies", false, "Don't delete replies, only top-level posts")
+	pruneCmd.MarkFlagsMutuallyExclusive("replies-only", "skip-replies")
+	pruneCmd.Flags().Bool("only-sensitive", false, "Only prune posts marked sensitive or behind a content warning (Mastodon, GoToSocial)")
+	pruneCmd.Flags().Bool("preserve-cw", false, "Don't delete posts behind a content warning (Mastodon, GoToSocial)")
+	pruneCmd.Flags().String("visibility", "", "Only prune posts with one of these comma-separated visibilities: public, unlisted, followers-only, direct")
+	pruneCmd.Flags().Bool("preserve-direct", false, "Don't delete direct messages (Mastodon, GoToSocial)")
+	pruneCmd.Flags().String("exclude-file", "", "File of post URLs or IDs, one per line, that are never deleted, unliked or unshared")
+	pruneCmd.Flags().Bool("liked-post-age", false, "Judge likes by the age of the liked post instead of when you liked it (Bluesky)")
 	pruneCmd.Flags().Bool("unlike-posts", false, "Unlike posts instead of deleting them")
+	pruneCmd.Flags().Bool("redact", false, "Edit your posts to a placeholder instead of deleting them, keeping threads intact (Mastodon, GoToSocial)")
+	pruneCmd.Flags().String("archive-dir", "", "Save each post to this directory before deleting or redacting it, so 'cringesweeper restore' can post it again")
 	pruneCmd.Flags().Bool("unshare-reposts", false, "Unshare/unrepost instead of deleting reposts")
+	pruneCmd.Flags().Bool("unshare-self-reposts", false, "Also unshare reposts of your own posts, leaving the original alone (by default they're kept)")
+	pruneCmd.Flags().Bool("skip-self-reposts", false, "Never touch reposts or quotes of your own posts")
+	pruneCmd.Flags().Bool("only-self-reposts", false, "Only prune reposts and quotes of your own posts, e.g. old self-boosts")
+	pruneCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "only-self-reposts")
+	pruneCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "unshare-self-reposts")
+	pruneCmd.Flags().Bool("delete-whole-threads", false, "When a self-thread's first post is deleted, also delete all your replies in that thread, whatever their age")
 	pruneCmd.Flags().Bool("continue", false, "Continue searching and processing posts until no more match the criteria")
+	pruneCmd.Flags().String("ids-file", "", "Only act on the posts, likes and reposts in this file, by URL or ID one per line, looking them up instead of scanning the timeline")
+	pruneCmd.Flags().StringArray("id", nil, "Only act on this post, like or repost, by URL or ID, instead of scanning the timeline (repeatable)")
+	pruneCmd.Flags().Bool("from-index", false, "Select posts from the local index kept by 'cringesweeper sync' instead of walking the timeline; deletions still go to the platform")
 	pruneCmd.Flags().Bool("dry-run", false, "Show what would be deleted without actually deleting")
-	pruneCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 60s for Mastodon, 1s for Bluesky)")
+	pruneCmd.Flags().Bool("interactive", false, "Show each matching post and ask whether to act on it, skip it, act on all the rest, or quit")
+	pruneCmd.MarkFlagsMutuallyExclusive("interactive", "dry-run")
+	for _, name := range []string{"ids-file", "id"} {
+		pruneCmd.MarkFlagsMutuallyExclusive(name, "from-index")
+		pruneCmd.MarkFlagsMutuallyExclusive(name, "continue")
+	}
+	pruneCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 2s for Mastodon and GoToSocial, 1s for Bluesky)")
+	pruneCmd.Flags().Bool("batch-writes", false, "On Bluesky, delete records up to 200 at a time with one request per batch")
+	pruneCmd.Flags().Int("max-likes", 0, "Only prune posts with at most this many likes")
+	pruneCmd.Flags().Int("max-reposts", 0, "Only prune posts with at most this many reposts")
+	pruneCmd.Flags().Int("max-replies", 0, "Only prune posts with at most this many replies")
+	pruneCmd.Flags().Bool("verify-counts", false, "Compare the account's post count before and after pruning and flag large discrepancies")
+	pruneCmd.Flags().Bool("verify", false, "After pruning, re-fetch the timeline and report any posts matching the criteria that still exist")
+	pruneCmd.Flags().Int("follow-up-passes", 0, "With --verify, prune posts that are still there up to this many more times")
+	pruneCmd.Flags().Bool("accept-instance-rules", false, "Acknowledge the instance's rules without prompting before the first prune on it")
+	pruneCmd.Flags().String("progress-interval", "", "Print a progress summary every N posts and/or after a duration (e.g., 100, 30s, 100,30s) instead of a line per post")
+	pruneCmd.Flags().Int("max-requests", 0, "Stop cleanly after this many API requests, retries included (0 for no limit); the next run picks up where it left off")
+	pruneCmd.Flags().Int("max-deletions", 0, "Stop cleanly after deleting, redacting, unliking or unsharing this many posts in all, across every platform (0 for no limit)")
+	pruneCmd.Flags().String("max-runtime", "", "Stop cleanly after this long, finishing the current action (e.g., 45m, 2h); the next run picks up where it left off")
+	pruneCmd.Flags().String("plan-out", "", "With --dry-run, write the actions the run would take to this file for review")
+	pruneCmd.Flags().String("apply-plan", "", "Take only the actions in a file written by --plan-out, re-checking the criteria it was made with")
+	pruneCmd.Flags().Bool("resume", false, "Carry on from where an interrupted prune with the same criteria stopped, instead of starting from the newest post")
+	pruneCmd.MarkFlagsMutuallyExclusive("resume", "dry-run")
+	pruneCmd.Flags().Bool("allow-mismatch", false, "Go ahead with a warning when the username isn't the authenticated account, e.g. a handle or alias that can't be matched to it")
+
+	// An applied plan carries its own platforms and criteria
+	for _, name := range append([]string{"platforms", "dry-run", "plan-out", "continue", "verify", "verify-counts", "ids-file", "id", "resume", "policy"}, platformOverridableFlags...) {
+		pruneCmd.MarkFlagsMutuallyExclusive("apply-plan", name)
+	}
 }
@@ -1,90 +1,21 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
 )
 
-func TestParseDuration(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected time.Duration
-		wantErr  bool
-	}{
-		{"hours", "24h", 24 * time.Hour, false},
-		{"days", "30d", 30 * 24 * time.Hour, false},
-		{"weeks", "2w", 2 * 7 * 24 * time.Hour, false},
-		{"go minutes", "6m", 6 * time.Minute, false},
-		{"years", "1y", 365 * 24 * time.Hour, false},
-		{"go duration", "2h30m", 2*time.Hour + 30*time.Minute, false},
-		{"invalid format", "abc", 0, true},
-		{"empty string", "", 0, true},
-		{"single char", "d", 0, true},
-		{"zero value", "0d", 0, false},
-		{"negative value", "-5d", 0, true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseDuration(tt.input)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Expected error for input %q, got none", tt.input)
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Unexpected error for input %q: %v", tt.input, err)
-				return
-			}
-
-			if result != tt.expected {
-				t.Errorf("Expected %v for input %q, got %v", tt.expected, tt.input, result)
-			}
-		})
-	}
-}
-
-func TestParseDate(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		wantErr bool
-	}{
-		{"ISO date", "2023-12-25", false},
-		{"ISO datetime", "2023-12-25 14:30:00", false},
-		{"ISO with timezone", "2023-12-25T14:30:00Z", false},
-		{"ISO with offset", "2023-12-25T14:30:00-05:00", false},
-		{"US date", "12/25/2023", false},
-		{"US datetime", "12/25/2023 14:30:00", false},
-		{"invalid format", "25-12-2023", true},
-		{"invalid date", "2023-13-40", true},
-		{"empty string", "", true},
-		{"partial date", "2023-12", true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := parseDate(tt.input)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Expected error for input %q, got none", tt.input)
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error for input %q: %v", tt.input, err)
-				}
-			}
-		})
-	}
-}
-
 func TestTruncateContent(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -172,7 +103,7 @@ func TestDisplayPruneResults(t *testing.T) {
 			}
 		}()
 
-		displayPruneResults(result, "TestPlatform", true)
+		displayPruneResults(io.Discard, result, "TestPlatform", true)
 	})
 
 	t.Run("actual run display", func(t *testing.T) {
@@ -182,7 +113,7 @@ func TestDisplayPruneResults(t *testing.T) {
 			}
 		}()
 
-		displayPruneResults(result, "TestPlatform", false)
+		displayPruneResults(io.Discard, result, "TestPlatform", false)
 	})
 
 	t.Run("empty result", func(t *testing.T) {
@@ -199,67 +130,83 @@ func TestDisplayPruneResults(t *testing.T) {
 			}
 		}()
 
-		displayPruneResults(emptyResult, "TestPlatform", true)
+		displayPruneResults(io.Discard, emptyResult, "TestPlatform", true)
 	})
 }
 
-func TestPreservedPostReasonDetection(t *testing.T) {
-	tests := []struct {
-		name     string
-		post     internal.Post
-		expected string
-	}{
-		{
-			name: "self-liked post",
-			post: internal.Post{
-				Handle:        "user",
-				Author:        "user",
-				IsLikedByUser: true,
-			},
-			expected: " (self-liked)",
-		},
-		{
-			name: "pinned post",
-			post: internal.Post{
-				IsPinned: true,
-			},
-			expected: " (pinned)",
-		},
-		{
-			name: "both self-liked and pinned",
-			post: internal.Post{
-				Handle:        "user",
-				Author:        "user",
-				IsLikedByUser: true,
-				IsPinned:      true,
-			},
-			expected: " (pinned)", // pinned takes precedence in the current logic
-		},
-		{
-			name: "regular post",
-			post: internal.Post{
-				Handle: "user",
-				Author: "user",
-			},
-			expected: "",
-		},
+// brokenWriter accepts limit bytes, then fails every write, like a closed pipe
+type brokenWriter struct {
+	limit  int
+	writes int
+}
+
+func (w *brokenWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("broken pipe")
 	}
+	w.limit -= len(p)
+	return len(p), nil
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Test the reason detection logic from displayPruneResults
-			reason := ""
-			if tt.post.IsLikedByUser && tt.post.Handle == tt.post.Author {
-				reason = " (self-liked)"
-			}
-			if tt.post.IsPinned {
-				reason = " (pinned)"
-			}
+func TestDisplayPruneResults_WriteError(t *testing.T) {
+	result := &internal.PruneResult{
+		PostsToDelete: []internal.Post{{ID: "1", Handle: "user", Content: "Old post"}, {ID: "2", Handle: "user", Content: "Older post"}},
+		DeletedCount:  2,
+		Warnings:      []string{"Something to know"},
+	}
 
-			if reason != tt.expected {
-				t.Errorf("Expected reason %q, got %q", tt.expected, reason)
-			}
-		})
+	w := &brokenWriter{limit: 40}
+	err := displayPruneResults(w, result, "TestPlatform", false)
+	if err == nil || err.Error() != "broken pipe" {
+		t.Errorf("Expected the write error back, got %v", err)
+	}
+	if w.writes != 2 {
+		t.Errorf("Expected writing to stop at the first failure, got %d writes", w.writes)
+	}
+
+	if err := displayPruneResults(&brokenWriter{limit: 1 << 20}, result, "TestPlatform", false); err != nil {
+		t.Errorf("Expected no error when every write succeeds, got %v", err)
+	}
+}
+
+func TestPreservedPostReasons(t *testing.T) {
+	posts := []internal.Post{
+		{ID: "p1", Handle: "user", Content: "Pinned post", IsPinned: true},
+		{ID: "p2", Handle: "user", Content: "Discussed post"},
+		{ID: "p3", Handle: "user", Content: "Another discussed post"},
+		{ID: "p4", Handle: "user", Content: "Post with no recorded reason"},
+	}
+	result := &internal.PruneResult{
+		PostsToDelete:  []internal.Post{{ID: "d1", Handle: "user", Content: "Forgotten post"}},
+		DeletedCount:   1,
+		PostsPreserved: posts,
+		PreservedCount: len(posts),
+		PreserveReasons: map[string]string{
+			"p1": internal.PreserveReasonPinned,
+			"p2": internal.PreserveReasonHasReplies,
+			"p3": internal.PreserveReasonHasReplies,
+		},
+	}
+
+	var out bytes.Buffer
+	displayPruneResults(&out, result, "TestPlatform", false)
+	got := out.String()
+
+	for _, want := range []string{
+		"Pinned post (pinned)",
+		"Discussed post (has-replies)",
+		"Another discussed post (has-replies)",
+		"Preserved: 4 posts (2 has-replies, 1 pinned)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Post with no recorded reason (") {
+		t.Errorf("Post without a reason should be shown without one:\n%s", got)
 	}
 }
 
@@ -269,25 +216,15 @@ func TestRateLimitDelayDefaults(t *testing.T) {
 		platform string
 		expected time.Duration
 	}{
-		{"mastodon default", "mastodon", 60 * time.Second},
+		{"mastodon default", "mastodon", 2 * time.Second},
+		{"gotosocial default", "gotosocial", 2 * time.Second},
 		{"bluesky default", "bluesky", 1 * time.Second},
 		{"unknown platform", "twitter", 5 * time.Second},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Test rate limit delay logic from prune command
-			var rateLimitDelay time.Duration
-			switch tt.platform {
-			case "mastodon":
-				rateLimitDelay = 60 * time.Second
-			case "bluesky":
-				rateLimitDelay = 1 * time.Second
-			default:
-				rateLimitDelay = 5 * time.Second
-			}
-
-			if rateLimitDelay != tt.expected {
+			if rateLimitDelay := defaultRateLimitDelay(tt.platform); rateLimitDelay != tt.expected {
 				t.Errorf("Expected %v for platform %q, got %v", tt.expected, tt.platform, rateLimitDelay)
 			}
 		})
@@ -425,79 +362,34 @@ func TestDisplayPruneResultsWithErrors(t *testing.T) {
 			}
 		}()
 
-		displayPruneResults(result, "TestPlatform", false)
+		displayPruneResults(io.Discard, result, "TestPlatform", false)
 	})
 }
 
-func TestParseDurationEdgeCases(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected time.Duration
-		wantErr  bool
-	}{
-		{"zero hours", "0h", 0, false},
-		{"large number", "1000d", 1000 * 24 * time.Hour, false},
-		{"decimal value", "1.5d", 0, true},
-		{"just unit", "d", 0, true},
-		{"mixed case", "5D", 0, true},
-		{"standard go duration", "2h30m", 2*time.Hour + 30*time.Minute, false},
-		{"complex go duration", "1h30m45s", time.Hour + 30*time.Minute + 45*time.Second, false},
+func TestDisplayPruneResultsWithWarnings(t *testing.T) {
+	result := &internal.PruneResult{
+		PostsToDelete:  []internal.Post{},
+		PostsToUnlike:  []internal.Post{},
+		PostsToUnshare: []internal.Post{},
+		PostsPreserved: []internal.Post{},
+		Errors:         []string{},
+		Warnings:       []string{"Failed to fetch liked posts: timeout", "Only the most recent 100 repost records were examined"},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseDuration(tt.input)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Expected error for input %q, got none", tt.input)
+	for _, dryRun := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("displayPruneResults panicked with warnings: %v", r)
 				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Unexpected error for input %q: %v", tt.input, err)
-				return
-			}
+			}()
 
-			if result != tt.expected {
-				t.Errorf("Expected %v for input %q, got %v", tt.expected, tt.input, result)
-			}
+			displayPruneResults(io.Discard, result, "TestPlatform", dryRun)
 		})
 	}
-}
-
-func TestParseDateEdgeCases(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		wantErr bool
-	}{
-		{"leap year date", "2024-02-29", false},
-		{"invalid leap year", "2023-02-29", true},
-		{"future date", "2030-12-31", false},
-		{"epoch date", "1970-01-01", false},
-		{"just year", "2023", true},
-		{"european format", "25/12/2023", true}, // Not supported
-		{"time only", "15:30:00", true},
-		{"iso format with milliseconds", "2023-12-25T14:30:00.123Z", false}, // Partially parsed by 2006-01-02T15:04:05Z
-	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := parseDate(tt.input)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Expected error for input %q, got none", tt.input)
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error for input %q: %v", tt.input, err)
-				}
-			}
-		})
+	if result.ErrorsCount != 0 {
+		t.Errorf("Warnings should not affect error count, got %d", result.ErrorsCount)
 	}
 }
 
@@ -574,7 +466,7 @@ func TestDisplayPruneResultsEmptyCategories(t *testing.T) {
 				}
 			}()
 
-			displayPruneResults(tt.result, "TestPlatform", tt.dryRun)
+			displayPruneResults(io.Discard, tt.result, "TestPlatform", tt.dryRun)
 		})
 	}
 }
@@ -635,3 +527,447 @@ func TestPruneOptionsComplexScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEngagementThresholds(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("max-likes", 0, "")
+		cmd.Flags().Int("max-reposts", 0, "")
+		cmd.Flags().Int("max-replies", 0, "")
+		return cmd
+	}
+
+	t.Run("unset flags are nil", func(t *testing.T) {
+		maxLikes, maxReposts, maxReplies, err := parseEngagementThresholds(newCmd())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if maxLikes != nil || maxReposts != nil || maxReplies != nil {
+			t.Error("Expected all thresholds to be nil when flags are not set")
+		}
+	})
+
+	t.Run("explicit zero is kept", func(t *testing.T) {
+		cmd := newCmd()
+		cmd.Flags().Set("max-likes", "0")
+		cmd.Flags().Set("max-replies", "5")
+		maxLikes, maxReposts, maxReplies, err := parseEngagementThresholds(cmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if maxLikes == nil || *maxLikes != 0 {
+			t.Errorf("Expected max-likes 0, got %v", maxLikes)
+		}
+		if maxReposts != nil {
+			t.Errorf("Expected max-reposts to be unset, got %v", *maxReposts)
+		}
+		if maxReplies == nil || *maxReplies != 5 {
+			t.Errorf("Expected max-replies 5, got %v", maxReplies)
+		}
+	})
+
+	t.Run("negative values are rejected", func(t *testing.T) {
+		cmd := newCmd()
+		cmd.Flags().Set("max-reposts", "-1")
+		if _, _, _, err := parseEngagementThresholds(cmd); err == nil {
+			t.Error("Expected an error for a negative threshold")
+		}
+	})
+}
+
+// rulesClient is a SocialClient that publishes fixed instance rules
+type rulesClient struct {
+	internal.SocialClient
+	rules *internal.InstanceRules
+}
+
+func (c *rulesClient) GetPlatformName() string { return "Test" }
+
+func (c *rulesClient) FetchInstanceRules(ctx context.Context, username string) (*internal.InstanceRules, error) {
+	return c.rules, nil
+}
+
+// withAcknowledgementStore points instance rule acknowledgements at a temporary file
+func withAcknowledgementStore(t *testing.T) *internal.AcknowledgementStore {
+	t.Helper()
+	store := internal.NewAcknowledgementStoreAt(filepath.Join(t.TempDir(), "acknowledgements.json"))
+	previous := newAcknowledgementStore
+	newAcknowledgementStore = func() (*internal.AcknowledgementStore, error) { return store, nil }
+	t.Cleanup(func() { newAcknowledgementStore = previous })
+	return store
+}
+
+func TestEnsureInstanceRulesAcknowledged(t *testing.T) {
+	rules := &internal.InstanceRules{
+		Platform: "mastodon",
+		Instance: "example.social",
+		Rules:    []string{"Be nice", "No automated bulk actions without permission"},
+		TermsURL: "https://example.social/about",
+	}
+	client := &rulesClient{rules: rules}
+
+	t.Run("non-interactive without accept refuses", func(t *testing.T) {
+		withAcknowledgementStore(t)
+		var buf bytes.Buffer
+		if err := ensureInstanceRulesAcknowledged(context.Background(), &buf, client, "me", false, false); err == nil {
+			t.Fatal("Expected error when rules are unacknowledged")
+		}
+		if !strings.Contains(buf.String(), "No automated bulk actions") {
+			t.Errorf("Expected rules to be shown, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("accept records acknowledgement", func(t *testing.T) {
+		store := withAcknowledgementStore(t)
+		var buf bytes.Buffer
+		if err := ensureInstanceRulesAcknowledged(context.Background(), &buf, client, "me", true, false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ok, _ := store.IsAcknowledged(rules); !ok {
+			t.Error("Expected rules to be acknowledged")
+		}
+
+		// Once acknowledged, nothing is shown and no flag is needed
+		buf.Reset()
+		if err := ensureInstanceRulesAcknowledged(context.Background(), &buf, client, "me", false, false); err != nil {
+			t.Fatalf("Unexpected error on second run: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no output once acknowledged, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("clients without rules are not checked", func(t *testing.T) {
+		withAcknowledgementStore(t)
+		var plain struct{ internal.SocialClient }
+		if err := ensureInstanceRulesAcknowledged(context.Background(), io.Discard, plain, "me", false, false); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDisplayInstanceRulesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	displayInstanceRules(&buf, &internal.InstanceRules{
+		Platform: "mastodon",
+		Instance: "example.social",
+		Rules:    []string{"Be nice", "No bots or scripts that post or delete in bulk"},
+		TermsURL: "https://example.social/about",
+	})
+	assertGolden(t, "display_instance_rules", buf.Bytes())
+}
+
+func TestWarnCredentialMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	warnCredentialMismatch(&buf, &internal.CredentialMismatch{
+		Platform:  "mastodon",
+		SavedUser: "alice@example.social",
+		EnvUser:   "bob@example.social",
+	})
+	assertGolden(t, "warn_credential_mismatch", buf.Bytes())
+}
+
+func TestNewPrunePrompter(t *testing.T) {
+	post := internal.Post{
+		Type:      internal.PostTypeOriginal,
+		Handle:    "user.bsky.social",
+		Content:   "Hot take from years ago",
+		CreatedAt: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		URL:       "https://bsky.app/profile/user.bsky.social/post/1",
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []internal.PruneDecision
+	}{
+		{"yes then skip", "y\ns\n", []internal.PruneDecision{internal.PruneProceed, internal.PruneSkip}},
+		{"asks again after nonsense", "maybe\nn\n", []internal.PruneDecision{internal.PruneSkip}},
+		{"all holds for the rest of the run", "a\n", []internal.PruneDecision{internal.PruneAll, internal.PruneAll, internal.PruneAll}},
+		{"quit holds for the rest of the run", "q\ny\n", []internal.PruneDecision{internal.PruneQuit, internal.PruneQuit}},
+		{"end of input quits", "", []internal.PruneDecision{internal.PruneQuit}},
+		{"answer without newline", "y", []internal.PruneDecision{internal.PruneProceed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			confirm := newPrunePrompter(strings.NewReader(tt.input), &out)
+			for i, want := range tt.want {
+				if got := confirm(post, "delete"); got != want {
+					t.Errorf("Answer %d: expected %v, got %v", i, want, got)
+				}
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	newPrunePrompter(strings.NewReader("y\n"), &out)(post, "unshare")
+	for _, want := range []string{"[2020-05-01] @user.bsky.social (original)", "Hot take from years ago", "URL: " + post.URL, "Unshare this post?"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected prompt to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestNewRunPrompter(t *testing.T) {
+	plan := internal.PacingPlan{Deletes: 3, Delay: time.Second}
+
+	var out bytes.Buffer
+	confirmRun := newRunPrompter(newConfirmGate(bufio.NewReader(strings.NewReader("n\ny\n")), &out))
+	if confirmRun("bluesky", plan) {
+		t.Error("Expected the run to be cancelled on no")
+	}
+	if !confirmRun("mastodon", plan) {
+		t.Error("Expected the run to go ahead on yes")
+	}
+	for _, want := range []string{"About to start on bluesky: ~3 deletions at 1s delay", "Cancelled, nothing was changed on bluesky"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+// flakyClient holds a set of posts that match the criteria. Dry runs report whatever is
+// left; real runs remove the posts they're limited to, except that each post fails the
+// first failures[id] times it's tried.
+type flakyClient struct {
+	internal.SocialClient
+	posts    []internal.Post
+	failures map[string]int
+	runs     []internal.PruneOptions
+}
+
+func (c *flakyClient) GetPlatformName() string { return "Test" }
+
+func (c *flakyClient) PrunePosts(ctx context.Context, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+	c.runs = append(c.runs, options)
+	result := &internal.PruneResult{}
+	var kept []internal.Post
+	for _, post := range c.posts {
+		if options.OnlyPostIDs != nil && !options.OnlyPostIDs[post.ID] {
+			kept = append(kept, post)
+			continue
+		}
+		result.PostsToDelete = append(result.PostsToDelete, post)
+		if options.DryRun {
+			kept = append(kept, post)
+		} else if c.failures[post.ID] > 0 {
+			c.failures[post.ID]--
+			result.ErrorsCount++
+			kept = append(kept, post)
+		} else {
+			result.DeletedCount++
+		}
+	}
+	c.posts = kept
+	return result, nil
+}
+
+func TestVerifyPrune(t *testing.T) {
+	posts := []internal.Post{
+		{ID: "1", Type: internal.PostTypeOriginal, Content: "stubborn", Handle: "me"},
+		{ID: "2", Type: internal.PostTypeOriginal, Content: "very stubborn", Handle: "me"},
+	}
+	deadline := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("reports without follow-up passes", func(t *testing.T) {
+		client := &flakyClient{posts: posts}
+		var out bytes.Buffer
+		if followUp, err := verifyPrune(context.Background(), &out, client, "me", internal.PruneOptions{Deadline: deadline}, 0); followUp != nil || err != nil {
+			t.Errorf("Expected no follow-up result, got %+v, %v", followUp, err)
+		}
+		if len(client.runs) != 1 || !client.runs[0].DryRun || !client.runs[0].Deadline.IsZero() {
+			t.Errorf("Expected one dry run without a deadline, got %+v", client.runs)
+		}
+		if !strings.Contains(out.String(), "2 matching post(s) still on Test") || !strings.Contains(out.String(), "--follow-up-passes") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+	})
+
+	t.Run("follow-up passes until nothing remains", func(t *testing.T) {
+		client := &flakyClient{posts: posts, failures: map[string]int{"2": 1}}
+		var out bytes.Buffer
+		followUp, err := verifyPrune(context.Background(), &out, client, "me", internal.PruneOptions{}, 3)
+		if err != nil {
+			t.Fatalf("verifyPrune() error = %v", err)
+		}
+		if followUp == nil || followUp.DeletedCount != 2 || followUp.ErrorsCount != 1 {
+			t.Fatalf("Expected 2 deletions and 1 error across follow-ups, got %+v", followUp)
+		}
+		// check, retry, check, retry, check
+		if len(client.runs) != 5 {
+			t.Errorf("Expected 5 runs, got %d", len(client.runs))
+		}
+		if retry := client.runs[1]; retry.DryRun || len(retry.OnlyPostIDs) != 2 {
+			t.Errorf("Expected the follow-up to act on just the remaining posts, got %+v", retry)
+		}
+		if !strings.Contains(out.String(), "Verified: no posts matching the criteria remain on Test") {
+			t.Errorf("Expected a clean verification at the end:\n%s", out.String())
+		}
+	})
+
+	t.Run("stops after the allowed passes", func(t *testing.T) {
+		client := &flakyClient{posts: posts, failures: map[string]int{"2": 5}}
+		var out bytes.Buffer
+		followUp, err := verifyPrune(context.Background(), &out, client, "me", internal.PruneOptions{}, 1)
+		if err != nil {
+			t.Fatalf("verifyPrune() error = %v", err)
+		}
+		if followUp == nil || followUp.DeletedCount != 1 || len(client.posts) != 1 {
+			t.Errorf("Expected one post removed and one left, got %+v with %d left", followUp, len(client.posts))
+		}
+		if !strings.HasSuffix(strings.TrimSpace(out.String()), "very stubborn") {
+			t.Errorf("Expected to finish by listing the post still there:\n%s", out.String())
+		}
+	})
+}
+
+func TestApplyPlatformPlan(t *testing.T) {
+	maxAge := 30 * 24 * time.Hour
+	deadline := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &flakyClient{posts: []internal.Post{
+		{ID: "1", Type: internal.PostTypeOriginal},
+		{ID: "2", Type: internal.PostTypeOriginal},
+		{ID: "3", Type: internal.PostTypeOriginal},
+	}}
+	// Post 2 was removed from the plan while reviewing it, and post 4 has gone since
+	platformPlan := internal.PlatformPlan{
+		Platform: "bluesky",
+		Username: "me",
+		Options:  internal.PruneOptions{MaxAge: &maxAge, DryRun: true},
+		Actions: []internal.PlannedAction{
+			{Action: "delete", ID: "1"},
+			{Action: "delete", ID: "3"},
+			{Action: "delete", ID: "4"},
+		},
+	}
+
+	result, err := applyPlatformPlan(context.Background(), client, platformPlan, internal.PruneOptions{Deadline: deadline})
+	if err != nil {
+		t.Fatalf("applyPlatformPlan() error = %v", err)
+	}
+	if len(client.runs) != 1 {
+		t.Fatalf("Expected one run, got %d", len(client.runs))
+	}
+	run := client.runs[0]
+	if run.DryRun || run.MaxAge == nil || *run.MaxAge != maxAge || !run.Deadline.Equal(deadline) {
+		t.Errorf("Expected a real run with the plan's criteria and the given deadline, got %+v", run)
+	}
+	if result.DeletedCount != 2 || len(client.posts) != 1 || client.posts[0].ID != "2" {
+		t.Errorf("Expected posts 1 and 3 deleted and 2 kept, got %+v with %v left", result, client.posts)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "1 planned post(s) on Test weren't acted on") {
+		t.Errorf("Expected a warning about the missing post, got %v", result.Warnings)
+	}
+}
+
+// engineClient prunes its posts through the shared engine, so options like Archive apply
+type engineClient struct {
+	internal.SocialClient
+	posts []internal.Post
+	now   time.Time
+	acted []string
+}
+
+func (c *engineClient) GetPlatformName() string { return "Mastodon" }
+
+func (c *engineClient) Act(ctx context.Context, action string, post internal.Post) error {
+	c.acted = append(c.acted, action+":"+post.ID)
+	return nil
+}
+
+func (c *engineClient) PrunePosts(ctx context.Context, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+	result := &internal.PruneResult{}
+	err := internal.NewPruneEngine("mastodon", c, options, internal.NewFakeClock(c.now)).Run(ctx, c.posts, result)
+	return result, err
+}
+
+func TestApplyPlatformPlan_Archive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 24 * time.Hour
+	client := &engineClient{now: now, posts: []internal.Post{
+		{ID: "1", Type: internal.PostTypeOriginal, Content: "planned", CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "2", Type: internal.PostTypeOriginal, Content: "left out of the plan", CreatedAt: now.Add(-48 * time.Hour)},
+	}}
+	platformPlan := internal.PlatformPlan{
+		Platform: "mastodon",
+		Username: "me",
+		Options:  internal.PruneOptions{MaxAge: &maxAge, DryRun: true},
+		Actions:  []internal.PlannedAction{{Action: "delete", ID: "1"}},
+	}
+	archive := internal.NewPostArchiveAt(t.TempDir())
+
+	result, err := applyPlatformPlan(context.Background(), client, platformPlan, internal.PruneOptions{Archive: archive})
+	if err != nil {
+		t.Fatalf("applyPlatformPlan() error = %v", err)
+	}
+	if result.DeletedCount != 1 {
+		t.Errorf("Expected the planned post deleted, got %+v", result)
+	}
+	entries, err := archive.Load("mastodon")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Post.ID != "1" || entries[0].Action != "delete" {
+		t.Errorf("Expected just the planned post archived, got %+v", entries)
+	}
+}
+
+func TestApplyPlatformPlan_EditedAction(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 24 * time.Hour
+	client := &engineClient{now: now, posts: []internal.Post{
+		{ID: "1", Type: internal.PostTypeOriginal, Content: "still planned for deletion", CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "2", Type: internal.PostTypeOriginal, Content: "edited to redact", CreatedAt: now.Add(-48 * time.Hour)},
+	}}
+	platformPlan := internal.PlatformPlan{
+		Platform: "mastodon",
+		Username: "me",
+		Options:  internal.PruneOptions{MaxAge: &maxAge},
+		Actions:  []internal.PlannedAction{{Action: "delete", ID: "1"}, {Action: "redact", ID: "2"}},
+	}
+
+	_, err := applyPlatformPlan(context.Background(), client, platformPlan, internal.PruneOptions{})
+	if err == nil || !strings.Contains(err.Error(), "the plan says to redact post 2") {
+		t.Errorf("Expected the edited action to be rejected, got %v", err)
+	}
+	if len(client.acted) != 0 {
+		t.Errorf("Expected nothing acted on when the plan is rejected, got %v", client.acted)
+	}
+}
+
+func TestFinishPruneCheckpoint(t *testing.T) {
+	store := internal.NewPruneCheckpointStoreAt(t.TempDir(), internal.SystemClock)
+	tests := []struct {
+		name   string
+		result *internal.PruneResult
+		kept   bool
+	}{
+		{"finished", &internal.PruneResult{DeletedCount: 3}, false},
+		{"stopped early", &internal.PruneResult{DeletedCount: 3, StoppedEarly: true}, true},
+		{"errors", &internal.PruneResult{DeletedCount: 3, ErrorsCount: 1}, true},
+		{"failed", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoint := store.Start("bluesky", "me", "criteria")
+			if err := checkpoint.Save(); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			finishPruneCheckpoint(&out, checkpoint, tt.result)
+
+			saved, _ := store.Load("bluesky", "me")
+			if (saved != nil) != tt.kept {
+				t.Errorf("Expected the checkpoint kept = %v, got %+v", tt.kept, saved)
+			}
+			if strings.Contains(out.String(), "--resume") != tt.kept {
+				t.Errorf("Unexpected output: %q", out.String())
+			}
+		})
+	}
+}
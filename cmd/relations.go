@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+var relationsCmd = &cobra.Command{
+	Use:   "relations [username]",
+	Short: "List and prune stale follows, and clear old mutes and blocks",
+	Long: `List the accounts you follow, mute or block, and with --prune remove the ones
+that match the criteria: unfollow, unmute or unblock them.
+
+--inactive-for picks accounts that haven't posted for a while, and
+--older-than picks relations made longer ago than that. Given both, a relation
+has to match both. Bluesky records when follows and blocks were made, but not
+mutes; Mastodon records neither, so --older-than never matches there. Anything
+the platform doesn't report is kept.
+
+Looking up activity on Bluesky takes a request per account, so listing a
+large follow list with --inactive-for can take a while.
+
+Use --dry-run with --prune first to see what would be removed. Requires
+authentication, and only works on your own account.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		kindsStr, _ := cmd.Flags().GetString("kinds")
+		inactiveForStr, _ := cmd.Flags().GetString("inactive-for")
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+		prune, _ := cmd.Flags().GetBool("prune")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		rateLimitDelayStr, _ := cmd.Flags().GetString("rate-limit-delay")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+		kinds, err := internal.ParseRelationKinds(kindsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		options := internal.RelationPruneOptions{DryRun: dryRun, RateLimitDelay: time.Second}
+		if prune && !dryRun {
+			options.Confirm = newRelationsPrompter(newConfirmGate(bufio.NewReader(os.Stdin), cmd.OutOrStdout()))
+		}
+		if inactiveForStr != "" {
+			inactiveFor, err := timespec.ParseDuration(inactiveForStr)
+			if err != nil {
+				exitWithError(fmt.Errorf("error parsing inactive-for: %w", err))
+			}
+			options.InactiveFor = &inactiveFor
+		}
+		if olderThanStr != "" {
+			olderThan, err := timespec.ParseDuration(olderThanStr)
+			if err != nil {
+				exitWithError(fmt.Errorf("error parsing older-than: %w", err))
+			}
+			options.OlderThan = &olderThan
+		}
+		if rateLimitDelayStr != "" {
+			options.RateLimitDelay, err = timespec.ParseDuration(rateLimitDelayStr)
+			if err != nil {
+				exitWithError(fmt.Errorf("error parsing rate-limit-delay: %w", err))
+			}
+		}
+		if prune && options.InactiveFor == nil && options.OlderThan == nil {
+			exitWithError(fmt.Errorf("--prune needs --inactive-for or --older-than to say which relations to remove"))
+		}
+		if dryRun && !prune {
+			exitWithError(fmt.Errorf("--dry-run only applies with --prune"))
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			if err := runRelations(cmd, platformName, argUsername, kinds, options, prune); err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) == 1 {
+					os.Exit(1)
+				}
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+	},
+}
+
+// runRelations lists, or with prune removes, one platform's relations of each kind
+func runRelations(cmd *cobra.Command, platformName, argUsername string, kinds []internal.RelationKind, options internal.RelationPruneOptions, prune bool) error {
+	ctx := cmd.Context()
+	w := cmd.OutOrStdout()
+
+	username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+	if err != nil {
+		return err
+	}
+	client, exists := internal.GetClient(platformName)
+	if !exists {
+		return fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+	}
+	manager, ok := client.(internal.RelationsManager)
+	if !ok {
+		return fmt.Errorf("%s doesn't support managing follows, mutes and blocks", client.GetPlatformName())
+	}
+
+	for _, kind := range kinds {
+		if !prune {
+			fmt.Fprintf(w, "🔍 Listing %ss on %s...\n", kind, client.GetPlatformName())
+			relations, err := manager.ListRelations(ctx, username, kind, options.InactiveFor != nil)
+			if err != nil {
+				return fmt.Errorf("listing %ss: %w", kind, err)
+			}
+			if err := displayRelations(w, relations, kind, options, clock.Now()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Fprintf(w, "🔍 Checking %ss on %s...\n", kind, client.GetPlatformName())
+		result, err := internal.PruneRelations(ctx, manager, username, kind, options, clock.Now())
+		if result != nil {
+			if displayErr := displayRelationPruneResult(w, result, client.GetPlatformName(), options.DryRun); displayErr != nil && err == nil {
+				return displayErr
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("pruning %ss: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// newRelationsPrompter returns the Confirm func that lists the matching relations and asks
+// the gate whether to remove them
+func newRelationsPrompter(gate *confirmGate) func(internal.RelationKind, []internal.Relation) bool {
+	return func(kind internal.RelationKind, matched []internal.Relation) bool {
+		if !gate.yes {
+			for _, relation := range matched {
+				fmt.Fprintf(gate.w, "  %s\n", formatRelation(relation))
+			}
+		}
+		return gate.Confirm(fmt.Sprintf("About to remove %d %s(s)", len(matched), kind))
+	}
+}
+
+// displayRelations lists relations of one kind, flagging the ones the criteria match
+func displayRelations(w io.Writer, relations []internal.Relation, kind internal.RelationKind, options internal.RelationPruneOptions, now time.Time) error {
+	out := newDisplayWriter(w)
+	w = out
+	if len(relations) == 0 {
+		fmt.Fprintf(w, "No %ss found.\n\n", kind)
+		return out.err
+	}
+
+	matched := 0
+	for _, relation := range relations {
+		marker := ""
+		if options.Matches(relation, now) {
+			marker = "[STALE] "
+			matched++
+		}
+		fmt.Fprintf(w, "  %s%s\n", marker, formatRelation(relation))
+	}
+	fmt.Fprintf(w, "\n%d %s(s)", len(relations), kind)
+	if options.InactiveFor != nil || options.OlderThan != nil {
+		fmt.Fprintf(w, ", %d matching the criteria", matched)
+	}
+	fmt.Fprintf(w, "\n\n")
+	return out.err
+}
+
+// displayRelationPruneResult shows what a relations prune removed, or would remove
+func displayRelationPruneResult(w io.Writer, result *internal.RelationPruneResult, platform string, dryRun bool) error {
+	out := newDisplayWriter(w)
+	w = out
+	if len(result.Matched) == 0 {
+		fmt.Fprintf(w, "No %ss on %s match the criteria (%d checked).\n\n", result.Kind, platform, result.Examined)
+		return out.err
+	}
+
+	if result.Cancelled {
+		fmt.Fprintf(w, "Cancelled, no %ss on %s were removed.\n\n", result.Kind, platform)
+		return out.err
+	}
+	if dryRun {
+		fmt.Fprintf(w, "DRY RUN: %d of %d %s(s) on %s would be removed:\n", len(result.Matched), result.Examined, result.Kind, platform)
+	} else {
+		fmt.Fprintf(w, "Removed %d of %d matching %s(s) on %s (%d checked):\n", result.RemovedCount, len(result.Matched), result.Kind, platform, result.Examined)
+	}
+	for _, relation := range result.Matched {
+		fmt.Fprintf(w, "  %s\n", formatRelation(relation))
+	}
+	if result.ErrorsCount > 0 {
+		fmt.Fprintf(w, "\n❌ %d error(s):\n", result.ErrorsCount)
+		for _, message := range result.Errors {
+			fmt.Fprintf(w, "  %s\n", message)
+		}
+	}
+	fmt.Fprintln(w)
+	return out.err
+}
+
+// relationMadeVerbs say how each kind of relation was made, for formatRelation
+var relationMadeVerbs = map[internal.RelationKind]string{
+	internal.RelationFollow: "followed",
+	internal.RelationMute:   "muted",
+	internal.RelationBlock:  "blocked",
+}
+
+// formatRelation describes a relation on one line: the account, when it last posted and
+// when the relation was made, as far as they're known
+func formatRelation(relation internal.Relation) string {
+	line := "@" + relation.Handle
+	if relation.DisplayName != "" {
+		line += " (" + relation.DisplayName + ")"
+	}
+	if relation.LastActive != nil {
+		line += " - last posted " + relation.LastActive.Format("2006-01-02")
+	}
+	if relation.CreatedAt != nil {
+		line += fmt.Sprintf(" - %s %s", relationMadeVerbs[relation.Kind], relation.CreatedAt.Format("2006-01-02"))
+	}
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(relationsCmd)
+	relationsCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	relationsCmd.Flags().String("kinds", "follows", "Comma-separated relations to work on: follows, mutes, blocks or all")
+	relationsCmd.Flags().String("inactive-for", "", "Match accounts that haven't posted for this long (e.g., 6mo, 1y)")
+	relationsCmd.Flags().String("older-than", "", "Match relations made longer ago than this, where the platform records it (e.g., 1y)")
+	relationsCmd.Flags().Bool("prune", false, "Unfollow, unmute or unblock the matching accounts")
+	relationsCmd.Flags().Bool("dry-run", false, "With --prune, show what would be removed without removing anything")
+	relationsCmd.Flags().String("rate-limit-delay", "", "Delay between removals to respect rate limits (default 1s)")
+}
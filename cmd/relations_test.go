@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestFormatRelation(t *testing.T) {
+	lastActive := time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2021, 7, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		relation internal.Relation
+		want     string
+	}{
+		{
+			name:     "handle only",
+			relation: internal.Relation{Kind: internal.RelationMute, Handle: "someone@example.social"},
+			want:     "@someone@example.social",
+		},
+		{
+			name:     "everything known",
+			relation: internal.Relation{Kind: internal.RelationFollow, Handle: "quiet.bsky.social", DisplayName: "Quiet", LastActive: &lastActive, CreatedAt: &created},
+			want:     "@quiet.bsky.social (Quiet) - last posted 2023-02-01 - followed 2021-07-04",
+		},
+		{
+			name:     "block date",
+			relation: internal.Relation{Kind: internal.RelationBlock, Handle: "troll.bsky.social", CreatedAt: &created},
+			want:     "@troll.bsky.social - blocked 2021-07-04",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRelation(tt.relation); got != tt.want {
+				t.Errorf("formatRelation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayRelations(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sixMonths := 180 * 24 * time.Hour
+	longAgo := now.AddDate(-1, 0, 0)
+	recently := now.AddDate(0, 0, -1)
+	relations := []internal.Relation{
+		{Kind: internal.RelationFollow, Handle: "quiet", LastActive: &longAgo},
+		{Kind: internal.RelationFollow, Handle: "chatty", LastActive: &recently},
+	}
+
+	var out bytes.Buffer
+	displayRelations(&out, relations, internal.RelationFollow, internal.RelationPruneOptions{InactiveFor: &sixMonths}, now)
+	want := "  [STALE] @quiet - last posted 2023-06-01\n" +
+		"  @chatty - last posted 2024-05-31\n" +
+		"\n2 follow(s), 1 matching the criteria\n\n"
+	if out.String() != want {
+		t.Errorf("displayRelations() output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
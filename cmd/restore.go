@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [username]",
+	Short: "Post archived posts again",
+	Long: `Post again the posts a prune with --archive-dir saved before deleting them.
+
+Posts are restored oldest first, so a thread comes back in order: a reply goes
+to the restored copy of its parent when there is one, and to the original
+parent otherwise. Posts that were restored before, or are still there, are
+skipped, as are quotes, reposts and direct messages. Redacted posts are still
+on the platform, so they're left alone.
+
+Bluesky keeps the original date on a restored post; Mastodon and GoToSocial
+date it when it's restored. Media isn't archived, so posts come back without
+their attachments, and on Bluesky links and mentions come back as plain text.
+
+Use --dry-run first to see what would be posted. Without it, restore asks
+before posting each platform's posts, unless --yes is given. Requires
+authentication, and only works on your own account.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		archiveDir, _ := cmd.Flags().GetString("archive-dir")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		rateLimitDelayStr, _ := cmd.Flags().GetString("rate-limit-delay")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+		if archiveDir == "" {
+			exitWithError(fmt.Errorf("--archive-dir is required: give the directory prune archived posts to"))
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+		archive := internal.NewPostArchiveAt(archiveDir)
+
+		// One gate reads stdin for the whole run, so typed-ahead answers carry across platforms
+		gate := newConfirmGate(bufio.NewReader(os.Stdin), cmd.OutOrStdout())
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			options := internal.RestoreOptions{DryRun: dryRun, RateLimitDelay: defaultRateLimitDelay(platformName)}
+			if rateLimitDelayStr != "" {
+				options.RateLimitDelay, err = timespec.ParseDuration(rateLimitDelayStr)
+				if err != nil {
+					exitWithError(fmt.Errorf("error parsing rate-limit-delay: %w", err))
+				}
+			}
+
+			if err := runRestore(cmd, gate, platformName, argUsername, archive, options); err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) == 1 {
+					os.Exit(1)
+				}
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+	},
+}
+
+// runRestore restores one platform's archived posts once gate agrees, or with DryRun
+// lists them
+func runRestore(cmd *cobra.Command, gate *confirmGate, platformName, argUsername string, archive *internal.PostArchive, options internal.RestoreOptions) error {
+	w := cmd.OutOrStdout()
+
+	username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+	if err != nil {
+		return err
+	}
+	client, exists := internal.GetClient(platformName)
+	if !exists {
+		return fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+	}
+	restorer, ok := client.(internal.PostRestorer)
+	if !ok {
+		return fmt.Errorf("%s doesn't support restoring posts", client.GetPlatformName())
+	}
+
+	if !options.DryRun {
+		options.Confirm = func(posts []internal.Post) bool {
+			return gate.Confirm(fmt.Sprintf("About to post %d archived post(s) again on %s", len(posts), client.GetPlatformName()))
+		}
+	}
+
+	fmt.Fprintf(w, "🔍 Checking archived posts for %s...\n", client.GetPlatformName())
+	result, err := internal.RestoreArchive(cmd.Context(), restorer, archive, platformName, username, options, clock)
+	if result != nil {
+		if displayErr := displayRestoreResult(w, result, client.GetPlatformName(), options.DryRun); displayErr != nil && err == nil {
+			return displayErr
+		}
+	}
+	return err
+}
+
+// displayRestoreResult shows what a restore posted, or would post
+func displayRestoreResult(w io.Writer, result *internal.RestoreResult, platform string, dryRun bool) error {
+	out := newDisplayWriter(w)
+	w = out
+	if result.Examined == 0 {
+		fmt.Fprintf(w, "No archived posts for %s.\n\n", platform)
+		return out.err
+	}
+	if result.Declined {
+		fmt.Fprintf(w, "Cancelled, nothing was posted on %s.\n\n", platform)
+		return out.err
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "DRY RUN: %d of %d archived post(s) would be posted again on %s:\n", len(result.Restored), result.Examined, platform)
+	} else {
+		fmt.Fprintf(w, "Restored %d of %d archived post(s) on %s:\n", len(result.Restored), result.Examined, platform)
+	}
+	for _, post := range result.Restored {
+		fmt.Fprintf(w, "  %s %s\n", post.CreatedAt.Format("2006-01-02"), internal.TruncateContent(post.Content, 60))
+	}
+	fmt.Fprintf(w, "%d already restored, %d still there, %d skipped\n", result.AlreadyRestored, result.StillExists, result.SkippedCount)
+	for _, reason := range result.Skipped {
+		fmt.Fprintf(w, "  %s\n", reason)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(w, "⚠️  %s\n", warning)
+	}
+	if result.ErrorsCount > 0 {
+		fmt.Fprintf(w, "\n❌ %d error(s):\n", result.ErrorsCount)
+		for _, message := range result.Errors {
+			fmt.Fprintf(w, "  %s\n", message)
+		}
+	}
+	fmt.Fprintln(w)
+	return out.err
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	restoreCmd.Flags().String("archive-dir", "", "Directory that prune --archive-dir saved posts to")
+	restoreCmd.Flags().Bool("dry-run", false, "Show what would be posted without posting anything")
+	restoreCmd.Flags().String("rate-limit-delay", "", "Delay between posts to respect rate limits (default: 2s for Mastodon and GoToSocial, 1s for Bluesky)")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestDisplayRestoreResult(t *testing.T) {
+	result := &internal.RestoreResult{
+		Examined:        4,
+		Restored:        []internal.Post{{Content: "hello again", CreatedAt: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		AlreadyRestored: 1,
+		StillExists:     1,
+		SkippedCount:    1,
+		Skipped:         []string{"q1: quote posts aren't restored"},
+		Warnings:        []string{"Post p1 had 1 media attachment(s), which aren't archived and won't be restored"},
+	}
+
+	var out bytes.Buffer
+	displayRestoreResult(&out, result, "Mastodon", true)
+	want := "DRY RUN: 1 of 4 archived post(s) would be posted again on Mastodon:\n" +
+		"  2023-05-01 hello again\n" +
+		"1 already restored, 1 still there, 1 skipped\n" +
+		"  q1: quote posts aren't restored\n" +
+		"⚠️  Post p1 had 1 media attachment(s), which aren't archived and won't be restored\n\n"
+	if out.String() != want {
+		t.Errorf("displayRestoreResult() output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	displayRestoreResult(&out, &internal.RestoreResult{}, "Bluesky", false)
+	if out.String() != "No archived posts for Bluesky.\n\n" {
+		t.Errorf("Unexpected output for an empty archive: %q", out.String())
+	}
+
+	out.Reset()
+	displayRestoreResult(&out, &internal.RestoreResult{Examined: 2, Declined: true}, "Bluesky", false)
+	if out.String() != "Cancelled, nothing was posted on Bluesky.\n\n" {
+		t.Errorf("Unexpected output for a declined restore: %q", out.String())
+	}
+}
@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [username]",
+	Short: "Page through matching posts and pick which ones to prune",
+	Long: `Find the posts a prune with the given criteria would act on, then page through
+them in the terminal, ticking the ones to delete, unlike or unshare. Nothing
+happens until you press enter and confirm, and then only the ticked posts are
+touched.
+
+The criteria flags are the same as prune's. Review works on one platform at a
+time, needs an interactive terminal, and starts with nothing selected.
+
+Keys:
+  ↑ / ↓ (j / k)        move between posts
+  space (x)            tick or untick the highlighted post
+  ← / → (p / n)        previous / next page, as do page up and page down
+  a / u                select / unselect every post on the current page
+  A / U                select / unselect every post on every page
+  enter                act on the selected posts, after confirming
+  q (esc, ctrl-c)      quit without changing anything`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		continueUntilEnd, _ := cmd.Flags().GetBool("continue")
+		acceptInstanceRules, _ := cmd.Flags().GetBool("accept-instance-rules")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify the platform to review (bluesky or mastodon)\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(platforms) != 1 {
+			exitWithError(fmt.Errorf("review works on one platform at a time, got %s", strings.Join(platforms, ", ")))
+		}
+		if pageSize < 1 {
+			exitWithError(fmt.Errorf("invalid page-size %d: must be at least 1", pageSize))
+		}
+		platformName := platforms[0]
+
+		options, err := platformPruneOptions(cmd, platformName)
+		if err != nil {
+			exitWithError(err)
+		}
+		options.ContinueUntilEnd = continueUntilEnd
+		options.Output = cmd.OutOrStdout()
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+		username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+		if err != nil {
+			exitWithError(fmt.Errorf("%s: %w", platformName, err))
+		}
+
+		client, exists := internal.GetClient(platformName)
+		if !exists {
+			exitWithError(fmt.Errorf("unsupported platform '%s'. Supported platforms: %s",
+				platformName, strings.Join(internal.GetAllPlatformNames(), ", ")))
+		}
+
+		if mismatch := internal.CheckCredentialMismatch(platformName); mismatch != nil {
+			warnCredentialMismatch(cmd.OutOrStdout(), mismatch)
+		}
+
+		// A dry run finds the candidates without touching anything
+		fmt.Printf("🔍 Finding matching posts on %s...\n", client.GetPlatformName())
+		dryRunOptions := options
+		dryRunOptions.DryRun = true
+		found, err := client.PrunePosts(ctx, username, dryRunOptions)
+		if err != nil {
+			exitWithError(fmt.Errorf("finding posts on %s: %w", client.GetPlatformName(), err))
+		}
+
+		var posts []internal.Post
+		posts = append(posts, found.PostsToDelete...)
+		posts = append(posts, found.PostsToRedact...)
+		posts = append(posts, found.PostsToUnshare...)
+		posts = append(posts, found.PostsToUnlike...)
+		if len(posts) == 0 {
+			fmt.Println("No posts match the specified criteria.")
+			return
+		}
+
+		// Keys are read as they're pressed, on a screen of its own that's put away afterwards
+		restore, err := makeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			exitWithError(fmt.Errorf("review needs an interactive terminal: %w", err))
+		}
+		session := newReviewSession(posts, pageSize)
+		session.action = options.ActionFor
+		fmt.Fprint(cmd.OutOrStdout(), enterAltScreen)
+		confirmed := runReview(bufio.NewReader(os.Stdin), cmd.OutOrStdout(), session, assumeYes)
+		fmt.Fprint(cmd.OutOrStdout(), leaveAltScreen)
+		if err := restore(); err != nil {
+			internal.Logger.Warn().Err(err).Msg("Failed to restore the terminal")
+		}
+		if !confirmed {
+			fmt.Println("Nothing was changed.")
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Acting on %d selected post(s): %s\n", len(session.selected), session.summary())
+
+		if err := ensureInstanceRulesAcknowledged(ctx, cmd.OutOrStdout(), client, username, acceptInstanceRules, true); err != nil {
+			exitWithError(fmt.Errorf("%s: %w", platformName, err))
+		}
+
+		// The real run re-checks every criterion, so posts that changed since the
+		// review (a new like, say) are still protected
+		options.OnlyPostIDs = session.selected
+		result, err := client.PrunePosts(ctx, username, options)
+		if err != nil {
+			presentError(os.Stdout, fmt.Errorf("pruning posts from %s: %w", client.GetPlatformName(), err))
+			os.Exit(1)
+		}
+		if err := displayPruneResults(cmd.OutOrStdout(), result, client.GetPlatformName(), false); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// reviewSession is the state of the review screen: the candidate posts, which of them
+// are selected, the page being shown and the post under the cursor on it
+type reviewSession struct {
+	posts    []internal.Post
+	selected map[string]bool
+	page     int
+	pageSize int
+	cursor   int                        // Position of the highlighted post on the page
+	message  string                     // Shown under the posts until the next key press
+	action   func(internal.Post) string // What the run will do to a post, such as "delete"
+}
+
+func newReviewSession(posts []internal.Post, pageSize int) *reviewSession {
+	return &reviewSession{posts: posts, selected: make(map[string]bool), pageSize: pageSize, action: internal.PruneAction}
+}
+
+func (s *reviewSession) pages() int {
+	return (len(s.posts) + s.pageSize - 1) / s.pageSize
+}
+
+// pagePosts returns the posts on the current page
+func (s *reviewSession) pagePosts() []internal.Post {
+	start := s.page * s.pageSize
+	return s.posts[start:min(start+s.pageSize, len(s.posts))]
+}
+
+// Escape sequences for drawing the review screen
+const (
+	enterAltScreen = "\033[?1049h\033[?25l" // Switch to the alternate screen and hide the cursor
+	leaveAltScreen = "\033[?25h\033[?1049l" // Show the cursor and go back to the normal screen
+	clearScreen    = "\033[H\033[2J"
+	reverseVideo   = "\033[7m"
+)
+
+const reviewKeyHelp = "↑/↓ move  space toggle  ←/→ page  a/u page  A/U all  enter act on selection  q quit"
+
+// render draws the whole screen in one write. Lines end in \r\n, since a terminal in raw
+// mode doesn't return the carriage by itself.
+func (s *reviewSession) render(w io.Writer) {
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "Page %d/%d, %d of %d selected\r\n\r\n", s.page+1, s.pages(), len(s.selected), len(s.posts))
+	for i, post := range s.pagePosts() {
+		box := "[ ]"
+		if s.selected[post.ID] {
+			box = "[x]"
+		}
+		icon := map[string]string{"delete": "🗑️ ", "redact": "✏️ ", "unlike": "👎", "unshare": "🔄"}[s.action(post)]
+		line := fmt.Sprintf("%s %s [%s] @%s - %s", box, icon, post.CreatedAt.Format("2006-01-02"), post.Handle, truncateContent(post.Content, 60))
+		if i == s.cursor {
+			fmt.Fprintf(&b, "> %s%s%s\r\n", reverseVideo, line, colorReset)
+		} else {
+			fmt.Fprintf(&b, "  %s\r\n", line)
+		}
+	}
+	b.WriteString("\r\n")
+	if s.message != "" {
+		b.WriteString(s.message + "\r\n")
+	}
+	b.WriteString(reviewKeyHelp + "\r\n")
+	io.WriteString(w, b.String())
+}
+
+// reviewOutcome is what a key press asks of the review
+type reviewOutcome int
+
+const (
+	reviewContinue reviewOutcome = iota
+	reviewQuit
+	reviewGo // Act on the selection, once confirmed
+)
+
+// press handles one key, as named by readKey
+func (s *reviewSession) press(key string) reviewOutcome {
+	s.message = ""
+	switch key {
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		} else if s.page > 0 {
+			s.page--
+			s.cursor = len(s.pagePosts()) - 1
+		}
+	case "down", "j":
+		if s.cursor < len(s.pagePosts())-1 {
+			s.cursor++
+		} else if s.page+1 < s.pages() {
+			s.page++
+			s.cursor = 0
+		}
+	case "left", "pgup", "p":
+		if s.page == 0 {
+			s.message = "Already on the first page"
+			break
+		}
+		s.page--
+		s.cursor = 0
+	case "right", "pgdn", "n":
+		if s.page+1 >= s.pages() {
+			s.message = "Already on the last page"
+			break
+		}
+		s.page++
+		s.cursor = 0
+	case "space", "x":
+		id := s.pagePosts()[s.cursor].ID
+		s.setSelected(id, !s.selected[id])
+	case "a", "u":
+		for _, post := range s.pagePosts() {
+			s.setSelected(post.ID, key == "a")
+		}
+	case "A", "U":
+		for _, post := range s.posts {
+			s.setSelected(post.ID, key == "A")
+		}
+	case "enter":
+		if len(s.selected) == 0 {
+			s.message = "Nothing is selected: space ticks the highlighted post"
+			break
+		}
+		return reviewGo
+	case "q", "esc", "ctrl-c":
+		return reviewQuit
+	}
+	return reviewContinue
+}
+
+func (s *reviewSession) setSelected(id string, selected bool) {
+	if selected {
+		s.selected[id] = true
+	} else {
+		delete(s.selected, id)
+	}
+}
+
+// summary counts the selected posts by what will be done to them
+func (s *reviewSession) summary() string {
+	counts := make(map[string]int)
+	for _, post := range s.posts {
+		if s.selected[post.ID] {
+			counts[s.action(post)]++
+		}
+	}
+	var parts []string
+	for _, action := range []string{"delete", "redact", "unlike", "unshare"} {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", action, counts[action]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escapeKeys names the keys sent as escape sequences, by what follows "\033[" or "\033O"
+var escapeKeys = map[string]string{
+	"A": "up", "B": "down", "C": "right", "D": "left",
+	"5~": "pgup", "6~": "pgdn",
+}
+
+// readKey reads one key press from a terminal in raw mode. Keys with a name in review
+// (arrows, page up and down, enter, space, escape and ctrl-c) come back as that name,
+// unknown escape sequences as "", and anything else as the character typed.
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case ' ':
+		return "space", nil
+	case 3:
+		return "ctrl-c", nil
+	case 27:
+		// A lone escape is the key itself; a sequence arrives all at once
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _ := r.Peek(1); next[0] != '[' && next[0] != 'O' {
+			return "esc", nil
+		}
+		r.ReadByte()
+		var seq []byte
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq = append(seq, b)
+			if b >= 0x40 && b <= 0x7e {
+				return escapeKeys[string(seq)], nil
+			}
+		}
+	}
+	return string(c), nil
+}
+
+// runReview shows the session and handles key presses until the user quits or goes
+// ahead, returning true once they have confirmed acting on the selected posts. With yes,
+// going ahead isn't confirmed. Running out of input counts as quitting.
+func runReview(r *bufio.Reader, w io.Writer, session *reviewSession, yes bool) bool {
+	for {
+		session.render(w)
+		key, err := readKey(r)
+		if err != nil {
+			return false
+		}
+		switch session.press(key) {
+		case reviewQuit:
+			return false
+		case reviewGo:
+			if yes {
+				return true
+			}
+			session.message = fmt.Sprintf("About to %s. This can't be undone. Go ahead? (--yes skips this question) [y/N]", session.summary())
+			session.render(w)
+			answer, err := readKey(r)
+			if err != nil {
+				return false
+			}
+			if answer == "y" || answer == "Y" {
+				return true
+			}
+			session.message = "Not confirmed, carry on reviewing"
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.Flags().String("platforms", "", "The platform to review (bluesky or mastodon)")
+	reviewCmd.Flags().String("policy", "", "Named retention policy to start from: conservative, low-engagement or aggressive (see 'cringesweeper policy presets'); other flags override its settings")
+	reviewCmd.Flags().String("max-post-age", "", "Consider posts older than this (e.g., 30d, 1y, 24h)")
+	reviewCmd.Flags().String("before-date", "", "Consider posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	reviewCmd.Flags().String("after-date", "", "Only consider posts created on or after this date (YYYY-MM-DD or MM/DD/YYYY)")
+	reviewCmd.Flags().Bool("preserve-selflike", false, "Don't offer user's own posts that they have liked")
+	reviewCmd.Flags().Bool("preserve-pinned", false, "Don't offer pinned posts")
+	reviewCmd.Flags().Bool("preserve-with-replies", false, "Don't offer posts other people have replied to")
+	reviewCmd.Flags().String("preserve-hashtags", "", "Comma-separated hashtags whose posts are never offered (e.g., #keep,#portfolio)")
+	reviewCmd.Flags().String("with-hashtags", "", "Only offer posts tagged with one of these comma-separated hashtags (e.g., #conf2019)")
+	reviewCmd.Flags().String("preserve-language", "", "Comma-separated languages whose posts are never offered (e.g., en,de)")
+	reviewCmd.Flags().String("language", "", "Only offer posts in one of these comma-separated languages (e.g., en,de)")
+	reviewCmd.Flags().Bool("media-only", false, "Only offer posts with media attachments (images, video)")
+	reviewCmd.Flags().Bool("skip-media", false, "Don't offer posts with media attachments, only text posts")
+	reviewCmd.MarkFlagsMutuallyExclusive("media-only", "skip-media")
+	reviewCmd.Flags().Bool("with-links", false, "Only offer posts containing links")
+	reviewCmd.Flags().String("links-to", "", "Only offer posts linking to one of these comma-separated domains or their subdomains (e.g., oldjob.com)")
+	reviewCmd.Flags().Bool("replies-only", false, "Only offer your replies to other people's posts")
+	reviewCmd.Flags().Bool("skip-replies", false, "Don't offer replies, only top-level posts")
+	reviewCmd.MarkFlagsMutuallyExclusive("replies-only", "skip-replies")
+	reviewCmd.Flags().Bool("only-sensitive", false, "Only offer posts marked sensitive or behind a content warning")
+	reviewCmd.Flags().Bool("preserve-cw", false, "Don't offer posts behind a content warning")
+	reviewCmd.Flags().String("visibility", "", "Only offer posts with one of these comma-separated visibilities: public, unlisted, followers-only, direct")
+	reviewCmd.Flags().Bool("preserve-direct", false, "Don't offer direct messages")
+	reviewCmd.Flags().String("exclude-file", "", "File of post URLs or IDs, one per line, that are never offered")
+	reviewCmd.Flags().Bool("unlike-posts", false, "Also offer posts you've liked, to unlike")
+	reviewCmd.Flags().Bool("liked-post-age", false, "Judge likes by the age of the liked post instead of when you liked it (Bluesky)")
+	reviewCmd.Flags().Bool("redact", false, "Edit the chosen posts to a placeholder instead of deleting them (Mastodon, GoToSocial)")
+	reviewCmd.Flags().Bool("unshare-reposts", false, "Unshare/unrepost instead of deleting reposts")
+	reviewCmd.Flags().Bool("unshare-self-reposts", false, "Also offer reposts of your own posts, to unshare without touching the original")
+	reviewCmd.Flags().Bool("skip-self-reposts", false, "Don't offer reposts or quotes of your own posts")
+	reviewCmd.Flags().Bool("only-self-reposts", false, "Only offer reposts and quotes of your own posts")
+	reviewCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "only-self-reposts")
+	reviewCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "unshare-self-reposts")
+	reviewCmd.Flags().Bool("delete-whole-threads", false, "When a self-thread's first post is offered, also offer all your replies in that thread")
+	reviewCmd.Flags().Int("max-likes", 0, "Only offer posts with at most this many likes")
+	reviewCmd.Flags().Int("max-reposts", 0, "Only offer posts with at most this many reposts")
+	reviewCmd.Flags().Int("max-replies", 0, "Only offer posts with at most this many replies")
+	reviewCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 2s for Mastodon and GoToSocial, 1s for Bluesky)")
+	reviewCmd.Flags().Bool("continue", false, "Search the whole timeline for matching posts, not just the most recent")
+	reviewCmd.Flags().Bool("accept-instance-rules", false, "Acknowledge the instance's rules without prompting before the first prune on it")
+	reviewCmd.Flags().Int("page-size", 10, "Number of posts shown per page")
+}
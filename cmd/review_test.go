@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func reviewPosts(n int) []internal.Post {
+	posts := make([]internal.Post, n)
+	for i := range posts {
+		posts[i] = internal.Post{
+			ID:        fmt.Sprintf("post%d", i+1),
+			Type:      internal.PostTypeOriginal,
+			Handle:    "user.bsky.social",
+			Content:   fmt.Sprintf("Post number %d", i+1),
+			CreatedAt: time.Date(2020, 1, i+1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	posts[n-1].Type = internal.PostTypeRepost
+	return posts
+}
+
+func TestReviewSessionPress(t *testing.T) {
+	session := newReviewSession(reviewPosts(5), 2)
+
+	steps := []struct {
+		key      string
+		selected []string
+		page     int
+		cursor   int
+		message  bool
+	}{
+		{"space", []string{"post1"}, 0, 0, false},
+		{"down", []string{"post1"}, 0, 1, false},
+		{"x", []string{"post1", "post2"}, 0, 1, false},
+		{"up", []string{"post1", "post2"}, 0, 0, false},
+		{"space", []string{"post2"}, 0, 0, false},
+		{"left", []string{"post2"}, 0, 0, true},
+		{"right", []string{"post2"}, 1, 0, false},
+		{"a", []string{"post2", "post3", "post4"}, 1, 0, false},
+		{"up", []string{"post2", "post3", "post4"}, 0, 1, false},
+		{"j", []string{"post2", "post3", "post4"}, 1, 0, false},
+		{"u", []string{"post2"}, 1, 0, false},
+		{"A", []string{"post1", "post2", "post3", "post4", "post5"}, 1, 0, false},
+		{"pgdn", []string{"post1", "post2", "post3", "post4", "post5"}, 2, 0, false},
+		{"down", []string{"post1", "post2", "post3", "post4", "post5"}, 2, 0, false},
+		{"n", []string{"post1", "post2", "post3", "post4", "post5"}, 2, 0, true},
+		{"U", nil, 2, 0, false},
+		{"?", nil, 2, 0, false},
+	}
+
+	for _, step := range steps {
+		if outcome := session.press(step.key); outcome != reviewContinue {
+			t.Fatalf("%q: didn't expect the review to finish", step.key)
+		}
+		if session.page != step.page || session.cursor != step.cursor {
+			t.Errorf("%q: expected page %d cursor %d, got page %d cursor %d", step.key, step.page, step.cursor, session.page, session.cursor)
+		}
+		if (session.message != "") != step.message {
+			t.Errorf("%q: unexpected message %q", step.key, session.message)
+		}
+		if len(session.selected) != len(step.selected) {
+			t.Errorf("%q: expected %v selected, got %v", step.key, step.selected, session.selected)
+		}
+		for _, id := range step.selected {
+			if !session.selected[id] {
+				t.Errorf("%q: expected %s to be selected", step.key, id)
+			}
+		}
+	}
+
+	if outcome := session.press("enter"); outcome != reviewContinue || session.message == "" {
+		t.Error("Expected enter with nothing selected to be refused")
+	}
+	for _, key := range []string{"q", "esc", "ctrl-c"} {
+		if outcome := session.press(key); outcome != reviewQuit {
+			t.Errorf("Expected %q to quit", key)
+		}
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	input := "\x1b[A\x1b[B\x1bOC\x1b[D\x1b[5~\x1b[6~\x1b[1;5A\r \x03xé\x1b"
+	want := []string{"up", "down", "right", "left", "pgup", "pgdn", "", "enter", "space", "ctrl-c", "x", "é", "esc"}
+
+	r := bufio.NewReader(strings.NewReader(input))
+	for _, w := range want {
+		got, err := readKey(r)
+		if err != nil {
+			t.Fatalf("readKey() error = %v, want %q", err, w)
+		}
+		if got != w {
+			t.Errorf("readKey() = %q, want %q", got, w)
+		}
+	}
+	if _, err := readKey(r); err == nil {
+		t.Error("Expected an error at the end of input")
+	}
+}
+
+func TestRunReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		yes      bool
+		want     bool
+		selected int
+		contains string
+	}{
+		{"select and confirm", " \x1b[B\x1b[B \ry", false, true, 2, "About to delete 2. This can't be undone."},
+		{"quit", "Aq", false, false, 5, ""},
+		{"end of input", " ", false, false, 1, ""},
+		{"declined then confirmed", "\x1b[C \rn\rY", false, true, 1, "Not confirmed, carry on reviewing"},
+		{"mixed actions", "A\ry", false, true, 5, "About to delete 4, unshare 1."},
+		{"yes skips confirming", "A\r", true, true, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			session := newReviewSession(reviewPosts(5), 3)
+			got := runReview(bufio.NewReader(strings.NewReader(tt.input)), &out, session, tt.yes)
+			if got != tt.want {
+				t.Errorf("runReview() = %v, want %v\n%s", got, tt.want, out.String())
+			}
+			if len(session.selected) != tt.selected {
+				t.Errorf("Expected %d selected, got %d", tt.selected, len(session.selected))
+			}
+			if !strings.Contains(out.String(), tt.contains) {
+				t.Errorf("Expected output to contain %q:\n%s", tt.contains, out.String())
+			}
+		})
+	}
+}
+
+func TestReviewSessionRender(t *testing.T) {
+	session := newReviewSession(reviewPosts(3), 2)
+	session.press("down")
+	session.press("space")
+
+	var out bytes.Buffer
+	session.render(&out)
+	for _, want := range []string{
+		"Page 1/2, 1 of 3 selected\r\n",
+		"  [ ] 🗑️  [2020-01-01] @user.bsky.social - Post number 1\r\n",
+		"> \033[7m[x] 🗑️  [2020-01-02]",
+		reviewKeyHelp,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%q", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <post-url>",
+	Short: "Delete a single post by its URL",
+	Long: `Delete one post, given its bsky.app link, AT URI or Mastodon/GoToSocial status
+URL, without scanning the timeline for it.
+
+The platform is worked out from the URL: bsky.app links and at:// URIs are
+Bluesky, and any other link is matched to the instance of the Mastodon or
+GoToSocial account you've logged in to. Use --platform for a bare post ID, or
+to say which platform a link is on.
+
+On Mastodon and GoToSocial, if the post isn't yours but you favourited or
+boosted it, that is undone instead; on Bluesky, give the at:// URI of your like
+or repost record to remove it. The post is shown and you're asked before
+anything happens, unless --yes is given; use --dry-run to only show it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		platformName, _ := cmd.Flags().GetString("platform")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		w := cmd.OutOrStdout()
+		ref := args[0]
+
+		if platformName == "" {
+			var err error
+			platformName, err = postURLPlatform(ref)
+			if err != nil {
+				exitWithError(err)
+			}
+		}
+		key := internal.PostRefKey(ref)
+		if key == "" {
+			exitWithError(fmt.Errorf("no post ID in %q", ref))
+		}
+
+		client, exists := internal.GetClient(platformName)
+		if !exists {
+			exitWithError(fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", platformName, strings.Join(internal.GetAllPlatformNames(), ", ")))
+		}
+		username, err := internal.GetUsernameForPlatform(platformName, "")
+		if err != nil {
+			exitWithError(err)
+		}
+
+		options := internal.PruneOptions{ListedPosts: []string{key}, DryRun: dryRun, Output: w}
+		if !dryRun {
+			gate := newConfirmGate(bufio.NewReader(os.Stdin), w)
+			options.Confirm = func(post internal.Post, action string) internal.PruneDecision {
+				fmt.Fprintf(w, "\n[%s] @%s (%s)\n", post.CreatedAt.Format("2006-01-02"), post.Handle, post.Type)
+				fmt.Fprintf(w, "  %s\n", truncateContent(post.Content, 200))
+				if gate.Confirm(fmt.Sprintf("About to %s this post on %s", action, client.GetPlatformName())) {
+					return internal.PruneProceed
+				}
+				return internal.PruneSkip
+			}
+		}
+
+		result, err := client.PrunePosts(cmd.Context(), username, options)
+		if err != nil {
+			exitWithError(fmt.Errorf("removing post from %s: %w", client.GetPlatformName(), err))
+		}
+		if result.SkippedCount > 0 {
+			fmt.Fprintf(w, "Cancelled, nothing was changed on %s.\n", client.GetPlatformName())
+			return
+		}
+		if !dryRun && result.DeletedCount > 0 {
+			if err := internal.DropDeletedFromIndex(platformName, username); err != nil {
+				internal.WithPlatform(platformName).Warn().Err(err).Msg("Failed to update local post index")
+			}
+		}
+		if err := displayPruneResults(w, result, client.GetPlatformName(), dryRun); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// postURLPlatform works out which platform a post link is on: Bluesky for bsky.app links
+// and AT URIs, or whichever of Mastodon and GoToSocial has credentials on the link's
+// instance
+func postURLPlatform(ref string) (string, error) {
+	if strings.HasPrefix(ref, "at://") {
+		return "bluesky", nil
+	}
+	parsed, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("can't tell which platform %q is on; pass --platform", ref)
+	}
+	if strings.EqualFold(parsed.Host, "bsky.app") {
+		return "bluesky", nil
+	}
+
+	for _, platform := range []string{"mastodon", "gotosocial"} {
+		creds, err := internal.GetCredentialsForPlatform(platform)
+		if err != nil {
+			continue
+		}
+		instance := creds.Instance
+		if instanceURL, err := url.Parse(instance); err == nil && instanceURL.Host != "" {
+			instance = instanceURL.Host
+		}
+		if strings.EqualFold(instance, parsed.Host) {
+			return platform, nil
+		}
+	}
+	return "", fmt.Errorf("%s isn't the instance of a Mastodon or GoToSocial account you're logged in to; pass --platform", parsed.Host)
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+	rmCmd.Flags().String("platform", "", "Platform the post is on (bluesky, mastodon or gotosocial), if it can't be told from the URL")
+	rmCmd.Flags().Bool("dry-run", false, "Show the post and what would be done to it without doing it")
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import "testing"
+
+func TestPostURLPlatform(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_USER", "me@mastodon.example")
+	t.Setenv("MASTODON_INSTANCE", "https://mastodon.example")
+	t.Setenv("MASTODON_ACCESS_TOKEN", "token")
+	t.Setenv("GOTOSOCIAL_USER", "me@gts.example")
+	t.Setenv("GOTOSOCIAL_INSTANCE", "https://gts.example")
+	t.Setenv("GOTOSOCIAL_ACCESS_TOKEN", "token")
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"https://bsky.app/profile/me.bsky.social/post/3kabc123", "bluesky", false},
+		{"at://did:plc:abc/app.bsky.feed.post/3kabc123", "bluesky", false},
+		{"https://mastodon.example/@me/109876543210", "mastodon", false},
+		{"https://GTS.example/@me/statuses/01HABC", "gotosocial", false},
+		{"https://elsewhere.example/@someone/1", "", true},
+		{"109876543210", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := postURLPlatform(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postURLPlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("postURLPlatform() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
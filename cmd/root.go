@@ -4,13 +4,35 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
 	"github.com/spf13/cobra"
 )
 
-var logLevel string
+var (
+	logLevel     string
+	maxRetries   int
+	retryBackoff time.Duration
+
+	httpTimeout     time.Duration
+	maxIdleConns    int
+	maxConnsPerHost int
+
+	operatorName string
+
+	auditLogPath string
+
+	blueskyPDS     string
+	blueskyAppView string
+
+	// clock is the time source for age filtering and server scheduling; tests swap in a FakeClock
+	clock internal.Clock = internal.SystemClock
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -28,15 +50,49 @@ Key features:
 
 Use 'cringesweeper [command] --help' for detailed information about each command.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Fill in anything not given on the command line from the config file
+		if err := loadConfig(cmd); err != nil {
+			exitWithError(err)
+		}
+
 		// Initialize logger with the specified log level before any command runs
 		internal.InitLoggerWithLevel(logLevel)
+
+		// Apply retry settings for transient HTTP failures
+		internal.SetRetryConfig(internal.RetryConfig{
+			MaxRetries:     maxRetries,
+			InitialBackoff: retryBackoff,
+			MaxBackoff:     internal.DefaultRetryConfig().MaxBackoff,
+		})
+
+		// Apply HTTP timeout and connection pool limits to all platform clients
+		httpConfig := internal.DefaultHTTPClientConfig()
+		httpConfig.Timeout = httpTimeout
+		httpConfig.MaxIdleConns = maxIdleConns
+		httpConfig.MaxConnsPerHost = maxConnsPerHost
+		internal.SetHTTPClientConfig(httpConfig)
+
+		// Record who is running this, for the tombstone log and server metrics
+		internal.SetOperator(internal.ResolveOperator(operatorName))
+
+		// Keep a record of every action taken on a post
+		internal.SetAuditLogPath(auditLogPath)
+
+		// Point Bluesky at a self-hosted PDS or AppView
+		if err := internal.SetBlueskyHosts(blueskyPDS, blueskyAppView); err != nil {
+			exitWithError(err)
+		}
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// Cancel in-flight requests when the user interrupts the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -49,9 +105,36 @@ func init() {
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cringesweeper.yaml)")
 
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file with default flag values (default: ~/.config/cringesweeper/config.yaml)")
+
 	// Add log level flag that applies to all commands
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Set the logging level (debug, info, warn, error)")
 
+	// Retry flags for transient network and server errors
+	defaultRetry := internal.DefaultRetryConfig()
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", defaultRetry.MaxRetries, "Retries for network errors and 5xx responses (0 disables retries)")
+	rootCmd.PersistentFlags().DurationVar(&retryBackoff, "retry-backoff", defaultRetry.InitialBackoff, "Initial retry delay, doubled on each attempt with jitter")
+
+	// HTTP transport flags
+	defaultHTTP := internal.DefaultHTTPClientConfig()
+	rootCmd.PersistentFlags().DurationVar(&httpTimeout, "http-timeout", defaultHTTP.Timeout, "Timeout for each HTTP request to a platform")
+	rootCmd.PersistentFlags().IntVar(&maxIdleConns, "max-idle-conns", defaultHTTP.MaxIdleConns, "Maximum idle HTTP connections kept open across all hosts")
+	rootCmd.PersistentFlags().IntVar(&maxConnsPerHost, "max-conns-per-host", defaultHTTP.MaxConnsPerHost, "Maximum concurrent HTTP connections per host (0 for unlimited)")
+
+	// Confirmation prompts before anything is deleted or removed
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Go ahead without asking to confirm destructive actions, for scripts and cron jobs")
+	rootCmd.PersistentFlags().BoolVar(&assumeYes, "force", false, "Same as --yes")
+
+	// Operator identity for attributing prune runs
+	rootCmd.PersistentFlags().StringVar(&operatorName, "operator", "", "Identity recorded against prune runs (default: $"+internal.OperatorEnvVar+", then the OS user)")
+
+	// Audit log of every action taken on a post
+	rootCmd.PersistentFlags().StringVar(&auditLogPath, "audit-log", "", "JSON Lines file recording every delete, redact, unlike and unshare with a snapshot of the post (default: ~/.config/cringesweeper/audit.jsonl; \"off\" to disable)")
+
+	// Bluesky servers, for accounts on a self-hosted PDS
+	rootCmd.PersistentFlags().StringVar(&blueskyPDS, "bluesky-pds", "", "Bluesky PDS to log in to with an app password (default: the saved credentials' PDS, then $"+internal.BlueskyPDSEnvVar+", then bsky.social)")
+	rootCmd.PersistentFlags().StringVar(&blueskyAppView, "bluesky-appview", "", "Bluesky AppView to read public posts and profiles from (default: $"+internal.BlueskyAppViewEnvVar+", then public.api.bsky.app)")
+
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -69,10 +69,41 @@ func TestSubcommands(t *testing.T) {
 			t.Error("prune command should be registered with root command")
 		}
 	})
+
+	t.Run("analyze command is registered", func(t *testing.T) {
+		analyzeCmd := findCommand(rootCmd, "analyze")
+		if analyzeCmd == nil {
+			t.Error("analyze command should be registered with root command")
+		}
+	})
+
+	t.Run("review command is registered", func(t *testing.T) {
+		if findCommand(rootCmd, "review") == nil {
+			t.Error("review command should be registered with root command")
+		}
+	})
+
+	t.Run("stats command is registered", func(t *testing.T) {
+		if findCommand(rootCmd, "stats") == nil {
+			t.Error("stats command should be registered with root command")
+		}
+	})
+
+	t.Run("sync command is registered", func(t *testing.T) {
+		if findCommand(rootCmd, "sync") == nil {
+			t.Error("sync command should be registered with root command")
+		}
+	})
+
+	t.Run("rm command is registered", func(t *testing.T) {
+		if findCommand(rootCmd, "rm") == nil {
+			t.Error("rm command should be registered with root command")
+		}
+	})
 }
 
 func TestCommandStructure(t *testing.T) {
-	commands := []*cobra.Command{authCmd, lsCmd, pruneCmd}
+	commands := []*cobra.Command{authCmd, lsCmd, pruneCmd, analyzeCmd, statsCmd, reviewCmd, syncCmd, rmCmd}
 
 	for _, cmd := range commands {
 		t.Run(cmd.Use+" command structure", func(t *testing.T) {
@@ -109,6 +140,19 @@ func TestRootFlags(t *testing.T) {
 	})
 }
 
+func TestConfirmationFlags(t *testing.T) {
+	for _, name := range []string{"yes", "force"} {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		if flag == nil {
+			t.Errorf("Root command should have a persistent %s flag", name)
+			continue
+		}
+		if flag.DefValue != "false" {
+			t.Errorf("Expected %s to default to false, got %q", name, flag.DefValue)
+		}
+	}
+}
+
 func TestAuthCommandFlags(t *testing.T) {
 	t.Run("auth has platforms flag", func(t *testing.T) {
 		flag := authCmd.Flags().Lookup("platforms")
@@ -159,12 +203,46 @@ func TestPruneCommandFlags(t *testing.T) {
 		{"platforms", false, "", false},
 		{"max-post-age", false, "", false},
 		{"before-date", false, "", false},
+		{"after-date", false, "", false},
 		{"preserve-selflike", false, "", false},
 		{"preserve-pinned", false, "", false},
+		{"preserve-hashtags", false, "", false},
+		{"with-hashtags", false, "", false},
+		{"preserve-language", false, "", false},
+		{"language", false, "", false},
+		{"only-sensitive", false, "", false},
+		{"preserve-cw", false, "", false},
+		{"visibility", false, "", false},
+		{"preserve-direct", false, "", false},
+		{"exclude-file", false, "", false},
+		{"ids-file", false, "", false},
+		{"id", false, "", false},
+		{"redact", false, "", false},
+		{"media-only", false, "", false},
+		{"skip-media", false, "", false},
 		{"unlike-posts", false, "", false},
+		{"liked-post-age", false, "", false},
 		{"unshare-reposts", false, "", false},
+		{"unshare-self-reposts", false, "", false},
+		{"delete-whole-threads", false, "", false},
 		{"dry-run", false, "", false},
+		{"interactive", false, "", false},
 		{"rate-limit-delay", false, "", false},
+		{"batch-writes", false, "", false},
+		{"from-index", false, "", false},
+		{"max-likes", false, "", false},
+		{"max-reposts", false, "", false},
+		{"max-replies", false, "", false},
+		{"verify-counts", false, "", false},
+		{"verify", false, "", false},
+		{"follow-up-passes", false, "", false},
+		{"accept-instance-rules", false, "", false},
+		{"progress-interval", false, "", false},
+		{"max-runtime", false, "", false},
+		{"max-requests", false, "", false},
+		{"max-deletions", false, "", false},
+		{"plan-out", false, "", false},
+		{"apply-plan", false, "", false},
 	}
 
 	for _, expected := range expectedFlags {
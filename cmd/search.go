@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query> [username]",
+	Short: "Find posts in a timeline that contain some text",
+	Long: `Walk a user's entire timeline and list the posts whose content contains the
+query, ignoring case. A repost is matched on the content of the post it shares.
+
+Matches are listed in the same formats as ls, so --output=csv gives a
+spreadsheet of them and --sort=likes shows the most popular first. This is a
+quick way to see what posts mentioning a word or phrase you'd like to be rid
+of look like before pruning.
+
+Use --from-index to search the local index kept by 'cringesweeper sync'
+instead of walking the timeline over the network. Nothing is ever deleted.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		fromIndex, _ := cmd.Flags().GetBool("from-index")
+		outputStr, _ := cmd.Flags().GetString("output")
+		sortStr, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+
+		query := strings.TrimSpace(args[0])
+		if query == "" {
+			exitWithError(fmt.Errorf("the search query can't be empty"))
+		}
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial,twitter) or 'all'\n")
+			os.Exit(1)
+		}
+
+		platforms, err := internal.ParseReadablePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		out, err := newPostOutput(outputStr, os.Stdout, os.Stderr)
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := out.sortBy(sortStr, reverse); err != nil {
+			exitWithError(err)
+		}
+
+		argUsername := ""
+		if len(args) > 1 {
+			argUsername = args[1]
+		}
+
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				out.statusf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+			out.startPlatform()
+
+			username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+			if err != nil {
+				presentError(out.status, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+
+			reader, exists := internal.GetReader(platformName)
+			if !exists {
+				out.statusf("Error: Unsupported platform '%s'. Supported platforms: %s\n",
+					platformName, strings.Join(internal.GetAllReadablePlatformNames(), ", "))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+			if fromIndex {
+				reader, err = indexReader(platformName, username)
+				if err != nil {
+					presentError(out.status, fmt.Errorf("%s: %w", platformName, err))
+					if len(platforms) > 1 {
+						continue
+					}
+					os.Exit(1)
+				}
+			}
+
+			searchTimeline(ctx, out, reader, username, query)
+			out.endPlatform()
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				out.statusf("\n")
+			}
+		}
+
+		if err := out.flush(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// searchTimeline walks a user's timeline, writing each post that matches query to out
+// as it's found, and reports how many matched
+func searchTimeline(ctx context.Context, out *postOutput, reader internal.PostReader, username, query string) {
+	platform := reader.GetPlatformName()
+	out.statusf("🔍 Searching %s for %q...\n\n", platform, query)
+
+	searched, matched := 0, 0
+	err := walkTimeline(ctx, reader, username, func(posts []internal.Post) {
+		for _, post := range posts {
+			searched++
+			if matchesQuery(post, query) {
+				matched++
+				out.post(post)
+			}
+		}
+	})
+	if err != nil {
+		out.statusf("Error fetching posts from %s: %v\n", platform, err)
+	}
+
+	if matched == 0 {
+		out.statusf("No posts containing %q found in %d searched\n", query, searched)
+		return
+	}
+	out.statusf("\nFound %d posts containing %q in %d searched\n", matched, query, searched)
+}
+
+// matchesQuery reports whether the content listed for a post contains query, ignoring case
+func matchesQuery(post internal.Post, query string) bool {
+	return strings.Contains(strings.ToLower(listedContent(post)), strings.ToLower(query))
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial,twitter) or 'all' for all live platforms")
+	searchCmd.Flags().Bool("from-index", false, "Search the local index kept by 'cringesweeper sync' instead of fetching posts")
+	searchCmd.Flags().String("output", "text", "Output format: text, table for a line per post in aligned columns, or csv for a row per post with its date, type, content, URL and engagement counts")
+	searchCmd.Flags().String("sort", "", "Sort each platform's matches by date, likes, reposts or replies, most first")
+	searchCmd.Flags().Bool("reverse", false, "Reverse the sort order, listing the oldest or least popular matches first (sorts by date if --sort isn't given)")
+}
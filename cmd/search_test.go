@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestMatchesQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		post  internal.Post
+		query string
+		want  bool
+	}{
+		{"contains", internal.Post{Content: "Hot take about crypto"}, "crypto", true},
+		{"ignores case", internal.Post{Content: "CRYPTO is the future"}, "Crypto", true},
+		{"phrase", internal.Post{Content: "hot take: crypto"}, "hot take", true},
+		{"no match", internal.Post{Content: "Nice weather"}, "crypto", false},
+		{"repost matched on the shared post", internal.Post{Type: internal.PostTypeRepost, OriginalPost: &internal.Post{Content: "crypto thread"}}, "crypto", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesQuery(tt.post, tt.query); got != tt.want {
+				t.Errorf("matchesQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchTimeline(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	index := &internal.PostIndex{}
+	for i, content := range []string{"First crypto post", "Lunch", "More Crypto", "Dinner"} {
+		index.Merge([]internal.Post{{ID: string(rune('a' + i)), Type: internal.PostTypeOriginal, Content: content, CreatedAt: now.Add(-time.Duration(i) * time.Hour)}})
+	}
+	reader := internal.NewIndexReader(index, "Test")
+
+	var w, status bytes.Buffer
+	out, _ := newPostOutput("table", &w, &status)
+	searchTimeline(context.Background(), out, reader, "me", "crypto")
+
+	output := w.String()
+	if !strings.Contains(output, "First crypto post") || !strings.Contains(output, "More Crypto") || strings.Contains(output, "Lunch") {
+		t.Errorf("Expected only the matching posts, got:\n%s", output)
+	}
+	if !strings.Contains(output, `Found 2 posts containing "crypto" in 4 searched`) {
+		t.Errorf("Expected a count of matches, got:\n%s", output)
+	}
+
+	w.Reset()
+	searchTimeline(context.Background(), out, reader, "me", "nothing")
+	if !strings.Contains(w.String(), `No posts containing "nothing" found in 4 searched`) {
+		t.Errorf("Expected to be told nothing matched, got:\n%s", w.String())
+	}
+}
@@ -2,21 +2,30 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"html"
+	"io"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type PlatformStatus struct {
@@ -25,13 +34,30 @@ type PlatformStatus struct {
 	LastPruneTime    time.Time         `json:"last_prune_time"`
 	LastPruneStatus  string            `json:"last_prune_status"`
 	LastPruneError   string            `json:"last_prune_error"`
+	LastPruneWarnings []string         `json:"last_prune_warnings,omitempty"`
+	DryRunMatches    []DryRunMatch     `json:"-"` // Posts the latest dry run would have acted on
 	TotalRuns        int64             `json:"total_runs"`
 	SuccessfulRuns   int64             `json:"successful_runs"`
 	PostsProcessed   map[string]int64  `json:"posts_processed"`
 	IsPruning        bool              `json:"is_pruning"`
 	NextPruneTime    time.Time         `json:"next_prune_time"`
+	Schedule         string            `json:"schedule"`
+	CircuitState     string            `json:"circuit_state"`
+	CircuitOpenUntil time.Time         `json:"circuit_open_until,omitempty"`
+	ConsecutiveFailures int            `json:"consecutive_failures"` // Failed runs since the last success
+	CredentialsRejected bool           `json:"credentials_rejected"` // The last run failed because the platform refused the login
+	Account          *internal.AccountStats `json:"account,omitempty"` // What the account had left after the last run that got through its posts
 }
 
+// DryRunMatch is a post that a dry-run prune would have acted on
+type DryRunMatch struct {
+	Action string
+	Post   internal.Post
+}
+
+// dryRunPageSize is the number of dry-run matches shown per page on the status page
+const dryRunPageSize = 25
+
 type ServerState struct {
 	mu                sync.RWMutex
 	Platforms         map[string]*PlatformStatus `json:"platforms"`
@@ -39,6 +65,7 @@ type ServerState struct {
 	Version           map[string]string          `json:"version"`
 	PruneInterval     time.Duration              `json:"prune_interval"`
 	DryRun            bool                       `json:"dry_run"`
+	Operator          string                     `json:"operator"`
 }
 
 func (s *ServerState) UpdatePlatformStatus(platform string, status *PlatformStatus) {
@@ -71,13 +98,143 @@ type PlatformConfig struct {
 }
 
 type PlatformRunner struct {
-	Config  PlatformConfig
-	Options internal.PruneOptions
+	Config       PlatformConfig
+	Options      internal.PruneOptions
+	Breaker      *internal.CircuitBreaker
+	Schedule     internal.Schedule
+	MaxRuntime   time.Duration // Time budget for each run, zero for none
+	MaxRequests  int           // API request budget for each run, zero for none
+	MaxDeletions int           // Cap on the posts each run acts on, zero for none
+}
+
+// startRun returns the context and options for a run starting now, with its deadline set,
+// fresh request and action budgets attached, its API responses feeding the metrics and
+// the server's shutdown drain able to stop it
+func (r PlatformRunner) startRun(ctx context.Context) (context.Context, internal.PruneOptions) {
+	options := r.Options
+	if r.MaxRuntime > 0 {
+		options.Deadline = clock.Now().Add(r.MaxRuntime)
+	}
+	ctx = internal.WithAPIObserver(ctx, apiMetricsObserver(r.Config.name))
+	ctx = internal.WithDrain(ctx, serverRuns.drain)
+	ctx = internal.WithActionBudget(ctx, internal.NewActionBudget(r.MaxDeletions))
+	return internal.WithRequestBudget(ctx, internal.NewRequestBudget(r.MaxRequests)), options
+}
+
+// shutdownTimeout is how long shutdown waits for prune runs to drain and HTTP requests to
+// finish before giving up on them
+const shutdownTimeout = 30 * time.Second
+
+// pruneRuns tracks the server's prune runs in progress, so that shutdown can drain them:
+// no new runs start, and the ones going stop after the post in hand and report
+type pruneRuns struct {
+	drain *internal.Drain
+
+	mu       sync.Mutex
+	active   int
+	idle     chan struct{} // Closed once draining with no runs left
+	idleOnce sync.Once
+}
+
+func newPruneRuns() *pruneRuns {
+	return &pruneRuns{drain: internal.NewDrain(), idle: make(chan struct{})}
+}
+
+// begin counts a run starting, returning false once the server is draining
+func (r *pruneRuns) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drain.Draining() {
+		return false
+	}
+	r.active++
+	return true
+}
+
+// end counts a run finishing
+func (r *pruneRuns) end() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active--
+	if r.active == 0 && r.drain.Draining() {
+		r.idleOnce.Do(func() { close(r.idle) })
+	}
+}
+
+// drainAndWait drains the runs in progress and waits for them to finish, returning false
+// if some were still going when ctx ended
+func (r *pruneRuns) drainAndWait(ctx context.Context) bool {
+	r.mu.Lock()
+	r.drain.Start()
+	if r.active == 0 {
+		r.idleOnce.Do(func() { close(r.idle) })
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.idle:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// apiMetricsObserver records each API response from a platform in the API metrics, so
+// expired tokens and throttling show up before runs start failing
+func apiMetricsObserver(platform string) internal.APIObserver {
+	return func(status int, header http.Header) {
+		apiResponsesTotal.WithLabelValues(platform, apiStatusClass(status)).Inc()
+		if class := apiErrorClass(status); class != "" {
+			apiErrorsTotal.WithLabelValues(platform, class).Inc()
+		}
+		if limit, ok := internal.ParseRateLimit(header); ok {
+			apiRateLimitRemaining.WithLabelValues(platform).Set(float64(limit.Remaining))
+			if limit.Limit > 0 {
+				apiRateLimitLimit.WithLabelValues(platform).Set(float64(limit.Limit))
+			}
+			if !limit.Reset.IsZero() {
+				apiRateLimitReset.WithLabelValues(platform).Set(float64(limit.Reset.Unix()))
+			}
+		}
+	}
+}
+
+// apiStatusClass groups a response status as 2xx, 3xx, 4xx or 5xx, or "error" when
+// there was no response at all
+func apiStatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// apiErrorClass names the kind of failure a response status represents, or "" for
+// statuses that aren't worth alerting on
+func apiErrorClass(status int) string {
+	switch {
+	case status == 0:
+		return "network"
+	case status == http.StatusUnauthorized:
+		return "unauthorized"
+	case status == http.StatusForbidden:
+		return "forbidden"
+	case status == http.StatusTooManyRequests:
+		return "rate_limited"
+	case status >= 500:
+		return "server_error"
+	}
+	return ""
 }
 
 var (
 	// Global server state
 	serverState *ServerState
+
+	// Prune runs in progress, drained on shutdown
+	serverRuns = newPruneRuns()
+
+	// Recent prune runs, for /api/history
+	serverHistory = newRunHistory(100)
 	
 	// Prometheus metrics
 	pruneRunsTotal = prometheus.NewCounterVec(
@@ -85,7 +242,7 @@ var (
 			Name: "cringesweeper_prune_runs_total",
 			Help: "Total number of prune runs executed",
 		},
-		[]string{"platform", "status"},
+		[]string{"platform", "status", "operator"},
 	)
 	
 	postsProcessedTotal = prometheus.NewCounterVec(
@@ -93,7 +250,7 @@ var (
 			Name: "cringesweeper_posts_processed_total",
 			Help: "Total number of posts processed",
 		},
-		[]string{"platform", "action"},
+		[]string{"platform", "action", "operator"},
 	)
 	
 	pruneRunDuration = prometheus.NewHistogramVec(
@@ -144,13 +301,101 @@ var (
 		},
 		[]string{"platform"},
 	)
+	
+	circuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_circuit_breaker_state",
+			Help: "Circuit breaker state per platform (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"platform"},
+	)
+	
+	apiRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cringesweeper_api_requests_total",
+			Help: "Total number of requests made to platform APIs, including retries",
+		},
+		[]string{"platform"},
+	)
+	
+	apiResponsesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cringesweeper_api_responses_total",
+			Help: "Responses from platform APIs by status class (2xx to 5xx, or error when none was received)",
+		},
+		[]string{"platform", "status_class"},
+	)
+	
+	apiErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cringesweeper_api_errors_total",
+			Help: "Failed platform API requests by class (unauthorized, forbidden, rate_limited, server_error, network)",
+		},
+		[]string{"platform", "class"},
+	)
+	
+	apiRateLimitRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_api_rate_limit_remaining",
+			Help: "Requests left in the current rate limit window, as last reported by the platform",
+		},
+		[]string{"platform"},
+	)
+	
+	apiRateLimitLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_api_rate_limit_limit",
+			Help: "Requests allowed per rate limit window, as last reported by the platform",
+		},
+		[]string{"platform"},
+	)
+	
+	apiRateLimitReset = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_api_rate_limit_reset_timestamp",
+			Help: "When the current rate limit window resets, as last reported by the platform",
+		},
+		[]string{"platform"},
+	)
+	
+	accountPostsTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_account_posts_total",
+			Help: "Posts the platform reports on the account after the last prune run",
+		},
+		[]string{"platform"},
+	)
+	
+	accountPostsSeenRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_account_posts_seen_remaining",
+			Help: "Posts, reposts and likes seen this run that are left on the account after the last prune run, by type",
+		},
+		[]string{"platform", "type"},
+	)
+	
+	accountPostsMatching = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_account_posts_matching",
+			Help: "Posts seen this run that are left on the account after the last prune run and that the prune criteria still match",
+		},
+		[]string{"platform"},
+	)
+	
+	accountOldestSeenPostAge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cringesweeper_account_oldest_seen_post_age_seconds",
+			Help: "Age of the oldest post or repost seen this run that is left on the account, as of the last prune run",
+		},
+		[]string{"platform"},
+	)
 )
 
 func init() {
 	// Initialize server state
 	serverState = &ServerState{
 		Platforms: make(map[string]*PlatformStatus),
-		StartTime: time.Now(),
+		StartTime: clock.Now(),
 	}
 	
 	// Register metrics
@@ -162,6 +407,17 @@ func init() {
 	prometheus.MustRegister(versionInfo)
 	prometheus.MustRegister(platformActiveGauge)
 	prometheus.MustRegister(platformPruningGauge)
+	prometheus.MustRegister(circuitBreakerState)
+	prometheus.MustRegister(apiRequestsTotal)
+	prometheus.MustRegister(apiResponsesTotal)
+	prometheus.MustRegister(apiErrorsTotal)
+	prometheus.MustRegister(apiRateLimitRemaining)
+	prometheus.MustRegister(apiRateLimitLimit)
+	prometheus.MustRegister(apiRateLimitReset)
+	prometheus.MustRegister(accountPostsTotal)
+	prometheus.MustRegister(accountPostsSeenRemaining)
+	prometheus.MustRegister(accountPostsMatching)
+	prometheus.MustRegister(accountOldestSeenPostAge)
 }
 
 var serverCmd = &cobra.Command{
@@ -173,53 +429,141 @@ Use --platforms to monitor multiple platforms concurrently (e.g., --platforms=bl
 or --platforms=all). Each platform runs in its own goroutine with independent scheduling.
 
 This mode runs continuously and:
-- Periodically executes prune operations for each platform based on the configured interval
+- Periodically executes prune operations for each platform based on its schedule
 - Serves HTTP endpoints for health checks and Prometheus metrics with multi-platform status
 - Suitable for containerized deployments for automated post management across platforms
 
 Server endpoints:
 - GET /         - Health check with service information
 - GET /metrics  - Prometheus metrics endpoint
+- GET /healthz  - Liveness probe, 200 while the server is serving
+- GET /readyz   - Readiness probe, 503 once a platform's credentials are rejected
+                  or its last --ready-failures runs have failed
+- GET /api/history - The last --history-size prune runs as JSON, for dashboards
+
+Use --tls-cert and --tls-key to serve HTTPS. --auth-token and --basic-auth put
+everything but /healthz and /metrics behind a bearer token and/or a username
+and password; --metrics-token guards /metrics separately, for Prometheus.
+Use --metrics-port to serve /metrics on a port of its own rather than --port.
 
 In server mode, credentials are ONLY read from environment variables:
-- BLUESKY_USERNAME, BLUESKY_APP_PASSWORD
+- BLUESKY_USERNAME, BLUESKY_APP_PASSWORD, and BLUESKY_PDS for a self-hosted PDS
 - MASTODON_USERNAME, MASTODON_ACCESS_TOKEN, MASTODON_INSTANCE
+- GOTOSOCIAL_USERNAME, GOTOSOCIAL_ACCESS_TOKEN, GOTOSOCIAL_INSTANCE
 
 All prune flags are supported for configuring the periodic pruning behavior.
-Use --prune-interval to control how often pruning runs (default: 1h).`,
+
+Use --prune-schedule to run at fixed times with a cron expression, either for
+every platform (--prune-schedule="0 3 * * *") or for one platform
+(--prune-schedule="mastodon=30 4 * * *"); repeat the flag to combine them.
+Cron times use the server's local time zone. Platforms without a schedule run
+every --prune-interval (default: 1h), starting as soon as the server starts.
+
+Prune flags apply to every platform, but can be overridden for one platform by
+prefixing them with its name, e.g. --max-post-age=30d --bluesky.max-post-age=90d.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		platformsStr, _ := cmd.Flags().GetString("platforms")
 		port, _ := cmd.Flags().GetInt("port")
 		pruneIntervalStr, _ := cmd.Flags().GetString("prune-interval")
+		pruneScheduleValues, _ := cmd.Flags().GetStringArray("prune-schedule")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		preserveSelfLike, _ := cmd.Flags().GetBool("preserve-selflike")
-		preservePinned, _ := cmd.Flags().GetBool("preserve-pinned")
-		unlikePosts, _ := cmd.Flags().GetBool("unlike-posts")
-		unshareReposts, _ := cmd.Flags().GetBool("unshare-reposts")
-		maxAgeStr, _ := cmd.Flags().GetString("max-post-age")
-		beforeDateStr, _ := cmd.Flags().GetString("before-date")
-		rateLimitDelayStr, _ := cmd.Flags().GetString("rate-limit-delay")
+		breakerThreshold, _ := cmd.Flags().GetInt("breaker-threshold")
+		breakerCooldownStr, _ := cmd.Flags().GetString("breaker-cooldown")
+		acceptInstanceRules, _ := cmd.Flags().GetBool("accept-instance-rules")
+		progressIntervalStr, _ := cmd.Flags().GetString("progress-interval")
+		maxRuntimeStr, _ := cmd.Flags().GetString("max-runtime")
+		maxRequests, _ := cmd.Flags().GetInt("max-requests")
+		maxDeletions, _ := cmd.Flags().GetInt("max-deletions")
+		metricsPort, _ := cmd.Flags().GetInt("metrics-port")
+		readyFailures, _ := cmd.Flags().GetInt("ready-failures")
+		historySize, _ := cmd.Flags().GetInt("history-size")
+		tlsCert, _ := cmd.Flags().GetString("tls-cert")
+		tlsKey, _ := cmd.Flags().GetString("tls-key")
+		authToken := flagOrEnv(cmd, "auth-token", authTokenEnvVar)
+		basicAuth := flagOrEnv(cmd, "basic-auth", basicAuthEnvVar)
+		metricsToken := flagOrEnv(cmd, "metrics-token", metricsTokenEnvVar)
+
+		progressEvery, progressInterval, err := internal.ParseProgressInterval(progressIntervalStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		var maxRuntime time.Duration
+		if maxRuntimeStr != "" {
+			maxRuntime, err = timespec.ParseDuration(maxRuntimeStr)
+			if err != nil || maxRuntime == 0 {
+				exitWithError(fmt.Errorf("invalid max-runtime %q: use a positive duration such as 45m", maxRuntimeStr))
+			}
+		}
+		if maxRequests < 0 {
+			exitWithError(fmt.Errorf("invalid max-requests %d: must be 0 (no limit) or more", maxRequests))
+		}
+		if maxDeletions < 0 {
+			exitWithError(fmt.Errorf("invalid max-deletions %d: must be 0 (no limit) or more", maxDeletions))
+		}
+		if metricsPort < 0 || metricsPort > 65535 || (metricsPort != 0 && metricsPort == port) {
+			exitWithError(fmt.Errorf("invalid metrics-port %d: must be 0 (serve /metrics on --port) or another port", metricsPort))
+		}
+		if readyFailures < 0 {
+			exitWithError(fmt.Errorf("invalid ready-failures %d: must be 0 (ignore failed runs) or more", readyFailures))
+		}
+		if historySize < 0 {
+			exitWithError(fmt.Errorf("invalid history-size %d: must be 0 (keep no history) or more", historySize))
+		}
+		serverHistory = newRunHistory(historySize)
+		if (tlsCert == "") != (tlsKey == "") {
+			exitWithError(fmt.Errorf("--tls-cert and --tls-key must be given together"))
+		}
+		if tlsCert != "" {
+			// Load the pair now so a bad path fails at startup rather than in the listener
+			if _, err := tls.LoadX509KeyPair(tlsCert, tlsKey); err != nil {
+				exitWithError(fmt.Errorf("loading TLS certificate: %w", err))
+			}
+		}
+		auth, err := parseServerAuth(authToken, basicAuth)
+		if err != nil {
+			exitWithError(fmt.Errorf("invalid basic-auth: %w", err))
+		}
+		metricsAuth, _ := parseServerAuth(metricsToken, "")
+		if (auth.enabled() || metricsAuth.enabled()) && tlsCert == "" {
+			log.Warn().Msg("Endpoint credentials are being sent over plain HTTP; use --tls-cert and --tls-key or a TLS-terminating proxy")
+		}
 
 		// Parse prune interval
-		pruneInterval, err := parseDuration(pruneIntervalStr)
+		pruneInterval, err := timespec.ParseDuration(pruneIntervalStr)
 		if err != nil {
 			fmt.Printf("Error parsing prune-interval: %v\n", err)
 			os.Exit(1)
 		}
 
+		pruneSchedules, err := internal.ParsePruneSchedules(pruneScheduleValues)
+		if err != nil {
+			fmt.Printf("Error parsing prune-schedule: %v\n", err)
+			os.Exit(1)
+		}
+
+		breakerCooldown, err := timespec.ParseDuration(breakerCooldownStr)
+		if err != nil {
+			fmt.Printf("Error parsing breaker-cooldown: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Determine which platforms to use
 		var platforms []string
 		
 		if platformsStr == "" {
-			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon) or 'all'\n")
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
 			os.Exit(1)
 		}
 		
 		platforms, err = internal.ParsePlatforms(platformsStr)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
+		}
+
+		if err := checkPlatformOverrides(cmd, platforms); err != nil {
+			exitWithError(err)
 		}
 
 		// Get username with fallback priority: argument > environment variables only (no saved credentials)
@@ -233,7 +577,7 @@ Use --prune-interval to control how often pruning runs (default: 1h).`,
 		for _, platformName := range platforms {
 			username, err := internal.GetUsernameForPlatformEnvOnly(platformName, argUsername)
 			if err != nil {
-				fmt.Printf("Error for %s: %v\n", platformName, err)
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
 				fmt.Printf("In server mode, credentials must be provided via environment variables only.\n")
 				os.Exit(1)
 			}
@@ -253,71 +597,33 @@ Use --prune-interval to control how often pruning runs (default: 1h).`,
 		}
 
 		// Create platform-specific configurations
+		platformOptions := make(map[string]internal.PruneOptions)
 		for i, config := range platformConfigs {
-			// Parse rate limit delay - use platform-appropriate defaults
-			var rateLimitDelay time.Duration
-			if rateLimitDelayStr != "" {
-				delay, err := parseDuration(rateLimitDelayStr)
-				if err != nil {
-					fmt.Printf("Error parsing rate-limit-delay: %v\n", err)
-					os.Exit(1)
-				}
-				rateLimitDelay = delay
-			} else {
-				// Set platform-appropriate defaults
-				switch config.name {
-				case "mastodon":
-					rateLimitDelay = 60 * time.Second
-				case "bluesky":
-					rateLimitDelay = 1 * time.Second
-				default:
-					rateLimitDelay = 5 * time.Second
-				}
-			}
-
-			// Parse options for this platform
-			options := internal.PruneOptions{
-				PreserveSelfLike: preserveSelfLike,
-				PreservePinned:   preservePinned,
-				UnlikePosts:      unlikePosts,
-				UnshareReposts:   unshareReposts,
-				DryRun:           dryRun,
-				RateLimitDelay:   rateLimitDelay,
-			}
-
-			// Parse max age
-			if maxAgeStr != "" {
-				maxAge, err := parseDuration(maxAgeStr)
-				if err != nil {
-					fmt.Printf("Error parsing max-post-age: %v\n", err)
-					os.Exit(1)
-				}
-				options.MaxAge = &maxAge
-			}
-
-			// Parse before date
-			if beforeDateStr != "" {
-				beforeDate, err := parseDate(beforeDateStr)
-				if err != nil {
-					fmt.Printf("Error parsing before-date: %v\n", err)
-					os.Exit(1)
-				}
-				options.BeforeDate = &beforeDate
-			}
-
-			// Validate that at least one criteria is specified
-			if options.MaxAge == nil && options.BeforeDate == nil {
-				fmt.Printf("Error for %s: Must specify either --max-post-age or --before-date\n", config.name)
+			options, err := platformPruneOptions(cmd, config.name)
+			if err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", config.name, err))
 				os.Exit(1)
 			}
+			options.DryRun = dryRun
+			options.ProgressEvery = progressEvery
+			options.ProgressInterval = progressInterval
+			platformOptions[config.name] = options
 
 			// Verify credentials work before starting server
 			if err := verifyCredentials(config.client, config.name); err != nil {
-				fmt.Printf("Error: Failed to verify credentials for %s: %v\n", config.name, err)
+				presentError(os.Stdout, fmt.Errorf("failed to verify credentials for %s: %w", config.name, err))
 				fmt.Printf("In server mode, credentials must be provided via environment variables.\n")
 				os.Exit(1)
 			}
 
+			// There's nobody to prompt in server mode, so rules must be acknowledged up front
+			if !dryRun {
+				if err := ensureInstanceRulesAcknowledged(cmd.Context(), os.Stdout, config.client, config.username, acceptInstanceRules, false); err != nil {
+					presentError(os.Stdout, fmt.Errorf("%s: %w", config.name, err))
+					os.Exit(1)
+				}
+			}
+
 			// Update platform config with options
 			platformConfigs[i] = PlatformConfig{
 				name:     config.name,
@@ -328,7 +634,7 @@ Use --prune-interval to control how often pruning runs (default: 1h).`,
 			log.Info().
 				Str("platform", config.name).
 				Str("username", config.username).
-				Dur("prune_interval", pruneInterval).
+				Stringer("schedule", scheduleForPlatform(pruneSchedules, config.name, pruneInterval)).
 				Int("port", port).
 				Bool("dry_run", dryRun).
 				Msg("Configured platform for CringeSweeper server")
@@ -344,6 +650,7 @@ Use --prune-interval to control how often pruning runs (default: 1h).`,
 		// Initialize server state
 		serverState.PruneInterval = pruneInterval
 		serverState.DryRun = dryRun
+		serverState.Operator = internal.GetOperator()
 		serverState.Version = internal.GetFullVersionInfo()
 		
 		// Initialize platform statuses
@@ -353,69 +660,39 @@ Use --prune-interval to control how often pruning runs (default: 1h).`,
 				Username:       config.username,
 				LastPruneStatus: "pending",
 				PostsProcessed: make(map[string]int64),
-				NextPruneTime:  time.Now(),
+				NextPruneTime:  clock.Now(),
+				Schedule:       scheduleForPlatform(pruneSchedules, config.name, pruneInterval).String(),
+				CircuitState:   internal.BreakerClosed.String(),
 			})
 			platformActiveGauge.WithLabelValues(config.name).Set(1)
+			circuitBreakerState.WithLabelValues(config.name).Set(float64(internal.BreakerClosed))
 		}
 		
 		// Create platform configurations with their specific options
 		var platformRunners []PlatformRunner
 		for _, config := range platformConfigs {
-			// Create platform-specific options
-			var rateLimitDelay time.Duration
-			if rateLimitDelayStr != "" {
-				delay, err := parseDuration(rateLimitDelayStr)
-				if err != nil {
-					fmt.Printf("Error parsing rate-limit-delay: %v\n", err)
-					os.Exit(1)
-				}
-				rateLimitDelay = delay
-			} else {
-				switch config.name {
-				case "mastodon":
-					rateLimitDelay = 60 * time.Second
-				case "bluesky":
-					rateLimitDelay = 1 * time.Second
-				default:
-					rateLimitDelay = 5 * time.Second
-				}
-			}
-			
-			options := internal.PruneOptions{
-				PreserveSelfLike: preserveSelfLike,
-				PreservePinned:   preservePinned,
-				UnlikePosts:      unlikePosts,
-				UnshareReposts:   unshareReposts,
-				DryRun:           dryRun,
-				RateLimitDelay:   rateLimitDelay,
-			}
-			
-			if maxAgeStr != "" {
-				maxAge, err := parseDuration(maxAgeStr)
-				if err != nil {
-					fmt.Printf("Error parsing max-post-age: %v\n", err)
-					os.Exit(1)
-				}
-				options.MaxAge = &maxAge
-			}
-			
-			if beforeDateStr != "" {
-				beforeDate, err := parseDate(beforeDateStr)
-				if err != nil {
-					fmt.Printf("Error parsing before-date: %v\n", err)
-					os.Exit(1)
-				}
-				options.BeforeDate = &beforeDate
-			}
-			
+			options := platformOptions[config.name]
 			platformRunners = append(platformRunners, PlatformRunner{
-				Config:  config,
-				Options: options,
+				Config:       config,
+				Options:      options,
+				Breaker:      internal.NewCircuitBreaker(breakerThreshold, breakerCooldown, clock),
+				Schedule:     scheduleForPlatform(pruneSchedules, config.name, pruneInterval),
+				MaxRuntime:   maxRuntime,
+				MaxRequests:  maxRequests,
+				MaxDeletions: maxDeletions,
 			})
 		}
 		
 		// Start the multi-platform server
-		startMultiPlatformServer(platformRunners, pruneInterval, port)
+		startMultiPlatformServer(platformRunners, serverConfig{
+			port:          port,
+			metricsPort:   metricsPort,
+			readyFailures: readyFailures,
+			tlsCert:       tlsCert,
+			tlsKey:        tlsKey,
+			auth:          auth,
+			metricsAuth:   metricsAuth,
+		})
 	},
 }
 
@@ -425,16 +702,231 @@ func verifyCredentials(client internal.SocialClient, platform string) error {
 	return err
 }
 
-func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval time.Duration, port int) {
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// scheduleForPlatform picks the platform's own cron schedule, then the default cron
+// schedule, falling back to running every pruneInterval
+func scheduleForPlatform(schedules map[string]internal.Schedule, platform string, pruneInterval time.Duration) internal.Schedule {
+	if schedule, ok := schedules[platform]; ok {
+		return schedule
+	}
+	if schedule, ok := schedules[""]; ok {
+		return schedule
+	}
+	return internal.IntervalSchedule{Interval: pruneInterval}
+}
 
-	// Initialize version metrics
-	version := internal.GetFullVersionInfo()
-	versionInfo.WithLabelValues(version["version"], version["commit"], version["build_time"]).Set(1)
+// platformOverridableFlags are the prune flags that can be set for one platform in
+// server mode as --<platform>.<flag>, e.g. --bluesky.max-post-age=90d
+var platformOverridableFlags = []string{
+	"max-post-age",
+	"before-date",
+	"after-date",
+	"preserve-selflike",
+	"preserve-pinned",
+	"preserve-with-replies",
+	"preserve-hashtags",
+	"with-hashtags",
+	"preserve-language",
+	"language",
+	"media-only",
+	"skip-media",
+	"with-links",
+	"links-to",
+	"replies-only",
+	"skip-replies",
+	"only-sensitive",
+	"preserve-cw",
+	"visibility",
+	"preserve-direct",
+	"exclude-file",
+	"liked-post-age",
+	"unlike-posts",
+	"redact",
+	"unshare-reposts",
+	"unshare-self-reposts",
+	"skip-self-reposts",
+	"only-self-reposts",
+	"delete-whole-threads",
+	"max-likes",
+	"max-reposts",
+	"max-replies",
+	"rate-limit-delay",
+}
+
+// addPlatformOverrideFlags registers a hidden --<platform>.<flag> twin of each overridable
+// flag for every live platform, so the help output isn't swamped with them
+func addPlatformOverrideFlags(cmd *cobra.Command) {
+	for _, platform := range internal.GetAllPlatformNames() {
+		addOverrideFlags(cmd, platform, "for "+platform)
+	}
+}
+
+// addOverrideFlags registers a hidden --<prefix>.<flag> twin of each overridable flag
+// the command has, described as overriding the flag with the given purpose
+func addOverrideFlags(cmd *cobra.Command, prefix, purpose string) {
+	for _, name := range platformOverridableFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			continue
+		}
+		overrideName := prefix + "." + name
+		usage := fmt.Sprintf("Override --%s %s", name, purpose)
+		switch flag.Value.Type() {
+		case "bool":
+			cmd.Flags().Bool(overrideName, false, usage)
+		case "int":
+			cmd.Flags().Int(overrideName, 0, usage)
+		default:
+			cmd.Flags().String(overrideName, "", usage)
+		}
+		cmd.Flags().MarkHidden(overrideName)
+	}
+}
 
-	// Setup HTTP server
+// checkPlatformOverrides rejects overrides for platforms that aren't being run, since
+// they'd otherwise be silently ignored
+func checkPlatformOverrides(cmd *cobra.Command, platforms []string) error {
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		platform, _, ok := strings.Cut(flag.Name, ".")
+		if ok && flag.Changed && err == nil && !slices.Contains(platforms, platform) {
+			err = fmt.Errorf("--%s was given but %s isn't in --platforms", flag.Name, platform)
+		}
+	})
+	return err
+}
+
+// platformPruneOptions builds the prune options for one platform from a command's flags,
+// with any --<platform>.<flag> overrides taking precedence over the shared flag. Only the
+// server has overrides; for other commands every platform gets the shared flags.
+func platformPruneOptions(cmd *cobra.Command, platform string) (internal.PruneOptions, error) {
+	return pruneOptionsFromFlags(platformFlags{cmd: cmd, platform: platform}, platform)
+}
+
+// pruneOptionsFromFlags builds the prune options for one platform from flags, which may
+// prefer overrides under a prefix other than the platform's, as compare's --against does
+func pruneOptionsFromFlags(flags platformFlags, platform string) (internal.PruneOptions, error) {
+	maxLikes, maxReposts, maxReplies, err := flags.engagementThresholds()
+	if err != nil {
+		return internal.PruneOptions{}, err
+	}
+
+	options := internal.PruneOptions{
+		PreserveSelfLike:   flags.getBool("preserve-selflike"),
+		PreservePinned:     flags.getBool("preserve-pinned"),
+		PreserveWithReplies: flags.getBool("preserve-with-replies"),
+		PreserveHashtags:   internal.ParseHashtags(flags.getString("preserve-hashtags")),
+		WithHashtags:       internal.ParseHashtags(flags.getString("with-hashtags")),
+		PreserveLanguages:  internal.ParseLanguages(flags.getString("preserve-language")),
+		WithLanguages:      internal.ParseLanguages(flags.getString("language")),
+		MediaOnly:          flags.getBool("media-only"),
+		SkipMedia:          flags.getBool("skip-media"),
+		WithLinks:          flags.getBool("with-links"),
+		LinksTo:            internal.ParseDomains(flags.getString("links-to")),
+		RepliesOnly:        flags.getBool("replies-only"),
+		SkipReplies:        flags.getBool("skip-replies"),
+		OnlySensitive:      flags.getBool("only-sensitive"),
+		PreserveCW:         flags.getBool("preserve-cw"),
+		PreserveDirect:     flags.getBool("preserve-direct"),
+		UnlikePosts:        flags.getBool("unlike-posts"),
+		LikedPostAge:       flags.getBool("liked-post-age"),
+		Redact:             flags.getBool("redact"),
+		UnshareReposts:     flags.getBool("unshare-reposts"),
+		UnshareSelfReposts: flags.getBool("unshare-self-reposts"),
+		SkipSelfReposts:    flags.getBool("skip-self-reposts"),
+		OnlySelfReposts:    flags.getBool("only-self-reposts"),
+		DeleteWholeThreads: flags.getBool("delete-whole-threads"),
+		BatchWrites:        flags.getBool("batch-writes"),
+		MaxLikes:           maxLikes,
+		MaxReposts:         maxReposts,
+		MaxReplies:         maxReplies,
+	}
+
+	options.Visibilities, err = internal.ParseVisibilities(flags.getString("visibility"))
+	if err != nil {
+		return internal.PruneOptions{}, fmt.Errorf("error parsing %s: %w", flags.name("visibility"), err)
+	}
+
+	if excludeFile := flags.getString("exclude-file"); excludeFile != "" {
+		options.ExcludePosts, err = internal.ReadPostRefs(excludeFile)
+		if err != nil {
+			return internal.PruneOptions{}, fmt.Errorf("error reading %s: %w", flags.name("exclude-file"), err)
+		}
+	}
+
+	// An override can combine with the other shared flag, which cobra can't catch for us
+	if options.MediaOnly && options.SkipMedia {
+		return internal.PruneOptions{}, fmt.Errorf("--%s and --%s can't both apply", flags.name("media-only"), flags.name("skip-media"))
+	}
+	if options.RepliesOnly && options.SkipReplies {
+		return internal.PruneOptions{}, fmt.Errorf("--%s and --%s can't both apply", flags.name("replies-only"), flags.name("skip-replies"))
+	}
+	if options.SkipSelfReposts && options.OnlySelfReposts {
+		return internal.PruneOptions{}, fmt.Errorf("--%s and --%s can't both apply", flags.name("skip-self-reposts"), flags.name("only-self-reposts"))
+	}
+	if options.SkipSelfReposts && options.UnshareSelfReposts {
+		return internal.PruneOptions{}, fmt.Errorf("--%s and --%s can't both apply", flags.name("skip-self-reposts"), flags.name("unshare-self-reposts"))
+	}
+
+	// Use platform-appropriate rate limit defaults unless a delay was given
+	if rateLimitDelayStr := flags.getString("rate-limit-delay"); rateLimitDelayStr != "" {
+		delay, err := timespec.ParseDuration(rateLimitDelayStr)
+		if err != nil {
+			return internal.PruneOptions{}, fmt.Errorf("error parsing %s: %w", flags.name("rate-limit-delay"), err)
+		}
+		options.RateLimitDelay = delay
+	} else {
+		options.RateLimitDelay = defaultRateLimitDelay(platform)
+	}
+
+	if maxAgeStr := flags.getString("max-post-age"); maxAgeStr != "" {
+		maxAge, err := timespec.ParseDuration(maxAgeStr)
+		if err != nil {
+			return internal.PruneOptions{}, fmt.Errorf("error parsing %s: %w", flags.name("max-post-age"), err)
+		}
+		options.MaxAge = &maxAge
+	}
+
+	if beforeDateStr := flags.getString("before-date"); beforeDateStr != "" {
+		beforeDate, err := timespec.ParseDate(beforeDateStr)
+		if err != nil {
+			return internal.PruneOptions{}, fmt.Errorf("error parsing %s: %w", flags.name("before-date"), err)
+		}
+		options.BeforeDate = &beforeDate
+	}
+
+	if afterDateStr := flags.getString("after-date"); afterDateStr != "" {
+		afterDate, err := timespec.ParseDate(afterDateStr)
+		if err != nil {
+			return internal.PruneOptions{}, fmt.Errorf("error parsing %s: %w", flags.name("after-date"), err)
+		}
+		options.AfterDate = &afterDate
+	}
+	if err := options.CheckDateRange(); err != nil {
+		return internal.PruneOptions{}, err
+	}
+
+	if options.MaxAge == nil && options.BeforeDate == nil {
+		return internal.PruneOptions{}, fmt.Errorf("must specify either --max-post-age or --before-date (or --%s.max-post-age / --%s.before-date)", flags.platform, flags.platform)
+	}
+
+	return options, nil
+}
+
+// serverConfig is how the server's HTTP side is set up
+type serverConfig struct {
+	port          int
+	metricsPort   int        // Serves /metrics on its own listener, zero to serve it on port
+	readyFailures int        // Failed runs in a row before /readyz fails, zero to ignore them
+	tlsCert       string     // Certificate and key files to serve HTTPS with, empty for HTTP
+	tlsKey        string
+	auth          serverAuth // Guards every endpoint but /healthz and /metrics
+	metricsAuth   serverAuth // Guards /metrics
+}
+
+// newServerHandler routes the server's endpoints. Liveness probes and metrics scrapes
+// come from orchestrators and Prometheus rather than people, so they're kept out of
+// reach of the credentials that guard the status page and API.
+func newServerHandler(platformRunners []PlatformRunner, config serverConfig) http.Handler {
 	mux := http.NewServeMux()
 	
 	// Root endpoint - health check with service info
@@ -447,6 +939,10 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 
 		platformStatuses := serverState.GetAllPlatformStatuses()
 		versionInfo := serverState.Version
+		metricsLocation := `Multi-platform metrics are available at <a href="/metrics">/metrics</a>`
+		if config.metricsPort != 0 {
+			metricsLocation = fmt.Sprintf("Multi-platform metrics are available at /metrics on port %d", config.metricsPort)
+		}
 		
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		fmt.Fprintf(w, `<!DOCTYPE html>
@@ -487,11 +983,11 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
         <p><strong>Commit:</strong> %s</p>
         <p><strong>Build Time:</strong> %s</p>
         <p><strong>Uptime:</strong> %v</p>
-        <p><strong>Prune Interval:</strong> %v</p>
         <p><strong>Dry Run Mode:</strong> %t</p>
+        <p><strong>Operator:</strong> %s</p>
     </div>
     
-    <h2>Platform Status</h2>`, len(platformStatuses), versionInfo["version"], versionInfo["commit"], versionInfo["build_time"], time.Since(serverState.StartTime).Round(time.Second), serverState.PruneInterval, serverState.DryRun)
+    <h2>Platform Status</h2>`, len(platformStatuses), versionInfo["version"], versionInfo["commit"], versionInfo["build_time"], clock.Now().Sub(serverState.StartTime).Round(time.Second), serverState.DryRun, html.EscapeString(serverState.Operator))
 		
 		// Platform status sections
 		for _, platform := range platformStatuses {
@@ -517,24 +1013,47 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
             <tr><th>Total Runs</th><td>%d</td></tr>
             <tr><th>Successful Runs</th><td>%d</td></tr>
             <tr><th>Last Prune</th><td>%s</td></tr>
+            <tr><th>Schedule</th><td>%s</td></tr>
             <tr><th>Next Prune</th><td>%s</td></tr>
+            <tr><th>Circuit Breaker</th><td>%s</td></tr>
         </table>
         <div class="metrics-grid">
             <div class="metric"><strong>Deleted</strong><br>%d</div>
             <div class="metric"><strong>Unliked</strong><br>%d</div>
             <div class="metric"><strong>Unshared</strong><br>%d</div>
+            <div class="metric"><strong>Redacted</strong><br>%d</div>
             <div class="metric"><strong>Preserved</strong><br>%d</div>
         </div>`, 
 				platform.Name, statusClass, statusText, platform.Username, 
 				platform.TotalRuns, platform.SuccessfulRuns,
-				formatTime(platform.LastPruneTime), formatTime(platform.NextPruneTime),
+				formatTime(platform.LastPruneTime), html.EscapeString(platform.Schedule), formatTime(platform.NextPruneTime),
+				formatCircuitState(platform),
 				platform.PostsProcessed["deleted"], platform.PostsProcessed["unliked"],
-				platform.PostsProcessed["unshared"], platform.PostsProcessed["preserved"])
+				platform.PostsProcessed["unshared"], platform.PostsProcessed["redacted"], platform.PostsProcessed["preserved"])
 			
 			if platform.LastPruneError != "" {
 				fmt.Fprintf(w, `<div class="status-error" style="margin-top: 10px; padding: 8px;"><strong>Last Error:</strong> %s</div>`, platform.LastPruneError)
 			}
 			
+			if len(platform.LastPruneWarnings) > 0 {
+				fmt.Fprintf(w, `<div class="status-pending" style="margin-top: 10px; padding: 8px;"><strong>Warnings (%d):</strong><ul style="margin: 4px 0;">`, len(platform.LastPruneWarnings))
+				for _, warning := range platform.LastPruneWarnings {
+					fmt.Fprintf(w, `<li>%s</li>`, html.EscapeString(warning))
+				}
+				fmt.Fprintf(w, `</ul></div>`)
+			}
+			
+			if serverState.DryRun {
+				page := 1
+				if r.URL.Query().Get("platform") == platform.Name {
+					page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+				}
+				if err := renderDryRunMatches(w, platform.Name, platform.DryRunMatches, page); err != nil {
+					log.Debug().Err(err).Msg("Failed to write the status page")
+					return
+				}
+			}
+			
 			fmt.Fprintf(w, `
     </div>`)
 		}
@@ -543,22 +1062,28 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
     <h3>Endpoints</h3>
     <ul>
         <li><code>GET /</code> - This multi-platform status page (auto-refreshes every 30s)</li>
+        <li><code>GET /?platform=NAME&amp;page=N</code> - Page through a platform's latest dry-run matches (dry-run mode only)</li>
         <li><code>GET /metrics</code> - Prometheus metrics</li>
+        <li><code>GET /healthz</code> - Liveness probe, OK while the server is up</li>
+        <li><code>GET /readyz</code> - Readiness probe, failing when a platform's login is rejected or its recent runs all failed</li>
         <li><code>GET /api/status</code> - JSON status endpoint</li>
+        <li><code>GET /api/history?platform=NAME&amp;limit=N</code> - Recent prune runs as JSON, for dashboards</li>
+        <li><code>GET /api/posts/{platform}?limit=N&amp;cursor=C</code> - A page of the account's posts as JSON</li>
     </ul>
     <h3>Prometheus Metrics</h3>
-    <p>Multi-platform metrics are available at <a href="/metrics">/metrics</a></p>
+    <p>%s</p>
     <p>Key metrics include:</p>
     <ul>
-        <li><code>cringesweeper_prune_runs_total{platform, status}</code> - Total prune runs per platform</li>
-        <li><code>cringesweeper_posts_processed_total{platform, action}</code> - Posts processed by platform and action</li>
+        <li><code>cringesweeper_prune_runs_total{platform, status, operator}</code> - Total prune runs per platform</li>
+        <li><code>cringesweeper_posts_processed_total{platform, action, operator}</code> - Posts processed by platform and action</li>
         <li><code>cringesweeper_prune_run_duration_seconds{platform}</code> - Prune run duration per platform</li>
         <li><code>cringesweeper_last_prune_timestamp{platform}</code> - Last prune timestamp per platform</li>
         <li><code>cringesweeper_platform_active{platform}</code> - Platform active status</li>
         <li><code>cringesweeper_platform_pruning{platform}</code> - Platform currently pruning status</li>
+        <li><code>cringesweeper_circuit_breaker_state{platform}</code> - Circuit breaker state (0 closed, 1 half-open, 2 open)</li>
     </ul>
 </body>
-</html>`)
+</html>`, metricsLocation)
 
 		log.Debug().
 			Str("method", r.Method).
@@ -600,22 +1125,75 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 			Msg("JSON API request served")
 	})
 
-	// Metrics endpoint
-	mux.Handle("/metrics", promhttp.Handler())
+	// Readiness probe for orchestrators, kept apart from the status page
+	mux.Handle("GET /readyz", readyzHandler(config.readyFailures))
 
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: mux,
+	// Recent prune runs as JSON, for dashboards without Prometheus
+	mux.Handle("GET /api/history", apiHistoryHandler(serverHistory))
+
+	// Posts from each platform being served, in the generic post model. They're the
+	// account's own content, so they're only served to requests with credentials
+	if config.auth.enabled() {
+		mux.Handle("GET /api/posts/{platform}", apiPostsHandler(platformRunners))
+	} else {
+		mux.Handle("GET /api/posts/{platform}", apiPostsDisabledHandler())
 	}
 
-	// Start HTTP server in goroutine
-	serverErrCh := make(chan error, 1)
-	go func() {
-		log.Info().Int("port", port).Msg("Starting HTTP server")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			serverErrCh <- err
-		}
-	}()
+	// Probes and scrapes skip the credentials the status page and API need
+	handler := http.NewServeMux()
+	handler.Handle("GET /healthz", healthzHandler())
+	if config.metricsPort == 0 {
+		handler.Handle("/metrics", config.metricsAuth.require(promhttp.Handler()))
+	}
+	handler.Handle("/", config.auth.require(mux))
+	return handler
+}
+
+// newMetricsHandler serves /metrics alone, for the --metrics-port listener. /healthz is
+// served too, so the scrape target can be probed on its own.
+func newMetricsHandler(config serverConfig) http.Handler {
+	handler := http.NewServeMux()
+	handler.Handle("GET /healthz", healthzHandler())
+	handler.Handle("/metrics", config.metricsAuth.require(promhttp.Handler()))
+	return handler
+}
+
+func startMultiPlatformServer(platformRunners []PlatformRunner, config serverConfig) {
+	// Create context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize version metrics
+	version := internal.GetFullVersionInfo()
+	versionInfo.WithLabelValues(version["version"], version["commit"], version["build_time"]).Set(1)
+
+	servers := []*http.Server{{
+		Addr:    fmt.Sprintf(":%d", config.port),
+		Handler: newServerHandler(platformRunners, config),
+	}}
+	if config.metricsPort != 0 {
+		servers = append(servers, &http.Server{
+			Addr:    fmt.Sprintf(":%d", config.metricsPort),
+			Handler: newMetricsHandler(config),
+		})
+	}
+
+	// Start HTTP servers in goroutines
+	serverErrCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			log.Info().Str("addr", server.Addr).Bool("tls", config.tlsCert != "").Msg("Starting HTTP server")
+			var err error
+			if config.tlsCert != "" {
+				err = server.ListenAndServeTLS(config.tlsCert, config.tlsKey)
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				serverErrCh <- err
+			}
+		}()
+	}
 
 	// Start platform monitoring goroutines
 	var wg sync.WaitGroup
@@ -631,7 +1209,7 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 		wg.Add(1)
 		go func(runner PlatformRunner) {
 			defer wg.Done()
-			startPlatformMonitoring(platformCtx, runner, pruneInterval)
+			startPlatformMonitoring(platformCtx, runner)
 		}(runner)
 		
 		log.Info().
@@ -655,6 +1233,16 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 	case sig := <-sigCh:
 		log.Info().Str("signal", sig.String()).Msg("Received shutdown signal")
 		
+		// Let runs in progress finish the post in hand and record what they did, then
+		// stop whatever is still going when the time is up
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+		
+		log.Info().Msg("Draining prune runs in progress...")
+		if !serverRuns.drainAndWait(shutdownCtx) {
+			log.Warn().Dur("timeout", shutdownTimeout).Msg("Prune runs didn't drain in time, cancelling them")
+		}
+		
 		// Cancel all platform contexts
 		for platform, platformCancel := range platformCancels {
 			log.Info().Str("platform", platform).Msg("Stopping platform monitoring")
@@ -662,11 +1250,11 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 		}
 		
 		// Graceful shutdown
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer shutdownCancel()
 		
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			log.Error().Err(err).Msg("Error during server shutdown")
+		for _, server := range servers {
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				log.Error().Err(err).Str("addr", server.Addr).Msg("Error during server shutdown")
+			}
 		}
 		
 		// Wait for platform goroutines to finish
@@ -681,6 +1269,213 @@ func startMultiPlatformServer(platformRunners []PlatformRunner, pruneInterval ti
 	wg.Wait()
 }
 
+// apiPostsPage is the response body of GET /api/posts/{platform}
+type apiPostsPage struct {
+	Platform   string          `json:"platform"`
+	Username   string          `json:"username"`
+	Posts      []internal.Post `json:"posts"`
+	NextCursor string          `json:"next_cursor,omitempty"` // Pass as ?cursor= for the next page, empty at the end
+}
+
+// apiPostsHandler serves a page of posts from one of the platforms the server runs, so
+// other tools can build on the normalized post model. limit defaults to 20 and can be
+// at most the platform's largest page; cursor comes from the previous page's next_cursor.
+func apiPostsHandler(runners []PlatformRunner) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := http.StatusOK
+		defer func() {
+			httpRequestsTotal.WithLabelValues(r.Method, "/api/posts/{platform}", strconv.Itoa(status)).Inc()
+		}()
+
+		writeError := func(code int, format string, args ...interface{}) {
+			status = code
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		platform := r.PathValue("platform")
+		i := slices.IndexFunc(runners, func(runner PlatformRunner) bool { return runner.Config.name == platform })
+		if i < 0 {
+			writeError(http.StatusNotFound, "platform %q isn't being served", platform)
+			return
+		}
+		config := runners[i].Config
+
+		limit := 20
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed < 1 || parsed > internal.MaxPageSize(platform) {
+				writeError(http.StatusBadRequest, "limit must be a number from 1 to %d", internal.MaxPageSize(platform))
+				return
+			}
+			limit = parsed
+		}
+
+		// Requests made for the API show up in the platform's API metrics like any others
+		ctx := internal.WithAPIObserver(r.Context(), apiMetricsObserver(platform))
+		posts, nextCursor, err := config.client.FetchUserPostsPaginated(ctx, config.username, limit, r.URL.Query().Get("cursor"))
+		if err != nil {
+			log.Error().Err(err).Str("platform", platform).Msg("Failed to fetch posts for API request")
+			writeError(http.StatusBadGateway, "fetching posts from %s: %v", platform, err)
+			return
+		}
+		if posts == nil {
+			posts = []internal.Post{}
+		}
+
+		json.NewEncoder(w).Encode(apiPostsPage{
+			Platform:   platform,
+			Username:   config.username,
+			Posts:      posts,
+			NextCursor: nextCursor,
+		})
+
+		log.Debug().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Dur("duration", time.Since(start)).
+			Msg("Posts API request served")
+	})
+}
+
+// apiPostsDisabledHandler stands in for GET /api/posts/{platform} when the server has
+// no credentials set, so the endpoint can't hand an account's posts to anyone who asks
+func apiPostsDisabledHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpRequestsTotal.WithLabelValues(r.Method, "/api/posts/{platform}", strconv.Itoa(http.StatusForbidden)).Inc()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "/api/posts is only served with --auth-token or --basic-auth set"})
+	})
+}
+
+// healthzHandler answers liveness probes. It only shows the server is up and serving,
+// so an orchestrator restarts it if it hangs, not when a platform is having trouble.
+func healthzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, "200").Inc()
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// readyzHandler answers readiness probes, failing with 503 and a line per platform
+// while any platform's credentials have been rejected or, with failureThreshold above
+// zero, its last failureThreshold runs have all failed
+func readyzHandler(failureThreshold int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problems := readinessProblems(serverState.GetAllPlatformStatuses(), failureThreshold)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if len(problems) == 0 {
+			httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, "200").Inc()
+			fmt.Fprintln(w, "ready")
+			return
+		}
+		httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, "503").Inc()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, problem := range problems {
+			fmt.Fprintln(w, problem)
+		}
+	})
+}
+
+// readinessProblems lists why each platform isn't ready, in platform order
+func readinessProblems(statuses map[string]*PlatformStatus, failureThreshold int) []string {
+	var problems []string
+	for _, name := range slices.Sorted(maps.Keys(statuses)) {
+		status := statuses[name]
+		switch {
+		case status.CredentialsRejected:
+			problems = append(problems, fmt.Sprintf("%s: credentials were rejected: %s", name, status.LastPruneError))
+		case failureThreshold > 0 && status.ConsecutiveFailures >= failureThreshold:
+			problems = append(problems, fmt.Sprintf("%s: last %d prune runs failed: %s", name, status.ConsecutiveFailures, status.LastPruneError))
+		}
+	}
+	return problems
+}
+
+// collectDryRunMatches flattens the posts a dry-run prune would act on into a single list
+func collectDryRunMatches(result *internal.PruneResult) []DryRunMatch {
+	matches := make([]DryRunMatch, 0, len(result.PostsToDelete)+len(result.PostsToRedact)+len(result.PostsToUnlike)+len(result.PostsToUnshare))
+	for _, post := range result.PostsToDelete {
+		matches = append(matches, DryRunMatch{Action: "delete", Post: post})
+	}
+	for _, post := range result.PostsToRedact {
+		matches = append(matches, DryRunMatch{Action: "redact", Post: post})
+	}
+	for _, post := range result.PostsToUnlike {
+		matches = append(matches, DryRunMatch{Action: "unlike", Post: post})
+	}
+	for _, post := range result.PostsToUnshare {
+		matches = append(matches, DryRunMatch{Action: "unshare", Post: post})
+	}
+	return matches
+}
+
+// paginate clamps page to a valid 1-based page number and returns the slice bounds for it
+func paginate(total, page, pageSize int) (start, end, clampedPage, pages int) {
+	pages = (total + pageSize - 1) / pageSize
+	if pages < 1 {
+		pages = 1
+	}
+	if page < 1 {
+		page = 1
+	}
+	if page > pages {
+		page = pages
+	}
+	start = (page - 1) * pageSize
+	end = start + pageSize
+	if end > total {
+		end = total
+	}
+	return start, end, page, pages
+}
+
+// renderDryRunMatches writes one page of the latest dry-run matches for a platform
+func renderDryRunMatches(w io.Writer, platformName string, matches []DryRunMatch, page int) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, `
+        <h4>Dry Run Matches (%d)</h4>`, len(matches))
+	if len(matches) == 0 {
+		fmt.Fprintf(w, `
+        <p>No posts matched in the latest dry run.</p>`)
+		return out.err
+	}
+
+	start, end, page, pages := paginate(len(matches), page, dryRunPageSize)
+	fmt.Fprintf(w, `
+        <table>
+            <tr><th>Action</th><th>Date</th><th>Content</th><th>URL</th></tr>`)
+	for _, match := range matches[start:end] {
+		link := ""
+		// Post URLs come from the platform, so only web links become clickable
+		if u, err := url.Parse(match.Post.URL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			link = fmt.Sprintf(`<a href="%s">view</a>`, html.EscapeString(match.Post.URL))
+		}
+		fmt.Fprintf(w, `
+            <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			match.Action, match.Post.CreatedAt.Format("2006-01-02"),
+			html.EscapeString(truncateContent(match.Post.Content, 80)), link)
+	}
+	fmt.Fprintf(w, `
+        </table>
+        <p>Showing %d-%d of %d (page %d of %d)`, start+1, end, len(matches), page, pages)
+	if page > 1 {
+		fmt.Fprintf(w, ` <a href="/?platform=%s&page=%d">&laquo; Previous</a>`, url.QueryEscape(platformName), page-1)
+	}
+	if page < pages {
+		fmt.Fprintf(w, ` <a href="/?platform=%s&page=%d">Next &raquo;</a>`, url.QueryEscape(platformName), page+1)
+	}
+	fmt.Fprintf(w, `</p>`)
+	return out.err
+}
+
 // Helper function to format time for display
 func formatTime(t time.Time) string {
 	if t.IsZero() {
@@ -689,30 +1484,52 @@ func formatTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05 UTC")
 }
 
+// formatCircuitState describes a platform's circuit breaker for the status page
+func formatCircuitState(status *PlatformStatus) string {
+	if status.CircuitState == internal.BreakerOpen.String() {
+		return fmt.Sprintf("open until %s", formatTime(status.CircuitOpenUntil))
+	}
+	return status.CircuitState
+}
+
 // startPlatformMonitoring runs platform-specific monitoring in a dedicated goroutine
-func startPlatformMonitoring(ctx context.Context, runner PlatformRunner, pruneInterval time.Duration) {
+func startPlatformMonitoring(ctx context.Context, runner PlatformRunner) {
 	platform := runner.Config.name
 	username := runner.Config.username
 	client := runner.Config.client
-	options := runner.Options
-	
-	log.Info().Str("platform", platform).Msg("Platform monitoring started")
+	breaker := runner.Breaker
+	schedule := runner.Schedule
 	
-	// Create platform-specific ticker
-	ticker := time.NewTicker(pruneInterval)
-	defer ticker.Stop()
+	log.Info().Str("platform", platform).Stringer("schedule", schedule).Msg("Platform monitoring started")
 	
 	// Platform-specific mutex to prevent concurrent pruning
 	var pruningMutex sync.Mutex
 	
-	// Run initial prune
-	go func() {
-		pruningMutex.Lock()
-		defer pruningMutex.Unlock()
-		runPruneWithMetrics(client, username, options, platform)
-	}()
+	// Interval schedules run straight away; cron schedules wait for their first slot
+	if schedule.RunOnStart() {
+		go func() {
+			pruningMutex.Lock()
+			defer pruningMutex.Unlock()
+			runCtx, options := runner.startRun(ctx)
+			runPruneWithMetrics(runCtx, client, username, options, platform, breaker)
+		}()
+	}
 	
 	for {
+		next := schedule.Next(clock.Now())
+		if next.IsZero() {
+			log.Error().Str("platform", platform).Stringer("schedule", schedule).Msg("Schedule has no upcoming run times")
+			<-ctx.Done()
+			platformActiveGauge.WithLabelValues(platform).Set(0)
+			return
+		}
+		
+		// Update next prune time
+		if status, exists := serverState.GetPlatformStatus(platform); exists {
+			status.NextPruneTime = next
+			serverState.UpdatePlatformStatus(platform, status)
+		}
+		
 		select {
 		case <-ctx.Done():
 			log.Info().Str("platform", platform).Msg("Platform monitoring stopped")
@@ -720,32 +1537,48 @@ func startPlatformMonitoring(ctx context.Context, runner PlatformRunner, pruneIn
 			platformActiveGauge.WithLabelValues(platform).Set(0)
 			return
 			
-		case <-ticker.C:
-			// Update next prune time
-			if status, exists := serverState.GetPlatformStatus(platform); exists {
-				status.NextPruneTime = time.Now().Add(pruneInterval)
-				serverState.UpdatePlatformStatus(platform, status)
-			}
-			
-			// Run prune in background to not block ticker
+		case <-clock.After(next.Sub(clock.Now())):
+			// Run prune in background so a long run doesn't delay the next slot
 			go func() {
 				if !pruningMutex.TryLock() {
 					log.Warn().Str("platform", platform).Msg("Skipping prune run - previous run still in progress")
 					return
 				}
 				defer pruningMutex.Unlock()
-				runPruneWithMetrics(client, username, options, platform)
+				runCtx, options := runner.startRun(ctx)
+				runPruneWithMetrics(runCtx, client, username, options, platform, breaker)
 			}()
 		}
 	}
 }
 
-func runPruneWithMetrics(client internal.SocialClient, username string, options internal.PruneOptions, platform string) {
+func runPruneWithMetrics(ctx context.Context, client internal.SocialClient, username string, options internal.PruneOptions, platform string, breaker *internal.CircuitBreaker) {
+	if !serverRuns.begin() {
+		log.Info().Str("platform", platform).Msg("Skipping prune run - server is shutting down")
+		return
+	}
+	defer serverRuns.end()
+
+	if !breaker.Allow() {
+		pruneRunsTotal.WithLabelValues(platform, "skipped", serverState.Operator).Inc()
+		log.Warn().
+			Str("platform", platform).
+			Int("consecutive_failures", breaker.ConsecutiveFailures()).
+			Time("open_until", breaker.OpenUntil()).
+			Msg("Skipping prune run - circuit breaker open")
+		updateCircuitStatus(platform, breaker)
+		return
+	}
+
 	start := time.Now()
 	status := "success"
 	errorMsg := ""
+	var runErr error
+	var warnings []string
+	var dryRunMatches []DryRunMatch
+	var result *internal.PruneResult
 
-	log.Info().Str("platform", platform).Msg("Starting scheduled prune run")
+	log.Info().Str("platform", platform).Str("operator", serverState.Operator).Msg("Starting scheduled prune run")
 	
 	// Update platform status to indicate pruning is in progress
 	if platformStatus, exists := serverState.GetPlatformStatus(platform); exists {
@@ -758,22 +1591,40 @@ func runPruneWithMetrics(client internal.SocialClient, username string, options
 	defer func() {
 		duration := time.Since(start)
 		pruneRunDuration.WithLabelValues(platform).Observe(duration.Seconds())
-		pruneRunsTotal.WithLabelValues(platform, status).Inc()
-		lastPruneTime.WithLabelValues(platform).Set(float64(time.Now().Unix()))
+		pruneRunsTotal.WithLabelValues(platform, status, serverState.Operator).Inc()
+		lastPruneTime.WithLabelValues(platform).Set(float64(clock.Now().Unix()))
+		apiRequestsTotal.WithLabelValues(platform).Add(float64(internal.RequestBudgetFromContext(ctx).Used()))
+		
+		// Shutdown cancellations aren't the platform's fault, so don't count them
+		if status == "success" {
+			breaker.RecordSuccess()
+		} else if ctx.Err() == nil {
+			breaker.RecordFailure()
+		}
 		
 		// Update platform status
 		if platformStatus, exists := serverState.GetPlatformStatus(platform); exists {
 			platformStatus.IsPruning = false
-			platformStatus.LastPruneTime = time.Now()
+			platformStatus.LastPruneTime = clock.Now()
 			platformStatus.LastPruneStatus = status
 			platformStatus.LastPruneError = errorMsg
+			platformStatus.LastPruneWarnings = warnings
+			if options.DryRun && status == "success" {
+				platformStatus.DryRunMatches = dryRunMatches
+			}
 			if status == "success" {
 				platformStatus.SuccessfulRuns++
+				platformStatus.ConsecutiveFailures = 0
+				platformStatus.CredentialsRejected = false
+			} else if ctx.Err() == nil {
+				platformStatus.ConsecutiveFailures++
+				platformStatus.CredentialsRejected = isCredentialError(runErr)
 			}
-			platformStatus.NextPruneTime = time.Now().Add(serverState.PruneInterval)
 			serverState.UpdatePlatformStatus(platform, platformStatus)
 		}
 		platformPruningGauge.WithLabelValues(platform).Set(0)
+		updateCircuitStatus(platform, breaker)
+		serverHistory.add(newRunRecord(platform, status, errorMsg, duration, options.DryRun, result))
 		
 		log.Info().
 			Str("platform", platform).
@@ -783,19 +1634,30 @@ func runPruneWithMetrics(client internal.SocialClient, username string, options
 	}()
 
 	// Use continuous pruning to process entire timeline
-	result, err := runContinuousPruneForServer(client, username, options)
+	result, err := runContinuousPruneForServer(ctx, client, username, options)
 	if err != nil {
+		runErr = err
 		status = "error"
 		errorMsg = err.Error()
 		log.Error().Err(err).Str("platform", platform).Msg("Prune run failed")
+	}
+	if result == nil {
 		return
 	}
+	// A run that failed or was cut short part way still counts what it got done
+	warnings = result.Warnings
+	if options.DryRun {
+		dryRunMatches = collectDryRunMatches(result)
+	}
 
 	// Update metrics
-	postsProcessedTotal.WithLabelValues(platform, "deleted").Add(float64(result.DeletedCount))
-	postsProcessedTotal.WithLabelValues(platform, "unliked").Add(float64(result.UnlikedCount))
-	postsProcessedTotal.WithLabelValues(platform, "unshared").Add(float64(result.UnsharedCount))
-	postsProcessedTotal.WithLabelValues(platform, "preserved").Add(float64(result.PreservedCount))
+	postsProcessedTotal.WithLabelValues(platform, "deleted", serverState.Operator).Add(float64(result.DeletedCount))
+	postsProcessedTotal.WithLabelValues(platform, "unliked", serverState.Operator).Add(float64(result.UnlikedCount))
+	postsProcessedTotal.WithLabelValues(platform, "unshared", serverState.Operator).Add(float64(result.UnsharedCount))
+	postsProcessedTotal.WithLabelValues(platform, "redacted", serverState.Operator).Add(float64(result.RedactedCount))
+	postsProcessedTotal.WithLabelValues(platform, "preserved", serverState.Operator).Add(float64(result.PreservedCount))
+	fillAccountTotal(ctx, client, username, result.Account)
+	updateAccountMetrics(platform, result.Account)
 	
 	// Update platform status with post counts
 	if platformStatus, exists := serverState.GetPlatformStatus(platform); exists {
@@ -805,7 +1667,11 @@ func runPruneWithMetrics(client internal.SocialClient, username string, options
 		platformStatus.PostsProcessed["deleted"] += int64(result.DeletedCount)
 		platformStatus.PostsProcessed["unliked"] += int64(result.UnlikedCount)
 		platformStatus.PostsProcessed["unshared"] += int64(result.UnsharedCount)
+		platformStatus.PostsProcessed["redacted"] += int64(result.RedactedCount)
 		platformStatus.PostsProcessed["preserved"] += int64(result.PreservedCount)
+		if result.Account != nil {
+			platformStatus.Account = result.Account
+		}
 		serverState.UpdatePlatformStatus(platform, platformStatus)
 	}
 
@@ -814,23 +1680,77 @@ func runPruneWithMetrics(client internal.SocialClient, username string, options
 		Int("deleted", result.DeletedCount).
 		Int("unliked", result.UnlikedCount).
 		Int("unshared", result.UnsharedCount).
+		Int("redacted", result.RedactedCount).
 		Int("preserved", result.PreservedCount).
 		Int("errors", result.ErrorsCount).
+		Int("warnings", len(result.Warnings)).
 		Msg("Prune run metrics")
 }
 
+// updateAccountMetrics publishes what a run found left on the account. Runs that failed
+// before getting through the posts don't know, so the previous run's figures stand.
+func updateAccountMetrics(platform string, stats *internal.AccountStats) {
+	if stats == nil {
+		return
+	}
+	if stats.Total < 0 {
+		accountPostsTotal.DeleteLabelValues(platform)
+	} else {
+		accountPostsTotal.WithLabelValues(platform).Set(float64(stats.Total))
+	}
+	// Types the account has run out of shouldn't keep their last count
+	accountPostsSeenRemaining.DeletePartialMatch(prometheus.Labels{"platform": platform})
+	for postType, count := range stats.Remaining {
+		accountPostsSeenRemaining.WithLabelValues(platform, string(postType)).Set(float64(count))
+	}
+	accountPostsMatching.WithLabelValues(platform).Set(float64(stats.Matching))
+	if stats.OldestPost.IsZero() {
+		accountOldestSeenPostAge.DeleteLabelValues(platform)
+	} else {
+		accountOldestSeenPostAge.WithLabelValues(platform).Set(clock.Now().Sub(stats.OldestPost).Seconds())
+	}
+}
+
+// fillAccountTotal asks the platform how many posts the account has in all, as a run's
+// own figures only cover the posts it fetched. Stats stay at -1 if it can't say.
+func fillAccountTotal(ctx context.Context, client internal.SocialClient, username string, stats *internal.AccountStats) {
+	counter, ok := client.(internal.PostCounter)
+	if stats == nil || !ok || ctx.Err() != nil {
+		return
+	}
+	count, err := counter.GetPostCount(ctx, username)
+	if err != nil {
+		log.Warn().Err(err).Str("platform", client.GetPlatformName()).Msg("Failed to fetch the account's post count")
+		return
+	}
+	stats.Total = count
+}
+
+// updateCircuitStatus publishes a platform's circuit breaker state to the status page and metrics
+func updateCircuitStatus(platform string, breaker *internal.CircuitBreaker) {
+	state := breaker.State()
+	circuitBreakerState.WithLabelValues(platform).Set(float64(state))
+	if platformStatus, exists := serverState.GetPlatformStatus(platform); exists {
+		platformStatus.CircuitState = state.String()
+		platformStatus.CircuitOpenUntil = breaker.OpenUntil()
+		serverState.UpdatePlatformStatus(platform, platformStatus)
+	}
+}
+
 // runContinuousPruneForServer runs continuous pruning with accurate success counting (server version of performContinuousPruningWithResult)
-func runContinuousPruneForServer(client internal.SocialClient, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+func runContinuousPruneForServer(ctx context.Context, client internal.SocialClient, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
 	// For server mode, respect the user's dry-run setting
 	// and only count posts that were successfully processed
 	serverOptions := options
+	serverOptions.ContinueUntilEnd = true
 	
 	log.Debug().Str("platform", client.GetPlatformName()).Msg("Starting prune operation for server")
 	
 	// Use the platform's built-in PrunePosts method which correctly tracks successful operations
-	result, err := client.PrunePosts(username, serverOptions)
+	result, err := client.PrunePosts(ctx, username, serverOptions)
 	if err != nil {
-		return nil, fmt.Errorf("prune operation failed: %w", err)
+		// Keep whatever part of the run was done, so it still reaches the metrics
+		return result, fmt.Errorf("prune operation failed: %w", err)
 	}
 	
 	log.Debug().
@@ -838,6 +1758,7 @@ func runContinuousPruneForServer(client internal.SocialClient, username string,
 		Int("successfully_deleted", result.DeletedCount).
 		Int("successfully_unliked", result.UnlikedCount).
 		Int("successfully_unshared", result.UnsharedCount).
+		Int("successfully_redacted", result.RedactedCount).
 		Int("preserved", result.PreservedCount).
 		Int("errors", result.ErrorsCount).
 		Msg("Prune operation completed")
@@ -850,16 +1771,67 @@ func init() {
 	
 	// Server-specific flags
 	serverCmd.Flags().IntP("port", "P", 8080, "HTTP server port")
-	serverCmd.Flags().String("prune-interval", "1h", "Time between prune runs (e.g., 30m, 1h, 2h)")
+	serverCmd.Flags().Int("metrics-port", 0, "Serve /metrics on this port instead of --port, e.g. to keep the scrape target on an internal network")
+	serverCmd.Flags().String("prune-interval", "1h", "Time between prune runs (e.g., 30m, 1h, 2h) for platforms without a --prune-schedule")
+	serverCmd.Flags().StringArray("prune-schedule", nil, "Cron expression for when to prune, e.g. \"0 3 * * *\", or \"platform=expression\" for one platform (repeatable)")
 	
 	// Inherit all prune flags
-	serverCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon) or 'all' for all platforms")
+	serverCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	serverCmd.Flags().String("policy", "", "Named retention policy to start from: conservative, low-engagement or aggressive (see 'cringesweeper policy presets'); other flags override its settings")
 	serverCmd.Flags().String("max-post-age", "", "Delete posts older than this (e.g., 30d, 1y, 24h)")
 	serverCmd.Flags().String("before-date", "", "Delete posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	serverCmd.Flags().String("after-date", "", "Only delete posts created on or after this date, e.g. with --before-date for a window (YYYY-MM-DD or MM/DD/YYYY)")
 	serverCmd.Flags().Bool("preserve-selflike", false, "Don't delete user's own posts that they have liked")
 	serverCmd.Flags().Bool("preserve-pinned", false, "Don't delete pinned posts")
+	serverCmd.Flags().Bool("preserve-with-replies", false, "Don't delete posts other people have replied to, so their threads stay intact")
+	serverCmd.Flags().String("preserve-hashtags", "", "Comma-separated hashtags whose posts are never deleted (e.g., #keep,#portfolio)")
+	serverCmd.Flags().String("with-hashtags", "", "Only prune posts tagged with one of these comma-separated hashtags (e.g., #conf2019)")
+	serverCmd.Flags().String("preserve-language", "", "Comma-separated languages whose posts are never deleted (e.g., en,de)")
+	serverCmd.Flags().String("language", "", "Only prune posts in one of these comma-separated languages (e.g., en,de); posts with no language set are kept")
+	serverCmd.Flags().Bool("media-only", false, "Only prune posts with media attachments (images, video), keeping text posts")
+	serverCmd.Flags().Bool("skip-media", false, "Don't delete posts with media attachments, only text posts")
+	serverCmd.MarkFlagsMutuallyExclusive("media-only", "skip-media")
+	serverCmd.Flags().Bool("with-links", false, "Only prune posts containing links")
+	serverCmd.Flags().String("links-to", "", "Only prune posts linking to one of these comma-separated domains or their subdomains (e.g., oldjob.com)")
+	serverCmd.Flags().Bool("replies-only", false, "Only prune your replies to other people's posts, not your own posts or the replies in your threads")
+	serverCmd.Flags().Bool("skip-replies", false, "Don't delete replies, only top-level posts")
+	serverCmd.MarkFlagsMutuallyExclusive("replies-only", "skip-replies")
+	serverCmd.Flags().Bool("only-sensitive", false, "Only prune posts marked sensitive or behind a content warning (Mastodon, GoToSocial)")
+	serverCmd.Flags().Bool("preserve-cw", false, "Don't delete posts behind a content warning (Mastodon, GoToSocial)")
+	serverCmd.Flags().String("visibility", "", "Only prune posts with one of these comma-separated visibilities: public, unlisted, followers-only, direct")
+	serverCmd.Flags().Bool("preserve-direct", false, "Don't delete direct messages (Mastodon, GoToSocial)")
+	serverCmd.Flags().String("exclude-file", "", "File of post URLs or IDs, one per line, that are never deleted, unliked or unshared")
+	serverCmd.Flags().Bool("liked-post-age", false, "Judge likes by the age of the liked post instead of when you liked it (Bluesky)")
 	serverCmd.Flags().Bool("unlike-posts", false, "Unlike posts instead of deleting them")
+	serverCmd.Flags().Bool("redact", false, "Edit your posts to a placeholder instead of deleting them, keeping threads intact (Mastodon, GoToSocial)")
 	serverCmd.Flags().Bool("unshare-reposts", false, "Unshare/unrepost instead of deleting reposts")
+	serverCmd.Flags().Bool("unshare-self-reposts", false, "Also unshare reposts of your own posts, leaving the original alone (by default they're kept)")
+	serverCmd.Flags().Bool("skip-self-reposts", false, "Never touch reposts or quotes of your own posts")
+	serverCmd.Flags().Bool("only-self-reposts", false, "Only prune reposts and quotes of your own posts")
+	serverCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "only-self-reposts")
+	serverCmd.MarkFlagsMutuallyExclusive("skip-self-reposts", "unshare-self-reposts")
+	serverCmd.Flags().Bool("delete-whole-threads", false, "When a self-thread's first post is deleted, also delete all your replies in that thread, whatever their age")
+	serverCmd.Flags().Int("max-likes", 0, "Only prune posts with at most this many likes")
+	serverCmd.Flags().Int("max-reposts", 0, "Only prune posts with at most this many reposts")
+	serverCmd.Flags().Int("max-replies", 0, "Only prune posts with at most this many replies")
 	serverCmd.Flags().Bool("dry-run", false, "Show what would be deleted without actually deleting (for testing)")
-	serverCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 60s for Mastodon, 1s for Bluesky)")
+	serverCmd.Flags().String("rate-limit-delay", "", "Delay between API requests to respect rate limits (default: 2s for Mastodon and GoToSocial, 1s for Bluesky)")
+	serverCmd.Flags().Bool("batch-writes", false, "On Bluesky, delete records up to 200 at a time with one request per batch")
+	serverCmd.Flags().Int("breaker-threshold", 3, "Consecutive failed prune runs before pausing a platform (0 disables the circuit breaker)")
+	serverCmd.Flags().String("breaker-cooldown", "2h", "How long a platform is paused after its circuit breaker opens")
+	serverCmd.Flags().Int("ready-failures", 3, "Consecutive failed prune runs on a platform before /readyz reports not ready (0 to only fail on rejected credentials)")
+	serverCmd.Flags().String("tls-cert", "", "Certificate file to serve HTTPS with, together with --tls-key")
+	serverCmd.Flags().String("tls-key", "", "Private key file for --tls-cert")
+	serverCmd.Flags().String("auth-token", "", "Bearer token required for the status page, API and /readyz (or set "+authTokenEnvVar+")")
+	serverCmd.Flags().String("basic-auth", "", "username:password required for the status page, API and /readyz (or set "+basicAuthEnvVar+")")
+	serverCmd.Flags().String("metrics-token", "", "Bearer token required to scrape /metrics (or set "+metricsTokenEnvVar+")")
+	serverCmd.Flags().Int("history-size", 100, "Prune runs to keep in memory for /api/history, across all platforms (0 to keep none)")
+	serverCmd.Flags().Bool("accept-instance-rules", false, "Acknowledge each instance's rules at startup; required before the first non-dry-run prune on an instance")
+	serverCmd.Flags().String("progress-interval", "", "Print a progress summary every N posts and/or after a duration (e.g., 100, 30s, 100,30s) instead of a line per post")
+	serverCmd.Flags().Int("max-requests", 0, "Stop each prune run cleanly after this many API requests, retries included (0 for no limit)")
+	serverCmd.Flags().Int("max-deletions", 0, "Stop each prune run cleanly after it has deleted, redacted, unliked or unshared this many posts (0 for no limit)")
+	serverCmd.Flags().String("max-runtime", "", "Stop each prune run cleanly after this long (e.g., 45m); the next run picks up where it left off")
+
+	// Per-platform overrides of the prune flags above, e.g. --mastodon.max-post-age=30d
+	addPlatformOverrideFlags(serverCmd)
 }
\ No newline at end of file
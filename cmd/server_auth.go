@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables the server reads its endpoint credentials from when the flags
+// aren't given, so they don't show up in the process list
+const (
+	authTokenEnvVar    = "CRINGESWEEPER_AUTH_TOKEN"
+	basicAuthEnvVar    = "CRINGESWEEPER_BASIC_AUTH"
+	metricsTokenEnvVar = "CRINGESWEEPER_METRICS_TOKEN"
+)
+
+// serverAuth is the credentials a request to a guarded endpoint must carry: a bearer
+// token, a basic auth username and password, or either. The zero serverAuth lets
+// everything through.
+type serverAuth struct {
+	token    string
+	username string
+	password string
+}
+
+// parseServerAuth builds a serverAuth from a bearer token and a "username:password"
+// pair, either of which may be empty
+func parseServerAuth(token, basic string) (serverAuth, error) {
+	auth := serverAuth{token: token}
+	if basic != "" {
+		username, password, ok := strings.Cut(basic, ":")
+		if !ok || username == "" || password == "" {
+			return serverAuth{}, fmt.Errorf("basic auth must be given as username:password")
+		}
+		auth.username = username
+		auth.password = password
+	}
+	return auth, nil
+}
+
+// enabled returns true if requests need credentials
+func (a serverAuth) enabled() bool {
+	return a.token != "" || a.username != ""
+}
+
+// allows returns true if the request carries credentials a accepts
+func (a serverAuth) allows(r *http.Request) bool {
+	if a.token != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secretsEqual(token, a.token) {
+			return true
+		}
+	}
+	if a.username != "" {
+		if username, password, ok := r.BasicAuth(); ok && secretsEqual(username, a.username) && secretsEqual(password, a.password) {
+			return true
+		}
+	}
+	return false
+}
+
+// require guards next, answering 401 to requests without credentials a accepts. Basic
+// auth is offered to browsers when it's configured, so the status page can still be
+// opened in one.
+func (a serverAuth) require(next http.Handler) http.Handler {
+	if !a.enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.allows(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if a.username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="cringesweeper"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// secretsEqual compares credentials in constant time, so response timing doesn't give
+// them away
+func secretsEqual(given, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(want)) == 1
+}
+
+// flagOrEnv returns a string flag's value, falling back to the environment variable
+// envVar, or the file named by envVar_FILE, when the flag wasn't given
+func flagOrEnv(cmd *cobra.Command, name, envVar string) string {
+	if value, _ := cmd.Flags().GetString(name); cmd.Flags().Changed(name) {
+		return value
+	}
+	value, err := internal.GetSecretFromEnv(envVar)
+	if err != nil {
+		exitWithError(err)
+	}
+	return value
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestParseServerAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		basic   string
+		want    serverAuth
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "token", token: "s3cret", want: serverAuth{token: "s3cret"}},
+		{name: "basic", basic: "admin:hunter2", want: serverAuth{username: "admin", password: "hunter2"}},
+		{name: "password with colon", basic: "admin:a:b", want: serverAuth{username: "admin", password: "a:b"}},
+		{name: "both", token: "s3cret", basic: "admin:hunter2", want: serverAuth{token: "s3cret", username: "admin", password: "hunter2"}},
+		{name: "no colon", basic: "admin", wantErr: true},
+		{name: "no username", basic: ":hunter2", wantErr: true},
+		{name: "no password", basic: "admin:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseServerAuth(tt.token, tt.basic)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseServerAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseServerAuth() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerAuthRequire(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name          string
+		auth          serverAuth
+		setup         func(r *http.Request)
+		wantCode      int
+		wantChallenge string
+	}{
+		{name: "disabled", auth: serverAuth{}, wantCode: http.StatusOK},
+		{name: "no token", auth: serverAuth{token: "s3cret"}, wantCode: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{
+			name:     "right token",
+			auth:     serverAuth{token: "s3cret"},
+			setup:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") },
+			wantCode: http.StatusOK,
+		},
+		{
+			name:          "wrong token",
+			auth:          serverAuth{token: "s3cret"},
+			setup:         func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") },
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: "Bearer",
+		},
+		{
+			name:     "right password",
+			auth:     serverAuth{username: "admin", password: "hunter2"},
+			setup:    func(r *http.Request) { r.SetBasicAuth("admin", "hunter2") },
+			wantCode: http.StatusOK,
+		},
+		{
+			name:          "wrong password",
+			auth:          serverAuth{username: "admin", password: "hunter2"},
+			setup:         func(r *http.Request) { r.SetBasicAuth("admin", "guess") },
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Basic realm="cringesweeper"`,
+		},
+		{
+			name:     "token when both are set",
+			auth:     serverAuth{token: "s3cret", username: "admin", password: "hunter2"},
+			setup:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") },
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			rec := httptest.NewRecorder()
+			tt.auth.require(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("Expected WWW-Authenticate %q, got %q", tt.wantChallenge, got)
+			}
+		})
+	}
+}
+
+func TestNewServerHandlerAuth(t *testing.T) {
+	handler := newServerHandler(nil, serverConfig{
+		readyFailures: 3,
+		auth:          serverAuth{token: "s3cret"},
+		metricsAuth:   serverAuth{token: "scrape"},
+	})
+	get := func(path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		path  string
+		token string
+		want  int
+	}{
+		{"/healthz", "", http.StatusOK},
+		{"/readyz", "", http.StatusUnauthorized},
+		{"/readyz", "s3cret", http.StatusOK},
+		{"/api/status", "", http.StatusUnauthorized},
+		{"/api/status", "s3cret", http.StatusOK},
+		{"/api/posts/bluesky", "", http.StatusUnauthorized},
+		{"/api/posts/bluesky", "s3cret", http.StatusNotFound},
+		{"/metrics", "", http.StatusUnauthorized},
+		{"/metrics", "s3cret", http.StatusUnauthorized},
+		{"/metrics", "scrape", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := get(tt.path, tt.token); got != tt.want {
+			t.Errorf("GET %s with token %q: expected %d, got %d", tt.path, tt.token, tt.want, got)
+		}
+	}
+}
+
+func TestNewServerHandlerPostsNeedAuth(t *testing.T) {
+	runners := []PlatformRunner{
+		{Config: PlatformConfig{name: "bluesky", username: "me.bsky.social", client: &pagingClient{posts: []internal.Post{{ID: "1"}}}}},
+	}
+	handler := newServerHandler(runners, serverConfig{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/posts/bluesky", nil))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "--auth-token") {
+		t.Errorf("Expected /api/posts to be refused without server credentials, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The account's posts aren't offered to other sites, while the status stays open to them
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("Expected no CORS header on /api/posts, got %q", origin)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "*" {
+		t.Errorf("Expected /api/status to allow any origin, got %q", origin)
+	}
+}
+
+func TestMetricsPortMovesMetrics(t *testing.T) {
+	config := serverConfig{metricsPort: 9090, metricsAuth: serverAuth{token: "scrape"}}
+	get := func(handler http.Handler, path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	main := newServerHandler(nil, config)
+	rec := httptest.NewRecorder()
+	main.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if strings.Contains(rec.Body.String(), "# HELP") {
+		t.Errorf("Expected /metrics to be gone from the main port, got metrics")
+	}
+	if code := get(main, "/healthz", ""); code != http.StatusOK {
+		t.Errorf("Expected /healthz on the main port, got %d", code)
+	}
+
+	metrics := newMetricsHandler(config)
+	if code := get(metrics, "/metrics", "scrape"); code != http.StatusOK {
+		t.Errorf("Expected /metrics on the metrics port, got %d", code)
+	}
+	if code := get(metrics, "/metrics", ""); code != http.StatusUnauthorized {
+		t.Errorf("Expected --metrics-token to guard the metrics port, got %d", code)
+	}
+	if code := get(metrics, "/api/status", ""); code != http.StatusNotFound {
+		t.Errorf("Expected the metrics port to serve only metrics, got %d for /api/status", code)
+	}
+}
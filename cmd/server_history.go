@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// RunRecord is one prune run in the server's run history, flattened so dashboards can
+// plot each field as a time series
+type RunRecord struct {
+	Time            time.Time `json:"time"` // When the run finished
+	Platform        string    `json:"platform"`
+	Status          string    `json:"status"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	DryRun          bool      `json:"dry_run"`
+	StoppedEarly    bool      `json:"stopped_early"`
+	Deleted         int       `json:"deleted"`
+	Unliked         int       `json:"unliked"`
+	Unshared        int       `json:"unshared"`
+	Redacted        int       `json:"redacted"`
+	Preserved       int       `json:"preserved"`
+	Errors          int       `json:"errors"`
+	RemainingPosts  *int      `json:"remaining_posts,omitempty"` // Unknown when the run didn't get through the posts
+	MatchingPosts   *int      `json:"matching_posts,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// newRunRecord describes a finished run for the history. result is whatever part of the
+// run got done, or nil if it failed before doing anything.
+func newRunRecord(platform, status, errorMsg string, duration time.Duration, dryRun bool, result *internal.PruneResult) RunRecord {
+	record := RunRecord{
+		Time:            clock.Now(),
+		Platform:        platform,
+		Status:          status,
+		DurationSeconds: duration.Seconds(),
+		DryRun:          dryRun,
+		Error:           errorMsg,
+	}
+	if result == nil {
+		return record
+	}
+	record.StoppedEarly = result.StoppedEarly
+	record.Deleted = result.DeletedCount
+	record.Unliked = result.UnlikedCount
+	record.Unshared = result.UnsharedCount
+	record.Redacted = result.RedactedCount
+	record.Preserved = result.PreservedCount
+	record.Errors = result.ErrorsCount
+	if result.Account != nil {
+		remaining, matching := result.Account.RemainingPosts(), result.Account.Matching
+		record.RemainingPosts = &remaining
+		record.MatchingPosts = &matching
+	}
+	return record
+}
+
+// runHistory keeps the last few prune runs across all platforms, oldest first. It lives
+// in memory, so it starts empty whenever the server does.
+type runHistory struct {
+	mu      sync.Mutex
+	size    int
+	records []RunRecord
+}
+
+// newRunHistory creates a history that keeps the last size runs, or none if size is zero
+func newRunHistory(size int) *runHistory {
+	return &runHistory{size: size}
+}
+
+// add records a run, dropping the oldest once the history is full
+func (h *runHistory) add(record RunRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.size <= 0 {
+		return
+	}
+	h.records = append(h.records, record)
+	if len(h.records) > h.size {
+		h.records = append(h.records[:0], h.records[len(h.records)-h.size:]...)
+	}
+}
+
+// runs returns up to the last limit runs on platform, oldest first. An empty platform
+// means every platform, and a limit of zero every run kept.
+func (h *runHistory) runs(platform string, limit int) []RunRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := []RunRecord{}
+	for _, record := range h.records {
+		if platform == "" || record.Platform == platform {
+			runs = append(runs, record)
+		}
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+	return runs
+}
+
+// apiHistoryHandler serves the run history as JSON, for dashboards such as Grafana's
+// JSON data sources. ?platform= picks out one platform and ?limit= the latest runs.
+func apiHistoryHandler(history *runHistory) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		defer func() {
+			httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, strconv.Itoa(status)).Inc()
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed < 1 {
+				status = http.StatusBadRequest
+				w.WriteHeader(status)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("limit must be a positive number, not %q", limitStr)})
+				return
+			}
+			limit = parsed
+		}
+
+		json.NewEncoder(w).Encode(history.runs(r.URL.Query().Get("platform"), limit))
+	})
+}
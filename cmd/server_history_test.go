@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+func TestRunHistory(t *testing.T) {
+	history := newRunHistory(3)
+	for i, platform := range []string{"bluesky", "mastodon", "bluesky", "mastodon", "bluesky"} {
+		history.add(RunRecord{Platform: platform, Deleted: i})
+	}
+
+	deleted := func(runs []RunRecord) []int {
+		var got []int
+		for _, run := range runs {
+			got = append(got, run.Deleted)
+		}
+		return got
+	}
+	tests := []struct {
+		name     string
+		platform string
+		limit    int
+		want     []int
+	}{
+		{name: "keeps the last runs", want: []int{2, 3, 4}},
+		{name: "one platform", platform: "bluesky", want: []int{2, 4}},
+		{name: "limit", limit: 2, want: []int{3, 4}},
+		{name: "unknown platform", platform: "gotosocial", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deleted(history.runs(tt.platform, tt.limit)); !slices.Equal(got, tt.want) {
+				t.Errorf("runs(%q, %d) = %v, want %v", tt.platform, tt.limit, got, tt.want)
+			}
+		})
+	}
+
+	empty := newRunHistory(0)
+	empty.add(RunRecord{Platform: "bluesky"})
+	if got := empty.runs("", 0); len(got) != 0 {
+		t.Errorf("Expected a zero-size history to keep nothing, got %v", got)
+	}
+}
+
+func TestAPIHistoryHandler(t *testing.T) {
+	history := newRunHistory(10)
+	history.add(newRunRecord("mastodon", "success", "", 2*time.Second, false, &internal.PruneResult{
+		DeletedCount: 5,
+		Account:      &internal.AccountStats{Remaining: map[internal.PostType]int{internal.PostTypeOriginal: 20}, Matching: 1},
+	}))
+	history.add(newRunRecord("mastodon", "error", "boom", time.Second, false, nil))
+
+	rec := httptest.NewRecorder()
+	apiHistoryHandler(history).ServeHTTP(rec, httptest.NewRequest("GET", "/api/history?platform=mastodon", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var runs []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &runs); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs, got %d", len(runs))
+	}
+	if runs[0]["deleted"] != 5.0 || runs[0]["remaining_posts"] != 20.0 || runs[0]["matching_posts"] != 1.0 || runs[0]["duration_seconds"] != 2.0 {
+		t.Errorf("Unexpected first run: %v", runs[0])
+	}
+	if _, ok := runs[1]["remaining_posts"]; ok || runs[1]["error"] != "boom" {
+		t.Errorf("Expected the failed run to have its error and no post counts, got %v", runs[1])
+	}
+
+	rec = httptest.NewRecorder()
+	apiHistoryHandler(history).ServeHTTP(rec, httptest.NewRequest("GET", "/api/history?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad limit, got %d", rec.Code)
+	}
+}
+
+func TestRunPruneWithMetricsRecordsHistory(t *testing.T) {
+	previous := serverHistory
+	serverHistory = newRunHistory(10)
+	t.Cleanup(func() { serverHistory = previous })
+
+	client := &partialClient{result: &internal.PruneResult{DeletedCount: 3}, err: errors.New("connection reset")}
+	runPruneWithMetrics(context.Background(), client, "me", internal.PruneOptions{}, "historytest", internal.NewCircuitBreaker(0, time.Hour, clock))
+
+	runs := serverHistory.runs("historytest", 0)
+	if len(runs) != 1 || runs[0].Status != "error" || runs[0].Deleted != 3 || runs[0].Error == "" {
+		t.Errorf("Expected the failed run in the history with its 3 deletions, got %+v", runs)
+	}
+}
@@ -0,0 +1,713 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/cobra"
+)
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		page          int
+		pageSize      int
+		expectedStart int
+		expectedEnd   int
+		expectedPage  int
+		expectedPages int
+	}{
+		{"empty list", 0, 1, 25, 0, 0, 1, 1},
+		{"first page", 60, 1, 25, 0, 25, 1, 3},
+		{"middle page", 60, 2, 25, 25, 50, 2, 3},
+		{"partial last page", 60, 3, 25, 50, 60, 3, 3},
+		{"page past end clamps to last", 60, 9, 25, 50, 60, 3, 3},
+		{"zero page clamps to first", 60, 0, 25, 0, 25, 1, 3},
+		{"negative page clamps to first", 10, -2, 25, 0, 10, 1, 1},
+		{"exact multiple", 50, 2, 25, 25, 50, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, page, pages := paginate(tt.total, tt.page, tt.pageSize)
+			if start != tt.expectedStart || end != tt.expectedEnd || page != tt.expectedPage || pages != tt.expectedPages {
+				t.Errorf("paginate(%d, %d, %d) = (%d, %d, %d, %d), expected (%d, %d, %d, %d)",
+					tt.total, tt.page, tt.pageSize, start, end, page, pages,
+					tt.expectedStart, tt.expectedEnd, tt.expectedPage, tt.expectedPages)
+			}
+		})
+	}
+}
+
+func TestCollectDryRunMatches(t *testing.T) {
+	result := &internal.PruneResult{
+		PostsToDelete:  []internal.Post{{ID: "1"}, {ID: "2"}},
+		PostsToUnlike:  []internal.Post{{ID: "3"}},
+		PostsToUnshare: []internal.Post{{ID: "4"}},
+		PostsPreserved: []internal.Post{{ID: "5"}},
+	}
+
+	matches := collectDryRunMatches(result)
+	expected := []struct{ action, id string }{
+		{"delete", "1"}, {"delete", "2"}, {"unlike", "3"}, {"unshare", "4"},
+	}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d", len(expected), len(matches))
+	}
+	for i, e := range expected {
+		if matches[i].Action != e.action || matches[i].Post.ID != e.id {
+			t.Errorf("Match %d: expected %s/%s, got %s/%s", i, e.action, e.id, matches[i].Action, matches[i].Post.ID)
+		}
+	}
+}
+
+func TestRenderDryRunMatches(t *testing.T) {
+	var matches []DryRunMatch
+	for i := 0; i < dryRunPageSize+5; i++ {
+		matches = append(matches, DryRunMatch{
+			Action: "delete",
+			Post: internal.Post{
+				Content:   "<script>alert(1)</script>",
+				CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				URL:       "https://example.com/post",
+			},
+		})
+	}
+	matches[len(matches)-1].Post.URL = "javascript:alert(1)"
+
+	t.Run("first page links forward only", func(t *testing.T) {
+		var buf bytes.Buffer
+		renderDryRunMatches(&buf, "bluesky", matches, 1)
+		out := buf.String()
+		if !strings.Contains(out, "Dry Run Matches (30)") {
+			t.Errorf("Expected match count in output")
+		}
+		if !strings.Contains(out, "/?platform=bluesky&page=2") {
+			t.Errorf("Expected link to next page")
+		}
+		if strings.Contains(out, "Previous") {
+			t.Errorf("Did not expect a previous link on the first page")
+		}
+		if strings.Contains(out, "<script>") {
+			t.Errorf("Post content should be HTML-escaped")
+		}
+	})
+
+	t.Run("last page links back only", func(t *testing.T) {
+		var buf bytes.Buffer
+		renderDryRunMatches(&buf, "bluesky", matches, 2)
+		out := buf.String()
+		if !strings.Contains(out, "Showing 26-30 of 30") {
+			t.Errorf("Expected range for last page, got %s", out)
+		}
+		if !strings.Contains(out, "/?platform=bluesky&page=1") || strings.Contains(out, "Next") {
+			t.Errorf("Expected only a previous link on the last page")
+		}
+		if !strings.Contains(out, `<a href="https://example.com/post">view</a>`) {
+			t.Errorf("Expected https post URLs to be linked")
+		}
+		if strings.Contains(out, "javascript:") {
+			t.Errorf("Did not expect a link for a javascript: URL")
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var buf bytes.Buffer
+		renderDryRunMatches(&buf, "mastodon", nil, 1)
+		if !strings.Contains(buf.String(), "No posts matched") {
+			t.Errorf("Expected empty-state message")
+		}
+	})
+}
+
+func TestFormatCircuitState(t *testing.T) {
+	openUntil := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status   *PlatformStatus
+		expected string
+	}{
+		{&PlatformStatus{CircuitState: "closed"}, "closed"},
+		{&PlatformStatus{CircuitState: "half-open"}, "half-open"},
+		{&PlatformStatus{CircuitState: "open", CircuitOpenUntil: openUntil}, "open until 2024-01-01 12:00:00 UTC"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCircuitState(tt.status); got != tt.expected {
+			t.Errorf("formatCircuitState(%q) = %q, expected %q", tt.status.CircuitState, got, tt.expected)
+		}
+	}
+}
+
+func TestScheduleForPlatform(t *testing.T) {
+	schedules, err := internal.ParsePruneSchedules([]string{"0 3 * * *", "mastodon=30 4 * * *"})
+	if err != nil {
+		t.Fatalf("ParsePruneSchedules failed: %v", err)
+	}
+
+	if got := scheduleForPlatform(schedules, "mastodon", time.Hour).String(); got != "30 4 * * *" {
+		t.Errorf("Expected mastodon's own schedule, got %q", got)
+	}
+	if got := scheduleForPlatform(schedules, "bluesky", time.Hour).String(); got != "0 3 * * *" {
+		t.Errorf("Expected the default schedule, got %q", got)
+	}
+	if got := scheduleForPlatform(nil, "bluesky", 2*time.Hour); got != (internal.IntervalSchedule{Interval: 2 * time.Hour}) {
+		t.Errorf("Expected the prune interval fallback, got %v", got)
+	}
+}
+
+// setServerFlags sets flags on serverCmd, restoring their defaults when the test ends
+func setServerFlags(t *testing.T, values map[string]string) {
+	t.Helper()
+	setCommandFlags(t, serverCmd, values)
+}
+
+// setCommandFlags sets flags on cmd, restoring their defaults when the test ends
+func setCommandFlags(t *testing.T, cmd *cobra.Command, values map[string]string) {
+	t.Helper()
+	for name, value := range values {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("No such %s flag --%s", cmd.Name(), name)
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("Setting --%s=%s: %v", name, value, err)
+		}
+		t.Cleanup(func() {
+			flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		})
+	}
+}
+
+func TestServerPruneOptions_PlatformOverrides(t *testing.T) {
+	setServerFlags(t, map[string]string{
+		"max-post-age":            "30d",
+		"preserve-pinned":         "true",
+		"max-likes":               "5",
+		"bluesky.max-post-age":    "90d",
+		"bluesky.preserve-pinned": "false",
+		"mastodon.max-likes":      "0",
+		"mastodon.unlike-posts":   "true",
+	})
+
+	bluesky, err := platformPruneOptions(serverCmd, "bluesky")
+	if err != nil {
+		t.Fatalf("platformPruneOptions(bluesky) failed: %v", err)
+	}
+	if *bluesky.MaxAge != 90*24*time.Hour {
+		t.Errorf("Expected bluesky's own max age, got %v", *bluesky.MaxAge)
+	}
+	if bluesky.PreservePinned {
+		t.Error("Expected the bluesky override to turn off preserve-pinned")
+	}
+	if bluesky.MaxLikes == nil || *bluesky.MaxLikes != 5 {
+		t.Errorf("Expected the shared max-likes, got %v", bluesky.MaxLikes)
+	}
+	if bluesky.UnlikePosts {
+		t.Error("mastodon's unlike-posts override shouldn't apply to bluesky")
+	}
+	if bluesky.RateLimitDelay != time.Second {
+		t.Errorf("Expected bluesky's default rate limit delay, got %v", bluesky.RateLimitDelay)
+	}
+
+	mastodon, err := platformPruneOptions(serverCmd, "mastodon")
+	if err != nil {
+		t.Fatalf("platformPruneOptions(mastodon) failed: %v", err)
+	}
+	if *mastodon.MaxAge != 30*24*time.Hour {
+		t.Errorf("Expected the shared max age, got %v", *mastodon.MaxAge)
+	}
+	if !mastodon.PreservePinned || !mastodon.UnlikePosts {
+		t.Errorf("Expected shared preserve-pinned and mastodon's unlike-posts, got %+v", mastodon)
+	}
+	if mastodon.MaxLikes == nil || *mastodon.MaxLikes != 0 {
+		t.Errorf("Expected mastodon's max-likes override of 0, got %v", mastodon.MaxLikes)
+	}
+}
+
+func TestServerPruneOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   string
+	}{
+		{"no age criteria", map[string]string{"preserve-pinned": "true"}, "must specify either"},
+		{"bad override", map[string]string{"max-post-age": "30d", "bluesky.max-post-age": "soon"}, "bluesky.max-post-age"},
+		{"negative override", map[string]string{"max-post-age": "30d", "bluesky.max-replies": "-1"}, "--bluesky.max-replies must not be negative"},
+		{"media conflict", map[string]string{"max-post-age": "30d", "skip-media": "true", "bluesky.media-only": "true"}, "--bluesky.media-only and --skip-media"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setServerFlags(t, tt.values)
+			_, err := platformPruneOptions(serverCmd, "bluesky")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Only the overridden platform is affected
+	t.Run("other platform unaffected", func(t *testing.T) {
+		setServerFlags(t, map[string]string{"max-post-age": "30d", "bluesky.max-post-age": "soon"})
+		if _, err := platformPruneOptions(serverCmd, "mastodon"); err != nil {
+			t.Errorf("Expected mastodon to use the shared flags, got %v", err)
+		}
+	})
+}
+
+func TestCheckPlatformOverrides(t *testing.T) {
+	setServerFlags(t, map[string]string{"mastodon.max-post-age": "30d"})
+
+	if err := checkPlatformOverrides(serverCmd, []string{"bluesky", "mastodon"}); err != nil {
+		t.Errorf("Expected override for a running platform to be accepted, got %v", err)
+	}
+	if err := checkPlatformOverrides(serverCmd, []string{"bluesky"}); err == nil {
+		t.Error("Expected an error for an override on a platform that isn't running")
+	}
+}
+
+// countingClient records each prune run on a channel
+type countingClient struct {
+	internal.SocialClient
+	runs chan time.Time
+}
+
+func (c *countingClient) GetPlatformName() string { return "Test" }
+
+func (c *countingClient) PrunePosts(ctx context.Context, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+	c.runs <- clock.Now()
+	return &internal.PruneResult{}, nil
+}
+
+func TestPlatformRunnerStartRun(t *testing.T) {
+	start := time.Date(2025, 1, 15, 2, 0, 0, 0, time.UTC)
+	fake := withFakeClock(t, start)
+
+	runner := PlatformRunner{Options: internal.PruneOptions{DryRun: true}}
+	ctx, options := runner.startRun(context.Background())
+	if !options.Deadline.IsZero() {
+		t.Errorf("Expected no deadline without --max-runtime, got %v", options.Deadline)
+	}
+	if budget := internal.RequestBudgetFromContext(ctx); budget == nil || budget.Exhausted() {
+		t.Error("Expected an unlimited request budget so requests are still counted")
+	}
+
+	// Each run gets the full budget from when it starts
+	runner.MaxRuntime = 45 * time.Minute
+	runner.MaxRequests = 100
+	ctx, options = runner.startRun(context.Background())
+	if !options.Deadline.Equal(start.Add(45 * time.Minute)) {
+		t.Errorf("Unexpected deadline %v", options.Deadline)
+	}
+	first := internal.RequestBudgetFromContext(ctx)
+	fake.Advance(time.Hour)
+	ctx, options = runner.startRun(context.Background())
+	if !options.Deadline.Equal(start.Add(105 * time.Minute)) {
+		t.Errorf("Unexpected deadline for a later run %v", options.Deadline)
+	}
+	if internal.RequestBudgetFromContext(ctx) == first {
+		t.Error("Expected each run to get its own request budget")
+	}
+	if !runner.Options.Deadline.IsZero() {
+		t.Error("startRun shouldn't modify the runner's options")
+	}
+}
+
+func TestAPIMetricsObserver(t *testing.T) {
+	const platform = "metrics-test"
+	observe := apiMetricsObserver(platform)
+
+	observe(http.StatusOK, http.Header{
+		"X-Ratelimit-Limit":     {"300"},
+		"X-Ratelimit-Remaining": {"12"},
+		"X-Ratelimit-Reset":     {"2025-01-15T02:05:00Z"},
+	})
+	observe(http.StatusUnauthorized, nil)
+	observe(http.StatusTooManyRequests, http.Header{"X-Ratelimit-Remaining": {"0"}})
+	observe(http.StatusBadGateway, nil)
+	observe(http.StatusNotFound, nil)
+	observe(0, nil)
+
+	counts := []struct {
+		metric prometheus.Collector
+		labels []string
+		want   float64
+	}{
+		{apiResponsesTotal, []string{platform, "2xx"}, 1},
+		{apiResponsesTotal, []string{platform, "4xx"}, 3},
+		{apiResponsesTotal, []string{platform, "5xx"}, 1},
+		{apiResponsesTotal, []string{platform, "error"}, 1},
+		{apiErrorsTotal, []string{platform, "unauthorized"}, 1},
+		{apiErrorsTotal, []string{platform, "forbidden"}, 0},
+		{apiErrorsTotal, []string{platform, "rate_limited"}, 1},
+		{apiErrorsTotal, []string{platform, "server_error"}, 1},
+		{apiErrorsTotal, []string{platform, "network"}, 1},
+		{apiRateLimitRemaining, []string{platform}, 0},
+		{apiRateLimitLimit, []string{platform}, 300},
+		{apiRateLimitReset, []string{platform}, float64(time.Date(2025, 1, 15, 2, 5, 0, 0, time.UTC).Unix())},
+	}
+	for _, tt := range counts {
+		var got float64
+		switch metric := tt.metric.(type) {
+		case *prometheus.CounterVec:
+			got = testutil.ToFloat64(metric.WithLabelValues(tt.labels...))
+		case *prometheus.GaugeVec:
+			got = testutil.ToFloat64(metric.WithLabelValues(tt.labels...))
+		}
+		if got != tt.want {
+			t.Errorf("%v = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
+
+// pagingClient serves FetchUserPostsPaginated from a fixed list of posts, with the
+// cursor being the offset of the next page
+type pagingClient struct {
+	internal.SocialClient
+	posts []internal.Post
+	err   error
+}
+
+func (c *pagingClient) FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]internal.Post, string, error) {
+	if c.err != nil {
+		return nil, "", c.err
+	}
+	offset, _ := strconv.Atoi(cursor)
+	end := min(offset+limit, len(c.posts))
+	next := ""
+	if end < len(c.posts) {
+		next = strconv.Itoa(end)
+	}
+	return c.posts[offset:end], next, nil
+}
+
+func TestAPIPostsHandler(t *testing.T) {
+	posts := []internal.Post{
+		{ID: "1", Content: "first", Type: internal.PostTypeOriginal, Platform: "bluesky"},
+		{ID: "2", Content: "second", Type: internal.PostTypeReply, Platform: "bluesky"},
+		{ID: "3", Content: "third", Type: internal.PostTypeOriginal, Platform: "bluesky"},
+	}
+	runners := []PlatformRunner{
+		{Config: PlatformConfig{name: "bluesky", username: "me.bsky.social", client: &pagingClient{posts: posts}}},
+		{Config: PlatformConfig{name: "mastodon", username: "me@example.social", client: &pagingClient{err: errors.New("instance down")}}},
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/posts/{platform}", apiPostsHandler(runners))
+
+	get := func(url string) (int, apiPostsPage, string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+		var page apiPostsPage
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		return rec.Code, page, rec.Body.String()
+	}
+
+	code, page, _ := get("/api/posts/bluesky?limit=2")
+	if code != http.StatusOK || len(page.Posts) != 2 || page.NextCursor != "2" || page.Username != "me.bsky.social" {
+		t.Fatalf("Unexpected first page: %d %+v", code, page)
+	}
+	code, page, _ = get("/api/posts/bluesky?limit=2&cursor=" + page.NextCursor)
+	if code != http.StatusOK || len(page.Posts) != 1 || page.Posts[0].Content != "third" || page.NextCursor != "" {
+		t.Errorf("Unexpected last page: %d %+v", code, page)
+	}
+
+	errorTests := []struct {
+		url      string
+		wantCode int
+		wantBody string
+	}{
+		{"/api/posts/twitter", http.StatusNotFound, "isn't being served"},
+		{"/api/posts/bluesky?limit=0", http.StatusBadRequest, "from 1 to 100"},
+		{"/api/posts/mastodon?limit=41", http.StatusBadRequest, "from 1 to 40"},
+		{"/api/posts/mastodon", http.StatusBadGateway, "instance down"},
+	}
+	for _, tt := range errorTests {
+		code, _, body := get(tt.url)
+		if code != tt.wantCode || !strings.Contains(body, tt.wantBody) {
+			t.Errorf("GET %s = %d %s, want %d containing %q", tt.url, code, body, tt.wantCode, tt.wantBody)
+		}
+	}
+}
+
+func TestPruneRunsDrain(t *testing.T) {
+	runs := newPruneRuns()
+	if !runs.begin() {
+		t.Fatal("Expected a run to start before draining")
+	}
+
+	drained := make(chan bool)
+	go func() { drained <- runs.drainAndWait(context.Background()) }()
+
+	// Once draining, the run in progress is told to stop and no new ones start
+	for !runs.drain.Draining() {
+		time.Sleep(time.Millisecond)
+	}
+	if runs.begin() {
+		t.Error("Expected no new runs once draining")
+	}
+	select {
+	case <-drained:
+		t.Fatal("Expected shutdown to wait for the run in progress")
+	default:
+	}
+
+	runs.end()
+	if !<-drained {
+		t.Error("Expected the drain to finish once the run ended")
+	}
+
+	// A run that won't stop is given up on when the shutdown timeout passes
+	stuck := newPruneRuns()
+	stuck.begin()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if stuck.drainAndWait(ctx) {
+		t.Error("Expected the drain to time out with a run still going")
+	}
+}
+
+func TestRunPruneWithMetricsKeepsPartialResult(t *testing.T) {
+	serverState.UpdatePlatformStatus("partialtest", &PlatformStatus{Name: "partialtest", PostsProcessed: make(map[string]int64)})
+	t.Cleanup(func() {
+		serverState.mu.Lock()
+		delete(serverState.Platforms, "partialtest")
+		serverState.mu.Unlock()
+	})
+
+	client := &partialClient{result: &internal.PruneResult{DeletedCount: 4}, err: errors.New("connection reset")}
+	runPruneWithMetrics(context.Background(), client, "me", internal.PruneOptions{}, "partialtest", internal.NewCircuitBreaker(0, time.Hour, clock))
+
+	status, _ := serverState.GetPlatformStatus("partialtest")
+	if status.LastPruneStatus != "error" || status.PostsProcessed["deleted"] != 4 {
+		t.Errorf("Expected a failed run with its 4 deletions counted, got %+v", status)
+	}
+}
+
+func TestUpdateAccountMetrics(t *testing.T) {
+	fake := withFakeClock(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	t.Cleanup(func() {
+		accountPostsTotal.DeleteLabelValues("statstest")
+		accountPostsSeenRemaining.DeletePartialMatch(prometheus.Labels{"platform": "statstest"})
+		accountPostsMatching.DeleteLabelValues("statstest")
+		accountOldestSeenPostAge.DeleteLabelValues("statstest")
+	})
+
+	updateAccountMetrics("statstest", &internal.AccountStats{
+		Remaining:  map[internal.PostType]int{internal.PostTypeOriginal: 40, internal.PostTypeLike: 7},
+		Matching:   12,
+		OldestPost: fake.Now().Add(-48 * time.Hour),
+		Total:      1500,
+	})
+	if got := testutil.ToFloat64(accountPostsTotal.WithLabelValues("statstest")); got != 1500 {
+		t.Errorf("Expected the platform's count of 1500 posts, got %v", got)
+	}
+	if got := testutil.ToFloat64(accountPostsSeenRemaining.WithLabelValues("statstest", "original")); got != 40 {
+		t.Errorf("Expected 40 original posts left, got %v", got)
+	}
+	if got := testutil.ToFloat64(accountPostsMatching.WithLabelValues("statstest")); got != 12 {
+		t.Errorf("Expected 12 matching posts, got %v", got)
+	}
+	if got := testutil.ToFloat64(accountOldestSeenPostAge.WithLabelValues("statstest")); got != (48 * time.Hour).Seconds() {
+		t.Errorf("Expected the oldest post to be 48h old, got %vs", got)
+	}
+
+	// Once the likes are all gone their count goes rather than staying at 7, a platform
+	// without a post count drops the total, and a run without stats leaves the last
+	// figures alone
+	updateAccountMetrics("statstest", &internal.AccountStats{Remaining: map[internal.PostType]int{internal.PostTypeOriginal: 30}, Total: -1})
+	updateAccountMetrics("statstest", nil)
+	if got := testutil.CollectAndCount(accountPostsSeenRemaining, "cringesweeper_account_posts_seen_remaining"); got != 1 {
+		t.Errorf("Expected only the original post count to be left, got %d series", got)
+	}
+	if got := testutil.ToFloat64(accountPostsSeenRemaining.WithLabelValues("statstest", "original")); got != 30 {
+		t.Errorf("Expected 30 original posts left, got %v", got)
+	}
+	if got := testutil.CollectAndCount(accountPostsTotal, "cringesweeper_account_posts_total"); got != 0 {
+		t.Errorf("Expected no total without a platform count, got %d series", got)
+	}
+}
+
+type postCountClient struct {
+	pagingClient
+	count int
+	err   error
+}
+
+func (c *postCountClient) GetPlatformName() string { return "Test" }
+
+func (c *postCountClient) GetPostCount(ctx context.Context, username string) (int, error) {
+	return c.count, c.err
+}
+
+func TestFillAccountTotal(t *testing.T) {
+	tests := []struct {
+		name   string
+		client internal.SocialClient
+		want   int
+	}{
+		{"platform reports a count", &postCountClient{count: 1500}, 1500},
+		{"count lookup fails", &postCountClient{err: errors.New("instance down")}, -1},
+		{"platform has no count", &pagingClient{}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &internal.AccountStats{Total: -1}
+			fillAccountTotal(context.Background(), tt.client, "me", stats)
+			if stats.Total != tt.want {
+				t.Errorf("Expected a total of %d, got %d", tt.want, stats.Total)
+			}
+		})
+	}
+}
+
+// partialClient fails its prune runs part way, after getting some done
+type partialClient struct {
+	internal.SocialClient
+	result *internal.PruneResult
+	err    error
+}
+
+func (c *partialClient) GetPlatformName() string { return "Test" }
+
+func (c *partialClient) PrunePosts(ctx context.Context, username string, options internal.PruneOptions) (*internal.PruneResult, error) {
+	return c.result, c.err
+}
+
+func TestReadinessProblems(t *testing.T) {
+	statuses := map[string]*PlatformStatus{
+		"mastodon":   {ConsecutiveFailures: 3, LastPruneError: "server error"},
+		"bluesky":    {ConsecutiveFailures: 1, CredentialsRejected: true, LastPruneError: "unauthorized"},
+		"gotosocial": {ConsecutiveFailures: 2, LastPruneError: "timeout"},
+	}
+
+	want := []string{
+		"bluesky: credentials were rejected: unauthorized",
+		"mastodon: last 3 prune runs failed: server error",
+	}
+	if got := readinessProblems(statuses, 3); !slices.Equal(got, want) {
+		t.Errorf("readinessProblems() = %q, expected %q", got, want)
+	}
+
+	// With no threshold only rejected credentials count
+	if got := readinessProblems(statuses, 0); len(got) != 1 || !strings.HasPrefix(got[0], "bluesky:") {
+		t.Errorf("Expected only the rejected credentials without a threshold, got %q", got)
+	}
+}
+
+func TestHealthHandlers(t *testing.T) {
+	serverState.UpdatePlatformStatus("readytest", &PlatformStatus{Name: "readytest", PostsProcessed: make(map[string]int64)})
+	t.Cleanup(func() {
+		serverState.mu.Lock()
+		delete(serverState.Platforms, "readytest")
+		serverState.mu.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", healthzHandler())
+	mux.Handle("GET /readyz", readyzHandler(2))
+	probe := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	if rec := probe("/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected ready before any failures, got %d: %s", rec.Code, rec.Body)
+	}
+
+	status, _ := serverState.GetPlatformStatus("readytest")
+	status.ConsecutiveFailures = 2
+	status.LastPruneError = "boom"
+	serverState.UpdatePlatformStatus("readytest", status)
+
+	if rec := probe("/readyz"); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "readytest: last 2 prune runs failed: boom") {
+		t.Errorf("Expected not ready after 2 failures, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := probe("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected liveness to be unaffected by failed runs, got %d", rec.Code)
+	}
+}
+
+func TestStartPlatformMonitoringFollowsCronSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 15, 2, 58, 0, 0, time.UTC)
+	fake := withFakeClock(t, start)
+	schedule, _ := internal.ParseCronSchedule("0 3 * * *")
+
+	serverState.UpdatePlatformStatus("crontest", &PlatformStatus{Name: "crontest", PostsProcessed: make(map[string]int64)})
+	t.Cleanup(func() {
+		serverState.mu.Lock()
+		delete(serverState.Platforms, "crontest")
+		serverState.mu.Unlock()
+	})
+
+	client := &countingClient{runs: make(chan time.Time, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		startPlatformMonitoring(ctx, PlatformRunner{
+			Config:   PlatformConfig{name: "crontest", username: "me", client: client},
+			Breaker:  internal.NewCircuitBreaker(0, time.Hour, fake),
+			Schedule: schedule,
+		})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForWaiter := func() {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for fake.Waiters() == 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting for the scheduler")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Cron schedules don't run on start
+	waitForWaiter()
+	if status, _ := serverState.GetPlatformStatus("crontest"); !status.NextPruneTime.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("Expected next prune at 03:00, got %v", status.NextPruneTime)
+	}
+	fake.Advance(time.Minute)
+	select {
+	case ran := <-client.runs:
+		t.Fatalf("Unexpected run at %v", ran)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	fake.Advance(time.Minute)
+	select {
+	case ran := <-client.runs:
+		if !ran.Equal(start.Add(2 * time.Minute)) {
+			t.Errorf("Expected run at 03:00, got %v", ran)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a run at 03:00")
+	}
+
+	// The next slot is a day later
+	waitForWaiter()
+	if status, _ := serverState.GetPlatformStatus("crontest"); !status.NextPruneTime.Equal(start.Add(24*time.Hour + 2*time.Minute)) {
+		t.Errorf("Expected next prune the following day, got %v", status.NextPruneTime)
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/gerrowadat/cringesweeper/internal/timespec"
+	"github.com/spf13/cobra"
+)
+
+// statsRecentMonths is how many months of posting frequency stats shows, ending with
+// the month of the newest post
+const statsRecentMonths = 12
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [username]",
+	Short: "Summarize a whole timeline and count what a prune would match",
+	Long: `Walk a user's entire timeline and report post counts by type, how often
+they post, average engagement and, given --max-post-age or --before-date, how
+many posts a prune with those criteria would match.
+
+Unlike analyze, stats always summarizes the whole timeline; the age criteria
+only decide the match count. It works like a prune dry run that reports
+numbers instead of listing every post. Nothing is ever deleted.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		maxAgeStr, _ := cmd.Flags().GetString("max-post-age")
+		beforeDateStr, _ := cmd.Flags().GetString("before-date")
+		afterDateStr, _ := cmd.Flags().GetString("after-date")
+		fromIndex, _ := cmd.Flags().GetBool("from-index")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial,twitter) or 'all'\n")
+			os.Exit(1)
+		}
+
+		platforms, err := internal.ParseReadablePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		var options internal.PruneOptions
+		if maxAgeStr != "" {
+			duration, err := timespec.ParseDuration(maxAgeStr)
+			if err != nil {
+				fmt.Printf("Error parsing max-post-age: %v\n", err)
+				os.Exit(1)
+			}
+			options.MaxAge = &duration
+		}
+		if beforeDateStr != "" {
+			date, err := timespec.ParseDate(beforeDateStr)
+			if err != nil {
+				fmt.Printf("Error parsing before-date: %v\n", err)
+				os.Exit(1)
+			}
+			options.BeforeDate = &date
+		}
+		if afterDateStr != "" {
+			date, err := timespec.ParseDate(afterDateStr)
+			if err != nil {
+				fmt.Printf("Error parsing after-date: %v\n", err)
+				os.Exit(1)
+			}
+			options.AfterDate = &date
+		}
+		if err := options.CheckDateRange(); err != nil {
+			exitWithError(err)
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		for i, platformName := range platforms {
+			if len(platforms) > 1 {
+				fmt.Printf("\n=== %s ===\n", strings.ToUpper(platformName))
+			}
+
+			username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+			if err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+
+			reader, exists := internal.GetReader(platformName)
+			if !exists {
+				fmt.Printf("Error: Unsupported platform '%s'. Supported platforms: %s\n",
+					platformName, strings.Join(internal.GetAllReadablePlatformNames(), ", "))
+				if len(platforms) > 1 {
+					continue
+				}
+				os.Exit(1)
+			}
+			if fromIndex {
+				reader, err = indexReader(platformName, username)
+				if err != nil {
+					presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+					if len(platforms) > 1 {
+						continue
+					}
+					os.Exit(1)
+				}
+			}
+
+			fmt.Printf("🔍 Reading posts from %s...\n", reader.GetPlatformName())
+			var all []internal.Post
+			matched := 0
+			err = walkTimeline(ctx, reader, username, func(posts []internal.Post) {
+				now := clock.Now()
+				for _, post := range posts {
+					all = append(all, post)
+					if matchesAnalyzeFilters(post, options, now) {
+						matched++
+					}
+				}
+			})
+			if err != nil {
+				fmt.Printf("Error fetching posts from %s: %v\n", reader.GetPlatformName(), err)
+			}
+
+			fmt.Println()
+			if err := displayTimelineStats(os.Stdout, internal.AnalyzePosts(all, 0), matched, describeAgeCriteria(options), reader.GetPlatformName()); err != nil {
+				exitWithError(err)
+			}
+
+			if len(platforms) > 1 && i < len(platforms)-1 {
+				fmt.Println()
+			}
+		}
+	},
+}
+
+// describeAgeCriteria says which posts the age options select, or "" when there are none
+func describeAgeCriteria(options internal.PruneOptions) string {
+	var criteria []string
+	if options.MaxAge != nil {
+		criteria = append(criteria, "older than "+timespec.Format(*options.MaxAge))
+	}
+	if options.BeforeDate != nil {
+		criteria = append(criteria, "before "+options.BeforeDate.Format("2006-01-02"))
+	}
+	description := strings.Join(criteria, " or ")
+	if options.AfterDate != nil {
+		if description != "" {
+			description += ", "
+		}
+		description += "from " + options.AfterDate.Format("2006-01-02") + " on"
+	}
+	return description
+}
+
+func displayTimelineStats(w io.Writer, stats internal.PostStats, matched int, criteria string, platform string) error {
+	out := newDisplayWriter(w)
+	w = out
+	if stats.Total == 0 {
+		fmt.Fprintf(w, "No posts found on %s\n", platform)
+		return out.err
+	}
+
+	fmt.Fprintf(w, "📊 Timeline stats for %s:\n\n", platform)
+	fmt.Fprintf(w, "  Total posts: %d\n", stats.Total)
+	fmt.Fprintf(w, "  Date range: %s to %s\n", stats.Oldest.Format("2006-01-02"), stats.Newest.Format("2006-01-02"))
+
+	fmt.Fprintf(w, "\n  By type:\n")
+	for _, postType := range []internal.PostType{
+		internal.PostTypeOriginal, internal.PostTypeReply, internal.PostTypeRepost,
+		internal.PostTypeQuote, internal.PostTypeLike,
+	} {
+		if count := stats.ByType[postType]; count > 0 {
+			fmt.Fprintf(w, "    %-9s %d\n", postType, count)
+		}
+	}
+
+	// Anything under a week is treated as a week, so a single post isn't "168 a week"
+	weeks := max(stats.Newest.Sub(stats.Oldest).Hours()/(7*24), 1)
+	fmt.Fprintf(w, "\n  Posting frequency: %.1f posts/week\n", float64(stats.Total)/weeks)
+	oldestMonth := time.Date(stats.Oldest.Year(), stats.Oldest.Month(), 1, 0, 0, 0, 0, time.UTC)
+	newestMonth := time.Date(stats.Newest.Year(), stats.Newest.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := statsRecentMonths - 1; i >= 0; i-- {
+		month := newestMonth.AddDate(0, -i, 0)
+		if month.Before(oldestMonth) {
+			continue
+		}
+		fmt.Fprintf(w, "    %s   %d\n", month.Format("2006-01"), stats.ByMonth[month.Format("2006-01")])
+	}
+
+	total := float64(stats.Total)
+	fmt.Fprintf(w, "\n  Average engagement: %.1f likes, %.1f reposts, %.1f replies per post\n",
+		float64(stats.Likes)/total, float64(stats.Reposts)/total, float64(stats.Replies)/total)
+
+	if criteria != "" {
+		fmt.Fprintf(w, "\n  Matching posts (%s): %d of %d (%.1f%%)\n", criteria, matched, stats.Total, 100*float64(matched)/total)
+	}
+	return out.err
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial,twitter) or 'all' for all live platforms")
+	statsCmd.Flags().String("max-post-age", "", "Count posts older than this (e.g., 30d, 1y, 24h)")
+	statsCmd.Flags().String("before-date", "", "Count posts created before this date (YYYY-MM-DD or MM/DD/YYYY)")
+	statsCmd.Flags().String("after-date", "", "Only count posts created on or after this date (YYYY-MM-DD or MM/DD/YYYY)")
+	statsCmd.Flags().Bool("from-index", false, "Read posts from the local index kept by 'cringesweeper sync' instead of fetching them")
+}
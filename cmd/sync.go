@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [username]",
+	Short: "Keep a local copy of your timeline for fast listing and pruning",
+	Long: `Copy an account's timeline into a local index under
+~/.config/cringesweeper/index, so ls, stats and prune can read it with
+--from-index instead of walking the timeline over the network.
+
+The first sync walks the whole timeline. Later ones only fetch what was posted
+since, stopping at the first page they already have. Use --full to walk the
+whole timeline again, which also drops posts that have since been deleted
+elsewhere and refreshes engagement counts.
+
+With --from-index, prune still makes its deletions over the network, and on
+Bluesky still lists likes and repost records from the platform.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+		full, _ := cmd.Flags().GetBool("full")
+
+		if platformsStr == "" {
+			fmt.Printf("Error: --platforms flag is required. Specify comma-separated platforms (bluesky,mastodon,gotosocial) or 'all'\n")
+			os.Exit(1)
+		}
+		platforms, err := internal.ParsePlatforms(platformsStr)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		argUsername := ""
+		if len(args) > 0 {
+			argUsername = args[0]
+		}
+
+		for _, platformName := range platforms {
+			if err := runSync(cmd, platformName, argUsername, full); err != nil {
+				presentError(os.Stdout, fmt.Errorf("%s: %w", platformName, err))
+				if len(platforms) == 1 {
+					os.Exit(1)
+				}
+			}
+		}
+	},
+}
+
+// runSync brings one platform's local post index up to date
+func runSync(cmd *cobra.Command, platformName, argUsername string, full bool) error {
+	w := cmd.OutOrStdout()
+
+	username, err := internal.GetUsernameForPlatform(platformName, argUsername)
+	if err != nil {
+		return err
+	}
+	reader, exists := internal.GetReader(platformName)
+	if !exists {
+		return fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", platformName, strings.Join(internal.GetAllPlatformNames(), ", "))
+	}
+	store := internal.DefaultPostIndexStore()
+	if store == nil {
+		return fmt.Errorf("the local post index is unavailable")
+	}
+
+	index, err := store.Load(platformName, username)
+	if errors.Is(err, internal.ErrNoPostIndex) {
+		index = &internal.PostIndex{Platform: platformName, Username: username}
+		full = true
+	} else if err != nil {
+		return err
+	}
+
+	if full {
+		fmt.Fprintf(w, "🔄 Syncing the whole %s timeline for %s...\n", reader.GetPlatformName(), username)
+	} else {
+		fmt.Fprintf(w, "🔄 Syncing new %s posts for %s since %s...\n", reader.GetPlatformName(), username, index.SyncedAt.Format("2006-01-02 15:04"))
+	}
+	added, err := internal.SyncPostIndex(cmd.Context(), reader, index, full, clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sync posts: %w", err)
+	}
+	if err := store.Save(index); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "✅ %d new posts, %d in the index\n", added, len(index.Posts))
+	return nil
+}
+
+// indexReader returns a reader over an account's local post index, for --from-index
+func indexReader(platformName, username string) (internal.PostReader, error) {
+	store := internal.DefaultPostIndexStore()
+	if store == nil {
+		return nil, fmt.Errorf("the local post index is unavailable")
+	}
+	index, err := store.Load(platformName, username)
+	if err != nil {
+		return nil, err
+	}
+	name := platformName
+	if reader, exists := internal.GetReader(platformName); exists {
+		name = reader.GetPlatformName()
+	}
+	return internal.NewIndexReader(index, name+" (local index)"), nil
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all' for all platforms")
+	syncCmd.Flags().Bool("full", false, "Walk the whole timeline again instead of only fetching new posts")
+}
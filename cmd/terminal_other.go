@@ -0,0 +1,13 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package cmd
+
+import (
+	"errors"
+	"runtime"
+)
+
+// makeRaw can't switch terminals to raw mode on this platform
+func makeRaw(fd int) (restore func() error, err error) {
+	return nil, errors.New("raw terminal mode isn't supported on " + runtime.GOOS)
+}
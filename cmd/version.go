@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/gerrowadat/cringesweeper/internal"
 	"github.com/spf13/cobra"
@@ -12,14 +13,21 @@ var versionCmd = &cobra.Command{
 	Short: "Show version information",
 	Long:  `Display version, commit, and build information for CringeSweeper.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		versionInfo := internal.GetFullVersionInfo()
-		
-		fmt.Printf("CringeSweeper %s\n", versionInfo["version"])
-		fmt.Printf("Commit: %s\n", versionInfo["commit"])
-		fmt.Printf("Build Time: %s\n", versionInfo["build_time"])
+		if err := displayVersion(cmd.OutOrStdout(), internal.GetFullVersionInfo()); err != nil {
+			exitWithError(err)
+		}
 	},
 }
 
+func displayVersion(w io.Writer, versionInfo map[string]string) error {
+	out := newDisplayWriter(w)
+	w = out
+	fmt.Fprintf(w, "CringeSweeper %s\n", versionInfo["version"])
+	fmt.Fprintf(w, "Commit: %s\n", versionInfo["commit"])
+	fmt.Fprintf(w, "Build Time: %s\n", versionInfo["build_time"])
+	return out.err
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
\ No newline at end of file
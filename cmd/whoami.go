@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gerrowadat/cringesweeper/internal"
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the account each platform's credentials log in to",
+	Long: `Log in to each platform with the active credentials and show the account the
+platform says they belong to: the handle and DID on Bluesky, or the account and
+its ID on Mastodon and GoToSocial.
+
+This catches credentials saved for one username that actually log in to
+another, such as an app password created on a second account or a handle that
+has since changed, before a prune acts on the wrong one.
+
+Without --platforms, every platform with credentials set up is checked. Exits
+with an error if any check fails or finds a mismatch.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		platformsStr, _ := cmd.Flags().GetString("platforms")
+
+		platforms := internal.GetAllPlatformNames()
+		if platformsStr != "" {
+			var err error
+			platforms, err = internal.ParsePlatforms(platformsStr)
+			if err != nil {
+				exitWithError(err)
+			}
+		}
+
+		w := cmd.OutOrStdout()
+		checked, ok := showWhoamiAll(cmd.Context(), w, platforms, platformsStr == "")
+		if checked == 0 {
+			fmt.Fprintln(w, "No credentials are set up for any platform")
+			fmt.Fprintln(w, "Run 'cringesweeper auth --platforms=<platform>' to set up authentication")
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+// showWhoamiAll shows the account each platform's credentials log in to, skipping
+// platforms without credentials when they weren't asked for by name. It returns how
+// many platforms it checked and whether they all matched.
+func showWhoamiAll(ctx context.Context, w io.Writer, platforms []string, skipUnconfigured bool) (int, bool) {
+	checked, ok := 0, true
+	for _, platformName := range platforms {
+		if _, err := internal.GetCredentialsForPlatform(platformName); err != nil && skipUnconfigured {
+			continue
+		}
+		if checked > 0 {
+			fmt.Fprintln(w)
+		}
+		checked++
+		if !showWhoami(ctx, w, platformName) {
+			ok = false
+		}
+	}
+	return checked, ok
+}
+
+// verifyAccount logs in to a platform with its active credentials and returns the
+// account they belong to
+func verifyAccount(ctx context.Context, platform string) (*internal.AccountInfo, error) {
+	client, exists := internal.GetClient(platform)
+	if !exists {
+		return nil, fmt.Errorf("unsupported platform '%s'", platform)
+	}
+	verifier, ok := client.(internal.AccountVerifier)
+	if !ok {
+		return nil, fmt.Errorf("%s can't look up the authenticated account", platform)
+	}
+	return verifier.VerifyAccount(ctx)
+}
+
+// showWhoami shows the account a platform's credentials log in to, reporting whether
+// it's the one they were set up for
+func showWhoami(ctx context.Context, w io.Writer, platform string) bool {
+	fmt.Fprintf(w, "%s:\n", platform)
+
+	account, err := verifyAccount(ctx, platform)
+	if err != nil {
+		presentError(w, fmt.Errorf("%s: %w", platform, err))
+		return false
+	}
+
+	idLabel := "Account ID"
+	if platform == "bluesky" {
+		idLabel = "DID"
+	}
+	fmt.Fprintf(w, "   Account: %s\n", account.Username)
+	fmt.Fprintf(w, "   %s: %s\n", idLabel, account.ID)
+
+	if !account.MatchesCredentials {
+		fmt.Fprintf(w, "⚠️  The credentials were set up for %s, but log in to %s\n", account.CredentialsUsername, account.Username)
+		fmt.Fprintf(w, "   Run 'cringesweeper auth --platforms=%s' to log in to the account you mean to use\n", platform)
+		return false
+	}
+	return true
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+	whoamiCmd.Flags().String("platforms", "", "Comma-separated list of platforms (bluesky,mastodon,gotosocial) or 'all'; defaults to every platform with credentials set up")
+}
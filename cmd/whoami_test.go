@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestShowWhoami(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer alice-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id": "109", "username": "alice", "acct": "alice"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+
+	tests := []struct {
+		name   string
+		user   string
+		token  string
+		wantOK bool
+		want   []string
+	}{
+		{"own account", "alice", "alice-token", true, []string{"mastodon:", "   Account: alice@", "   Account ID: 109"}},
+		{"token for another account", "bob", "alice-token", false, []string{"   Account ID: 109", "⚠️  The credentials were set up for bob, but log in to alice@"}},
+		{"revoked token", "alice", "revoked-token", false, []string{"❌", "401"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MASTODON_USER", tt.user)
+			t.Setenv("MASTODON_ACCESS_TOKEN", tt.token)
+
+			var buf bytes.Buffer
+			if ok := showWhoami(context.Background(), &buf, "mastodon"); ok != tt.wantOK {
+				t.Errorf("showWhoami() = %v, want %v", ok, tt.wantOK)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestShowWhoamiAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "109", "username": "alice", "acct": "alice"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLUESKY_USER", "")
+	t.Setenv("BLUESKY_PASSWORD", "")
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+	t.Setenv("MASTODON_USER", "alice")
+	t.Setenv("MASTODON_ACCESS_TOKEN", "alice-token")
+
+	// Platforms without credentials are skipped unless asked for by name
+	var buf bytes.Buffer
+	checked, ok := showWhoamiAll(context.Background(), &buf, []string{"bluesky", "mastodon"}, true)
+	if checked != 1 || !ok {
+		t.Errorf("showWhoamiAll() = %d, %v, want 1, true", checked, ok)
+	}
+	if output := buf.String(); strings.Contains(output, "bluesky") || !strings.Contains(output, "   Account ID: 109") {
+		t.Errorf("Expected only mastodon to be shown, got:\n%s", output)
+	}
+
+	buf.Reset()
+	checked, ok = showWhoamiAll(context.Background(), &buf, []string{"bluesky", "mastodon"}, false)
+	if checked != 2 || ok {
+		t.Errorf("showWhoamiAll() = %d, %v, want 2, false", checked, ok)
+	}
+	if output := buf.String(); !strings.Contains(output, "bluesky:\n❌") {
+		t.Errorf("Expected the unconfigured bluesky check to fail, got:\n%s", output)
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+import "time"
+
+// AccountStats describes what an account has left once a prune run is over: how much of
+// it there is, how old the oldest of it is, and how much the run's criteria still pick
+// out, such as posts a limit or an error left behind. Graphed over time, it shows the
+// backlog shrinking. Apart from Total, it only covers the posts the run fetched, which
+// is the whole account only when the run paged through all of it.
+type AccountStats struct {
+	Remaining  map[PostType]int `json:"remaining"`             // Posts, reposts and likes seen this run and left, by type
+	Matching   int              `json:"matching"`              // Of those, the ones the run would act on
+	OldestPost time.Time        `json:"oldest_post,omitempty"` // When the oldest post or repost seen this run and left was made
+	Total      int              `json:"total"`                 // Posts the platform reports on the account in all, -1 if it doesn't report a count
+}
+
+// RemainingPosts returns how many posts, reposts and likes are left in all
+func (s *AccountStats) RemainingPosts() int {
+	total := 0
+	for _, count := range s.Remaining {
+		total += count
+	}
+	return total
+}
+
+// accountStats works out what's left of posts after a run, given the IDs it acted on.
+// Posts an earlier run deleted that still linger in the listings aren't counted.
+func (o PruneOptions) accountStats(platform string, posts []Post, acted map[string]bool, now time.Time) *AccountStats {
+	stats := &AccountStats{Remaining: make(map[PostType]int), Total: -1}
+	for _, post := range posts {
+		if acted[post.ID] || wasDeleted(platform, post.ID) {
+			continue
+		}
+		stats.Remaining[post.Type]++
+		// A like is dated by the like or the liked post, neither of which is the account's
+		if post.Type != PostTypeLike && (stats.OldestPost.IsZero() || post.CreatedAt.Before(stats.OldestPost)) {
+			stats.OldestPost = post.CreatedAt
+		}
+		if selected, preserveReason := o.selectForPrune(platform, post, now); selected && preserveReason == "" && o.ActionFor(post) != "" {
+			stats.Matching++
+		}
+	}
+	return stats
+}
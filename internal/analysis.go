@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// HashtagCount is the number of posts carrying a hashtag
+type HashtagCount struct {
+	Hashtag string
+	Count   int
+}
+
+// PostStats summarizes a set of posts for the analyze and stats commands
+type PostStats struct {
+	Total       int
+	ByType      map[PostType]int
+	ByYear      map[int]int
+	ByMonth     map[string]int // Keyed by "2006-01"
+	WithMedia   int
+	Likes       int
+	Reposts     int
+	Replies     int
+	TopHashtags []HashtagCount // Most used first, ties broken alphabetically
+	Oldest      time.Time
+	Newest      time.Time
+	MostLiked   *Post
+}
+
+// AnalyzePosts computes statistics over posts, keeping at most topHashtags hashtags
+func AnalyzePosts(posts []Post, topHashtags int) PostStats {
+	stats := PostStats{
+		ByType:  make(map[PostType]int),
+		ByYear:  make(map[int]int),
+		ByMonth: make(map[string]int),
+	}
+
+	hashtagCounts := make(map[string]int)
+	for i := range posts {
+		post := &posts[i]
+		stats.Total++
+		stats.ByType[post.Type]++
+		stats.ByYear[post.CreatedAt.Year()]++
+		stats.ByMonth[post.CreatedAt.Format("2006-01")]++
+		if post.HasMedia() {
+			stats.WithMedia++
+		}
+		stats.Likes += post.LikeCount
+		stats.Reposts += post.RepostCount
+		stats.Replies += post.ReplyCount
+
+		// Count each hashtag once per post, however it was capitalized
+		seen := make(map[string]bool)
+		for _, tag := range post.Hashtags {
+			tag = NormalizeHashtag(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			hashtagCounts[tag]++
+		}
+
+		if stats.Oldest.IsZero() || post.CreatedAt.Before(stats.Oldest) {
+			stats.Oldest = post.CreatedAt
+		}
+		if post.CreatedAt.After(stats.Newest) {
+			stats.Newest = post.CreatedAt
+		}
+		if post.Type != PostTypeRepost && post.LikeCount > 0 &&
+			(stats.MostLiked == nil || post.LikeCount > stats.MostLiked.LikeCount) {
+			stats.MostLiked = post
+		}
+	}
+
+	for tag, count := range hashtagCounts {
+		stats.TopHashtags = append(stats.TopHashtags, HashtagCount{Hashtag: tag, Count: count})
+	}
+	sort.Slice(stats.TopHashtags, func(i, j int) bool {
+		if stats.TopHashtags[i].Count != stats.TopHashtags[j].Count {
+			return stats.TopHashtags[i].Count > stats.TopHashtags[j].Count
+		}
+		return stats.TopHashtags[i].Hashtag < stats.TopHashtags[j].Hashtag
+	})
+	if topHashtags >= 0 && len(stats.TopHashtags) > topHashtags {
+		stats.TopHashtags = stats.TopHashtags[:topHashtags]
+	}
+
+	return stats
+}
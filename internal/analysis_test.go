@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzePosts(t *testing.T) {
+	posts := []Post{
+		{ID: "1", Type: PostTypeOriginal, CreatedAt: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), LikeCount: 3, Hashtags: []string{"Go", "go"}},
+		{ID: "2", Type: PostTypeReply, CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), ReplyCount: 2, Hashtags: []string{"rust"}},
+		{ID: "3", Type: PostTypeRepost, CreatedAt: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), LikeCount: 50},
+		{ID: "4", Type: PostTypeOriginal, CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), LikeCount: 9, RepostCount: 1,
+			Hashtags: []string{"#go"}, Attachments: []Attachment{{Type: "image"}}},
+	}
+
+	stats := AnalyzePosts(posts, 10)
+
+	if stats.Total != 4 {
+		t.Errorf("Expected 4 posts, got %d", stats.Total)
+	}
+	if stats.ByType[PostTypeOriginal] != 2 || stats.ByType[PostTypeReply] != 1 || stats.ByType[PostTypeRepost] != 1 {
+		t.Errorf("Unexpected type counts %v", stats.ByType)
+	}
+	if stats.ByYear[2023] != 2 || stats.ByYear[2021] != 1 || stats.ByYear[2024] != 1 {
+		t.Errorf("Unexpected year counts %v", stats.ByYear)
+	}
+	if stats.ByMonth["2023-05"] != 1 || stats.ByMonth["2023-01"] != 1 || len(stats.ByMonth) != 4 {
+		t.Errorf("Unexpected month counts %v", stats.ByMonth)
+	}
+	if stats.WithMedia != 1 {
+		t.Errorf("Expected 1 post with media, got %d", stats.WithMedia)
+	}
+	if stats.Likes != 62 || stats.Reposts != 1 || stats.Replies != 2 {
+		t.Errorf("Unexpected engagement %d/%d/%d", stats.Likes, stats.Reposts, stats.Replies)
+	}
+	if !stats.Oldest.Equal(posts[2].CreatedAt) || !stats.Newest.Equal(posts[3].CreatedAt) {
+		t.Errorf("Unexpected range %v to %v", stats.Oldest, stats.Newest)
+	}
+	// Reposts are someone else's content, so they don't count as most liked
+	if stats.MostLiked == nil || stats.MostLiked.ID != "4" {
+		t.Errorf("Expected post 4 to be most liked, got %+v", stats.MostLiked)
+	}
+
+	// "Go" and "go" on the same post count once
+	want := []HashtagCount{{Hashtag: "go", Count: 2}, {Hashtag: "rust", Count: 1}}
+	if len(stats.TopHashtags) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, stats.TopHashtags)
+	}
+	for i := range want {
+		if stats.TopHashtags[i] != want[i] {
+			t.Errorf("Hashtag %d: expected %v, got %v", i, want[i], stats.TopHashtags[i])
+		}
+	}
+
+	if limited := AnalyzePosts(posts, 1); len(limited.TopHashtags) != 1 {
+		t.Errorf("Expected top hashtags limited to 1, got %v", limited.TopHashtags)
+	}
+}
+
+func TestAnalyzePosts_Empty(t *testing.T) {
+	stats := AnalyzePosts(nil, 10)
+	if stats.Total != 0 || stats.MostLiked != nil || len(stats.TopHashtags) != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
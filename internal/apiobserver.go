@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIObserver is told about every attempt at a platform API request made with a context
+// carrying it, retries included. status is zero when the attempt got no response at
+// all, in which case header is nil.
+type APIObserver func(status int, header http.Header)
+
+type apiObserverKey struct{}
+
+// WithAPIObserver returns a context whose API requests are reported to observer
+func WithAPIObserver(ctx context.Context, observer APIObserver) context.Context {
+	return context.WithValue(ctx, apiObserverKey{}, observer)
+}
+
+// observeAPIResponse reports one request attempt to the observer attached to ctx, if any
+func observeAPIResponse(ctx context.Context, resp *http.Response) {
+	observer, _ := ctx.Value(apiObserverKey{}).(APIObserver)
+	if observer == nil {
+		return
+	}
+	if resp == nil {
+		// Cancelled requests are our doing, not the platform's
+		if ctx.Err() != nil {
+			return
+		}
+		observer(0, nil)
+		return
+	}
+	observer(resp.StatusCode, resp.Header)
+}
+
+// RateLimit is the rate limit state a platform reported in a response's headers
+type RateLimit struct {
+	Limit     int       // Requests allowed in the current window
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the window resets, zero if not given
+}
+
+// ParseRateLimit reads the rate limit headers from a response. Mastodon sends
+// X-RateLimit-* with an ISO 8601 reset time, Bluesky sends RateLimit-* with the reset
+// as Unix seconds. ok is false when there's no usable remaining count.
+func ParseRateLimit(header http.Header) (limit RateLimit, ok bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+		limit.Remaining = remaining
+		limit.Limit, _ = strconv.Atoi(header.Get(prefix + "Limit"))
+
+		reset := header.Get(prefix + "Reset")
+		if t, err := time.Parse(time.RFC3339, reset); err == nil {
+			limit.Reset = t
+		} else if seconds, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			limit.Reset = time.Unix(seconds, 0)
+		}
+		return limit, true
+	}
+	return RateLimit{}, false
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   RateLimit
+		wantOK bool
+	}{
+		{
+			name: "mastodon",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"300"},
+				"X-Ratelimit-Remaining": {"297"},
+				"X-Ratelimit-Reset":     {"2025-01-15T02:05:00.000Z"},
+			},
+			want:   RateLimit{Limit: 300, Remaining: 297, Reset: time.Date(2025, 1, 15, 2, 5, 0, 0, time.UTC)},
+			wantOK: true,
+		},
+		{
+			name: "bluesky",
+			header: http.Header{
+				"Ratelimit-Limit":     {"5000"},
+				"Ratelimit-Remaining": {"4990"},
+				"Ratelimit-Reset":     {"1736906700"},
+				"Ratelimit-Policy":    {"5000;w=3600"},
+			},
+			want:   RateLimit{Limit: 5000, Remaining: 4990, Reset: time.Unix(1736906700, 0)},
+			wantOK: true,
+		},
+		{
+			name:   "remaining only",
+			header: http.Header{"X-Ratelimit-Remaining": {"0"}},
+			want:   RateLimit{Remaining: 0},
+			wantOK: true,
+		},
+		{
+			name:   "no headers",
+			header: http.Header{},
+		},
+		{
+			name:   "unparseable remaining",
+			header: http.Header{"Ratelimit-Remaining": {"lots"}},
+		},
+		{
+			name: "nil header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRateLimit(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRateLimit() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining || !got.Reset.Equal(tt.want.Reset) {
+				t.Errorf("ParseRateLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIObserver_SeesEveryAttempt(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("RateLimit-Remaining", "10")
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var statuses []int
+	ctx := WithAPIObserver(context.Background(), func(status int, header http.Header) {
+		mu.Lock()
+		defer mu.Unlock()
+		statuses = append(statuses, status)
+		if header.Get("RateLimit-Remaining") != "10" {
+			t.Errorf("Expected the response headers, got %v", header)
+		}
+	})
+
+	resp, err := httpGetWithRetry(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !slices.Equal(statuses, []int{http.StatusServiceUnavailable, http.StatusOK}) {
+		t.Errorf("Expected the retried 503 and the final 200 to be observed, got %v", statuses)
+	}
+}
+
+func TestAPIObserver_NetworkErrors(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 0})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var statuses []int
+	observer := func(status int, header http.Header) { statuses = append(statuses, status) }
+
+	if _, err := httpGetWithRetry(WithAPIObserver(context.Background(), observer), url); err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if !slices.Equal(statuses, []int{0}) {
+		t.Errorf("Expected a network failure to be observed as status 0, got %v", statuses)
+	}
+
+	// Requests we cancelled ourselves aren't the platform's failures
+	statuses = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	httpGetWithRetry(WithAPIObserver(ctx, observer), url)
+	if len(statuses) != 0 {
+		t.Errorf("Expected cancelled requests not to be observed, got %v", statuses)
+	}
+}
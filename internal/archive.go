@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchivedPost is a post saved to a PostArchive before it was deleted or redacted
+type ArchivedPost struct {
+	Post       Post      `json:"post"`
+	Action     string    `json:"action"` // "delete" or "redact"
+	ArchivedAt time.Time `json:"archived_at"`
+	RestoredID string    `json:"restored_id,omitempty"` // ID of the post restore made from this one, once it has
+	RestoredAt time.Time `json:"restored_at,omitempty"`
+}
+
+// PostArchive keeps a copy of each post a prune deletes or redacts, so restore can post it
+// again. Each platform gets its own directory, with one JSON file per post:
+//
+//	<dir>/<platform>/<post ID, with anything but letters, digits, '.', '_' and '-' as '_'>.json
+type PostArchive struct {
+	dir string
+}
+
+// NewPostArchiveAt creates a post archive rooted at the given directory
+func NewPostArchiveAt(dir string) *PostArchive {
+	return &PostArchive{dir: dir}
+}
+
+var archiveFileUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+func (a *PostArchive) path(platform, id string) string {
+	return filepath.Join(a.dir, strings.ToLower(platform), archiveFileUnsafe.ReplaceAllString(id, "_")+".json")
+}
+
+// Save writes post to the archive before action is taken on it. A post archived before
+// is overwritten, so it's the latest copy that gets restored.
+func (a *PostArchive) Save(platform, action string, post Post, now time.Time) error {
+	if post.ID == "" {
+		return fmt.Errorf("post ID is required")
+	}
+	return a.write(platform, &ArchivedPost{Post: post, Action: action, ArchivedAt: now.UTC()})
+}
+
+// MarkRestored records that entry was posted again as restoredID, so later restores skip it
+func (a *PostArchive) MarkRestored(platform string, entry *ArchivedPost, restoredID string, now time.Time) error {
+	entry.RestoredID = restoredID
+	entry.RestoredAt = now.UTC()
+	return a.write(platform, entry)
+}
+
+func (a *PostArchive) write(platform string, entry *ArchivedPost) error {
+	path := a.path(platform, entry.Post.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archived post: %w", err)
+	}
+
+	// Write to a temporary file and rename, so an interrupted write can't leave half a post
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write archived post: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save archived post: %w", err)
+	}
+	return nil
+}
+
+// Load returns a platform's archived posts, oldest post first. An archive with nothing
+// for the platform returns none.
+func (a *PostArchive) Load(platform string) ([]*ArchivedPost, error) {
+	dir := filepath.Join(a.dir, strings.ToLower(platform))
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
+	}
+
+	var entries []*ArchivedPost
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archived post: %w", err)
+		}
+		var entry ArchivedPost
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse archived post %s: %w", file.Name(), err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Post.CreatedAt.Before(entries[j].Post.CreatedAt)
+	})
+	return entries, nil
+}
+
+// archivePost saves post to options.Archive, when set, before a real delete or redact
+func (o PruneOptions) archivePost(platform, action string, post Post, now time.Time) error {
+	if o.Archive == nil || o.DryRun || (action != "delete" && action != "redact") {
+		return nil
+	}
+	if err := o.Archive.Save(platform, action, post, now); err != nil {
+		return fmt.Errorf("archiving post before %s: %w", action, err)
+	}
+	return nil
+}
+
+// PostRestorer is implemented by clients that can post archived posts again
+type PostRestorer interface {
+	// PostExists reports whether post is still on the platform. Returns ErrNotOwnAccount
+	// if username isn't the authenticated account.
+	PostExists(ctx context.Context, username string, post Post) (bool, error)
+
+	// RestorePost posts post's text again from username's account, as a reply to inReplyTo
+	// when that's set, and returns the new post's ID
+	RestorePost(ctx context.Context, username string, post Post, inReplyTo string) (string, error)
+}
+
+// RestoreOptions defines how archived posts are restored
+type RestoreOptions struct {
+	DryRun         bool                    // Only show what would be restored
+	RateLimitDelay time.Duration           // Delay between posts to respect rate limits
+	Confirm        func(posts []Post) bool // Asked with the posts about to be posted, before posting any (nil posts them right away)
+}
+
+// RestoreResult is the outcome of restoring one platform's archived posts
+type RestoreResult struct {
+	Examined        int      `json:"examined"`
+	Restored        []Post   `json:"restored"` // Posted again, or with DryRun would be
+	AlreadyRestored int      `json:"already_restored"`
+	StillExists     int      `json:"still_exists"` // Never deleted, or redacted and so still there
+	SkippedCount    int      `json:"skipped_count"`
+	Skipped         []string `json:"skipped"`
+	ErrorsCount     int      `json:"errors_count"`
+	Errors          []string `json:"errors"`
+	Warnings        []string `json:"warnings,omitempty"`
+	Declined        bool     `json:"declined,omitempty"` // Confirm said no, so nothing was posted
+}
+
+// restoreSkipReason returns why a post can't be restored, or "" if it can. Quotes and
+// reposts point at someone else's post, and posting a direct message again would notify
+// its recipients a second time.
+func restoreSkipReason(post Post) string {
+	switch {
+	case post.Type != PostTypeOriginal && post.Type != PostTypeReply:
+		return fmt.Sprintf("%s posts aren't restored", post.Type)
+	case post.Visibility == VisibilityDirect:
+		return "direct messages aren't restored"
+	default:
+		return ""
+	}
+}
+
+// RestoreArchive posts a platform's archived posts again, oldest first, skipping ones that
+// were restored before or are still on the platform. Replies go to the restored copy of
+// their parent when there is one, and to the original parent otherwise. Every post is
+// checked before options.Confirm is asked, and nothing is posted if it declines. If ctx
+// is cancelled, the partial result is returned along with ctx.Err().
+func RestoreArchive(ctx context.Context, restorer PostRestorer, archive *PostArchive, platform, username string, options RestoreOptions, clock Clock) (*RestoreResult, error) {
+	entries, err := archive.Load(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{Examined: len(entries), Restored: []Post{}, Skipped: []string{}, Errors: []string{}}
+	restoredIDs := make(map[string]string)
+	for _, entry := range entries {
+		if entry.RestoredID != "" {
+			restoredIDs[entry.Post.ID] = entry.RestoredID
+		}
+	}
+
+	var pending []*ArchivedPost
+	for _, entry := range entries {
+		post := entry.Post
+		if entry.RestoredID != "" {
+			result.AlreadyRestored++
+			continue
+		}
+		if reason := restoreSkipReason(post); reason != "" {
+			result.SkippedCount++
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %s", post.ID, reason))
+			continue
+		}
+
+		exists, err := restorer.PostExists(ctx, username, post)
+		if err != nil {
+			if errors.Is(err, ErrNotOwnAccount) {
+				return result, err
+			}
+			WithPlatform(platform).Error().Err(err).Str("post_id", post.ID).Msg("Failed to check whether archived post still exists")
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to check post %s: %v", post.ID, err))
+			result.ErrorsCount++
+			continue
+		}
+		if exists {
+			result.StillExists++
+			continue
+		}
+		if post.HasMedia() {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Post %s had %d media attachment(s), which aren't archived and won't be restored", post.ID, len(post.Attachments)))
+		}
+		pending = append(pending, entry)
+	}
+
+	if options.DryRun {
+		for _, entry := range pending {
+			result.Restored = append(result.Restored, entry.Post)
+		}
+		return result, nil
+	}
+	if len(pending) > 0 && options.Confirm != nil {
+		posts := make([]Post, len(pending))
+		for i, entry := range pending {
+			posts[i] = entry.Post
+		}
+		if !options.Confirm(posts) {
+			result.Declined = true
+			return result, nil
+		}
+	}
+
+	for _, entry := range pending {
+		post := entry.Post
+		logger := WithPlatform(platform).With().Str("post_id", post.ID).Logger()
+		inReplyTo := ""
+		if post.Type == PostTypeReply {
+			inReplyTo = post.InReplyToID
+			if restoredID, ok := restoredIDs[post.InReplyToID]; ok {
+				inReplyTo = restoredID
+			}
+		}
+
+		if err := sleepContext(ctx, options.RateLimitDelay); err != nil {
+			return result, err
+		}
+		restoredID, err := restorer.RestorePost(ctx, username, post, inReplyTo)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to restore post")
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to restore post %s: %v", post.ID, err))
+			result.ErrorsCount++
+			continue
+		}
+		logger.Info().Str("restored_id", restoredID).Msg("Post restored")
+		restoredIDs[post.ID] = restoredID
+		result.Restored = append(result.Restored, post)
+
+		// Without the mark, the next restore would post it a second time
+		if err := archive.MarkRestored(platform, entry, restoredID, clock.Now()); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Restored post %s as %s but couldn't mark it restored, so restoring again would post it twice: %v", post.ID, restoredID, err))
+			result.ErrorsCount++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostArchive(t *testing.T) {
+	archive := NewPostArchiveAt(t.TempDir())
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	newer := Post{ID: "at://did:plc:me/app.bsky.feed.post/newer", Type: PostTypeOriginal, Content: "second", CreatedAt: now.Add(-time.Hour)}
+	older := Post{ID: "at://did:plc:me/app.bsky.feed.post/older", Type: PostTypeOriginal, Content: "first", CreatedAt: now.Add(-2 * time.Hour)}
+
+	if entries, err := archive.Load("bluesky"); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected an empty archive, got %v, %v", entries, err)
+	}
+	for _, post := range []Post{newer, older} {
+		if err := archive.Save("Bluesky", "delete", post, now); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := archive.Save("bluesky", "delete", Post{}, now); err == nil {
+		t.Error("Expected an error archiving a post without an ID")
+	}
+
+	entries, err := archive.Load("bluesky")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Post.Content != "first" || entries[1].Post.Content != "second" {
+		t.Fatalf("Expected both posts, oldest first, got %+v", entries)
+	}
+	if entries[0].Action != "delete" || !entries[0].ArchivedAt.Equal(now) {
+		t.Errorf("Unexpected archive entry: %+v", entries[0])
+	}
+
+	if err := archive.MarkRestored("bluesky", entries[0], "at://did:plc:me/app.bsky.feed.post/again", now); err != nil {
+		t.Fatalf("MarkRestored() error = %v", err)
+	}
+	entries, err = archive.Load("bluesky")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entries[0].RestoredID != "at://did:plc:me/app.bsky.feed.post/again" || entries[1].RestoredID != "" {
+		t.Errorf("Expected only the first post marked restored, got %+v", entries)
+	}
+}
+
+func TestPruneEngine_Archive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+	posts := []Post{
+		{ID: "archive-post", Type: PostTypeOriginal, Content: "gone soon", CreatedAt: old},
+		{ID: "archive-like", Type: PostTypeLike, CreatedAt: old},
+	}
+
+	t.Run("archives posts before deleting them", func(t *testing.T) {
+		withTombstoneStore(t)
+		archive := NewPostArchiveAt(t.TempDir())
+		actor := &fakePruneActor{}
+		options := PruneOptions{MaxAge: &maxAge, UnlikePosts: true, Archive: archive}
+		if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		entries, err := archive.Load("mastodon")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(entries) != 1 || entries[0].Post.Content != "gone soon" {
+			t.Errorf("Expected just the deleted post archived, got %+v", entries)
+		}
+		if len(actor.acted) != 2 {
+			t.Errorf("Expected the post deleted and the like undone, got %v", actor.acted)
+		}
+	})
+
+	t.Run("keeps posts it can't archive", func(t *testing.T) {
+		withTombstoneStore(t)
+		blocked := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(blocked, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		actor := &fakePruneActor{}
+		options := PruneOptions{MaxAge: &maxAge, UnlikePosts: true, Archive: NewPostArchiveAt(blocked)}
+		result := &PruneResult{}
+		if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !slices.Equal(actor.acted, []string{"unlike archive-like"}) {
+			t.Errorf("Expected only the like undone, got %v", actor.acted)
+		}
+		if result.DeletedCount != 0 || result.ErrorsCount != 1 || !strings.Contains(result.Errors[0], "archiving post") {
+			t.Errorf("Expected the unarchived post reported as an error, got %+v", result)
+		}
+	})
+
+	t.Run("dry run archives nothing", func(t *testing.T) {
+		withTombstoneStore(t)
+		dir := filepath.Join(t.TempDir(), "archive")
+		options := PruneOptions{MaxAge: &maxAge, DryRun: true, Archive: NewPostArchiveAt(dir)}
+		if err := NewPruneEngine("mastodon", &fakePruneActor{}, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected no archive for a dry run, got %v", err)
+		}
+	})
+}
+
+// fakePostRestorer restores posts as "new-<ID>", treating the ones in existing as still
+// there and failing the ones in fail
+type fakePostRestorer struct {
+	existing map[string]bool
+	fail     map[string]bool
+	restored []string
+}
+
+func (r *fakePostRestorer) PostExists(ctx context.Context, username string, post Post) (bool, error) {
+	return r.existing[post.ID], nil
+}
+
+func (r *fakePostRestorer) RestorePost(ctx context.Context, username string, post Post, inReplyTo string) (string, error) {
+	if r.fail[post.ID] {
+		return "", errors.New("server said no")
+	}
+	r.restored = append(r.restored, post.ID+">"+inReplyTo)
+	return "new-" + post.ID, nil
+}
+
+func TestRestoreArchive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	posts := []Post{
+		{ID: "reply", Type: PostTypeReply, InReplyToID: "root", CreatedAt: now.Add(-2 * day)},
+		{ID: "root", Type: PostTypeOriginal, CreatedAt: now.Add(-3 * day)},
+		{ID: "answer", Type: PostTypeReply, InReplyToID: "someone-else", CreatedAt: now.Add(-day)},
+		{ID: "quote", Type: PostTypeQuote, CreatedAt: now.Add(-day)},
+		{ID: "dm", Type: PostTypeOriginal, Visibility: VisibilityDirect, CreatedAt: now.Add(-day)},
+		{ID: "redacted", Type: PostTypeOriginal, CreatedAt: now.Add(-day)},
+		{ID: "broken", Type: PostTypeOriginal, CreatedAt: now.Add(-day)},
+		{ID: "photo", Type: PostTypeOriginal, Attachments: []Attachment{{}}, CreatedAt: now.Add(-day)},
+	}
+	newArchive := func(t *testing.T) *PostArchive {
+		archive := NewPostArchiveAt(t.TempDir())
+		for _, post := range posts {
+			if err := archive.Save("mastodon", "delete", post, now); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+		return archive
+	}
+	restorer := func() *fakePostRestorer {
+		return &fakePostRestorer{existing: map[string]bool{"redacted": true}, fail: map[string]bool{"broken": true}}
+	}
+
+	t.Run("dry run posts nothing", func(t *testing.T) {
+		fake := restorer()
+		result, err := RestoreArchive(context.Background(), fake, newArchive(t), "mastodon", "me", RestoreOptions{DryRun: true}, NewFakeClock(now))
+		if err != nil {
+			t.Fatalf("RestoreArchive() error = %v", err)
+		}
+		if len(fake.restored) != 0 || len(result.Restored) != 5 {
+			t.Errorf("Expected 5 posts listed and none posted, got %+v, posted %v", result, fake.restored)
+		}
+	})
+
+	t.Run("declining posts nothing", func(t *testing.T) {
+		fake := restorer()
+		var asked []Post
+		options := RestoreOptions{Confirm: func(posts []Post) bool {
+			asked = posts
+			return false
+		}}
+		archive := newArchive(t)
+		result, err := RestoreArchive(context.Background(), fake, archive, "mastodon", "me", options, NewFakeClock(now))
+		if err != nil {
+			t.Fatalf("RestoreArchive() error = %v", err)
+		}
+		if len(asked) != 5 || asked[0].ID != "root" {
+			t.Errorf("Expected to be asked about the 5 posts to restore, oldest first, got %v", asked)
+		}
+		if len(fake.restored) != 0 || len(result.Restored) != 0 || !result.Declined {
+			t.Errorf("Expected nothing posted, got %+v, posted %v", result, fake.restored)
+		}
+		if entries, _ := archive.Load("mastodon"); slices.ContainsFunc(entries, func(entry *ArchivedPost) bool { return entry.RestoredID != "" }) {
+			t.Error("Expected no post marked restored")
+		}
+	})
+
+	t.Run("restores oldest first and replies to restored parents", func(t *testing.T) {
+		fake := restorer()
+		archive := newArchive(t)
+		result, err := RestoreArchive(context.Background(), fake, archive, "mastodon", "me", RestoreOptions{}, NewFakeClock(now))
+		if err != nil {
+			t.Fatalf("RestoreArchive() error = %v", err)
+		}
+		if fake.restored[0] != "root>" || fake.restored[1] != "reply>new-root" || !slices.Contains(fake.restored, "answer>someone-else") {
+			t.Errorf("Expected the thread restored in order and the reply to someone else kept, got %v", fake.restored)
+		}
+		if len(result.Restored) != 4 || result.StillExists != 1 || result.SkippedCount != 2 || result.ErrorsCount != 1 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "photo") {
+			t.Errorf("Expected a warning about the photo's media, got %v", result.Warnings)
+		}
+
+		// Running again only retries the post that failed
+		fake = restorer()
+		delete(fake.fail, "broken")
+		result, err = RestoreArchive(context.Background(), fake, archive, "mastodon", "me", RestoreOptions{}, NewFakeClock(now))
+		if err != nil {
+			t.Fatalf("RestoreArchive() error = %v", err)
+		}
+		if !slices.Equal(fake.restored, []string{"broken>"}) || result.AlreadyRestored != 4 {
+			t.Errorf("Expected only the failed post restored again, got %v, %+v", fake.restored, result)
+		}
+	})
+}
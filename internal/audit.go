@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditLogOff is the audit log path that turns the audit log off
+const AuditLogOff = "off"
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailed  = "failed"
+)
+
+// AuditEntry is one action a prune run took on a post, successful or not
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Platform string    `json:"platform"`
+	Action   string    `json:"action"` // delete, redact, unlike or unshare
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+	Operator string    `json:"operator,omitempty"`
+	PostID   string    `json:"post_id"`
+	URL      string    `json:"url,omitempty"`
+	Post     Post      `json:"post"` // The post as it was before the action, so it could be put back
+}
+
+// AuditLog is an append-only JSON Lines file with an entry for every action taken on a
+// post. Where the tombstone store only remembers which IDs are gone, the audit log keeps
+// what they said, for people who need a record of what was removed and for restoring it.
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLogAt creates an audit log that appends to the file at path
+func NewAuditLogAt(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Path returns the file the audit log appends to
+func (l *AuditLog) Path() string {
+	return l.path
+}
+
+// Record appends an entry to the audit log
+func (l *AuditLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	// One write per entry, so concurrent runs can't interleave their lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+var (
+	auditLogPath     string // Set with SetAuditLogPath, empty for the default
+	defaultAudit     *AuditLog
+	defaultAuditOnce sync.Once
+)
+
+// SetAuditLogPath points the shared audit log at path instead of
+// ~/.config/cringesweeper/audit.jsonl, or turns it off if path is AuditLogOff. It must be
+// called before the first action is recorded.
+func SetAuditLogPath(path string) {
+	auditLogPath = path
+}
+
+// DefaultAuditLog returns the shared audit log, or nil if it's off or can't be created
+func DefaultAuditLog() *AuditLog {
+	defaultAuditOnce.Do(func() {
+		path := auditLogPath
+		if path == AuditLogOff {
+			return
+		}
+		if path == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				Logger.Warn().Err(err).Msg("Audit log unavailable")
+				return
+			}
+			path = filepath.Join(homeDir, ".config", "cringesweeper", "audit.jsonl")
+		}
+		defaultAudit = NewAuditLogAt(path)
+	})
+	return defaultAudit
+}
+
+// recordAudit appends an action on post taken at now to the default audit log, logging
+// rather than failing on errors
+func recordAudit(platform, action string, post Post, actionErr error, now time.Time) {
+	log := DefaultAuditLog()
+	if log == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:     now.UTC(),
+		Platform: platform,
+		Action:   action,
+		Outcome:  AuditOutcomeSuccess,
+		Operator: GetOperator(),
+		PostID:   post.ID,
+		URL:      post.URL,
+		Post:     post,
+	}
+	if actionErr != nil {
+		entry.Outcome = AuditOutcomeFailed
+		entry.Error = actionErr.Error()
+	}
+	if err := log.Record(entry); err != nil {
+		WithPlatform(platform).Warn().Err(err).Str("post_id", post.ID).Msg("Failed to record audit entry")
+	}
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// readAuditLog returns the entries in an audit log file
+func readAuditLog(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Audit log line %q isn't JSON: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLog_RecordAppends(t *testing.T) {
+	log := NewAuditLogAt(t.TempDir() + "/nested/audit.jsonl")
+	for _, id := range []string{"first", "second"} {
+		if err := log.Record(AuditEntry{Platform: "mastodon", Action: "delete", PostID: id}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	entries := readAuditLog(t, log.Path())
+	if len(entries) != 2 || entries[0].PostID != "first" || entries[1].PostID != "second" {
+		t.Errorf("Expected both entries in order, got %+v", entries)
+	}
+	if info, err := os.Stat(log.Path()); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the audit log to be private to the user, got %v (%v)", info.Mode(), err)
+	}
+}
+
+func TestPruneEngine_RecordsAudit(t *testing.T) {
+	withTombstoneStore(t)
+	log := withAuditLog(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+
+	posts := []Post{
+		{ID: "audit-post", Type: PostTypeOriginal, CreatedAt: old, Content: "regrettable take", URL: "https://example.social/@me/1"},
+		{ID: "audit-like", Type: PostTypeLike, CreatedAt: old},
+		{ID: "audit-broken", Type: PostTypeOriginal, CreatedAt: old},
+	}
+	options := PruneOptions{MaxAge: &maxAge, UnlikePosts: true}
+	actor := &fakePruneActor{fail: map[string]bool{"audit-broken": true}}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	entries := readAuditLog(t, log.Path())
+	if len(entries) != 3 {
+		t.Fatalf("Expected an entry per action, got %+v", entries)
+	}
+	deleted := entries[0]
+	if deleted.Action != "delete" || deleted.Outcome != AuditOutcomeSuccess || deleted.PostID != "audit-post" ||
+		deleted.URL != "https://example.social/@me/1" || deleted.Post.Content != "regrettable take" {
+		t.Errorf("Expected the deletion with a snapshot of the post, got %+v", deleted)
+	}
+	if entries[1].Action != "unlike" || entries[1].Outcome != AuditOutcomeSuccess {
+		t.Errorf("Expected the unlike, got %+v", entries[1])
+	}
+	if entries[2].Outcome != AuditOutcomeFailed || entries[2].Error != "server said no" {
+		t.Errorf("Expected the failed deletion with its error, got %+v", entries[2])
+	}
+
+	// Dry runs don't take any actions to record
+	options.DryRun = true
+	withTombstoneStore(t)
+	dryLog := withAuditLog(t)
+	if err := NewPruneEngine("mastodon", &fakePruneActor{}, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := os.Stat(dryLog.Path()); !os.IsNotExist(err) {
+		t.Errorf("Expected no audit log from a dry run, got %v", err)
+	}
+}
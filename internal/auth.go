@@ -5,18 +5,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Credentials stores authentication information for a platform
 type Credentials struct {
 	Platform    string            `json:"platform"`
 	Username    string            `json:"username"`
-	Instance    string            `json:"instance,omitempty"` // For Mastodon
+	Instance    string            `json:"instance,omitempty"` // For Mastodon, or a Bluesky PDS other than bsky.social
 	AccessToken string            `json:"access_token,omitempty"`
 	AppPassword string            `json:"app_password,omitempty"` // For Bluesky
 	ExtraData   map[string]string `json:"extra_data,omitempty"`
 }
 
+// UsesOAuth reports whether these are Bluesky credentials from an OAuth login rather
+// than an app password
+func (c *Credentials) UsesOAuth() bool {
+	return c.Platform == "bluesky" && c.ExtraData[oauthExtraAuthMethod] == "oauth"
+}
+
 // AuthManager handles credential storage and retrieval
 type AuthManager struct {
 	configDir string
@@ -104,23 +111,59 @@ func (am *AuthManager) ListPlatforms() ([]string, error) {
 	return platforms, nil
 }
 
-// GetCredentialsFromEnv retrieves credentials from environment variables
+// secretFileSuffix ends the name of a variable that holds the path of a file containing
+// a secret rather than the secret itself, as Docker and Kubernetes secrets are mounted:
+// BLUESKY_PASSWORD_FILE=/run/secrets/bluesky_password
+const secretFileSuffix = "_FILE"
+
+// GetSecretFromEnv returns the value of the environment variable name or, when that
+// isn't set, the contents of the file named by name_FILE, without the trailing newline
+func GetSecretFromEnv(name string) (string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+	path := os.Getenv(name + secretFileSuffix)
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s%s: %w", name, secretFileSuffix, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// envSecret returns a credential with GetSecretFromEnv, treating one whose file can't be
+// read as missing after logging why
+func envSecret(platform, name string) string {
+	value, err := GetSecretFromEnv(name)
+	if err != nil {
+		WithPlatform(platform).Warn().Err(err).Msg("Ignoring credential from the environment")
+	}
+	return value
+}
+
+// GetCredentialsFromEnv retrieves credentials from environment variables. The password
+// or access token can instead be read from a file named by the same variable with _FILE
+// on the end.
 func GetCredentialsFromEnv(platform string) *Credentials {
 	switch platform {
 	case "bluesky":
 		username := os.Getenv("BLUESKY_USER")
-		password := os.Getenv("BLUESKY_PASSWORD")
+		password := envSecret(platform, "BLUESKY_PASSWORD")
 		if username != "" && password != "" {
 			return &Credentials{
 				Platform:    platform,
 				Username:    username,
+				Instance:    os.Getenv(BlueskyPDSEnvVar),
 				AppPassword: password,
 			}
 		}
-	case "mastodon":
-		username := os.Getenv("MASTODON_USER")
-		instance := os.Getenv("MASTODON_INSTANCE")
-		token := os.Getenv("MASTODON_ACCESS_TOKEN")
+	case "mastodon", "gotosocial":
+		prefix := strings.ToUpper(platform)
+		username := os.Getenv(prefix + "_USER")
+		instance := os.Getenv(prefix + "_INSTANCE")
+		token := envSecret(platform, prefix+"_ACCESS_TOKEN")
 		if username != "" && instance != "" && token != "" {
 			return &Credentials{
 				Platform:    platform,
@@ -145,15 +188,23 @@ func ValidateCredentials(creds *Credentials) error {
 
 	switch creds.Platform {
 	case "bluesky":
-		if creds.AppPassword == "" {
+		if creds.UsesOAuth() {
+			if creds.AccessToken == "" || creds.ExtraData[oauthExtraRefreshToken] == "" {
+				return fmt.Errorf("OAuth tokens are required for Bluesky OAuth login")
+			}
+		} else if creds.AppPassword == "" {
 			return fmt.Errorf("app password is required for Bluesky")
 		}
-	case "mastodon":
+		if _, err := NormalizeBlueskyHost(creds.Instance); err != nil {
+			return fmt.Errorf("invalid Bluesky PDS: %w", err)
+		}
+	case "mastodon", "gotosocial":
+		name := mastodonFlavors[creds.Platform].name
 		if creds.Instance == "" {
-			return fmt.Errorf("instance is required for Mastodon")
+			return fmt.Errorf("instance is required for %s", name)
 		}
 		if creds.AccessToken == "" {
-			return fmt.Errorf("access token is required for Mastodon")
+			return fmt.Errorf("access token is required for %s", name)
 		}
 	default:
 		return fmt.Errorf("unsupported platform: %s", creds.Platform)
@@ -297,3 +297,116 @@ func TestCredentials_JSON_Serialization(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckCredentialMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		saved   *Credentials
+		env     map[string]string
+		wantEnv string // Expected ignored account, or "" for no mismatch
+	}{
+		{
+			name:    "bluesky different handles",
+			saved:   &Credentials{Platform: "bluesky", Username: "alice.bsky.social", AppPassword: "pw"},
+			env:     map[string]string{"BLUESKY_USER": "bob.bsky.social", "BLUESKY_PASSWORD": "pw"},
+			wantEnv: "bob.bsky.social",
+		},
+		{
+			name:  "bluesky same handle differently written",
+			saved: &Credentials{Platform: "bluesky", Username: "alice.bsky.social", AppPassword: "pw"},
+			env:   map[string]string{"BLUESKY_USER": "@Alice.bsky.social", "BLUESKY_PASSWORD": "other"},
+		},
+		{
+			name:  "no environment",
+			saved: &Credentials{Platform: "bluesky", Username: "alice.bsky.social", AppPassword: "pw"},
+		},
+		{
+			name: "no saved credentials",
+			env:  map[string]string{"BLUESKY_USER": "bob.bsky.social", "BLUESKY_PASSWORD": "pw"},
+		},
+		{
+			name:    "mastodon same user on another instance",
+			saved:   &Credentials{Platform: "mastodon", Username: "alice", Instance: "https://example.social", AccessToken: "t"},
+			env:     map[string]string{"MASTODON_USER": "alice", "MASTODON_INSTANCE": "https://other.social", "MASTODON_ACCESS_TOKEN": "t"},
+			wantEnv: "alice@other.social",
+		},
+		{
+			name:  "mastodon full and short usernames",
+			saved: &Credentials{Platform: "mastodon", Username: "alice@example.social", Instance: "https://example.social", AccessToken: "t"},
+			env:   map[string]string{"MASTODON_USER": "alice", "MASTODON_INSTANCE": "https://example.social/", "MASTODON_ACCESS_TOKEN": "t"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			for _, name := range []string{"BLUESKY_USER", "BLUESKY_PASSWORD", "MASTODON_USER", "MASTODON_INSTANCE", "MASTODON_ACCESS_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+			platform := "bluesky"
+			if tt.saved != nil {
+				platform = tt.saved.Platform
+				authManager, err := NewAuthManager()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := authManager.SaveCredentials(tt.saved); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mismatch := CheckCredentialMismatch(platform)
+			if tt.wantEnv == "" {
+				if mismatch != nil {
+					t.Errorf("Expected no mismatch, got %+v", mismatch)
+				}
+				return
+			}
+			if mismatch == nil || mismatch.EnvUser != tt.wantEnv {
+				t.Errorf("Expected mismatch ignoring %s, got %+v", tt.wantEnv, mismatch)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsFromEnv_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "bluesky_password")
+	if err := os.WriteFile(passwordFile, []byte("file-password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(dir, "mastodon_token")
+	if err := os.WriteFile(tokenFile, []byte("file-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("BLUESKY_USER", "me.bsky.social")
+	t.Setenv("BLUESKY_PASSWORD", "")
+	t.Setenv("BLUESKY_PASSWORD_FILE", passwordFile)
+	t.Setenv("MASTODON_USER", "me")
+	t.Setenv("MASTODON_INSTANCE", "https://example.social")
+	t.Setenv("MASTODON_ACCESS_TOKEN", "")
+	t.Setenv("MASTODON_ACCESS_TOKEN_FILE", tokenFile)
+
+	if creds := GetCredentialsFromEnv("bluesky"); creds == nil || creds.AppPassword != "file-password" {
+		t.Errorf("Expected the password from the file without its newline, got %+v", creds)
+	}
+	if creds := GetCredentialsFromEnv("mastodon"); creds == nil || creds.AccessToken != "file-token" {
+		t.Errorf("Expected the token from the file, got %+v", creds)
+	}
+
+	// The variable itself wins over the file
+	t.Setenv("MASTODON_ACCESS_TOKEN", "env-token")
+	if creds := GetCredentialsFromEnv("mastodon"); creds == nil || creds.AccessToken != "env-token" {
+		t.Errorf("Expected the token from the variable, got %+v", creds)
+	}
+
+	// A file that can't be read leaves the credentials missing
+	t.Setenv("BLUESKY_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	if creds := GetCredentialsFromEnv("bluesky"); creds != nil {
+		t.Errorf("Expected no credentials with an unreadable password file, got %+v", creds)
+	}
+	if _, err := GetSecretFromEnv("BLUESKY_PASSWORD"); err == nil {
+		t.Error("Expected an error for an unreadable password file")
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerHalfOpen                     // Cool-down elapsed, a single trial is allowed through
+	BreakerOpen                         // Too many consecutive failures, requests are refused
+)
+
+// String returns the state name used in logs and on the status page
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calling a failing platform after a run of consecutive failures,
+// then lets a single trial through once the cool-down has passed. A successful trial
+// closes the breaker again; a failed one reopens it for another cool-down.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	threshold        int
+	cooldown         time.Duration
+	clock            Clock
+	consecutiveFails int
+	openedAt         time.Time
+	trialInFlight    bool
+	state            BreakerState
+}
+
+// NewCircuitBreaker creates a breaker that opens after threshold consecutive failures
+// and stays open for cooldown. A threshold of 0 or less disables the breaker.
+func NewCircuitBreaker(threshold int, cooldown time.Duration, clock Clock) *CircuitBreaker {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clock,
+		state:     BreakerClosed,
+	}
+}
+
+// Allow reports whether an action may proceed. Once the cool-down has elapsed the
+// breaker moves to half-open and allows exactly one trial until its outcome is recorded.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trialInFlight = true
+		return true
+	default:
+		return false
+	}
+}
+
+// RecordSuccess resets the failure count and closes the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFails = 0
+	b.trialInFlight = false
+	b.state = BreakerClosed
+}
+
+// RecordFailure counts a failure, opening the breaker once the threshold is reached
+// or immediately if the half-open trial failed
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFails++
+	if b.threshold <= 0 {
+		return
+	}
+	if b.trialInFlight || b.consecutiveFails >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.clock.Now()
+	}
+	b.trialInFlight = false
+}
+
+// State returns the current breaker state, accounting for an elapsed cool-down
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// OpenUntil returns when the current cool-down ends, or the zero time if the breaker isn't open
+func (b *CircuitBreaker) OpenUntil() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.currentState() != BreakerOpen {
+		return time.Time{}
+	}
+	return b.openedAt.Add(b.cooldown)
+}
+
+// ConsecutiveFailures returns the number of failures since the last success
+func (b *CircuitBreaker) ConsecutiveFailures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consecutiveFails
+}
+
+// currentState must be called with b.mu held
+func (b *CircuitBreaker) currentState() BreakerState {
+	if b.state == BreakerOpen && !b.clock.Now().Before(b.openedAt.Add(b.cooldown)) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
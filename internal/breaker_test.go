@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	breaker := NewCircuitBreaker(3, time.Hour, clock)
+
+	for i := 0; i < 2; i++ {
+		if !breaker.Allow() {
+			t.Fatalf("Expected breaker to allow run %d", i+1)
+		}
+		breaker.RecordFailure()
+	}
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Expected breaker to stay closed below threshold, got %s", breaker.State())
+	}
+
+	breaker.Allow()
+	breaker.RecordFailure()
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("Expected breaker to open at threshold, got %s", breaker.State())
+	}
+	if breaker.Allow() {
+		t.Error("Expected open breaker to refuse runs")
+	}
+	if want := clock.Now().Add(time.Hour); !breaker.OpenUntil().Equal(want) {
+		t.Errorf("Expected OpenUntil %v, got %v", want, breaker.OpenUntil())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrial(t *testing.T) {
+	tests := []struct {
+		name          string
+		trialSucceeds bool
+		expectedState BreakerState
+	}{
+		{"successful trial closes breaker", true, BreakerClosed},
+		{"failed trial reopens breaker", false, BreakerOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			breaker := NewCircuitBreaker(1, time.Hour, clock)
+			breaker.Allow()
+			breaker.RecordFailure()
+
+			clock.Advance(time.Hour)
+			if breaker.State() != BreakerHalfOpen {
+				t.Fatalf("Expected breaker to be half-open after cool-down, got %s", breaker.State())
+			}
+			if !breaker.Allow() {
+				t.Fatal("Expected half-open breaker to allow a trial run")
+			}
+			if breaker.Allow() {
+				t.Error("Expected half-open breaker to allow only one trial at a time")
+			}
+
+			if tt.trialSucceeds {
+				breaker.RecordSuccess()
+			} else {
+				breaker.RecordFailure()
+			}
+			if breaker.State() != tt.expectedState {
+				t.Errorf("Expected state %s, got %s", tt.expectedState, breaker.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Hour, NewFakeClock(time.Now()))
+	breaker.RecordFailure()
+	breaker.RecordSuccess()
+	breaker.RecordFailure()
+
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Expected non-consecutive failures to leave breaker closed, got %s", breaker.State())
+	}
+	if breaker.ConsecutiveFailures() != 1 {
+		t.Errorf("Expected 1 consecutive failure, got %d", breaker.ConsecutiveFailures())
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := NewCircuitBreaker(0, time.Hour, NewFakeClock(time.Now()))
+	for i := 0; i < 10; i++ {
+		breaker.RecordFailure()
+	}
+	if !breaker.Allow() {
+		t.Error("Expected disabled breaker to always allow runs")
+	}
+}
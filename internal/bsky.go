@@ -2,12 +2,14 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,15 +18,25 @@ import (
 type BlueskyClient struct {
 	sessionManager *SessionManager
 	session        *atpSessionResponse
+	oauth          *blueskyOAuthSession // Set when logged in with OAuth instead of an app password
+	pds            string               // PDS the app password session was created on
+	clock          Clock
 }
 
 // NewBlueskyClient creates a new Bluesky client
 func NewBlueskyClient() *BlueskyClient {
 	return &BlueskyClient{
 		sessionManager: NewSessionManager("bluesky"),
+		clock:          SystemClock,
 	}
 }
 
+// SetClock replaces the client's time source, used by tests to simulate time passing
+func (c *BlueskyClient) SetClock(clock Clock) {
+	c.clock = clock
+	c.sessionManager.clock = clock
+}
+
 // GetPlatformName returns the platform name
 func (c *BlueskyClient) GetPlatformName() string {
 	return "Bluesky"
@@ -36,67 +48,91 @@ func (c *BlueskyClient) RequiresAuth() bool {
 }
 
 // FetchUserPosts retrieves recent posts for a Bluesky user
-func (c *BlueskyClient) FetchUserPosts(username string, limit int) ([]Post, error) {
-	posts, err := c.fetchBlueskyPosts(username, limit)
+func (c *BlueskyClient) FetchUserPosts(ctx context.Context, username string, limit int) ([]Post, error) {
+	posts, err := c.fetchBlueskyPosts(ctx, username, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert Bluesky posts to generic Post format
+	// Note: Likes are not returned by getAuthorFeed - they need to be fetched separately
+	// if we want to include them in the pruning process
 	var genericPosts []Post
 	for _, bskyPost := range posts {
-		post := Post{
-			ID:        bskyPost.URI,
-			Author:    bskyPost.Author.DisplayName,
-			Handle:    bskyPost.Author.Handle,
-			Content:   bskyPost.Record.Text,
-			CreatedAt: bskyPost.Record.CreatedAt,
-			URL:       fmt.Sprintf("https://bsky.app/profile/%s/post/%s", bskyPost.Author.Handle, extractPostID(bskyPost.URI)),
-			Type:      c.determinePostType(bskyPost),
-			Platform:  "bluesky",
+		genericPosts = append(genericPosts, c.viewPost(bskyPost))
+	}
 
-			// Engagement metrics
-			RepostCount: bskyPost.RepostCount,
-			LikeCount:   bskyPost.LikeCount,
-			ReplyCount:  bskyPost.ReplyCount,
-		}
+	return genericPosts, nil
+}
 
-		// Use Author.Handle as fallback if DisplayName is empty
-		if post.Author == "" {
-			post.Author = bskyPost.Author.Handle
-		}
+// viewPost converts a post view from the AppView to the generic Post format
+func (c *BlueskyClient) viewPost(bskyPost blueskyPost) Post {
+	post := Post{
+		ID:        bskyPost.URI,
+		Author:    bskyPost.Author.DisplayName,
+		Handle:    bskyPost.Author.Handle,
+		Content:   bskyPost.Record.Text,
+		CreatedAt: bskyPost.Record.CreatedAt,
+		URL:       fmt.Sprintf("https://bsky.app/profile/%s/post/%s", bskyPost.Author.Handle, extractPostID(bskyPost.URI)),
+		Type:      c.determinePostType(bskyPost),
+		Platform:  "bluesky",
+		Hashtags:    bskyPost.Record.hashtags(),
+		Languages:   bskyPost.Record.Langs,
+		Visibility:  VisibilityPublic, // Bluesky has no private posts
+		Attachments: bskyPost.Record.Embed.attachments(),
+		Links:       bskyPost.Record.links(),
 
-		// Set viewer interaction status and pinned status
-		if bskyPost.ViewerData != nil {
-			post.IsLikedByUser = bskyPost.ViewerData.Like != nil
-		}
-		post.IsPinned = bskyPost.IsPinned
+		// Engagement metrics
+		RepostCount: bskyPost.RepostCount,
+		LikeCount:   bskyPost.LikeCount,
+		ReplyCount:  bskyPost.ReplyCount,
+	}
 
-		// Handle reposts - these are the user's own repost records, not the original posts
-		if bskyPost.Record.Type == "app.bsky.feed.repost" {
-			post.Type = PostTypeRepost
-			// For reposts, the ID should be the repost record URI, not the original post URI
-			post.ID = bskyPost.URI // This is the user's repost record URI
-		}
+	// Use Author.Handle as fallback if DisplayName is empty
+	if post.Author == "" {
+		post.Author = bskyPost.Author.Handle
+	}
 
-		// Handle replies
-		if bskyPost.Record.Reply != nil {
-			post.Type = PostTypeReply
-			post.InReplyToID = bskyPost.Record.Reply.Parent.URI
-		}
+	// Set viewer interaction status and pinned status
+	if bskyPost.ViewerData != nil {
+		post.IsLikedByUser = bskyPost.ViewerData.Like != nil
+	}
+	post.IsPinned = bskyPost.IsPinned
 
-		// Note: Likes are not returned by getAuthorFeed - they need to be fetched separately
-		// if we want to include them in the pruning process
+	// Handle reposts - these are the user's own repost records, not the original posts
+	if bskyPost.Record.Type == "app.bsky.feed.repost" {
+		post.Type = PostTypeRepost
+		// For reposts, the ID should be the repost record URI, not the original post URI
+		post.ID = bskyPost.URI // This is the user's repost record URI
+	}
 
-		genericPosts = append(genericPosts, post)
+	// Handle replies
+	if bskyPost.Record.Reply != nil {
+		post.Type = PostTypeReply
+		post.InReplyToID = bskyPost.Record.Reply.Parent.URI
+		post.SelfReply = atURIRepo(post.InReplyToID) == atURIRepo(bskyPost.URI)
 	}
 
-	return genericPosts, nil
+	// Handle quotes, unless they're replies, which are thread posts first
+	if quoted := bskyPost.Record.Embed.quotedPost(); quoted != "" && post.Type == PostTypeOriginal {
+		post.Type = PostTypeQuote
+		post.OriginalPost = &Post{ID: quoted, Type: PostTypeOriginal, Platform: "bluesky"}
+		post.SelfQuote = atURIRepo(quoted) == atURIRepo(bskyPost.URI)
+	}
+
+	return post
 }
 
 // FetchUserPostsPaginated retrieves posts with cursor-based pagination
-func (c *BlueskyClient) FetchUserPostsPaginated(username string, limit int, cursor string) ([]Post, string, error) {
-	posts, nextCursor, err := c.fetchBlueskyPostsPaginated(username, limit, cursor)
+func (c *BlueskyClient) FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]Post, string, error) {
+	return c.fetchPostsPage(ctx, username, limit, cursor, FetchProfileDisplay)
+}
+
+// fetchPostsPage fetches one page of the author feed. The display profile also mixes the
+// user's likes into the first page; the prune profile skips that, since prune fetches
+// likes from the like collection itself.
+func (c *BlueskyClient) fetchPostsPage(ctx context.Context, username string, limit int, cursor string, profile FetchProfile) ([]Post, string, error) {
+	posts, nextCursor, err := c.fetchBlueskyPostsPaginated(ctx, username, limit, cursor)
 	if err != nil {
 		return nil, "", err
 	}
@@ -104,53 +140,13 @@ func (c *BlueskyClient) FetchUserPostsPaginated(username string, limit int, curs
 	// Convert Bluesky posts to generic Post format
 	var genericPosts []Post
 	for _, bskyPost := range posts {
-		post := Post{
-			ID:        bskyPost.URI,
-			Author:    bskyPost.Author.DisplayName,
-			Handle:    bskyPost.Author.Handle,
-			Content:   bskyPost.Record.Text,
-			CreatedAt: bskyPost.Record.CreatedAt,
-			URL:       fmt.Sprintf("https://bsky.app/profile/%s/post/%s", bskyPost.Author.Handle, extractPostID(bskyPost.URI)),
-			Type:      c.determinePostType(bskyPost),
-			Platform:  "bluesky",
-
-			// Engagement metrics
-			RepostCount: bskyPost.RepostCount,
-			LikeCount:   bskyPost.LikeCount,
-			ReplyCount:  bskyPost.ReplyCount,
-		}
-
-		// Use Author.Handle as fallback if DisplayName is empty
-		if post.Author == "" {
-			post.Author = bskyPost.Author.Handle
-		}
-
-		// Set viewer interaction status and pinned status
-		if bskyPost.ViewerData != nil {
-			post.IsLikedByUser = bskyPost.ViewerData.Like != nil
-		}
-		post.IsPinned = bskyPost.IsPinned
-
-		// Handle reposts - these are the user's own repost records, not the original posts
-		if bskyPost.Record.Type == "app.bsky.feed.repost" {
-			post.Type = PostTypeRepost
-			// For reposts, the ID should be the repost record URI, not the original post URI
-			post.ID = bskyPost.URI // This is the user's repost record URI
-		}
-
-		// Handle replies
-		if bskyPost.Record.Reply != nil {
-			post.Type = PostTypeReply
-			post.InReplyToID = bskyPost.Record.Reply.Parent.URI
-		}
-
-		genericPosts = append(genericPosts, post)
+		genericPosts = append(genericPosts, c.viewPost(bskyPost))
 	}
 
 	// Fetch user's liked posts separately and include them in the results
 	// This allows likes to be included in pruning operations and listing
-	if cursor == "" { // Only fetch likes on the first page to avoid duplicates
-		likedPosts, err := c.fetchLikedPostsIntegrated(limit)
+	if cursor == "" && profile == FetchProfileDisplay { // Only fetch likes on the first page to avoid duplicates
+		likedPosts, err := c.fetchLikedPostsIntegrated(ctx, limit)
 		if err != nil {
 			// Log the error but don't fail the entire operation
 			logger := WithPlatform("bluesky")
@@ -163,8 +159,8 @@ func (c *BlueskyClient) FetchUserPostsPaginated(username string, limit int, curs
 	return genericPosts, nextCursor, nil
 }
 
-func (c *BlueskyClient) fetchBlueskyPostsPaginated(username string, limit int, cursor string) ([]blueskyPost, string, error) {
-	baseURL := "https://public.api.bsky.app/xrpc/app.bsky.feed.getAuthorFeed"
+func (c *BlueskyClient) fetchBlueskyPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]blueskyPost, string, error) {
+	baseURL := blueskyAppView() + "/xrpc/app.bsky.feed.getAuthorFeed"
 	params := url.Values{}
 	params.Add("actor", username)
 	params.Add("limit", fmt.Sprintf("%d", limit))
@@ -177,18 +173,15 @@ func (c *BlueskyClient) fetchBlueskyPostsPaginated(username string, limit int, c
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	LogHTTPRequest("GET", fullURL)
-	resp, err := http.Get(fullURL)
+	resp, err := httpGetWithRetry(ctx, fullURL)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to fetch posts: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, "", newAPIError("bluesky", "API request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -242,10 +235,128 @@ type blueskyAuthor struct {
 }
 
 type blueskyRecord struct {
-	Type      string        `json:"$type"`
-	Text      string        `json:"text"`
-	CreatedAt time.Time     `json:"createdAt"`
-	Reply     *blueskyReply `json:"reply,omitempty"`
+	Type      string         `json:"$type"`
+	Text      string         `json:"text"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Reply     *blueskyReply  `json:"reply,omitempty"`
+	Facets    []blueskyFacet `json:"facets,omitempty"`
+	Embed     *blueskyEmbed  `json:"embed,omitempty"`
+	Langs     []string       `json:"langs,omitempty"`
+}
+
+// blueskyEmbed is the embed on a post record. Only the media-carrying and quoting embed
+// types are decoded; recordWithMedia nests its images or video under Media.
+type blueskyEmbed struct {
+	Type   string              `json:"$type"`
+	Images []blueskyEmbedImage `json:"images,omitempty"` // app.bsky.embed.images
+	Alt    string              `json:"alt,omitempty"`    // app.bsky.embed.video
+	Media  *blueskyEmbed       `json:"media,omitempty"`  // app.bsky.embed.recordWithMedia
+	Record *blueskyEmbedRecord `json:"record,omitempty"` // app.bsky.embed.record and recordWithMedia
+
+	External *struct {
+		URI string `json:"uri"`
+	} `json:"external,omitempty"` // app.bsky.embed.external, a link card
+}
+
+// blueskyEmbedRecord is the record a quote embeds. app.bsky.embed.record refers to it
+// directly, while recordWithMedia wraps that embed, so its reference is one level down.
+type blueskyEmbedRecord struct {
+	URI    string              `json:"uri,omitempty"`
+	Record *blueskyEmbedRecord `json:"record,omitempty"`
+}
+
+// link returns the URL of the embed's link card, or "" if it hasn't got one
+func (e *blueskyEmbed) link() string {
+	switch {
+	case e == nil:
+		return ""
+	case e.External != nil:
+		return e.External.URI
+	default:
+		return e.Media.link()
+	}
+}
+
+// quotedPost returns the AT URI of the post the embed quotes, or "" if it doesn't quote
+// one. Record embeds can also point at lists, feeds and starter packs, which don't count.
+func (e *blueskyEmbed) quotedPost() string {
+	if e == nil || e.Record == nil {
+		return ""
+	}
+	uri := e.Record.URI
+	if e.Type == "app.bsky.embed.recordWithMedia" && e.Record.Record != nil {
+		uri = e.Record.Record.URI
+	}
+	if !strings.Contains(uri, "/app.bsky.feed.post/") {
+		return ""
+	}
+	return uri
+}
+
+type blueskyEmbedImage struct {
+	Alt string `json:"alt"`
+}
+
+// attachments converts the embed's images or video into generic attachments
+func (e *blueskyEmbed) attachments() []Attachment {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case "app.bsky.embed.images":
+		attachments := make([]Attachment, 0, len(e.Images))
+		for _, image := range e.Images {
+			attachments = append(attachments, Attachment{Type: "image", AltText: image.Alt})
+		}
+		return attachments
+	case "app.bsky.embed.video":
+		return []Attachment{{Type: "video", AltText: e.Alt}}
+	case "app.bsky.embed.recordWithMedia":
+		return e.Media.attachments()
+	default:
+		return nil
+	}
+}
+
+// blueskyFacet annotates a range of the post text with rich-text features such as hashtags
+type blueskyFacet struct {
+	Features []blueskyFacetFeature `json:"features"`
+}
+
+type blueskyFacetFeature struct {
+	Type string `json:"$type"`
+	Tag  string `json:"tag,omitempty"` // Set for app.bsky.richtext.facet#tag features
+	URI  string `json:"uri,omitempty"` // Set for app.bsky.richtext.facet#link features
+}
+
+// hashtags returns the tags from the record's hashtag facets
+func (r blueskyRecord) hashtags() []string {
+	var tags []string
+	for _, facet := range r.Facets {
+		for _, feature := range facet.Features {
+			if feature.Type == "app.bsky.richtext.facet#tag" && feature.Tag != "" {
+				tags = append(tags, feature.Tag)
+			}
+		}
+	}
+	return tags
+}
+
+// links returns the URLs in the record's link facets and link card, without duplicates
+func (r blueskyRecord) links() []string {
+	var links []string
+	for _, facet := range r.Facets {
+		for _, feature := range facet.Features {
+			if feature.Type == "app.bsky.richtext.facet#link" && feature.URI != "" && !slices.Contains(links, feature.URI) {
+				links = append(links, feature.URI)
+			}
+		}
+	}
+	if link := r.Embed.link(); link != "" && !slices.Contains(links, link) {
+		links = append(links, link)
+	}
+	return links
 }
 
 type blueskyReply struct {
@@ -273,8 +384,8 @@ type blueskyEnhancedFeedResponse struct {
 	Cursor *string `json:"cursor,omitempty"`
 }
 
-func (c *BlueskyClient) fetchBlueskyPosts(username string, limit int) ([]blueskyPost, error) {
-	baseURL := "https://public.api.bsky.app/xrpc/app.bsky.feed.getAuthorFeed"
+func (c *BlueskyClient) fetchBlueskyPosts(ctx context.Context, username string, limit int) ([]blueskyPost, error) {
+	baseURL := blueskyAppView() + "/xrpc/app.bsky.feed.getAuthorFeed"
 	params := url.Values{}
 	params.Add("actor", username)
 	params.Add("limit", fmt.Sprintf("%d", limit))
@@ -283,18 +394,15 @@ func (c *BlueskyClient) fetchBlueskyPosts(username string, limit int) ([]bluesky
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	LogHTTPRequest("GET", fullURL)
-	resp, err := http.Get(fullURL)
+	resp, err := httpGetWithRetry(ctx, fullURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch posts: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("bluesky", "API request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -318,6 +426,132 @@ func (c *BlueskyClient) fetchBlueskyPosts(username string, limit int) ([]bluesky
 	return posts, nil
 }
 
+// GetPostCount returns the postsCount Bluesky reports for the account profile
+func (c *BlueskyClient) GetPostCount(ctx context.Context, username string) (int, error) {
+	baseURL := blueskyAppView() + "/xrpc/app.bsky.actor.getProfile"
+	params := url.Values{}
+	params.Add("actor", username)
+
+	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
+
+	resp, err := httpGetWithRetry(ctx, fullURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, newAPIError("bluesky", "profile request", resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read profile response: %w", err)
+	}
+
+	var profile struct {
+		PostsCount int `json:"postsCount"`
+	}
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return 0, fmt.Errorf("failed to parse profile response: %w", err)
+	}
+
+	return profile.PostsCount, nil
+}
+
+// VerifyAccount logs in with the saved credentials and fetches the account's profile
+// through the session, so that both the login and its access token are checked
+func (c *BlueskyClient) VerifyAccount(ctx context.Context) (*AccountInfo, error) {
+	creds, err := GetCredentialsForPlatform("bluesky")
+	if err != nil {
+		return nil, fmt.Errorf("authentication required: %w", err)
+	}
+	if err := ValidateCredentials(creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials: %w", err)
+	}
+	session, err := c.ensureValidSession(ctx, creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with Bluesky: %w", err)
+	}
+
+	params := url.Values{}
+	params.Add("actor", session.DID)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.xrpcURL("app.bsky.actor.getProfile")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile request: %w", err)
+	}
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("bluesky", "profile request", resp.StatusCode, body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile response: %w", err)
+	}
+
+	var profile struct {
+		DID        string    `json:"did"`
+		Handle     string    `json:"handle"`
+		PostsCount int       `json:"postsCount"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile response: %w", err)
+	}
+	return &AccountInfo{
+		ID:                  profile.DID,
+		Username:            profile.Handle,
+		PostCount:           profile.PostsCount,
+		CreatedAt:           profile.CreatedAt,
+		CredentialsUsername: creds.Username,
+		MatchesCredentials:  isOwnBlueskyAccount(creds.Username, &atpSessionResponse{Handle: profile.Handle, DID: profile.DID}),
+	}, nil
+}
+
+// FetchInstanceRules fetches the terms published by the account's PDS, bsky.social unless
+// another is configured. Bluesky has no per-instance rule list, so only the terms of
+// service link is returned.
+func (c *BlueskyClient) FetchInstanceRules(ctx context.Context, username string) (*InstanceRules, error) {
+	creds, _ := GetCredentialsForPlatform("bluesky") // Without any, the configured or default PDS is used
+	pds := blueskyPDS(creds)
+	resp, err := httpGetWithRetry(ctx, pds+"/xrpc/com.atproto.server.describeServer")
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("bluesky", "describe server request", resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read describe server response: %w", err)
+	}
+
+	var server struct {
+		Links struct {
+			TermsOfService string `json:"termsOfService"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal(body, &server); err != nil {
+		return nil, fmt.Errorf("failed to parse describe server response: %w", err)
+	}
+
+	return &InstanceRules{
+		Platform: "bluesky",
+		Instance: blueskyHostName(pds),
+		TermsURL: server.Links.TermsOfService,
+	}, nil
+}
+
 // determinePostType determines the type of Bluesky post
 func (c *BlueskyClient) determinePostType(post blueskyPost) PostType {
 	switch post.Record.Type {
@@ -334,7 +568,11 @@ func (c *BlueskyClient) determinePostType(post blueskyPost) PostType {
 }
 
 // PrunePosts deletes posts according to specified criteria
-func (c *BlueskyClient) PrunePosts(username string, options PruneOptions) (*PruneResult, error) {
+func (c *BlueskyClient) PrunePosts(ctx context.Context, username string, options PruneOptions) (*PruneResult, error) {
+	if options.Redact {
+		return nil, ErrRedactUnsupported
+	}
+
 	// Get authentication credentials
 	creds, err := GetCredentialsForPlatform("bluesky")
 	if err != nil {
@@ -346,185 +584,265 @@ func (c *BlueskyClient) PrunePosts(username string, options PruneOptions) (*Prun
 	}
 
 	// Create session to get authenticated user's DID
-	session, err := c.ensureValidSession(creds)
+	session, err := c.ensureValidSession(ctx, creds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to authenticate with Bluesky: %w. This may indicate invalid credentials or DID resolution issues", err)
 	}
 
-	// Fetch ALL user's posts using pagination to ensure we process posts older than 60 days
+	// Other accounts can be listed, but never pruned
+	mismatch, err := checkOwnAccount("bluesky", username, session.Handle, isOwnBlueskyAccount(username, session), options)
+	if err != nil {
+		return nil, err
+	}
+
+	// With --ids-file or --id, only the listed records are looked at
+	if len(options.ListedPosts) > 0 {
+		result, err := c.pruneListedPosts(ctx, creds, session, options)
+		result.addMismatchWarning(mismatch)
+		return result, err
+	}
+
+	// With --from-index the posts come from the last sync instead of the author feed
 	var allPosts []Post
-	cursor := ""
-	batchSize := 100
-	
-	for {
-		posts, nextCursor, err := c.FetchUserPostsPaginated(username, batchSize, cursor)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch posts: %w", err)
-		}
-		
-		if len(posts) == 0 {
-			break // No more posts to fetch
-		}
-		
-		allPosts = append(allPosts, posts...)
-		
-		// Check if we should continue fetching based on age criteria
-		shouldContinue := false
-		if options.MaxAge != nil || options.BeforeDate != nil {
-			for _, post := range posts {
-				// If any post in this batch matches the age criteria, continue fetching
-				if options.MaxAge != nil && time.Now().Sub(post.CreatedAt) > *options.MaxAge {
-					shouldContinue = true
-					break
-				}
-				if options.BeforeDate != nil && post.CreatedAt.Before(*options.BeforeDate) {
-					shouldContinue = true
-					break
-				}
-			}
-		}
-		
-		if nextCursor == "" || !shouldContinue {
-			break // No more pages or no posts match age criteria
-		}
-		
-		cursor = nextCursor
+	if options.FromIndex {
+		allPosts, err = indexedPrunePosts("bluesky", username)
+	} else {
+		allPosts, err = c.fetchPruneTimeline(ctx, username, options)
 	}
-	
-	posts := allPosts
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete replies before the posts they answer, so a partly pruned thread never has
+	// replies hanging off a deleted parent
+	posts := orderRepliesBeforeParents(allPosts)
+
+	result := &PruneResult{
+		PostsToDelete:  []Post{},
+		PostsToUnlike:  []Post{},
+		PostsToUnshare: []Post{},
+		PostsPreserved: []Post{},
+		Errors:         []string{},
+	}
+	result.addMismatchWarning(mismatch)
 
 	// If user wants to unlike posts, also fetch their liked posts
 	if options.UnlikePosts {
-		likedPosts, err := c.fetchAllLikedPosts(session, options)
+		likedPosts, truncated, err := c.fetchAllLikedPosts(ctx, session, options)
 		if err != nil {
 			fmt.Printf("⚠️  Warning: Failed to fetch liked posts: %v\n", err)
+			result.AddWarning("Failed to fetch liked posts: %v", err)
 		} else {
 			posts = append(posts, likedPosts...)
+			if truncated {
+				result.AddWarning("%s", warnTruncatedListing("like", len(likedPosts), options.ContinueUntilEnd))
+			}
 		}
 	}
 
 	// Always fetch the user's repost records separately to ensure we get the correct repost URIs
-	repostPosts, err := c.fetchAllRepostPosts(session, options)
+	repostPosts, truncated, err := c.fetchAllRepostPosts(ctx, session, options)
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to fetch repost records: %v\n", err)
+		result.AddWarning("Failed to fetch repost records: %v", err)
 	} else {
 		posts = append(posts, repostPosts...)
+		if truncated {
+			result.AddWarning("%s", warnTruncatedListing("repost", len(repostPosts), options.ContinueUntilEnd))
+		}
 	}
 
-	result := &PruneResult{
-		PostsToDelete:  []Post{},
-		PostsToUnlike:  []Post{},
-		PostsToUnshare: []Post{},
-		PostsPreserved: []Post{},
-		Errors:         []string{},
+	if err := NewPruneEngine("bluesky", c.pruneActor(creds, session, options), options, c.clock).Run(ctx, posts, result); err != nil {
+		return result, err
 	}
+	return result, nil
+}
 
-	now := time.Now()
+// fetchPruneTimeline walks the account's author feed for a prune, without likes
+func (c *BlueskyClient) fetchPruneTimeline(ctx context.Context, username string, options PruneOptions) ([]Post, error) {
+	// Fetch the user's posts. The author feed is newest-first, so the old posts we want to
+	// prune sit at the end of it. Pages are fetched for as long as they hold posts old
+	// enough to prune; with ContinueUntilEnd we walk every page until the cursor runs out
+	// instead of stopping at the first batch that contains nothing to prune.
+	var allPosts []Post
+	seen := make(map[string]bool)
+	cursor := options.Checkpoint.StartCursor()
+	page := 1
 
-	for _, post := range posts {
-		shouldProcess := false
-		preserveReason := ""
+	// A single page should be as full as possible; a whole-timeline walk starts small
+	// so progress shows up quickly, then ramps up to keep the request count down
+	pageSizes := NewPageSizeRamp(MaxPageSize("bluesky"), MaxPageSize("bluesky"))
+	if options.ContinueUntilEnd {
+		pageSizes = NewPageSizeRamp(initialPruneScanPageSize, MaxPageSize("bluesky"))
+	}
 
-		// Check age criteria
-		if options.MaxAge != nil {
-			if now.Sub(post.CreatedAt) > *options.MaxAge {
-				shouldProcess = true
-			}
+	for {
+		posts, nextCursor, err := c.fetchPostsPage(ctx, username, pageSizes.Next(), cursor, FetchProfilePrune)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch posts: %w", err)
 		}
 
-		// Check date criteria
-		if options.BeforeDate != nil {
-			if post.CreatedAt.Before(*options.BeforeDate) {
-				shouldProcess = true
+		if len(posts) == 0 {
+			break // No more posts to fetch
+		}
+		options.Checkpoint.addPage(cursor, posts)
+
+		for _, post := range posts {
+			// Likes are fetched from the like collection below, and only when --unlike-posts is set
+			if post.Type == PostTypeLike || seen[post.ID] {
+				continue
 			}
+			seen[post.ID] = true
+			allPosts = append(allPosts, post)
 		}
 
-		if !shouldProcess {
-			continue
+		if !options.ContinueUntilEnd && !options.pageMatchesAge(posts, c.clock.Now()) {
+			break // Nothing old enough on this page, and not asked to walk the whole timeline
 		}
 
-		// Check preservation rules
-		if options.PreservePinned && post.IsPinned {
-			preserveReason = "pinned"
-		} else if options.PreserveSelfLike && post.IsLikedByUser && post.Type == PostTypeOriginal {
-			preserveReason = "self-liked"
+		if options.reachedAfterDate(posts) {
+			break // Everything further back is before --after-date
 		}
 
-		if preserveReason != "" {
-			result.PostsPreserved = append(result.PostsPreserved, post)
-			result.PreservedCount++
-		} else {
-			// Determine action based on post type
-			if post.Type == PostTypeLike {
-				// Handle like records - delete the like record directly
-				result.PostsToUnlike = append(result.PostsToUnlike, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					logger := WithPlatform("bluesky").With().Str("post_id", post.ID).Logger()
-					if err := c.deleteLikeRecord(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to unlike post")
-						fmt.Printf("❌ Failed to unlike post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to unlike post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Post unliked successfully")
-						fmt.Printf("👍 Unliked post from %s: %s\n", post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
-						result.UnlikedCount++
-					}
-				}
-			} else if post.Type == PostTypeRepost {
-				// Always unrepost for repost records - these are the user's own repost actions
-				result.PostsToUnshare = append(result.PostsToUnshare, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					// For reposts, we need to delete the repost record directly
-					logger := WithPlatform("bluesky").With().Str("post_id", post.ID).Logger()
-					if err := c.deleteRepostRecord(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to unrepost")
-						fmt.Printf("❌ Failed to unrepost from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to unrepost post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Repost unshared successfully")
-						fmt.Printf("🔄 Unshared repost from %s: %s\n", post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
-						result.UnsharedCount++
-					}
-				}
-			} else if post.Type == PostTypeOriginal || post.Type == PostTypeReply {
-				// Validate that the post belongs to the authenticated user
-				if err := c.validatePostURI(post.ID, session.DID); err != nil {
-					fmt.Printf("⚠️  Skipping post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-					result.Errors = append(result.Errors, fmt.Sprintf("Post validation failed %s: %v", post.ID, err))
-					result.ErrorsCount++
-					continue
-				}
-
-				result.PostsToDelete = append(result.PostsToDelete, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					logger := WithPlatform("bluesky").With().Str("post_id", post.ID).Logger()
-					if err := c.deletePost(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to delete post")
-						fmt.Printf("❌ Failed to delete post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to delete post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Post deleted successfully")
-						fmt.Printf("🗑️  Deleted post from %s: %s\n", post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
-						result.DeletedCount++
-					}
-				}
-			}
+		if nextCursor == "" || nextCursor == cursor {
+			break // Reached the end of the timeline
+		}
+
+		if options.runLimitReached(ctx, c.clock.Now()) != "" || DrainFromContext(ctx).Draining() {
+			break // No budget left to act on more pages, or shutting down
 		}
+
+		logger := WithPlatform("bluesky")
+		logger.Debug().Int("page", page).Int("posts_so_far", len(allPosts)).Msg("Fetching next page of author feed")
+		fmt.Printf("📄 Fetched page %d (%d posts so far), continuing...\n", page, len(allPosts))
+
+		cursor = nextCursor
+		page++
 	}
 
-	return result, nil
+	return allPosts, nil
+}
+
+// blueskyPruneActor removes records from the authenticated account's repo for a PruneEngine
+type blueskyPruneActor struct {
+	client   *BlueskyClient
+	creds    *Credentials
+	session  *atpSessionResponse
+	batching bool // Sending records with applyWrites, for BatchWrites
+	batch    *blueskyBatch
+}
+
+// pruneActor creates the actor that carries out a prune run with options for the session
+func (c *BlueskyClient) pruneActor(creds *Credentials, session *atpSessionResponse, options PruneOptions) *blueskyPruneActor {
+	return &blueskyPruneActor{
+		client:   c,
+		creds:    creds,
+		session:  session,
+		batching: options.BatchWrites && !options.DryRun,
+		batch:    &blueskyBatch{client: c, creds: creds, session: session, options: options},
+	}
+}
+
+// Act deletes the post, like or repost record behind post
+func (a *blueskyPruneActor) Act(ctx context.Context, action string, post Post) error {
+	switch action {
+	case "delete":
+		return a.client.deletePost(ctx, a.creds, post.ID)
+	case "unlike":
+		return a.client.deleteLikeRecord(ctx, a.creds, post.ID)
+	case "unshare":
+		return a.client.deleteRepostRecord(ctx, a.creds, post.ID)
+	case "redact":
+		return ErrRedactUnsupported
+	}
+	return fmt.Errorf("unknown prune action %q", action)
+}
+
+// checkPost makes sure a post to delete belongs to the authenticated user. Batched
+// records are all checked, as one record from another repo fails the whole batch.
+func (a *blueskyPruneActor) checkPost(action string, post Post) error {
+	if action == "delete" || a.batching {
+		return a.client.validatePostURI(post.ID, a.session.DID)
+	}
+	return nil
+}
+
+func (a *blueskyPruneActor) queue(ctx context.Context, action string, post Post, report pruneReport) error {
+	return a.batch.add(ctx, post, report)
+}
+
+func (a *blueskyPruneActor) flush(ctx context.Context, report pruneReport) error {
+	return a.batch.flush(ctx, report)
+}
+
+// orderRepliesBeforeParents reorders posts so that every reply comes before the post it
+// answers, when both are in posts. It builds a reply graph of the given posts and emits
+// each post after all of its replies, keeping the original order otherwise.
+func orderRepliesBeforeParents(posts []Post) []Post {
+	present := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		present[post.ID] = true
+	}
+	replies := make(map[string][]int)
+	for i, post := range posts {
+		if post.InReplyToID != "" && present[post.InReplyToID] {
+			replies[post.InReplyToID] = append(replies[post.InReplyToID], i)
+		}
+	}
+	if len(replies) == 0 {
+		return posts
+	}
+
+	ordered := make([]Post, 0, len(posts))
+	visited := make([]bool, len(posts))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, reply := range replies[posts[i].ID] {
+			visit(reply)
+		}
+		ordered = append(ordered, posts[i])
+	}
+	for i := range posts {
+		visit(i)
+	}
+	return ordered
+}
+
+// truncatedListingMessage describes a record listing that stopped before reaching the oldest records
+func truncatedListingMessage(recordType string, examined int, continued bool) string {
+	message := fmt.Sprintf("%s listing truncated: %d %s records fetched, more available; older %s records were not examined or pruned",
+		strings.ToUpper(recordType[:1])+recordType[1:], examined, recordType, recordType)
+	if continued {
+		return message + ". The server returned a repeated cursor; re-run the prune to pick up the remaining records"
+	}
+	return message + ". Use --continue to page through every record"
+}
+
+// warnTruncatedListing tells the user that older like/repost records were not examined
+// and returns the message so it can be recorded as a warning
+func warnTruncatedListing(recordType string, examined int, continued bool) string {
+	message := truncatedListingMessage(recordType, examined, continued)
+	logger := WithPlatform("bluesky")
+	logger.Warn().Str("record_type", recordType).Int("examined", examined).Msg("Record listing truncated")
+	fmt.Printf("⚠️  Warning: %s\n", message)
+	return message
 }
 
 // extractPostID extracts the post ID from a Bluesky URI
+// atURIRepo returns the repository DID an AT URI points into, or "" if it isn't one
+func atURIRepo(uri string) string {
+	rest, ok := strings.CutPrefix(uri, "at://")
+	if !ok {
+		return ""
+	}
+	repo, _, _ := strings.Cut(rest, "/")
+	return repo
+}
+
 func extractPostID(uri string) string {
 	// URI format: at://did:plc:xxx/app.bsky.feed.post/postid
 	// We want just the postid part
@@ -548,10 +866,21 @@ type atpSessionResponse struct {
 }
 
 
+// isOwnBlueskyAccount returns true if username is the session's handle or DID
+func isOwnBlueskyAccount(username string, session *atpSessionResponse) bool {
+	username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+	return username == strings.ToLower(session.Handle) || username == session.DID
+}
+
 // ensureValidSession ensures we have a valid session, creating/refreshing as needed
-func (c *BlueskyClient) ensureValidSession(creds *Credentials) (*atpSessionResponse, error) {
+func (c *BlueskyClient) ensureValidSession(ctx context.Context, creds *Credentials) (*atpSessionResponse, error) {
 	logger := WithPlatform("bluesky")
 	
+	if creds.UsesOAuth() {
+		return c.ensureOAuthSession(ctx, creds)
+	}
+	c.oauth = nil
+
 	// If we don't have a session or credentials changed, create new session
 	if c.session == nil || c.sessionManager.HasCredentialsChanged(creds) {
 		if c.sessionManager.HasCredentialsChanged(creds) {
@@ -561,19 +890,19 @@ func (c *BlueskyClient) ensureValidSession(creds *Credentials) (*atpSessionRespo
 			logger.Debug().Msg("Creating new Bluesky session")
 			fmt.Printf("🔐 Creating new Bluesky session...\n")
 		}
-		return c.createNewSession(creds)
+		return c.createNewSession(ctx, creds)
 	}
 
 	// If session is expired or about to expire, try to refresh
 	if !c.sessionManager.IsSessionValid() {
 		logger.Debug().Msg("Session expired, refreshing using refresh token")
 		fmt.Printf("🔄 Refreshing Bluesky session using refresh token...\n")
-		refreshedSession, err := c.refreshSession()
+		refreshedSession, err := c.refreshSession(ctx)
 		if err != nil {
 			// If refresh fails, fall back to creating a new session
 			logger.Debug().Err(err).Msg("Session refresh failed, creating new session")
 			fmt.Printf("⚠️  Refresh failed, creating new session: %v\n", err)
-			return c.createNewSession(creds)
+			return c.createNewSession(ctx, creds)
 		}
 		return refreshedSession, nil
 	}
@@ -583,15 +912,90 @@ func (c *BlueskyClient) ensureValidSession(creds *Credentials) (*atpSessionRespo
 	return c.session, nil
 }
 
+// ensureOAuthSession loads the session saved by an OAuth login, refreshing its tokens as
+// they expire. Refresh tokens can only be used once, so new tokens are saved straight away.
+func (c *BlueskyClient) ensureOAuthSession(ctx context.Context, creds *Credentials) (*atpSessionResponse, error) {
+	logger := WithPlatform("bluesky")
+
+	if c.session == nil || c.oauth == nil || c.sessionManager.HasCredentialsChanged(creds) {
+		oauth, err := newBlueskyOAuthSession(creds)
+		if err != nil {
+			return nil, err
+		}
+		c.oauth = oauth
+		c.useOAuthTokens(creds)
+		logger.Debug().Str("pds", oauth.pds).Msg("Loaded Bluesky OAuth session")
+	}
+
+	if c.sessionManager.IsSessionValid() {
+		return c.session, nil
+	}
+
+	logger.Debug().Msg("OAuth access token expired, refreshing")
+	fmt.Printf("🔄 Refreshing Bluesky OAuth session...\n")
+	if err := c.oauth.refresh(ctx, creds, c.clock.Now()); err != nil {
+		return nil, fmt.Errorf("failed to refresh Bluesky OAuth session, run 'cringesweeper auth --platforms=bluesky' to log in again: %w", err)
+	}
+	c.useOAuthTokens(creds)
+
+	authManager, err := NewAuthManager()
+	if err == nil {
+		err = authManager.SaveCredentials(creds)
+	}
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to save refreshed OAuth tokens")
+		fmt.Printf("⚠️  Failed to save refreshed tokens, you may need to log in again next time: %v\n", err)
+	}
+	return c.session, nil
+}
+
+// useOAuthTokens makes the tokens in OAuth credentials the current session
+func (c *BlueskyClient) useOAuthTokens(creds *Credentials) {
+	c.session = &atpSessionResponse{
+		AccessJwt:  creds.AccessToken,
+		RefreshJwt: creds.ExtraData[oauthExtraRefreshToken],
+		Handle:     creds.Username,
+		DID:        creds.ExtraData[oauthExtraDID],
+	}
+	c.sessionManager.UpdateSession(c.session.AccessJwt, c.session.RefreshJwt, oauthExpiry(creds), creds)
+}
+
+// xrpcURL returns the URL of an authenticated XRPC method. OAuth sessions talk to the
+// account's own PDS; app password sessions go through the PDS they were created on.
+func (c *BlueskyClient) xrpcURL(method string) string {
+	if c.oauth != nil {
+		return c.oauth.pds + "/xrpc/" + method
+	}
+	return c.sessionPDS() + "/xrpc/" + method
+}
+
+// sessionPDS returns the PDS of the app password session, or the configured one if no
+// session has been created yet
+func (c *BlueskyClient) sessionPDS() string {
+	if c.pds != "" {
+		return c.pds
+	}
+	return blueskyPDS(nil)
+}
+
+// doAuthenticated sends a request with the session's access token
+func (c *BlueskyClient) doAuthenticated(req *http.Request, session *atpSessionResponse) (*http.Response, error) {
+	if c.oauth != nil {
+		return c.oauth.do(req, session.AccessJwt)
+	}
+	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
+	return doRequest(sharedHTTPClient(), req)
+}
+
 // refreshSession uses the refresh token to extend the current session
-func (c *BlueskyClient) refreshSession() (*atpSessionResponse, error) {
+func (c *BlueskyClient) refreshSession(ctx context.Context) (*atpSessionResponse, error) {
 	if c.session == nil || c.session.RefreshJwt == "" {
 		return nil, fmt.Errorf("no valid refresh token available")
 	}
 
-	refreshURL := "https://bsky.social/xrpc/com.atproto.server.refreshSession"
+	refreshURL := c.xrpcURL("com.atproto.server.refreshSession")
 
-	req, err := http.NewRequest("POST", refreshURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", refreshURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create refresh request: %w", err)
 	}
@@ -600,10 +1004,8 @@ func (c *BlueskyClient) refreshSession() (*atpSessionResponse, error) {
 	req.Header.Set("Authorization", "Bearer "+c.session.RefreshJwt)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("POST", refreshURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("POST", refreshURL, resp.StatusCode, resp.Status)
+	client := sharedHTTPClient()
+	resp, err := doRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("refresh request failed: %w", err)
 	}
@@ -611,7 +1013,7 @@ func (c *BlueskyClient) refreshSession() (*atpSessionResponse, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("session refresh failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("bluesky", "session refresh", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -635,7 +1037,7 @@ func (c *BlueskyClient) refreshSession() (*atpSessionResponse, error) {
 		fmt.Printf("✅ Session refreshed, expires at %s\n", expTime.Format("15:04:05"))
 	} else {
 		// Fallback to default 24 hours
-		expTime := time.Now().Add(24 * time.Hour)
+		expTime := c.clock.Now().Add(24 * time.Hour)
 		c.sessionManager.UpdateSession(refreshedSession.AccessJwt, refreshedSession.RefreshJwt, expTime, &Credentials{})
 		logger.Debug().Time("expires_at", expTime).Msg("Session refreshed with default 24h expiration")
 		fmt.Printf("✅ Session refreshed with default 24h expiration\n")
@@ -674,15 +1076,15 @@ func (c *BlueskyClient) parseJWTExpiration(token string) (time.Time, error) {
 
 	if claims.Exp == 0 {
 		// No expiration in token, fall back to default
-		return time.Now().Add(24 * time.Hour), nil
+		return c.clock.Now().Add(24 * time.Hour), nil
 	}
 
 	return time.Unix(claims.Exp, 0), nil
 }
 
 // createNewSession creates a fresh session and stores it
-func (c *BlueskyClient) createNewSession(creds *Credentials) (*atpSessionResponse, error) {
-	session, err := c.createSession(creds)
+func (c *BlueskyClient) createNewSession(ctx context.Context, creds *Credentials) (*atpSessionResponse, error) {
+	session, err := c.createSession(ctx, creds)
 	if err != nil {
 		return nil, err
 	}
@@ -698,7 +1100,7 @@ func (c *BlueskyClient) createNewSession(creds *Credentials) (*atpSessionRespons
 		fmt.Printf("✅ Session created, expires at %s\n", expTime.Format("15:04:05"))
 	} else {
 		// Fallback to default 24 hours
-		expTime := time.Now().Add(24 * time.Hour)
+		expTime := c.clock.Now().Add(24 * time.Hour)
 		c.sessionManager.UpdateSession(session.AccessJwt, session.RefreshJwt, expTime, creds)
 		logger.Debug().Time("expires_at", expTime).Msg("Session created with default 24h expiration")
 		fmt.Printf("✅ Session created with default 24h expiration\n")
@@ -708,8 +1110,9 @@ func (c *BlueskyClient) createNewSession(creds *Credentials) (*atpSessionRespons
 }
 
 // createSession authenticates with AT Protocol and returns access token
-func (c *BlueskyClient) createSession(creds *Credentials) (*atpSessionResponse, error) {
-	sessionURL := "https://bsky.social/xrpc/com.atproto.server.createSession"
+func (c *BlueskyClient) createSession(ctx context.Context, creds *Credentials) (*atpSessionResponse, error) {
+	pds := blueskyPDS(creds)
+	sessionURL := pds + "/xrpc/com.atproto.server.createSession"
 
 	sessionData := map[string]string{
 		"identifier": creds.Username,
@@ -721,17 +1124,15 @@ func (c *BlueskyClient) createSession(creds *Credentials) (*atpSessionResponse,
 		return nil, fmt.Errorf("failed to marshal session data: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", sessionURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", sessionURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("POST", sessionURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("POST", sessionURL, resp.StatusCode, resp.Status)
+	client := sharedHTTPClient()
+	resp, err := doRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("session request failed: %w", err)
 	}
@@ -739,7 +1140,7 @@ func (c *BlueskyClient) createSession(creds *Credentials) (*atpSessionResponse,
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("session creation failed with status %d: %s. This may indicate invalid credentials or DID resolution issues", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%w. This may indicate invalid credentials, DID resolution issues, or an account on another PDS than %s", newAPIError("bluesky", "session creation", resp.StatusCode, body), blueskyHostName(pds))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -752,12 +1153,13 @@ func (c *BlueskyClient) createSession(creds *Credentials) (*atpSessionResponse,
 		return nil, fmt.Errorf("failed to parse session response: %w", err)
 	}
 
+	c.pds = pds
 	return &session, nil
 }
 
 // deletePost deletes a Bluesky post using AT Protocol
-func (c *BlueskyClient) deletePost(creds *Credentials, postURI string) error {
-	session, err := c.ensureValidSession(creds)
+func (c *BlueskyClient) deletePost(ctx context.Context, creds *Credentials, postURI string) error {
+	session, err := c.ensureValidSession(ctx, creds)
 	if err != nil {
 		return fmt.Errorf("failed to ensure valid session: %w", err)
 	}
@@ -778,7 +1180,7 @@ func (c *BlueskyClient) deletePost(creds *Credentials, postURI string) error {
 		return fmt.Errorf("DID mismatch: post DID %s does not match authenticated user DID %s. This suggests the post belongs to a different user or there's a DID resolution issue", did, session.DID)
 	}
 
-	deleteURL := "https://bsky.social/xrpc/com.atproto.repo.deleteRecord"
+	deleteURL := c.xrpcURL("com.atproto.repo.deleteRecord")
 
 	deleteData := map[string]string{
 		"repo":       session.DID, // Use authenticated user's DID instead of post DID
@@ -791,18 +1193,16 @@ func (c *BlueskyClient) deletePost(creds *Credentials, postURI string) error {
 		return fmt.Errorf("failed to marshal delete data: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", deleteURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", deleteURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create delete request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
 	req.Header.Set("Content-Type", "application/json")
+	// deleteRecord succeeds for a record that's already gone, so it's safe to retry
+	markIdempotent(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("POST", deleteURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("POST", deleteURL, resp.StatusCode, resp.Status)
+	resp, err := c.doAuthenticated(req, session)
 	if err != nil {
 		return fmt.Errorf("delete request failed: %w", err)
 	}
@@ -810,7 +1210,7 @@ func (c *BlueskyClient) deletePost(creds *Credentials, postURI string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete request failed with status %d: %s. DID used: %s, rkey: %s", resp.StatusCode, string(body), session.DID, rkey)
+		return fmt.Errorf("%w. DID used: %s, rkey: %s", newAPIError("bluesky", "delete request", resp.StatusCode, body), session.DID, rkey)
 	}
 
 	return nil
@@ -833,8 +1233,8 @@ func (c *BlueskyClient) validatePostURI(postURI string, userDID string) error {
 }
 
 // fetchLikedPosts fetches posts that the user has liked
-func (c *BlueskyClient) fetchLikedPosts(session *atpSessionResponse, limit int) ([]Post, error) {
-	listURL := "https://bsky.social/xrpc/com.atproto.repo.listRecords"
+func (c *BlueskyClient) fetchLikedPosts(ctx context.Context, session *atpSessionResponse, limit int) ([]Post, error) {
+	listURL := c.xrpcURL("com.atproto.repo.listRecords")
 
 	params := url.Values{}
 	params.Add("repo", session.DID)
@@ -843,17 +1243,12 @@ func (c *BlueskyClient) fetchLikedPosts(session *atpSessionResponse, limit int)
 
 	fullURL := fmt.Sprintf("%s?%s", listURL, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create list request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("GET", fullURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
+	resp, err := c.doAuthenticated(req, session)
 	if err != nil {
 		return nil, fmt.Errorf("list request failed: %w", err)
 	}
@@ -861,7 +1256,7 @@ func (c *BlueskyClient) fetchLikedPosts(session *atpSessionResponse, limit int)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("bluesky", "list request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -893,22 +1288,27 @@ func (c *BlueskyClient) fetchLikedPosts(session *atpSessionResponse, limit int)
 			Platform:  "bluesky",
 			CreatedAt: record.Value.CreatedAt,
 			Content:   fmt.Sprintf("Liked: %s", record.Value.Subject.URI), // Show what was liked
+
+			OriginalPost: &Post{ID: record.Value.Subject.URI, Type: PostTypeOriginal, Platform: "bluesky"},
 		}
 		likedPosts = append(likedPosts, post)
 	}
 
+	c.hydrateSubjects(ctx, likedPosts)
 	return likedPosts, nil
 }
 
-// fetchAllRepostPosts fetches ALL repost records using pagination based on age criteria
-func (c *BlueskyClient) fetchAllRepostPosts(session *atpSessionResponse, options PruneOptions) ([]Post, error) {
+// fetchAllRepostPosts fetches repost records, walking every page when ContinueUntilEnd is set.
+// The returned bool is true if the listing stopped before the end of the collection.
+func (c *BlueskyClient) fetchAllRepostPosts(ctx context.Context, session *atpSessionResponse, options PruneOptions) ([]Post, bool, error) {
 	var allRepostPosts []Post
 	cursor := ""
 	previousCursor := ""
 	batchSize := 100
+	truncated := false
 	
 	for {
-		listURL := "https://bsky.social/xrpc/com.atproto.repo.listRecords"
+		listURL := c.xrpcURL("com.atproto.repo.listRecords")
 		params := url.Values{}
 		params.Add("repo", session.DID)
 		params.Add("collection", "app.bsky.feed.repost")
@@ -919,30 +1319,25 @@ func (c *BlueskyClient) fetchAllRepostPosts(session *atpSessionResponse, options
 		
 		fullURL := fmt.Sprintf("%s?%s", listURL, params.Encode())
 		
-		req, err := http.NewRequest("GET", fullURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create list request: %w", err)
+			return nil, false, fmt.Errorf("failed to create list request: %w", err)
 		}
 		
-		req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-		
-		client := &http.Client{Timeout: 30 * time.Second}
-		LogHTTPRequest("GET", fullURL)
-		resp, err := client.Do(req)
-		LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
+		resp, err := c.doAuthenticated(req, session)
 		if err != nil {
-			return nil, fmt.Errorf("list request failed: %w", err)
+			return nil, false, fmt.Errorf("list request failed: %w", err)
 		}
 		defer resp.Body.Close()
 		
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("list request failed with status %d: %s", resp.StatusCode, string(body))
+			return nil, false, newAPIError("bluesky", "list request", resp.StatusCode, body)
 		}
 		
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read list response: %w", err)
+			return nil, false, fmt.Errorf("failed to read list response: %w", err)
 		}
 		
 		var listResponse struct {
@@ -959,59 +1354,60 @@ func (c *BlueskyClient) fetchAllRepostPosts(session *atpSessionResponse, options
 		}
 		
 		if err := json.Unmarshal(body, &listResponse); err != nil {
-			return nil, fmt.Errorf("failed to parse list response: %w", err)
+			return nil, false, fmt.Errorf("failed to parse list response: %w", err)
 		}
 		
 		if len(listResponse.Records) == 0 {
 			break // No more reposts to fetch
 		}
 		
-		// Check if we should continue fetching based on age criteria
-		shouldContinue := false
+		pageStart := len(allRepostPosts)
 		for _, record := range listResponse.Records {
 			post := Post{
-				ID:        record.URI, // This is the repost record URI, not the original post
-				Type:      PostTypeRepost,
-				Platform:  "bluesky",
-				CreatedAt: record.Value.CreatedAt,
-				Content:   fmt.Sprintf("Reposted: %s", record.Value.Subject.URI), // Show what was reposted
+				ID:           record.URI, // This is the repost record URI, not the original post
+				Type:         PostTypeRepost,
+				Platform:     "bluesky",
+				CreatedAt:    record.Value.CreatedAt,
+				Content:      fmt.Sprintf("Reposted: %s", record.Value.Subject.URI), // Show what was reposted
+				OriginalPost: &Post{ID: record.Value.Subject.URI, Type: PostTypeOriginal, Platform: "bluesky"},
+				SelfRepost:   atURIRepo(record.Value.Subject.URI) == atURIRepo(record.URI),
 			}
 			allRepostPosts = append(allRepostPosts, post)
-			
-			// Check if any repost in this batch matches the age criteria
-			if options.MaxAge != nil && time.Now().Sub(record.Value.CreatedAt) > *options.MaxAge {
-				shouldContinue = true
-			}
-			if options.BeforeDate != nil && record.Value.CreatedAt.Before(*options.BeforeDate) {
-				shouldContinue = true
-			}
 		}
 		
 		// Update cursor for next request with infinite loop protection
 		newCursor := listResponse.Cursor
 		if newCursor == previousCursor && newCursor != "" {
+			truncated = true
 			break // Prevent infinite loop from duplicate cursors
 		}
 		previousCursor = cursor
 		cursor = newCursor
 		
-		if cursor == "" || !shouldContinue {
-			break // No more pages or no reposts match age criteria
+		if cursor == "" {
+			break // No more pages
+		}
+		if !options.ContinueUntilEnd && !options.pageMatchesAge(allRepostPosts[pageStart:], c.clock.Now()) {
+			truncated = true
+			break // Nothing old enough on this page, older records remain
 		}
 	}
 	
-	return allRepostPosts, nil
+	c.hydrateSubjects(ctx, allRepostPosts)
+	return allRepostPosts, truncated, nil
 }
 
-// fetchAllLikedPosts fetches ALL liked posts using pagination based on age criteria
-func (c *BlueskyClient) fetchAllLikedPosts(session *atpSessionResponse, options PruneOptions) ([]Post, error) {
+// fetchAllLikedPosts fetches like records, walking every page when ContinueUntilEnd is set.
+// The returned bool is true if the listing stopped before the end of the collection.
+func (c *BlueskyClient) fetchAllLikedPosts(ctx context.Context, session *atpSessionResponse, options PruneOptions) ([]Post, bool, error) {
 	var allLikedPosts []Post
 	cursor := ""
 	previousCursor := ""
 	batchSize := 100
+	truncated := false
 	
 	for {
-		listURL := "https://bsky.social/xrpc/com.atproto.repo.listRecords"
+		listURL := c.xrpcURL("com.atproto.repo.listRecords")
 		params := url.Values{}
 		params.Add("repo", session.DID)
 		params.Add("collection", "app.bsky.feed.like")
@@ -1022,30 +1418,25 @@ func (c *BlueskyClient) fetchAllLikedPosts(session *atpSessionResponse, options
 		
 		fullURL := fmt.Sprintf("%s?%s", listURL, params.Encode())
 		
-		req, err := http.NewRequest("GET", fullURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create list request: %w", err)
+			return nil, false, fmt.Errorf("failed to create list request: %w", err)
 		}
 		
-		req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-		
-		client := &http.Client{Timeout: 30 * time.Second}
-		LogHTTPRequest("GET", fullURL)
-		resp, err := client.Do(req)
-		LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
+		resp, err := c.doAuthenticated(req, session)
 		if err != nil {
-			return nil, fmt.Errorf("list request failed: %w", err)
+			return nil, false, fmt.Errorf("list request failed: %w", err)
 		}
 		defer resp.Body.Close()
 		
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("list request failed with status %d: %s", resp.StatusCode, string(body))
+			return nil, false, newAPIError("bluesky", "list request", resp.StatusCode, body)
 		}
 		
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read list response: %w", err)
+			return nil, false, fmt.Errorf("failed to read list response: %w", err)
 		}
 		
 		var listResponse struct {
@@ -1062,15 +1453,14 @@ func (c *BlueskyClient) fetchAllLikedPosts(session *atpSessionResponse, options
 		}
 		
 		if err := json.Unmarshal(body, &listResponse); err != nil {
-			return nil, fmt.Errorf("failed to parse list response: %w", err)
+			return nil, false, fmt.Errorf("failed to parse list response: %w", err)
 		}
 		
 		if len(listResponse.Records) == 0 {
 			break // No more likes to fetch
 		}
 		
-		// Check if we should continue fetching based on age criteria
-		shouldContinue := false
+		pageStart := len(allLikedPosts)
 		for _, record := range listResponse.Records {
 			post := Post{
 				ID:        record.URI, // This is the like record URI, not the original post
@@ -1078,37 +1468,37 @@ func (c *BlueskyClient) fetchAllLikedPosts(session *atpSessionResponse, options
 				Platform:  "bluesky",
 				CreatedAt: record.Value.CreatedAt,
 				Content:   fmt.Sprintf("Liked: %s", record.Value.Subject.URI), // Show what was liked
+
+				OriginalPost: &Post{ID: record.Value.Subject.URI, Type: PostTypeOriginal, Platform: "bluesky"},
 			}
 			allLikedPosts = append(allLikedPosts, post)
-			
-			// Check if any like in this batch matches the age criteria
-			if options.MaxAge != nil && time.Now().Sub(record.Value.CreatedAt) > *options.MaxAge {
-				shouldContinue = true
-			}
-			if options.BeforeDate != nil && record.Value.CreatedAt.Before(*options.BeforeDate) {
-				shouldContinue = true
-			}
 		}
 		
 		// Update cursor for next request with infinite loop protection
 		newCursor := listResponse.Cursor
 		if newCursor == previousCursor && newCursor != "" {
+			truncated = true
 			break // Prevent infinite loop from duplicate cursors
 		}
 		previousCursor = cursor
 		cursor = newCursor
 		
-		if cursor == "" || !shouldContinue {
-			break // No more pages or no likes match age criteria
+		if cursor == "" {
+			break // No more pages
+		}
+		if !options.ContinueUntilEnd && !options.pageMatchesAge(allLikedPosts[pageStart:], c.clock.Now()) {
+			truncated = true
+			break // Nothing old enough on this page, older records remain
 		}
 	}
 	
-	return allLikedPosts, nil
+	c.hydrateSubjects(ctx, allLikedPosts)
+	return allLikedPosts, truncated, nil
 }
 
 // deleteLikeRecord deletes a like record directly
-func (c *BlueskyClient) deleteLikeRecord(creds *Credentials, likeURI string) error {
-	session, err := c.ensureValidSession(creds)
+func (c *BlueskyClient) deleteLikeRecord(ctx context.Context, creds *Credentials, likeURI string) error {
+	session, err := c.ensureValidSession(ctx, creds)
 	if err != nil {
 		return fmt.Errorf("failed to ensure valid session: %w", err)
 	}
@@ -1129,7 +1519,7 @@ func (c *BlueskyClient) deleteLikeRecord(creds *Credentials, likeURI string) err
 		return fmt.Errorf("DID mismatch: like DID %s does not match authenticated user DID %s", did, session.DID)
 	}
 
-	deleteURL := "https://bsky.social/xrpc/com.atproto.repo.deleteRecord"
+	deleteURL := c.xrpcURL("com.atproto.repo.deleteRecord")
 
 	deleteData := map[string]string{
 		"repo":       session.DID,
@@ -1142,18 +1532,15 @@ func (c *BlueskyClient) deleteLikeRecord(creds *Credentials, likeURI string) err
 		return fmt.Errorf("failed to marshal delete data: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", deleteURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", deleteURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create delete request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
 	req.Header.Set("Content-Type", "application/json")
+	markIdempotent(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("POST", deleteURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("POST", deleteURL, resp.StatusCode, resp.Status)
+	resp, err := c.doAuthenticated(req, session)
 	if err != nil {
 		return fmt.Errorf("delete request failed: %w", err)
 	}
@@ -1161,14 +1548,14 @@ func (c *BlueskyClient) deleteLikeRecord(creds *Credentials, likeURI string) err
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete like failed with status %d: %s. DID used: %s, rkey: %s", resp.StatusCode, string(body), session.DID, rkey)
+		return fmt.Errorf("%w. DID used: %s, rkey: %s", newAPIError("bluesky", "delete like", resp.StatusCode, body), session.DID, rkey)
 	}
 
 	return nil
 }
 
 // fetchLikedPostsIntegrated fetches liked posts with authentication handling for integration
-func (c *BlueskyClient) fetchLikedPostsIntegrated(limit int) ([]Post, error) {
+func (c *BlueskyClient) fetchLikedPostsIntegrated(ctx context.Context, limit int) ([]Post, error) {
 	// Get authentication credentials
 	creds, err := GetCredentialsForPlatform("bluesky")
 	if err != nil {
@@ -1182,17 +1569,17 @@ func (c *BlueskyClient) fetchLikedPostsIntegrated(limit int) ([]Post, error) {
 	}
 
 	// Create session and fetch liked posts
-	session, err := c.ensureValidSession(creds)
+	session, err := c.ensureValidSession(ctx, creds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to authenticate for liked posts: %w", err)
 	}
 
-	return c.fetchLikedPosts(session, limit)
+	return c.fetchLikedPosts(ctx, session, limit)
 }
 
 // deleteRepostRecord deletes a repost record directly (simpler than unrepost)
-func (c *BlueskyClient) deleteRepostRecord(creds *Credentials, repostURI string) error {
-	session, err := c.ensureValidSession(creds)
+func (c *BlueskyClient) deleteRepostRecord(ctx context.Context, creds *Credentials, repostURI string) error {
+	session, err := c.ensureValidSession(ctx, creds)
 	if err != nil {
 		return fmt.Errorf("failed to ensure valid session: %w", err)
 	}
@@ -1213,7 +1600,7 @@ func (c *BlueskyClient) deleteRepostRecord(creds *Credentials, repostURI string)
 		return fmt.Errorf("DID mismatch: repost DID %s does not match authenticated user DID %s", did, session.DID)
 	}
 
-	deleteURL := "https://bsky.social/xrpc/com.atproto.repo.deleteRecord"
+	deleteURL := c.xrpcURL("com.atproto.repo.deleteRecord")
 
 	deleteData := map[string]string{
 		"repo":       session.DID,
@@ -1226,18 +1613,15 @@ func (c *BlueskyClient) deleteRepostRecord(creds *Credentials, repostURI string)
 		return fmt.Errorf("failed to marshal delete data: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", deleteURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", deleteURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create delete request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
 	req.Header.Set("Content-Type", "application/json")
+	markIdempotent(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	LogHTTPRequest("POST", deleteURL)
-	resp, err := client.Do(req)
-	LogHTTPResponse("POST", deleteURL, resp.StatusCode, resp.Status)
+	resp, err := c.doAuthenticated(req, session)
 	if err != nil {
 		return fmt.Errorf("delete request failed: %w", err)
 	}
@@ -1245,7 +1629,7 @@ func (c *BlueskyClient) deleteRepostRecord(creds *Credentials, repostURI string)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete repost failed with status %d: %s. DID used: %s, rkey: %s", resp.StatusCode, string(body), session.DID, rkey)
+		return fmt.Errorf("%w. DID used: %s, rkey: %s", newAPIError("bluesky", "delete repost", resp.StatusCode, body), session.DID, rkey)
 	}
 
 	return nil
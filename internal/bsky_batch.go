@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// blueskyMaxBatchWrites is the most record operations com.atproto.repo.applyWrites
+// accepts in one call
+const blueskyMaxBatchWrites = 200
+
+// blueskyBatch queues the record deletions of a prune with BatchWrites set, and sends
+// them with applyWrites once a batch is full or the run ends
+type blueskyBatch struct {
+	client  *BlueskyClient
+	creds   *Credentials
+	session *atpSessionResponse
+	options PruneOptions
+	pending []Post
+}
+
+// add queues a post's record for deletion, and sends the batch once it's full
+func (b *blueskyBatch) add(ctx context.Context, post Post, report pruneReport) error {
+	b.pending = append(b.pending, post)
+	if len(b.pending) < blueskyMaxBatchWrites {
+		return nil
+	}
+	return b.flush(ctx, report)
+}
+
+// flush sends the queued deletions in one applyWrites call. The call is all or nothing,
+// so if it fails over a record the batch is retried one record at a time, and only the
+// records that really can't be deleted are reported as errors. A rate limit or a spent
+// request budget fails the whole batch instead, as every single delete would hit it too.
+func (b *blueskyBatch) flush(ctx context.Context, report pruneReport) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	pending := b.pending
+	b.pending = nil
+
+	if err := sleepContext(ctx, b.options.RateLimitDelay); err != nil {
+		return err
+	}
+	err := b.client.applyDeletes(ctx, b.session, pending)
+	if err == nil {
+		for _, post := range pending {
+			report(PruneAction(post), post, nil)
+		}
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if IsRateLimited(err) || errors.Is(err, ErrRequestBudgetExhausted) {
+		for _, post := range pending {
+			report(PruneAction(post), post, err)
+		}
+		return nil
+	}
+
+	WithPlatform("bluesky").Warn().Err(err).Int("records", len(pending)).Msg("Batch delete failed, deleting records one at a time")
+	for i, post := range pending {
+		if i > 0 {
+			if err := sleepContext(ctx, b.options.RateLimitDelay); err != nil {
+				return err
+			}
+		}
+		report(PruneAction(post), post, b.client.deletePost(ctx, b.creds, post.ID))
+	}
+	return nil
+}
+
+// blueskyDeleteWrite is a delete operation in an applyWrites request
+type blueskyDeleteWrite struct {
+	Type       string `json:"$type"`
+	Collection string `json:"collection"`
+	RKey       string `json:"rkey"`
+}
+
+// deleteWrites builds the applyWrites operations deleting the records behind
+// posts, all of which must be in the repo of did
+func (c *BlueskyClient) deleteWrites(posts []Post, did string) ([]blueskyDeleteWrite, error) {
+	writes := make([]blueskyDeleteWrite, 0, len(posts))
+	for _, post := range posts {
+		if err := c.validatePostURI(post.ID, did); err != nil {
+			return nil, err
+		}
+		parts := strings.Split(post.ID, "/")
+		writes = append(writes, blueskyDeleteWrite{
+			Type:       "com.atproto.repo.applyWrites#delete",
+			Collection: strings.Join(parts[3:len(parts)-1], "/"),
+			RKey:       parts[len(parts)-1],
+		})
+	}
+	return writes, nil
+}
+
+// applyDeletes deletes the records behind posts, all of which must be in the session's
+// repo, with a single com.atproto.repo.applyWrites call
+func (c *BlueskyClient) applyDeletes(ctx context.Context, session *atpSessionResponse, posts []Post) error {
+	writes, err := c.deleteWrites(posts, session.DID)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"repo":   session.DID,
+		"writes": writes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.xrpcURL("com.atproto.repo.applyWrites"), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	markIdempotent(req) // The batch only deletes, which is safe to repeat
+
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return fmt.Errorf("batch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError("bluesky", "batch delete", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variables naming the Bluesky servers to use instead of Bluesky's own
+const (
+	BlueskyPDSEnvVar     = "BLUESKY_PDS"     // PDS that app password logins and record writes go to
+	BlueskyAppViewEnvVar = "BLUESKY_APPVIEW" // AppView that public timelines and profiles are read from
+)
+
+const (
+	defaultBlueskyPDS     = "https://bsky.social"
+	defaultBlueskyAppView = "https://public.api.bsky.app"
+)
+
+var (
+	blueskyPDSOverride     string
+	blueskyAppViewOverride string
+	blueskyHostsMu         sync.RWMutex
+)
+
+// SetBlueskyHosts sets the PDS and AppView for this process, taking precedence over
+// saved credentials and the environment. An empty value leaves that one unset.
+func SetBlueskyHosts(pds, appView string) error {
+	pds, err := NormalizeBlueskyHost(pds)
+	if err != nil {
+		return fmt.Errorf("invalid Bluesky PDS: %w", err)
+	}
+	appView, err = NormalizeBlueskyHost(appView)
+	if err != nil {
+		return fmt.Errorf("invalid Bluesky AppView: %w", err)
+	}
+
+	blueskyHostsMu.Lock()
+	defer blueskyHostsMu.Unlock()
+	blueskyPDSOverride = pds
+	blueskyAppViewOverride = appView
+	return nil
+}
+
+// NormalizeBlueskyHost turns a PDS or AppView given as a hostname or URL into a base URL
+// with no trailing slash, assuming https when no scheme is given. Empty stays empty.
+func NormalizeBlueskyHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", nil
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return "", fmt.Errorf("%s: scheme must be https or http", host)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("%s: no host given", host)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("%s: give the server's base URL, without a query", host)
+	}
+	return strings.TrimRight(parsed.String(), "/"), nil
+}
+
+// blueskyPDS returns the PDS to log in to with an app password: --bluesky-pds, then
+// the credentials' instance, then $BLUESKY_PDS, then bsky.social. creds may be nil.
+// OAuth logins ignore this, as they find the account's PDS from its DID.
+func blueskyPDS(creds *Credentials) string {
+	blueskyHostsMu.RLock()
+	override := blueskyPDSOverride
+	blueskyHostsMu.RUnlock()
+	if override != "" {
+		return override
+	}
+
+	candidates := []string{os.Getenv(BlueskyPDSEnvVar)}
+	if creds != nil {
+		candidates = append([]string{creds.Instance}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if host, err := NormalizeBlueskyHost(candidate); err == nil && host != "" {
+			return host
+		}
+	}
+	return defaultBlueskyPDS
+}
+
+// blueskyAppView returns the AppView to read public data from: --bluesky-appview, then
+// $BLUESKY_APPVIEW, then Bluesky's public AppView
+func blueskyAppView() string {
+	blueskyHostsMu.RLock()
+	override := blueskyAppViewOverride
+	blueskyHostsMu.RUnlock()
+	if override != "" {
+		return override
+	}
+
+	if host, err := NormalizeBlueskyHost(os.Getenv(BlueskyAppViewEnvVar)); err == nil && host != "" {
+		return host
+	}
+	return defaultBlueskyAppView
+}
+
+// blueskyHostName returns the host part of a PDS or AppView base URL, for display
+func blueskyHostName(base string) string {
+	if parsed, err := url.Parse(base); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return base
+}
@@ -0,0 +1,376 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withBlueskyHosts points Bluesky at the given PDS and AppView for the duration of a test
+func withBlueskyHosts(t *testing.T, pds, appView string) {
+	t.Helper()
+	blueskyHostsMu.RLock()
+	previousPDS, previousAppView := blueskyPDSOverride, blueskyAppViewOverride
+	blueskyHostsMu.RUnlock()
+	if err := SetBlueskyHosts(pds, appView); err != nil {
+		t.Fatalf("SetBlueskyHosts() error = %v", err)
+	}
+	t.Cleanup(func() { SetBlueskyHosts(previousPDS, previousAppView) })
+}
+
+func TestNormalizeBlueskyHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"pds.example.com", "https://pds.example.com", false},
+		{"https://pds.example.com/", "https://pds.example.com", false},
+		{"  http://localhost:2583  ", "http://localhost:2583", false},
+		{"ftp://pds.example.com", "", true},
+		{"https://", "", true},
+		{"https://pds.example.com/?x=1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := NormalizeBlueskyHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeBlueskyHost(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeBlueskyHost(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlueskyPDS(t *testing.T) {
+	saved := &Credentials{Platform: "bluesky", Instance: "saved.example.com"}
+
+	t.Run("defaults to bsky.social", func(t *testing.T) {
+		t.Setenv(BlueskyPDSEnvVar, "")
+		if got := blueskyPDS(nil); got != defaultBlueskyPDS {
+			t.Errorf("Expected %q, got %q", defaultBlueskyPDS, got)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(BlueskyPDSEnvVar, "env.example.com")
+		if got := blueskyPDS(nil); got != "https://env.example.com" {
+			t.Errorf("Expected the environment's PDS, got %q", got)
+		}
+		if got := blueskyPDS(&Credentials{Platform: "bluesky"}); got != "https://env.example.com" {
+			t.Errorf("Expected the environment's PDS for credentials without one, got %q", got)
+		}
+	})
+
+	t.Run("credentials win over environment", func(t *testing.T) {
+		t.Setenv(BlueskyPDSEnvVar, "env.example.com")
+		if got := blueskyPDS(saved); got != "https://saved.example.com" {
+			t.Errorf("Expected the saved PDS, got %q", got)
+		}
+	})
+
+	t.Run("flag wins over everything", func(t *testing.T) {
+		t.Setenv(BlueskyPDSEnvVar, "env.example.com")
+		withBlueskyHosts(t, "flag.example.com", "")
+		if got := blueskyPDS(saved); got != "https://flag.example.com" {
+			t.Errorf("Expected the flag's PDS, got %q", got)
+		}
+	})
+}
+
+func TestBlueskyAppView(t *testing.T) {
+	t.Setenv(BlueskyAppViewEnvVar, "")
+	if got := blueskyAppView(); got != defaultBlueskyAppView {
+		t.Errorf("Expected %q, got %q", defaultBlueskyAppView, got)
+	}
+
+	t.Setenv(BlueskyAppViewEnvVar, "appview.example.com")
+	if got := blueskyAppView(); got != "https://appview.example.com" {
+		t.Errorf("Expected the environment's AppView, got %q", got)
+	}
+
+	withBlueskyHosts(t, "", "https://flag.example.com")
+	if got := blueskyAppView(); got != "https://flag.example.com" {
+		t.Errorf("Expected the flag's AppView, got %q", got)
+	}
+}
+
+func TestBlueskyClient_CustomPDS(t *testing.T) {
+	var paths []string
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewEncoder(w).Encode(atpSessionResponse{AccessJwt: "access", RefreshJwt: "refresh", Handle: "me.example.com", DID: "did:plc:me"})
+		case "/xrpc/com.atproto.repo.applyWrites":
+			if got := r.Header.Get("Authorization"); got != "Bearer access" {
+				t.Errorf("Expected the session's token, got %q", got)
+			}
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(pds.Close)
+
+	client := NewBlueskyClient()
+	creds := &Credentials{Platform: "bluesky", Username: "me.example.com", Instance: pds.URL, AppPassword: "secret"}
+	session, err := client.ensureValidSession(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("ensureValidSession() error = %v", err)
+	}
+	if err := client.applyDeletes(context.Background(), session, []Post{{ID: "at://did:plc:me/app.bsky.feed.post/abc"}}); err != nil {
+		t.Fatalf("applyDeletes() error = %v", err)
+	}
+
+	want := []string{"/xrpc/com.atproto.server.createSession", "/xrpc/com.atproto.repo.applyWrites"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("Expected requests %v on the custom PDS, got %v", want, paths)
+	}
+}
+
+func TestBlueskyClient_RetriesDeletes(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	calls := make(map[string]int)
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewEncoder(w).Encode(atpSessionResponse{AccessJwt: "access", RefreshJwt: "refresh", Handle: "me.example.com", DID: "did:plc:me"})
+		case "/xrpc/com.atproto.repo.deleteRecord", "/xrpc/com.atproto.repo.applyWrites":
+			body, _ := io.ReadAll(r.Body)
+			calls[string(body)]++
+			// Every request fails once before it goes through
+			if calls[string(body)] == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(pds.Close)
+
+	client := NewBlueskyClient()
+	ctx := context.Background()
+	creds := &Credentials{Platform: "bluesky", Username: "me.example.com", Instance: pds.URL, AppPassword: "secret"}
+	session, err := client.ensureValidSession(ctx, creds)
+	if err != nil {
+		t.Fatalf("ensureValidSession() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		do   func() error
+	}{
+		{"delete", func() error { return client.deletePost(ctx, creds, "at://did:plc:me/app.bsky.feed.post/abc") }},
+		{"unlike", func() error { return client.deleteLikeRecord(ctx, creds, "at://did:plc:me/app.bsky.feed.like/abc") }},
+		{"unrepost", func() error { return client.deleteRepostRecord(ctx, creds, "at://did:plc:me/app.bsky.feed.repost/abc") }},
+		{"batch", func() error {
+			return client.applyDeletes(ctx, session, []Post{{ID: "at://did:plc:me/app.bsky.feed.post/def"}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.do(); err != nil {
+				t.Errorf("Expected the failed request retried, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBlueskyBatch_FlushFailures(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 0})
+	posts := []Post{
+		{ID: "at://did:plc:me/app.bsky.feed.post/one", Type: PostTypeOriginal},
+		{ID: "at://did:plc:me/app.bsky.feed.post/two", Type: PostTypeOriginal},
+	}
+	tests := []struct {
+		name        string
+		batchStatus int
+		spent       bool // The request budget is used up before the batch is sent
+		wantSingles int
+		wantErr     string
+	}{
+		{"a bad record falls back to single deletes", http.StatusBadRequest, false, 2, ""},
+		{"a rate limit fails the batch", http.StatusTooManyRequests, false, 0, "status 429"},
+		{"a spent request budget fails the batch", http.StatusOK, true, 0, ErrRequestBudgetExhausted.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			singles := 0
+			pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/xrpc/com.atproto.server.createSession":
+					json.NewEncoder(w).Encode(atpSessionResponse{AccessJwt: "access", RefreshJwt: "refresh", Handle: "me.example.com", DID: "did:plc:me"})
+				case "/xrpc/com.atproto.repo.applyWrites":
+					w.WriteHeader(tt.batchStatus)
+					w.Write([]byte(`{"error": "nope"}`))
+				case "/xrpc/com.atproto.repo.deleteRecord":
+					singles++
+					w.Write([]byte("{}"))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(pds.Close)
+
+			client := NewBlueskyClient()
+			creds := &Credentials{Platform: "bluesky", Username: "me.example.com", Instance: pds.URL, AppPassword: "secret"}
+			session, err := client.ensureValidSession(context.Background(), creds)
+			if err != nil {
+				t.Fatalf("ensureValidSession() error = %v", err)
+			}
+			ctx := context.Background()
+			if tt.spent {
+				budget := NewRequestBudget(1)
+				budget.take()
+				ctx = WithRequestBudget(ctx, budget)
+			}
+
+			batch := &blueskyBatch{client: client, creds: creds, session: session, pending: posts}
+			var failed []string
+			err = batch.flush(ctx, func(action string, post Post, err error) {
+				if err != nil {
+					failed = append(failed, err.Error())
+				}
+			})
+			if err != nil {
+				t.Fatalf("flush() error = %v", err)
+			}
+			if singles != tt.wantSingles {
+				t.Errorf("Expected %d single deletes, got %d", tt.wantSingles, singles)
+			}
+			if tt.wantErr == "" && len(failed) != 0 {
+				t.Errorf("Expected every record deleted one at a time, got errors %v", failed)
+			}
+			if tt.wantErr != "" && (len(failed) != len(posts) || !strings.Contains(failed[0], tt.wantErr)) {
+				t.Errorf("Expected every record reported with %q, got %v", tt.wantErr, failed)
+			}
+		})
+	}
+}
+
+func TestBlueskyClient_VerifyAccount(t *testing.T) {
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewEncoder(w).Encode(atpSessionResponse{AccessJwt: "access", RefreshJwt: "refresh", Handle: "me.example.com", DID: "did:plc:me"})
+		case "/xrpc/app.bsky.actor.getProfile":
+			if r.Header.Get("Authorization") != "Bearer access" || r.URL.Query().Get("actor") != "did:plc:me" {
+				t.Errorf("Expected the session's own profile, got %s with %q", r.URL, r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"did": "did:plc:me", "handle": "me.example.com", "postsCount": 42, "createdAt": "2023-04-01T12:00:00.000Z"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(pds.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLUESKY_USER", "me.example.com")
+	t.Setenv("BLUESKY_PASSWORD", "secret")
+	t.Setenv(BlueskyPDSEnvVar, pds.URL)
+
+	account, err := NewBlueskyClient().VerifyAccount(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyAccount() error = %v", err)
+	}
+	if account.Username != "me.example.com" || account.PostCount != 42 || account.CreatedAt.Format("2006-01-02") != "2023-04-01" {
+		t.Errorf("Expected me.example.com with 42 posts created 2023-04-01, got %+v", account)
+	}
+	if account.ID != "did:plc:me" || !account.MatchesCredentials {
+		t.Errorf("Expected did:plc:me to match the credentials, got %+v", account)
+	}
+}
+
+// fakePagedBlueskyPDS serves an author feed and a repost collection of three pages of two
+// records each, newest first, dated by ages
+func fakePagedBlueskyPDS(t *testing.T, now time.Time, ages [3][2]time.Duration) (*httptest.Server, *[]string) {
+	t.Helper()
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := map[string]int{"": 0, "p2": 1, "p3": 2}[r.URL.Query().Get("cursor")]
+		requested = append(requested, fmt.Sprintf("%s#%d", path.Base(r.URL.Path), page+1))
+		next := ""
+		if page < 2 {
+			next = fmt.Sprintf("p%d", page+2)
+		}
+		var items []string
+		for i, age := range ages[page] {
+			createdAt := now.Add(-age).Format(time.RFC3339)
+			switch r.URL.Path {
+			case "/xrpc/app.bsky.feed.getAuthorFeed":
+				items = append(items, fmt.Sprintf(`{"post": {"uri": "at://did:plc:me/app.bsky.feed.post/post%d-%d", "author": {"did": "did:plc:me", "handle": "me.example.com"}, "record": {"$type": "app.bsky.feed.post", "text": "hi", "createdAt": %q}}}`, page, i, createdAt))
+			case "/xrpc/com.atproto.repo.listRecords":
+				items = append(items, fmt.Sprintf(`{"uri": "at://did:plc:me/app.bsky.feed.repost/repost%d-%d", "value": {"subject": {"uri": "at://did:plc:other/app.bsky.feed.post/x"}, "createdAt": %q}}`, page, i, createdAt))
+			}
+		}
+		switch r.URL.Path {
+		case "/xrpc/app.bsky.feed.getAuthorFeed":
+			fmt.Fprintf(w, `{"cursor": %q, "feed": [%s]}`, next, strings.Join(items, ","))
+		case "/xrpc/com.atproto.repo.listRecords":
+			fmt.Fprintf(w, `{"cursor": %q, "records": [%s]}`, next, strings.Join(items, ","))
+		case "/xrpc/app.bsky.feed.getPosts":
+			w.Write([]byte(`{"posts": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requested
+}
+
+func TestBlueskyClient_PruneWalksPagesWithOldPosts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	maxAge := 30 * day
+
+	tests := []struct {
+		name      string
+		ages      [3][2]time.Duration
+		continued bool
+		wantPages int
+	}{
+		{"old posts past the first page", [3][2]time.Duration{{day, 60 * day}, {90 * day, 100 * day}, {400 * day, 500 * day}}, false, 3},
+		{"stops at a page with nothing old enough", [3][2]time.Duration{{day, 2 * day}, {90 * day, 100 * day}, {400 * day, 500 * day}}, false, 1},
+		{"--continue walks everything", [3][2]time.Duration{{day, 2 * day}, {3 * day, 4 * day}, {5 * day, 6 * day}}, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pds, requested := fakePagedBlueskyPDS(t, now, tt.ages)
+			withBlueskyHosts(t, pds.URL, pds.URL)
+			client := NewBlueskyClient()
+			client.SetClock(NewFakeClock(now))
+			options := PruneOptions{MaxAge: &maxAge, ContinueUntilEnd: tt.continued}
+
+			posts, err := client.fetchPruneTimeline(context.Background(), "me.example.com", options)
+			if err != nil {
+				t.Fatalf("fetchPruneTimeline() error = %v", err)
+			}
+			if len(posts) != 2*tt.wantPages {
+				t.Errorf("Expected posts from %d pages, got %d (requests %v)", tt.wantPages, len(posts), *requested)
+			}
+
+			reposts, truncated, err := client.fetchAllRepostPosts(context.Background(), &atpSessionResponse{DID: "did:plc:me"}, options)
+			if err != nil {
+				t.Fatalf("fetchAllRepostPosts() error = %v", err)
+			}
+			if len(reposts) != 2*tt.wantPages || truncated != (tt.wantPages < 3) {
+				t.Errorf("Expected reposts from %d pages, got %d, truncated %v", tt.wantPages, len(reposts), truncated)
+			}
+		})
+	}
+}
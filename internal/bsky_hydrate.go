@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// blueskyMaxGetPosts is the most URIs app.bsky.feed.getPosts accepts in one call
+const blueskyMaxGetPosts = 25
+
+// fetchPostViews looks up posts by AT URI on the AppView, a batch at a time. Posts that
+// have been deleted, or that the AppView won't show, are missing from the result.
+func (c *BlueskyClient) fetchPostViews(ctx context.Context, uris []string) (map[string]Post, error) {
+	views := make(map[string]Post, len(uris))
+	for start := 0; start < len(uris); start += blueskyMaxGetPosts {
+		end := min(start+blueskyMaxGetPosts, len(uris))
+		params := url.Values{}
+		for _, uri := range uris[start:end] {
+			params.Add("uris", uri)
+		}
+
+		resp, err := httpGetWithRetry(ctx, blueskyAppView()+"/xrpc/app.bsky.feed.getPosts?"+params.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch posts: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, newAPIError("bluesky", "post lookup", resp.StatusCode, body)
+		}
+
+		var response struct {
+			Posts []blueskyPost `json:"posts"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		for _, view := range response.Posts {
+			views[view.URI] = c.viewPost(view)
+		}
+	}
+	return views, nil
+}
+
+// hydrateSubjects fills in the posts that like and repost records point at, so they show
+// who wrote the post, what it said and when, rather than just its URI. Records for posts
+// that can't be found are left as they are, and a failed lookup only costs the detail.
+func (c *BlueskyClient) hydrateSubjects(ctx context.Context, records []Post) {
+	var uris []string
+	for _, record := range records {
+		if blueskyHasSubject(record) {
+			uris = append(uris, record.OriginalPost.ID)
+		}
+	}
+	if len(uris) == 0 {
+		return
+	}
+
+	views, err := c.fetchPostViews(ctx, uris)
+	if err != nil {
+		WithPlatform("bluesky").Warn().Err(err).Msg("Failed to look up liked and reposted posts, showing them by URI")
+		return
+	}
+
+	for i := range records {
+		record := &records[i]
+		if !blueskyHasSubject(*record) {
+			continue
+		}
+		original, ok := views[record.OriginalPost.ID]
+		if !ok {
+			continue
+		}
+		verb := "Liked"
+		if record.Type == PostTypeRepost {
+			verb = "Reposted"
+		}
+		record.OriginalPost = &original
+		record.OriginalAuthor = original.Author
+		record.OriginalHandle = original.Handle
+		record.Handle = original.Handle
+		record.Content = fmt.Sprintf("%s post from %s: %s", verb, original.CreatedAt.Format("2006-01-02"), original.Content)
+	}
+}
+
+// blueskyHasSubject returns true for a like or repost record that points at a post
+func blueskyHasSubject(record Post) bool {
+	return (record.Type == PostTypeLike || record.Type == PostTypeRepost) && record.OriginalPost != nil
+}
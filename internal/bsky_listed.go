@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// blueskyListedCollections are the repo collections a listed record key is looked for
+// in, in order. Record keys are timestamps, so a key only ever names one of them.
+var blueskyListedCollections = []string{"app.bsky.feed.post", "app.bsky.feed.repost", "app.bsky.feed.like"}
+
+// blueskyListedRecord is a post, repost or like record fetched with getRecord
+type blueskyListedRecord struct {
+	URI   string `json:"uri"`
+	CID   string `json:"cid"`
+	Value struct {
+		blueskyRecord
+		Subject struct {
+			URI string `json:"uri"`
+		} `json:"subject"` // The post a repost or like points at
+	} `json:"value"`
+}
+
+// pruneListedPosts prunes just the records in options.ListedPosts, fetching each one from
+// the user's repo instead of walking the author feed and the like and repost collections
+func (c *BlueskyClient) pruneListedPosts(ctx context.Context, creds *Credentials, session *atpSessionResponse, options PruneOptions) (*PruneResult, error) {
+	result := &PruneResult{
+		PostsToDelete:  []Post{},
+		PostsToUnlike:  []Post{},
+		PostsToUnshare: []Post{},
+		PostsPreserved: []Post{},
+		Errors:         []string{},
+	}
+
+	var posts []Post
+	for _, rkey := range options.ListedPosts {
+		post, err := c.fetchListedRecord(ctx, session, rkey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", rkey, err)
+		}
+		if post == nil {
+			result.AddWarning("No post, repost or like %s found in your Bluesky repo", rkey)
+			continue
+		}
+		posts = append(posts, *post)
+	}
+	c.hydrateSubjects(ctx, posts)
+
+	if err := NewPruneEngine("bluesky", c.pruneActor(creds, session, options), options, c.clock).Run(ctx, orderRepliesBeforeParents(posts), result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// fetchListedRecord finds the post, repost or like with the record key rkey in the
+// user's repo, returning nil if there's none
+func (c *BlueskyClient) fetchListedRecord(ctx context.Context, session *atpSessionResponse, rkey string) (*Post, error) {
+	for _, collection := range blueskyListedCollections {
+		record, err := c.getRepoRecord(ctx, session, collection, rkey)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+
+		post := Post{ID: record.URI, Platform: "bluesky", CreatedAt: record.Value.CreatedAt}
+		switch collection {
+		case "app.bsky.feed.post":
+			post.Type = PostTypeOriginal
+			post.Author = session.Handle
+			post.Handle = session.Handle
+			post.Content = record.Value.Text
+			post.URL = fmt.Sprintf("https://bsky.app/profile/%s/post/%s", session.Handle, rkey)
+			post.Hashtags = record.Value.hashtags()
+			post.Languages = record.Value.Langs
+			post.Visibility = VisibilityPublic
+			post.Attachments = record.Value.Embed.attachments()
+			post.Links = record.Value.links()
+			if record.Value.Reply != nil {
+				post.Type = PostTypeReply
+				post.InReplyToID = record.Value.Reply.Parent.URI
+				post.SelfReply = atURIRepo(post.InReplyToID) == session.DID
+			} else if quoted := record.Value.Embed.quotedPost(); quoted != "" {
+				post.Type = PostTypeQuote
+				post.OriginalPost = &Post{ID: quoted, Type: PostTypeOriginal, Platform: "bluesky"}
+				post.SelfQuote = atURIRepo(quoted) == session.DID
+			}
+		case "app.bsky.feed.repost":
+			post.Type = PostTypeRepost
+			post.Content = fmt.Sprintf("Reposted: %s", record.Value.Subject.URI)
+			post.OriginalPost = &Post{ID: record.Value.Subject.URI, Type: PostTypeOriginal, Platform: "bluesky"}
+			post.SelfRepost = atURIRepo(record.Value.Subject.URI) == session.DID
+		case "app.bsky.feed.like":
+			post.Type = PostTypeLike
+			post.Content = fmt.Sprintf("Liked: %s", record.Value.Subject.URI)
+			post.OriginalPost = &Post{ID: record.Value.Subject.URI, Type: PostTypeOriginal, Platform: "bluesky"}
+		}
+		return &post, nil
+	}
+	return nil, nil
+}
+
+// getRepoRecord fetches one record from the user's repo, returning nil if it isn't there
+func (c *BlueskyClient) getRepoRecord(ctx context.Context, session *atpSessionResponse, collection, rkey string) (*blueskyListedRecord, error) {
+	params := url.Values{}
+	params.Add("repo", session.DID)
+	params.Add("collection", collection)
+	params.Add("rkey", rkey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.xrpcURL("com.atproto.repo.getRecord")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record request: %w", err)
+	}
+
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return nil, fmt.Errorf("record request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound:
+		// The PDS answers RecordNotFound with a 400, as it does a key that can't exist
+		return nil, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("bluesky", "record request", resp.StatusCode, body)
+	}
+
+	var record blueskyListedRecord
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return nil, fmt.Errorf("failed to parse record: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,549 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// blueskyOAuthScope asks for the same access an app password gives
+const blueskyOAuthScope = "atproto transition:generic"
+
+// plcDirectoryURL is the directory did:plc identities are resolved with; swapped out in tests
+var plcDirectoryURL = "https://plc.directory"
+
+// Keys in Credentials.ExtraData for Bluesky OAuth sessions
+const (
+	oauthExtraAuthMethod    = "auth_method"
+	oauthExtraDID           = "did"
+	oauthExtraPDS           = "pds"
+	oauthExtraIssuer        = "issuer"
+	oauthExtraTokenEndpoint = "token_endpoint"
+	oauthExtraClientID      = "client_id"
+	oauthExtraDPoPKey       = "dpop_key"
+	oauthExtraRefreshToken  = "refresh_token"
+	oauthExtraExpiresAt     = "expires_at"
+)
+
+// blueskyAuthServer is what discovery learns about an account's PDS and the
+// authorization server that issues tokens for it
+type blueskyAuthServer struct {
+	DID                string
+	Handle             string
+	PDS                string
+	Issuer             string `json:"issuer"`
+	AuthorizationURL   string `json:"authorization_endpoint"`
+	TokenURL           string `json:"token_endpoint"`
+	PushedAuthorizeURL string `json:"pushed_authorization_request_endpoint"`
+}
+
+// BlueskyOAuthFlow is an authorization in progress, from the pushed authorization request
+// until the code comes back
+type BlueskyOAuthFlow struct {
+	server      *blueskyAuthServer
+	dpop        *dpopSigner
+	clientID    string
+	redirectURI string
+	verifier    string
+	state       string
+	requestURI  string
+}
+
+// StartBlueskyOAuth resolves the handle to its authorization server and pushes an
+// authorization request for it. CringeSweeper is a loopback client, so redirectURI must
+// point at http://127.0.0.1 and no client metadata needs to be hosted anywhere.
+func StartBlueskyOAuth(ctx context.Context, handle, redirectURI string) (*BlueskyOAuthFlow, error) {
+	server, err := discoverBlueskyAuthServer(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DPoP key: %w", err)
+	}
+	verifier, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := NewOAuthState()
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := "http://localhost?" + url.Values{
+		"redirect_uri": {redirectURI},
+		"scope":        {blueskyOAuthScope},
+	}.Encode()
+
+	flow := &BlueskyOAuthFlow{
+		server:      server,
+		dpop:        &dpopSigner{key: key, nonces: make(map[string]string)},
+		clientID:    clientID,
+		redirectURI: redirectURI,
+		verifier:    verifier,
+		state:       state,
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("response_type", "code")
+	form.Set("redirect_uri", redirectURI)
+	form.Set("scope", blueskyOAuthScope)
+	form.Set("state", state)
+	form.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	form.Set("code_challenge_method", "S256")
+	form.Set("login_hint", server.Handle)
+
+	var par struct {
+		RequestURI string `json:"request_uri"`
+	}
+	if err := flow.dpop.postForm(ctx, server.PushedAuthorizeURL, form, "pushed authorization request", &par); err != nil {
+		return nil, err
+	}
+	if par.RequestURI == "" {
+		return nil, fmt.Errorf("pushed authorization response is missing the request URI")
+	}
+	flow.requestURI = par.RequestURI
+	return flow, nil
+}
+
+// State is the value the authorization server echoes back with the code
+func (f *BlueskyOAuthFlow) State() string {
+	return f.state
+}
+
+// AuthorizeURL returns the page where the user approves CringeSweeper
+func (f *BlueskyOAuthFlow) AuthorizeURL() string {
+	params := url.Values{}
+	params.Set("client_id", f.clientID)
+	params.Set("request_uri", f.requestURI)
+	return f.server.AuthorizationURL + "?" + params.Encode()
+}
+
+// Exchange trades the authorization code for tokens, returning credentials ready to save.
+// The issuer is the iss parameter of the redirect, if it had one.
+func (f *BlueskyOAuthFlow) Exchange(ctx context.Context, code, issuer string) (*Credentials, error) {
+	if issuer != "" && issuer != f.server.Issuer {
+		return nil, fmt.Errorf("authorization came from %s, expected %s", issuer, f.server.Issuer)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", f.redirectURI)
+	form.Set("client_id", f.clientID)
+	form.Set("code_verifier", f.verifier)
+
+	var tokens blueskyOAuthTokens
+	if err := f.dpop.postForm(ctx, f.server.TokenURL, form, "token request", &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.Subject != f.server.DID {
+		return nil, fmt.Errorf("tokens were issued for %s, expected %s", tokens.Subject, f.server.DID)
+	}
+
+	encodedKey, err := f.dpop.encodeKey()
+	if err != nil {
+		return nil, err
+	}
+	creds := &Credentials{
+		Platform: "bluesky",
+		Username: f.server.Handle,
+		ExtraData: map[string]string{
+			oauthExtraAuthMethod:    "oauth",
+			oauthExtraDID:           f.server.DID,
+			oauthExtraPDS:           f.server.PDS,
+			oauthExtraIssuer:        f.server.Issuer,
+			oauthExtraTokenEndpoint: f.server.TokenURL,
+			oauthExtraClientID:      f.clientID,
+			oauthExtraDPoPKey:       encodedKey,
+		},
+	}
+	tokens.apply(creds, SystemClock.Now())
+	return creds, nil
+}
+
+// blueskyOAuthTokens is a token endpoint response
+type blueskyOAuthTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Subject      string `json:"sub"`
+}
+
+// apply stores the tokens on the credentials
+func (t *blueskyOAuthTokens) apply(creds *Credentials, now time.Time) {
+	creds.AccessToken = t.AccessToken
+	creds.ExtraData[oauthExtraRefreshToken] = t.RefreshToken
+	expiresIn := time.Duration(t.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 15 * time.Minute
+	}
+	creds.ExtraData[oauthExtraExpiresAt] = now.Add(expiresIn).UTC().Format(time.RFC3339)
+}
+
+// blueskyOAuthSession makes requests with a saved OAuth session's DPoP-bound tokens
+type blueskyOAuthSession struct {
+	pds           string
+	tokenEndpoint string
+	clientID      string
+	dpop          *dpopSigner
+}
+
+// newBlueskyOAuthSession loads the OAuth session saved in the credentials
+func newBlueskyOAuthSession(creds *Credentials) (*blueskyOAuthSession, error) {
+	extra := creds.ExtraData
+	key, err := parseDPoPKey(extra[oauthExtraDPoPKey])
+	if err != nil {
+		return nil, err
+	}
+	if extra[oauthExtraPDS] == "" || extra[oauthExtraTokenEndpoint] == "" || extra[oauthExtraClientID] == "" {
+		return nil, fmt.Errorf("saved Bluesky OAuth session is incomplete; run 'cringesweeper auth --platforms=bluesky' again")
+	}
+	return &blueskyOAuthSession{
+		pds:           extra[oauthExtraPDS],
+		tokenEndpoint: extra[oauthExtraTokenEndpoint],
+		clientID:      extra[oauthExtraClientID],
+		dpop:          &dpopSigner{key: key, nonces: make(map[string]string)},
+	}, nil
+}
+
+// oauthExpiry returns when the saved access token expires, or the zero time if unknown
+func oauthExpiry(creds *Credentials) time.Time {
+	expiry, _ := time.Parse(time.RFC3339, creds.ExtraData[oauthExtraExpiresAt])
+	return expiry
+}
+
+// refresh swaps the refresh token for new tokens, updating the credentials in place.
+// Refresh tokens can only be used once, so the caller must save the credentials.
+func (s *blueskyOAuthSession) refresh(ctx context.Context, creds *Credentials, now time.Time) error {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", creds.ExtraData[oauthExtraRefreshToken])
+	form.Set("client_id", s.clientID)
+
+	var tokens blueskyOAuthTokens
+	if err := s.dpop.postForm(ctx, s.tokenEndpoint, form, "token refresh", &tokens); err != nil {
+		return err
+	}
+	if did := creds.ExtraData[oauthExtraDID]; tokens.Subject != "" && tokens.Subject != did {
+		return fmt.Errorf("refreshed tokens were issued for %s, expected %s", tokens.Subject, did)
+	}
+	tokens.apply(creds, now)
+	return nil
+}
+
+// do sends a request to the PDS with a DPoP-bound access token, retrying once if the
+// server asks for a fresh nonce
+func (s *blueskyOAuthSession) do(req *http.Request, accessToken string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		proof, err := s.dpop.proof(req.Method, req.URL, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "DPoP "+accessToken)
+		req.Header.Set("DPoP", proof)
+
+		resp, err := doRequest(sharedHTTPClient(), req)
+		if err != nil {
+			return nil, err
+		}
+		s.dpop.rememberNonce(req.URL, resp)
+		if attempt > 0 || resp.StatusCode != http.StatusUnauthorized ||
+			!strings.Contains(resp.Header.Get("WWW-Authenticate"), "use_dpop_nonce") {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("can't retry %s %s with a DPoP nonce", req.Method, RedactSensitiveURL(req.URL.String()))
+			}
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("failed to reset request body: %w", err)
+			}
+		}
+	}
+}
+
+// dpopSigner creates DPoP proofs (RFC 9449), which bind tokens to a key only we hold,
+// and tracks the nonces each server hands out
+type dpopSigner struct {
+	key *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	nonces map[string]string // Latest nonce by server origin
+}
+
+func parseDPoPKey(encoded string) (*ecdsa.PrivateKey, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid saved DPoP key: %w", err)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid saved DPoP key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("saved DPoP key is not an ECDSA key")
+	}
+	return key, nil
+}
+
+func (d *dpopSigner) encodeKey() (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(d.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode DPoP key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// proof returns a signed DPoP proof for a request. With an access token the proof also
+// carries the token's hash, as resource servers require.
+func (d *dpopSigner) proof(method string, target *url.URL, accessToken string) (string, error) {
+	pub, err := d.key.PublicKey.ECDH()
+	if err != nil {
+		return "", fmt.Errorf("invalid DPoP key: %w", err)
+	}
+	point := pub.Bytes() // 0x04 || X || Y
+	header := map[string]interface{}{
+		"typ": "dpop+jwt",
+		"alg": "ES256",
+		"jwk": map[string]string{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(point[1:33]),
+			"y":   base64.RawURLEncoding.EncodeToString(point[33:]),
+		},
+	}
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	htu := *target
+	htu.RawQuery, htu.Fragment = "", ""
+	claims := map[string]interface{}{
+		"jti": jti,
+		"htm": method,
+		"htu": htu.String(),
+		"iat": SystemClock.Now().Unix(),
+	}
+	if nonce := d.nonce(target); nonce != "" {
+		claims["nonce"] = nonce
+	}
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+	return signES256(d.key, header, claims)
+}
+
+// postForm sends a DPoP-signed form to an authorization server endpoint and decodes the
+// JSON response, retrying once if the server asks for a nonce
+func (d *dpopSigner) postForm(ctx context.Context, endpoint string, form url.Values, operation string, out interface{}) error {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid %s endpoint: %w", operation, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		proof, err := d.proof("POST", target, "")
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("DPoP", proof)
+
+		resp, err := doRequest(sharedHTTPClient(), req)
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", operation, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s response: %w", operation, err)
+		}
+		d.rememberNonce(target, resp)
+
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("failed to parse %s response: %w", operation, err)
+			}
+			return nil
+		}
+
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(body, &oauthErr)
+		if attempt == 0 && resp.StatusCode == http.StatusBadRequest && oauthErr.Error == "use_dpop_nonce" {
+			continue
+		}
+		return newAPIError("bluesky", operation, resp.StatusCode, body)
+	}
+}
+
+func (d *dpopSigner) nonce(target *url.URL) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.nonces[target.Scheme+"://"+target.Host]
+}
+
+func (d *dpopSigner) rememberNonce(target *url.URL, resp *http.Response) {
+	nonce := resp.Header.Get("DPoP-Nonce")
+	if nonce == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nonces[target.Scheme+"://"+target.Host] = nonce
+}
+
+// signES256 builds a compact JWT signed with ES256
+func signES256(key *ecdsa.PrivateKey, header, claims interface{}) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign DPoP proof: %w", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// discoverBlueskyAuthServer follows handle → DID → PDS → authorization server
+func discoverBlueskyAuthServer(ctx context.Context, handle string) (*blueskyAuthServer, error) {
+	handle = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+
+	var resolved struct {
+		DID string `json:"did"`
+	}
+	resolveURL := blueskyAppView() + "/xrpc/com.atproto.identity.resolveHandle?" + url.Values{"handle": {handle}}.Encode()
+	if err := getJSON(ctx, resolveURL, "handle resolution", &resolved); err != nil {
+		return nil, err
+	}
+
+	var didURL string
+	switch {
+	case strings.HasPrefix(resolved.DID, "did:plc:"):
+		didURL = plcDirectoryURL + "/" + resolved.DID
+	case strings.HasPrefix(resolved.DID, "did:web:"):
+		didURL = "https://" + strings.TrimPrefix(resolved.DID, "did:web:") + "/.well-known/did.json"
+	default:
+		return nil, fmt.Errorf("unsupported DID %q for %s", resolved.DID, handle)
+	}
+
+	var didDoc struct {
+		AlsoKnownAs []string `json:"alsoKnownAs"`
+		Service     []struct {
+			ID              string `json:"id"`
+			ServiceEndpoint string `json:"serviceEndpoint"`
+		} `json:"service"`
+	}
+	if err := getJSON(ctx, didURL, "DID document request", &didDoc); err != nil {
+		return nil, err
+	}
+
+	// The DID document must claim the handle back, or anyone could point a handle at it
+	claimed := false
+	for _, aka := range didDoc.AlsoKnownAs {
+		if strings.EqualFold(aka, "at://"+handle) {
+			claimed = true
+		}
+	}
+	if !claimed {
+		return nil, fmt.Errorf("the DID document for %s doesn't list the handle %s", resolved.DID, handle)
+	}
+
+	server := &blueskyAuthServer{DID: resolved.DID, Handle: handle}
+	for _, service := range didDoc.Service {
+		if service.ID == "#atproto_pds" || service.ID == resolved.DID+"#atproto_pds" {
+			server.PDS = strings.TrimSuffix(service.ServiceEndpoint, "/")
+		}
+	}
+	if server.PDS == "" {
+		return nil, fmt.Errorf("no PDS found for %s", handle)
+	}
+
+	var resource struct {
+		AuthorizationServers []string `json:"authorization_servers"`
+	}
+	if err := getJSON(ctx, server.PDS+"/.well-known/oauth-protected-resource", "protected resource metadata request", &resource); err != nil {
+		return nil, err
+	}
+	if len(resource.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("the PDS for %s doesn't support OAuth", handle)
+	}
+	issuer := strings.TrimSuffix(resource.AuthorizationServers[0], "/")
+
+	if err := getJSON(ctx, issuer+"/.well-known/oauth-authorization-server", "authorization server metadata request", server); err != nil {
+		return nil, err
+	}
+	if server.Issuer != issuer {
+		return nil, fmt.Errorf("authorization server metadata is for %s, expected %s", server.Issuer, issuer)
+	}
+	if server.AuthorizationURL == "" || server.TokenURL == "" || server.PushedAuthorizeURL == "" {
+		return nil, errors.New("authorization server metadata is missing required endpoints")
+	}
+	return server, nil
+}
+
+func getJSON(ctx context.Context, target, operation string, out interface{}) error {
+	resp, err := httpGetWithRetry(ctx, target)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", operation, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("bluesky", operation, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", operation, err)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBlueskyOAuthServer plays the handle resolver, PLC directory, PDS and authorization
+// server for one account, insisting on DPoP nonces the way bsky.social does
+type fakeBlueskyOAuthServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu          sync.Mutex
+	challenge   string
+	accessToken string
+	refreshes   int
+	deletes     int
+}
+
+func newFakeBlueskyOAuthServer(t *testing.T) *fakeBlueskyOAuthServer {
+	t.Helper()
+	fake := &fakeBlueskyOAuthServer{t: t, accessToken: "access1"}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.Close)
+
+	previousPLC := plcDirectoryURL
+	t.Cleanup(func() { plcDirectoryURL = previousPLC })
+	plcDirectoryURL = fake.URL
+	withBlueskyHosts(t, "", fake.URL)
+	return fake
+}
+
+func (f *fakeBlueskyOAuthServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/xrpc/com.atproto.identity.resolveHandle":
+		fmt.Fprint(w, `{"did": "did:plc:alice"}`)
+	case "/did:plc:alice":
+		fmt.Fprintf(w, `{"id": "did:plc:alice", "alsoKnownAs": ["at://alice.test"],
+			"service": [{"id": "#atproto_pds", "type": "AtprotoPersonalDataServer", "serviceEndpoint": %q}]}`, f.URL)
+	case "/.well-known/oauth-protected-resource":
+		fmt.Fprintf(w, `{"resource": %q, "authorization_servers": [%q]}`, f.URL, f.URL)
+	case "/.well-known/oauth-authorization-server":
+		fmt.Fprintf(w, `{"issuer": %q, "authorization_endpoint": "%s/oauth/authorize",
+			"token_endpoint": "%s/oauth/token", "pushed_authorization_request_endpoint": "%s/oauth/par"}`,
+			f.URL, f.URL, f.URL, f.URL)
+	case "/oauth/par":
+		if !f.checkAuthServerProof(w, r) {
+			return
+		}
+		if r.FormValue("code_challenge_method") != "S256" || r.FormValue("login_hint") != "alice.test" ||
+			r.FormValue("scope") != blueskyOAuthScope {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.challenge = r.FormValue("code_challenge")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"request_uri": "urn:ietf:params:oauth:request_uri:req1", "expires_in": 300}`)
+	case "/oauth/token":
+		if !f.checkAuthServerProof(w, r) {
+			return
+		}
+		switch r.FormValue("grant_type") {
+		case "authorization_code":
+			verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+			if r.FormValue("code") != "good-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != f.challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_grant"}`)
+				return
+			}
+		case "refresh_token":
+			if r.FormValue("refresh_token") != fmt.Sprintf("refresh%d", f.refreshes+1) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_grant"}`)
+				return
+			}
+			f.refreshes++
+			f.accessToken = fmt.Sprintf("access%d", f.refreshes+1)
+		}
+		fmt.Fprintf(w, `{"access_token": %q, "refresh_token": "refresh%d", "expires_in": 3600,
+			"token_type": "DPoP", "scope": %q, "sub": "did:plc:alice"}`, f.accessToken, f.refreshes+1, blueskyOAuthScope)
+	case "/xrpc/com.atproto.repo.deleteRecord":
+		claims := verifyDPoPProof(f.t, r.Header.Get("DPoP"))
+		if claims["nonce"] != "pds-nonce" {
+			w.Header().Set("DPoP-Nonce", "pds-nonce")
+			w.Header().Set("WWW-Authenticate", `DPoP error="use_dpop_nonce"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		ath := sha256.Sum256([]byte(f.accessToken))
+		if r.Header.Get("Authorization") != "DPoP "+f.accessToken || claims["ath"] != base64.RawURLEncoding.EncodeToString(ath[:]) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.deletes++
+		fmt.Fprint(w, `{}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// checkAuthServerProof rejects the first proof without a nonce, as authorization servers do
+func (f *fakeBlueskyOAuthServer) checkAuthServerProof(w http.ResponseWriter, r *http.Request) bool {
+	claims := verifyDPoPProof(f.t, r.Header.Get("DPoP"))
+	if claims["htm"] != "POST" || claims["htu"] != f.URL+r.URL.Path {
+		f.t.Errorf("Proof is for %v %v, not this request", claims["htm"], claims["htu"])
+	}
+	if claims["nonce"] != "as-nonce" {
+		w.Header().Set("DPoP-Nonce", "as-nonce")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": "use_dpop_nonce"}`)
+		return false
+	}
+	return true
+}
+
+// verifyDPoPProof checks a proof's signature against its embedded key and returns its claims
+func verifyDPoPProof(t *testing.T, proof string) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(proof, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Malformed DPoP proof %q", proof)
+	}
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("Malformed DPoP proof segment: %v", err)
+		}
+		return b
+	}
+
+	var header struct {
+		Typ string            `json:"typ"`
+		Alg string            `json:"alg"`
+		JWK map[string]string `json:"jwk"`
+	}
+	if err := json.Unmarshal(decode(parts[0]), &header); err != nil {
+		t.Fatalf("Malformed DPoP proof header: %v", err)
+	}
+	if header.Typ != "dpop+jwt" || header.Alg != "ES256" || header.JWK["crv"] != "P-256" {
+		t.Fatalf("Unexpected DPoP proof header %+v", header)
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(decode(header.JWK["x"])),
+		Y:     new(big.Int).SetBytes(decode(header.JWK["y"])),
+	}
+	signature := decode(parts[2])
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if len(signature) != 64 || !ecdsa.Verify(key, hash[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		t.Fatal("DPoP proof signature doesn't verify")
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(decode(parts[1]), &claims); err != nil {
+		t.Fatalf("Malformed DPoP proof claims: %v", err)
+	}
+	if claims["jti"] == "" || claims["iat"] == nil {
+		t.Errorf("DPoP proof is missing jti or iat: %v", claims)
+	}
+	return claims
+}
+
+// loginWithFakeBlueskyOAuth runs the whole authorization flow against the fake server
+func loginWithFakeBlueskyOAuth(t *testing.T, fake *fakeBlueskyOAuthServer) *Credentials {
+	t.Helper()
+	ctx := context.Background()
+
+	flow, err := StartBlueskyOAuth(ctx, "@Alice.test", "http://127.0.0.1:1234/callback")
+	if err != nil {
+		t.Fatalf("StartBlueskyOAuth failed: %v", err)
+	}
+	creds, err := flow.Exchange(ctx, "good-code", fake.URL)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	return creds
+}
+
+func TestBlueskyOAuthFlow(t *testing.T) {
+	fake := newFakeBlueskyOAuthServer(t)
+	ctx := context.Background()
+
+	flow, err := StartBlueskyOAuth(ctx, "alice.test", "http://127.0.0.1:1234/callback")
+	if err != nil {
+		t.Fatalf("StartBlueskyOAuth failed: %v", err)
+	}
+
+	authorizeURL, err := url.Parse(flow.AuthorizeURL())
+	if err != nil {
+		t.Fatalf("Invalid authorize URL: %v", err)
+	}
+	clientID, err := url.Parse(authorizeURL.Query().Get("client_id"))
+	if err != nil {
+		t.Fatalf("Invalid client ID: %v", err)
+	}
+	if authorizeURL.Path != "/oauth/authorize" || authorizeURL.Query().Get("request_uri") != "urn:ietf:params:oauth:request_uri:req1" {
+		t.Errorf("Unexpected authorize URL %s", authorizeURL)
+	}
+	if clientID.Host != "localhost" || clientID.Query().Get("redirect_uri") != "http://127.0.0.1:1234/callback" {
+		t.Errorf("Expected a loopback client ID, got %s", clientID)
+	}
+	if flow.State() == "" {
+		t.Error("Expected a state")
+	}
+
+	if _, err := flow.Exchange(ctx, "good-code", "https://evil.example"); err == nil {
+		t.Error("Expected a code from another issuer to be rejected")
+	}
+	if _, err := flow.Exchange(ctx, "bad-code", fake.URL); err == nil {
+		t.Error("Expected a bad code to be rejected")
+	}
+
+	creds, err := flow.Exchange(ctx, "good-code", fake.URL)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	if creds.Username != "alice.test" || creds.AccessToken != "access1" || creds.ExtraData[oauthExtraRefreshToken] != "refresh1" ||
+		creds.ExtraData[oauthExtraDID] != "did:plc:alice" || creds.ExtraData[oauthExtraPDS] != fake.URL {
+		t.Errorf("Unexpected credentials %+v", creds)
+	}
+	if !creds.UsesOAuth() {
+		t.Error("Expected OAuth credentials")
+	}
+	if err := ValidateCredentials(creds); err != nil {
+		t.Errorf("Expected OAuth credentials to validate, got %v", err)
+	}
+}
+
+func TestDiscoverBlueskyAuthServer_HandleNotClaimed(t *testing.T) {
+	newFakeBlueskyOAuthServer(t)
+
+	// The resolver points bob.test at alice's DID, whose document doesn't claim it
+	if _, err := discoverBlueskyAuthServer(context.Background(), "bob.test"); err == nil {
+		t.Error("Expected an unclaimed handle to be rejected")
+	}
+}
+
+func TestBlueskyClient_OAuthSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeBlueskyOAuthServer(t)
+	creds := loginWithFakeBlueskyOAuth(t, fake)
+	ctx := context.Background()
+
+	client := NewBlueskyClient()
+	clock := NewFakeClock(time.Now())
+	client.SetClock(clock)
+
+	if err := client.deletePost(ctx, creds, "at://did:plc:alice/app.bsky.feed.post/one"); err != nil {
+		t.Fatalf("deletePost failed: %v", err)
+	}
+	if fake.refreshes != 0 {
+		t.Errorf("Expected no refresh while the token is fresh, got %d", fake.refreshes)
+	}
+
+	// Once the access token expires it's refreshed, and the rotated tokens are saved
+	clock.Advance(2 * time.Hour)
+	if err := client.deletePost(ctx, creds, "at://did:plc:alice/app.bsky.feed.post/two"); err != nil {
+		t.Fatalf("deletePost after expiry failed: %v", err)
+	}
+	if fake.refreshes != 1 || fake.deletes != 2 {
+		t.Errorf("Expected 1 refresh and 2 deletes, got %d and %d", fake.refreshes, fake.deletes)
+	}
+	if creds.AccessToken != "access2" || creds.ExtraData[oauthExtraRefreshToken] != "refresh2" {
+		t.Errorf("Expected credentials to hold the refreshed tokens, got %+v", creds)
+	}
+
+	authManager, err := NewAuthManager()
+	if err != nil {
+		t.Fatalf("NewAuthManager failed: %v", err)
+	}
+	saved, err := authManager.LoadCredentials("bluesky")
+	if err != nil {
+		t.Fatalf("Expected refreshed credentials to be saved: %v", err)
+	}
+	if saved.ExtraData[oauthExtraRefreshToken] != "refresh2" {
+		t.Errorf("Expected the saved refresh token to be rotated, got %q", saved.ExtraData[oauthExtraRefreshToken])
+	}
+}
@@ -0,0 +1,313 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// blueskyRelationCollections are the repo collections holding follow and block records.
+// Mutes aren't records; they're kept privately by the server.
+var blueskyRelationCollections = map[RelationKind]string{
+	RelationFollow: "app.bsky.graph.follow",
+	RelationBlock:  "app.bsky.graph.block",
+}
+
+// blueskyProfileBatchSize is the most actors app.bsky.actor.getProfiles takes at once
+const blueskyProfileBatchSize = 25
+
+// ListRelations lists the accounts the user follows, mutes or blocks. Follows and blocks
+// are records, so they say when they were made; mutes don't. Activity takes one author
+// feed request per account.
+func (c *BlueskyClient) ListRelations(ctx context.Context, username string, kind RelationKind, activity bool) ([]Relation, error) {
+	_, session, err := c.relationSession(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []Relation
+	switch kind {
+	case RelationFollow, RelationBlock:
+		relations, err = c.listRelationRecords(ctx, session, kind)
+		if err == nil {
+			err = c.resolveRelationProfiles(ctx, relations)
+		}
+	case RelationMute:
+		relations, err = c.listMutes(ctx, session)
+	default:
+		err = fmt.Errorf("unknown relation kind %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if activity {
+		for i := range relations {
+			lastActive, err := c.lastPostTime(ctx, relations[i].AccountID)
+			if err != nil {
+				// Deleted and suspended accounts have no feed; leave them unknown rather than stale
+				WithPlatform("bluesky").Debug().Err(err).Str("account", relations[i].AccountID).Msg("Couldn't look up account activity")
+				continue
+			}
+			relations[i].LastActive = lastActive
+		}
+	}
+	return relations, nil
+}
+
+// RemoveRelation unfollows, unmutes or unblocks an account. Follows and blocks are
+// undone by deleting their record.
+func (c *BlueskyClient) RemoveRelation(ctx context.Context, username string, relation Relation) error {
+	creds, session, err := c.relationSession(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	switch relation.Kind {
+	case RelationFollow, RelationBlock:
+		return c.deletePost(ctx, creds, relation.ID)
+	case RelationMute:
+		return c.unmuteActor(ctx, session, relation.AccountID)
+	}
+	return fmt.Errorf("unknown relation kind %q", relation.Kind)
+}
+
+// relationSession authenticates and checks username is the logged-in account, as only
+// its own relations can be managed
+func (c *BlueskyClient) relationSession(ctx context.Context, username string) (*Credentials, *atpSessionResponse, error) {
+	creds, err := GetCredentialsForPlatform("bluesky")
+	if err != nil {
+		return nil, nil, fmt.Errorf("authentication required: %w", err)
+	}
+	if err := ValidateCredentials(creds); err != nil {
+		return nil, nil, fmt.Errorf("invalid credentials: %w", err)
+	}
+	session, err := c.ensureValidSession(ctx, creds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to authenticate with Bluesky: %w", err)
+	}
+	if !isOwnBlueskyAccount(username, session) {
+		return nil, nil, fmt.Errorf("%w: %s is not the authenticated account %s", ErrNotOwnAccount, username, session.Handle)
+	}
+	return creds, session, nil
+}
+
+// listRelationRecords walks every page of the follow or block collection
+func (c *BlueskyClient) listRelationRecords(ctx context.Context, session *atpSessionResponse, kind RelationKind) ([]Relation, error) {
+	var relations []Relation
+	cursor := ""
+
+	for {
+		params := url.Values{}
+		params.Add("repo", session.DID)
+		params.Add("collection", blueskyRelationCollections[kind])
+		params.Add("limit", "100")
+		if cursor != "" {
+			params.Add("cursor", cursor)
+		}
+
+		var listResponse struct {
+			Records []struct {
+				URI   string `json:"uri"`
+				Value struct {
+					Subject   string    `json:"subject"`
+					CreatedAt time.Time `json:"createdAt"`
+				} `json:"value"`
+			} `json:"records"`
+			Cursor string `json:"cursor,omitempty"`
+		}
+		if err := c.getRelationPage(ctx, session, c.xrpcURL("com.atproto.repo.listRecords")+"?"+params.Encode(), &listResponse); err != nil {
+			return nil, err
+		}
+
+		for _, record := range listResponse.Records {
+			createdAt := record.Value.CreatedAt
+			relations = append(relations, Relation{
+				Kind:      kind,
+				ID:        record.URI,
+				AccountID: record.Value.Subject,
+				Handle:    record.Value.Subject, // Replaced with the handle once profiles are resolved
+				CreatedAt: &createdAt,
+			})
+		}
+
+		// A repeated cursor would loop forever
+		if len(listResponse.Records) == 0 || listResponse.Cursor == "" || listResponse.Cursor == cursor {
+			break
+		}
+		cursor = listResponse.Cursor
+	}
+	return relations, nil
+}
+
+// listMutes walks every page of the user's muted accounts
+func (c *BlueskyClient) listMutes(ctx context.Context, session *atpSessionResponse) ([]Relation, error) {
+	var relations []Relation
+	cursor := ""
+
+	for {
+		params := url.Values{}
+		params.Add("limit", "100")
+		if cursor != "" {
+			params.Add("cursor", cursor)
+		}
+
+		var mutesResponse struct {
+			Mutes  []blueskyAuthor `json:"mutes"`
+			Cursor string          `json:"cursor,omitempty"`
+		}
+		if err := c.getRelationPage(ctx, session, c.xrpcURL("app.bsky.graph.getMutes")+"?"+params.Encode(), &mutesResponse); err != nil {
+			return nil, err
+		}
+
+		for _, muted := range mutesResponse.Mutes {
+			relations = append(relations, Relation{
+				Kind:        RelationMute,
+				ID:          muted.DID,
+				AccountID:   muted.DID,
+				Handle:      muted.Handle,
+				DisplayName: muted.DisplayName,
+			})
+		}
+
+		if len(mutesResponse.Mutes) == 0 || mutesResponse.Cursor == "" || mutesResponse.Cursor == cursor {
+			break
+		}
+		cursor = mutesResponse.Cursor
+	}
+	return relations, nil
+}
+
+// getRelationPage fetches one authenticated listing page and decodes it into v
+func (c *BlueskyClient) getRelationPage(ctx context.Context, session *atpSessionResponse, pageURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create list request: %w", err)
+	}
+
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return fmt.Errorf("list request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("bluesky", "list request", resp.StatusCode, body)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read list response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse list response: %w", err)
+	}
+	return nil
+}
+
+// resolveRelationProfiles fills in the handles and display names of the accounts in
+// follow and block records, which only name a DID. Accounts the AppView no longer knows
+// keep their DID as the handle.
+func (c *BlueskyClient) resolveRelationProfiles(ctx context.Context, relations []Relation) error {
+	for start := 0; start < len(relations); start += blueskyProfileBatchSize {
+		end := min(start+blueskyProfileBatchSize, len(relations))
+		params := url.Values{}
+		for _, relation := range relations[start:end] {
+			params.Add("actors", relation.AccountID)
+		}
+
+		resp, err := httpGetWithRetry(ctx, blueskyAppView()+"/xrpc/app.bsky.actor.getProfiles?"+params.Encode())
+		if err != nil {
+			return fmt.Errorf("failed to fetch profiles: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return newAPIError("bluesky", "profiles request", resp.StatusCode, body)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read profiles response: %w", err)
+		}
+
+		var profilesResponse struct {
+			Profiles []blueskyAuthor `json:"profiles"`
+		}
+		if err := json.Unmarshal(body, &profilesResponse); err != nil {
+			return fmt.Errorf("failed to parse profiles response: %w", err)
+		}
+
+		profiles := make(map[string]blueskyAuthor, len(profilesResponse.Profiles))
+		for _, profile := range profilesResponse.Profiles {
+			profiles[profile.DID] = profile
+		}
+		for i := start; i < end; i++ {
+			if profile, ok := profiles[relations[i].AccountID]; ok {
+				relations[i].Handle = profile.Handle
+				relations[i].DisplayName = profile.DisplayName
+			}
+		}
+	}
+	return nil
+}
+
+// lastPostTime returns when an account last posted, or nil if its feed is empty
+func (c *BlueskyClient) lastPostTime(ctx context.Context, did string) (*time.Time, error) {
+	params := url.Values{}
+	params.Add("actor", did)
+	params.Add("limit", "1")
+	params.Add("filter", "posts_with_replies")
+
+	resp, err := httpGetWithRetry(ctx, blueskyAppView()+"/xrpc/app.bsky.feed.getAuthorFeed?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch author feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("bluesky", "author feed request", resp.StatusCode, body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read author feed: %w", err)
+	}
+
+	var feedResponse blueskyEnhancedFeedResponse
+	if err := json.Unmarshal(body, &feedResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse author feed: %w", err)
+	}
+	if len(feedResponse.Feed) == 0 {
+		return nil, nil
+	}
+	lastPost := feedResponse.Feed[0].Post.Record.CreatedAt
+	return &lastPost, nil
+}
+
+// unmuteActor unmutes an account
+func (c *BlueskyClient) unmuteActor(ctx context.Context, session *atpSessionResponse, did string) error {
+	jsonData, err := json.Marshal(map[string]string{"actor": did})
+	if err != nil {
+		return fmt.Errorf("failed to marshal unmute data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.xrpcURL("app.bsky.graph.unmuteActor"), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create unmute request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return fmt.Errorf("unmute request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError("bluesky", "unmute request", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// blueskyPostRkey returns the record key of a post's AT URI, checking it's a post in repo
+func blueskyPostRkey(uri, repo string) (string, error) {
+	prefix := "at://" + repo + "/app.bsky.feed.post/"
+	rkey, ok := strings.CutPrefix(uri, prefix)
+	if !ok || rkey == "" || strings.Contains(rkey, "/") {
+		return "", fmt.Errorf("%s is not a post in the authenticated account's repo %s", uri, repo)
+	}
+	return rkey, nil
+}
+
+// PostExists reports whether the post record is still in the user's repo
+func (c *BlueskyClient) PostExists(ctx context.Context, username string, post Post) (bool, error) {
+	_, session, err := c.relationSession(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	rkey, err := blueskyPostRkey(post.ID, session.DID)
+	if err != nil {
+		return false, err
+	}
+	record, err := c.getRepoRecord(ctx, session, "app.bsky.feed.post", rkey)
+	if err != nil {
+		return false, err
+	}
+	return record != nil, nil
+}
+
+// RestorePost creates the post record again under its original record key, so it gets
+// back its AT URI and a retried create can't post it twice. Its text, languages and
+// createdAt are the original's, so it's shown with the date it was first posted. Link and
+// mention facets and embeds aren't archived, so links and mentions come back as plain text.
+func (c *BlueskyClient) RestorePost(ctx context.Context, username string, post Post, inReplyTo string) (string, error) {
+	_, session, err := c.relationSession(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	rkey, err := blueskyPostRkey(post.ID, session.DID)
+	if err != nil {
+		return "", err
+	}
+
+	record := blueskyRecord{
+		Type:      "app.bsky.feed.post",
+		Text:      post.Content,
+		CreatedAt: post.CreatedAt.UTC(),
+		Langs:     post.Languages,
+	}
+	if inReplyTo != "" {
+		record.Reply, err = c.replyRefTo(ctx, session, inReplyTo)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"repo":       session.DID,
+		"collection": "app.bsky.feed.post",
+		"rkey":       rkey,
+		"record":     record,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.xrpcURL("com.atproto.repo.createRecord"), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create post request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doAuthenticated(req, session)
+	if err != nil {
+		return "", fmt.Errorf("post request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newAPIError("bluesky", "post request", resp.StatusCode, body)
+	}
+
+	var created blueskyPostRef
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to parse created post: %w", err)
+	}
+	return created.URI, nil
+}
+
+// replyRefTo returns the reply reference for answering parentURI: the parent itself, and
+// the root of its thread. Parents in the user's own repo are read from the PDS, so a post
+// restored a moment ago is found before the AppView has indexed it.
+func (c *BlueskyClient) replyRefTo(ctx context.Context, session *atpSessionResponse, parentURI string) (*blueskyReply, error) {
+	var parent blueskyPostRef
+	var parentRecord blueskyRecord
+
+	if atURIRepo(parentURI) == session.DID {
+		rkey, err := blueskyPostRkey(parentURI, session.DID)
+		if err != nil {
+			return nil, err
+		}
+		record, err := c.getRepoRecord(ctx, session, "app.bsky.feed.post", rkey)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, fmt.Errorf("the post it replied to, %s, is gone", parentURI)
+		}
+		parent = blueskyPostRef{URI: record.URI, CID: record.CID}
+		parentRecord = record.Value.blueskyRecord
+	} else {
+		resp, err := httpGetWithRetry(ctx, blueskyAppView()+"/xrpc/app.bsky.feed.getPosts?"+url.Values{"uris": {parentURI}}.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the post it replied to: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, newAPIError("bluesky", "post lookup", resp.StatusCode, body)
+		}
+
+		var response struct {
+			Posts []blueskyPost `json:"posts"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if len(response.Posts) == 0 {
+			return nil, fmt.Errorf("the post it replied to, %s, is gone", parentURI)
+		}
+		parent = blueskyPostRef{URI: response.Posts[0].URI, CID: response.Posts[0].CID}
+		parentRecord = response.Posts[0].Record
+	}
+
+	root := parent
+	if parentRecord.Reply != nil {
+		root = parentRecord.Reply.Root
+	}
+	return &blueskyReply{Parent: parent, Root: root}, nil
+}
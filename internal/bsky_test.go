@@ -1,9 +1,12 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -74,6 +77,137 @@ func TestExtractPostID(t *testing.T) {
 	}
 }
 
+func TestATURIRepo(t *testing.T) {
+	tests := map[string]string{
+		"at://did:plc:abc123/app.bsky.feed.post/xyz789":   "did:plc:abc123",
+		"at://did:plc:abc123/app.bsky.feed.repost/rst456": "did:plc:abc123",
+		"at://did:plc:abc123":                             "did:plc:abc123",
+		"https://bsky.app/profile/someone":                "",
+		"":                                                "",
+	}
+	for uri, want := range tests {
+		if got := atURIRepo(uri); got != want {
+			t.Errorf("atURIRepo(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
+
+func TestBlueskyPostRkey(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"own post", "at://did:plc:me/app.bsky.feed.post/3k2abc", "3k2abc", false},
+		{"someone else's post", "at://did:plc:other/app.bsky.feed.post/3k2abc", "", true},
+		{"a like", "at://did:plc:me/app.bsky.feed.like/3k2abc", "", true},
+		{"no key", "at://did:plc:me/app.bsky.feed.post/", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := blueskyPostRkey(tt.uri, "did:plc:me")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("blueskyPostRkey(%q) = %q, %v, want %q", tt.uri, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlueskyClient_RestorePost(t *testing.T) {
+	var created []blueskyRecord
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewEncoder(w).Encode(atpSessionResponse{AccessJwt: "access", RefreshJwt: "refresh", Handle: "me.example.com", DID: "did:plc:me"})
+		case "/xrpc/com.atproto.repo.getRecord":
+			if r.URL.Query().Get("rkey") != "thread2" {
+				http.Error(w, `{"error": "RecordNotFound"}`, http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"uri": "at://did:plc:me/app.bsky.feed.post/thread2", "cid": "cid2", "value": {"text": "two", "reply": {"parent": {"uri": "at://did:plc:me/app.bsky.feed.post/thread1", "cid": "cid1"}, "root": {"uri": "at://did:plc:me/app.bsky.feed.post/thread1", "cid": "cid1"}}}}`))
+		case "/xrpc/app.bsky.feed.getPosts":
+			w.Write([]byte(`{"posts": [{"uri": "at://did:plc:other/app.bsky.feed.post/theirs", "cid": "cidtheirs", "record": {"text": "theirs"}}]}`))
+		case "/xrpc/com.atproto.repo.createRecord":
+			var request struct {
+				Repo   string        `json:"repo"`
+				Rkey   string        `json:"rkey"`
+				Record blueskyRecord `json:"record"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Repo != "did:plc:me" || request.Rkey != "gone" {
+				t.Errorf("Expected the post created under its original key in the session's repo, got %s/%s", request.Repo, request.Rkey)
+			}
+			created = append(created, request.Record)
+			fmt.Fprintf(w, `{"uri": "at://did:plc:me/app.bsky.feed.post/%s", "cid": "cidnew"}`, request.Rkey)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(pds.Close)
+	withBlueskyHosts(t, pds.URL, pds.URL)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLUESKY_USER", "me.example.com")
+	t.Setenv("BLUESKY_PASSWORD", "secret")
+	client := NewBlueskyClient()
+	ctx := context.Background()
+
+	if exists, err := client.PostExists(ctx, "me.example.com", Post{ID: "at://did:plc:me/app.bsky.feed.post/thread2"}); err != nil || !exists {
+		t.Errorf("Expected thread2 to exist, got %v, %v", exists, err)
+	}
+	if exists, err := client.PostExists(ctx, "me.example.com", Post{ID: "at://did:plc:me/app.bsky.feed.post/gone"}); err != nil || exists {
+		t.Errorf("Expected gone to be gone, got %v, %v", exists, err)
+	}
+	if _, err := client.PostExists(ctx, "me.example.com", Post{ID: "at://did:plc:other/app.bsky.feed.post/theirs"}); err == nil {
+		t.Error("Expected an error checking a post in someone else's repo")
+	}
+
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	post := Post{ID: "at://did:plc:me/app.bsky.feed.post/gone", Content: "back again", CreatedAt: createdAt, Languages: []string{"en"}}
+	tests := []struct {
+		name      string
+		inReplyTo string
+		wantReply *blueskyReply
+	}{
+		{"original", "", nil},
+		{"reply in own thread", "at://did:plc:me/app.bsky.feed.post/thread2", &blueskyReply{
+			Parent: blueskyPostRef{URI: "at://did:plc:me/app.bsky.feed.post/thread2", CID: "cid2"},
+			Root:   blueskyPostRef{URI: "at://did:plc:me/app.bsky.feed.post/thread1", CID: "cid1"},
+		}},
+		{"reply to someone else", "at://did:plc:other/app.bsky.feed.post/theirs", &blueskyReply{
+			Parent: blueskyPostRef{URI: "at://did:plc:other/app.bsky.feed.post/theirs", CID: "cidtheirs"},
+			Root:   blueskyPostRef{URI: "at://did:plc:other/app.bsky.feed.post/theirs", CID: "cidtheirs"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newID, err := client.RestorePost(ctx, "me.example.com", post, tt.inReplyTo)
+			if err != nil {
+				t.Fatalf("RestorePost() error = %v", err)
+			}
+			if newID != post.ID {
+				t.Errorf("Expected the post back under its original URI, got %s", newID)
+			}
+			record := created[len(created)-1]
+			if record.Text != "back again" || !record.CreatedAt.Equal(createdAt) || strings.Join(record.Langs, ",") != "en" {
+				t.Errorf("Expected the archived text, date and language, got %+v", record)
+			}
+			if (record.Reply == nil) != (tt.wantReply == nil) || (record.Reply != nil && *record.Reply != *tt.wantReply) {
+				t.Errorf("Expected reply %+v, got %+v", tt.wantReply, record.Reply)
+			}
+		})
+	}
+
+	if _, err := client.RestorePost(ctx, "me.example.com", post, "at://did:plc:me/app.bsky.feed.post/gone"); err == nil {
+		t.Error("Expected an error replying to a post that's gone")
+	}
+	if _, err := client.RestorePost(ctx, "me.example.com", Post{ID: "at://did:plc:other/app.bsky.feed.post/theirs"}, ""); err == nil {
+		t.Error("Expected an error restoring a post from someone else's repo")
+	}
+}
+
 func TestBlueskyClient_DeterminePostType(t *testing.T) {
 	client := NewBlueskyClient()
 
@@ -273,7 +407,7 @@ func TestBlueskyClient_PrunePosts(t *testing.T) {
 		}
 
 		// This should fail due to missing credentials
-		result, err := client.PrunePosts("test.bsky.social", options)
+		result, err := client.PrunePosts(context.Background(), "test.bsky.social", options)
 
 		if err == nil {
 			t.Error("Expected error when no credentials are available")
@@ -428,6 +562,94 @@ func TestBlueskyReplyHandling(t *testing.T) {
 			t.Errorf("Expected parent URI %q, got %q", expectedParentURI, inReplyToID)
 		}
 	})
+
+	t.Run("self-reply detection", func(t *testing.T) {
+		client := NewBlueskyClient()
+		toOthers := replyPost
+		toOthers.URI = "at://did:plc:me/app.bsky.feed.post/reply1"
+		if post := client.viewPost(toOthers); post.SelfReply {
+			t.Error("Expected a reply to someone else's post not to be a self-reply")
+		}
+
+		inThread := toOthers
+		inThread.Record.Reply = &blueskyReply{Parent: blueskyPostRef{URI: "at://did:plc:me/app.bsky.feed.post/first"}}
+		if post := client.viewPost(inThread); !post.SelfReply {
+			t.Error("Expected a reply to your own post to be a self-reply")
+		}
+	})
+}
+
+func TestBlueskyRecordLinks(t *testing.T) {
+	recordJSON := `{
+		"$type": "app.bsky.feed.post",
+		"text": "Read this #blog https://example.com/post",
+		"facets": [
+			{"features": [{"$type": "app.bsky.richtext.facet#tag", "tag": "blog"}]},
+			{"features": [{"$type": "app.bsky.richtext.facet#link", "uri": "https://example.com/post"}]}
+		],
+		"embed": {
+			"$type": "app.bsky.embed.recordWithMedia",
+			"record": {"$type": "app.bsky.embed.record", "record": {"uri": "at://did:plc:them/app.bsky.feed.post/abc", "cid": "x"}},
+			"media": {"$type": "app.bsky.embed.external", "external": {"uri": "https://news.example.org/story", "title": "Story"}}
+		}
+	}`
+	var record blueskyRecord
+	if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+		t.Fatalf("Failed to unmarshal record: %v", err)
+	}
+
+	want := []string{"https://example.com/post", "https://news.example.org/story"}
+	if links := record.links(); !slices.Equal(links, want) {
+		t.Errorf("Expected links %v, got %v", want, links)
+	}
+}
+
+func TestBlueskyQuoteDetection(t *testing.T) {
+	client := NewBlueskyClient()
+	records := `{
+		"others": {"$type": "app.bsky.embed.record", "record": {"uri": "at://did:plc:them/app.bsky.feed.post/abc", "cid": "x"}},
+		"self": {"$type": "app.bsky.embed.recordWithMedia", "record": {"$type": "app.bsky.embed.record", "record": {"uri": "at://did:plc:me/app.bsky.feed.post/def", "cid": "y"}}, "media": {"$type": "app.bsky.embed.images", "images": [{"alt": ""}]}},
+		"list": {"$type": "app.bsky.embed.record", "record": {"uri": "at://did:plc:them/app.bsky.graph.list/ghi", "cid": "z"}}
+	}`
+	var embeds map[string]*blueskyEmbed
+	if err := json.Unmarshal([]byte(records), &embeds); err != nil {
+		t.Fatalf("Failed to unmarshal embeds: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		embed     *blueskyEmbed
+		reply     bool
+		wantType  PostType
+		wantQuote string
+		wantSelf  bool
+	}{
+		{"quote of someone else", embeds["others"], false, PostTypeQuote, "at://did:plc:them/app.bsky.feed.post/abc", false},
+		{"quote of your own post with media", embeds["self"], false, PostTypeQuote, "at://did:plc:me/app.bsky.feed.post/def", true},
+		{"embedded list isn't a quote", embeds["list"], false, PostTypeOriginal, "", false},
+		{"quoting reply stays a reply", embeds["others"], true, PostTypeReply, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := blueskyPost{
+				URI:    "at://did:plc:me/app.bsky.feed.post/post1",
+				Author: blueskyAuthor{DID: "did:plc:me", Handle: "me.bsky.social"},
+				Record: blueskyRecord{Type: "app.bsky.feed.post", Embed: tt.embed},
+			}
+			if tt.reply {
+				view.Record.Reply = &blueskyReply{Parent: blueskyPostRef{URI: "at://did:plc:them/app.bsky.feed.post/parent"}}
+			}
+
+			post := client.viewPost(view)
+			if post.Type != tt.wantType || post.SelfQuote != tt.wantSelf {
+				t.Errorf("Got type %s, self-quote %v; expected %s, %v", post.Type, post.SelfQuote, tt.wantType, tt.wantSelf)
+			}
+			if tt.wantQuote != "" && (post.OriginalPost == nil || post.OriginalPost.ID != tt.wantQuote) {
+				t.Errorf("Expected the quoted post %s, got %+v", tt.wantQuote, post.OriginalPost)
+			}
+		})
+	}
 }
 
 func TestBlueskyRepostHandling(t *testing.T) {
@@ -622,3 +844,296 @@ func TestBlueskyAPIParameterValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncatedListingMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		examined   int
+		continued  bool
+		contains   []string
+	}{
+		{
+			name:       "single page of reposts",
+			recordType: "repost",
+			examined:   100,
+			continued:  false,
+			contains:   []string{"Repost listing truncated", "100 repost records fetched, more available", "--continue"},
+		},
+		{
+			name:       "repeated cursor while continuing",
+			recordType: "like",
+			examined:   350,
+			continued:  true,
+			contains:   []string{"Like listing truncated", "350 like records fetched, more available", "repeated cursor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := truncatedListingMessage(tt.recordType, tt.examined, tt.continued)
+			for _, want := range tt.contains {
+				if !strings.Contains(message, want) {
+					t.Errorf("Expected message to contain %q, got %q", want, message)
+				}
+			}
+			if tt.continued && strings.Contains(message, "--continue") {
+				t.Errorf("Message should not suggest --continue when already continuing: %q", message)
+			}
+		})
+	}
+}
+
+func TestBlueskyRecord_Hashtags(t *testing.T) {
+	recordJSON := `{
+		"$type": "app.bsky.feed.post",
+		"text": "Shipping it #Portfolio #keep https://example.com",
+		"createdAt": "2024-01-01T00:00:00Z",
+		"facets": [
+			{"features": [{"$type": "app.bsky.richtext.facet#tag", "tag": "Portfolio"}]},
+			{"features": [{"$type": "app.bsky.richtext.facet#link", "uri": "https://example.com"}]},
+			{"features": [{"$type": "app.bsky.richtext.facet#tag", "tag": "keep"}]}
+		]
+	}`
+
+	var record blueskyRecord
+	if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+		t.Fatalf("Failed to unmarshal record: %v", err)
+	}
+
+	tags := record.hashtags()
+	if len(tags) != 2 || tags[0] != "Portfolio" || tags[1] != "keep" {
+		t.Errorf("Expected [Portfolio keep], got %v", tags)
+	}
+
+	if tags := (blueskyRecord{Text: "no tags here"}).hashtags(); len(tags) != 0 {
+		t.Errorf("Expected no hashtags, got %v", tags)
+	}
+}
+
+func TestBlueskyEmbed_Attachments(t *testing.T) {
+	tests := []struct {
+		name     string
+		embed    string
+		expected []Attachment
+	}{
+		{"no embed", `null`, nil},
+		{"external link", `{"$type": "app.bsky.embed.external", "external": {"uri": "https://example.com"}}`, nil},
+		{"images", `{"$type": "app.bsky.embed.images", "images": [{"alt": "a cat"}, {"alt": ""}]}`,
+			[]Attachment{{Type: "image", AltText: "a cat"}, {Type: "image"}}},
+		{"video", `{"$type": "app.bsky.embed.video", "alt": "a dog"}`, []Attachment{{Type: "video", AltText: "a dog"}}},
+		{"quote with media", `{"$type": "app.bsky.embed.recordWithMedia", "record": {}, "media": {"$type": "app.bsky.embed.images", "images": [{"alt": "chart"}]}}`,
+			[]Attachment{{Type: "image", AltText: "chart"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var embed *blueskyEmbed
+			if err := json.Unmarshal([]byte(tt.embed), &embed); err != nil {
+				t.Fatalf("Failed to unmarshal embed: %v", err)
+			}
+
+			got := embed.attachments()
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d attachments, got %v", len(tt.expected), got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Attachment %d = %+v, expected %+v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsOwnBlueskyAccount(t *testing.T) {
+	session := &atpSessionResponse{Handle: "me.bsky.social", DID: "did:plc:me123"}
+
+	tests := []struct {
+		username string
+		expected bool
+	}{
+		{"me.bsky.social", true},
+		{"@Me.Bsky.Social", true},
+		{"did:plc:me123", true},
+		{"someone.bsky.social", false},
+		{"did:plc:other", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isOwnBlueskyAccount(tt.username, session); got != tt.expected {
+			t.Errorf("isOwnBlueskyAccount(%q) = %v, expected %v", tt.username, got, tt.expected)
+		}
+	}
+}
+
+func TestOrderRepliesBeforeParents(t *testing.T) {
+	ids := func(posts []Post) string {
+		var out []string
+		for _, post := range posts {
+			out = append(out, post.ID)
+		}
+		return strings.Join(out, " ")
+	}
+
+	tests := []struct {
+		name  string
+		posts []Post
+		want  string
+	}{
+		{
+			name:  "no threads",
+			posts: []Post{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			want:  "a b c",
+		},
+		{
+			name: "newest first is already child first",
+			posts: []Post{
+				{ID: "reply2", InReplyToID: "reply1"},
+				{ID: "reply1", InReplyToID: "root"},
+				{ID: "root"},
+			},
+			want: "reply2 reply1 root",
+		},
+		{
+			name: "pinned root listed first",
+			posts: []Post{
+				{ID: "root", IsPinned: true},
+				{ID: "other"},
+				{ID: "reply1", InReplyToID: "root"},
+				{ID: "reply2", InReplyToID: "reply1"},
+				{ID: "sibling", InReplyToID: "root"},
+			},
+			want: "reply2 reply1 sibling root other",
+		},
+		{
+			name: "replies to posts not being pruned keep their place",
+			posts: []Post{
+				{ID: "a"},
+				{ID: "reply", InReplyToID: "someone-else"},
+				{ID: "b"},
+			},
+			want: "a reply b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(orderRepliesBeforeParents(tt.posts)); got != tt.want {
+				t.Errorf("Got order %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlueskyClient_DeleteWrites(t *testing.T) {
+	client := NewBlueskyClient()
+	posts := []Post{
+		{ID: "at://did:plc:abc123/app.bsky.feed.post/post1"},
+		{ID: "at://did:plc:abc123/app.bsky.feed.like/like1"},
+		{ID: "at://did:plc:abc123/app.bsky.feed.repost/repost1"},
+	}
+
+	writes, err := client.deleteWrites(posts, "did:plc:abc123")
+	if err != nil {
+		t.Fatalf("deleteWrites() error = %v", err)
+	}
+	want := []blueskyDeleteWrite{
+		{Type: "com.atproto.repo.applyWrites#delete", Collection: "app.bsky.feed.post", RKey: "post1"},
+		{Type: "com.atproto.repo.applyWrites#delete", Collection: "app.bsky.feed.like", RKey: "like1"},
+		{Type: "com.atproto.repo.applyWrites#delete", Collection: "app.bsky.feed.repost", RKey: "repost1"},
+	}
+	if len(writes) != len(want) {
+		t.Fatalf("Got %d writes, want %d", len(writes), len(want))
+	}
+	for i := range want {
+		if writes[i] != want[i] {
+			t.Errorf("Write %d = %+v, want %+v", i, writes[i], want[i])
+		}
+	}
+
+	// A record in someone else's repo fails the whole batch before anything is sent
+	posts = append(posts, Post{ID: "at://did:plc:other/app.bsky.feed.post/post2"})
+	if _, err := client.deleteWrites(posts, "did:plc:abc123"); err == nil {
+		t.Error("Expected an error for a record in another repo")
+	}
+}
+
+func TestBlueskyClient_FetchListedRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xrpc/com.atproto.repo.getRecord" || r.URL.Query().Get("repo") != "did:plc:me" {
+			t.Errorf("Unexpected request to %s", r.URL)
+		}
+		switch r.URL.Query().Get("collection") + "/" + r.URL.Query().Get("rkey") {
+		case "app.bsky.feed.post/3kpost":
+			fmt.Fprint(w, `{"uri": "at://did:plc:me/app.bsky.feed.post/3kpost", "value": {"text": "hello", "createdAt": "2024-01-02T03:04:05Z", "reply": {"parent": {"uri": "at://did:plc:me/app.bsky.feed.post/3kparent"}}}}`)
+		case "app.bsky.feed.like/3klike":
+			fmt.Fprint(w, `{"uri": "at://did:plc:me/app.bsky.feed.like/3klike", "value": {"subject": {"uri": "at://did:plc:them/app.bsky.feed.post/3kx"}, "createdAt": "2024-01-02T03:04:05Z"}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "RecordNotFound"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBlueskyClient()
+	client.pds = server.URL
+	session := &atpSessionResponse{AccessJwt: "token", Handle: "me.bsky.social", DID: "did:plc:me"}
+
+	post, err := client.fetchListedRecord(context.Background(), session, "3kpost")
+	if err != nil {
+		t.Fatalf("fetchListedRecord() error = %v", err)
+	}
+	if post == nil || post.Type != PostTypeReply || post.Content != "hello" || post.URL != "https://bsky.app/profile/me.bsky.social/post/3kpost" {
+		t.Errorf("Expected the reply record, got %+v", post)
+	}
+
+	post, err = client.fetchListedRecord(context.Background(), session, "3klike")
+	if err != nil {
+		t.Fatalf("fetchListedRecord() error = %v", err)
+	}
+	if post == nil || post.Type != PostTypeLike || post.OriginalPost.ID != "at://did:plc:them/app.bsky.feed.post/3kx" {
+		t.Errorf("Expected the like record, got %+v", post)
+	}
+
+	if post, err := client.fetchListedRecord(context.Background(), session, "3kmissing"); err != nil || post != nil {
+		t.Errorf("Expected nothing for a missing record, got %+v, %v", post, err)
+	}
+}
+
+func TestBlueskyClient_HydrateSubjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xrpc/app.bsky.feed.getPosts" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if uris := r.URL.Query()["uris"]; len(uris) != 3 {
+			t.Errorf("Expected every subject in one lookup, got %v", uris)
+		}
+		fmt.Fprint(w, `{"posts": [{"uri": "at://did:plc:them/app.bsky.feed.post/3kliked", "author": {"did": "did:plc:them", "handle": "them.bsky.social", "displayName": "Them"}, "record": {"text": "worth a like", "createdAt": "2021-05-01T12:00:00Z"}, "likeCount": 3}]}`)
+	}))
+	defer server.Close()
+	withBlueskyHosts(t, "", server.URL)
+
+	records := []Post{
+		{ID: "at://did:plc:me/app.bsky.feed.like/1", Type: PostTypeLike, Content: "Liked: at://did:plc:them/app.bsky.feed.post/3kliked", OriginalPost: &Post{ID: "at://did:plc:them/app.bsky.feed.post/3kliked"}},
+		{ID: "at://did:plc:me/app.bsky.feed.like/2", Type: PostTypeLike, Content: "Liked: at://did:plc:them/app.bsky.feed.post/3kgone", OriginalPost: &Post{ID: "at://did:plc:them/app.bsky.feed.post/3kgone"}},
+		{ID: "at://did:plc:me/app.bsky.feed.repost/4", Type: PostTypeRepost, Content: "Reposted: at://did:plc:them/app.bsky.feed.post/3kliked", OriginalPost: &Post{ID: "at://did:plc:them/app.bsky.feed.post/3kliked"}},
+		{ID: "at://did:plc:me/app.bsky.feed.post/3", Type: PostTypeOriginal},
+	}
+	NewBlueskyClient().hydrateSubjects(context.Background(), records)
+
+	liked := records[0]
+	if liked.Handle != "them.bsky.social" || liked.OriginalAuthor != "Them" || liked.Content != "Liked post from 2021-05-01: worth a like" {
+		t.Errorf("Expected the like to show the liked post, got %+v", liked)
+	}
+	if liked.OriginalPost.CreatedAt.Year() != 2021 || liked.OriginalPost.LikeCount != 3 {
+		t.Errorf("Expected the liked post's details, got %+v", liked.OriginalPost)
+	}
+	if records[1].Content != "Liked: at://did:plc:them/app.bsky.feed.post/3kgone" {
+		t.Errorf("Expected a like of a deleted post to be left alone, got %q", records[1].Content)
+	}
+	if reposted := records[2]; reposted.Content != "Reposted post from 2021-05-01: worth a like" || reposted.OriginalHandle != "them.bsky.social" {
+		t.Errorf("Expected the repost to show the reposted post, got %+v", reposted)
+	}
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrRequestBudgetExhausted is returned for API requests made after a run's
+// RequestBudget has been used up
+var ErrRequestBudgetExhausted = errors.New("API request budget for this run is used up")
+
+// RequestBudget counts the API requests made during a run and, with a limit, refuses
+// any beyond it. Every attempt counts, including retries. A nil budget counts nothing
+// and allows everything, so callers never need to check for one.
+type RequestBudget struct {
+	limit int64 // Zero for no limit
+	used  atomic.Int64
+}
+
+// NewRequestBudget creates a budget allowing limit requests, or any number if limit is zero
+func NewRequestBudget(limit int) *RequestBudget {
+	return &RequestBudget{limit: int64(limit)}
+}
+
+type requestBudgetKey struct{}
+
+// WithRequestBudget returns a context whose API requests are counted against budget
+func WithRequestBudget(ctx context.Context, budget *RequestBudget) context.Context {
+	return context.WithValue(ctx, requestBudgetKey{}, budget)
+}
+
+// RequestBudgetFromContext returns the budget attached to ctx, or nil if there isn't one
+func RequestBudgetFromContext(ctx context.Context) *RequestBudget {
+	budget, _ := ctx.Value(requestBudgetKey{}).(*RequestBudget)
+	return budget
+}
+
+// Used returns how many requests have been made so far
+func (b *RequestBudget) Used() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.used.Load())
+}
+
+// Exhausted reports whether the limit has been reached
+func (b *RequestBudget) Exhausted() bool {
+	return b != nil && b.limit > 0 && b.used.Load() >= b.limit
+}
+
+// take counts a request about to be made, returning false if it would exceed the limit
+func (b *RequestBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	if b.limit <= 0 {
+		b.used.Add(1)
+		return true
+	}
+	for {
+		used := b.used.Load()
+		if used >= b.limit {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+// ActionBudget caps how many posts a run deletes, redacts, unlikes or unshares, counted
+// together across every platform, as a guard against criteria that match far more than
+// meant. Like a RequestBudget, a nil budget counts nothing and allows everything.
+type ActionBudget struct {
+	limit int64 // Zero for no limit
+	used  atomic.Int64
+}
+
+// NewActionBudget creates a budget allowing limit actions, or any number if limit is zero
+func NewActionBudget(limit int) *ActionBudget {
+	return &ActionBudget{limit: int64(limit)}
+}
+
+type actionBudgetKey struct{}
+
+// WithActionBudget returns a context whose prune actions are counted against budget
+func WithActionBudget(ctx context.Context, budget *ActionBudget) context.Context {
+	return context.WithValue(ctx, actionBudgetKey{}, budget)
+}
+
+// ActionBudgetFromContext returns the action budget attached to ctx, or nil if there isn't one
+func ActionBudgetFromContext(ctx context.Context) *ActionBudget {
+	budget, _ := ctx.Value(actionBudgetKey{}).(*ActionBudget)
+	return budget
+}
+
+// Used returns how many actions have been taken so far
+func (b *ActionBudget) Used() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.used.Load())
+}
+
+// Exhausted reports whether the limit has been reached
+func (b *ActionBudget) Exhausted() bool {
+	return b != nil && b.limit > 0 && b.used.Load() >= b.limit
+}
+
+// take counts an action about to be taken, returning false if it would exceed the limit
+func (b *ActionBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	if b.limit <= 0 {
+		b.used.Add(1)
+		return true
+	}
+	for {
+		used := b.used.Load()
+		if used >= b.limit {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestBudget(t *testing.T) {
+	budget := NewRequestBudget(2)
+	if budget.Exhausted() {
+		t.Fatal("Fresh budget shouldn't be exhausted")
+	}
+	if !budget.take() || !budget.take() {
+		t.Fatal("Expected two requests to be allowed")
+	}
+	if !budget.Exhausted() {
+		t.Error("Expected budget to be exhausted after two requests")
+	}
+	if budget.take() {
+		t.Error("Expected a third request to be refused")
+	}
+	if budget.Used() != 2 {
+		t.Errorf("Expected 2 requests used, got %d", budget.Used())
+	}
+
+	unlimited := NewRequestBudget(0)
+	for i := 0; i < 100; i++ {
+		unlimited.take()
+	}
+	if unlimited.Exhausted() || unlimited.Used() != 100 {
+		t.Errorf("Expected an unlimited budget to count 100 requests, got %d (exhausted %v)", unlimited.Used(), unlimited.Exhausted())
+	}
+
+	var none *RequestBudget
+	if !none.take() || none.Exhausted() || none.Used() != 0 {
+		t.Error("A nil budget should allow everything and count nothing")
+	}
+}
+
+func TestActionBudget(t *testing.T) {
+	budget := NewActionBudget(1)
+	if !budget.take() || budget.take() || !budget.Exhausted() || budget.Used() != 1 {
+		t.Errorf("Expected one action allowed and the next refused, got %d used", budget.Used())
+	}
+
+	ctx := WithActionBudget(context.Background(), budget)
+	if ActionBudgetFromContext(ctx) != budget || ActionBudgetFromContext(context.Background()) != nil {
+		t.Error("Expected the budget back from the context it was attached to, and nil otherwise")
+	}
+
+	var none *ActionBudget
+	if !none.take() || none.Exhausted() || none.Used() != 0 {
+		t.Error("A nil budget should allow everything and count nothing")
+	}
+}
+
+func TestRequestBudget_LimitsHTTPRequests(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Retries count against the budget too
+	budget := NewRequestBudget(2)
+	ctx := WithRequestBudget(context.Background(), budget)
+	_, err := httpGetWithRetry(ctx, server.URL)
+	if !errors.Is(err, ErrRequestBudgetExhausted) {
+		t.Fatalf("Expected ErrRequestBudgetExhausted, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+
+	if _, err := httpGetWithRetry(ctx, server.URL); !errors.Is(err, ErrRequestBudgetExhausted) {
+		t.Errorf("Expected later requests to be refused, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected no more attempts once exhausted, got %d", calls)
+	}
+}
+
+func TestPruneOptions_RunLimitReached(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	exhausted := NewRequestBudget(1)
+	exhausted.take()
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		ctx      context.Context
+		expected string
+	}{
+		{"no limits", PruneOptions{}, context.Background(), ""},
+		{"deadline ahead", PruneOptions{Deadline: now.Add(time.Minute)}, context.Background(), ""},
+		{"deadline passed", PruneOptions{Deadline: now}, context.Background(), "--max-runtime"},
+		{"budget left", PruneOptions{}, WithRequestBudget(context.Background(), NewRequestBudget(5)), ""},
+		{"budget used up", PruneOptions{}, WithRequestBudget(context.Background(), exhausted), "--max-requests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.runLimitReached(tt.ctx, now); got != tt.expected {
+				t.Errorf("runLimitReached() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
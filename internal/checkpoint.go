@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Completed posts are saved in batches rather than one at a time, as each save rewrites
+// the whole checkpoint. A run that's killed outright can lose up to a batch, and acts on
+// those posts again when resumed.
+const (
+	checkpointSaveEvery    = 25               // Completed posts between saves
+	checkpointSaveInterval = 10 * time.Second // Longest a completed post goes unsaved while the run goes on
+)
+
+// PruneCheckpoint records how far a prune run has got on one account, so a run that's
+// interrupted can be resumed with --resume instead of starting again from the newest post.
+// It keeps the timeline cursor to start the next walk from, which is the page holding the
+// newest post the run still had to act on, and the IDs of the posts it has acted on. A
+// checkpoint only applies to a run with the same criteria, as given by CriteriaKey.
+type PruneCheckpoint struct {
+	Platform  string    `json:"platform"`
+	Username  string    `json:"username"`
+	Criteria  string    `json:"criteria"`
+	Cursor    string    `json:"cursor,omitempty"` // Where to start the timeline walk ("" for the newest post)
+	Completed []string  `json:"completed,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	store     *PruneCheckpointStore
+	clock     Clock
+	completed map[string]bool
+	pending   map[string]bool  // Posts the run means to act on and hasn't yet
+	pages     []checkpointPage // Timeline pages fetched by this run, oldest cursor last
+	saveErr   bool             // A save has failed and been logged
+	unsaved   int              // Posts completed since the last save
+	savedAt   time.Time        // When the checkpoint was last saved
+}
+
+// checkpointPage is a timeline page a run fetched, and the cursor it was fetched with
+type checkpointPage struct {
+	cursor string
+	ids    []string
+}
+
+// StartCursor returns the cursor a timeline walk starts from: the newest post, or where
+// the run being resumed left off. A nil checkpoint starts from the newest post.
+func (c *PruneCheckpoint) StartCursor() string {
+	if c == nil {
+		return ""
+	}
+	return c.Cursor
+}
+
+// addPage records a page of the timeline fetched with cursor
+func (c *PruneCheckpoint) addPage(cursor string, posts []Post) {
+	if c == nil {
+		return
+	}
+	page := checkpointPage{cursor: cursor}
+	for _, post := range posts {
+		page.ids = append(page.ids, post.ID)
+	}
+	c.pages = append(c.pages, page)
+}
+
+// Done reports whether the run being resumed already acted on the post with this ID
+func (c *PruneCheckpoint) Done(id string) bool {
+	return c != nil && c.completed[id]
+}
+
+// expect marks the posts a run is about to act on, which hold the cursor back until
+// they're done, and saves the checkpoint
+func (c *PruneCheckpoint) expect(ids []string) {
+	if c == nil {
+		return
+	}
+	if c.pending == nil {
+		c.pending = make(map[string]bool)
+	}
+	for _, id := range ids {
+		c.pending[id] = true
+	}
+	c.save()
+}
+
+// settle marks a post the run decided to leave alone after all, as when it's skipped
+// with --interactive
+func (c *PruneCheckpoint) settle(id string) {
+	if c == nil {
+		return
+	}
+	delete(c.pending, id)
+}
+
+// complete records that the run acted on a post, saving the checkpoint once enough posts
+// or time have gone by since the last save
+func (c *PruneCheckpoint) complete(id string) {
+	if c == nil {
+		return
+	}
+	delete(c.pending, id)
+	if !c.completed[id] {
+		if c.completed == nil {
+			c.completed = make(map[string]bool)
+		}
+		c.completed[id] = true
+		c.Completed = append(c.Completed, id)
+		c.unsaved++
+	}
+	if c.unsaved >= checkpointSaveEvery || c.clock.Now().Sub(c.savedAt) >= checkpointSaveInterval {
+		c.save()
+	}
+}
+
+// flush saves posts completed since the last save, for when the run stops acting on posts
+func (c *PruneCheckpoint) flush() {
+	if c == nil || c.unsaved == 0 {
+		return
+	}
+	c.save()
+}
+
+// advance moves Cursor up to the first fetched page that still holds a pending post, or
+// with none pending, to the last page fetched
+func (c *PruneCheckpoint) advance() {
+	for _, page := range c.pages {
+		for _, id := range page.ids {
+			if c.pending[id] {
+				c.Cursor = page.cursor
+				return
+			}
+		}
+	}
+	if len(c.pages) > 0 {
+		c.Cursor = c.pages[len(c.pages)-1].cursor
+	}
+}
+
+// save writes the checkpoint to its store, logging rather than failing the run if it can't
+func (c *PruneCheckpoint) save() {
+	if c.store == nil {
+		return
+	}
+	c.unsaved = 0
+	c.savedAt = c.clock.Now()
+	if err := c.Save(); err != nil && !c.saveErr {
+		c.saveErr = true
+		WithPlatform(c.Platform).Warn().Err(err).Msg("Failed to save prune checkpoint, --resume won't be able to pick up from here")
+	}
+}
+
+// Save brings the cursor up to date and writes the checkpoint to the store it came from
+func (c *PruneCheckpoint) Save() error {
+	c.advance()
+	c.UpdatedAt = c.clock.Now()
+	return c.store.save(c)
+}
+
+// Remove deletes the checkpoint once the run it records has finished
+func (c *PruneCheckpoint) Remove() error {
+	return c.store.Remove(c.Platform, c.Username)
+}
+
+// CriteriaKey identifies what a prune with these options picks out and what it does to
+// it, leaving out how the run goes about it, so a resumed run can check it's carrying on
+// with the same prune
+func (o PruneOptions) CriteriaKey() string {
+	criteria := o
+	criteria.DryRun = false
+	criteria.BatchWrites = false
+	criteria.RateLimitDelay = 0
+	criteria.ContinueUntilEnd = false
+	criteria.FromIndex = false
+	criteria.ProgressEvery = 0
+	criteria.ProgressInterval = 0
+	criteria.Deadline = time.Time{}
+	criteria.AllowAccountMismatch = false
+
+	data, _ := json.Marshal(criteria)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// PruneCheckpointStore keeps the checkpoint of the latest unfinished prune of each
+// account, one JSON file apiece:
+//
+//	<platform>-<username>.json
+type PruneCheckpointStore struct {
+	dir   string
+	clock Clock
+	mu    sync.Mutex
+}
+
+// NewPruneCheckpointStoreAt creates a checkpoint store rooted at the given directory
+func NewPruneCheckpointStoreAt(dir string, clock Clock) *PruneCheckpointStore {
+	return &PruneCheckpointStore{dir: dir, clock: clock}
+}
+
+func (s *PruneCheckpointStore) path(platform, username string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(strings.ToLower(username))
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s.json", strings.ToLower(platform), name))
+}
+
+// Start returns a new checkpoint for a run on an account with criteria. It replaces any
+// earlier checkpoint for the account once it's first saved.
+func (s *PruneCheckpointStore) Start(platform, username, criteria string) *PruneCheckpoint {
+	return &PruneCheckpoint{Platform: platform, Username: username, Criteria: criteria, store: s, clock: s.clock}
+}
+
+// Load returns the checkpoint of an account's unfinished prune, or nil if there's none
+func (s *PruneCheckpointStore) Load(platform, username string) (*PruneCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(platform, username))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read prune checkpoint: %w", err)
+	}
+
+	var checkpoint PruneCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse prune checkpoint: %w", err)
+	}
+	checkpoint.store = s
+	checkpoint.clock = s.clock
+	checkpoint.completed = make(map[string]bool, len(checkpoint.Completed))
+	for _, id := range checkpoint.Completed {
+		checkpoint.completed[id] = true
+	}
+	return &checkpoint, nil
+}
+
+func (s *PruneCheckpointStore) save(checkpoint *PruneCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal prune checkpoint: %w", err)
+	}
+
+	// Write then rename, so an interruption never leaves a half-written checkpoint
+	path := s.path(checkpoint.Platform, checkpoint.Username)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to write prune checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write prune checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes an account's checkpoint, if it has one
+func (s *PruneCheckpointStore) Remove(platform, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(platform, username)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove prune checkpoint: %w", err)
+	}
+	return nil
+}
+
+var (
+	defaultPruneCheckpoints     *PruneCheckpointStore
+	defaultPruneCheckpointsOnce sync.Once
+)
+
+// DefaultPruneCheckpointStore returns the shared checkpoint store in
+// ~/.config/cringesweeper/checkpoints, or nil if it can't be created
+func DefaultPruneCheckpointStore() *PruneCheckpointStore {
+	defaultPruneCheckpointsOnce.Do(func() {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			Logger.Warn().Err(err).Msg("Prune checkpoints unavailable")
+			return
+		}
+		defaultPruneCheckpoints = NewPruneCheckpointStoreAt(filepath.Join(homeDir, ".config", "cringesweeper", "checkpoints"), SystemClock)
+	})
+	return defaultPruneCheckpoints
+}
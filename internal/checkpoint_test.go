@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPruneCheckpointStore(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := NewPruneCheckpointStoreAt(t.TempDir(), NewFakeClock(now))
+
+	if checkpoint, err := store.Load("mastodon", "me@example.social"); err != nil || checkpoint != nil {
+		t.Fatalf("Expected no checkpoint before a run, got %+v, %v", checkpoint, err)
+	}
+
+	checkpoint := store.Start("mastodon", "Me@example.social", "criteria")
+	checkpoint.addPage("", []Post{{ID: "1"}, {ID: "2"}})
+	checkpoint.addPage("2", []Post{{ID: "3"}, {ID: "4"}})
+	checkpoint.expect([]string{"2", "3"})
+	checkpoint.complete("2")
+	checkpoint.flush()
+
+	loaded, err := store.Load("Mastodon", "me@example.social")
+	if err != nil || loaded == nil {
+		t.Fatalf("Load() = %+v, %v", loaded, err)
+	}
+	if loaded.Cursor != "2" || loaded.Criteria != "criteria" || !loaded.UpdatedAt.Equal(now) {
+		t.Errorf("Expected the cursor at the page still to do, got %+v", loaded)
+	}
+	if !loaded.Done("2") || loaded.Done("3") {
+		t.Errorf("Expected only post 2 to be done, got %v", loaded.Completed)
+	}
+
+	if err := loaded.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if checkpoint, _ := store.Load("mastodon", "me@example.social"); checkpoint != nil {
+		t.Errorf("Expected the checkpoint to be gone, got %+v", checkpoint)
+	}
+}
+
+func TestPruneCheckpoint_Cursor(t *testing.T) {
+	store := NewPruneCheckpointStoreAt(t.TempDir(), NewFakeClock(time.Now()))
+	pages := func() *PruneCheckpoint {
+		checkpoint := store.Start("bluesky", "me", "criteria")
+		checkpoint.addPage("", []Post{{ID: "a"}, {ID: "b"}})
+		checkpoint.addPage("c1", []Post{{ID: "c"}, {ID: "d"}})
+		checkpoint.addPage("c2", []Post{{ID: "e"}})
+		return checkpoint
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+		done     []string
+		skipped  []string
+		want     string
+	}{
+		{"nothing done yet", []string{"b", "d"}, nil, nil, ""},
+		{"first page done", []string{"b", "d"}, []string{"b"}, nil, "c1"},
+		{"done out of order", []string{"b", "d", "e"}, []string{"e", "b"}, nil, "c1"},
+		{"skipped posts don't hold it back", []string{"b", "d"}, []string{"b"}, []string{"d"}, "c2"},
+		{"everything done", []string{"a", "c", "e"}, []string{"a", "c", "e"}, nil, "c2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoint := pages()
+			checkpoint.expect(tt.expected)
+			for _, id := range tt.skipped {
+				checkpoint.settle(id)
+			}
+			for _, id := range tt.done {
+				checkpoint.complete(id)
+			}
+			checkpoint.advance()
+			if checkpoint.Cursor != tt.want {
+				t.Errorf("Cursor = %q, want %q", checkpoint.Cursor, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneCheckpoint_BatchedSaves(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	store := NewPruneCheckpointStoreAt(t.TempDir(), clock)
+	checkpoint := store.Start("mastodon", "me", "criteria")
+	var ids []string
+	for i := range checkpointSaveEvery + 3 {
+		ids = append(ids, fmt.Sprintf("post-%d", i))
+	}
+	checkpoint.addPage("", nil)
+	checkpoint.expect(ids)
+
+	saved := func() int {
+		t.Helper()
+		loaded, err := store.Load("mastodon", "me")
+		if err != nil || loaded == nil {
+			t.Fatalf("Expected a saved checkpoint, got %+v, %v", loaded, err)
+		}
+		return len(loaded.Completed)
+	}
+
+	for _, id := range ids[:checkpointSaveEvery-1] {
+		checkpoint.complete(id)
+	}
+	if got := saved(); got != 0 {
+		t.Errorf("Expected no completed posts saved before a batch fills, got %d", got)
+	}
+	checkpoint.complete(ids[checkpointSaveEvery-1])
+	if got := saved(); got != checkpointSaveEvery {
+		t.Errorf("Expected a full batch to be saved, got %d", got)
+	}
+
+	// A slow run still saves once the interval has gone by
+	checkpoint.complete(ids[checkpointSaveEvery])
+	clock.Advance(checkpointSaveInterval)
+	checkpoint.complete(ids[checkpointSaveEvery+1])
+	if got := saved(); got != checkpointSaveEvery+2 {
+		t.Errorf("Expected the interval to trigger a save, got %d", got)
+	}
+
+	// And whatever is left is saved when the run stops
+	checkpoint.complete(ids[checkpointSaveEvery+2])
+	checkpoint.flush()
+	if got := saved(); got != len(ids) {
+		t.Errorf("Expected flush to save every completed post, got %d", got)
+	}
+}
+
+func TestPruneOptions_CriteriaKey(t *testing.T) {
+	maxAge := 30 * 24 * time.Hour
+	otherAge := 60 * 24 * time.Hour
+	base := PruneOptions{MaxAge: &maxAge, PreservePinned: true}
+
+	sameRun := base
+	sameRun.RateLimitDelay = 5 * time.Second
+	sameRun.ContinueUntilEnd = true
+	sameRun.ProgressEvery = 100
+	sameRun.Deadline = time.Now()
+	if sameRun.CriteriaKey() != base.CriteriaKey() {
+		t.Error("Expected how the run goes not to change its criteria")
+	}
+
+	for name, options := range map[string]PruneOptions{
+		"age":      {MaxAge: &otherAge, PreservePinned: true},
+		"preserve": {MaxAge: &maxAge},
+		"action":   {MaxAge: &maxAge, PreservePinned: true, UnlikePosts: true},
+	} {
+		if options.CriteriaKey() == base.CriteriaKey() {
+			t.Errorf("Expected a different %s to change the criteria", name)
+		}
+	}
+}
+
+func TestPruneEngine_Checkpoint(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := time.Hour
+	old := now.Add(-2 * time.Hour)
+	posts := []Post{
+		{ID: "cp-1", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "cp-2", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "cp-3", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "cp-4", Type: PostTypeOriginal, CreatedAt: old},
+	}
+	store := NewPruneCheckpointStoreAt(t.TempDir(), NewFakeClock(now))
+
+	// The first run fails on a post on the second page
+	withTombstoneStore(t)
+	checkpoint := store.Start("mastodon", "me", "criteria")
+	checkpoint.addPage("", posts[:2])
+	checkpoint.addPage("cp-2", posts[2:])
+	actor := &fakePruneActor{fail: map[string]bool{"cp-3": true}}
+	options := PruneOptions{MaxAge: &maxAge, Checkpoint: checkpoint}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	resumed, err := store.Load("mastodon", "me")
+	if err != nil || resumed == nil {
+		t.Fatalf("Expected a saved checkpoint, got %+v, %v", resumed, err)
+	}
+	if resumed.Cursor != "cp-2" || !slices.Equal(resumed.Completed, []string{"cp-1", "cp-2", "cp-4"}) {
+		t.Errorf("Expected to resume at the failed post's page, got %+v", resumed)
+	}
+
+	// Resumed without the tombstones, the checkpoint alone keeps completed posts from being redone
+	withTombstoneStore(t)
+	actor = &fakePruneActor{}
+	options.Checkpoint = resumed
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts[2:], &PruneResult{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !slices.Equal(actor.acted, []string{"delete cp-3"}) {
+		t.Errorf("Expected the resumed run to retry only the failed post, got %v", actor.acted)
+	}
+}
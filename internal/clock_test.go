@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock_Advance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+
+	clock.Advance(90 * time.Minute)
+	if expected := start.Add(90 * time.Minute); !clock.Now().Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, clock.Now())
+	}
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ticker := clock.NewTicker(time.Hour)
+
+	t.Run("does not fire before interval", func(t *testing.T) {
+		clock.Advance(59 * time.Minute)
+		select {
+		case tick := <-ticker.C():
+			t.Errorf("Unexpected tick at %v", tick)
+		default:
+		}
+	})
+
+	t.Run("fires when interval elapses", func(t *testing.T) {
+		clock.Advance(time.Minute)
+		select {
+		case tick := <-ticker.C():
+			if expected := start.Add(time.Hour); !tick.Equal(expected) {
+				t.Errorf("Expected tick at %v, got %v", expected, tick)
+			}
+		default:
+			t.Error("Expected a tick after one interval")
+		}
+	})
+
+	t.Run("drops ticks for a slow receiver", func(t *testing.T) {
+		clock.Advance(3 * time.Hour)
+		select {
+		case <-ticker.C():
+		default:
+			t.Fatal("Expected a buffered tick")
+		}
+		select {
+		case tick := <-ticker.C():
+			t.Errorf("Expected extra ticks to be dropped, got %v", tick)
+		default:
+		}
+	})
+
+	t.Run("stopped ticker does not fire", func(t *testing.T) {
+		ticker.Stop()
+		clock.Advance(2 * time.Hour)
+		select {
+		case tick := <-ticker.C():
+			t.Errorf("Unexpected tick after Stop at %v", tick)
+		default:
+		}
+	})
+}
+
+func TestSessionManager_ExpiryWithFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	sm := NewSessionManager("bluesky")
+	sm.clock = clock
+
+	sm.UpdateSession("access", "refresh", start.Add(time.Hour), &Credentials{Username: "user"})
+	if !sm.IsSessionValid() {
+		t.Error("Session should be valid right after creation")
+	}
+
+	// Sessions are treated as expired 5 minutes early
+	clock.Advance(54 * time.Minute)
+	if !sm.IsSessionValid() {
+		t.Error("Session should still be valid 6 minutes before expiry")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if sm.IsSessionValid() {
+		t.Error("Session should be invalid within 5 minutes of expiry")
+	}
+}
+
+func TestClientsUseInjectedClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	bsky := NewBlueskyClient()
+	bsky.SetClock(clock)
+	if bsky.clock != clock || bsky.sessionManager.clock != clock {
+		t.Error("BlueskyClient.SetClock should update the client and session manager clocks")
+	}
+
+	mastodon := NewMastodonClient()
+	mastodon.SetClock(clock)
+	if mastodon.clock != clock || mastodon.sessionManager.clock != clock {
+		t.Error("MastodonClient.SetClock should update the client and session manager clocks")
+	}
+}
+
+func TestFakeClock_After(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	c := clock.After(time.Hour)
+	if clock.Waiters() != 1 {
+		t.Errorf("Expected 1 waiter, got %d", clock.Waiters())
+	}
+
+	clock.Advance(59 * time.Minute)
+	select {
+	case fired := <-c:
+		t.Errorf("Unexpected fire at %v", fired)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	select {
+	case fired := <-c:
+		if expected := start.Add(time.Hour); !fired.Equal(expected) {
+			t.Errorf("Expected fire at %v, got %v", expected, fired)
+		}
+	default:
+		t.Error("Expected After to fire once the duration elapsed")
+	}
+	if clock.Waiters() != 0 {
+		t.Errorf("Expected no waiters after firing, got %d", clock.Waiters())
+	}
+
+	// Non-positive durations fire immediately
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("Expected After(0) to fire immediately")
+	}
+}
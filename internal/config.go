@@ -0,0 +1,348 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Config is a parsed config file. Top-level keys are flag names that apply to every
+// command; a section is a command name ("prune", "server") or a platform name
+// ("bluesky") holding keys that only apply there. Every value is a list, with scalars
+// stored as a single item.
+type Config struct {
+	Values   map[string][]string
+	Sections map[string]map[string][]string
+}
+
+// DefaultConfigPath returns ~/.config/cringesweeper/config.yaml
+func DefaultConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "cringesweeper", "config.yaml"), nil
+}
+
+// LoadConfigFile reads and parses a config file. A missing file is returned as an
+// error wrapping fs.ErrNotExist so callers can treat the default path as optional.
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig parses the YAML subset used for config files: "key: value" pairs, one level
+// of sections, and lists written either as "- item" lines or inline as [a, b]. Keys may
+// use underscores in place of hyphens. Values may refer to environment variables as
+// ${VAR}, so that secrets can stay out of the file (see expandConfigEnv).
+//
+//	platforms: [bluesky, mastodon]
+//	max-post-age: 30d
+//	preserve-hashtags: "#keep"
+//	server:
+//	  prune-schedule:
+//	    - "0 3 * * *"
+//	bluesky:
+//	  max-post-age: 90d
+//	server:
+//	  auth-token: ${CRINGESWEEPER_AUTH_TOKEN}
+func ParseConfig(data string) (*Config, error) {
+	config := &Config{
+		Values:   make(map[string][]string),
+		Sections: make(map[string]map[string][]string),
+	}
+
+	var (
+		topKey      string // Top-level key whose block we're in, if any
+		topIndent   = -1   // Indentation of the lines inside that block
+		sectionKey  string // Key inside a section whose list we're in, if any
+		sectionList = -1   // Indentation of that list's items
+	)
+
+	// A key with an empty block is more likely a mistake than a deliberate empty setting
+	checkBlock := func() error {
+		if topKey == "" {
+			return nil
+		}
+		_, isList := config.Values[topKey]
+		_, isSection := config.Sections[topKey]
+		if !isList && !isSection {
+			return fmt.Errorf("%s has no value", topKey)
+		}
+		return nil
+	}
+
+	for i, raw := range strings.Split(data, "\n") {
+		lineNum := i + 1
+		line := stripConfigComment(strings.TrimRight(raw, " \r"))
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.Contains(line[:len(line)-len(strings.TrimLeft(line, " \t"))], "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", lineNum)
+		}
+		content := strings.TrimLeft(line, " ")
+		indent := len(line) - len(content)
+
+		// YAML also allows a top-level list's items to start at column 0
+		if indent == 0 && topKey != "" && sectionKey == "" {
+			if item, ok := parseConfigListItem(content); ok {
+				if _, isSection := config.Sections[topKey]; !isSection {
+					config.Values[topKey] = append(config.Values[topKey], item)
+					continue
+				}
+			}
+		}
+
+		// A new top-level key ends any open block
+		if indent == 0 {
+			if err := checkBlock(); err != nil {
+				return nil, err
+			}
+			topKey, topIndent, sectionKey, sectionList = "", -1, "", -1
+			key, value, err := parseConfigPair(content)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if _, exists := config.Values[key]; exists {
+				return nil, fmt.Errorf("line %d: %s is set more than once", lineNum, key)
+			}
+			if _, exists := config.Sections[key]; exists {
+				return nil, fmt.Errorf("line %d: %s is set more than once", lineNum, key)
+			}
+			if value == "" {
+				topKey = key
+				continue
+			}
+			config.Values[key] = parseConfigValue(value)
+			continue
+		}
+
+		if topKey == "" {
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNum)
+		}
+		if topIndent < 0 {
+			topIndent = indent
+		}
+
+		// Items of a list under a key inside a section, which may be indented further or
+		// line up with the key
+		_, isItem := parseConfigListItem(content)
+		if sectionKey != "" && (indent > topIndent || (isItem && indent == topIndent)) {
+			if sectionList < 0 {
+				sectionList = indent
+			}
+			item, ok := parseConfigListItem(content)
+			if !ok || indent != sectionList {
+				return nil, fmt.Errorf("line %d: expected a list item for %s", lineNum, sectionKey)
+			}
+			section := config.Sections[topKey]
+			section[sectionKey] = append(section[sectionKey], item)
+			continue
+		}
+
+		if indent != topIndent {
+			return nil, fmt.Errorf("line %d: inconsistent indentation", lineNum)
+		}
+		sectionKey, sectionList = "", -1
+
+		// Items of a top-level list
+		if item, ok := parseConfigListItem(content); ok {
+			if _, isSection := config.Sections[topKey]; isSection {
+				return nil, fmt.Errorf("line %d: %s mixes keys and list items", lineNum, topKey)
+			}
+			config.Values[topKey] = append(config.Values[topKey], item)
+			continue
+		}
+
+		// Keys of a section
+		if _, isList := config.Values[topKey]; isList {
+			return nil, fmt.Errorf("line %d: %s mixes keys and list items", lineNum, topKey)
+		}
+		key, value, err := parseConfigPair(content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		section, ok := config.Sections[topKey]
+		if !ok {
+			section = make(map[string][]string)
+			config.Sections[topKey] = section
+		}
+		if _, exists := section[key]; exists {
+			return nil, fmt.Errorf("line %d: %s.%s is set more than once", lineNum, topKey, key)
+		}
+		if value == "" {
+			sectionKey = key
+			section[key] = nil
+			continue
+		}
+		section[key] = parseConfigValue(value)
+	}
+
+	if err := checkBlock(); err != nil {
+		return nil, err
+	}
+	for name, section := range config.Sections {
+		for key, values := range section {
+			if len(values) == 0 {
+				return nil, fmt.Errorf("%s.%s has no value", name, key)
+			}
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(config.Values)) {
+		if err := expandConfigValues(config.Values[key]); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Sections)) {
+		section := config.Sections[name]
+		for _, key := range slices.Sorted(maps.Keys(section)) {
+			if err := expandConfigValues(section[key]); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, key, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+// expandConfigValues expands environment variables in each of a setting's values
+func expandConfigValues(values []string) error {
+	for i, value := range values {
+		expanded, err := expandConfigEnv(value)
+		if err != nil {
+			return err
+		}
+		values[i] = expanded
+	}
+	return nil
+}
+
+// expandConfigEnv replaces ${VAR} in a config value with the environment variable's
+// value, and ${VAR:-default} with the default when the variable is unset or empty. An
+// unset variable without a default is an error, so a missing secret can't quietly become
+// an empty setting. "$${" stands for a literal "${", so "$${VAR}" is kept as "${VAR}";
+// any other $, including $$, is left as it is, so values written before expansion such
+// as passwords with $$ in them don't change.
+func expandConfigEnv(value string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(value, '$')
+		if i < 0 || i == len(value)-1 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		b.WriteString(value[:i])
+		switch value[i+1] {
+		case '$':
+			if strings.HasPrefix(value[i+2:], "{") {
+				b.WriteByte('$')
+			} else {
+				b.WriteString("$$")
+			}
+			value = value[i+2:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			value = value[i+1:]
+			continue
+		}
+
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", value)
+		}
+		name, fallback, hasFallback := strings.Cut(value[i+2:i+end], ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty ${} in %q", value)
+		}
+		envValue, set := os.LookupEnv(name)
+		switch {
+		case envValue != "":
+			b.WriteString(envValue)
+		case hasFallback:
+			b.WriteString(fallback)
+		case set:
+		default:
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		value = value[i+end+1:]
+	}
+}
+
+// parseConfigPair splits "key: value", normalizing the key
+func parseConfigPair(content string) (key, value string, err error) {
+	key, value, ok := strings.Cut(content, ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \"'") {
+		return "", "", errors.New(`expected "key: value"`)
+	}
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-"), strings.TrimSpace(value), nil
+}
+
+func parseConfigListItem(content string) (string, bool) {
+	if content != "-" && !strings.HasPrefix(content, "- ") {
+		return "", false
+	}
+	return parseConfigScalar(strings.TrimPrefix(content, "-")), true
+}
+
+// parseConfigValue parses a scalar or an inline [a, b] list
+func parseConfigValue(value string) []string {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var items []string
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = parseConfigScalar(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return []string{parseConfigScalar(value)}
+}
+
+func parseConfigScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// stripConfigComment removes a # comment that isn't inside quotes. As in YAML, a # only
+// starts a comment at the start of a line or after whitespace, so "#keep" needs quoting.
+func stripConfigComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(`
+# Shared by every command
+platforms: [bluesky, mastodon]
+max_post_age: 30d        # underscores work too
+preserve-hashtags: "#keep, #portfolio"
+dry-run: true
+with-hashtags:
+- '#conf2019'
+- "#conf2020"
+
+server:
+  port: 9090
+  prune-schedule:
+    - "0 3 * * *"
+    - mastodon=30 4 * * 1-5
+  breaker-threshold: 5
+
+bluesky:
+  max-post-age: 90d
+  preserve-pinned: true
+`)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	wantValues := map[string][]string{
+		"platforms":         {"bluesky", "mastodon"},
+		"max-post-age":      {"30d"},
+		"preserve-hashtags": {"#keep, #portfolio"},
+		"dry-run":           {"true"},
+		"with-hashtags":     {"#conf2019", "#conf2020"},
+	}
+	if !reflect.DeepEqual(config.Values, wantValues) {
+		t.Errorf("Values = %v, want %v", config.Values, wantValues)
+	}
+
+	wantSections := map[string]map[string][]string{
+		"server": {
+			"port":              {"9090"},
+			"prune-schedule":    {"0 3 * * *", "mastodon=30 4 * * 1-5"},
+			"breaker-threshold": {"5"},
+		},
+		"bluesky": {
+			"max-post-age":    {"90d"},
+			"preserve-pinned": {"true"},
+		},
+	}
+	if !reflect.DeepEqual(config.Sections, wantSections) {
+		t.Errorf("Sections = %v, want %v", config.Sections, wantSections)
+	}
+}
+
+func TestParseConfig_SectionListAlignedWithKey(t *testing.T) {
+	config, err := ParseConfig("server:\n  prune-schedule:\n  - \"@daily\"\n  port: 80\n")
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if got := config.Sections["server"]["prune-schedule"]; !reflect.DeepEqual(got, []string{"@daily"}) {
+		t.Errorf("Unexpected prune-schedule %v", got)
+	}
+	if got := config.Sections["server"]["port"]; !reflect.DeepEqual(got, []string{"80"}) {
+		t.Errorf("Unexpected port %v", got)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not a pair":            "max-post-age 30d\n",
+		"duplicate key":         "max-post-age: 30d\nmax-post-age: 1y\n",
+		"duplicate section key": "server:\n  port: 1\n  port: 2\n",
+		"stray indentation":     "  max-post-age: 30d\n",
+		"inconsistent indent":   "server:\n  port: 1\n    dry-run: true\n",
+		"tab indent":            "server:\n\tport: 1\n",
+		"mixed list and keys":   "server:\n  port: 1\n  - x\n",
+		"empty value":           "max-post-age:\n",
+		"unquoted hashtag":      "preserve-hashtags: #keep\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseConfig(data); err == nil {
+				t.Errorf("Expected error for %q", data)
+			}
+		})
+	}
+}
+
+func TestParseConfig_EnvExpansion(t *testing.T) {
+	t.Setenv("CS_TEST_TOKEN", "s3cret")
+	t.Setenv("CS_TEST_TAG", "keep")
+	t.Setenv("CS_TEST_EMPTY", "")
+
+	config, err := ParseConfig(`
+preserve-hashtags: "#${CS_TEST_TAG}, #portfolio"
+with-hashtags: ["#${CS_TEST_TAG}", "#${CS_TEST_UNSET:-other}"]
+server:
+  auth-token: ${CS_TEST_TOKEN}
+  metrics-token: ${CS_TEST_EMPTY}
+  webhook-url: ${CS_TEST_EMPTY:-https://example.com/hook}
+  prune-schedule:
+    - "$${CS_TEST_TOKEN} costs $5"
+bluesky:
+  password: "pa$$word$$"
+`)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	wantValues := map[string][]string{
+		"preserve-hashtags": {"#keep, #portfolio"},
+		"with-hashtags":     {"#keep", "#other"},
+	}
+	if !reflect.DeepEqual(config.Values, wantValues) {
+		t.Errorf("Values = %v, want %v", config.Values, wantValues)
+	}
+	wantServer := map[string][]string{
+		"auth-token":     {"s3cret"},
+		"metrics-token":  {""},
+		"webhook-url":    {"https://example.com/hook"},
+		"prune-schedule": {"${CS_TEST_TOKEN} costs $5"},
+	}
+	if !reflect.DeepEqual(config.Sections["server"], wantServer) {
+		t.Errorf("server = %v, want %v", config.Sections["server"], wantServer)
+	}
+	// $$ is only an escape in front of {, so values written before expansion are unchanged
+	if got := config.Sections["bluesky"]["password"]; !reflect.DeepEqual(got, []string{"pa$$word$$"}) {
+		t.Errorf("password = %v, want the literal pa$$word$$", got)
+	}
+}
+
+func TestParseConfig_EnvExpansionErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unset variable": "server:\n  auth-token: ${CS_TEST_UNSET}\n",
+		"unterminated":   "auth-token: ${CS_TEST_TOKEN\n",
+		"empty name":     "auth-token: ${}\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseConfig(data); err == nil {
+				t.Errorf("Expected error for %q", data)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if _, err := LoadConfigFile(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a not-exist error for a missing file, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("max-post-age: 30d\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile failed: %v", err)
+	}
+	if config.Values["max-post-age"][0] != "30d" {
+		t.Errorf("Unexpected config %v", config.Values)
+	}
+}
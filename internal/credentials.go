@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 // GetCredentialsForPlatform attempts to load credentials using multiple fallback methods
@@ -24,7 +25,7 @@ func GetCredentialsForPlatform(platform string) (*Credentials, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("no valid credentials found for platform %s. Run 'cringesweeper auth --platforms=%s' to set up authentication", platform, platform)
+	return nil, fmt.Errorf("%w for platform %s. Run 'cringesweeper auth --platforms=%s' to set up authentication", ErrNoCredentials, platform, platform)
 }
 
 // GetUsernameForPlatform gets username with fallback priority: argument > saved credentials > environment
@@ -34,6 +35,14 @@ func GetUsernameForPlatform(platform string, argUsername string) (string, error)
 		return argUsername, nil
 	}
 
+	// Archive platforms take a file path rather than a username
+	if platform == "twitter" {
+		if archivePath := os.Getenv("TWITTER_ARCHIVE"); archivePath != "" {
+			return archivePath, nil
+		}
+		return "", fmt.Errorf("no Twitter archive found. Please provide the path to your archive zip as an argument or set TWITTER_ARCHIVE environment variable")
+	}
+
 	// Try to get username from saved credentials
 	authManager, err := NewAuthManager()
 	if err == nil {
@@ -50,8 +59,8 @@ func GetUsernameForPlatform(platform string, argUsername string) (string, error)
 		if username := os.Getenv("BLUESKY_USER"); username != "" {
 			return username, nil
 		}
-	case "mastodon":
-		if username := os.Getenv("MASTODON_USER"); username != "" {
+	case "mastodon", "gotosocial":
+		if username := os.Getenv(strings.ToUpper(platform) + "_USER"); username != "" {
 			return username, nil
 		}
 	}
@@ -61,14 +70,14 @@ func GetUsernameForPlatform(platform string, argUsername string) (string, error)
 		return username, nil
 	}
 
-	return "", fmt.Errorf("no username found. Please provide a username as an argument, run 'cringesweeper auth --platforms=%s', or set %s_USER environment variable", platform, map[string]string{"bluesky": "BLUESKY", "mastodon": "MASTODON"}[platform])
+	return "", fmt.Errorf("no username found. Please provide a username as an argument, run 'cringesweeper auth --platforms=%s', or set %s_USER environment variable", platform, strings.ToUpper(platform))
 }
 
 // GetCredentialsForPlatformEnvOnly only loads credentials from environment variables (for server mode)
 func GetCredentialsForPlatformEnvOnly(platform string) (*Credentials, error) {
 	creds := GetCredentialsFromEnv(platform)
 	if creds == nil {
-		return nil, fmt.Errorf("no credentials found in environment variables for platform %s. In server mode, credentials must be provided via environment variables", platform)
+		return nil, fmt.Errorf("%w in environment variables for platform %s. In server mode, credentials must be provided via environment variables", ErrNoCredentials, platform)
 	}
 	
 	if err := ValidateCredentials(creds); err != nil {
@@ -94,11 +103,12 @@ func GetUsernameForPlatformEnvOnly(platform string, argUsername string) (string,
 		if username := os.Getenv("BLUESKY_USERNAME"); username != "" {
 			return username, nil
 		}
-	case "mastodon":
-		if username := os.Getenv("MASTODON_USER"); username != "" {
+	case "mastodon", "gotosocial":
+		prefix := strings.ToUpper(platform)
+		if username := os.Getenv(prefix + "_USER"); username != "" {
 			return username, nil
 		}
-		if username := os.Getenv("MASTODON_USERNAME"); username != "" {
+		if username := os.Getenv(prefix + "_USERNAME"); username != "" {
 			return username, nil
 		}
 	}
@@ -108,5 +118,48 @@ func GetUsernameForPlatformEnvOnly(platform string, argUsername string) (string,
 		return username, nil
 	}
 
-	return "", fmt.Errorf("no username found in environment variables. In server mode, please provide a username as an argument or set %s_USERNAME environment variable", map[string]string{"bluesky": "BLUESKY", "mastodon": "MASTODON"}[platform])
+	return "", fmt.Errorf("no username found in environment variables. In server mode, please provide a username as an argument or set %s_USERNAME environment variable", strings.ToUpper(platform))
+}
+
+// CredentialMismatch is saved credentials and environment variables that belong to
+// different accounts on the same platform. Saved credentials take precedence, which is
+// easy to forget when the environment was set up for another account.
+type CredentialMismatch struct {
+	Platform  string
+	SavedUser string // The account that will be used
+	EnvUser   string // The account that will be ignored
+}
+
+// CheckCredentialMismatch returns the mismatch when both saved credentials and
+// environment variables are usable for the platform but name different accounts, or nil
+func CheckCredentialMismatch(platform string) *CredentialMismatch {
+	authManager, err := NewAuthManager()
+	if err != nil {
+		return nil
+	}
+	saved, err := authManager.LoadCredentials(platform)
+	if err != nil || ValidateCredentials(saved) != nil {
+		return nil
+	}
+	env := GetCredentialsFromEnv(platform)
+	if env == nil || ValidateCredentials(env) != nil {
+		return nil
+	}
+
+	savedUser, envUser := accountName(saved), accountName(env)
+	if strings.EqualFold(savedUser, envUser) {
+		return nil
+	}
+	return &CredentialMismatch{Platform: platform, SavedUser: savedUser, EnvUser: envUser}
+}
+
+// accountName identifies the account credentials belong to: the handle on Bluesky, and
+// user@host on Mastodon and GoToSocial, where the same username on two instances is two
+// accounts
+func accountName(creds *Credentials) string {
+	username := strings.TrimPrefix(strings.TrimSpace(creds.Username), "@")
+	if _, ok := mastodonFlavors[creds.Platform]; !ok || strings.Contains(username, "@") {
+		return username
+	}
+	return username + "@" + instanceHost(creds.Instance)
 }
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"sync"
+)
+
+// Drain asks prune runs to wind down: to finish the post in hand but start no more, and
+// return what they did as a normal result. A server shutting down drains its runs so
+// they end with a partial result rather than being abandoned. Like a RequestBudget, a
+// nil Drain is never started, so callers never need to check for one.
+type Drain struct {
+	once sync.Once
+	done chan struct{}
+}
+
+// NewDrain creates a drain that hasn't started
+func NewDrain() *Drain {
+	return &Drain{done: make(chan struct{})}
+}
+
+// Start tells the runs using the drain to stop. It can be called more than once.
+func (d *Drain) Start() {
+	d.once.Do(func() { close(d.done) })
+}
+
+// Draining reports whether Start has been called
+func (d *Drain) Draining() bool {
+	if d == nil {
+		return false
+	}
+	select {
+	case <-d.done:
+		return true
+	default:
+		return false
+	}
+}
+
+type drainKey struct{}
+
+// WithDrain returns a context whose prune runs stop when drain is started
+func WithDrain(ctx context.Context, drain *Drain) context.Context {
+	return context.WithValue(ctx, drainKey{}, drain)
+}
+
+// DrainFromContext returns the drain attached to ctx, or nil if there isn't one
+func DrainFromContext(ctx context.Context) *Drain {
+	drain, _ := ctx.Value(drainKey{}).(*Drain)
+	return drain
+}
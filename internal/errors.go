@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoCredentials is wrapped by credential lookups that find nothing for a platform
+var ErrNoCredentials = errors.New("no credentials found")
+
+// APIError is a non-success HTTP response from a platform API
+type APIError struct {
+	Platform   string
+	Operation  string // What was being attempted, e.g. "delete request"
+	StatusCode int
+	Body       string
+}
+
+func newAPIError(platform, operation string, statusCode int, body []byte) *APIError {
+	return &APIError{
+		Platform:   platform,
+		Operation:  operation,
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s failed with status %d: %s", e.Operation, e.StatusCode, e.Body)
+}
+
+// IsRateLimited reports whether err is, or wraps, a platform refusing a request with
+// 429 Too Many Requests
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	err := fmt.Errorf("%w. DID used: %s", newAPIError("bluesky", "delete request", 401, []byte(`{"error":"ExpiredToken"}`)), "did:plc:abc")
+
+	want := `delete request failed with status 401: {"error":"ExpiredToken"}. DID used: did:plc:abc`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("Expected to unwrap an APIError")
+	}
+	if apiErr.Platform != "bluesky" || apiErr.StatusCode != 401 {
+		t.Errorf("Unexpected APIError %+v", apiErr)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"429", newAPIError("mastodon", "API request", 429, nil), true},
+		{"wrapped 429", fmt.Errorf("delete failed: %w", newAPIError("bluesky", "delete request", 429, nil)), true},
+		{"other status", newAPIError("mastodon", "API request", 500, nil), false},
+		{"not an API error", errors.New("connection reset"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimited(tt.err); got != tt.want {
+				t.Errorf("IsRateLimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package internal
+
+// NewGoToSocialClient creates a client for GoToSocial, a lightweight ActivityPub server
+// that implements most of the Mastodon client API. It differs in where it lists its
+// rules and in not linking every page of statuses to the next, which mastodonFlavors
+// records; everything else goes through the Mastodon client as is.
+func NewGoToSocialClient() *MastodonClient {
+	client := NewMastodonClient()
+	client.platform = "gotosocial"
+	client.deleteLimit = newRateTracker(mastodonFlavors["gotosocial"].deleteLimit)
+	return client
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMastodonClient_NextPageCursor(t *testing.T) {
+	statuses := []mastodonStatus{{ID: "01J2"}, {ID: "01J1"}}
+	linked := http.Header{"Link": {`<https://example.social/api/v1/accounts/1/statuses?max_id=99>; rel="next"`}}
+
+	tests := []struct {
+		name     string
+		client   *MastodonClient
+		header   http.Header
+		statuses []mastodonStatus
+		want     string
+	}{
+		{"mastodon follows the link", NewMastodonClient(), linked, statuses, "99"},
+		{"mastodon stops without a link", NewMastodonClient(), http.Header{}, statuses, ""},
+		{"gotosocial follows the link", NewGoToSocialClient(), linked, statuses, "99"},
+		{"gotosocial pages from the last status", NewGoToSocialClient(), http.Header{}, statuses, "01J1"},
+		{"gotosocial stops on an empty page", NewGoToSocialClient(), http.Header{}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.client.nextPageCursor(tt.header, tt.statuses); got != tt.want {
+				t.Errorf("nextPageCursor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsFromEnv_GoToSocial(t *testing.T) {
+	t.Setenv("MASTODON_USER", "")
+	t.Setenv("GOTOSOCIAL_USER", "alice@gts.example.org")
+	t.Setenv("GOTOSOCIAL_INSTANCE", "https://gts.example.org")
+	t.Setenv("GOTOSOCIAL_ACCESS_TOKEN", "token")
+
+	creds := GetCredentialsFromEnv("gotosocial")
+	if creds == nil {
+		t.Fatal("Expected credentials from the GOTOSOCIAL_ variables")
+	}
+	if creds.Platform != "gotosocial" || creds.Instance != "https://gts.example.org" || creds.AccessToken != "token" {
+		t.Errorf("Unexpected credentials %+v", creds)
+	}
+	if err := ValidateCredentials(creds); err != nil {
+		t.Errorf("ValidateCredentials() error = %v", err)
+	}
+	if GetCredentialsFromEnv("mastodon") != nil {
+		t.Error("GoToSocial variables shouldn't be read as Mastodon credentials")
+	}
+}
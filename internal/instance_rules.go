@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// automationKeywords flag rules that are likely to restrict tools like cringesweeper
+var automationKeywords = []string{"automat", "bot", "bulk", "script", "tool", "api", "mass"}
+
+// InstanceRules are the usage rules and terms an instance publishes
+type InstanceRules struct {
+	Platform string
+	Instance string   // Host the rules apply to, e.g. mastodon.social
+	Rules    []string // Rule text in the order the instance lists it
+	TermsURL string   // Link to the full terms of service, if published
+}
+
+// AutomationRules returns the rules that mention automation or bulk actions
+func (r *InstanceRules) AutomationRules() []string {
+	var matched []string
+	for _, rule := range r.Rules {
+		lower := strings.ToLower(rule)
+		for _, keyword := range automationKeywords {
+			if strings.Contains(lower, keyword) {
+				matched = append(matched, rule)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Digest identifies this version of the rules, so a change prompts for acknowledgement again
+func (r *InstanceRules) Digest() string {
+	h := sha256.New()
+	for _, rule := range r.Rules {
+		h.Write([]byte(rule))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte(r.TermsURL))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Acknowledgement records that an operator reviewed an instance's rules before pruning
+type Acknowledgement struct {
+	Platform       string    `json:"platform"`
+	Instance       string    `json:"instance"`
+	RulesDigest    string    `json:"rules_digest"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+	Operator       string    `json:"operator,omitempty"`
+}
+
+// AcknowledgementStore persists instance rule acknowledgements as a single JSON file
+type AcknowledgementStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAcknowledgementStore creates a store at ~/.config/cringesweeper/acknowledgements.json
+func NewAcknowledgementStore() (*AcknowledgementStore, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return NewAcknowledgementStoreAt(filepath.Join(homeDir, ".config", "cringesweeper", "acknowledgements.json")), nil
+}
+
+// NewAcknowledgementStoreAt creates a store backed by the given file
+func NewAcknowledgementStoreAt(path string) *AcknowledgementStore {
+	return &AcknowledgementStore{path: path}
+}
+
+func acknowledgementKey(platform, instance string) string {
+	return strings.ToLower(platform) + "/" + strings.ToLower(instance)
+}
+
+// IsAcknowledged returns true if these exact rules have been acknowledged before
+func (s *AcknowledgementStore) IsAcknowledged(rules *InstanceRules) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acks, err := s.loadLocked()
+	if err != nil {
+		return false, err
+	}
+	ack, ok := acks[acknowledgementKey(rules.Platform, rules.Instance)]
+	return ok && ack.RulesDigest == rules.Digest(), nil
+}
+
+// Acknowledge records that operator has reviewed the rules
+func (s *AcknowledgementStore) Acknowledge(rules *InstanceRules, operator string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acks, err := s.loadLocked()
+	if err != nil {
+		return err
+	}
+	acks[acknowledgementKey(rules.Platform, rules.Instance)] = Acknowledgement{
+		Platform:       rules.Platform,
+		Instance:       rules.Instance,
+		RulesDigest:    rules.Digest(),
+		AcknowledgedAt: at.UTC(),
+		Operator:       operator,
+	}
+
+	data, err := json.MarshalIndent(acks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal acknowledgements: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write acknowledgements file: %w", err)
+	}
+	return nil
+}
+
+func (s *AcknowledgementStore) loadLocked() (map[string]Acknowledgement, error) {
+	acks := make(map[string]Acknowledgement)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return acks, nil
+		}
+		return nil, fmt.Errorf("failed to read acknowledgements file: %w", err)
+	}
+	if err := json.Unmarshal(data, &acks); err != nil {
+		return nil, fmt.Errorf("failed to parse acknowledgements file: %w", err)
+	}
+	return acks, nil
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInstanceRules_AutomationRules(t *testing.T) {
+	rules := &InstanceRules{Rules: []string{
+		"Be excellent to each other",
+		"No automated posting without a Bot flag",
+		"Bulk deletion tools must respect rate limits",
+		"No spam",
+	}}
+
+	got := rules.AutomationRules()
+	if len(got) != 2 || got[0] != rules.Rules[1] || got[1] != rules.Rules[2] {
+		t.Errorf("Expected the two automation rules, got %v", got)
+	}
+}
+
+func TestInstanceRules_Digest(t *testing.T) {
+	a := &InstanceRules{Rules: []string{"one", "two"}, TermsURL: "https://example.social/about"}
+	b := &InstanceRules{Rules: []string{"one", "two"}, TermsURL: "https://example.social/about"}
+	if a.Digest() != b.Digest() {
+		t.Error("Identical rules should have the same digest")
+	}
+
+	// Rule boundaries matter, not just the concatenated text
+	c := &InstanceRules{Rules: []string{"on", "etwo"}, TermsURL: "https://example.social/about"}
+	if a.Digest() == c.Digest() {
+		t.Error("Different rules should have different digests")
+	}
+}
+
+func TestAcknowledgementStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "acknowledgements.json")
+	store := NewAcknowledgementStoreAt(path)
+	rules := &InstanceRules{Platform: "mastodon", Instance: "Example.Social", Rules: []string{"Be nice"}}
+
+	// A missing file means nothing has been acknowledged yet
+	if ok, err := store.IsAcknowledged(rules); err != nil || ok {
+		t.Fatalf("Expected unacknowledged with no error, got %v, %v", ok, err)
+	}
+
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Acknowledge(rules, "tester", at); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+
+	// A fresh store reads the acknowledgement back, matching the instance case-insensitively
+	reloaded := NewAcknowledgementStoreAt(path)
+	sameInstance := &InstanceRules{Platform: "mastodon", Instance: "example.social", Rules: []string{"Be nice"}}
+	if ok, err := reloaded.IsAcknowledged(sameInstance); err != nil || !ok {
+		t.Errorf("Expected acknowledged, got %v, %v", ok, err)
+	}
+
+	// Changed rules need acknowledging again
+	changed := &InstanceRules{Platform: "mastodon", Instance: "example.social", Rules: []string{"Be nice", "No bots"}}
+	if ok, _ := reloaded.IsAcknowledged(changed); ok {
+		t.Error("Expected changed rules to need acknowledgement")
+	}
+
+	// Other instances are unaffected
+	other := &InstanceRules{Platform: "mastodon", Instance: "other.social", Rules: []string{"Be nice"}}
+	if ok, _ := reloaded.IsAcknowledged(other); ok {
+		t.Error("Expected other instance to need acknowledgement")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected acknowledgements file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+}
+
+func TestAcknowledgementStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acknowledgements.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewAcknowledgementStoreAt(path)
+	if _, err := store.IsAcknowledged(&InstanceRules{Platform: "mastodon", Instance: "example.social"}); err == nil {
+		t.Error("Expected error for corrupt acknowledgements file")
+	}
+}
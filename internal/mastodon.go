@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"html"
@@ -8,28 +10,71 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// MastodonClient implements the SocialClient interface for Mastodon
+// replyAuthorConcurrency limits how many account lookups run at once when resolving reply authors
+const replyAuthorConcurrency = 4
+
+// mastodonFlavor records where a server that speaks the Mastodon client API departs
+// from Mastodon itself
+type mastodonFlavor struct {
+	name         string     // Display name
+	rulesInIndex bool       // Lists its rules in /api/v1/instance rather than /api/v1/instance/rules
+	linkPaging   bool       // Links every page of statuses to the next; otherwise pages follow on from the last status
+	deleteLimit  RateWindow // How many statuses can be deleted in a window, beyond the general request limit
+}
+
+// mastodonFlavors are the servers the Mastodon client works with, by platform name
+var mastodonFlavors = map[string]mastodonFlavor{
+	"mastodon":   {name: "Mastodon", linkPaging: true, deleteLimit: RateWindow{Limit: 30, Window: 30 * time.Minute}},
+	"gotosocial": {name: "GoToSocial", rulesInIndex: true},
+}
+
+// MastodonClient implements the SocialClient interface for Mastodon, and for servers
+// that implement enough of its API, such as GoToSocial
 type MastodonClient struct {
+	platform            string // Key into mastodonFlavors, used for credentials, tombstones and logs
 	sessionManager      *SessionManager
 	authenticatedClient *AuthenticatedHTTPClient
 	instanceURL         string
+	clock               Clock
+	deleteLimit         *rateTracker // Status deletions made, kept for the life of the client so they span runs
+
+	// Accounts already looked up, keyed by instance URL and account ID
+	accountCache   map[string]*mastodonAccount
+	accountCacheMu sync.Mutex
 }
 
 // NewMastodonClient creates a new Mastodon client
 func NewMastodonClient() *MastodonClient {
 	return &MastodonClient{
+		platform:       "mastodon",
 		sessionManager: NewSessionManager("mastodon"),
+		clock:          SystemClock,
+		deleteLimit:    newRateTracker(mastodonFlavors["mastodon"].deleteLimit),
+		accountCache:   make(map[string]*mastodonAccount),
 	}
 }
 
+// SetClock replaces the client's time source, used by tests to simulate time passing
+func (c *MastodonClient) SetClock(clock Clock) {
+	c.clock = clock
+	c.sessionManager.clock = clock
+}
+
 // GetPlatformName returns the platform name
 func (c *MastodonClient) GetPlatformName() string {
-	return "Mastodon"
+	return c.flavor().name
+}
+
+// flavor returns how the client's server differs from Mastodon
+func (c *MastodonClient) flavor() mastodonFlavor {
+	return mastodonFlavors[c.platform]
 }
 
 // RequiresAuth returns true if the platform requires authentication for deletion
@@ -38,27 +83,27 @@ func (c *MastodonClient) RequiresAuth() bool {
 }
 
 // FetchUserPosts retrieves recent posts for a Mastodon user
-func (c *MastodonClient) FetchUserPosts(username string, limit int) ([]Post, error) {
+func (c *MastodonClient) FetchUserPosts(ctx context.Context, username string, limit int) ([]Post, error) {
 	instanceURL, acct, err := c.parseUsername(username)
 	if err != nil {
 		return nil, fmt.Errorf("invalid username format: %w", err)
 	}
 
 	// First, get the account ID
-	accountID, err := c.getAccountID(instanceURL, acct)
+	accountID, err := c.getAccountID(ctx, instanceURL, acct)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account ID: %w", err)
 	}
 
 	// Check if we have authentication for enhanced data
 	var statuses []mastodonStatus
-	creds, authErr := GetCredentialsForPlatform("mastodon")
+	creds, authErr := GetCredentialsForPlatform(c.platform)
 	if authErr == nil && ValidateCredentials(creds) == nil {
 		// Use authenticated fetch for viewer interaction data
-		statuses, err = c.fetchUserStatusesAuthenticated(instanceURL, accountID, limit, creds)
+		statuses, err = c.fetchUserStatusesAuthenticated(ctx, instanceURL, accountID, limit, creds)
 	} else {
 		// Use public fetch without viewer data
-		statuses, err = c.fetchUserStatuses(instanceURL, accountID, limit)
+		statuses, err = c.fetchUserStatuses(ctx, instanceURL, accountID, limit)
 		creds = nil // Ensure no credentials used for public fetch
 	}
 
@@ -66,82 +111,127 @@ func (c *MastodonClient) FetchUserPosts(username string, limit int) ([]Post, err
 		return nil, fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 
+	replyAuthors := c.resolveReplyAuthors(ctx, instanceURL, statuses, creds)
+
 	// Convert to generic Post format
 	var posts []Post
 	for _, status := range statuses {
-		post := Post{
-			ID:        status.ID,
-			Author:    status.Account.DisplayName,
-			Handle:    status.Account.Acct,
-			Content:   c.stripHTML(status.Content),
-			CreatedAt: status.CreatedAt,
-			URL:       status.URL,
-			Type:      c.determinePostType(status),
-			Platform:  "mastodon",
-
-			// Engagement metrics
-			RepostCount: status.ReblogsCount,
-			LikeCount:   status.FavouritesCount,
-			ReplyCount:  status.RepliesCount,
-
-			// Viewer interaction status
-			IsLikedByUser: status.Favourited != nil && *status.Favourited,
-			IsPinned:      status.Pinned != nil && *status.Pinned,
-		}
-
-		// Handle reblogs/reposts
-		if status.Reblog != nil {
-			post.Type = PostTypeRepost
-			// IMPORTANT: For reblogs, post.ID should be the reblog status ID (for unrebogging)
-			// status.ID is the reblog action ID, status.Reblog.ID is the original post ID
-			post.ID = status.ID // This is the reblog action ID we need to unreblog
-			post.OriginalAuthor = status.Reblog.Account.DisplayName
-			post.OriginalHandle = status.Reblog.Account.Acct
-			post.Content = c.stripHTML(status.Reblog.Content)
-			// Create embedded original post
-			post.OriginalPost = &Post{
-				ID:        status.Reblog.ID, // Original post ID
-				Author:    status.Reblog.Account.DisplayName,
-				Handle:    status.Reblog.Account.Acct,
-				Content:   c.stripHTML(status.Reblog.Content),
-				CreatedAt: status.Reblog.CreatedAt,
-				URL:       status.Reblog.URL,
-				Type:      PostTypeOriginal,
-				Platform:  "mastodon",
-			}
+		posts = append(posts, c.statusPost(status, replyAuthors))
+	}
+
+	return posts, nil
+}
+
+// statusPost converts a status to the generic Post format. replyAuthors maps account IDs
+// to the handles of the accounts replied to, and may be nil.
+func (c *MastodonClient) statusPost(status mastodonStatus, replyAuthors map[string]string) Post {
+	post := Post{
+		ID:        status.ID,
+		Author:    status.Account.DisplayName,
+		Handle:    status.Account.Acct,
+		Content:   c.stripHTML(status.Content),
+		CreatedAt: status.CreatedAt,
+		URL:       status.URL,
+		Type:      c.determinePostType(status),
+		Platform:  c.platform,
+
+		// Engagement metrics
+		RepostCount: status.ReblogsCount,
+		LikeCount:   status.FavouritesCount,
+		ReplyCount:  status.RepliesCount,
+
+		// Viewer interaction status
+		IsLikedByUser: status.Favourited != nil && *status.Favourited,
+		IsPinned:      status.Pinned != nil && *status.Pinned,
+
+		Hashtags:    mastodonHashtags(status.Tags),
+		Languages:   mastodonLanguages(status.Language),
+		Attachments: mastodonAttachments(status.MediaAttachments),
+		Links:       mastodonLinks(status.Content),
+
+		ContentWarning: status.SpoilerText,
+		Sensitive:      status.Sensitive,
+		Visibility:     status.Visibility,
+	}
+
+	// Handle reblogs/reposts
+	if status.Reblog != nil {
+		post.Type = PostTypeRepost
+		// IMPORTANT: For reblogs, post.ID should be the reblog status ID (for unrebogging)
+		// status.ID is the reblog action ID, status.Reblog.ID is the original post ID
+		post.ID = status.ID // This is the reblog action ID we need to unreblog
+		post.OriginalAuthor = status.Reblog.Account.DisplayName
+		post.OriginalHandle = status.Reblog.Account.Acct
+		post.SelfRepost = status.Reblog.Account.ID == status.Account.ID
+		post.Content = c.stripHTML(status.Reblog.Content)
+		post.Links = mastodonLinks(status.Reblog.Content)
+		post.Languages = mastodonLanguages(status.Reblog.Language)
+		post.ContentWarning = status.Reblog.SpoilerText
+		post.Sensitive = status.Reblog.Sensitive
+		// Create embedded original post
+		post.OriginalPost = &Post{
+			ID:        status.Reblog.ID, // Original post ID
+			Author:    status.Reblog.Account.DisplayName,
+			Handle:    status.Reblog.Account.Acct,
+			Content:   c.stripHTML(status.Reblog.Content),
+			CreatedAt: status.Reblog.CreatedAt,
+			URL:       status.Reblog.URL,
+			Type:      PostTypeOriginal,
+			Platform:  c.platform,
 		}
+	}
 
-		// Handle replies
-		if status.InReplyToID != nil {
-			post.Type = PostTypeReply
-			post.InReplyToID = *status.InReplyToID
-			if status.InReplyToAccountID != nil {
-				// Fetch reply author information
-				if replyAccount, err := c.fetchAccountInfo(instanceURL, *status.InReplyToAccountID, creds); err == nil {
-					post.InReplyToAuthor = replyAccount.DisplayName
-					if post.InReplyToAuthor == "" {
-						post.InReplyToAuthor = replyAccount.Acct
-					}
-				}
-				// Continue silently if account fetch fails to avoid disrupting the main operation
-			}
+	// Handle replies
+	if status.InReplyToID != nil {
+		post.Type = PostTypeReply
+		post.InReplyToID = *status.InReplyToID
+		if status.InReplyToAccountID != nil {
+			// Left blank if the lookup failed, to avoid disrupting the main operation
+			post.InReplyToAuthor = replyAuthors[*status.InReplyToAccountID]
+			post.SelfReply = *status.InReplyToAccountID == status.Account.ID
 		}
+	}
 
-		posts = append(posts, post)
+	// Handle quotes, unless they're replies, which are thread posts first
+	if quoted := status.Quote.quotedID(); quoted != "" && post.Type == PostTypeOriginal {
+		post.Type = PostTypeQuote
+		post.OriginalPost = &Post{ID: quoted, Type: PostTypeOriginal, Platform: c.platform}
+		if original := status.Quote.QuotedStatus; original != nil {
+			post.OriginalAuthor = original.Account.DisplayName
+			post.OriginalHandle = original.Account.Acct
+			post.SelfQuote = original.Account.ID == status.Account.ID
+			post.OriginalPost = &Post{
+				ID:        original.ID,
+				Author:    original.Account.DisplayName,
+				Handle:    original.Account.Acct,
+				Content:   c.stripHTML(original.Content),
+				CreatedAt: original.CreatedAt,
+				URL:       original.URL,
+				Type:      PostTypeOriginal,
+				Platform:  c.platform,
+			}
+		}
 	}
 
-	return posts, nil
+	return post
 }
 
-// FetchUserPostsPaginated retrieves posts with pagination support using max_id
-func (c *MastodonClient) FetchUserPostsPaginated(username string, limit int, cursor string) ([]Post, string, error) {
+// FetchUserPostsPaginated retrieves posts with pagination support. The cursor is the
+// max_id of the next page, taken from the response's Link header.
+func (c *MastodonClient) FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]Post, string, error) {
+	return c.fetchPostsPage(ctx, username, limit, cursor, FetchProfileDisplay)
+}
+
+// fetchPostsPage fetches one page of statuses. Reply author names are only resolved for
+// the display profile, as prune decisions never look at them.
+func (c *MastodonClient) fetchPostsPage(ctx context.Context, username string, limit int, cursor string, profile FetchProfile) ([]Post, string, error) {
 	instanceURL, acct, err := c.parseUsername(username)
 	if err != nil {
 		return nil, "", fmt.Errorf("invalid username format: %w", err)
 	}
 
 	// First, get the account ID
-	accountID, err := c.getAccountID(instanceURL, acct)
+	accountID, err := c.getAccountID(ctx, instanceURL, acct)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get account ID: %w", err)
 	}
@@ -149,13 +239,13 @@ func (c *MastodonClient) FetchUserPostsPaginated(username string, limit int, cur
 	// Check if we have authentication for enhanced data
 	var statuses []mastodonStatus
 	var nextCursor string
-	creds, authErr := GetCredentialsForPlatform("mastodon")
+	creds, authErr := GetCredentialsForPlatform(c.platform)
 	if authErr == nil && ValidateCredentials(creds) == nil {
 		// Use authenticated fetch for viewer interaction data
-		statuses, nextCursor, err = c.fetchUserStatusesPaginated(instanceURL, accountID, limit, cursor, creds)
+		statuses, nextCursor, err = c.fetchUserStatusesPaginated(ctx, instanceURL, accountID, limit, cursor, creds)
 	} else {
 		// Use public fetch without viewer data
-		statuses, nextCursor, err = c.fetchUserStatusesPaginatedPublic(instanceURL, accountID, limit, cursor)
+		statuses, nextCursor, err = c.fetchUserStatusesPaginatedPublic(ctx, instanceURL, accountID, limit, cursor)
 		creds = nil // Ensure no credentials used for public fetch
 	}
 
@@ -163,74 +253,21 @@ func (c *MastodonClient) FetchUserPostsPaginated(username string, limit int, cur
 		return nil, "", fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 
-	// Convert to generic Post format (same logic as FetchUserPosts)
+	var replyAuthors map[string]string
+	if profile == FetchProfileDisplay {
+		replyAuthors = c.resolveReplyAuthors(ctx, instanceURL, statuses, creds)
+	}
+
+	// Convert to generic Post format
 	var posts []Post
 	for _, status := range statuses {
-		post := Post{
-			ID:        status.ID,
-			Author:    status.Account.DisplayName,
-			Handle:    status.Account.Acct,
-			Content:   c.stripHTML(status.Content),
-			CreatedAt: status.CreatedAt,
-			URL:       status.URL,
-			Type:      c.determinePostType(status),
-			Platform:  "mastodon",
-
-			// Engagement metrics
-			RepostCount: status.ReblogsCount,
-			LikeCount:   status.FavouritesCount,
-			ReplyCount:  status.RepliesCount,
-
-			// Viewer interaction status
-			IsLikedByUser: status.Favourited != nil && *status.Favourited,
-			IsPinned:      status.Pinned != nil && *status.Pinned,
-		}
-
-		// Handle reblogs/reposts
-		if status.Reblog != nil {
-			post.Type = PostTypeRepost
-			// IMPORTANT: For reblogs, post.ID should be the reblog status ID (for unrebogging)
-			// status.ID is the reblog action ID, status.Reblog.ID is the original post ID
-			post.ID = status.ID // This is the reblog action ID we need to unreblog
-			post.OriginalAuthor = status.Reblog.Account.DisplayName
-			post.OriginalHandle = status.Reblog.Account.Acct
-			post.Content = c.stripHTML(status.Reblog.Content)
-			// Create embedded original post
-			post.OriginalPost = &Post{
-				ID:        status.Reblog.ID, // Original post ID
-				Author:    status.Reblog.Account.DisplayName,
-				Handle:    status.Reblog.Account.Acct,
-				Content:   c.stripHTML(status.Reblog.Content),
-				CreatedAt: status.Reblog.CreatedAt,
-				URL:       status.Reblog.URL,
-				Type:      PostTypeOriginal,
-				Platform:  "mastodon",
-			}
-		}
-
-		// Handle replies
-		if status.InReplyToID != nil {
-			post.Type = PostTypeReply
-			post.InReplyToID = *status.InReplyToID
-			if status.InReplyToAccountID != nil {
-				// Fetch reply author information
-				if replyAccount, err := c.fetchAccountInfo(instanceURL, *status.InReplyToAccountID, creds); err == nil {
-					post.InReplyToAuthor = replyAccount.DisplayName
-					if post.InReplyToAuthor == "" {
-						post.InReplyToAuthor = replyAccount.Acct
-					}
-				}
-				// Continue silently if account fetch fails to avoid disrupting the main operation
-			}
-		}
-
-		posts = append(posts, post)
+		posts = append(posts, c.statusPost(status, replyAuthors))
 	}
 
 	return posts, nextCursor, nil
 }
 
-func (c *MastodonClient) fetchUserStatusesPaginatedPublic(instanceURL, accountID string, limit int, maxID string) ([]mastodonStatus, string, error) {
+func (c *MastodonClient) fetchUserStatusesPaginatedPublic(ctx context.Context, instanceURL, accountID string, limit int, maxID string) ([]mastodonStatus, string, error) {
 	statusesURL := fmt.Sprintf("%s/api/v1/accounts/%s/statuses", instanceURL, accountID)
 
 	params := url.Values{}
@@ -245,18 +282,15 @@ func (c *MastodonClient) fetchUserStatusesPaginatedPublic(instanceURL, accountID
 
 	fullURL := fmt.Sprintf("%s?%s", statusesURL, params.Encode())
 
-	LogHTTPRequest("GET", fullURL)
-	resp, err := http.Get(fullURL)
+	resp, err := httpGetWithRetry(ctx, fullURL)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("statuses request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, "", newAPIError(c.platform, "statuses request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -269,17 +303,10 @@ func (c *MastodonClient) fetchUserStatusesPaginatedPublic(instanceURL, accountID
 		return nil, "", fmt.Errorf("failed to parse statuses response: %w", err)
 	}
 
-	// Determine next cursor from Link header or last status ID
-	nextCursor := ""
-	if len(statuses) > 0 {
-		// Use the ID of the last status as the max_id for the next request
-		nextCursor = statuses[len(statuses)-1].ID
-	}
-
-	return statuses, nextCursor, nil
+	return statuses, c.nextPageCursor(resp.Header, statuses), nil
 }
 
-func (c *MastodonClient) fetchUserStatusesPaginated(instanceURL, accountID string, limit int, maxID string, creds *Credentials) ([]mastodonStatus, string, error) {
+func (c *MastodonClient) fetchUserStatusesPaginated(ctx context.Context, instanceURL, accountID string, limit int, maxID string, creds *Credentials) ([]mastodonStatus, string, error) {
 	statusesURL := fmt.Sprintf("%s/api/v1/accounts/%s/statuses", instanceURL, accountID)
 
 	params := url.Values{}
@@ -294,7 +321,7 @@ func (c *MastodonClient) fetchUserStatusesPaginated(instanceURL, accountID strin
 
 	fullURL := fmt.Sprintf("%s?%s", statusesURL, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -302,19 +329,16 @@ func (c *MastodonClient) fetchUserStatusesPaginated(instanceURL, accountID strin
 	// Add authentication header
 	req.Header.Set("Authorization", "Bearer "+creds.AccessToken)
 
-	LogHTTPRequest("GET", fullURL)
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	client := sharedHTTPClient()
+	resp, err := doRequest(client, req)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("statuses request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, "", newAPIError(c.platform, "statuses request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -327,14 +351,70 @@ func (c *MastodonClient) fetchUserStatusesPaginated(instanceURL, accountID strin
 		return nil, "", fmt.Errorf("failed to parse statuses response: %w", err)
 	}
 
-	// Determine next cursor from last status ID
-	nextCursor := ""
-	if len(statuses) > 0 {
-		// Use the ID of the last status as the max_id for the next request
-		nextCursor = statuses[len(statuses)-1].ID
+	return statuses, c.nextPageCursor(resp.Header, statuses), nil
+}
+
+// parseLinkHeader returns the URLs in an RFC 5988 Link header by relation type.
+// Mastodon uses it to point at the next and previous pages of a timeline:
+//
+//	<https://example.social/api/v1/favourites?max_id=42>; rel="next", <...?min_id=57>; rel="prev"
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = target[1 : len(target)-1]
+		for _, param := range parts[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(name)) != "rel" {
+				continue
+			}
+			// A rel can hold several space-separated relation types
+			for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+				links[strings.ToLower(rel)] = target
+			}
+		}
+	}
+	return links
+}
+
+// nextPageMaxID returns the max_id of the next page linked from a response, or an empty
+// string on the last page. Statuses and favourites are paged by IDs that aren't always
+// the IDs of the statuses returned, so only the server can say where the next page starts.
+func nextPageMaxID(header http.Header) string {
+	next, ok := parseLinkHeader(header.Get("Link"))["next"]
+	if !ok {
+		return ""
+	}
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return ""
+	}
+	return nextURL.Query().Get("max_id")
+}
+
+// nextPageURL returns the URL of the page after a response from instanceURL, or false on
+// the last page. Our token goes with every request, so a link off the instance isn't
+// followed, nor one back to a page in seen.
+func nextPageURL(header http.Header, instanceURL string, seen map[string]bool) (string, bool) {
+	next, ok := parseLinkHeader(header.Get("Link"))["next"]
+	if !ok || seen[next] || !strings.HasPrefix(next, instanceURL+"/") {
+		return "", false
 	}
+	return next, true
+}
 
-	return statuses, nextCursor, nil
+// nextPageCursor returns the max_id of the page after statuses, or an empty string after
+// the last page. Servers that don't link every page to the next are paged from the last
+// status returned instead, until a page comes back empty.
+func (c *MastodonClient) nextPageCursor(header http.Header, statuses []mastodonStatus) string {
+	if next := nextPageMaxID(header); next != "" || c.flavor().linkPaging || len(statuses) == 0 {
+		return next
+	}
+	return statuses[len(statuses)-1].ID
 }
 
 // parseUsername extracts instance URL and account from username
@@ -356,12 +436,45 @@ func (c *MastodonClient) parseUsername(username string) (instanceURL, acct strin
 	return instanceURL, acct, nil
 }
 
+// isOwnAccount returns true if username refers to the account the credentials belong to.
+// Credentials saved without an instance in the username fall back to creds.Instance.
+func (c *MastodonClient) isOwnAccount(username string, creds *Credentials) bool {
+	if strings.EqualFold(username, creds.Username) {
+		return true
+	}
+
+	targetInstance, targetAcct, err := c.parseUsername(username)
+	if err != nil {
+		return false
+	}
+
+	ownInstance, ownAcct, err := c.parseUsername(creds.Username)
+	if err != nil {
+		return false
+	}
+	if !strings.Contains(creds.Username, "@") && creds.Instance != "" {
+		ownInstance = creds.Instance
+	}
+
+	return strings.EqualFold(targetAcct, ownAcct) && strings.EqualFold(instanceHost(targetInstance), instanceHost(ownInstance))
+}
+
+// instanceHost reduces an instance URL to its host so "https://x.social/" and "x.social" compare equal
+func instanceHost(instanceURL string) string {
+	if parsed, err := url.Parse(instanceURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return strings.TrimSuffix(instanceURL, "/")
+}
+
 // Mastodon API types
 type mastodonAccount struct {
-	ID          string `json:"id"`
-	Username    string `json:"username"`
-	Acct        string `json:"acct"`
-	DisplayName string `json:"display_name"`
+	ID            string    `json:"id"`
+	Username      string    `json:"username"`
+	Acct          string    `json:"acct"`
+	DisplayName   string    `json:"display_name"`
+	StatusesCount int       `json:"statuses_count"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type mastodonStatus struct {
@@ -376,6 +489,13 @@ type mastodonStatus struct {
 	ReblogsCount       int             `json:"reblogs_count"`
 	FavouritesCount    int             `json:"favourites_count"`
 	RepliesCount       int             `json:"replies_count"`
+	Tags               []mastodonTag   `json:"tags"`
+	Language           *string         `json:"language"`     // ISO 639 code, or null if not set
+	SpoilerText        string          `json:"spoiler_text"` // Content warning, empty if none
+	Sensitive          bool            `json:"sensitive"`
+	Visibility         string          `json:"visibility"` // public, unlisted, private or direct
+	MediaAttachments   []mastodonMedia `json:"media_attachments"`
+	Quote              *mastodonQuote  `json:"quote"` // Set on quote posts, from Mastodon 4.4
 
 	// Viewer interaction fields
 	Favourited *bool `json:"favourited,omitempty"` // Whether the authenticated user has favorited this status
@@ -383,8 +503,213 @@ type mastodonStatus struct {
 	Pinned     *bool `json:"pinned,omitempty"`     // Whether this is a pinned status
 }
 
+// mastodonQuote is the post a status quotes. Quotes the quoted author hasn't approved, or
+// has since revoked, are in a state other than accepted and show nothing. The quoted
+// status is left out when it's nested too deeply, leaving only its ID.
+type mastodonQuote struct {
+	State          string          `json:"state"`
+	QuotedStatus   *mastodonStatus `json:"quoted_status"`
+	QuotedStatusID string          `json:"quoted_status_id"`
+}
+
+// quotedID returns the ID of the status an accepted quote points at, or ""
+func (q *mastodonQuote) quotedID() string {
+	switch {
+	case q == nil || q.State != "accepted":
+		return ""
+	case q.QuotedStatus != nil:
+		return q.QuotedStatus.ID
+	default:
+		return q.QuotedStatusID
+	}
+}
+
+// mastodonLanguages converts a status's language into the post's languages
+func mastodonLanguages(language *string) []string {
+	if language == nil || *language == "" {
+		return nil
+	}
+	return []string{*language}
+}
+
+// mastodonTag is a hashtag attached to a status
+type mastodonTag struct {
+	Name string `json:"name"` // Tag name without the leading '#'
+	URL  string `json:"url"`
+}
+
+// mastodonMedia is a media attachment on a status
+type mastodonMedia struct {
+	ID          string  `json:"id"`
+	Type        string  `json:"type"` // image, gifv, video, audio or unknown
+	URL         string  `json:"url"`
+	Description *string `json:"description"`
+}
+
+// mastodonAttachments converts a status's media attachments into generic attachments
+func mastodonAttachments(media []mastodonMedia) []Attachment {
+	var attachments []Attachment
+	for _, item := range media {
+		attachment := Attachment{Type: item.Type, URL: item.URL}
+		if item.Description != nil {
+			attachment.AltText = *item.Description
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments
+}
+
+var (
+	mastodonLinkTag   = regexp.MustCompile(`<a\s[^>]*>`)
+	mastodonLinkHref  = regexp.MustCompile(`\shref="([^"]*)"`)
+	mastodonLinkClass = regexp.MustCompile(`\s(?:class|rel)="([^"]*)"`)
+)
+
+// mastodonLinks returns the web links in a status's HTML, without duplicates. Mentions
+// and hashtags are links too, to profiles and tag pages, so they're left out.
+func mastodonLinks(content string) []string {
+	var links []string
+	for _, tag := range mastodonLinkTag.FindAllString(content, -1) {
+		href := mastodonLinkHref.FindStringSubmatch(tag)
+		if href == nil {
+			continue
+		}
+		isMention := false
+		for _, attr := range mastodonLinkClass.FindAllStringSubmatch(tag, -1) {
+			for _, word := range strings.Fields(attr[1]) {
+				if word == "mention" || word == "hashtag" || word == "tag" {
+					isMention = true
+				}
+			}
+		}
+		link := html.UnescapeString(href[1])
+		if isMention || LinkDomain(link) == "" || slices.Contains(links, link) {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// mastodonHashtags returns the names of a status's tags
+func mastodonHashtags(tags []mastodonTag) []string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
 // getAccountID looks up account ID by username
-func (c *MastodonClient) getAccountID(instanceURL, acct string) (string, error) {
+func (c *MastodonClient) getAccountID(ctx context.Context, instanceURL, acct string) (string, error) {
+	account, err := c.lookupAccount(ctx, instanceURL, acct)
+	if err != nil {
+		return "", err
+	}
+	return account.ID, nil
+}
+
+// GetPostCount returns the statuses_count Mastodon reports for the account
+func (c *MastodonClient) GetPostCount(ctx context.Context, username string) (int, error) {
+	instanceURL, acct, err := c.parseUsername(username)
+	if err != nil {
+		return 0, fmt.Errorf("invalid username format: %w", err)
+	}
+
+	account, err := c.lookupAccount(ctx, instanceURL, acct)
+	if err != nil {
+		return 0, err
+	}
+	return account.StatusesCount, nil
+}
+
+// VerifyAccount checks the access token against the instance's verify_credentials endpoint
+func (c *MastodonClient) VerifyAccount(ctx context.Context) (*AccountInfo, error) {
+	creds, err := GetCredentialsForPlatform(c.platform)
+	if err != nil {
+		return nil, fmt.Errorf("authentication required: %w", err)
+	}
+	if err := ValidateCredentials(creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials: %w", err)
+	}
+
+	account, err := verifyMastodonCredentials(ctx, c.platform, creds.Instance, creds.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	username := account.Username + "@" + instanceHost(creds.Instance)
+	return &AccountInfo{
+		ID:                  account.ID,
+		Username:            username,
+		PostCount:           account.StatusesCount,
+		CreatedAt:           account.CreatedAt,
+		CredentialsUsername: creds.Username,
+		MatchesCredentials:  c.isOwnAccount(username, creds),
+	}, nil
+}
+
+// FetchInstanceRules fetches the server rules the username's instance publishes
+func (c *MastodonClient) FetchInstanceRules(ctx context.Context, username string) (*InstanceRules, error) {
+	instanceURL, _, err := c.parseUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username format: %w", err)
+	}
+
+	rulesURL := instanceURL + "/api/v1/instance/rules"
+	if c.flavor().rulesInIndex {
+		rulesURL = instanceURL + "/api/v1/instance"
+	}
+	resp, err := httpGetWithRetry(ctx, rulesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance rules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(c.platform, "instance rules request", resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instance rules response: %w", err)
+	}
+
+	type rule struct {
+		Text string `json:"text"`
+		Hint string `json:"hint"`
+	}
+	var rules []rule
+	if c.flavor().rulesInIndex {
+		var instance struct {
+			Rules []rule `json:"rules"`
+		}
+		err = json.Unmarshal(body, &instance)
+		rules = instance.Rules
+	} else {
+		err = json.Unmarshal(body, &rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse instance rules response: %w", err)
+	}
+
+	result := &InstanceRules{
+		Platform: c.platform,
+		Instance: instanceHost(instanceURL),
+		TermsURL: instanceURL + "/about",
+	}
+	for _, rule := range rules {
+		text := strings.TrimSpace(rule.Text)
+		if hint := strings.TrimSpace(rule.Hint); hint != "" {
+			text += " (" + hint + ")"
+		}
+		result.Rules = append(result.Rules, text)
+	}
+	return result, nil
+}
+
+// lookupAccount fetches the public account record for a username
+func (c *MastodonClient) lookupAccount(ctx context.Context, instanceURL, acct string) (*mastodonAccount, error) {
 	lookupURL := fmt.Sprintf("%s/api/v1/accounts/lookup", instanceURL)
 
 	params := url.Values{}
@@ -392,35 +717,32 @@ func (c *MastodonClient) getAccountID(instanceURL, acct string) (string, error)
 
 	fullURL := fmt.Sprintf("%s?%s", lookupURL, params.Encode())
 
-	LogHTTPRequest("GET", fullURL)
-	resp, err := http.Get(fullURL)
+	resp, err := httpGetWithRetry(ctx, fullURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to lookup account: %w", err)
+		return nil, fmt.Errorf("failed to lookup account: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("account lookup failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(c.platform, "account lookup", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read lookup response: %w", err)
+		return nil, fmt.Errorf("failed to read lookup response: %w", err)
 	}
 
 	var account mastodonAccount
 	if err := json.Unmarshal(body, &account); err != nil {
-		return "", fmt.Errorf("failed to parse account response: %w", err)
+		return nil, fmt.Errorf("failed to parse account response: %w", err)
 	}
 
-	return account.ID, nil
+	return &account, nil
 }
 
 // fetchUserStatuses gets statuses for an account ID
-func (c *MastodonClient) fetchUserStatuses(instanceURL, accountID string, limit int) ([]mastodonStatus, error) {
+func (c *MastodonClient) fetchUserStatuses(ctx context.Context, instanceURL, accountID string, limit int) ([]mastodonStatus, error) {
 	statusesURL := fmt.Sprintf("%s/api/v1/accounts/%s/statuses", instanceURL, accountID)
 
 	params := url.Values{}
@@ -431,18 +753,15 @@ func (c *MastodonClient) fetchUserStatuses(instanceURL, accountID string, limit
 
 	fullURL := fmt.Sprintf("%s?%s", statusesURL, params.Encode())
 
-	LogHTTPRequest("GET", fullURL)
-	resp, err := http.Get(fullURL)
+	resp, err := httpGetWithRetry(ctx, fullURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("statuses request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(c.platform, "statuses request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -459,7 +778,7 @@ func (c *MastodonClient) fetchUserStatuses(instanceURL, accountID string, limit
 }
 
 // fetchUserStatusesAuthenticated gets statuses with viewer interaction data
-func (c *MastodonClient) fetchUserStatusesAuthenticated(instanceURL, accountID string, limit int, creds *Credentials) ([]mastodonStatus, error) {
+func (c *MastodonClient) fetchUserStatusesAuthenticated(ctx context.Context, instanceURL, accountID string, limit int, creds *Credentials) ([]mastodonStatus, error) {
 	statusesURL := fmt.Sprintf("%s/api/v1/accounts/%s/statuses", instanceURL, accountID)
 
 	params := url.Values{}
@@ -470,7 +789,7 @@ func (c *MastodonClient) fetchUserStatusesAuthenticated(instanceURL, accountID s
 
 	fullURL := fmt.Sprintf("%s?%s", statusesURL, params.Encode())
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -478,19 +797,16 @@ func (c *MastodonClient) fetchUserStatusesAuthenticated(instanceURL, accountID s
 	// Add authentication header
 	req.Header.Set("Authorization", "Bearer "+creds.AccessToken)
 
-	LogHTTPRequest("GET", fullURL)
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	client := sharedHTTPClient()
+	resp, err := doRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch statuses: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", fullURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("statuses request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(c.platform, "statuses request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -558,9 +874,9 @@ func (c *MastodonClient) stripHTML(content string) string {
 }
 
 // PrunePosts deletes posts according to specified criteria
-func (c *MastodonClient) PrunePosts(username string, options PruneOptions) (*PruneResult, error) {
+func (c *MastodonClient) PrunePosts(ctx context.Context, username string, options PruneOptions) (*PruneResult, error) {
 	// Get authentication credentials
-	creds, err := GetCredentialsForPlatform("mastodon")
+	creds, err := GetCredentialsForPlatform(c.platform)
 	if err != nil {
 		return nil, fmt.Errorf("authentication required: %w", err)
 	}
@@ -575,13 +891,84 @@ func (c *MastodonClient) PrunePosts(username string, options PruneOptions) (*Pru
 		return nil, fmt.Errorf("invalid username format: %w", err)
 	}
 
-	// Fetch ALL user's posts using pagination to ensure we process posts older than 60 days
+	// Other accounts can be listed, but never pruned. The token is checked too, as it may
+	// belong to another account than the username it was saved with, and deletions would
+	// then only fail one by one once the run was under way.
+	owner, err := verifyMastodonCredentials(ctx, c.platform, creds.Instance, creds.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with %s: %w", c.platform, err)
+	}
+	account := owner.Username + "@" + instanceHost(creds.Instance)
+	own := c.isOwnAccount(username, creds) && c.isOwnAccount(account, creds)
+	mismatch, err := checkOwnAccount(c.platform, username, account, own, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// With --ids-file or --id, only the listed statuses are looked at
+	if len(options.ListedPosts) > 0 {
+		result, err := c.pruneListedPosts(ctx, username, creds, options)
+		result.addMismatchWarning(mismatch)
+		return result, err
+	}
+
+	// With --from-index the posts come from the last sync instead of the timeline
+	var posts []Post
+	if options.FromIndex {
+		posts, err = indexedPrunePosts(c.platform, username)
+	} else {
+		posts, err = c.fetchPruneTimeline(ctx, username, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Notice redrafted statuses before acting, so the local index follows them to their new IDs
+	if ids := DefaultPostIDMap(); ids != nil {
+		c.trackRedrafts(ctx, creds, posts, ids)
+	}
+
+	result := &PruneResult{
+		PostsToDelete:  []Post{},
+		PostsToUnlike:  []Post{},
+		PostsToUnshare: []Post{},
+		PostsPreserved: []Post{},
+		Errors:         []string{},
+	}
+	result.addMismatchWarning(mismatch)
+
+	// If user wants to unlike posts, also fetch their favorited posts
+	if options.UnlikePosts {
+		favourites, err := c.fetchAllFavourites(ctx, instanceURL, creds, options)
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Failed to fetch favorited posts: %v\n", err)
+			result.AddWarning("Failed to fetch favorited posts: %v", err)
+		} else {
+			posts = append(posts, favourites...)
+		}
+	}
+
+	// With --delete-whole-threads, take a thread down leaf-first like Bluesky does
+	if options.DeleteWholeThreads {
+		posts = orderRepliesBeforeParents(posts)
+	}
+
+	actor := &mastodonPruneActor{client: c, creds: creds, deleteMedia: options.DeleteMedia}
+	if err := NewPruneEngine(c.platform, actor, options, c.clock).Run(ctx, posts, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// fetchPruneTimeline pages back through the account's statuses for as long as the pages
+// hold posts old enough to prune
+func (c *MastodonClient) fetchPruneTimeline(ctx context.Context, username string, options PruneOptions) ([]Post, error) {
 	var allPosts []Post
-	cursor := ""
-	batchSize := 100
+	cursor := options.Checkpoint.StartCursor()
+	pageSizes := NewPageSizeRamp(initialPruneScanPageSize, MaxPageSize(c.platform))
 	
 	for {
-		posts, nextCursor, err := c.FetchUserPostsPaginated(username, batchSize, cursor)
+		posts, nextCursor, err := c.fetchPostsPage(ctx, username, pageSizes.Next(), cursor, FetchProfilePrune)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch posts: %w", err)
 		}
@@ -589,165 +976,147 @@ func (c *MastodonClient) PrunePosts(username string, options PruneOptions) (*Pru
 		if len(posts) == 0 {
 			break // No more posts to fetch
 		}
+		options.Checkpoint.addPage(cursor, posts)
 		
 		allPosts = append(allPosts, posts...)
 		
 		// Check if we should continue fetching based on age criteria
-		shouldContinue := false
-		if options.MaxAge != nil || options.BeforeDate != nil {
-			for _, post := range posts {
-				// If any post in this batch matches the age criteria, continue fetching
-				if options.MaxAge != nil && time.Now().Sub(post.CreatedAt) > *options.MaxAge {
-					shouldContinue = true
-					break
-				}
-				if options.BeforeDate != nil && post.CreatedAt.Before(*options.BeforeDate) {
-					shouldContinue = true
-					break
-				}
-			}
+		if nextCursor == "" || !options.pageMatchesAge(posts, c.clock.Now()) {
+			break // No more pages or no posts match age criteria
 		}
 		
-		if nextCursor == "" || !shouldContinue {
-			break // No more pages or no posts match age criteria
+		if options.reachedAfterDate(posts) {
+			break // Everything further back is before --after-date
+		}
+		
+		if options.runLimitReached(ctx, c.clock.Now()) != "" || DrainFromContext(ctx).Draining() {
+			break // No budget left to act on more pages, or shutting down
 		}
 		
 		cursor = nextCursor
 	}
 	
-	posts := allPosts
+	return allPosts, nil
+}
 
-	// If user wants to unlike posts, also fetch their favorited posts
-	if options.UnlikePosts {
-		favoriteIDs, err := c.fetchAllFavoriteIDs(instanceURL, creds, options)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to fetch favorited posts: %v\n", err)
-		} else {
-			// Convert favorite IDs to Post structs for processing
-			for _, favoriteID := range favoriteIDs {
-				favoritePost := Post{
-					ID:        favoriteID,
-					Type:      PostTypeLike,
-					Platform:  "mastodon",
-					CreatedAt: time.Now(), // We don't have the actual favorite time
-					Content:   fmt.Sprintf("Favorited status: %s", favoriteID),
-				}
-				posts = append(posts, favoritePost)
-			}
-		}
-	}
+// mastodonPruneActor acts on the authenticated account's statuses for a PruneEngine
+type mastodonPruneActor struct {
+	client      *MastodonClient
+	creds       *Credentials
+	deleteMedia bool
+}
 
-	result := &PruneResult{
-		PostsToDelete:  []Post{},
-		PostsToUnlike:  []Post{},
-		PostsToUnshare: []Post{},
-		PostsPreserved: []Post{},
-		Errors:         []string{},
-	}
+// Act deletes, redacts, unfavourites or unreblogs the status behind post
+func (a *mastodonPruneActor) Act(ctx context.Context, action string, post Post) error {
+	switch action {
+	case "delete":
+		return a.client.deletePost(ctx, a.creds, post.ID, a.deleteMedia)
+	case "redact":
+		return a.client.redactPost(ctx, a.creds, post.ID)
+	case "unlike":
+		return a.client.unlikePost(ctx, a.creds, post.ID)
+	case "unshare":
+		return a.client.unreblogPost(ctx, a.creds, post.ID)
+	}
+	return fmt.Errorf("unknown prune action %q", action)
+}
 
-	now := time.Now()
+// trackRedrafts records in ids any of the user's statuses that reappeared under a new ID.
+// A status is taken to be a redraft when its fingerprint was last seen on a different ID
+// that the server no longer has, and that cringesweeper didn't delete itself.
+func (c *MastodonClient) trackRedrafts(ctx context.Context, creds *Credentials, posts []Post, ids *PostIDMap) {
+	logger := WithPlatform(c.platform)
+	previous, err := ids.Fingerprints(c.platform)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Can't check for redrafted posts")
+		return
+	}
 
+	present := make(map[string]bool, len(posts))
 	for _, post := range posts {
-		shouldProcess := false
-		preserveReason := ""
+		present[post.ID] = true
+	}
 
-		// Check age criteria
-		if options.MaxAge != nil {
-			if now.Sub(post.CreatedAt) > *options.MaxAge {
-				shouldProcess = true
-			}
+	// Fingerprints of posts outside this fetch are kept, so deeper history still matches later
+	fingerprints := make(map[string]string, len(previous)+len(posts))
+	for fingerprint, id := range previous {
+		fingerprints[fingerprint] = id
+	}
+	for _, post := range posts {
+		if post.Type != PostTypeOriginal && post.Type != PostTypeReply && post.Type != PostTypeQuote {
+			continue
 		}
-
-		// Check date criteria
-		if options.BeforeDate != nil {
-			if post.CreatedAt.Before(*options.BeforeDate) {
-				shouldProcess = true
-			}
+		fingerprint := postFingerprint(post)
+		if fingerprint == "" {
+			continue
 		}
+		fingerprints[fingerprint] = post.ID
 
-		if !shouldProcess {
+		oldID, seen := previous[fingerprint]
+		if !seen || present[oldID] || wasDeleted(c.platform, oldID) {
 			continue
 		}
-
-		// Check preservation rules
-		if options.PreservePinned && post.IsPinned {
-			preserveReason = "pinned"
-		} else if options.PreserveSelfLike && post.IsLikedByUser && post.Type == PostTypeOriginal {
-			preserveReason = "self-liked"
+		gone, err := c.statusGone(ctx, creds, oldID)
+		if err != nil {
+			logger.Debug().Err(err).Str("post_id", oldID).Msg("Couldn't check whether post was redrafted")
+			continue
 		}
-
-		if preserveReason != "" {
-			result.PostsPreserved = append(result.PostsPreserved, post)
-			result.PreservedCount++
-		} else {
-			// Determine action based on post type
-			if post.Type == PostTypeLike {
-				// Handle favorite records - unfavorite them
-				result.PostsToUnlike = append(result.PostsToUnlike, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					logger := WithPlatform("mastodon").With().Str("post_id", post.ID).Logger()
-					if err := c.unlikePost(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to unfavorite post")
-						fmt.Printf("❌ Failed to unfavorite post: %v\n", err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to unfavorite post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Post unfavorited successfully")
-						fmt.Printf("👍 Unfavorited post: %s\n", TruncateContent(post.Content, 50))
-						result.UnlikedCount++
-					}
-				}
-			} else if post.Type == PostTypeRepost {
-				// Always unreblog for reblog records - these are the user's own reblog actions
-				result.PostsToUnshare = append(result.PostsToUnshare, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					logger := WithPlatform("mastodon").With().Str("post_id", post.ID).Logger()
-					if err := c.unreblogPost(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to unreblog post")
-						fmt.Printf("❌ Failed to unreblog post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to unreblog post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Reblog unshared successfully")
-						fmt.Printf("🔄 Unshared reblog from %s: %s\n", post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
-						result.UnsharedCount++
-					}
-				}
-			} else if post.Type == PostTypeOriginal || post.Type == PostTypeReply {
-				// Only delete the user's own original posts and replies
-				result.PostsToDelete = append(result.PostsToDelete, post)
-				if !options.DryRun {
-					// Add configurable delay to respect rate limits
-					time.Sleep(options.RateLimitDelay)
-					logger := WithPlatform("mastodon").With().Str("post_id", post.ID).Logger()
-					if err := c.deletePost(creds, post.ID); err != nil {
-						logger.Error().Err(err).Msg("Failed to delete post")
-						fmt.Printf("❌ Failed to delete post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
-						result.Errors = append(result.Errors, fmt.Sprintf("Failed to delete post %s: %v", post.ID, err))
-						result.ErrorsCount++
-					} else {
-						logger.Info().Str("content", TruncateContent(post.Content, 50)).Msg("Post deleted successfully")
-						fmt.Printf("🗑️  Deleted post from %s: %s\n", post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
-						result.DeletedCount++
-					}
-				}
-			}
+		if !gone {
+			continue
+		}
+		if err := ids.Record(c.platform, oldID, post.ID, c.clock.Now()); err != nil {
+			logger.Warn().Err(err).Str("old_id", oldID).Str("new_id", post.ID).Msg("Failed to record redrafted post")
+			continue
 		}
+		logger.Info().Str("old_id", oldID).Str("new_id", post.ID).Msg("Post was redrafted under a new ID")
 	}
 
-	return result, nil
+	if err := ids.SaveFingerprints(c.platform, fingerprints); err != nil {
+		logger.Warn().Err(err).Msg("Failed to save post fingerprints")
+	}
+}
+
+// statusGone returns true if the instance no longer has the status
+func (c *MastodonClient) statusGone(ctx context.Context, creds *Credentials, statusID string) (bool, error) {
+	c.ensureAuthenticated(creds, creds.Instance)
+	url := fmt.Sprintf("%s/api/v1/statuses/%s", creds.Instance, statusID)
+
+	req, err := c.authenticatedClient.CreateRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.authenticatedClient.DoRequest(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return false, nil
+	case http.StatusNotFound, http.StatusGone:
+		return true, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, newAPIError(c.platform, "status lookup", resp.StatusCode, body)
+	}
 }
 
-// deletePost deletes a Mastodon post
-func (c *MastodonClient) deletePost(creds *Credentials, postID string) error {
+// deletePost deletes a Mastodon post. With deleteMedia the server removes its attachments
+// right away instead of keeping them around for a redraft; servers older than 4.4 ignore it.
+func (c *MastodonClient) deletePost(ctx context.Context, creds *Credentials, postID string, deleteMedia bool) error {
 	c.ensureAuthenticated(creds, creds.Instance)
 	url := fmt.Sprintf("%s/api/v1/statuses/%s", creds.Instance, postID)
+	if deleteMedia {
+		url += "?delete_media=true"
+	}
+
+	if err := c.waitForDeleteLimit(ctx); err != nil {
+		return err
+	}
 
-	req, err := c.authenticatedClient.CreateRequest("DELETE", url, nil)
+	req, err := c.authenticatedClient.CreateRequest(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -757,21 +1126,76 @@ func (c *MastodonClient) deletePost(creds *Credentials, postID string) error {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	c.deleteLimit.record(c.clock.Now(), resp.Header)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return newAPIError(c.platform, "API request", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// waitForDeleteLimit pauses until the server's deletion limit allows another delete.
+// Small runs go at the --rate-limit-delay pace, and only a run that uses up the window
+// waits, for just as long as it takes to free a slot.
+func (c *MastodonClient) waitForDeleteLimit(ctx context.Context) error {
+	pause := c.deleteLimit.pause(c.clock.Now())
+	if pause <= 0 {
+		return nil
+	}
+	WithPlatform(c.platform).Info().Dur("pause", pause).Msg("Deletion rate limit reached, pausing")
+	limit := c.flavor().deleteLimit
+	fmt.Printf("⏳ %s allows %d deletions every %s, waiting %s for the next\n", c.flavor().name, limit.Limit, formatWindow(limit.Window), pause.Round(time.Second))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(pause):
+		return nil
+	}
+}
+
+// redactPost edits a Mastodon post down to RedactedContent, dropping its content warning
+// and media. Mastodon keeps the post's edit history, which still shows earlier versions.
+func (c *MastodonClient) redactPost(ctx context.Context, creds *Credentials, postID string) error {
+	c.ensureAuthenticated(creds, creds.Instance)
+	url := fmt.Sprintf("%s/api/v1/statuses/%s", creds.Instance, postID)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"status":       RedactedContent,
+		"spoiler_text": "",
+		"sensitive":    false,
+		"media_ids":    []string{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal edit data: %w", err)
+	}
+
+	req, err := c.authenticatedClient.CreateRequest(ctx, "PUT", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.authenticatedClient.DoRequest(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(c.platform, "API request", resp.StatusCode, body)
 	}
 
 	return nil
 }
 
 // unlikePost unlikes (unfavourites) a Mastodon post
-func (c *MastodonClient) unlikePost(creds *Credentials, postID string) error {
+func (c *MastodonClient) unlikePost(ctx context.Context, creds *Credentials, postID string) error {
 	c.ensureAuthenticated(creds, creds.Instance)
 	url := fmt.Sprintf("%s/api/v1/statuses/%s/unfavourite", creds.Instance, postID)
 
-	req, err := c.authenticatedClient.CreateRequest("POST", url, nil)
+	req, err := c.authenticatedClient.CreateRequest(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -784,18 +1208,23 @@ func (c *MastodonClient) unlikePost(creds *Credentials, postID string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return newAPIError(c.platform, "API request", resp.StatusCode, body)
 	}
 
 	return nil
 }
 
-// unreblogPost unreblogs (unshares) a Mastodon post
-func (c *MastodonClient) unreblogPost(creds *Credentials, postID string) error {
+// unreblogPost unreblogs (unshares) a Mastodon post. Mastodon deletes the reblog's own
+// status to do it, so unreblogs count against the deletion limit along with deletes.
+func (c *MastodonClient) unreblogPost(ctx context.Context, creds *Credentials, postID string) error {
 	c.ensureAuthenticated(creds, creds.Instance)
 	url := fmt.Sprintf("%s/api/v1/statuses/%s/unreblog", creds.Instance, postID)
 
-	req, err := c.authenticatedClient.CreateRequest("POST", url, nil)
+	if err := c.waitForDeleteLimit(ctx); err != nil {
+		return err
+	}
+
+	req, err := c.authenticatedClient.CreateRequest(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -805,10 +1234,11 @@ func (c *MastodonClient) unreblogPost(creds *Credentials, postID string) error {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	c.deleteLimit.record(c.clock.Now(), resp.Header)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return newAPIError(c.platform, "API request", resp.StatusCode, body)
 	}
 
 	return nil
@@ -828,24 +1258,97 @@ func (c *MastodonClient) determinePostType(status mastodonStatus) PostType {
 // ensureAuthenticated ensures we have cached authentication details
 func (c *MastodonClient) ensureAuthenticated(creds *Credentials, instanceURL string) {
 	// Cache credentials and instance URL for reuse
-	logger := WithPlatform("mastodon")
+	logger := WithPlatform(c.platform)
 	if c.sessionManager.HasCredentialsChanged(creds) || c.instanceURL != instanceURL {
 		if c.sessionManager.HasCredentialsChanged(creds) {
 			logger.Debug().Str("instance", instanceURL).Msg("Setting up Mastodon authentication")
 			fmt.Printf("🔐 Setting up Mastodon authentication for %s...\n", instanceURL)
 		}
-		c.sessionManager.UpdateSession(creds.AccessToken, "", time.Now().Add(24*time.Hour), creds)
-		c.authenticatedClient = NewAuthenticatedHTTPClient(creds.AccessToken, instanceURL, 30*time.Second)
+		c.sessionManager.UpdateSession(creds.AccessToken, "", c.clock.Now().Add(24*time.Hour), creds)
+		c.authenticatedClient = NewAuthenticatedHTTPClient(creds.AccessToken, instanceURL, 0)
 		c.instanceURL = instanceURL
 	}
 }
 
 
+// resolveReplyAuthors returns the display names of the accounts the given statuses reply to,
+// keyed by account ID. Each account is fetched at most once per client, with up to
+// replyAuthorConcurrency lookups in flight. Failed lookups are left out of the result.
+func (c *MastodonClient) resolveReplyAuthors(ctx context.Context, instanceURL string, statuses []mastodonStatus, creds *Credentials) map[string]string {
+	var missing []string
+	wanted := make(map[string]bool)
+
+	c.accountCacheMu.Lock()
+	if c.accountCache == nil {
+		c.accountCache = make(map[string]*mastodonAccount)
+	}
+	for _, status := range statuses {
+		if status.InReplyToID == nil || status.InReplyToAccountID == nil {
+			continue
+		}
+		accountID := *status.InReplyToAccountID
+		if wanted[accountID] {
+			continue
+		}
+		wanted[accountID] = true
+		if _, cached := c.accountCache[accountCacheKey(instanceURL, accountID)]; !cached {
+			missing = append(missing, accountID)
+		}
+	}
+	c.accountCacheMu.Unlock()
+
+	if len(missing) > 0 {
+		logger := WithPlatform(c.platform)
+		logger.Debug().Int("accounts", len(missing)).Int("cached", len(wanted)-len(missing)).Msg("Resolving reply authors")
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, replyAuthorConcurrency)
+		for _, accountID := range missing {
+			wg.Add(1)
+			go func(accountID string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				account, err := c.fetchAccountInfo(ctx, instanceURL, accountID, creds)
+				if err != nil {
+					logger.Debug().Err(err).Str("account_id", accountID).Msg("Failed to resolve reply author")
+					return
+				}
+				c.accountCacheMu.Lock()
+				c.accountCache[accountCacheKey(instanceURL, accountID)] = account
+				c.accountCacheMu.Unlock()
+			}(accountID)
+		}
+		wg.Wait()
+	}
+
+	names := make(map[string]string, len(wanted))
+	c.accountCacheMu.Lock()
+	defer c.accountCacheMu.Unlock()
+	for accountID := range wanted {
+		account, ok := c.accountCache[accountCacheKey(instanceURL, accountID)]
+		if !ok {
+			continue
+		}
+		names[accountID] = account.DisplayName
+		if names[accountID] == "" {
+			names[accountID] = account.Acct
+		}
+	}
+	return names
+}
+
+// accountCacheKey scopes account IDs to their instance, since IDs are only unique per instance
+func accountCacheKey(instanceURL, accountID string) string {
+	return instanceURL + "|" + accountID
+}
+
 // fetchAccountInfo fetches account information by account ID
-func (c *MastodonClient) fetchAccountInfo(instanceURL, accountID string, creds *Credentials) (*mastodonAccount, error) {
+func (c *MastodonClient) fetchAccountInfo(ctx context.Context, instanceURL, accountID string, creds *Credentials) (*mastodonAccount, error) {
 	accountURL := fmt.Sprintf("%s/api/v1/accounts/%s", instanceURL, accountID)
 
-	req, err := http.NewRequest("GET", accountURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", accountURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -855,19 +1358,16 @@ func (c *MastodonClient) fetchAccountInfo(instanceURL, accountID string, creds *
 		req.Header.Set("Authorization", "Bearer "+creds.AccessToken)
 	}
 
-	LogHTTPRequest("GET", accountURL)
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	client := sharedHTTPClient()
+	resp, err := doRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch account: %w", err)
 	}
 	defer resp.Body.Close()
 
-	LogHTTPResponse("GET", accountURL, resp.StatusCode, resp.Status)
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("account request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(c.platform, "account request", resp.StatusCode, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -883,25 +1383,24 @@ func (c *MastodonClient) fetchAccountInfo(instanceURL, accountID string, creds *
 	return &account, nil
 }
 
-// fetchAllFavoriteIDs fetches ALL favorited posts using pagination based on age criteria
-func (c *MastodonClient) fetchAllFavoriteIDs(instanceURL string, creds *Credentials, options PruneOptions) ([]string, error) {
+// fetchAllFavourites fetches favourited statuses as likes, paging back for as long as the
+// pages hold statuses old enough to prune. The API doesn't say when a status was
+// favourited, so each like carries the favourited status's own created_at.
+func (c *MastodonClient) fetchAllFavourites(ctx context.Context, instanceURL string, creds *Credentials, options PruneOptions) ([]Post, error) {
 	c.ensureAuthenticated(creds, instanceURL)
-	var allFavoriteIDs []string
-	maxID := ""
-	previousMaxID := ""
+	var allFavourites []Post
 	batchSize := 100
 	
+	// Favourites are paged by the ID of the favourite, not of the status, so the next
+	// page's URL has to come from the Link header
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(batchSize))
+	fullURL := fmt.Sprintf("%s/api/v1/favourites?%s", instanceURL, params.Encode())
+	seenURLs := make(map[string]bool)
+	
 	for {
-		favoritesURL := fmt.Sprintf("%s/api/v1/favourites", instanceURL)
-		params := url.Values{}
-		params.Add("limit", strconv.Itoa(batchSize))
-		if maxID != "" {
-			params.Add("max_id", maxID)
-		}
-		
-		fullURL := fmt.Sprintf("%s?%s", favoritesURL, params.Encode())
-		
-		req, err := c.authenticatedClient.CreateRequest("GET", fullURL, nil)
+		seenURLs[fullURL] = true
+		req, err := c.authenticatedClient.CreateRequest(ctx, "GET", fullURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
@@ -914,7 +1413,7 @@ func (c *MastodonClient) fetchAllFavoriteIDs(instanceURL string, creds *Credenti
 		
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+			return nil, newAPIError(c.platform, "API request", resp.StatusCode, body)
 		}
 		
 		body, err := io.ReadAll(resp.Body)
@@ -934,11 +1433,12 @@ func (c *MastodonClient) fetchAllFavoriteIDs(instanceURL string, creds *Credenti
 		// Check if we should continue fetching based on age criteria
 		shouldContinue := false
 		for _, status := range statuses {
-			// Add to our list
-			allFavoriteIDs = append(allFavoriteIDs, status.ID)
+			like := c.statusPost(status, nil)
+			like.Type = PostTypeLike
+			allFavourites = append(allFavourites, like)
 			
 			// Check if any favorite in this batch matches the age criteria
-			if options.MaxAge != nil && time.Now().Sub(status.CreatedAt) > *options.MaxAge {
+			if options.MaxAge != nil && c.clock.Now().Sub(status.CreatedAt) > *options.MaxAge {
 				shouldContinue = true
 			}
 			if options.BeforeDate != nil && status.CreatedAt.Before(*options.BeforeDate) {
@@ -946,19 +1446,17 @@ func (c *MastodonClient) fetchAllFavoriteIDs(instanceURL string, creds *Credenti
 			}
 		}
 		
-		// Set maxID for next request with infinite loop protection
-		newMaxID := statuses[len(statuses)-1].ID
-		if newMaxID == previousMaxID {
-			break // Prevent infinite loop - API returned same results
-		}
-		previousMaxID = maxID
-		maxID = newMaxID
-		
 		if !shouldContinue {
 			break // No favorites match age criteria, stop fetching
 		}
+		
+		nextURL, ok := nextPageURL(resp.Header, instanceURL, seenURLs)
+		if !ok {
+			break // Last page, or a link we won't follow
+		}
+		fullURL = nextURL
 	}
 	
-	return allFavoriteIDs, nil
+	return allFavourites, nil
 }
 
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// pruneListedPosts prunes just the statuses in options.ListedPosts, looking each one up
+// instead of paging through the timeline. A listed status of someone else's is unliked
+// and unshared if the user favourited or boosted it.
+func (c *MastodonClient) pruneListedPosts(ctx context.Context, username string, creds *Credentials, options PruneOptions) (*PruneResult, error) {
+	instanceURL, acct, err := c.parseUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username format: %w", err)
+	}
+	accountID, err := c.getAccountID(ctx, instanceURL, acct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account ID: %w", err)
+	}
+
+	result := &PruneResult{
+		PostsToDelete:  []Post{},
+		PostsToUnlike:  []Post{},
+		PostsToUnshare: []Post{},
+		PostsPreserved: []Post{},
+		Errors:         []string{},
+	}
+
+	var posts []Post
+	for _, statusID := range options.ListedPosts {
+		status, err := c.fetchStatus(ctx, creds, statusID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up status %s: %w", statusID, err)
+		}
+		if status == nil {
+			result.AddWarning("Status %s wasn't found on %s", statusID, c.platform)
+			continue
+		}
+
+		if status.Account.ID == accountID {
+			posts = append(posts, c.statusPost(*status, nil))
+			continue
+		}
+
+		// Someone else's status can only be unliked or unshared
+		liked := status.Favourited != nil && *status.Favourited
+		reblogged := status.Reblogged != nil && *status.Reblogged
+		if !liked && !reblogged {
+			result.AddWarning("Status %s on %s isn't yours, and you haven't favourited or boosted it", statusID, c.platform)
+			continue
+		}
+		original := c.statusPost(*status, nil)
+		if liked {
+			like := original
+			like.Type = PostTypeLike
+			posts = append(posts, like)
+		}
+		if reblogged {
+			repost := original
+			repost.Type = PostTypeRepost
+			repost.OriginalPost = &original
+			posts = append(posts, repost)
+		}
+	}
+
+	actor := &mastodonPruneActor{client: c, creds: creds, deleteMedia: options.DeleteMedia}
+	if err := NewPruneEngine(c.platform, actor, options, c.clock).Run(ctx, orderRepliesBeforeParents(posts), result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// fetchStatus looks up one status as the authenticated user sees it, returning nil if
+// the instance doesn't have it
+func (c *MastodonClient) fetchStatus(ctx context.Context, creds *Credentials, statusID string) (*mastodonStatus, error) {
+	c.ensureAuthenticated(creds, creds.Instance)
+	url := fmt.Sprintf("%s/api/v1/statuses/%s", creds.Instance, statusID)
+
+	req, err := c.authenticatedClient.CreateRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.authenticatedClient.DoRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(c.platform, "status lookup", resp.StatusCode, body)
+	}
+
+	var status mastodonStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+	return &status, nil
+}
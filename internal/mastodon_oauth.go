@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MastodonOOBRedirectURI asks the instance to show the authorization code to the user
+// instead of redirecting, for when no browser can reach a local callback
+const MastodonOOBRedirectURI = "urn:ietf:wg:oauth:2.0:oob"
+
+// mastodonScopes are the scopes CringeSweeper needs: read to list posts, write to delete them
+const mastodonScopes = "read write"
+
+// MastodonApp is an OAuth application registered on a Mastodon instance
+type MastodonApp struct {
+	InstanceURL  string
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURI  string `json:"-"`
+}
+
+// RegisterMastodonApp registers CringeSweeper as an OAuth application on the instance
+// via /api/v1/apps, so the user doesn't have to create one by hand
+func RegisterMastodonApp(ctx context.Context, instanceURL, redirectURI string) (*MastodonApp, error) {
+	form := url.Values{}
+	form.Set("client_name", "CringeSweeper")
+	form.Set("redirect_uris", redirectURI)
+	form.Set("scopes", mastodonScopes)
+	form.Set("website", "https://github.com/gerrowadat/cringesweeper")
+
+	body, err := postMastodonForm(ctx, instanceURL+"/api/v1/apps", form, "app registration")
+	if err != nil {
+		return nil, err
+	}
+
+	var app MastodonApp
+	if err := json.Unmarshal(body, &app); err != nil {
+		return nil, fmt.Errorf("failed to parse app registration response: %w", err)
+	}
+	if app.ClientID == "" || app.ClientSecret == "" {
+		return nil, fmt.Errorf("app registration response is missing the client credentials")
+	}
+	app.InstanceURL = instanceURL
+	app.RedirectURI = redirectURI
+	return &app, nil
+}
+
+// AuthorizeURL returns the page where the user approves the app. The state is echoed
+// back to the redirect URI so the callback can be matched to this request.
+func (a *MastodonApp) AuthorizeURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", a.ClientID)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", a.RedirectURI)
+	params.Set("scope", mastodonScopes)
+	if state != "" {
+		params.Set("state", state)
+	}
+	return a.InstanceURL + "/oauth/authorize?" + params.Encode()
+}
+
+// ExchangeCode trades an authorization code for an access token
+func (a *MastodonApp) ExchangeCode(ctx context.Context, code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", a.ClientID)
+	form.Set("client_secret", a.ClientSecret)
+	form.Set("redirect_uri", a.RedirectURI)
+	form.Set("scope", mastodonScopes)
+
+	body, err := postMastodonForm(ctx, a.InstanceURL+"/oauth/token", form, "token request")
+	if err != nil {
+		return "", err
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token response is missing the access token")
+	}
+	return token.AccessToken, nil
+}
+
+// VerifyMastodonToken returns the username of the account an access token belongs to
+func VerifyMastodonToken(ctx context.Context, instanceURL, accessToken string) (string, error) {
+	account, err := verifyMastodonCredentials(ctx, "mastodon", instanceURL, accessToken)
+	if err != nil {
+		return "", err
+	}
+	return account.Username, nil
+}
+
+// verifyMastodonCredentials fetches the account an access token belongs to
+func verifyMastodonCredentials(ctx context.Context, platform, instanceURL, accessToken string) (*mastodonAccount, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", instanceURL+"/api/v1/accounts/verify_credentials", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := doRequest(sharedHTTPClient(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(platform, "verify credentials request", resp.StatusCode, body)
+	}
+
+	var account mastodonAccount
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse account response: %w", err)
+	}
+	if account.Username == "" {
+		return nil, fmt.Errorf("account response is missing the username")
+	}
+	return &account, nil
+}
+
+// NewOAuthState returns a random value for the OAuth state parameter
+func NewOAuthState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func postMastodonForm(ctx context.Context, endpoint string, form url.Values, operation string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := doRequest(sharedHTTPClient(), req)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", operation, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("mastodon", operation, resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// fakeMastodonOAuthServer implements the app registration, token and account endpoints
+func fakeMastodonOAuthServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/apps":
+			if r.Method != "POST" || r.FormValue("scopes") != "read write" || r.FormValue("redirect_uris") == "" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			fmt.Fprint(w, `{"client_id": "id123", "client_secret": "secret456"}`)
+		case "/oauth/token":
+			if r.FormValue("grant_type") != "authorization_code" || r.FormValue("client_secret") != "secret456" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.FormValue("code") != "good-code" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_grant"}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "token789", "token_type": "Bearer", "scope": "read write"}`)
+		case "/api/v1/accounts/verify_credentials":
+			if r.Header.Get("Authorization") != "Bearer token789" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"id": "1", "username": "alice", "acct": "alice"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMastodonOAuthFlow(t *testing.T) {
+	server := fakeMastodonOAuthServer(t)
+	ctx := context.Background()
+
+	app, err := RegisterMastodonApp(ctx, server.URL, "http://127.0.0.1:1234/callback")
+	if err != nil {
+		t.Fatalf("RegisterMastodonApp failed: %v", err)
+	}
+	if app.ClientID != "id123" || app.ClientSecret != "secret456" {
+		t.Errorf("Unexpected app credentials %+v", app)
+	}
+
+	authorizeURL, err := url.Parse(app.AuthorizeURL("state1"))
+	if err != nil {
+		t.Fatalf("Invalid authorize URL: %v", err)
+	}
+	query := authorizeURL.Query()
+	if authorizeURL.Path != "/oauth/authorize" || query.Get("client_id") != "id123" || query.Get("response_type") != "code" ||
+		query.Get("redirect_uri") != "http://127.0.0.1:1234/callback" || query.Get("scope") != "read write" || query.Get("state") != "state1" {
+		t.Errorf("Unexpected authorize URL %s", authorizeURL)
+	}
+
+	token, err := app.ExchangeCode(ctx, "good-code")
+	if err != nil {
+		t.Fatalf("ExchangeCode failed: %v", err)
+	}
+	if token != "token789" {
+		t.Errorf("Expected token789, got %q", token)
+	}
+
+	username, err := VerifyMastodonToken(ctx, server.URL, token)
+	if err != nil {
+		t.Fatalf("VerifyMastodonToken failed: %v", err)
+	}
+	if username != "alice" {
+		t.Errorf("Expected alice, got %q", username)
+	}
+}
+
+func TestMastodonOAuthErrors(t *testing.T) {
+	server := fakeMastodonOAuthServer(t)
+	ctx := context.Background()
+	app := &MastodonApp{InstanceURL: server.URL, ClientID: "id123", ClientSecret: "secret456", RedirectURI: MastodonOOBRedirectURI}
+
+	var apiErr *APIError
+	if _, err := app.ExchangeCode(ctx, "bad-code"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a 400 APIError for a bad code, got %v", err)
+	}
+	if _, err := VerifyMastodonToken(ctx, server.URL, "wrong"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a 401 APIError for a bad token, got %v", err)
+	}
+	if _, err := RegisterMastodonApp(ctx, server.URL+"/missing", MastodonOOBRedirectURI); err == nil {
+		t.Error("Expected registration against a non-Mastodon server to fail")
+	}
+}
+
+func TestNewOAuthState(t *testing.T) {
+	a, err := NewOAuthState()
+	if err != nil {
+		t.Fatalf("NewOAuthState failed: %v", err)
+	}
+	b, _ := NewOAuthState()
+	if len(a) != 32 || a == b {
+		t.Errorf("Expected distinct 32-character states, got %q and %q", a, b)
+	}
+}
+
+func TestMastodonClient_VerifyAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/verify_credentials" || r.Header.Get("Authorization") != "Bearer good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "The access token is invalid"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "1", "username": "alice", "acct": "alice", "statuses_count": 1234, "created_at": "2022-11-05T00:00:00.000Z"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_USER", "alice")
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+
+	t.Run("working token", func(t *testing.T) {
+		t.Setenv("MASTODON_ACCESS_TOKEN", "good-token")
+		account, err := NewMastodonClient().VerifyAccount(context.Background())
+		if err != nil {
+			t.Fatalf("VerifyAccount() error = %v", err)
+		}
+		want := "alice@" + instanceHost(server.URL)
+		if account.Username != want || account.PostCount != 1234 || account.CreatedAt.Format("2006-01-02") != "2022-11-05" {
+			t.Errorf("Expected %s with 1234 posts created 2022-11-05, got %+v", want, account)
+		}
+		if account.ID != "1" || !account.MatchesCredentials {
+			t.Errorf("Expected account 1 to match the credentials, got %+v", account)
+		}
+	})
+
+	t.Run("token for another account", func(t *testing.T) {
+		t.Setenv("MASTODON_USER", "bob")
+		t.Setenv("MASTODON_ACCESS_TOKEN", "good-token")
+		account, err := NewMastodonClient().VerifyAccount(context.Background())
+		if err != nil {
+			t.Fatalf("VerifyAccount() error = %v", err)
+		}
+		if account.MatchesCredentials || account.CredentialsUsername != "bob" {
+			t.Errorf("Expected alice's token not to match bob, got %+v", account)
+		}
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		t.Setenv("MASTODON_ACCESS_TOKEN", "revoked-token")
+		_, err := NewMastodonClient().VerifyAccount(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected a 401 API error, got %v", err)
+		}
+	})
+}
+
+func TestMastodonClient_PrunePostsChecksTokenOwner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/verify_credentials" {
+			t.Errorf("Expected nothing but the token check, got %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"id": "2", "username": "bob", "acct": "bob"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_USER", "alice")
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+	t.Setenv("MASTODON_ACCESS_TOKEN", "bobs-token")
+
+	_, err := NewMastodonClient().PrunePosts(context.Background(), "alice@"+instanceHost(server.URL), PruneOptions{DryRun: true})
+	if !errors.Is(err, ErrNotOwnAccount) {
+		t.Errorf("Expected a token for another account to stop the prune, got %v", err)
+	}
+}
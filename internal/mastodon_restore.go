@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PostExists reports whether the status is still on the server
+func (c *MastodonClient) PostExists(ctx context.Context, username string, post Post) (bool, error) {
+	creds, err := c.relationCredentials(username)
+	if err != nil {
+		return false, err
+	}
+	gone, err := c.statusGone(ctx, creds, post.ID)
+	if err != nil {
+		return false, err
+	}
+	return !gone, nil
+}
+
+// RestorePost posts a new status with the archived post's text, content warning,
+// visibility and language. Mastodon sets a status's date when it's posted, so the
+// original date is lost. The Idempotency-Key makes a retried request post it only once.
+func (c *MastodonClient) RestorePost(ctx context.Context, username string, post Post, inReplyTo string) (string, error) {
+	creds, err := c.relationCredentials(username)
+	if err != nil {
+		return "", err
+	}
+	c.ensureAuthenticated(creds, creds.Instance)
+
+	status := map[string]interface{}{
+		"status":       post.Content,
+		"spoiler_text": post.ContentWarning,
+		"sensitive":    post.Sensitive,
+	}
+	if inReplyTo != "" {
+		status["in_reply_to_id"] = inReplyTo
+	}
+	if post.Visibility != "" {
+		status["visibility"] = post.Visibility
+	}
+	if len(post.Languages) > 0 {
+		status["language"] = post.Languages[0]
+	}
+	jsonData, err := json.Marshal(status)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	req, err := c.authenticatedClient.CreateRequest(ctx, "POST", creds.Instance+"/api/v1/statuses", bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Idempotency-Key", "cringesweeper-restore-"+post.ID)
+
+	resp, err := c.authenticatedClient.DoRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newAPIError(c.platform, "status request", resp.StatusCode, body)
+	}
+
+	var created mastodonStatus
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to parse created status: %w", err)
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,504 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMastodonClient_ResolveReplyAuthors(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := strings.TrimPrefix(r.URL.Path, "/api/v1/accounts/")
+		mu.Lock()
+		requests[accountID]++
+		mu.Unlock()
+
+		switch accountID {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "nodisplay":
+			fmt.Fprintf(w, `{"id": %q, "acct": "quiet@example.social"}`, accountID)
+		default:
+			fmt.Fprintf(w, `{"id": %q, "acct": "user%s", "display_name": "User %s"}`, accountID, accountID, accountID)
+		}
+	}))
+	defer server.Close()
+
+	reply := func(accountID string) mastodonStatus {
+		parentID := "parent"
+		return mastodonStatus{InReplyToID: &parentID, InReplyToAccountID: &accountID}
+	}
+	statuses := []mastodonStatus{reply("1"), reply("2"), reply("1"), reply("nodisplay"), reply("missing"), {ID: "original"}}
+
+	client := NewMastodonClient()
+	names := client.resolveReplyAuthors(context.Background(), server.URL, statuses, nil)
+
+	expected := map[string]string{"1": "User 1", "2": "User 2", "nodisplay": "quiet@example.social"}
+	if len(names) != len(expected) {
+		t.Errorf("Expected %d resolved authors, got %v", len(expected), names)
+	}
+	for accountID, name := range expected {
+		if names[accountID] != name {
+			t.Errorf("Expected author %q for account %s, got %q", name, accountID, names[accountID])
+		}
+	}
+	for accountID, count := range requests {
+		if count != 1 {
+			t.Errorf("Expected account %s to be fetched once, got %d", accountID, count)
+		}
+	}
+
+	// A second page replying to the same accounts should be served from the cache
+	client.resolveReplyAuthors(context.Background(), server.URL, []mastodonStatus{reply("1"), reply("2")}, nil)
+	if requests["1"] != 1 || requests["2"] != 1 {
+		t.Errorf("Expected cached accounts not to be fetched again, got %v", requests)
+	}
+}
+
+func TestMastodonStatus_MediaAttachments(t *testing.T) {
+	statusJSON := `{"id": "1", "media_attachments": [
+		{"id": "10", "type": "image", "url": "https://files.example/1.png", "description": "a cat"},
+		{"id": "11", "type": "gifv", "url": "https://files.example/2.mp4", "description": null}
+	]}`
+
+	var status mastodonStatus
+	if err := json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+
+	expected := []Attachment{
+		{Type: "image", URL: "https://files.example/1.png", AltText: "a cat"},
+		{Type: "gifv", URL: "https://files.example/2.mp4"},
+	}
+	got := mastodonAttachments(status.MediaAttachments)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d attachments, got %v", len(expected), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Attachment %d = %+v, expected %+v", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestMastodonClient_StatusPostSelfReply(t *testing.T) {
+	client := NewMastodonClient()
+	statuses := `[
+		{"id": "1", "account": {"id": "me"}, "in_reply_to_id": "0", "in_reply_to_account_id": "me"},
+		{"id": "2", "account": {"id": "me"}, "in_reply_to_id": "9", "in_reply_to_account_id": "them"}
+	]`
+	var parsed []mastodonStatus
+	if err := json.Unmarshal([]byte(statuses), &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal statuses: %v", err)
+	}
+
+	if post := client.statusPost(parsed[0], nil); post.Type != PostTypeReply || !post.SelfReply {
+		t.Errorf("Expected a reply to your own status to be a self-reply, got %+v", post)
+	}
+	if post := client.statusPost(parsed[1], nil); post.Type != PostTypeReply || post.SelfReply {
+		t.Errorf("Expected a reply to someone else not to be a self-reply, got %+v", post)
+	}
+}
+
+func TestMastodonClient_StatusPostQuote(t *testing.T) {
+	client := NewMastodonClient()
+	statuses := `[
+		{"id": "1", "account": {"id": "me"}, "quote": {"state": "accepted", "quoted_status": {"id": "10", "account": {"id": "me", "acct": "me"}}}},
+		{"id": "2", "account": {"id": "me"}, "quote": {"state": "accepted", "quoted_status": {"id": "20", "account": {"id": "them", "acct": "them@example.social"}}}},
+		{"id": "3", "account": {"id": "me"}, "quote": {"state": "accepted", "quoted_status_id": "30"}},
+		{"id": "4", "account": {"id": "me"}, "quote": {"state": "revoked", "quoted_status": null}},
+		{"id": "5", "account": {"id": "me"}, "quote": null}
+	]`
+	var parsed []mastodonStatus
+	if err := json.Unmarshal([]byte(statuses), &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal statuses: %v", err)
+	}
+
+	tests := []struct {
+		wantType   PostType
+		wantQuoted string
+		wantSelf   bool
+	}{
+		{PostTypeQuote, "10", true},
+		{PostTypeQuote, "20", false},
+		{PostTypeQuote, "30", false},
+		{PostTypeOriginal, "", false},
+		{PostTypeOriginal, "", false},
+	}
+	for i, tt := range tests {
+		post := client.statusPost(parsed[i], nil)
+		if post.Type != tt.wantType || post.SelfQuote != tt.wantSelf {
+			t.Errorf("Status %s: got type %s, self-quote %v; expected %s, %v", parsed[i].ID, post.Type, post.SelfQuote, tt.wantType, tt.wantSelf)
+		}
+		quoted := ""
+		if post.OriginalPost != nil {
+			quoted = post.OriginalPost.ID
+		}
+		if quoted != tt.wantQuoted {
+			t.Errorf("Status %s: expected quoted status %q, got %q", parsed[i].ID, tt.wantQuoted, quoted)
+		}
+	}
+}
+
+func TestMastodonClient_IsOwnAccount(t *testing.T) {
+	client := NewMastodonClient()
+
+	tests := []struct {
+		name     string
+		username string
+		creds    *Credentials
+		expected bool
+	}{
+		{"same account", "me@example.social", &Credentials{Username: "me@example.social", Instance: "https://example.social"}, true},
+		{"case differs", "Me@Example.Social", &Credentials{Username: "me@example.social"}, true},
+		{"bare saved username uses instance", "me@example.social", &Credentials{Username: "me", Instance: "https://example.social/"}, true},
+		{"same bare username", "me", &Credentials{Username: "me", Instance: "https://example.social"}, true},
+		{"different user", "someone@example.social", &Credentials{Username: "me@example.social"}, false},
+		{"same user on another instance", "me@other.social", &Credentials{Username: "me@example.social"}, false},
+		{"invalid username", "a@b@c", &Credentials{Username: "me@example.social"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := client.isOwnAccount(tt.username, tt.creds); got != tt.expected {
+				t.Errorf("isOwnAccount(%q) = %v, expected %v", tt.username, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{
+			"next and prev",
+			`<https://example.social/api/v1/favourites?max_id=42>; rel="next", <https://example.social/api/v1/favourites?min_id=57>; rel="prev"`,
+			map[string]string{
+				"next": "https://example.social/api/v1/favourites?max_id=42",
+				"prev": "https://example.social/api/v1/favourites?min_id=57",
+			},
+		},
+		{"unquoted rel", `<https://example.social/a?max_id=1>;rel=next`, map[string]string{"next": "https://example.social/a?max_id=1"}},
+		{"several relation types", `<https://example.social/a>; rel="next last"`, map[string]string{"next": "https://example.social/a", "last": "https://example.social/a"}},
+		{"no angle brackets", `https://example.social/a; rel="next"`, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := parseLinkHeader(tt.header)
+			if len(links) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, links)
+			}
+			for rel, target := range tt.expected {
+				if links[rel] != target {
+					t.Errorf("Expected %s link %q, got %q", rel, target, links[rel])
+				}
+			}
+		})
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	instance := "https://example.social"
+	seen := map[string]bool{instance + "/api/v1/favourites?limit=100": true}
+
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{"next page", `<https://example.social/api/v1/favourites?max_id=42>; rel="next"`, "https://example.social/api/v1/favourites?max_id=42"},
+		{"last page", `<https://example.social/api/v1/favourites?min_id=57>; rel="prev"`, ""},
+		{"no header", "", ""},
+		{"page already fetched", `<https://example.social/api/v1/favourites?limit=100>; rel="next"`, ""},
+		{"another host", `<https://elsewhere.social/api/v1/favourites?max_id=42>; rel="next"`, ""},
+		{"lookalike host", `<https://example.social.evil/api/v1/favourites?max_id=42>; rel="next"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.link != "" {
+				header.Set("Link", tt.link)
+			}
+			next, ok := nextPageURL(header, instance, seen)
+			if next != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("nextPageURL() = (%q, %v), expected %q", next, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMastodonClient_FetchAllFavouritesFollowsLinkHeader(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/favourites" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// Favourites page by favourite ID, which is unrelated to the status IDs returned
+		switch r.URL.Query().Get("max_id") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/favourites?max_id=5>; rel="next", <%s/api/v1/favourites?min_id=9>; rel="prev"`, server.URL, server.URL))
+			fmt.Fprintf(w, `[{"id": "900", "created_at": %q}]`, old)
+		case "5":
+			// Links back to the first page, which must not be fetched again
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/favourites?limit=100>; rel="next"`, server.URL))
+			fmt.Fprintf(w, `[{"id": "800", "created_at": %q}]`, old)
+		default:
+			t.Errorf("Unexpected max_id %q", r.URL.Query().Get("max_id"))
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewMastodonClient()
+	maxAge := 24 * time.Hour
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+
+	likes, err := client.fetchAllFavourites(context.Background(), server.URL, creds, PruneOptions{MaxAge: &maxAge})
+	if err != nil {
+		t.Fatalf("fetchAllFavourites failed: %v", err)
+	}
+	if len(likes) != 2 || likes[0].ID != "900" || likes[1].ID != "800" {
+		t.Fatalf("Expected likes of [900 800], got %v", likes)
+	}
+	for _, like := range likes {
+		if like.Type != PostTypeLike || time.Since(like.CreatedAt) < 29*24*time.Hour {
+			t.Errorf("Expected a like dated by the favourited status, got %s from %s", like.Type, like.CreatedAt)
+		}
+	}
+}
+
+func TestMastodonClient_TrackRedrafts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/statuses/old":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/statuses/duplicate":
+			fmt.Fprint(w, `{"id": "duplicate"}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+	ids := NewPostIDMapAt(t.TempDir())
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	client := NewMastodonClient()
+	client.SetClock(NewFakeClock(now))
+
+	redrafted := Post{ID: "new", Type: PostTypeOriginal, Content: "Typo fixed, honest"}
+	repeated := Post{ID: "again", Type: PostTypeOriginal, Content: "Good morning"}
+	unchanged := Post{ID: "same", Type: PostTypeOriginal, Content: "Nothing to see"}
+	if err := ids.SaveFingerprints("mastodon", map[string]string{
+		postFingerprint(redrafted): "old",
+		postFingerprint(repeated):  "duplicate",
+		postFingerprint(unchanged): "same",
+		"elsewhere":                "deep-history",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	client.trackRedrafts(context.Background(), creds, []Post{redrafted, repeated, unchanged}, ids)
+
+	if aliases := ids.Aliases("mastodon", "new"); len(aliases) != 2 || aliases[1] != "old" {
+		t.Errorf("Expected the redraft to be mapped to its old ID, got %v", aliases)
+	}
+	// The same text posted again while the first copy still exists isn't a redraft
+	if aliases := ids.Aliases("mastodon", "again"); len(aliases) != 1 {
+		t.Errorf("Expected a repeated post not to be mapped, got %v", aliases)
+	}
+
+	fingerprints, err := ids.Fingerprints("mastodon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprints[postFingerprint(redrafted)] != "new" || fingerprints["elsewhere"] != "deep-history" {
+		t.Errorf("Expected fingerprints to follow the redraft and keep older history, got %v", fingerprints)
+	}
+}
+
+func TestMastodonClient_RedactPost(t *testing.T) {
+	var edit map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/v1/statuses/42" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+			t.Errorf("Failed to decode edit: %v", err)
+		}
+		fmt.Fprint(w, `{"id": "42"}`)
+	}))
+	defer server.Close()
+
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+	if err := NewMastodonClient().redactPost(context.Background(), creds, "42"); err != nil {
+		t.Fatalf("redactPost() error = %v", err)
+	}
+	if edit["status"] != RedactedContent || edit["spoiler_text"] != "" {
+		t.Errorf("Expected the status replaced and its content warning cleared, got %v", edit)
+	}
+	if media, ok := edit["media_ids"].([]interface{}); !ok || len(media) != 0 {
+		t.Errorf("Expected media to be dropped, got %v", edit["media_ids"])
+	}
+}
+
+func TestMastodonClient_DeletePostMedia(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/api/v1/statuses/42" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprint(w, `{"id": "42"}`)
+	}))
+	defer server.Close()
+
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+	client := NewMastodonClient()
+	for _, deleteMedia := range []bool{false, true} {
+		if err := client.deletePost(context.Background(), creds, "42", deleteMedia); err != nil {
+			t.Fatalf("deletePost() error = %v", err)
+		}
+	}
+	if len(queries) != 2 || queries[0] != "" || queries[1] != "delete_media=true" {
+		t.Errorf("Expected delete_media only when asked for, got %q", queries)
+	}
+}
+
+func TestMastodonClient_UnreblogsShareDeleteLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "42"}`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+	client := NewMastodonClient()
+	client.SetClock(NewFakeClock(now))
+	client.deleteLimit = newRateTracker(RateWindow{Limit: 3, Window: 30 * time.Minute})
+
+	ctx := context.Background()
+	if err := client.deletePost(ctx, creds, "1", false); err != nil {
+		t.Fatalf("deletePost() error = %v", err)
+	}
+	for _, id := range []string{"2", "3"} {
+		if err := client.unreblogPost(ctx, creds, id); err != nil {
+			t.Fatalf("unreblogPost() error = %v", err)
+		}
+	}
+	if pause := client.deleteLimit.pause(now); pause != 30*time.Minute {
+		t.Errorf("Expected a delete and two unreblogs to use up the window, got a %v pause", pause)
+	}
+
+	// The next unreblog waits for the window like a delete would
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := client.unreblogPost(cancelled, creds, "4"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the unreblog to wait on the delete limit, got %v", err)
+	}
+}
+
+func TestMastodonClient_FetchStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/statuses/42":
+			fmt.Fprint(w, `{"id": "42", "content": "<p>hello</p>", "favourited": true, "account": {"id": "7"}}`)
+		case "/api/v1/statuses/gone":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	creds := &Credentials{Platform: "mastodon", Username: "me", Instance: server.URL, AccessToken: "token"}
+	client := NewMastodonClient()
+
+	status, err := client.fetchStatus(context.Background(), creds, "42")
+	if err != nil {
+		t.Fatalf("fetchStatus() error = %v", err)
+	}
+	if status == nil || status.Account.ID != "7" || status.Favourited == nil || !*status.Favourited {
+		t.Errorf("Expected the favourited status, got %+v", status)
+	}
+
+	if status, err := client.fetchStatus(context.Background(), creds, "gone"); err != nil || status != nil {
+		t.Errorf("Expected nothing for a deleted status, got %+v, %v", status, err)
+	}
+	if _, err := client.fetchStatus(context.Background(), creds, "private"); err == nil {
+		t.Error("Expected an error when the instance refuses the lookup")
+	}
+}
+
+func TestMastodonClient_RestorePost(t *testing.T) {
+	var posted map[string]interface{}
+	var idempotencyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/statuses/still-here":
+			fmt.Fprint(w, `{"id": "still-here"}`)
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/api/v1/statuses":
+			idempotencyKey = r.Header.Get("Idempotency-Key")
+			json.NewDecoder(r.Body).Decode(&posted)
+			fmt.Fprint(w, `{"id": "200"}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MASTODON_USER", "me")
+	t.Setenv("MASTODON_INSTANCE", server.URL)
+	t.Setenv("MASTODON_ACCESS_TOKEN", "token")
+	client := NewMastodonClient()
+	ctx := context.Background()
+
+	for id, want := range map[string]bool{"still-here": true, "100": false} {
+		if exists, err := client.PostExists(ctx, "me", Post{ID: id}); err != nil || exists != want {
+			t.Errorf("PostExists(%s) = %v, %v, want %v", id, exists, err, want)
+		}
+	}
+	if _, err := client.PostExists(ctx, "someone-else", Post{ID: "100"}); !errors.Is(err, ErrNotOwnAccount) {
+		t.Errorf("Expected ErrNotOwnAccount for another account, got %v", err)
+	}
+
+	post := Post{ID: "100", Content: "hello again", ContentWarning: "old news", Visibility: VisibilityUnlisted, Languages: []string{"de"}}
+	newID, err := client.RestorePost(ctx, "me", post, "99")
+	if err != nil {
+		t.Fatalf("RestorePost() error = %v", err)
+	}
+	if newID != "200" || idempotencyKey != "cringesweeper-restore-100" {
+		t.Errorf("Expected status 200 posted with the archived ID as its key, got %q with %q", newID, idempotencyKey)
+	}
+	want := map[string]interface{}{"status": "hello again", "spoiler_text": "old news", "sensitive": false, "in_reply_to_id": "99", "visibility": "unlisted", "language": "de"}
+	for field, value := range want {
+		if posted[field] != value {
+			t.Errorf("Expected %s %v, got %v", field, value, posted[field])
+		}
+	}
+}
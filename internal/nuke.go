@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NukeOptions returns the options for one pass of a nuke: every post, reply, quote,
+// repost and like on the account, whatever its age, with the media of deleted posts
+// removed along with them where the platform allows it
+func NukeOptions() PruneOptions {
+	everything := time.Duration(0)
+	return PruneOptions{
+		MaxAge:             &everything,
+		UnlikePosts:        true,
+		UnshareReposts:     true,
+		UnshareSelfReposts: true,
+		DeleteMedia:        true,
+		ContinueUntilEnd:   true,
+	}
+}
+
+// NukeCheckpoint records how far a nuke of one account has got, so a wipe interrupted by
+// rate limits, a crash or Ctrl-C carries on where it stopped rather than starting over.
+// The posts already removed are in the tombstone store, which keeps later passes from
+// trying them again; the checkpoint adds the running totals and whether the wipe is done.
+type NukeCheckpoint struct {
+	Platform  string    `json:"platform"`
+	Username  string    `json:"username"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Passes    int       `json:"passes"`
+	Deleted   int       `json:"deleted"`
+	Unliked   int       `json:"unliked"`
+	Unshared  int       `json:"unshared"`
+	Errors    int       `json:"errors"`
+	Complete  bool      `json:"complete"`             // Set once a pass finds nothing left to remove
+	LastError string    `json:"last_error,omitempty"` // Why the last pass stopped, if it failed
+}
+
+// NewNukeCheckpoint starts the checkpoint for a wipe of an account beginning at now
+func NewNukeCheckpoint(platform, username string, now time.Time) *NukeCheckpoint {
+	return &NukeCheckpoint{Platform: platform, Username: username, StartedAt: now, UpdatedAt: now}
+}
+
+// Record adds a finished pass to the checkpoint
+func (c *NukeCheckpoint) Record(result *PruneResult, now time.Time) {
+	c.Passes++
+	c.Deleted += result.DeletedCount
+	c.Unliked += result.UnlikedCount
+	c.Unshared += result.UnsharedCount
+	c.Errors += result.ErrorsCount
+	c.LastError = ""
+	c.UpdatedAt = now
+}
+
+// Removed returns how many posts, reposts and likes the wipe has removed so far
+func (c *NukeCheckpoint) Removed() int {
+	return c.Deleted + c.Unliked + c.Unshared
+}
+
+// NukeCheckpointStore keeps a NukeCheckpoint for each account being wiped, one JSON file
+// apiece:
+//
+//	<platform>-<username>.json
+type NukeCheckpointStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewNukeCheckpointStoreAt creates a checkpoint store rooted at the given directory
+func NewNukeCheckpointStoreAt(dir string) *NukeCheckpointStore {
+	return &NukeCheckpointStore{dir: dir}
+}
+
+func (s *NukeCheckpointStore) path(platform, username string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(strings.ToLower(username))
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s.json", strings.ToLower(platform), name))
+}
+
+// Load returns the checkpoint for an account, or nil if no wipe of it has been started
+func (s *NukeCheckpointStore) Load(platform, username string) (*NukeCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(platform, username))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read nuke checkpoint: %w", err)
+	}
+
+	var checkpoint NukeCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse nuke checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint for its account, replacing any earlier one
+func (s *NukeCheckpointStore) Save(checkpoint *NukeCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal nuke checkpoint: %w", err)
+	}
+
+	// Write then rename, so an interruption never leaves a half-written checkpoint
+	path := s.path(checkpoint.Platform, checkpoint.Username)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to write nuke checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write nuke checkpoint: %w", err)
+	}
+	return nil
+}
+
+var (
+	defaultNukeCheckpoints     *NukeCheckpointStore
+	defaultNukeCheckpointsOnce sync.Once
+)
+
+// DefaultNukeCheckpointStore returns the shared checkpoint store in
+// ~/.config/cringesweeper/nuke, or nil if it can't be created
+func DefaultNukeCheckpointStore() *NukeCheckpointStore {
+	defaultNukeCheckpointsOnce.Do(func() {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			Logger.Warn().Err(err).Msg("Nuke checkpoints unavailable")
+			return
+		}
+		defaultNukeCheckpoints = NewNukeCheckpointStoreAt(filepath.Join(homeDir, ".config", "cringesweeper", "nuke"))
+	})
+	return defaultNukeCheckpoints
+}
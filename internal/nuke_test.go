@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNukeOptions(t *testing.T) {
+	withTombstoneStore(t)
+	options := NukeOptions()
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	posts := []Post{
+		{ID: "pinned", Type: PostTypeOriginal, CreatedAt: now.Add(-time.Minute), IsPinned: true, IsLikedByUser: true},
+		{ID: "reply", Type: PostTypeReply, CreatedAt: now.Add(-time.Minute), ReplyCount: 3},
+		{ID: "quote", Type: PostTypeQuote, CreatedAt: now.Add(-time.Minute), SelfQuote: true},
+		{ID: "repost", Type: PostTypeRepost, CreatedAt: now.Add(-time.Minute), SelfRepost: true},
+		{ID: "like", Type: PostTypeLike, CreatedAt: now.Add(-time.Minute)},
+	}
+	for _, post := range posts {
+		selected, reason := options.selectForPrune("mastodon", post, now)
+		if !selected || reason != "" || options.ActionFor(post) == "" {
+			t.Errorf("Expected a nuke to remove %s, got selected=%v reason=%q action=%q", post.ID, selected, reason, options.ActionFor(post))
+		}
+	}
+	if !options.DeleteMedia || !options.ContinueUntilEnd {
+		t.Errorf("Expected a nuke to walk the whole timeline and delete media, got %+v", options)
+	}
+}
+
+func TestNukeCheckpointStore(t *testing.T) {
+	store := NewNukeCheckpointStoreAt(t.TempDir())
+
+	checkpoint, err := store.Load("bluesky", "me.bsky.social")
+	if err != nil || checkpoint != nil {
+		t.Fatalf("Expected no checkpoint before a wipe starts, got %+v, %v", checkpoint, err)
+	}
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	checkpoint = NewNukeCheckpoint("bluesky", "Me.bsky.social", start)
+	checkpoint.LastError = "rate limited"
+	checkpoint.Record(&PruneResult{DeletedCount: 3, UnlikedCount: 2, UnsharedCount: 1, ErrorsCount: 1}, start.Add(time.Hour))
+	checkpoint.Record(&PruneResult{DeletedCount: 1}, start.Add(2*time.Hour))
+	if err := store.Save(checkpoint); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load("Bluesky", "me.bsky.social")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Passes != 2 || loaded.Deleted != 4 || loaded.Removed() != 7 || loaded.Errors != 1 {
+		t.Errorf("Unexpected totals in %+v", loaded)
+	}
+	if loaded.LastError != "" || !loaded.StartedAt.Equal(start) || !loaded.UpdatedAt.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Expected a finished pass to clear the error and move UpdatedAt, got %+v", loaded)
+	}
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+)
+
+// OperatorEnvVar names the environment variable that identifies who is running cringesweeper
+const OperatorEnvVar = "CRINGESWEEPER_OPERATOR"
+
+var (
+	operator   string
+	operatorMu sync.RWMutex
+)
+
+// ResolveOperator picks the operator identity recorded against prune runs: the explicit
+// value if given, then CRINGESWEEPER_OPERATOR, then the OS user, falling back to "unknown".
+// Tabs and newlines are replaced so the identity is safe to write into the tombstone log.
+func ResolveOperator(explicit string) string {
+	identity := strings.TrimSpace(explicit)
+	if identity == "" {
+		identity = strings.TrimSpace(os.Getenv(OperatorEnvVar))
+	}
+	if identity == "" {
+		if current, err := user.Current(); err == nil {
+			identity = current.Username
+		}
+	}
+	if identity == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, identity)
+}
+
+// SetOperator sets the operator identity for this process
+func SetOperator(identity string) {
+	operatorMu.Lock()
+	defer operatorMu.Unlock()
+	operator = identity
+}
+
+// GetOperator returns the operator identity for this process, resolving it from the
+// environment if SetOperator hasn't been called
+func GetOperator() string {
+	operatorMu.RLock()
+	identity := operator
+	operatorMu.RUnlock()
+	if identity == "" {
+		return ResolveOperator("")
+	}
+	return identity
+}
@@ -0,0 +1,48 @@
+package internal
+
+import "testing"
+
+func TestResolveOperator(t *testing.T) {
+	t.Run("explicit value wins", func(t *testing.T) {
+		t.Setenv(OperatorEnvVar, "from-env")
+		if got := ResolveOperator("  alice  "); got != "alice" {
+			t.Errorf("Expected %q, got %q", "alice", got)
+		}
+	})
+
+	t.Run("falls back to environment", func(t *testing.T) {
+		t.Setenv(OperatorEnvVar, "ci-bot")
+		if got := ResolveOperator(""); got != "ci-bot" {
+			t.Errorf("Expected %q, got %q", "ci-bot", got)
+		}
+	})
+
+	t.Run("falls back to OS user", func(t *testing.T) {
+		t.Setenv(OperatorEnvVar, "")
+		if got := ResolveOperator(""); got == "" {
+			t.Error("Expected a non-empty identity")
+		}
+	})
+
+	t.Run("strips tabs and newlines", func(t *testing.T) {
+		if got := ResolveOperator("bob\tsmith\n"); got != "bob smith" {
+			t.Errorf("Expected %q, got %q", "bob smith", got)
+		}
+	})
+}
+
+func TestGetOperator(t *testing.T) {
+	previous := operator
+	t.Cleanup(func() { SetOperator(previous) })
+
+	SetOperator("deploy-bot")
+	if got := GetOperator(); got != "deploy-bot" {
+		t.Errorf("Expected %q, got %q", "deploy-bot", got)
+	}
+
+	SetOperator("")
+	t.Setenv(OperatorEnvVar, "from-env")
+	if got := GetOperator(); got != "from-env" {
+		t.Errorf("Expected unset operator to resolve from environment, got %q", got)
+	}
+}
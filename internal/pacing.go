@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pacingAdviceThreshold is how long a run can be expected to take before the plan
+// suggests splitting it up
+const pacingAdviceThreshold = time.Hour
+
+// PacingPlan estimates how long a prune's actions will take at its rate limit delay
+type PacingPlan struct {
+	Deletes  int
+	Unlikes  int
+	Unshares int
+	Redacts  int
+	Delay    time.Duration
+	Batch    int           // Actions sent together per delay (0 or 1 when each is sent alone)
+	Budget   time.Duration // Time left before --max-runtime stops the run (zero for no limit)
+
+	DeleteLimit RateWindow // The platform's own cap on deletions and unshares, which they wait for once used up
+}
+
+// planPacing counts the actions a prune over posts will take
+func planPacing(platform string, posts []Post, options PruneOptions, now time.Time) PacingPlan {
+	plan := PacingPlan{Delay: options.RateLimitDelay, DeleteLimit: mastodonFlavors[platform].deleteLimit}
+	if options.BatchWrites && platform == "bluesky" {
+		plan.Batch = blueskyMaxBatchWrites
+	}
+	if !options.Deadline.IsZero() {
+		plan.Budget = max(options.Deadline.Sub(now), 0)
+	}
+	for _, post := range posts {
+		if selected, preserveReason := options.selectForPrune(platform, post, now); !selected || preserveReason != "" {
+			continue
+		}
+		switch options.ActionFor(post) {
+		case "delete":
+			plan.Deletes++
+		case "redact":
+			plan.Redacts++
+		case "unlike":
+			plan.Unlikes++
+		case "unshare":
+			plan.Unshares++
+		}
+	}
+	return plan
+}
+
+// Actions is the total number of posts the run will act on
+func (p PacingPlan) Actions() int {
+	return p.Deletes + p.Unlikes + p.Unshares + p.Redacts
+}
+
+// requests is how many delayed requests the run's actions are sent in
+func (p PacingPlan) requests() int {
+	if p.Batch <= 1 {
+		return p.Actions()
+	}
+	return (p.Actions() + p.Batch - 1) / p.Batch
+}
+
+// limitedActions is how many of the run's actions DeleteLimit counts. Mastodon undoes a
+// reblog by deleting its status, so unshares count along with deletes.
+func (p PacingPlan) limitedActions() int {
+	return p.Deletes + p.Unshares
+}
+
+// deleteLimited returns true if the run deletes more than DeleteLimit allows at once
+func (p PacingPlan) deleteLimited() bool {
+	return p.DeleteLimit.Limit > 0 && p.limitedActions() > p.DeleteLimit.Limit
+}
+
+// Duration is how long the run's delays add up to, or the waits for DeleteLimit if
+// those are longer. Request time isn't included, so real runs take a little longer.
+func (p PacingPlan) Duration() time.Duration {
+	d := time.Duration(p.requests()) * p.Delay
+	if p.deleteLimited() {
+		d = max(d, time.Duration((p.limitedActions()-1)/p.DeleteLimit.Limit)*p.DeleteLimit.Window)
+	}
+	return d
+}
+
+// String describes the plan, such as "~1,240 deletions at 60s delay ≈ 20.7 hours",
+// with advice when the run is long or won't fit in its --max-runtime
+func (p PacingPlan) String() string {
+	var counts []string
+	for _, count := range []struct {
+		n    int
+		noun string
+	}{{p.Deletes, "deletion"}, {p.Unlikes, "unlike"}, {p.Unshares, "unshare"}, {p.Redacts, "redaction"}} {
+		if count.n > 0 {
+			counts = append(counts, formatCount(count.n)+" "+pluralize(count.n, count.noun))
+		}
+	}
+
+	delay := p.Delay.String()
+	if p.Delay < time.Hour && p.Delay%time.Second == 0 {
+		delay = fmt.Sprintf("%ds", int(p.Delay.Seconds())) // "60s" rather than "1m0s"
+	}
+	batches := ""
+	if p.Batch > 1 {
+		batches = fmt.Sprintf(" in batches of %d", p.Batch)
+	}
+	limits := ""
+	if p.deleteLimited() {
+		limits = fmt.Sprintf(", %d deletions per %s", p.DeleteLimit.Limit, formatWindow(p.DeleteLimit.Window))
+	}
+	plan := fmt.Sprintf("~%s%s at %s delay%s ≈ %s", strings.Join(counts, ", "), batches, delay, limits, formatPacingDuration(p.Duration()))
+	switch {
+	case p.Budget > 0 && p.Duration() > p.Budget:
+		fitting := p.Actions()
+		if p.Delay > 0 {
+			fitting = int(p.Budget/p.Delay) * max(p.Batch, 1)
+		}
+		if p.deleteLimited() {
+			fitting = min(fitting, p.Actions()-p.limitedActions()+(int(p.Budget/p.DeleteLimit.Window)+1)*p.DeleteLimit.Limit)
+		}
+		plan += fmt.Sprintf("; --max-runtime allows about %s this run, later runs will carry on", formatCount(fitting))
+	case p.Budget == 0 && p.Duration() > pacingAdviceThreshold:
+		plan += "; consider --max-runtime to spread it over several runs, or server mode"
+	}
+	return plan
+}
+
+// printPacingPlan shows how long the run's actions will take before any are started
+func printPacingPlan(platform string, plan PacingPlan, options PruneOptions) {
+	if plan.Actions() == 0 {
+		return
+	}
+	WithPlatform(platform).Info().
+		Int("deletes", plan.Deletes).
+		Int("unlikes", plan.Unlikes).
+		Int("unshares", plan.Unshares).
+		Int("redacts", plan.Redacts).
+		Dur("delay", plan.Delay).
+		Dur("estimate", plan.Duration()).
+		Msg("Prune pacing plan")
+	if options.DryRun {
+		fmt.Fprintf(options.output(), "⏳ A real run would take: %s\n", plan)
+	} else {
+		fmt.Fprintf(options.output(), "⏳ Pacing: %s\n", plan)
+	}
+}
+
+// formatPacingDuration rounds an estimate to a unit that reads naturally
+func formatPacingDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return d.Round(time.Second).String()
+	case d < time.Hour:
+		minutes := int(d.Round(time.Minute).Minutes())
+		return fmt.Sprintf("%d %s", minutes, pluralize(minutes, "minute"))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	default:
+		return fmt.Sprintf("%.1f days", d.Hours()/24)
+	}
+}
+
+// formatCount writes n with thousands separators, such as 1,240
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
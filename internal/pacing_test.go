@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanPacing(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	old := now.Add(-60 * 24 * time.Hour)
+
+	posts := []Post{
+		{ID: "1", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "2", Type: PostTypeReply, CreatedAt: old},
+		{ID: "3", Type: PostTypeRepost, CreatedAt: old},
+		{ID: "4", Type: PostTypeLike, CreatedAt: old},
+		{ID: "5", Type: PostTypeOriginal, CreatedAt: old, IsPinned: true},
+		{ID: "6", Type: PostTypeOriginal, CreatedAt: now.Add(-time.Hour)},
+	}
+	options := PruneOptions{
+		MaxAge:         &maxAge,
+		PreservePinned: true,
+		RateLimitDelay: time.Minute,
+		Deadline:       now.Add(2 * time.Minute),
+	}
+
+	plan := planPacing("bluesky", posts, options, now)
+	if plan.Deletes != 2 || plan.Unshares != 1 || plan.Unlikes != 1 {
+		t.Errorf("Expected 2 deletes, 1 unshare and 1 unlike, got %+v", plan)
+	}
+	if plan.Duration() != 4*time.Minute {
+		t.Errorf("Expected 4m, got %v", plan.Duration())
+	}
+	if plan.Budget != 2*time.Minute {
+		t.Errorf("Expected a 2m budget, got %v", plan.Budget)
+	}
+
+	// Batched writes go out together, one delay per batch
+	batched := options
+	batched.BatchWrites = true
+	if plan := planPacing("bluesky", posts, batched, now); plan.Batch != blueskyMaxBatchWrites || plan.Duration() != time.Minute {
+		t.Errorf("Expected one batch taking 1m, got %+v taking %v", plan, plan.Duration())
+	}
+	if plan := planPacing("mastodon", posts, batched, now); plan.Batch != 0 {
+		t.Errorf("Expected no batching on Mastodon, got %+v", plan)
+	}
+
+	// Mastodon's deletion limit covers unshares as well as deletes
+	if plan := planPacing("mastodon", posts, options, now); plan.limitedActions() != 3 || plan.DeleteLimit.Limit == 0 {
+		t.Errorf("Expected 3 actions under Mastodon's delete limit, got %+v", plan)
+	}
+
+	// Posts picked in review narrow the run down further
+	options.OnlyPostIDs = map[string]bool{"2": true, "5": true, "6": true}
+	if plan := planPacing("bluesky", posts, options, now); plan.Actions() != 1 || plan.Deletes != 1 {
+		t.Errorf("Expected only post 2 to be acted on, got %+v", plan)
+	}
+}
+
+func TestPacingPlan_String(t *testing.T) {
+	tests := []struct {
+		name string
+		plan PacingPlan
+		want string
+	}{
+		{
+			name: "long run",
+			plan: PacingPlan{Deletes: 1240, Delay: time.Minute},
+			want: "~1,240 deletions at 60s delay ≈ 20.7 hours; consider --max-runtime to spread it over several runs, or server mode",
+		},
+		{
+			name: "short run",
+			plan: PacingPlan{Deletes: 1, Unshares: 12, Delay: time.Second},
+			want: "~1 deletion, 12 unshares at 1s delay ≈ 13s",
+		},
+		{
+			name: "minutes",
+			plan: PacingPlan{Unlikes: 30, Delay: time.Minute},
+			want: "~30 unlikes at 60s delay ≈ 30 minutes",
+		},
+		{
+			name: "days",
+			plan: PacingPlan{Deletes: 5000, Delay: time.Minute},
+			want: "~5,000 deletions at 60s delay ≈ 3.5 days; consider --max-runtime to spread it over several runs, or server mode",
+		},
+		{
+			name: "doesn't fit max runtime",
+			plan: PacingPlan{Deletes: 100, Delay: time.Minute, Budget: 45 * time.Minute},
+			want: "~100 deletions at 60s delay ≈ 1.7 hours; --max-runtime allows about 45 this run, later runs will carry on",
+		},
+		{
+			name: "fits max runtime",
+			plan: PacingPlan{Deletes: 100, Delay: time.Minute, Budget: 2 * time.Hour},
+			want: "~100 deletions at 60s delay ≈ 1.7 hours",
+		},
+		{
+			name: "batched",
+			plan: PacingPlan{Deletes: 1240, Unlikes: 10, Delay: time.Second, Batch: 200},
+			want: "~1,240 deletions, 10 unlikes in batches of 200 at 1s delay ≈ 7s",
+		},
+		{
+			name: "batched doesn't fit max runtime",
+			plan: PacingPlan{Deletes: 5000, Delay: time.Minute, Batch: 200, Budget: 10 * time.Minute},
+			want: "~5,000 deletions in batches of 200 at 60s delay ≈ 25 minutes; --max-runtime allows about 2,000 this run, later runs will carry on",
+		},
+		{
+			name: "within delete limit",
+			plan: PacingPlan{Deletes: 20, Delay: 2 * time.Second, DeleteLimit: RateWindow{Limit: 30, Window: 30 * time.Minute}},
+			want: "~20 deletions at 2s delay ≈ 40s",
+		},
+		{
+			name: "past delete limit",
+			plan: PacingPlan{Deletes: 100, Unlikes: 10, Delay: 2 * time.Second, DeleteLimit: RateWindow{Limit: 30, Window: 30 * time.Minute}},
+			want: "~100 deletions, 10 unlikes at 2s delay, 30 deletions per 30m ≈ 1.5 hours; consider --max-runtime to spread it over several runs, or server mode",
+		},
+		{
+			name: "unshares count against delete limit",
+			plan: PacingPlan{Deletes: 20, Unshares: 20, Unlikes: 10, Delay: 2 * time.Second, DeleteLimit: RateWindow{Limit: 30, Window: 30 * time.Minute}},
+			want: "~20 deletions, 10 unlikes, 20 unshares at 2s delay, 30 deletions per 30m ≈ 30 minutes",
+		},
+		{
+			name: "delete limit doesn't fit max runtime",
+			plan: PacingPlan{Deletes: 100, Unlikes: 10, Delay: 2 * time.Second, DeleteLimit: RateWindow{Limit: 30, Window: 30 * time.Minute}, Budget: 45 * time.Minute},
+			want: "~100 deletions, 10 unlikes at 2s delay, 30 deletions per 30m ≈ 1.5 hours; --max-runtime allows about 70 this run, later runs will carry on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plan.String(); got != tt.want {
+				t.Errorf("String() = %q\nwant       %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 1240: "1,240", 1234567: "1,234,567"}
+	for n, want := range tests {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+// Largest page each platform's timeline API returns. Mastodon quietly caps larger
+// requests at 40, and GoToSocial follows suit.
+var maxPageSizes = map[string]int{
+	"bluesky":    100,
+	"mastodon":   40,
+	"gotosocial": 40,
+}
+
+// initialPruneScanPageSize is the first page size when a prune walks back through a timeline
+const initialPruneScanPageSize = 20
+
+// MaxPageSize returns the largest page of posts the platform will return in one request.
+// Platforms without a limit of their own, such as local archives, get 100.
+func MaxPageSize(platform string) int {
+	if size, ok := maxPageSizes[platform]; ok {
+		return size
+	}
+	return 100
+}
+
+// PageSizeRamp hands out page sizes for a walk through a timeline. The first page is
+// small so the newest posts show up quickly, then each page doubles up to the maximum,
+// so deep scans of accounts with thousands of posts take far fewer requests.
+type PageSizeRamp struct {
+	next int
+	max  int
+}
+
+// NewPageSizeRamp creates a ramp starting at start and growing to max. A start above
+// max is kept as it is, since the caller asked for that size explicitly.
+func NewPageSizeRamp(start, max int) *PageSizeRamp {
+	if start < 1 {
+		start = 1
+	}
+	if start > max {
+		max = start
+	}
+	return &PageSizeRamp{next: start, max: max}
+}
+
+// Next returns the size to request for the next page
+func (r *PageSizeRamp) Next() int {
+	size := r.next
+	r.next = min(r.next*2, r.max)
+	return size
+}
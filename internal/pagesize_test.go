@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPageSizeRamp(t *testing.T) {
+	tests := []struct {
+		name  string
+		start int
+		max   int
+		want  []int
+	}{
+		{"doubles up to the maximum", 10, 100, []int{10, 20, 40, 80, 100, 100}},
+		{"starts at the maximum", 40, 40, []int{40, 40, 40}},
+		{"explicit start above the maximum is kept", 200, 100, []int{200, 200}},
+		{"zero start begins at one", 0, 4, []int{1, 2, 4, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ramp := NewPageSizeRamp(tt.start, tt.max)
+			var got []int
+			for range tt.want {
+				got = append(got, ramp.Next())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("page sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxPageSize(t *testing.T) {
+	tests := map[string]int{
+		"bluesky":  100,
+		"mastodon": 40,
+		"twitter":  100,
+	}
+	for platform, want := range tests {
+		if got := MaxPageSize(platform); got != want {
+			t.Errorf("MaxPageSize(%q) = %d, want %d", platform, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// prunePlanVersion is the format version written to plan files, so a later format
+// change can refuse plans it doesn't understand rather than misreading them
+const prunePlanVersion = 1
+
+// PrunePlan is the set of actions a dry run would take, written out for review with
+// prune --plan-out and carried out with prune --apply-plan. Removing an action from the
+// file means it isn't taken.
+type PrunePlan struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Platforms []PlatformPlan `json:"platforms"`
+}
+
+// PlatformPlan holds one account's planned actions, and the criteria they were found
+// with. The criteria are checked again when the plan is applied, so posts that changed
+// in between (a new like, say) are still protected.
+type PlatformPlan struct {
+	Platform string          `json:"platform"`
+	Username string          `json:"username"`
+	Options  PruneOptions    `json:"options"`
+	Actions  []PlannedAction `json:"actions"`
+}
+
+// PlannedAction is one action in a plan. Only Action and ID are used when applying it;
+// the rest is there so the file can be reviewed.
+type PlannedAction struct {
+	Action    string    `json:"action"` // "delete", "redact", "unlike" or "unshare"
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	URL       string    `json:"url,omitempty"`
+	Content   string    `json:"content,omitempty"`
+}
+
+// NewPlatformPlan records the posts a dry run found to act on. Settings that only
+// concern how a run is carried out (dry run, batching, progress, deadline and
+// prompting) are left out of the stored options, as they're given when the plan is
+// applied.
+func NewPlatformPlan(platform, username string, options PruneOptions, result *PruneResult) PlatformPlan {
+	options.DryRun = false
+	options.BatchWrites = false
+	options.ProgressEvery = 0
+	options.ProgressInterval = 0
+	options.Deadline = time.Time{}
+	options.Confirm = nil
+	options.OnlyPostIDs = nil
+	options.PlannedActions = nil
+
+	plan := PlatformPlan{Platform: platform, Username: username, Options: options, Actions: []PlannedAction{}}
+	for _, posts := range [][]Post{result.PostsToDelete, result.PostsToRedact, result.PostsToUnlike, result.PostsToUnshare} {
+		for _, post := range posts {
+			plan.Actions = append(plan.Actions, PlannedAction{
+				Action:    options.ActionFor(post),
+				ID:        post.ID,
+				CreatedAt: post.CreatedAt,
+				URL:       post.URL,
+				Content:   post.Content,
+			})
+		}
+	}
+	return plan
+}
+
+// PostIDs returns the IDs of the posts the plan acts on, for PruneOptions.OnlyPostIDs
+func (p PlatformPlan) PostIDs() map[string]bool {
+	ids := make(map[string]bool, len(p.Actions))
+	for _, action := range p.Actions {
+		ids[action.ID] = true
+	}
+	return ids
+}
+
+// PlannedActions returns the action planned for each post by ID, for
+// PruneOptions.PlannedActions
+func (p PlatformPlan) PlannedActions() map[string]string {
+	actions := make(map[string]string, len(p.Actions))
+	for _, action := range p.Actions {
+		actions[action.ID] = action.Action
+	}
+	return actions
+}
+
+// checkPlannedActions returns an error if any post the run would act on has a different
+// action in PlannedActions than the one the run would take
+func (o PruneOptions) checkPlannedActions(platform string, posts []Post, now time.Time) error {
+	if o.PlannedActions == nil {
+		return nil
+	}
+	for _, post := range posts {
+		planned, ok := o.PlannedActions[post.ID]
+		if !ok {
+			continue
+		}
+		if selected, preserveReason := o.selectForPrune(platform, post, now); !selected || preserveReason != "" {
+			continue
+		}
+		if action := o.ActionFor(post); action != planned {
+			return fmt.Errorf("the plan says to %s post %s on %s, but its criteria would %s it; write a new plan with --plan-out rather than editing actions", planned, post.ID, platform, action)
+		}
+	}
+	return nil
+}
+
+// WritePrunePlan writes a plan to path as indented JSON
+func WritePrunePlan(path string, plan *PrunePlan) error {
+	plan.Version = prunePlanVersion
+
+	// Post content is left readable, rather than having <, > and & escaped
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// ReadPrunePlan reads a plan written by WritePrunePlan, and possibly edited since. It
+// rejects plans with actions it wouldn't know how to take, rather than skipping them.
+func ReadPrunePlan(path string) (*PrunePlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan PrunePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.Version != prunePlanVersion {
+		return nil, fmt.Errorf("plan %s has version %d, but only version %d is supported", path, plan.Version, prunePlanVersion)
+	}
+
+	seen := make(map[string]bool)
+	for _, platformPlan := range plan.Platforms {
+		if _, ok := GetClient(platformPlan.Platform); !ok {
+			return nil, fmt.Errorf("plan %s: unsupported platform %q", path, platformPlan.Platform)
+		}
+		if seen[platformPlan.Platform] {
+			return nil, fmt.Errorf("plan %s: %s is listed more than once", path, platformPlan.Platform)
+		}
+		seen[platformPlan.Platform] = true
+		if platformPlan.Username == "" {
+			return nil, fmt.Errorf("plan %s: %s has no username", path, platformPlan.Platform)
+		}
+		for i, action := range platformPlan.Actions {
+			switch {
+			case action.ID == "":
+				return nil, fmt.Errorf("plan %s: %s action %d has no id", path, platformPlan.Platform, i+1)
+			case action.Action != "delete" && action.Action != "redact" && action.Action != "unlike" && action.Action != "unshare":
+				return nil, fmt.Errorf("plan %s: %s action %d: unknown action %q (want delete, redact, unlike or unshare)", path, platformPlan.Platform, i+1, action.Action)
+			}
+		}
+	}
+	return &plan, nil
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrunePlan_RoundTrip(t *testing.T) {
+	maxAge := 30 * 24 * time.Hour
+	created := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	options := PruneOptions{MaxAge: &maxAge, PreservePinned: true, DryRun: true, ProgressEvery: 100, Deadline: created}
+	result := &PruneResult{
+		PostsToDelete:  []Post{{ID: "1", Type: PostTypeOriginal, Content: "<b>old</b> & gone", CreatedAt: created}},
+		PostsToUnlike:  []Post{{ID: "2", Type: PostTypeLike, CreatedAt: created}},
+		PostsToUnshare: []Post{{ID: "3", Type: PostTypeRepost, CreatedAt: created}},
+		PostsPreserved: []Post{{ID: "4", Type: PostTypeOriginal, IsPinned: true, CreatedAt: created}},
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	plan := &PrunePlan{CreatedAt: created, Platforms: []PlatformPlan{NewPlatformPlan("bluesky", "me.bsky.social", options, result)}}
+	if err := WritePrunePlan(path, plan); err != nil {
+		t.Fatalf("WritePrunePlan() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<b>old</b> & gone") {
+		t.Errorf("Expected post content to be written unescaped:\n%s", data)
+	}
+
+	got, err := ReadPrunePlan(path)
+	if err != nil {
+		t.Fatalf("ReadPrunePlan() error = %v", err)
+	}
+	if len(got.Platforms) != 1 {
+		t.Fatalf("Expected 1 platform, got %d", len(got.Platforms))
+	}
+	platformPlan := got.Platforms[0]
+	if platformPlan.Username != "me.bsky.social" || platformPlan.Options.MaxAge == nil || *platformPlan.Options.MaxAge != maxAge || !platformPlan.Options.PreservePinned {
+		t.Errorf("Expected the criteria to survive, got %+v", platformPlan)
+	}
+	if platformPlan.Options.DryRun || platformPlan.Options.ProgressEvery != 0 || !platformPlan.Options.Deadline.IsZero() {
+		t.Errorf("Expected run settings to be left out of the plan, got %+v", platformPlan.Options)
+	}
+
+	var actions []string
+	for _, action := range platformPlan.Actions {
+		actions = append(actions, action.Action+":"+action.ID)
+	}
+	if want := "delete:1,unlike:2,unshare:3"; strings.Join(actions, ",") != want {
+		t.Errorf("Actions = %v, want %s", actions, want)
+	}
+	if ids := platformPlan.PostIDs(); len(ids) != 3 || !ids["1"] || !ids["2"] || !ids["3"] {
+		t.Errorf("PostIDs() = %v", ids)
+	}
+}
+
+func TestReadPrunePlan_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "not JSON",
+			content: "delete everything",
+			wantErr: "failed to parse plan",
+		},
+		{
+			name:    "unknown version",
+			content: `{"version": 2, "platforms": []}`,
+			wantErr: "only version 1 is supported",
+		},
+		{
+			name:    "unknown platform",
+			content: `{"version": 1, "platforms": [{"platform": "myspace", "username": "me"}]}`,
+			wantErr: `unsupported platform "myspace"`,
+		},
+		{
+			name:    "platform listed twice",
+			content: `{"version": 1, "platforms": [{"platform": "bluesky", "username": "me"}, {"platform": "bluesky", "username": "me"}]}`,
+			wantErr: "bluesky is listed more than once",
+		},
+		{
+			name:    "missing username",
+			content: `{"version": 1, "platforms": [{"platform": "bluesky"}]}`,
+			wantErr: "bluesky has no username",
+		},
+		{
+			name:    "unknown action",
+			content: `{"version": 1, "platforms": [{"platform": "bluesky", "username": "me", "actions": [{"action": "delete", "id": "1"}, {"action": "archive", "id": "2"}]}]}`,
+			wantErr: `bluesky action 2: unknown action "archive"`,
+		},
+		{
+			name:    "missing id",
+			content: `{"version": 1, "platforms": [{"platform": "mastodon", "username": "me", "actions": [{"action": "unlike"}]}]}`,
+			wantErr: "mastodon action 1 has no id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadPrunePlan(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadPrunePlan() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoPostIndex is returned when reading a local post index that hasn't been synced yet
+var ErrNoPostIndex = errors.New("no local post index")
+
+// PostIndex is a local copy of one account's timeline, kept up to date by sync so that
+// listing, stats and prune selection don't have to walk the timeline over the network
+type PostIndex struct {
+	Platform string    `json:"platform"`
+	Username string    `json:"username"`
+	SyncedAt time.Time `json:"synced_at"` // When the index was last brought up to date
+	Posts    []Post    `json:"posts"`     // Newest first
+
+	byID map[string]int // Position of each post in Posts, built on first lookup
+}
+
+// positions returns the position of each post in Posts by ID. Merge and DropDeleted keep
+// it current; anything else that replaces Posts must call resetPositions.
+func (idx *PostIndex) positions() map[string]int {
+	if idx.byID == nil {
+		idx.byID = make(map[string]int, len(idx.Posts))
+		for i, post := range idx.Posts {
+			idx.byID[post.ID] = i
+		}
+	}
+	return idx.byID
+}
+
+func (idx *PostIndex) resetPositions() {
+	idx.byID = nil
+}
+
+// Contains returns true if a post with the given ID is in the index
+func (idx *PostIndex) Contains(id string) bool {
+	_, ok := idx.positions()[id]
+	return ok
+}
+
+// Merge adds posts to the index, replacing any it already holds with the same ID so
+// engagement counts and edits stay current. It returns how many posts were new.
+func (idx *PostIndex) Merge(posts []Post) int {
+	positions := idx.positions()
+	added := 0
+	for _, post := range posts {
+		if i, ok := positions[post.ID]; ok {
+			idx.Posts[i] = post
+			continue
+		}
+		positions[post.ID] = len(idx.Posts)
+		idx.Posts = append(idx.Posts, post)
+		added++
+	}
+
+	sort.SliceStable(idx.Posts, func(i, j int) bool {
+		return idx.Posts[i].CreatedAt.After(idx.Posts[j].CreatedAt)
+	})
+	idx.resetPositions()
+	return added
+}
+
+// DropDeleted removes the posts cringesweeper has since deleted, as recorded in the
+// tombstone index, and returns how many it removed
+func (idx *PostIndex) DropDeleted() int {
+	kept := idx.Posts[:0]
+	for _, post := range idx.Posts {
+		if !wasDeleted(idx.Platform, post.ID) {
+			kept = append(kept, post)
+		}
+	}
+	dropped := len(idx.Posts) - len(kept)
+	idx.Posts = kept
+	if dropped > 0 {
+		idx.resetPositions()
+	}
+	return dropped
+}
+
+// DropDeletedFromIndex removes the posts cringesweeper has deleted from an account's
+// index, if it has one, so the index doesn't go on listing them after a prune
+func DropDeletedFromIndex(platform, username string) error {
+	store := DefaultPostIndexStore()
+	if store == nil {
+		return nil
+	}
+	idx, err := store.Load(platform, username)
+	if errors.Is(err, ErrNoPostIndex) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if idx.DropDeleted() == 0 {
+		return nil
+	}
+	return store.Save(idx)
+}
+
+// PostIndexStore keeps a PostIndex for each synced account, one JSON file apiece:
+//
+//	<platform>-<username>.json
+type PostIndexStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewPostIndexStoreAt creates a post index store rooted at the given directory
+func NewPostIndexStoreAt(dir string) *PostIndexStore {
+	return &PostIndexStore{dir: dir}
+}
+
+func (s *PostIndexStore) path(platform, username string) string {
+	// Handles can't contain slashes, but don't let a stray one escape the directory
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(strings.ToLower(username))
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s.json", strings.ToLower(platform), name))
+}
+
+// Load returns the index for an account, wrapping ErrNoPostIndex if it hasn't been synced
+func (s *PostIndexStore) Load(platform, username string) (*PostIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(platform, username))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w for %s on %s", ErrNoPostIndex, username, platform)
+		}
+		return nil, fmt.Errorf("failed to read post index: %w", err)
+	}
+
+	var idx PostIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse post index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index for its account, replacing any earlier copy
+func (s *PostIndexStore) Save(idx *PostIndex) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal post index: %w", err)
+	}
+
+	// Write then rename, so a crash never leaves a half-written file
+	path := s.path(idx.Platform, idx.Username)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to write post index: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write post index: %w", err)
+	}
+	return nil
+}
+
+var (
+	defaultPostIndexes     *PostIndexStore
+	defaultPostIndexesOnce sync.Once
+)
+
+// DefaultPostIndexStore returns the shared post index store in
+// ~/.config/cringesweeper/index, or nil if it can't be created
+func DefaultPostIndexStore() *PostIndexStore {
+	defaultPostIndexesOnce.Do(func() {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			Logger.Warn().Err(err).Msg("Post index unavailable")
+			return
+		}
+		defaultPostIndexes = NewPostIndexStoreAt(filepath.Join(homeDir, ".config", "cringesweeper", "index"))
+	})
+	return defaultPostIndexes
+}
+
+// loadDefaultPostIndex returns an account's index from the default store
+func loadDefaultPostIndex(platform, username string) (*PostIndex, error) {
+	store := DefaultPostIndexStore()
+	if store == nil {
+		return nil, fmt.Errorf("%w for %s on %s", ErrNoPostIndex, username, platform)
+	}
+	return store.Load(platform, username)
+}
+
+// indexedPrunePosts returns the posts a prune with FromIndex starts from: the account's
+// indexed timeline, less likes, which prune lists from the platform when unliking
+func indexedPrunePosts(platform, username string) ([]Post, error) {
+	idx, err := loadDefaultPostIndex(platform, username)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]Post, 0, len(idx.Posts))
+	for _, post := range idx.Posts {
+		if post.Type != PostTypeLike {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+// SyncPostIndex brings idx up to date with the account's timeline. Unless full is set, it
+// stops at the first page holding nothing new, so a regular sync only fetches what was
+// posted since the last one. A full sync walks the whole timeline and drops posts that
+// are no longer on it. It returns how many posts were added.
+func SyncPostIndex(ctx context.Context, reader PostReader, idx *PostIndex, full bool, now time.Time) (int, error) {
+	known := make(map[string]bool)
+	var fetched []Post
+	added := 0
+	cursor := ""
+	for {
+		posts, nextCursor, err := reader.FetchUserPostsPaginated(ctx, idx.Username, MaxPageSize(idx.Platform), cursor)
+		if err != nil {
+			return 0, err
+		}
+
+		fresh := 0
+		for _, post := range posts {
+			if !known[post.ID] && !idx.Contains(post.ID) {
+				known[post.ID] = true
+				fresh++
+			}
+		}
+		added += fresh
+		fetched = append(fetched, posts...)
+
+		if len(posts) == 0 || nextCursor == "" || nextCursor == cursor {
+			break
+		}
+		if !full && fresh == 0 && len(idx.Posts) > 0 {
+			break // Caught up with the last sync
+		}
+		cursor = nextCursor
+	}
+
+	if full {
+		idx.Posts = nil
+		idx.resetPositions()
+	}
+	idx.Merge(fetched)
+	idx.DropDeleted()
+	idx.SyncedAt = now
+	return added, nil
+}
+
+// IndexReader is a PostReader over a local post index, so listing and stats can work
+// from the index instead of the network. Cursors are offsets into the index.
+type IndexReader struct {
+	index    *PostIndex
+	platform string
+}
+
+// NewIndexReader creates a reader over idx that reports the given platform name
+func NewIndexReader(idx *PostIndex, platform string) *IndexReader {
+	return &IndexReader{index: idx, platform: platform}
+}
+
+// FetchUserPosts returns the newest posts in the index
+func (r *IndexReader) FetchUserPosts(ctx context.Context, username string, limit int) ([]Post, error) {
+	posts, _, err := r.FetchUserPostsPaginated(ctx, username, limit, "")
+	return posts, err
+}
+
+// FetchUserPostsPaginated returns the next limit posts in the index after cursor
+func (r *IndexReader) FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]Post, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("invalid index cursor %q", cursor)
+		}
+	}
+	if offset >= len(r.index.Posts) {
+		return nil, "", nil
+	}
+
+	end := min(offset+limit, len(r.index.Posts))
+	next := ""
+	if end < len(r.index.Posts) {
+		next = strconv.Itoa(end)
+	}
+	return r.index.Posts[offset:end], next, nil
+}
+
+// GetPlatformName returns the platform name of the indexed account
+func (r *IndexReader) GetPlatformName() string {
+	return r.platform
+}
@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// pagedReader serves a fixed newest-first timeline a page at a time, counting requests
+type pagedReader struct {
+	posts    []Post
+	requests int
+}
+
+func (r *pagedReader) FetchUserPosts(ctx context.Context, username string, limit int) ([]Post, error) {
+	posts, _, err := r.FetchUserPostsPaginated(ctx, username, limit, "")
+	return posts, err
+}
+
+func (r *pagedReader) FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]Post, string, error) {
+	r.requests++
+	return NewIndexReader(&PostIndex{Posts: r.posts}, "").FetchUserPostsPaginated(ctx, username, limit, cursor)
+}
+
+func (r *pagedReader) GetPlatformName() string {
+	return "Fake"
+}
+
+// timeline returns n posts, newest first, each an hour older than the last
+func timeline(prefix string, n int, newest time.Time) []Post {
+	posts := make([]Post, n)
+	for i := range posts {
+		posts[i] = Post{ID: fmt.Sprintf("%s-%d", prefix, i), Type: PostTypeOriginal, CreatedAt: newest.Add(-time.Duration(i) * time.Hour)}
+	}
+	return posts
+}
+
+func TestPostIndexStore(t *testing.T) {
+	store := NewPostIndexStoreAt(t.TempDir())
+
+	if _, err := store.Load("mastodon", "me@example.social"); !errors.Is(err, ErrNoPostIndex) {
+		t.Fatalf("Expected ErrNoPostIndex before the first sync, got %v", err)
+	}
+
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	idx := &PostIndex{Platform: "mastodon", Username: "Me@example.social", SyncedAt: now, Posts: timeline("p", 3, now)}
+	if err := store.Save(idx); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load("Mastodon", "me@example.social")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Posts) != 3 || !loaded.SyncedAt.Equal(now) || loaded.Posts[0].ID != "p-0" {
+		t.Errorf("Loaded index doesn't match what was saved: %+v", loaded)
+	}
+}
+
+func TestPostIndex_Merge(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	idx := &PostIndex{Posts: timeline("p", 2, now.Add(-time.Hour))}
+
+	updated := idx.Posts[0]
+	updated.LikeCount = 7
+	added := idx.Merge([]Post{{ID: "new", CreatedAt: now}, updated})
+	if added != 1 {
+		t.Errorf("Expected 1 new post, got %d", added)
+	}
+	if len(idx.Posts) != 3 || idx.Posts[0].ID != "new" || idx.Posts[1].LikeCount != 7 {
+		t.Errorf("Expected the new post first and the existing one updated, got %+v", idx.Posts)
+	}
+}
+
+func TestPostIndex_Contains(t *testing.T) {
+	store := withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	idx := &PostIndex{Platform: "bluesky", Posts: timeline("p", 3, now)}
+
+	// Look up before changing the index, so a stale lookup table would show
+	if !idx.Contains("p-2") || idx.Contains("new") {
+		t.Fatal("Expected Contains to find only indexed posts")
+	}
+	idx.Merge([]Post{{ID: "new", CreatedAt: now.Add(time.Hour)}})
+	if !idx.Contains("new") || !idx.Contains("p-2") {
+		t.Error("Expected Contains to find merged and existing posts")
+	}
+
+	if err := store.Record("bluesky", TombstoneActionDeleted, "p-2", "", now); err != nil {
+		t.Fatal(err)
+	}
+	idx.DropDeleted()
+	if idx.Contains("p-2") || !idx.Contains("p-1") {
+		t.Error("Expected Contains to forget dropped posts")
+	}
+}
+
+func TestSyncPostIndex(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	pageSize := MaxPageSize("mastodon")
+	reader := &pagedReader{posts: timeline("old", pageSize*3, now.Add(-24*time.Hour))}
+	idx := &PostIndex{Platform: "mastodon", Username: "me"}
+
+	// The first sync walks the whole timeline
+	added, err := SyncPostIndex(context.Background(), reader, idx, false, now)
+	if err != nil {
+		t.Fatalf("SyncPostIndex() error = %v", err)
+	}
+	if added != pageSize*3 || len(idx.Posts) != pageSize*3 || reader.requests != 3 || !idx.SyncedAt.Equal(now) {
+		t.Errorf("First sync added %d posts of %d in %d requests", added, len(idx.Posts), reader.requests)
+	}
+
+	// A later one stops once it reaches posts it already has
+	reader.posts = append(timeline("new", 2, now), reader.posts...)
+	reader.requests = 0
+	added, err = SyncPostIndex(context.Background(), reader, idx, false, now)
+	if err != nil {
+		t.Fatalf("SyncPostIndex() error = %v", err)
+	}
+	if added != 2 || idx.Posts[0].ID != "new-0" || reader.requests != 2 {
+		t.Errorf("Incremental sync added %d posts in %d requests, newest %s", added, reader.requests, idx.Posts[0].ID)
+	}
+
+	// A full sync drops posts that are gone from the timeline
+	reader.posts = reader.posts[:5]
+	if _, err := SyncPostIndex(context.Background(), reader, idx, true, now); err != nil {
+		t.Fatalf("SyncPostIndex() error = %v", err)
+	}
+	if len(idx.Posts) != 5 {
+		t.Errorf("Expected the full sync to leave 5 posts, got %d", len(idx.Posts))
+	}
+}
+
+func TestPostIndex_DropDeleted(t *testing.T) {
+	store := withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	idx := &PostIndex{Platform: "bluesky", Posts: timeline("p", 3, now)}
+	if err := store.Record("bluesky", TombstoneActionDeleted, "p-1", "", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Record("bluesky", TombstoneActionUnliked, "p-2", "", now); err != nil {
+		t.Fatal(err)
+	}
+
+	if dropped := idx.DropDeleted(); dropped != 1 {
+		t.Errorf("Expected 1 post dropped, got %d", dropped)
+	}
+	if len(idx.Posts) != 2 || idx.Contains("p-1") {
+		t.Errorf("Expected only the deleted post to be dropped, got %+v", idx.Posts)
+	}
+}
+
+func TestIndexReader(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	reader := NewIndexReader(&PostIndex{Posts: timeline("p", 5, now)}, "Mastodon (local index)")
+
+	var ids []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		posts, next, err := reader.FetchUserPostsPaginated(context.Background(), "me", 2, cursor)
+		if err != nil {
+			t.Fatalf("FetchUserPostsPaginated() error = %v", err)
+		}
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(ids) != 5 || ids[4] != "p-4" {
+		t.Errorf("Expected all 5 posts in order, got %v", ids)
+	}
+
+	if _, _, err := reader.FetchUserPostsPaginated(context.Background(), "me", 2, "bogus"); err == nil {
+		t.Error("Expected an error for a cursor that isn't an offset")
+	}
+	if _, _, err := reader.FetchUserPostsPaginated(context.Background(), "me", 2, strconv.Itoa(10)); err != nil {
+		t.Errorf("Expected an empty page past the end, got %v", err)
+	}
+}
+
+func TestIndexedPrunePosts(t *testing.T) {
+	store := NewPostIndexStoreAt(t.TempDir())
+	previous := DefaultPostIndexStore()
+	defaultPostIndexes = store
+	t.Cleanup(func() { defaultPostIndexes = previous })
+
+	if _, err := indexedPrunePosts("bluesky", "me.bsky.social"); !errors.Is(err, ErrNoPostIndex) {
+		t.Errorf("Expected ErrNoPostIndex without a sync, got %v", err)
+	}
+
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	posts := append(timeline("p", 2, now), Post{ID: "liked", Type: PostTypeLike, CreatedAt: now})
+	if err := store.Save(&PostIndex{Platform: "bluesky", Username: "me.bsky.social", Posts: posts}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := indexedPrunePosts("bluesky", "me.bsky.social")
+	if err != nil {
+		t.Fatalf("indexedPrunePosts() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Expected the 2 indexed posts without the like, got %+v", got)
+	}
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PostIDMap records posts that came back under a new ID, so lookups in the local index
+// follow them. Mastodon gives a deleted-and-redrafted status a new ID, and some servers
+// implement edits the same way. Alongside the map it keeps a fingerprint of each post
+// last seen, which is how a redraft is recognized on a later run.
+//
+// Each platform gets two files beside the tombstones:
+//
+//	<platform>-ids.tsv           <RFC3339 timestamp>\t<old ID>\t<new ID>   (append-only)
+//	<platform>-fingerprints.tsv  <fingerprint>\t<post ID>                  (rewritten each run)
+type PostIDMap struct {
+	dir    string
+	mu     sync.Mutex
+	loaded map[string]map[string]string // Platform, then old ID to new ID
+}
+
+// NewPostIDMapAt creates a post ID map rooted at the given directory
+func NewPostIDMapAt(dir string) *PostIDMap {
+	return &PostIDMap{dir: dir, loaded: make(map[string]map[string]string)}
+}
+
+func (m *PostIDMap) path(platform, kind string) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%s-%s.tsv", strings.ToLower(platform), kind))
+}
+
+// Record notes that the post once known as oldID is now newID
+func (m *PostIDMap) Record(platform, oldID, newID string, at time.Time) error {
+	if oldID == "" || newID == "" || strings.ContainsAny(oldID+newID, "\t\n") {
+		return fmt.Errorf("invalid post IDs %q and %q", oldID, newID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	f, err := os.OpenFile(m.path(platform, "ids"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open post ID map: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s\t%s\t%s\n", at.UTC().Format(time.RFC3339), oldID, newID); err != nil {
+		return fmt.Errorf("failed to write post ID map: %w", err)
+	}
+	if renamed, ok := m.loaded[strings.ToLower(platform)]; ok {
+		renamed[oldID] = newID
+	}
+	return nil
+}
+
+func (m *PostIDMap) loadLocked(platform string) (map[string]string, error) {
+	platform = strings.ToLower(platform)
+	if renamed, ok := m.loaded[platform]; ok {
+		return renamed, nil
+	}
+
+	renamed := make(map[string]string)
+	err := readTSV(m.path(platform, "ids"), func(fields []string) {
+		if len(fields) == 3 {
+			renamed[fields[1]] = fields[2]
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post ID map: %w", err)
+	}
+	m.loaded[platform] = renamed
+	return renamed, nil
+}
+
+// Aliases returns every ID the post has been known by, starting with id itself
+func (m *PostIDMap) Aliases(platform, id string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	renamed, err := m.loadLocked(platform)
+	if err != nil || len(renamed) == 0 {
+		return []string{id}
+	}
+
+	// Walk the renames in both directions, since a lookup can start from any version
+	linked := make(map[string][]string)
+	for oldID, newID := range renamed {
+		linked[oldID] = append(linked[oldID], newID)
+		linked[newID] = append(linked[newID], oldID)
+	}
+	aliases := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(aliases); i++ {
+		for _, next := range linked[aliases[i]] {
+			if !seen[next] {
+				seen[next] = true
+				aliases = append(aliases, next)
+			}
+		}
+	}
+	return aliases
+}
+
+// Fingerprints returns the post ID last seen for each content fingerprint
+func (m *PostIDMap) Fingerprints(platform string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fingerprints := make(map[string]string)
+	err := readTSV(m.path(platform, "fingerprints"), func(fields []string) {
+		if len(fields) == 2 {
+			fingerprints[fields[0]] = fields[1]
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post fingerprints: %w", err)
+	}
+	return fingerprints, nil
+}
+
+// SaveFingerprints replaces the stored fingerprints for a platform
+func (m *PostIDMap) SaveFingerprints(platform string, fingerprints map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	keys := make([]string, 0, len(fingerprints))
+	for fingerprint := range fingerprints {
+		keys = append(keys, fingerprint)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, fingerprint := range keys {
+		fmt.Fprintf(&b, "%s\t%s\n", fingerprint, fingerprints[fingerprint])
+	}
+
+	// Write then rename, so a crash never leaves a half-written file
+	path := m.path(platform, "fingerprints")
+	if err := os.WriteFile(path+".tmp", []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write post fingerprints: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write post fingerprints: %w", err)
+	}
+	return nil
+}
+
+// readTSV calls fn with the fields of each line in a tab-separated file, which may not exist yet
+func readTSV(path string, fn func(fields []string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fn(strings.Split(scanner.Text(), "\t"))
+	}
+	return scanner.Err()
+}
+
+// postFingerprint identifies a post by what it says and what it replies to, so a redraft
+// matches its original. Posts without text have no fingerprint, since they'd all match.
+func postFingerprint(post Post) string {
+	content := strings.Join(strings.Fields(post.Content), " ")
+	if content == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(string(post.Type) + "\x00" + post.InReplyToID + "\x00" + content))
+	return hex.EncodeToString(sum[:16])
+}
+
+var (
+	defaultPostIDs     *PostIDMap
+	defaultPostIDsOnce sync.Once
+)
+
+// DefaultPostIDMap returns the shared post ID map, kept with the tombstone index, or nil
+// if it can't be created
+func DefaultPostIDMap() *PostIDMap {
+	defaultPostIDsOnce.Do(func() {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			Logger.Warn().Err(err).Msg("Post ID map unavailable")
+			return
+		}
+		defaultPostIDs = NewPostIDMapAt(filepath.Join(homeDir, ".config", "cringesweeper", "tombstones"))
+	})
+	return defaultPostIDs
+}
+
+// postAliases returns every ID a post has been known by in the default post ID map
+func postAliases(platform, id string) []string {
+	ids := DefaultPostIDMap()
+	if ids == nil {
+		return []string{id}
+	}
+	return ids.Aliases(platform, id)
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPostIDMap_Aliases(t *testing.T) {
+	dir := t.TempDir()
+	ids := NewPostIDMapAt(dir)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := ids.Record("mastodon", "1", "2", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := ids.Record("mastodon", "2", "3", now); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh map reads the same history back, and lookups work from any version
+	reloaded := NewPostIDMapAt(dir)
+	for _, id := range []string{"1", "2", "3"} {
+		aliases := reloaded.Aliases("mastodon", id)
+		slices.Sort(aliases)
+		if !slices.Equal(aliases, []string{"1", "2", "3"}) {
+			t.Errorf("Aliases(%s) = %v, expected all three versions", id, aliases)
+		}
+	}
+	if aliases := reloaded.Aliases("mastodon", "9"); !slices.Equal(aliases, []string{"9"}) {
+		t.Errorf("Expected an unmapped post to be its only alias, got %v", aliases)
+	}
+	if aliases := reloaded.Aliases("bluesky", "1"); !slices.Equal(aliases, []string{"1"}) {
+		t.Errorf("Expected platforms to be kept apart, got %v", aliases)
+	}
+
+	if err := ids.Record("mastodon", "a\tb", "c", now); err == nil {
+		t.Error("Expected IDs containing tabs to be rejected")
+	}
+}
+
+func TestPostIDMap_Fingerprints(t *testing.T) {
+	ids := NewPostIDMapAt(t.TempDir())
+
+	if fingerprints, err := ids.Fingerprints("mastodon"); err != nil || len(fingerprints) != 0 {
+		t.Fatalf("Expected no fingerprints yet, got %v, %v", fingerprints, err)
+	}
+
+	want := map[string]string{"abc": "1", "def": "2"}
+	if err := ids.SaveFingerprints("mastodon", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ids.Fingerprints("mastodon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) || got["abc"] != "1" || got["def"] != "2" {
+		t.Errorf("Fingerprints() = %v, expected %v", got, want)
+	}
+}
+
+func TestPostFingerprint(t *testing.T) {
+	post := Post{Type: PostTypeOriginal, Content: "Hello  world\n"}
+
+	if postFingerprint(post) != postFingerprint(Post{Type: PostTypeOriginal, Content: "Hello world"}) {
+		t.Error("Expected whitespace differences to be ignored")
+	}
+	if postFingerprint(post) == postFingerprint(Post{Type: PostTypeReply, Content: "Hello world", InReplyToID: "9"}) {
+		t.Error("Expected a reply to differ from an original with the same text")
+	}
+	if postFingerprint(Post{Type: PostTypeOriginal}) != "" {
+		t.Error("Expected posts without text to have no fingerprint")
+	}
+}
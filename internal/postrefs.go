@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// PostRefKey reduces a post reference to the key it's matched on: the last path segment
+// of a post URL or AT URI, or the ID itself. That's the status ID on Mastodon and
+// GoToSocial, and the record key on Bluesky, so the web link and the at:// URI of the
+// same post give the same key.
+func PostRefKey(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	ref = strings.TrimRight(ref, "/")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return ref
+}
+
+// ParsePostRefs turns post URLs and IDs into their PostRefKeys, dropping empties and
+// duplicates
+func ParsePostRefs(refs []string) []string {
+	var keys []string
+	for _, ref := range refs {
+		key := PostRefKey(ref)
+		if key == "" || slices.Contains(keys, key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ReadPostRefs reads a file of post URLs or IDs, one per line, skipping blank lines and
+// lines starting with #
+func ReadPostRefs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var refs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ParsePostRefs(refs), nil
+}
+
+// IsListed returns true if the post is in ListedPosts. Likes and reposts are matched by
+// their own record, not the post they point at.
+func (o PruneOptions) IsListed(post Post) bool {
+	for _, ref := range []string{post.ID, post.URL} {
+		if key := PostRefKey(ref); key != "" && slices.Contains(o.ListedPosts, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExcluded returns true if the post, or the post it likes or reposts, is in ExcludePosts
+func (o PruneOptions) IsExcluded(post Post) bool {
+	if len(o.ExcludePosts) == 0 {
+		return false
+	}
+	refs := []string{post.ID, post.URL}
+	if post.OriginalPost != nil {
+		refs = append(refs, post.OriginalPost.ID, post.OriginalPost.URL)
+	}
+	for _, ref := range refs {
+		if key := PostRefKey(ref); key != "" && slices.Contains(o.ExcludePosts, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPostRefKey(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"109876543210", "109876543210"},
+		{"  109876543210  ", "109876543210"},
+		{"https://mastodon.social/@someone/109876543210", "109876543210"},
+		{"https://mastodon.social/@someone/109876543210/", "109876543210"},
+		{"https://mastodon.social/@someone/109876543210?foo=bar#top", "109876543210"},
+		{"https://bsky.app/profile/someone.bsky.social/post/3kabc123", "3kabc123"},
+		{"at://did:plc:abc/app.bsky.feed.post/3kabc123", "3kabc123"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := PostRefKey(tt.ref); got != tt.want {
+			t.Errorf("PostRefKey(%q) = %q, expected %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestReadPostRefs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keep.txt")
+	content := `# Posts to keep forever
+https://bsky.app/profile/someone.bsky.social/post/3kabc123
+
+at://did:plc:abc/app.bsky.feed.post/3kabc123
+109876543210
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := ReadPostRefs(path)
+	if err != nil {
+		t.Fatalf("ReadPostRefs() error = %v", err)
+	}
+	if want := []string{"3kabc123", "109876543210"}; !slices.Equal(refs, want) {
+		t.Errorf("Expected %v, got %v", want, refs)
+	}
+
+	if _, err := ReadPostRefs(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestPruneOptions_IsExcluded(t *testing.T) {
+	options := PruneOptions{ExcludePosts: []string{"3kabc123", "109876543210"}}
+	tests := []struct {
+		name string
+		post Post
+		want bool
+	}{
+		{"bluesky post by URI", Post{ID: "at://did:plc:abc/app.bsky.feed.post/3kabc123"}, true},
+		{"mastodon post by ID", Post{ID: "109876543210"}, true},
+		{"matched by URL", Post{ID: "1", URL: "https://mastodon.social/@someone/109876543210"}, true},
+		{"like of an excluded post", Post{ID: "at://did:plc:abc/app.bsky.feed.like/3klike", OriginalPost: &Post{ID: "at://did:plc:xyz/app.bsky.feed.post/3kabc123"}}, true},
+		{"other post", Post{ID: "at://did:plc:abc/app.bsky.feed.post/3kother", URL: "https://bsky.app/profile/someone/post/3kother"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := options.IsExcluded(tt.post); got != tt.want {
+				t.Errorf("IsExcluded() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+
+	if (PruneOptions{}).IsExcluded(Post{ID: "109876543210"}) {
+		t.Error("Expected nothing to be excluded without an exclude list")
+	}
+}
+
+func TestPruneOptions_ListedPosts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	listed := Post{ID: "at://did:plc:abc/app.bsky.feed.post/3kabc123", Type: PostTypeOriginal, CreatedAt: now}
+	other := Post{ID: "at://did:plc:abc/app.bsky.feed.post/3kother", Type: PostTypeOriginal, CreatedAt: now.Add(-365 * 24 * time.Hour)}
+	options := PruneOptions{ListedPosts: []string{"3kabc123"}}
+
+	// Without age criteria, a listed post is acted on however new it is
+	if selected, reason := options.selectForPrune("bluesky", listed, now); !selected || reason != "" {
+		t.Errorf("Expected the listed post to be selected, got %v %q", selected, reason)
+	}
+	if selected, _ := options.selectForPrune("bluesky", other, now); selected {
+		t.Error("Expected a post that isn't listed to be left alone")
+	}
+
+	// Age criteria given alongside the list still apply
+	maxAge := 24 * time.Hour
+	options.MaxAge = &maxAge
+	if selected, _ := options.selectForPrune("bluesky", listed, now); selected {
+		t.Error("Expected a listed post newer than --max-post-age to be left alone")
+	}
+
+	// And the exclude list still wins
+	options = PruneOptions{ListedPosts: []string{"3kabc123"}, ExcludePosts: []string{"3kabc123"}}
+	if _, reason := options.selectForPrune("bluesky", listed, now); reason != "excluded" {
+		t.Errorf("Expected an excluded post to be preserved, got %q", reason)
+	}
+}
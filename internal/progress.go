@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ParseProgressInterval parses --progress-interval: a post count ("100"), a duration
+// ("30s"), or both separated by a comma ("100,30s"). A summary is printed whenever
+// either limit is reached.
+func ParseProgressInterval(s string) (every int, interval time.Duration, err error) {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if n, convErr := strconv.Atoi(part); convErr == nil {
+			if n <= 0 || every != 0 {
+				return 0, 0, fmt.Errorf("invalid progress post count %q", part)
+			}
+			every = n
+			continue
+		}
+		d, parseErr := time.ParseDuration(part)
+		if parseErr != nil || d <= 0 || interval != 0 {
+			return 0, 0, fmt.Errorf("invalid progress interval %q: use a post count (100), a duration (30s) or both (100,30s)", part)
+		}
+		interval = d
+	}
+	return every, interval, nil
+}
+
+// ProgressReporter replaces per-post output during a prune with periodic summary lines
+// when ProgressEvery or ProgressInterval is set. Without either, it stays out of the way
+// and each post is reported as it is processed.
+type ProgressReporter struct {
+	platform string
+	every    int
+	interval time.Duration
+	clock    Clock
+	out      io.Writer
+
+	total    int // Posts the run will examine, for the ETA
+	examined int
+	deleted  int
+	unliked  int
+	unshared int
+	redacted int
+	failed   int
+
+	start            time.Time
+	lastReport       time.Time
+	examinedAtReport int
+}
+
+// NewProgressReporter creates a reporter for a run that will examine total posts
+func NewProgressReporter(platform string, total int, options PruneOptions, clock Clock) *ProgressReporter {
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+	return &ProgressReporter{
+		platform:   platform,
+		every:      options.ProgressEvery,
+		interval:   options.ProgressInterval,
+		clock:      clock,
+		out:        options.output(),
+		total:      total,
+		start:      now,
+		lastReport: now,
+	}
+}
+
+// Enabled reports whether summaries replace per-post output
+func (p *ProgressReporter) Enabled() bool {
+	return p.every > 0 || p.interval > 0
+}
+
+// PostEvent returns the log event for one post's outcome, dropped to debug while
+// summaries are enabled so long runs don't log every post
+func (p *ProgressReporter) PostEvent(logger zerolog.Logger) *zerolog.Event {
+	if p.Enabled() {
+		return logger.Debug()
+	}
+	return logger.Info()
+}
+
+// PrintPost prints a per-post line unless summaries are enabled
+func (p *ProgressReporter) PrintPost(format string, args ...interface{}) {
+	if !p.Enabled() {
+		fmt.Fprintf(p.out, format, args...)
+	}
+}
+
+// Step is called before each post is examined, printing a summary if one is due
+func (p *ProgressReporter) Step() {
+	if p.Enabled() && p.examined > p.examinedAtReport {
+		due := p.every > 0 && p.examined-p.examinedAtReport >= p.every
+		if p.interval > 0 && p.clock.Now().Sub(p.lastReport) >= p.interval {
+			due = true
+		}
+		if due {
+			p.report()
+		}
+	}
+	p.examined++
+}
+
+// Record counts the outcome of an action taken on a post
+func (p *ProgressReporter) Record(action string, err error) {
+	if err != nil {
+		p.failed++
+		return
+	}
+	switch action {
+	case TombstoneActionDeleted:
+		p.deleted++
+	case TombstoneActionUnliked:
+		p.unliked++
+	case TombstoneActionUnshared:
+		p.unshared++
+	case TombstoneActionRedacted:
+		p.redacted++
+	}
+}
+
+// Finish prints a final summary if anything happened since the last one
+func (p *ProgressReporter) Finish() {
+	if p.Enabled() && p.examined > p.examinedAtReport {
+		p.report()
+	}
+}
+
+func (p *ProgressReporter) report() {
+	now := p.clock.Now()
+	elapsed := now.Sub(p.start)
+
+	line := fmt.Sprintf("📊 %s progress: %d/%d posts processed, %d deleted, %d unliked, %d unshared, %d failed",
+		p.platform, p.examined, p.total, p.deleted, p.unliked, p.unshared, p.failed)
+	if p.redacted > 0 {
+		line += fmt.Sprintf(", %d redacted", p.redacted)
+	}
+
+	event := WithPlatform(p.platform).Info().
+		Int("processed", p.examined).
+		Int("total", p.total).
+		Int("deleted", p.deleted).
+		Int("unliked", p.unliked).
+		Int("unshared", p.unshared).
+		Int("redacted", p.redacted).
+		Int("failed", p.failed)
+
+	if elapsed > 0 && p.examined > 0 {
+		rate := float64(p.examined) / elapsed.Minutes()
+		line += fmt.Sprintf(" (%.1f posts/min", rate)
+		event = event.Float64("posts_per_minute", rate)
+		if remaining := p.total - p.examined; remaining > 0 {
+			eta := time.Duration(float64(remaining) / float64(p.examined) * float64(elapsed)).Round(time.Second)
+			line += fmt.Sprintf(", ETA %s", eta)
+			event = event.Dur("eta", eta)
+		}
+		line += ")"
+	}
+
+	fmt.Fprintln(p.out, line)
+	event.Msg("Prune progress")
+
+	p.lastReport = now
+	p.examinedAtReport = p.examined
+}
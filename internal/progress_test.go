@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProgressInterval(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantEvery    int
+		wantInterval time.Duration
+		wantErr      bool
+	}{
+		{"", 0, 0, false},
+		{"100", 100, 0, false},
+		{"30s", 0, 30 * time.Second, false},
+		{"100,30s", 100, 30 * time.Second, false},
+		{"2m, 50", 50, 2 * time.Minute, false},
+		{"0", 0, 0, true},
+		{"-5", 0, 0, true},
+		{"soon", 0, 0, true},
+		{"10,20", 0, 0, true},
+		{"10s,20s", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			every, interval, err := ParseProgressInterval(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProgressInterval(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if every != tt.wantEvery || interval != tt.wantInterval {
+				t.Errorf("ParseProgressInterval(%q) = %d, %v, want %d, %v", tt.input, every, interval, tt.wantEvery, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func newTestProgressReporter(total int, options PruneOptions, clock Clock) (*ProgressReporter, *bytes.Buffer) {
+	p := NewProgressReporter("bluesky", total, options, clock)
+	var out bytes.Buffer
+	p.out = &out
+	return p, &out
+}
+
+func TestProgressReporter_Disabled(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	p, out := newTestProgressReporter(3, PruneOptions{}, clock)
+
+	for i := 0; i < 3; i++ {
+		p.Step()
+		p.Record(TombstoneActionDeleted, nil)
+		p.PrintPost("deleted %d\n", i)
+	}
+	p.Finish()
+
+	if out.String() != "deleted 0\ndeleted 1\ndeleted 2\n" {
+		t.Errorf("Expected only per-post lines, got %q", out.String())
+	}
+}
+
+func TestProgressReporter_EveryNPosts(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	p, out := newTestProgressReporter(5, PruneOptions{ProgressEvery: 2}, clock)
+
+	for i := 0; i < 5; i++ {
+		p.Step()
+		p.PrintPost("post %d\n", i)
+		if i == 3 {
+			p.Record(TombstoneActionDeleted, errors.New("boom"))
+		} else {
+			p.Record(TombstoneActionDeleted, nil)
+		}
+		clock.Advance(30 * time.Second)
+	}
+	p.Finish()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"📊 bluesky progress: 2/5 posts processed, 2 deleted, 0 unliked, 0 unshared, 0 failed (2.0 posts/min, ETA 1m30s)",
+		"📊 bluesky progress: 4/5 posts processed, 3 deleted, 0 unliked, 0 unshared, 1 failed (2.0 posts/min, ETA 30s)",
+		"📊 bluesky progress: 5/5 posts processed, 4 deleted, 0 unliked, 0 unshared, 1 failed (2.0 posts/min)",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d summary lines, got %q", len(want), out.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("Line %d:\n got %q\nwant %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestProgressReporter_Interval(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	p, out := newTestProgressReporter(100, PruneOptions{ProgressInterval: time.Minute}, clock)
+
+	// Posts every 20s: a summary is due once a minute has passed since the last one
+	for i := 0; i < 7; i++ {
+		p.Step()
+		p.Record(TombstoneActionUnliked, nil)
+		clock.Advance(20 * time.Second)
+	}
+
+	if got := strings.Count(out.String(), "📊"); got != 2 {
+		t.Errorf("Expected 2 summaries after 7 posts at 20s apart, got %d: %q", got, out.String())
+	}
+
+	// Finish reports the remainder once, and not again if called twice
+	p.Finish()
+	p.Finish()
+	if got := strings.Count(out.String(), "📊"); got != 3 {
+		t.Errorf("Expected a final summary, got %d: %q", got, out.String())
+	}
+	if !strings.Contains(out.String(), "7/100 posts processed, 0 deleted, 7 unliked") {
+		t.Errorf("Final summary should include all posts, got %q", out.String())
+	}
+}
@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// PruneActor carries out a platform's prune actions for a PruneEngine
+type PruneActor interface {
+	// Act carries out action, one of "delete", "redact", "unlike" or "unshare", on post
+	Act(ctx context.Context, action string, post Post) error
+}
+
+// pruneChecker is a PruneActor that refuses some posts before they're acted on, such as
+// records outside the account's own repo
+type pruneChecker interface {
+	checkPost(action string, post Post) error
+}
+
+// pruneBatcher is a PruneActor that can send actions together, for BatchWrites. report
+// is called for each action once its batch has been sent.
+type pruneBatcher interface {
+	queue(ctx context.Context, action string, post Post, report pruneReport) error
+	flush(ctx context.Context, report pruneReport) error
+}
+
+// pruneReport records the outcome of one action
+type pruneReport func(action string, post Post, err error)
+
+// pruneOutcome is how an action is reported once it has been carried out
+type pruneOutcome struct {
+	tombstone string // Tombstone action
+	failure   string // Start of the error message
+	success   string // Log message
+	printed   string // Printed line, with the post date and content to fill in
+}
+
+var pruneOutcomes = map[string]pruneOutcome{
+	"delete":  {TombstoneActionDeleted, "Failed to delete post", "Post deleted successfully", "🗑️  Deleted post from %s: %s\n"},
+	"redact":  {TombstoneActionRedacted, "Failed to redact post", "Post redacted successfully", "✏️  Redacted post from %s: %s\n"},
+	"unlike":  {TombstoneActionUnliked, "Failed to unlike post", "Post unliked successfully", "👍 Unliked post from %s: %s\n"},
+	"unshare": {TombstoneActionUnshared, "Failed to unshare repost", "Repost unshared successfully", "🔄 Unshared repost from %s: %s\n"},
+}
+
+// PruneEngine is the part of a prune every platform shares: picking the posts to act on
+// and the ones to preserve, asking first with --interactive, pacing requests, stopping at
+// the run's limits and recording what happened. Clients fetch the posts, and act on them
+// through a PruneActor.
+type PruneEngine struct {
+	platform string
+	actor    PruneActor
+	options  PruneOptions
+	clock    Clock
+	out      io.Writer // Where each action is printed, from options.Output
+}
+
+// NewPruneEngine creates an engine that prunes a platform's posts through actor
+func NewPruneEngine(platform string, actor PruneActor, options PruneOptions, clock Clock) *PruneEngine {
+	return &PruneEngine{platform: platform, actor: actor, options: options, clock: clock, out: options.output()}
+}
+
+// Run acts on the posts that match the engine's options, adding what it did, or with
+// DryRun would do, to result. Posts are acted on in the order given, so replies should
+// come before the posts they answer.
+func (e *PruneEngine) Run(ctx context.Context, posts []Post, result *PruneResult) error {
+	// Don't start acting on posts if we were cancelled while fetching them
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := e.clock.Now()
+	options := e.options.withWholeThreads(e.platform, posts, now)
+
+	// The same post can turn up in more than one listing; act on each one only once
+	posts = options.mergeActionsByTarget(e.platform, posts, now)
+
+	progress := NewProgressReporter(e.platform, len(posts), options, e.clock)
+	defer progress.Finish()
+
+	// A plan is carried out as written or not at all, so an edited action isn't quietly replaced
+	if err := options.checkPlannedActions(e.platform, posts, now); err != nil {
+		return err
+	}
+
+	pacing := planPacing(e.platform, posts, options, now)
+	printPacingPlan(e.platform, pacing, options)
+
+	// Knowing how long the run will take, the user can still back out before it starts
+	if !options.DryRun && options.ConfirmRun != nil && pacing.Actions() > 0 && !options.ConfirmRun(e.platform, pacing) {
+		WithPlatform(e.platform).Info().Int("actions", pacing.Actions()).Msg("Prune run cancelled before starting")
+		result.StoppedEarly = true
+		result.AddWarning("Cancelled on %s before acting on any posts", e.platform)
+		return nil
+	}
+
+	// Until they're done, the posts about to be acted on hold the checkpoint's cursor back
+	if !options.DryRun && options.Checkpoint != nil {
+		var expected []string
+		for _, post := range posts {
+			if selected, preserveReason := options.selectForPrune(e.platform, post, now); selected && preserveReason == "" && options.ActionFor(post) != "" {
+				expected = append(expected, post.ID)
+			}
+		}
+		options.Checkpoint.expect(expected)
+		defer options.Checkpoint.flush()
+	}
+
+	acted := make(map[string]bool)
+	report := func(action string, post Post, err error) {
+		e.report(result, progress, action, post, err)
+		if err == nil {
+			acted[post.ID] = true
+			options.Checkpoint.complete(post.ID)
+		}
+	}
+	batcher, batching := e.actor.(pruneBatcher)
+	batching = batching && options.BatchWrites && !options.DryRun
+
+	for _, post := range posts {
+		progress.Step()
+		selected, preserveReason := options.selectForPrune(e.platform, post, now)
+		if !selected {
+			continue
+		}
+		if preserveReason != "" {
+			result.preserve(post, preserveReason)
+			continue
+		}
+
+		// A shutting-down server lets the run end here, with what it has done so far
+		if DrainFromContext(ctx).Draining() {
+			result.stopForDrain(e.platform)
+			break
+		}
+
+		// Leave the rest for the next run once the time or request budget is spent
+		if limit := options.runLimitReached(ctx, e.clock.Now()); limit != "" {
+			result.stopEarly(e.platform, limit)
+			break
+		}
+
+		// With --interactive, the user has the final say on each post
+		proceed, stop := options.confirm(post, result, e.platform)
+		if stop {
+			break
+		}
+		if !proceed {
+			options.Checkpoint.settle(post.ID)
+			continue
+		}
+
+		action := options.ActionFor(post)
+		if action == "" {
+			continue
+		}
+		if checker, ok := e.actor.(pruneChecker); ok {
+			if err := checker.checkPost(action, post); err != nil {
+				fmt.Fprintf(e.out, "⚠️  Skipping post from %s: %v\n", post.CreatedAt.Format("2006-01-02"), err)
+				result.AddWarning("Skipped post %s that failed validation: %v", post.ID, err)
+				options.Checkpoint.settle(post.ID)
+				continue
+			}
+		}
+
+		// --max-deletions caps the actions taken, whichever kind they are
+		if !options.DryRun && !ActionBudgetFromContext(ctx).take() {
+			result.stopEarly(e.platform, "--max-deletions")
+			break
+		}
+		result.addPlanned(action, post)
+
+		// A post that can't be archived isn't deleted, since restore couldn't bring it back
+		if err := options.archivePost(e.platform, action, post, e.clock.Now()); err != nil {
+			report(action, post, err)
+			continue
+		}
+
+		switch {
+		case options.DryRun:
+		case batching:
+			// The action goes with the rest of its batch in one request
+			if err := batcher.queue(ctx, action, post, report); err != nil {
+				return err
+			}
+		default:
+			// Add configurable delay to respect rate limits
+			if err := sleepContext(ctx, options.RateLimitDelay); err != nil {
+				return err
+			}
+			report(action, post, e.actor.Act(ctx, action, post))
+		}
+	}
+
+	// Send whatever is left of the last batch, including after stopping early
+	if batching {
+		if err := batcher.flush(ctx, report); err != nil {
+			return err
+		}
+	}
+	result.Account = options.accountStats(e.platform, posts, acted, now)
+	return nil
+}
+
+// report records the outcome of an action on post in the result, progress and logs
+func (e *PruneEngine) report(result *PruneResult, progress *ProgressReporter, action string, post Post, err error) {
+	outcome := pruneOutcomes[action]
+	logger := WithPlatform(e.platform).With().Str("post_id", post.ID).Logger()
+	recordAudit(e.platform, action, post, err, e.clock.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg(outcome.failure)
+		fmt.Fprintf(e.out, "❌ %s from %s: %v\n", outcome.failure, post.CreatedAt.Format("2006-01-02"), err)
+		result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %v", outcome.failure, post.ID, err))
+		result.ErrorsCount++
+		if IsRateLimited(err) {
+			result.RateLimitedCount++
+		}
+		progress.Record(outcome.tombstone, err)
+		return
+	}
+
+	progress.PostEvent(logger).Str("content", TruncateContent(post.Content, 50)).Msg(outcome.success)
+	recordTombstone(e.platform, outcome.tombstone, post.ID)
+	progress.Record(outcome.tombstone, nil)
+	progress.PrintPost(outcome.printed, post.CreatedAt.Format("2006-01-02"), TruncateContent(post.Content, 50))
+	switch action {
+	case "delete":
+		result.DeletedCount++
+	case "redact":
+		result.RedactedCount++
+	case "unlike":
+		result.UnlikedCount++
+	case "unshare":
+		result.UnsharedCount++
+	}
+}
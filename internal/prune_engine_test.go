@@ -0,0 +1,403 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withTombstoneStore records tombstones in a throwaway store for the duration of a test,
+// and the audit log with them
+func withTombstoneStore(t *testing.T) *TombstoneStore {
+	t.Helper()
+	previous := DefaultTombstoneStore()
+	store := NewTombstoneStoreAt(t.TempDir())
+	defaultTombstones = store
+	t.Cleanup(func() { defaultTombstones = previous })
+	withAuditLog(t)
+	return store
+}
+
+// withAuditLog records audit entries in a throwaway file for the duration of a test
+func withAuditLog(t *testing.T) *AuditLog {
+	t.Helper()
+	previous := DefaultAuditLog()
+	log := NewAuditLogAt(filepath.Join(t.TempDir(), "audit.jsonl"))
+	defaultAudit = log
+	t.Cleanup(func() { defaultAudit = previous })
+	return log
+}
+
+// fakePruneActor records the actions it's asked to carry out, failing the ones in fail and
+// rate limiting the ones in limited
+type fakePruneActor struct {
+	acted   []string
+	fail    map[string]bool
+	limited map[string]bool
+}
+
+func (a *fakePruneActor) Act(ctx context.Context, action string, post Post) error {
+	a.acted = append(a.acted, action+" "+post.ID)
+	if a.fail[post.ID] {
+		return errors.New("server said no")
+	}
+	if a.limited[post.ID] {
+		return newAPIError("mastodon", "API request", 429, []byte("Too many requests"))
+	}
+	return nil
+}
+
+// fakeBatchActor is a fakePruneActor that batches its actions and refuses some posts
+type fakeBatchActor struct {
+	fakePruneActor
+	queued  []Post
+	flushes int
+	refuse  map[string]bool
+}
+
+func (a *fakeBatchActor) checkPost(action string, post Post) error {
+	if a.refuse[post.ID] {
+		return errors.New("not yours")
+	}
+	return nil
+}
+
+func (a *fakeBatchActor) queue(ctx context.Context, action string, post Post, report pruneReport) error {
+	a.queued = append(a.queued, post)
+	return nil
+}
+
+func (a *fakeBatchActor) flush(ctx context.Context, report pruneReport) error {
+	a.flushes++
+	for _, post := range a.queued {
+		report(PruneAction(post), post, nil)
+	}
+	a.queued = nil
+	return nil
+}
+
+func TestPruneEngine_Run(t *testing.T) {
+	store := withTombstoneStore(t)
+	audit := withAuditLog(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	maxAge := 30 * 24 * time.Hour
+
+	posts := []Post{
+		{ID: "engine-post", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "engine-pinned", Type: PostTypeOriginal, CreatedAt: old, IsPinned: true},
+		{ID: "engine-new", Type: PostTypeOriginal, CreatedAt: now},
+		{ID: "engine-like", Type: PostTypeLike, CreatedAt: old},
+		{ID: "engine-repost", Type: PostTypeRepost, CreatedAt: old},
+		{ID: "engine-broken", Type: PostTypeReply, CreatedAt: old},
+	}
+	var out bytes.Buffer
+	options := PruneOptions{MaxAge: &maxAge, PreservePinned: true, UnlikePosts: true, Output: &out}
+	actor := &fakePruneActor{fail: map[string]bool{"engine-broken": true}}
+
+	result := &PruneResult{}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"delete engine-post", "unlike engine-like", "unshare engine-repost", "delete engine-broken"}
+	if !slices.Equal(actor.acted, want) {
+		t.Errorf("Expected actions %v, got %v", want, actor.acted)
+	}
+	if result.DeletedCount != 1 || result.UnlikedCount != 1 || result.UnsharedCount != 1 || result.ErrorsCount != 1 {
+		t.Errorf("Unexpected counts: %+v", result)
+	}
+	if len(result.PostsToDelete) != 2 || result.PreservedCount != 1 || result.PostsPreserved[0].ID != "engine-pinned" {
+		t.Errorf("Expected two posts to delete and the pinned post preserved, got %+v", result)
+	}
+	if reason := result.PreserveReasons["engine-pinned"]; reason != PreserveReasonPinned {
+		t.Errorf("Expected the pinned post to be preserved as %q, got %q", PreserveReasonPinned, reason)
+	}
+	if tombstone, ok := store.Get("mastodon", "engine-post"); !ok || tombstone.Action != TombstoneActionDeleted {
+		t.Errorf("Expected a tombstone for the deleted post, got %+v", tombstone)
+	}
+	if _, ok := store.Get("mastodon", "engine-broken"); ok {
+		t.Error("Expected no tombstone for the post that failed to delete")
+	}
+	for _, line := range []string{"⏳ Pacing:", "🗑️  Deleted post from", "❌ Failed to delete post from"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected %q in the run's output, got:\n%s", line, out.String())
+		}
+	}
+
+	// Audit entries are stamped by the engine's clock
+	data, err := os.ReadFile(audit.Path())
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d audit entries, got %d", len(want), len(lines))
+	}
+	for _, line := range lines {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil || !entry.Time.Equal(now) {
+			t.Errorf("Expected audit entry at %v, got %s (%v)", now, line, err)
+		}
+	}
+}
+
+func TestPruneEngine_RateLimited(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := time.Hour
+	posts := []Post{
+		{ID: "limited-ok", Type: PostTypeOriginal, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "limited-broken", Type: PostTypeOriginal, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "limited-429", Type: PostTypeOriginal, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	actor := &fakePruneActor{fail: map[string]bool{"limited-broken": true}, limited: map[string]bool{"limited-429": true}}
+
+	result := &PruneResult{}
+	if err := NewPruneEngine("mastodon", actor, PruneOptions{MaxAge: &maxAge}, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.DeletedCount != 1 || result.ErrorsCount != 2 || result.RateLimitedCount != 1 {
+		t.Errorf("Expected two errors, one of them rate limited, got %+v", result)
+	}
+}
+
+func TestPruneEngine_DryRun(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 24 * time.Hour
+	options := PruneOptions{MaxAge: &maxAge, DryRun: true, Redact: true}
+	actor := &fakePruneActor{}
+
+	result := &PruneResult{}
+	posts := []Post{{ID: "dry-post", Type: PostTypeOriginal, CreatedAt: now.Add(-48 * time.Hour)}}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actor.acted) != 0 {
+		t.Errorf("Expected a dry run not to act, got %v", actor.acted)
+	}
+	if len(result.PostsToRedact) != 1 || result.RedactedCount != 0 {
+		t.Errorf("Expected one post listed to redact and none redacted, got %+v", result)
+	}
+}
+
+func TestPruneEngine_Batching(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+	posts := []Post{
+		{ID: "batch-1", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "batch-foreign", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "batch-2", Type: PostTypeReply, CreatedAt: old},
+	}
+
+	actor := &fakeBatchActor{refuse: map[string]bool{"batch-foreign": true}}
+	result := &PruneResult{}
+	var out bytes.Buffer
+	options := PruneOptions{MaxAge: &maxAge, BatchWrites: true, Output: &out}
+	if err := NewPruneEngine("bluesky", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actor.acted) != 0 || actor.flushes != 1 {
+		t.Errorf("Expected every action to go in one batch, got %d single actions and %d flushes", len(actor.acted), actor.flushes)
+	}
+	if result.DeletedCount != 2 || len(result.Warnings) != 1 {
+		t.Errorf("Expected two deletions and a warning for the refused post, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "⚠️  Skipping post from") {
+		t.Errorf("Expected the refused post reported in the run's output, got:\n%s", out.String())
+	}
+
+	// Without BatchWrites, the same actor acts on each post alone
+	actor = &fakeBatchActor{}
+	options.BatchWrites = false
+	posts = []Post{{ID: "single-1", Type: PostTypeOriginal, CreatedAt: old}}
+	if err := NewPruneEngine("bluesky", actor, options, NewFakeClock(now)).Run(context.Background(), posts, &PruneResult{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actor.acted) != 1 || actor.flushes != 0 {
+		t.Errorf("Expected a single action and no batch, got %v and %d flushes", actor.acted, actor.flushes)
+	}
+}
+
+func TestPruneEngine_ConfirmRun(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 24 * time.Hour
+	posts := []Post{{ID: "confirm-post", Type: PostTypeOriginal, CreatedAt: now.Add(-48 * time.Hour)}}
+
+	for _, answer := range []bool{false, true} {
+		var asked PacingPlan
+		options := PruneOptions{MaxAge: &maxAge, ConfirmRun: func(platform string, plan PacingPlan) bool {
+			asked = plan
+			return answer
+		}}
+		actor := &fakePruneActor{}
+		result := &PruneResult{}
+		if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if asked.Deletes != 1 {
+			t.Errorf("Expected to be asked about 1 deletion, got %+v", asked)
+		}
+		if answer != (len(actor.acted) == 1) || answer == result.StoppedEarly {
+			t.Errorf("Answering %v: acted on %v, stopped early %v", answer, actor.acted, result.StoppedEarly)
+		}
+	}
+}
+
+func TestPruneEngine_MaxDeletions(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+	options := PruneOptions{MaxAge: &maxAge, UnlikePosts: true}
+
+	// The cap counts every kind of action together, and carries over to the next platform
+	budget := NewActionBudget(3)
+	ctx := WithActionBudget(context.Background(), budget)
+	first := []Post{
+		{ID: "cap-post", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "cap-like", Type: PostTypeLike, CreatedAt: old},
+	}
+	actor := &fakePruneActor{}
+	result := &PruneResult{}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(ctx, first, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actor.acted) != 2 || result.StoppedEarly {
+		t.Errorf("Expected both actions within the cap, got %v", actor.acted)
+	}
+
+	second := []Post{
+		{ID: "cap-repost", Type: PostTypeRepost, CreatedAt: old},
+		{ID: "cap-reply", Type: PostTypeReply, CreatedAt: old},
+	}
+	actor = &fakePruneActor{}
+	result = &PruneResult{}
+	if err := NewPruneEngine("bluesky", actor, options, NewFakeClock(now)).Run(ctx, second, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actor.acted) != 1 || !result.StoppedEarly || budget.Used() != 3 {
+		t.Errorf("Expected one more action before stopping at the cap, got %v (stopped early %v)", actor.acted, result.StoppedEarly)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "--max-deletions") {
+		t.Errorf("Expected a warning naming --max-deletions, got %v", result.Warnings)
+	}
+
+	// Dry runs don't use up the cap
+	options.DryRun = true
+	budget = NewActionBudget(1)
+	result = &PruneResult{}
+	if err := NewPruneEngine("bluesky", &fakePruneActor{}, options, NewFakeClock(now)).Run(WithActionBudget(context.Background(), budget), second, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if budget.Used() != 0 || len(result.PostsToDelete)+len(result.PostsToUnshare) != 2 {
+		t.Errorf("Expected a dry run to list both posts without using the cap, got %d used", budget.Used())
+	}
+}
+
+func TestPruneEngine_AccountStats(t *testing.T) {
+	store := withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	maxAge := 30 * 24 * time.Hour
+	if err := store.Record("mastodon", TombstoneActionDeleted, "stats-gone", "", now); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := []Post{
+		{ID: "stats-old", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "stats-pinned", Type: PostTypeOriginal, CreatedAt: old.Add(-24 * time.Hour), IsPinned: true},
+		{ID: "stats-new", Type: PostTypeOriginal, CreatedAt: now},
+		{ID: "stats-broken", Type: PostTypeReply, CreatedAt: old},
+		{ID: "stats-like", Type: PostTypeLike, CreatedAt: old.Add(-100 * 24 * time.Hour)},
+		{ID: "stats-gone", Type: PostTypeOriginal, CreatedAt: old.Add(-200 * 24 * time.Hour)},
+	}
+	options := PruneOptions{MaxAge: &maxAge, PreservePinned: true, UnlikePosts: true}
+	actor := &fakePruneActor{fail: map[string]bool{"stats-broken": true}}
+
+	result := &PruneResult{}
+	if err := NewPruneEngine("mastodon", actor, options, NewFakeClock(now)).Run(context.Background(), posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The deleted post, the unliked like and the post deleted by an earlier run are gone;
+	// the reply that failed to delete is still left and still matches
+	stats := result.Account
+	if stats == nil {
+		t.Fatal("Expected account stats on the result")
+	}
+	if stats.Remaining[PostTypeOriginal] != 2 || stats.Remaining[PostTypeReply] != 1 || stats.RemainingPosts() != 3 {
+		t.Errorf("Expected 2 originals and 1 reply left, got %v", stats.Remaining)
+	}
+	if stats.Matching != 1 {
+		t.Errorf("Expected 1 matching post left, got %d", stats.Matching)
+	}
+	if !stats.OldestPost.Equal(old.Add(-24 * time.Hour)) {
+		t.Errorf("Expected the pinned post to be the oldest left, got %v", stats.OldestPost)
+	}
+
+	// A dry run leaves everything, and everything it would act on still matches
+	withTombstoneStore(t)
+	options.DryRun = true
+	result = &PruneResult{}
+	if err := NewPruneEngine("mastodon", &fakePruneActor{}, options, NewFakeClock(now)).Run(context.Background(), posts[:5], result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Account.RemainingPosts() != 5 || result.Account.Matching != 3 {
+		t.Errorf("Expected 5 posts left and 3 matching after a dry run, got %d and %d", result.Account.RemainingPosts(), result.Account.Matching)
+	}
+}
+
+// drainingActor starts a drain as soon as it's asked to act, like a shutdown arriving
+// mid-run
+type drainingActor struct {
+	fakePruneActor
+	drain *Drain
+}
+
+func (a *drainingActor) Act(ctx context.Context, action string, post Post) error {
+	a.drain.Start()
+	return a.fakePruneActor.Act(ctx, action, post)
+}
+
+func TestPruneEngine_Drain(t *testing.T) {
+	withTombstoneStore(t)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+	posts := []Post{
+		{ID: "drain-1", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "drain-2", Type: PostTypeOriginal, CreatedAt: old},
+	}
+
+	// The post in hand is finished, and the run ends cleanly with what it did
+	actor := &drainingActor{drain: NewDrain()}
+	ctx := WithDrain(context.Background(), actor.drain)
+	result := &PruneResult{}
+	if err := NewPruneEngine("mastodon", actor, PruneOptions{MaxAge: &maxAge}, NewFakeClock(now)).Run(ctx, posts, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !slices.Equal(actor.acted, []string{"delete drain-1"}) || result.DeletedCount != 1 {
+		t.Errorf("Expected only the first post deleted, got %v", actor.acted)
+	}
+	if !result.StoppedEarly || len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "shutdown") {
+		t.Errorf("Expected the run to stop early for shutdown, got %+v", result)
+	}
+
+	// Starting twice is harmless, and a context without a drain never drains
+	actor.drain.Start()
+	if DrainFromContext(context.Background()).Draining() {
+		t.Error("Expected no drain without one attached")
+	}
+}
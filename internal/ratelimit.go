@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateWindow is a limit of Limit requests in any Window, such as Mastodon's 30 status
+// deletions every 30 minutes. The zero RateWindow is no limit.
+type RateWindow struct {
+	Limit  int
+	Window time.Duration
+}
+
+// formatWindow writes a window without the zero units time.Duration adds, so "30m"
+// rather than "30m0s"
+func formatWindow(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return d.String()
+}
+
+// rateTracker keeps requests to one endpoint inside a RateWindow. It remembers when the
+// requests in the current window were made, so the next one only waits once the window
+// is full, and only until the oldest of them drops out of it. When the server's rate
+// limit headers say the window is used up sooner, as after requests from another
+// client, they win. A nil tracker never waits, so callers never need to check for one.
+type rateTracker struct {
+	window RateWindow
+
+	mu      sync.Mutex
+	sent    []time.Time // When requests in the window were made, oldest first
+	blocked time.Time   // The server refused more requests until then
+}
+
+// newRateTracker creates a tracker for window, or returns nil if window is no limit
+func newRateTracker(window RateWindow) *rateTracker {
+	if window.Limit <= 0 || window.Window <= 0 {
+		return nil
+	}
+	return &rateTracker{window: window}
+}
+
+// pause returns how long to wait at now before another request fits in the window
+func (t *rateTracker) pause(now time.Time) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.forget(now)
+	until := t.blocked
+	if len(t.sent) >= t.window.Limit {
+		if free := t.sent[len(t.sent)-t.window.Limit].Add(t.window.Window); free.After(until) {
+			until = free
+		}
+	}
+	return max(until.Sub(now), 0)
+}
+
+// record counts a request made at now, noting the rate limit headers on its response
+// if it got one
+func (t *rateTracker) record(now time.Time, header http.Header) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.forget(now)
+	t.sent = append(t.sent, now)
+	if limit, ok := ParseRateLimit(header); ok && limit.Remaining <= 0 && limit.Reset.After(now) {
+		t.blocked = limit.Reset
+	}
+}
+
+// forget drops requests that have left the window
+func (t *rateTracker) forget(now time.Time) {
+	cutoff := now.Add(-t.window.Window)
+	i := 0
+	for i < len(t.sent) && !t.sent[i].After(cutoff) {
+		i++
+	}
+	t.sent = t.sent[i:]
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateTracker(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newRateTracker(RateWindow{Limit: 3, Window: 10 * time.Minute})
+
+	// The window fills without waiting
+	for i := range 3 {
+		now := start.Add(time.Duration(i) * time.Minute)
+		if pause := tracker.pause(now); pause != 0 {
+			t.Fatalf("Expected no pause for request %d, got %v", i+1, pause)
+		}
+		tracker.record(now, nil)
+	}
+
+	// The fourth waits until the first leaves the window, and no longer
+	if pause := tracker.pause(start.Add(3 * time.Minute)); pause != 7*time.Minute {
+		t.Errorf("Expected a 7m pause, got %v", pause)
+	}
+	tracker.record(start.Add(10*time.Minute), nil)
+	if pause := tracker.pause(start.Add(10 * time.Minute)); pause != time.Minute {
+		t.Errorf("Expected a 1m pause once the second request is next to leave, got %v", pause)
+	}
+	if pause := tracker.pause(start.Add(time.Hour)); pause != 0 {
+		t.Errorf("Expected no pause once the window has passed, got %v", pause)
+	}
+}
+
+func TestRateTracker_ServerHeaders(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newRateTracker(RateWindow{Limit: 30, Window: 30 * time.Minute})
+
+	// Requests made elsewhere used up the window, which only the server knows
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "30")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", now.Add(12*time.Minute).Format(time.RFC3339))
+	tracker.record(now, header)
+	if pause := tracker.pause(now); pause != 12*time.Minute {
+		t.Errorf("Expected to wait for the server's reset, got %v", pause)
+	}
+
+	// A reset already passed doesn't hold anything up
+	header.Set("X-RateLimit-Reset", now.Add(-time.Minute).Format(time.RFC3339))
+	tracker = newRateTracker(RateWindow{Limit: 30, Window: 30 * time.Minute})
+	tracker.record(now, header)
+	if pause := tracker.pause(now); pause != 0 {
+		t.Errorf("Expected no pause after a past reset, got %v", pause)
+	}
+}
+
+func TestRateTracker_Nil(t *testing.T) {
+	tracker := newRateTracker(RateWindow{})
+	if tracker != nil {
+		t.Fatalf("Expected no tracker for no limit, got %+v", tracker)
+	}
+	tracker.record(time.Now(), nil)
+	if pause := tracker.pause(time.Now()); pause != 0 {
+		t.Errorf("Expected a nil tracker never to pause, got %v", pause)
+	}
+}
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelationKind is a kind of tie to another account that can be listed and removed
+type RelationKind string
+
+const (
+	RelationFollow RelationKind = "follow" // An account you follow
+	RelationMute   RelationKind = "mute"   // An account you've muted
+	RelationBlock  RelationKind = "block"  // An account you've blocked
+)
+
+// AllRelationKinds lists every relation kind, in the order they're processed
+var AllRelationKinds = []RelationKind{RelationFollow, RelationMute, RelationBlock}
+
+// ParseRelationKinds parses a comma-separated list of relation kinds, accepting plurals
+// ("follows,mutes") and "all"
+func ParseRelationKinds(kindsStr string) ([]RelationKind, error) {
+	if strings.TrimSpace(strings.ToLower(kindsStr)) == "all" {
+		return AllRelationKinds, nil
+	}
+
+	var kinds []RelationKind
+	seen := make(map[RelationKind]bool)
+	for _, name := range strings.Split(kindsStr, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		kind := RelationKind(strings.TrimSuffix(name, "s"))
+		switch kind {
+		case RelationFollow, RelationMute, RelationBlock:
+		default:
+			return nil, fmt.Errorf("unknown relation kind %q: use follows, mutes, blocks or all", name)
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("no relation kinds given: use follows, mutes, blocks or all")
+	}
+	return kinds, nil
+}
+
+// Relation is a follow, mute or block of another account
+type Relation struct {
+	Kind        RelationKind `json:"kind"`
+	ID          string       `json:"id"`         // What removing it takes: the record URI or account ID
+	AccountID   string       `json:"account_id"` // The other account's DID or account ID
+	Handle      string       `json:"handle"`
+	DisplayName string       `json:"display_name,omitempty"`
+	CreatedAt   *time.Time   `json:"created_at,omitempty"`  // When the relation was made, where the platform records it
+	LastActive  *time.Time   `json:"last_active,omitempty"` // When the account last posted, if it was looked up and has posted
+}
+
+// RelationsManager is implemented by clients that can list and remove an account's
+// follows, mutes and blocks
+type RelationsManager interface {
+	// ListRelations returns the account's relations of one kind. With activity set, each
+	// account's LastActive is looked up too, which can take a request per account.
+	// Returns ErrNotOwnAccount if username isn't the authenticated account.
+	ListRelations(ctx context.Context, username string, kind RelationKind, activity bool) ([]Relation, error)
+
+	// RemoveRelation unfollows, unmutes or unblocks the account in a relation
+	RemoveRelation(ctx context.Context, username string, relation Relation) error
+}
+
+// RelationPruneOptions defines which relations to remove. When both criteria are set, a
+// relation has to meet both.
+type RelationPruneOptions struct {
+	InactiveFor    *time.Duration // Remove relations with accounts that haven't posted for this long
+	OlderThan      *time.Duration // Remove relations made longer ago than this
+	DryRun         bool           // Only show what would be removed
+	RateLimitDelay time.Duration  // Delay between removals to respect rate limits
+
+	// Confirm is asked once the matching relations are known, before any is removed
+	// (nil removes them right away)
+	Confirm func(kind RelationKind, matched []Relation) bool
+}
+
+// Matches reports whether a relation meets the criteria. A relation whose age or
+// activity the platform doesn't report never matches that criterion, so it's kept.
+func (o RelationPruneOptions) Matches(relation Relation, now time.Time) bool {
+	if o.InactiveFor == nil && o.OlderThan == nil {
+		return false
+	}
+	if o.InactiveFor != nil && (relation.LastActive == nil || now.Sub(*relation.LastActive) <= *o.InactiveFor) {
+		return false
+	}
+	if o.OlderThan != nil && (relation.CreatedAt == nil || now.Sub(*relation.CreatedAt) <= *o.OlderThan) {
+		return false
+	}
+	return true
+}
+
+// RelationPruneResult is the outcome of pruning one kind of relation
+type RelationPruneResult struct {
+	Kind         RelationKind `json:"kind"`
+	Examined     int          `json:"examined"`
+	Matched      []Relation   `json:"matched"`
+	RemovedCount int          `json:"removed_count"`
+	ErrorsCount  int          `json:"errors_count"`
+	Errors       []string     `json:"errors"`
+	Cancelled    bool         `json:"cancelled,omitempty"` // Confirm declined, so nothing was removed
+}
+
+// PruneRelations removes the relations of one kind that match the options, or with
+// DryRun just reports them. If ctx is cancelled, the partial result is returned along
+// with ctx.Err().
+func PruneRelations(ctx context.Context, manager RelationsManager, username string, kind RelationKind, options RelationPruneOptions, now time.Time) (*RelationPruneResult, error) {
+	relations, err := manager.ListRelations(ctx, username, kind, options.InactiveFor != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RelationPruneResult{Kind: kind, Examined: len(relations), Matched: []Relation{}, Errors: []string{}}
+	for _, relation := range relations {
+		if options.Matches(relation, now) {
+			result.Matched = append(result.Matched, relation)
+		}
+	}
+	if options.DryRun || len(result.Matched) == 0 {
+		return result, nil
+	}
+	if options.Confirm != nil && !options.Confirm(kind, result.Matched) {
+		result.Cancelled = true
+		return result, nil
+	}
+
+	for _, relation := range result.Matched {
+		if err := sleepContext(ctx, options.RateLimitDelay); err != nil {
+			return result, err
+		}
+		logger := WithOperation("prune_relations").With().Str("kind", string(kind)).Str("account", relation.Handle).Logger()
+		if err := manager.RemoveRelation(ctx, username, relation); err != nil {
+			logger.Error().Err(err).Msg("Failed to remove relation")
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to remove %s of @%s: %v", kind, relation.Handle, err))
+			result.ErrorsCount++
+			continue
+		}
+		logger.Info().Msg("Relation removed")
+		result.RemovedCount++
+	}
+	return result, nil
+}
+
+// parseRelationTime parses a timestamp or date from a relation listing, returning nil
+// when there's none
+func parseRelationTime(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRelationKinds(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []RelationKind
+		wantErr bool
+	}{
+		{input: "follows", want: []RelationKind{RelationFollow}},
+		{input: "Mutes, block", want: []RelationKind{RelationMute, RelationBlock}},
+		{input: "all", want: AllRelationKinds},
+		{input: "follows,follow", want: []RelationKind{RelationFollow}},
+		{input: "friends", wantErr: true},
+		{input: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRelationKinds(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRelationKinds(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseRelationKinds(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseRelationKinds(%q) = %v, want %v", tt.input, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRelationPruneOptions_Matches(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sixMonths := 180 * 24 * time.Hour
+	year := 365 * 24 * time.Hour
+	longAgo := now.Add(-2 * year)
+	recently := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		options  RelationPruneOptions
+		relation Relation
+		want     bool
+	}{
+		{
+			name:     "no criteria never matches",
+			relation: Relation{LastActive: &longAgo, CreatedAt: &longAgo},
+		},
+		{
+			name:     "inactive account",
+			options:  RelationPruneOptions{InactiveFor: &sixMonths},
+			relation: Relation{LastActive: &longAgo},
+			want:     true,
+		},
+		{
+			name:     "active account",
+			options:  RelationPruneOptions{InactiveFor: &sixMonths},
+			relation: Relation{LastActive: &recently},
+		},
+		{
+			name:     "unknown activity is kept",
+			options:  RelationPruneOptions{InactiveFor: &sixMonths},
+			relation: Relation{},
+		},
+		{
+			name:     "old relation",
+			options:  RelationPruneOptions{OlderThan: &year},
+			relation: Relation{CreatedAt: &longAgo},
+			want:     true,
+		},
+		{
+			name:     "unknown age is kept",
+			options:  RelationPruneOptions{OlderThan: &year},
+			relation: Relation{LastActive: &longAgo},
+		},
+		{
+			name:     "both criteria have to match",
+			options:  RelationPruneOptions{InactiveFor: &sixMonths, OlderThan: &year},
+			relation: Relation{LastActive: &longAgo, CreatedAt: &recently},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.Matches(tt.relation, now); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRelationsManager serves a fixed set of relations and records which were removed
+type fakeRelationsManager struct {
+	relations []Relation
+	failing   map[string]bool
+	activity  bool
+	removed   []string
+}
+
+func (m *fakeRelationsManager) ListRelations(ctx context.Context, username string, kind RelationKind, activity bool) ([]Relation, error) {
+	m.activity = activity
+	var relations []Relation
+	for _, relation := range m.relations {
+		if relation.Kind == kind {
+			relations = append(relations, relation)
+		}
+	}
+	return relations, nil
+}
+
+func (m *fakeRelationsManager) RemoveRelation(ctx context.Context, username string, relation Relation) error {
+	if m.failing[relation.ID] {
+		return errors.New("rate limited")
+	}
+	m.removed = append(m.removed, relation.ID)
+	return nil
+}
+
+func TestPruneRelations(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sixMonths := 180 * 24 * time.Hour
+	longAgo := now.Add(-365 * 24 * time.Hour)
+	recently := now.Add(-24 * time.Hour)
+	relations := []Relation{
+		{Kind: RelationFollow, ID: "quiet", Handle: "quiet", LastActive: &longAgo},
+		{Kind: RelationFollow, ID: "chatty", Handle: "chatty", LastActive: &recently},
+		{Kind: RelationFollow, ID: "stuck", Handle: "stuck", LastActive: &longAgo},
+		{Kind: RelationMute, ID: "muted", Handle: "muted", LastActive: &longAgo},
+	}
+
+	t.Run("dry run removes nothing", func(t *testing.T) {
+		manager := &fakeRelationsManager{relations: relations}
+		result, err := PruneRelations(context.Background(), manager, "me", RelationFollow, RelationPruneOptions{InactiveFor: &sixMonths, DryRun: true}, now)
+		if err != nil {
+			t.Fatalf("PruneRelations() error = %v", err)
+		}
+		if !manager.activity {
+			t.Error("Expected activity to be looked up for --inactive-for")
+		}
+		if result.Examined != 3 || len(result.Matched) != 2 || len(manager.removed) != 0 {
+			t.Errorf("Expected 2 of 3 follows matched and none removed, got %+v, removed %v", result, manager.removed)
+		}
+	})
+
+	t.Run("removes matches and reports failures", func(t *testing.T) {
+		manager := &fakeRelationsManager{relations: relations, failing: map[string]bool{"stuck": true}}
+		result, err := PruneRelations(context.Background(), manager, "me", RelationFollow, RelationPruneOptions{InactiveFor: &sixMonths}, now)
+		if err != nil {
+			t.Fatalf("PruneRelations() error = %v", err)
+		}
+		if result.RemovedCount != 1 || result.ErrorsCount != 1 || strings.Join(manager.removed, ",") != "quiet" {
+			t.Errorf("Expected quiet removed and stuck failed, got %+v, removed %v", result, manager.removed)
+		}
+		if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "follow of @stuck") {
+			t.Errorf("Unexpected errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("declining confirmation removes nothing", func(t *testing.T) {
+		manager := &fakeRelationsManager{relations: relations}
+		var asked []Relation
+		options := RelationPruneOptions{InactiveFor: &sixMonths, Confirm: func(kind RelationKind, matched []Relation) bool {
+			asked = matched
+			return false
+		}}
+		result, err := PruneRelations(context.Background(), manager, "me", RelationFollow, options, now)
+		if err != nil {
+			t.Fatalf("PruneRelations() error = %v", err)
+		}
+		if len(asked) != 2 || !result.Cancelled || len(manager.removed) != 0 {
+			t.Errorf("Expected to be asked about 2 follows and remove none, got %+v, removed %v", result, manager.removed)
+		}
+	})
+}
+
+func TestParseRelationTime(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "2024-05-30", want: "2024-05-30T00:00:00Z"},
+		{input: "2019-11-24T12:34:56.000Z", want: "2019-11-24T12:34:56Z"},
+		{input: "", want: ""},
+		{input: "yesterday", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseRelationTime(tt.input)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("parseRelationTime(%q) = %v, want nil", tt.input, got)
+				}
+				return
+			}
+			if got == nil || got.Format(time.RFC3339) != tt.want {
+				t.Errorf("parseRelationTime(%q) = %v, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RetryConfig controls how transient HTTP failures are retried
+type RetryConfig struct {
+	MaxRetries     int           // Number of retries after the first attempt (0 disables retries)
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Upper bound on any single delay
+}
+
+// DefaultRetryConfig returns the retry settings used when none are configured
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+var (
+	retryConfig   = DefaultRetryConfig()
+	retryConfigMu sync.RWMutex
+)
+
+// SetRetryConfig sets the retry behaviour for all platform HTTP requests
+func SetRetryConfig(config RetryConfig) {
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultRetryConfig().InitialBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+
+	retryConfigMu.Lock()
+	defer retryConfigMu.Unlock()
+	retryConfig = config
+}
+
+// GetRetryConfig returns the current retry configuration
+func GetRetryConfig() RetryConfig {
+	retryConfigMu.RLock()
+	defer retryConfigMu.RUnlock()
+	return retryConfig
+}
+
+// Backoff returns the delay before the given retry (0-based), doubling each time up to
+// MaxBackoff, with jitter so that concurrent clients don't retry in lockstep
+func (rc RetryConfig) Backoff(retry int) time.Duration {
+	delay := rc.InitialBackoff
+	for i := 0; i < retry && delay < rc.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > rc.MaxBackoff {
+		delay = rc.MaxBackoff
+	}
+	// Pick a delay between half and the full backoff
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isRetryableStatus returns true for server-side failures that are worth retrying
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= 500 && statusCode <= 599
+}
+
+// isIdempotentRequest reports whether repeating a request after a server error is safe.
+// As in net/http, a non-idempotent request opts in by carrying an Idempotency-Key header.
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case "", "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	_, hasKey := req.Header["Idempotency-Key"]
+	return hasKey
+}
+
+// markIdempotent lets doWithRetry retry a POST that is safe to repeat, such as an XRPC
+// record delete. As in net/http, an Idempotency-Key header with no value opts in without
+// being sent.
+func markIdempotent(req *http.Request) {
+	req.Header["Idempotency-Key"] = nil
+}
+
+// doWithRetry executes a request, retrying network errors and 5xx responses with
+// exponential backoff. A non-idempotent request such as a record create or createSession
+// may have been applied despite a 5xx or a timeout, so its response or error is returned
+// rather than retried. Requests whose body can't be replayed are only attempted once.
+func doWithRetry(client *http.Client, req *http.Request) (*http.Response, error) {
+	config := GetRetryConfig()
+	if req.Body != nil && req.GetBody == nil {
+		config.MaxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to reset request body for retry: %w", err)
+			}
+			req.Body = body
+		}
+
+		if !RequestBudgetFromContext(req.Context()).take() {
+			return nil, ErrRequestBudgetExhausted
+		}
+		resp, err := client.Do(req)
+		observeAPIResponse(req.Context(), resp)
+		if attempt >= config.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if (err == nil && !isRetryableStatus(resp.StatusCode)) || !isIdempotentRequest(req) {
+			return resp, err
+		}
+
+		delay := config.Backoff(attempt)
+		logger := WithHTTP(req.Method, req.URL.String())
+		if err != nil {
+			logger.Warn().Err(err).Int("attempt", attempt+1).Dur("retry_in", delay).Msg("HTTP request failed, retrying")
+		} else {
+			logger.Warn().Int("status_code", resp.StatusCode).Int("attempt", attempt+1).Dur("retry_in", delay).Msg("Server error, retrying")
+			resp.Body.Close()
+		}
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepContext waits for the given duration, returning early with ctx.Err() if ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// doRequest executes a request with retries and logs it. The response is only logged once
+// it is known to exist, so network failures surface as errors rather than nil-response
+// panics. Errors are wrapped with the method and redacted URL; the original cause is
+// still reachable with errors.Is / errors.As.
+func doRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	LogHTTPRequest(req.Method, req.URL.String())
+
+	resp, err := doWithRetry(client, req)
+	if err != nil {
+		WithHTTP(req.Method, req.URL.String()).Error().Err(err).Msg("HTTP request failed")
+		// url.Error repeats the unredacted URL, so wrap its cause instead
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("%s %s: %w", req.Method, RedactSensitiveURL(req.URL.String()), err)
+	}
+
+	LogHTTPResponse(req.Method, req.URL.String(), resp.StatusCode, resp.Status)
+	return resp, nil
+}
+
+// httpGetWithRetry is a retrying replacement for http.Get used by unauthenticated fetches.
+// Unlike http.Get it uses the shared client, so requests are bounded by the configured timeout.
+func httpGetWithRetry(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRequest(sharedHTTPClient(), req)
+}
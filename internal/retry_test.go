@@ -0,0 +1,354 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func withRetryConfig(t *testing.T, config RetryConfig) {
+	t.Helper()
+	previous := GetRetryConfig()
+	SetRetryConfig(config)
+	t.Cleanup(func() { SetRetryConfig(previous) })
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	config := RetryConfig{MaxRetries: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 1 * time.Second}
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, 1 * time.Second},
+		{10, 1 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := config.Backoff(tt.retry)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Errorf("Backoff(%d) = %v, expected between %v and %v", tt.retry, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestSetRetryConfig_Normalizes(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: -1, InitialBackoff: 0, MaxBackoff: 0})
+
+	config := GetRetryConfig()
+	if config.MaxRetries != 0 {
+		t.Errorf("Expected negative retries to be clamped to 0, got %d", config.MaxRetries)
+	}
+	if config.InitialBackoff != DefaultRetryConfig().InitialBackoff {
+		t.Errorf("Expected default initial backoff, got %v", config.InitialBackoff)
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		t.Errorf("Max backoff %v should not be below initial backoff %v", config.MaxBackoff, config.InitialBackoff)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleepContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := sleepContext(ctx, 0); err != context.Canceled {
+		t.Errorf("Expected context.Canceled for zero delay, got %v", err)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected bool
+	}{
+		{200, false},
+		{400, false},
+		{401, false},
+		{404, false},
+		{429, false},
+		{500, true},
+		{502, true},
+		{503, true},
+		{599, true},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.status); got != tt.expected {
+			t.Errorf("isRetryableStatus(%d) = %v, expected %v", tt.status, got, tt.expected)
+		}
+	}
+}
+
+func TestDoWithRetry(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+	t.Run("retries server errors until success", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		resp, err := httpGetWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200, got %d", resp.StatusCode)
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 attempts, got %d", calls)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		resp, err := httpGetWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("Expected final 502 response, got %d", resp.StatusCode)
+		}
+		if calls != 4 {
+			t.Errorf("Expected 4 attempts, got %d", calls)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		resp, err := httpGetWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if calls != 1 {
+			t.Errorf("Expected 1 attempt, got %d", calls)
+		}
+	})
+
+	t.Run("replays request body on retry", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "payload" {
+				t.Errorf("Attempt %d got body %q", atomic.LoadInt32(&calls)+1, body)
+			}
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		req, _ := http.NewRequest("PUT", server.URL, strings.NewReader("payload"))
+		resp, err := doWithRetry(http.DefaultClient, req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if calls != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls)
+		}
+	})
+
+	t.Run("server errors on non-idempotent requests", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			method    string
+			key       string
+			mark      bool
+			wantCalls int32
+		}{
+			{"POST is not retried", "POST", "", false, 1},
+			{"POST with Idempotency-Key is retried", "POST", "abc123", false, 4},
+			{"POST marked idempotent is retried", "POST", "", true, 4},
+			{"DELETE is retried", "DELETE", "", false, 4},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var calls int32
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&calls, 1)
+					if _, sent := r.Header["Idempotency-Key"]; sent != (tt.key != "") {
+						t.Errorf("Expected the Idempotency-Key header sent only when it has a value, got %q", r.Header.Get("Idempotency-Key"))
+					}
+					w.WriteHeader(http.StatusInternalServerError)
+				}))
+				defer server.Close()
+
+				req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+				if tt.key != "" {
+					req.Header.Set("Idempotency-Key", tt.key)
+				}
+				if tt.mark {
+					markIdempotent(req)
+				}
+				resp, err := doWithRetry(http.DefaultClient, req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusInternalServerError {
+					t.Errorf("Expected the 500 response to be returned, got %d", resp.StatusCode)
+				}
+				if calls != tt.wantCalls {
+					t.Errorf("Expected %d attempts, got %d", tt.wantCalls, calls)
+				}
+			})
+		}
+	})
+
+	t.Run("network errors on non-idempotent requests", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			method    string
+			mark      bool
+			wantCalls int32
+		}{
+			{"POST is not retried", "POST", false, 1},
+			{"POST marked idempotent is retried", "POST", true, 4},
+			{"GET is retried", "GET", false, 4},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// The connection is dropped once the request has arrived, so it may have been applied
+				var calls int32
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&calls, 1)
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Errorf("Hijack() error = %v", err)
+						return
+					}
+					conn.Close()
+				}))
+				defer server.Close()
+
+				req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+				if tt.mark {
+					markIdempotent(req)
+				}
+				if _, err := doWithRetry(&http.Client{Transport: &http.Transport{}}, req); err == nil {
+					t.Fatal("Expected an error from the dropped connection")
+				}
+				if calls != tt.wantCalls {
+					t.Errorf("Expected %d attempts, got %d", tt.wantCalls, calls)
+				}
+			})
+		}
+	})
+
+	t.Run("stops retrying when context is cancelled", func(t *testing.T) {
+		withRetryConfig(t, RetryConfig{MaxRetries: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := httpGetWithRetry(ctx, server.URL)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 attempt before cancellation, got %d", calls)
+		}
+	})
+
+	t.Run("retries network errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		_, err := httpGetWithRetry(context.Background(), url)
+		if err == nil {
+			t.Error("Expected an error from a closed server")
+		}
+	})
+}
+
+func TestDoRequest_NetworkFailure(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 0, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	req, _ := http.NewRequest("POST", serverURL+"/xrpc/com.atproto.server.refreshSession?access_token=secret", nil)
+	resp, err := doRequest(http.DefaultClient, req)
+	if err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if resp != nil {
+		t.Errorf("Expected nil response on network failure, got %v", resp)
+	}
+	if !strings.HasPrefix(err.Error(), "POST ") {
+		t.Errorf("Expected error to be prefixed with the request method, got %q", err.Error())
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected sensitive query parameters to be redacted, got %q", err.Error())
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Errorf("Expected underlying network error to be preserved, got %v", err)
+	}
+}
+
+func TestAuthenticatedHTTPClient_DoRequestNetworkFailure(t *testing.T) {
+	withRetryConfig(t, RetryConfig{MaxRetries: 0, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	client := NewAuthenticatedHTTPClient("token", serverURL, 0)
+	req, err := client.CreateRequest(context.Background(), "GET", "/api/v1/statuses", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if _, err := client.DoRequest(req); err == nil {
+		t.Error("Expected an error from a closed server")
+	}
+}
@@ -0,0 +1,262 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when the next prune run is due
+type Schedule interface {
+	// Next returns the first run time strictly after the given time
+	Next(after time.Time) time.Time
+
+	// RunOnStart reports whether a run should happen as soon as the server starts
+	RunOnStart() bool
+
+	String() string
+}
+
+// IntervalSchedule runs every Interval, starting immediately
+type IntervalSchedule struct {
+	Interval time.Duration
+}
+
+func (s IntervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.Interval)
+}
+
+func (s IntervalSchedule) RunOnStart() bool {
+	return true
+}
+
+func (s IntervalSchedule) String() string {
+	return "every " + s.Interval.String()
+}
+
+// cronMacros are the shorthand schedules accepted in place of five fields
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronMaxSearch bounds how far ahead Next looks, so impossible dates like
+// February 30th can't loop forever
+const cronMaxSearch = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a standard five-field cron expression (minute hour day-of-month month
+// day-of-week), evaluated in the time zone of the times passed to Next. Fields accept
+// *, numbers, names for months and weekdays, ranges (1-5), lists (1,15) and steps (*/15).
+// As in cron, when both day fields are restricted a day matching either one runs.
+type CronSchedule struct {
+	spec       string
+	minutes    []bool
+	hours      []bool
+	daysOfMon  []bool
+	months     []bool
+	daysOfWeek []bool
+	domStar    bool
+	dowStar    bool
+}
+
+// ParseCronSchedule parses a five-field cron expression or a macro such as @daily
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := cronMacros[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule macro %q", expr)
+		}
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day-of-month month day-of-week), got %d", spec, len(fields))
+	}
+
+	s := &CronSchedule{spec: spec}
+	var err error
+	if s.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if s.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if s.daysOfMon, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if s.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	// 7 is accepted as Sunday, as most crons do
+	if s.daysOfWeek, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+	if s.daysOfWeek[7] {
+		s.daysOfWeek[0] = true
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// parseCronField expands one field into a lookup table indexed by value
+func parseCronField(field string, min, max int, names map[string]int) ([]bool, error) {
+	values := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepStr, ok := strings.Cut(part, "/"); ok {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepStr)
+			}
+			part = base
+		}
+
+		var lo, hi int
+		switch {
+		case part == "*" || part == "?":
+			lo, hi = min, max
+		case strings.Contains(part, "-"):
+			loStr, hiStr, _ := strings.Cut(part, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, names); err != nil {
+				return nil, err
+			}
+			if hi, err = parseCronValue(hiStr, names); err != nil {
+				return nil, err
+			}
+		default:
+			value, err := parseCronValue(part, names)
+			if err != nil {
+				return nil, err
+			}
+			lo, hi = value, value
+			// "5/15" means from 5 to the end of the range in steps of 15
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return value, nil
+}
+
+// Next returns the first matching minute strictly after the given time, or the zero time
+// if nothing matches within five years
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(cronMaxSearch)
+
+	for t.Before(limit) {
+		if !s.months[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.daysOfMon[t.Day()]
+	dowMatch := s.daysOfWeek[t.Weekday()]
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowMatch
+	case s.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// RunOnStart is false, since a cron schedule exists to pick when runs happen
+func (s *CronSchedule) RunOnStart() bool {
+	return false
+}
+
+func (s *CronSchedule) String() string {
+	return s.spec
+}
+
+// ParsePruneSchedules reads --prune-schedule values, each either a bare cron expression
+// applying to every platform or "platform=expression" for one platform. The result maps
+// platform names to schedules, with "" holding the default.
+func ParsePruneSchedules(values []string) (map[string]Schedule, error) {
+	schedules := make(map[string]Schedule)
+	for _, value := range values {
+		platform := ""
+		spec := value
+		if name, rest, ok := strings.Cut(value, "="); ok {
+			platform = strings.ToLower(strings.TrimSpace(name))
+			spec = rest
+			if _, exists := SupportedPlatforms[platform]; !exists {
+				return nil, fmt.Errorf("unsupported platform '%s' in schedule %q", platform, value)
+			}
+		}
+		if _, exists := schedules[platform]; exists {
+			if platform == "" {
+				return nil, fmt.Errorf("more than one default schedule given")
+			}
+			return nil, fmt.Errorf("more than one schedule given for %s", platform)
+		}
+		schedule, err := ParseCronSchedule(spec)
+		if err != nil {
+			return nil, err
+		}
+		schedules[platform] = schedule
+	}
+	return schedules, nil
+}
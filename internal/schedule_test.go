@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday 2025-01-15 10:17 UTC
+	from := time.Date(2025, 1, 15, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"daily at 3am", "0 3 * * *", time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"later today", "30 22 * * *", time.Date(2025, 1, 15, 22, 30, 0, 0, time.UTC)},
+		{"every minute", "* * * * *", time.Date(2025, 1, 15, 10, 18, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"step from offset", "5/20 * * * *", time.Date(2025, 1, 15, 10, 25, 0, 0, time.UTC)},
+		{"hour range", "0 1-4 * * *", time.Date(2025, 1, 16, 1, 0, 0, 0, time.UTC)},
+		{"list", "0 9,12 * * *", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"weekday name", "0 3 * * sun", time.Date(2025, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 3 * * 7", time.Date(2025, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{"month name", "0 0 1 mar *", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 20 * mon", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"daily macro", "@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"hourly macro", "@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"impossible date", "0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q) failed: %v", tt.spec, err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_NextIsStrictlyAfter(t *testing.T) {
+	schedule, _ := ParseCronSchedule("0 3 * * *")
+	at := time.Date(2025, 1, 15, 3, 0, 0, 0, time.UTC)
+	if got := schedule.Next(at); !got.Equal(at.Add(24 * time.Hour)) {
+		t.Errorf("Expected the following day, got %v", got)
+	}
+}
+
+func TestCronSchedule_LocalTimeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	schedule, _ := ParseCronSchedule("0 3 * * *")
+
+	// Clocks go forward at 01:00 on 2025-03-30; 3am local still comes once a day
+	from := time.Date(2025, 3, 29, 12, 0, 0, 0, loc)
+	want := time.Date(2025, 3, 30, 3, 0, 0, 0, loc)
+	if got := schedule.Next(from); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestParseCronSchedule_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"abc * * * *",
+		"@fortnightly",
+	} {
+		if _, err := ParseCronSchedule(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestIntervalSchedule(t *testing.T) {
+	schedule := IntervalSchedule{Interval: 2 * time.Hour}
+	from := time.Date(2025, 1, 15, 10, 17, 0, 0, time.UTC)
+	if got := schedule.Next(from); !got.Equal(from.Add(2 * time.Hour)) {
+		t.Errorf("Next() = %v", got)
+	}
+	if !schedule.RunOnStart() {
+		t.Error("Interval schedules should run on start")
+	}
+	if schedule.String() != "every 2h0m0s" {
+		t.Errorf("Unexpected String() %q", schedule.String())
+	}
+
+	cron, _ := ParseCronSchedule("0 3 * * *")
+	if cron.RunOnStart() {
+		t.Error("Cron schedules should wait for their first slot")
+	}
+}
+
+func TestParsePruneSchedules(t *testing.T) {
+	schedules, err := ParsePruneSchedules([]string{"0 3 * * *", "mastodon=30 4 * * *"})
+	if err != nil {
+		t.Fatalf("ParsePruneSchedules failed: %v", err)
+	}
+	if schedules[""].String() != "0 3 * * *" {
+		t.Errorf("Unexpected default schedule %v", schedules[""])
+	}
+	if schedules["mastodon"].String() != "30 4 * * *" {
+		t.Errorf("Unexpected mastodon schedule %v", schedules["mastodon"])
+	}
+	if _, ok := schedules["bluesky"]; ok {
+		t.Error("bluesky should fall back to the default")
+	}
+
+	errorCases := [][]string{
+		{"myspace=0 3 * * *"},
+		{"0 3 * * *", "0 4 * * *"},
+		{"bluesky=0 3 * * *", "bluesky=0 4 * * *"},
+		{"bluesky=not cron"},
+	}
+	for _, values := range errorCases {
+		if _, err := ParsePruneSchedules(values); err == nil {
+			t.Errorf("Expected error for %v", values)
+		}
+	}
+}
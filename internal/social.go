@@ -1,13 +1,18 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,6 +27,14 @@ const (
 	PostTypeQuote    PostType = "quote"    // Quote post/retweet with comment
 )
 
+// FetchProfile selects how much a fetch resolves beyond the posts themselves
+type FetchProfile int
+
+const (
+	FetchProfileDisplay FetchProfile = iota // Resolve everything shown when listing posts
+	FetchProfilePrune                       // Skip decorative lookups that don't affect prune decisions
+)
+
 // Post represents a generic social media post
 type Post struct {
 	ID        string    `json:"id"`
@@ -38,10 +51,13 @@ type Post struct {
 	OriginalPost   *Post  `json:"original_post,omitempty"`   // The original post being shared
 	OriginalAuthor string `json:"original_author,omitempty"` // Display name of original author
 	OriginalHandle string `json:"original_handle,omitempty"` // Handle of original author
+	SelfRepost     bool   `json:"self_repost,omitempty"`     // Repost of one of the author's own posts
+	SelfQuote      bool   `json:"self_quote,omitempty"`      // Quote of one of the author's own posts
 
 	// Reply metadata
 	InReplyToID     string `json:"in_reply_to_id,omitempty"`     // ID of post being replied to
 	InReplyToAuthor string `json:"in_reply_to_author,omitempty"` // Author of post being replied to
+	SelfReply       bool   `json:"self_reply,omitempty"`         // Reply to one of the author's own posts, as in a thread
 
 	// Engagement metrics
 	RepostCount int `json:"repost_count,omitempty"` // Number of reposts/retweets
@@ -52,59 +68,753 @@ type Post struct {
 	IsLikedByUser bool `json:"is_liked_by_user,omitempty"` // Whether the viewing user has liked this post
 	IsPinned      bool `json:"is_pinned,omitempty"`        // Whether this post is pinned by the author
 
+	// Hashtags on the post, without the leading '#'
+	Hashtags []string `json:"hashtags,omitempty"`
+
+	// Languages the post is tagged as written in, such as "en" or "pt-BR", if any
+	Languages []string `json:"languages,omitempty"`
+
+	// Content warning and sensitive flag, as Mastodon's spoiler_text and sensitive
+	ContentWarning string `json:"content_warning,omitempty"` // Text shown in place of the hidden content
+	Sensitive      bool   `json:"sensitive,omitempty"`       // Media or content marked sensitive
+
+	// Who can see the post: one of the Visibility constants, or empty if unknown
+	Visibility string `json:"visibility,omitempty"`
+
+	// Media attached to the post
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Web links in the post's text or link card, not counting mentions and hashtags
+	Links []string `json:"links,omitempty"`
+
 	// Platform-specific metadata
 	Platform string                 `json:"platform"`           // Which platform this post is from
 	RawData  map[string]interface{} `json:"raw_data,omitempty"` // Platform-specific raw data
 }
 
+// targetID returns the ID of the post an action on p really affects: the liked or shared
+// post for like and repost records when it's known, and p itself otherwise
+func (p Post) targetID() string {
+	if (p.Type == PostTypeLike || p.Type == PostTypeRepost) && p.OriginalPost != nil {
+		return p.OriginalPost.ID
+	}
+	return p.ID
+}
+
+// SharesOwnPost returns true if the post is a repost or quote of one of the author's
+// own posts
+func (p Post) SharesOwnPost() bool {
+	return p.SelfRepost || p.SelfQuote
+}
+
+// HasMedia returns true if the post has any media attachments
+func (p Post) HasMedia() bool {
+	return len(p.Attachments) > 0
+}
+
+// IsSensitive returns true if the post is marked sensitive or hidden behind a content warning
+func (p Post) IsSensitive() bool {
+	return p.Sensitive || p.ContentWarning != ""
+}
+
+// Attachment describes a media item attached to a post
+type Attachment struct {
+	Type    string `json:"type"`               // image, video, gifv, audio or unknown
+	URL     string `json:"url,omitempty"`      // Where the media can be viewed, if the platform provides it
+	AltText string `json:"alt_text,omitempty"` // Description of the media for accessibility
+}
+
 // PruneOptions defines criteria for pruning posts
 type PruneOptions struct {
-	MaxAge           *time.Duration `json:"max_age,omitempty"`     // Delete posts older than this duration
-	BeforeDate       *time.Time     `json:"before_date,omitempty"` // Delete posts created before this date
-	PreserveSelfLike bool           `json:"preserve_self_like"`    // Don't delete user's own posts they've liked
-	PreservePinned   bool           `json:"preserve_pinned"`       // Don't delete pinned posts
-	UnlikePosts      bool           `json:"unlike_posts"`          // Unlike posts instead of deleting them
-	UnshareReposts   bool           `json:"unshare_reposts"`       // Unshare/unrepost instead of deleting reposts
-	DryRun           bool           `json:"dry_run"`               // Only show what would be deleted
-	RateLimitDelay   time.Duration  `json:"rate_limit_delay"`      // Delay between API requests to respect rate limits
+	MaxAge               *time.Duration   `json:"max_age,omitempty"`                // Delete posts older than this duration
+	BeforeDate           *time.Time       `json:"before_date,omitempty"`            // Delete posts created before this date
+	AfterDate            *time.Time       `json:"after_date,omitempty"`             // Only delete posts created on or after this date
+	PreserveSelfLike     bool             `json:"preserve_self_like"`               // Don't delete user's own posts they've liked
+	PreservePinned       bool             `json:"preserve_pinned"`                  // Don't delete pinned posts
+	PreserveWithReplies  bool             `json:"preserve_with_replies"`            // Don't delete posts other people have replied to
+	UnlikePosts          bool             `json:"unlike_posts"`                     // Unlike posts instead of deleting them
+	UnshareReposts       bool             `json:"unshare_reposts"`                  // Unshare/unrepost instead of deleting reposts
+	Redact               bool             `json:"redact"`                           // Edit own posts to RedactedContent instead of deleting them
+	UnshareSelfReposts   bool             `json:"unshare_self_reposts"`             // Also unshare reposts of your own posts, leaving the original alone
+	SkipSelfReposts      bool             `json:"skip_self_reposts"`                // Never touch reposts or quotes of your own posts
+	OnlySelfReposts      bool             `json:"only_self_reposts"`                // Only prune reposts and quotes of your own posts
+	DeleteWholeThreads   bool             `json:"delete_whole_threads"`             // Also delete your replies under a self-thread whose root is deleted
+	BatchWrites          bool             `json:"batch_writes"`                     // Group record deletions into batched requests where the platform supports it
+	DryRun               bool             `json:"dry_run"`                          // Only show what would be deleted
+	RateLimitDelay       time.Duration    `json:"rate_limit_delay"`                 // Delay between API requests to respect rate limits
+	ContinueUntilEnd     bool             `json:"continue_until_end"`               // Walk the entire timeline instead of just the most recent page
+	DeleteMedia          bool             `json:"delete_media"`                     // Have the server remove a deleted post's media right away, where it supports that
+	FromIndex            bool             `json:"from_index"`                       // Select from the local post index built by sync instead of fetching the timeline
+	MaxLikes             *int             `json:"max_likes,omitempty"`              // Only prune posts with at most this many likes
+	MaxReposts           *int             `json:"max_reposts,omitempty"`            // Only prune posts with at most this many reposts
+	MaxReplies           *int             `json:"max_replies,omitempty"`            // Only prune posts with at most this many replies
+	PreserveHashtags     []string         `json:"preserve_hashtags,omitempty"`      // Don't delete posts tagged with any of these (normalized by ParseHashtags)
+	WithHashtags         []string         `json:"with_hashtags,omitempty"`          // Only prune posts tagged with one of these (normalized by ParseHashtags)
+	PreserveLanguages    []string         `json:"preserve_languages,omitempty"`     // Don't delete posts in any of these languages (normalized by ParseLanguages)
+	WithLanguages        []string         `json:"with_languages,omitempty"`         // Only prune posts in one of these languages (normalized by ParseLanguages)
+	MediaOnly            bool             `json:"media_only"`                       // Only prune posts with media attachments
+	WithLinks            bool             `json:"with_links"`                       // Only prune posts containing links
+	LinksTo              []string         `json:"links_to,omitempty"`               // Only prune posts linking to one of these domains (normalized by ParseDomains)
+	SkipMedia            bool             `json:"skip_media"`                       // Don't delete posts with media attachments
+	RepliesOnly          bool             `json:"replies_only"`                     // Only prune replies to other people's posts
+	SkipReplies          bool             `json:"skip_replies"`                     // Don't delete replies
+	OnlySensitive        bool             `json:"only_sensitive"`                   // Only prune posts marked sensitive or with a content warning
+	PreserveCW           bool             `json:"preserve_cw"`                      // Don't delete posts with a content warning
+	Visibilities         []string         `json:"visibilities,omitempty"`           // Only prune posts with one of these visibilities (normalized by ParseVisibilities)
+	PreserveDirect       bool             `json:"preserve_direct"`                  // Don't delete direct messages
+	ExcludePosts         []string         `json:"exclude_posts,omitempty"`          // Never touch these posts, as PostRefKeys of their URLs or IDs
+	ListedPosts          []string         `json:"listed_posts,omitempty"`           // Only act on these posts, as PostRefKeys, looked up directly instead of scanning the timeline
+	LikedPostAge         bool             `json:"liked_post_age"`                   // Judge likes by when the liked post was made instead of when it was liked
+	ProgressEvery        int              `json:"progress_every,omitempty"`         // Summarize progress every N posts instead of printing each one
+	ProgressInterval     time.Duration    `json:"progress_interval,omitempty"`      // Summarize progress at least this often instead of printing each one
+	Deadline             time.Time        `json:"deadline,omitempty"`               // Stop before starting any action after this time (zero for no limit)
+	AllowAccountMismatch bool             `json:"allow_account_mismatch,omitempty"` // Go ahead, with a warning, when the target isn't the authenticated account
+	Confirm              ConfirmFunc      `json:"-"`                                // Asked before acting on each matching post (nil acts on all of them)
+	ConfirmRun           ConfirmRunFunc   `json:"-"`                                // Asked once the run's estimate is known, before any action (nil starts right away)
+	OnlyPostIDs          map[string]bool  `json:"-"`                                // When set, only these matching posts are acted on, as picked with review
+	Checkpoint           *PruneCheckpoint `json:"-"`                                // Kept up to date as the run goes so it can be resumed, and where a resumed run starts (nil for none)
+	Archive              *PostArchive     `json:"-"`                                // Each post is saved here before it's deleted or redacted, so restore can post it again (nil for none)
+	Output               io.Writer        `json:"-"`                                // Where the run prints its pacing, progress and each action as it's taken (nil for stdout)
+
+	// PlannedActions is the action a plan gives each post, by ID. The run fails rather than
+	// take a different one, so applying a plan does exactly what it says or nothing.
+	PlannedActions map[string]string `json:"-"`
+
+	threadReplies map[string]bool // Replies pulled in by DeleteWholeThreads regardless of age, set by withWholeThreads
+}
+
+// PruneDecision is the answer to a Confirm prompt about one post
+type PruneDecision int
+
+const (
+	PruneProceed PruneDecision = iota // Act on this post
+	PruneSkip                         // Leave this post alone and ask about the next
+	PruneAll                          // Act on this post and every remaining one without asking
+	PruneQuit                         // Leave this post and every remaining one alone
+)
+
+// ConfirmFunc decides whether a prune acts on post. action is what would be done to it:
+// "delete", "redact", "unlike" or "unshare".
+type ConfirmFunc func(post Post, action string) PruneDecision
+
+// ConfirmRunFunc decides whether a prune goes ahead once it knows how many actions it will
+// take and how long they'll take, before acting on any post
+type ConfirmRunFunc func(platform string, plan PacingPlan) bool
+
+// RedactedContent replaces the text of posts redacted with PruneOptions.Redact
+const RedactedContent = "[removed by cringesweeper]"
+
+// PruneAction returns what a prune does to a matching post: "delete", "unlike" or
+// "unshare", or "" for post types that are never acted on
+func PruneAction(post Post) string {
+	switch post.Type {
+	case PostTypeOriginal, PostTypeReply, PostTypeQuote:
+		return "delete"
+	case PostTypeLike:
+		return "unlike"
+	case PostTypeRepost:
+		return "unshare"
+	default:
+		return ""
+	}
+}
+
+// ActionFor returns what a run with these options does to a matching post. It's
+// PruneAction, except that Redact edits original posts and replies instead of deleting them.
+func (o PruneOptions) ActionFor(post Post) string {
+	action := PruneAction(post)
+	if o.Redact && action == "delete" {
+		return "redact"
+	}
+	return action
+}
+
+// output returns where the run prints as it goes
+func (o PruneOptions) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
+}
+
+// confirm asks Confirm, when set, whether to act on post. It returns false for posts to
+// leave alone, counting them as skipped, and sets stop once the user quits. After
+// PruneAll, Confirm is cleared so the rest of the run goes ahead without asking.
+func (o *PruneOptions) confirm(post Post, result *PruneResult, platform string) (proceed, stop bool) {
+	action := o.ActionFor(post)
+	if o.Confirm == nil || action == "" {
+		return true, false
+	}
+	switch o.Confirm(post, action) {
+	case PruneSkip:
+		result.SkippedCount++
+		return false, false
+	case PruneAll:
+		o.Confirm = nil
+		return true, false
+	case PruneQuit:
+		result.StoppedEarly = true
+		result.AddWarning("Stopped at the user's request before every matching post was processed")
+		WithPlatform(platform).Info().Msg("Interactive prune run stopped by user")
+		return false, true
+	default:
+		return true, false
+	}
+}
+
+// ExceedsEngagementThreshold returns true if the post has more likes, reposts or replies
+// than the options allow. Like and repost records are never filtered, since their counts
+// belong to someone else's post.
+func (o PruneOptions) ExceedsEngagementThreshold(post Post) bool {
+	if post.Type == PostTypeLike || post.Type == PostTypeRepost {
+		return false
+	}
+	if o.MaxLikes != nil && post.LikeCount > *o.MaxLikes {
+		return true
+	}
+	if o.MaxReposts != nil && post.RepostCount > *o.MaxReposts {
+		return true
+	}
+	if o.MaxReplies != nil && post.ReplyCount > *o.MaxReplies {
+		return true
+	}
+	return false
+}
+
+// DeadlineReached returns true if a Deadline is set and has passed
+func (o PruneOptions) DeadlineReached(now time.Time) bool {
+	return !o.Deadline.IsZero() && !now.Before(o.Deadline)
+}
+
+// runLimitReached returns the flag whose limit means no more actions should be started,
+// or "" if the run can carry on
+func (o PruneOptions) runLimitReached(ctx context.Context, now time.Time) string {
+	if o.DeadlineReached(now) {
+		return "--max-runtime"
+	}
+	if RequestBudgetFromContext(ctx).Exhausted() {
+		return "--max-requests"
+	}
+	if ActionBudgetFromContext(ctx).Exhausted() {
+		return "--max-deletions"
+	}
+	return ""
+}
+
+// HasPreservedHashtag returns true if the post carries any of the PreserveHashtags
+func (o PruneOptions) HasPreservedHashtag(post Post) bool {
+	return hasAnyHashtag(post, o.PreserveHashtags)
+}
+
+// MatchesHashtagFilter returns true if the post may be pruned under WithHashtags,
+// which is always the case when no hashtags are required
+func (o PruneOptions) MatchesHashtagFilter(post Post) bool {
+	return len(o.WithHashtags) == 0 || hasAnyHashtag(post, o.WithHashtags)
+}
+
+// HasPreservedLanguage returns true if the post is in any of the PreserveLanguages
+func (o PruneOptions) HasPreservedLanguage(post Post) bool {
+	return hasAnyLanguage(post, o.PreserveLanguages)
+}
+
+// MatchesLanguageFilter returns true if the post may be pruned under WithLanguages.
+// Posts with no language tagged never match, since there's no telling what they're in.
+func (o PruneOptions) MatchesLanguageFilter(post Post) bool {
+	return len(o.WithLanguages) == 0 || hasAnyLanguage(post, o.WithLanguages)
+}
+
+// MatchesMediaFilter returns true if the post may be pruned under MediaOnly
+func (o PruneOptions) MatchesMediaFilter(post Post) bool {
+	return !o.MediaOnly || post.HasMedia()
+}
+
+// MatchesLinkFilter returns true if the post may be pruned under WithLinks and LinksTo
+func (o PruneOptions) MatchesLinkFilter(post Post) bool {
+	if len(o.LinksTo) > 0 {
+		return hasAnyLinkTo(post, o.LinksTo)
+	}
+	return !o.WithLinks || len(post.Links) > 0
+}
+
+// MatchesReplyFilter returns true if the post may be pruned under RepliesOnly. Replies
+// in the user's own threads don't count, since it's replies to others that it's after.
+func (o PruneOptions) MatchesReplyFilter(post Post) bool {
+	return !o.RepliesOnly || (post.Type == PostTypeReply && !post.SelfReply)
+}
+
+// MatchesSelfRepostFilter returns true if the post may be pruned under OnlySelfReposts
+func (o PruneOptions) MatchesSelfRepostFilter(post Post) bool {
+	return !o.OnlySelfReposts || post.SharesOwnPost()
+}
+
+// MatchesSensitiveFilter returns true if the post may be pruned under OnlySensitive
+func (o PruneOptions) MatchesSensitiveFilter(post Post) bool {
+	return !o.OnlySensitive || post.IsSensitive()
+}
+
+// MatchesVisibilityFilter returns true if the post may be pruned under Visibilities.
+// Posts whose visibility isn't known never match.
+func (o PruneOptions) MatchesVisibilityFilter(post Post) bool {
+	return len(o.Visibilities) == 0 || slices.Contains(o.Visibilities, post.Visibility)
+}
+
+// selectForPrune applies the age, engagement, hashtag, language, media, link, reply,
+// self-repost, sensitivity and visibility criteria to a post, and
+// skips posts a previous run already deleted. Selected posts come with the reason they
+// are preserved, or "" if the run should act on them.
+func (o PruneOptions) selectForPrune(platform string, post Post, now time.Time) (selected bool, preserveReason string) {
+	created := o.ageFrom(post)
+	oldEnough := o.MaxAge != nil && now.Sub(created) > *o.MaxAge
+	earlyEnough := o.BeforeDate != nil && created.Before(*o.BeforeDate)
+	// A list of posts to act on stands in for the age criteria, but doesn't override them
+	anyAge := len(o.ListedPosts) > 0 && o.MaxAge == nil && o.BeforeDate == nil
+	if !oldEnough && !earlyEnough && !anyAge && !o.threadReplies[post.ID] {
+		return false, ""
+	}
+	if o.AfterDate != nil && created.Before(*o.AfterDate) {
+		return false, ""
+	}
+
+	// Posts we've already deleted can linger in feeds until the platform catches up
+	if wasDeleted(platform, post.ID) || o.Checkpoint.Done(post.ID) {
+		return false, ""
+	}
+
+	if o.OnlyPostIDs != nil && !o.OnlyPostIDs[post.ID] {
+		return false, ""
+	}
+	if len(o.ListedPosts) > 0 && !o.IsListed(post) {
+		return false, ""
+	}
+
+	// Redacted posts stay on the timeline, but there's nothing left in them to remove
+	if o.Redact && strings.TrimSpace(post.Content) == RedactedContent {
+		return false, ""
+	}
+
+	// Keep posts that got more engagement than the thresholds allow, and with --with-hashtags,
+	// --language, --media-only, --with-links, --links-to, --replies-only, --only-self-reposts,
+	// --only-sensitive or --visibility, only touch the posts they pick out
+	if o.ExceedsEngagementThreshold(post) || !o.MatchesHashtagFilter(post) || !o.MatchesLanguageFilter(post) ||
+		!o.MatchesMediaFilter(post) || !o.MatchesLinkFilter(post) || !o.MatchesReplyFilter(post) ||
+		!o.MatchesSelfRepostFilter(post) || !o.MatchesSensitiveFilter(post) || !o.MatchesVisibilityFilter(post) {
+		return false, ""
+	}
+
+	switch {
+	case o.IsExcluded(post):
+		return true, PreserveReasonExcluded
+	case post.SelfRepost && (o.SkipSelfReposts || !o.UnshareSelfReposts && !o.OnlySelfReposts):
+		// Only unshared when asked, and even then the original is left to its own criteria
+		return true, PreserveReasonSelfRepost
+	case post.SelfQuote && o.SkipSelfReposts:
+		return true, PreserveReasonSelfQuote
+	case o.PreservePinned && post.IsPinned:
+		return true, PreserveReasonPinned
+	case o.PreserveSelfLike && post.IsLikedByUser && (post.Type == PostTypeOriginal || post.Type == PostTypeQuote):
+		return true, PreserveReasonSelfLiked
+	case o.PreserveWithReplies && post.ReplyCount > 0 && post.Type != PostTypeRepost && post.Type != PostTypeLike:
+		// Deleting a post people answered leaves holes in their threads
+		return true, PreserveReasonHasReplies
+	case o.HasPreservedHashtag(post):
+		return true, PreserveReasonHashtag
+	case o.HasPreservedLanguage(post):
+		return true, PreserveReasonLanguage
+	case o.SkipMedia && post.HasMedia():
+		return true, PreserveReasonMedia
+	case o.SkipReplies && post.Type == PostTypeReply:
+		return true, PreserveReasonReply
+	case o.PreserveCW && post.ContentWarning != "":
+		return true, PreserveReasonContentWarning
+	case o.PreserveDirect && post.Visibility == VisibilityDirect:
+		return true, PreserveReasonDirect
+	}
+	return true, ""
+}
+
+// Reasons a post that matches the prune criteria is kept anyway, as recorded in
+// PruneResult.PreserveReasons
+const (
+	PreserveReasonExcluded       = "excluded"
+	PreserveReasonSelfRepost     = "self-repost"
+	PreserveReasonSelfQuote      = "self-quote"
+	PreserveReasonPinned         = "pinned"
+	PreserveReasonSelfLiked      = "self-liked"
+	PreserveReasonHasReplies     = "has-replies"
+	PreserveReasonHashtag        = "hashtag"
+	PreserveReasonLanguage       = "language"
+	PreserveReasonMedia          = "media"
+	PreserveReasonReply          = "reply"
+	PreserveReasonContentWarning = "content-warning"
+	PreserveReasonDirect         = "direct"
+)
+
+// ageFrom returns the time a post's age is judged from: when it was made, or with
+// LikedPostAge, when the post a like points at was made, if that's known
+func (o PruneOptions) ageFrom(post Post) time.Time {
+	if o.LikedPostAge && post.Type == PostTypeLike && post.OriginalPost != nil && !post.OriginalPost.CreatedAt.IsZero() {
+		return post.OriginalPost.CreatedAt
+	}
+	return post.CreatedAt
+}
+
+// CheckDateRange returns an error if AfterDate isn't before BeforeDate, since no post
+// could fall between them
+func (o PruneOptions) CheckDateRange() error {
+	if o.AfterDate != nil && o.BeforeDate != nil && !o.AfterDate.Before(*o.BeforeDate) {
+		return fmt.Errorf("--after-date %s must be before --before-date %s", o.AfterDate.Format("2006-01-02"), o.BeforeDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// pageMatchesAge reports whether any post in a page is old enough for MaxAge or
+// BeforeDate. Without ContinueUntilEnd, a newest-first walk keeps fetching pages only
+// while they still hold posts to prune.
+func (o PruneOptions) pageMatchesAge(page []Post, now time.Time) bool {
+	for _, post := range page {
+		if o.MaxAge != nil && now.Sub(post.CreatedAt) > *o.MaxAge {
+			return true
+		}
+		if o.BeforeDate != nil && post.CreatedAt.Before(*o.BeforeDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachedAfterDate reports whether a newest-first page of posts reaches back past
+// AfterDate, so the pages after it hold nothing to prune. The last post is the one
+// checked, since pinned posts can sit out of order at the top of a feed.
+func (o PruneOptions) reachedAfterDate(page []Post) bool {
+	return o.AfterDate != nil && len(page) > 0 && page[len(page)-1].CreatedAt.Before(*o.AfterDate)
+}
+
+// withWholeThreads returns the options with DeleteWholeThreads applied to posts: every
+// reply under an original post the run deletes, however deep, is selected whatever its
+// age. The other criteria still apply to those replies, so a pinned or preserved reply
+// is kept. Replies come before their parents in a newest-first listing, and
+// orderRepliesBeforeParents keeps it that way, so a thread is deleted leaf-first.
+func (o PruneOptions) withWholeThreads(platform string, posts []Post, now time.Time) PruneOptions {
+	if !o.DeleteWholeThreads {
+		return o
+	}
+
+	replies := make(map[string][]string)
+	for _, post := range posts {
+		if post.Type == PostTypeReply && post.InReplyToID != "" {
+			replies[post.InReplyToID] = append(replies[post.InReplyToID], post.ID)
+		}
+	}
+
+	o.threadReplies = make(map[string]bool)
+	var collect func(id string)
+	collect = func(id string) {
+		for _, reply := range replies[id] {
+			if !o.threadReplies[reply] {
+				o.threadReplies[reply] = true
+				collect(reply)
+			}
+		}
+	}
+	for _, post := range posts {
+		if post.Type != PostTypeOriginal && post.Type != PostTypeQuote {
+			continue
+		}
+		if selected, preserveReason := o.selectForPrune(platform, post, now); selected && preserveReason == "" {
+			collect(post.ID)
+		}
+	}
+	return o
+}
+
+// mergeActionsByTarget drops posts that would repeat or conflict with another action on
+// the same underlying post. A record listed by more than one source is only acted on once,
+// and likes and reposts of a post the run deletes are left to go with it. Unliking and
+// unsharing the same post are independent, so both still happen.
+func (o PruneOptions) mergeActionsByTarget(platform string, posts []Post, now time.Time) []Post {
+	deleting := make(map[string]bool)
+	for _, post := range posts {
+		if o.ActionFor(post) != "delete" {
+			continue
+		}
+		if selected, preserveReason := o.selectForPrune(platform, post, now); selected && preserveReason == "" {
+			deleting[post.ID] = true
+		}
+	}
+
+	type action struct{ name, target string }
+	seen := make(map[action]bool)
+	merged := make([]Post, 0, len(posts))
+	for _, post := range posts {
+		key := action{o.ActionFor(post), post.targetID()}
+		if seen[key] || (key.name != "delete" && deleting[key.target]) {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, post)
+	}
+	return merged
+}
+
+// hasAnyHashtag returns true if the post carries any of the given normalized hashtags
+func hasAnyHashtag(post Post, hashtags []string) bool {
+	for _, tag := range post.Hashtags {
+		tag = NormalizeHashtag(tag)
+		for _, wanted := range hashtags {
+			if tag == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAnyLanguage returns true if the post is tagged with any of the given normalized
+// languages. A bare language matches its regional variants, so "en" matches "en-GB".
+func hasAnyLanguage(post Post, languages []string) bool {
+	for _, lang := range post.Languages {
+		lang = NormalizeLanguage(lang)
+		for _, wanted := range languages {
+			if lang == wanted || strings.HasPrefix(lang, wanted+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAnyLinkTo returns true if the post links to any of the given normalized domains or
+// their subdomains, so "example.com" matches links to blog.example.com
+func hasAnyLinkTo(post Post, domains []string) bool {
+	for _, link := range post.Links {
+		host := LinkDomain(link)
+		if host == "" {
+			continue
+		}
+		for _, wanted := range domains {
+			if host == wanted || strings.HasSuffix(host, "."+wanted) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // PruneResult represents the result of a pruning operation
 type PruneResult struct {
-	PostsToDelete  []Post   `json:"posts_to_delete"`
-	PostsToUnlike  []Post   `json:"posts_to_unlike"`
-	PostsToUnshare []Post   `json:"posts_to_unshare"`
-	PostsPreserved []Post   `json:"posts_preserved"`
-	DeletedCount   int      `json:"deleted_count"`
-	UnlikedCount   int      `json:"unliked_count"`
-	UnsharedCount  int      `json:"unshared_count"`
-	PreservedCount int      `json:"preserved_count"`
-	ErrorsCount    int      `json:"errors_count"`
-	Errors         []string `json:"errors,omitempty"`
+	PostsToDelete    []Post   `json:"posts_to_delete"`
+	PostsToUnlike    []Post   `json:"posts_to_unlike"`
+	PostsToUnshare   []Post   `json:"posts_to_unshare"`
+	PostsToRedact    []Post   `json:"posts_to_redact,omitempty"`
+	PostsPreserved   []Post   `json:"posts_preserved"`
+	DeletedCount     int      `json:"deleted_count"`
+	UnlikedCount     int      `json:"unliked_count"`
+	UnsharedCount    int      `json:"unshared_count"`
+	RedactedCount    int      `json:"redacted_count,omitempty"`
+	PreservedCount   int      `json:"preserved_count"`
+	SkippedCount     int      `json:"skipped_count,omitempty"` // Matching posts the user chose to leave alone
+	ErrorsCount      int      `json:"errors_count"`
+	RateLimitedCount int      `json:"rate_limited_count,omitempty"` // Of ErrorsCount, actions the platform refused with a rate limit
+	Errors           []string `json:"errors,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`      // Non-fatal advisories that don't count as errors
+	StoppedEarly     bool     `json:"stopped_early,omitempty"` // The run hit its --max-runtime or --max-requests before finishing
+	APIRequests      int      `json:"api_requests,omitempty"`  // API requests made during the run, when counted
+
+	// Why each of PostsPreserved was kept, by post ID, as one of the PreserveReason constants
+	PreserveReasons map[string]string `json:"preserve_reasons,omitempty"`
+
+	// What the account has left after the run, out of the posts it looked at
+	Account *AccountStats `json:"account,omitempty"`
 }
 
-// SocialClient defines the interface for social media platforms
-type SocialClient interface {
+// AddWarning records a non-fatal advisory on the result
+func (r *PruneResult) AddWarning(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// preserve lists post as kept for reason
+func (r *PruneResult) preserve(post Post, reason string) {
+	r.PostsPreserved = append(r.PostsPreserved, post)
+	r.PreservedCount++
+	if r.PreserveReasons == nil {
+		r.PreserveReasons = make(map[string]string)
+	}
+	r.PreserveReasons[post.ID] = reason
+}
+
+// addPlanned lists post under the action a run takes on it, or with DryRun would take
+func (r *PruneResult) addPlanned(action string, post Post) {
+	switch action {
+	case "delete":
+		r.PostsToDelete = append(r.PostsToDelete, post)
+	case "redact":
+		r.PostsToRedact = append(r.PostsToRedact, post)
+	case "unlike":
+		r.PostsToUnlike = append(r.PostsToUnlike, post)
+	case "unshare":
+		r.PostsToUnshare = append(r.PostsToUnshare, post)
+	}
+}
+
+// stopEarly marks the result as cut short by the limit set with flag. Every completed
+// action is already in the tombstone log, so the next run picks up where this one stopped.
+func (r *PruneResult) stopEarly(platform, flag string) {
+	r.StoppedEarly = true
+	r.AddWarning("Stopped after reaching %s before every matching post was processed; re-run to continue where this run left off", flag)
+	WithPlatform(platform).Warn().Str("limit", flag).Msg("Run budget reached, stopping prune run")
+	fmt.Printf("⏱️  %s reached, stopping %s prune run\n", flag, platform)
+}
+
+// stopForDrain marks the run as stopped early because it was drained for shutdown
+func (r *PruneResult) stopForDrain(platform string) {
+	r.StoppedEarly = true
+	r.AddWarning("Stopped for shutdown before every matching post was processed; the next run continues where this one left off")
+	WithPlatform(platform).Warn().Msg("Shutting down, stopping prune run")
+	fmt.Printf("🛑 Shutting down, stopping %s prune run\n", platform)
+}
+
+// ErrNotOwnAccount is returned by PrunePosts when the target isn't the authenticated account
+var ErrNotOwnAccount = errors.New("prune only works on your own authenticated account")
+
+// checkOwnAccount fails a prune of username before it starts when own says it isn't
+// account, the one the session logs in to. With AllowAccountMismatch the prune goes
+// ahead instead, for handles and aliases that can't be compared, and the warning to
+// add to its result is returned.
+func checkOwnAccount(platform, username, account string, own bool, options PruneOptions) (string, error) {
+	if own {
+		return "", nil
+	}
+	if !options.AllowAccountMismatch {
+		return "", fmt.Errorf("%w: %s is not the authenticated account %s (if it's the same account under another name, use --allow-mismatch)", ErrNotOwnAccount, username, account)
+	}
+	WithPlatform(platform).Warn().Str("target", username).Str("account", account).Msg("Pruning a target that isn't the authenticated account")
+	return fmt.Sprintf("%s is not the authenticated account %s, went ahead because of --allow-mismatch", username, account), nil
+}
+
+// addMismatchWarning adds the warning checkOwnAccount gave, if any, to a result, which
+// may be nil when the prune failed
+func (r *PruneResult) addMismatchWarning(warning string) {
+	if r != nil && warning != "" {
+		r.AddWarning("%s", warning)
+	}
+}
+
+// ErrRedactUnsupported is returned by PrunePosts when Redact is set for a platform whose
+// posts can't be edited
+var ErrRedactUnsupported = errors.New("this platform doesn't support editing posts, so they can't be redacted")
+
+// PostReader is the read-only side of a platform client. It works against any public
+// account, and is all that listing and analysis need.
+type PostReader interface {
 	// FetchUserPosts retrieves recent posts for a given username
-	FetchUserPosts(username string, limit int) ([]Post, error)
+	FetchUserPosts(ctx context.Context, username string, limit int) ([]Post, error)
 
 	// FetchUserPostsPaginated retrieves posts with pagination support
-	FetchUserPostsPaginated(username string, limit int, cursor string) ([]Post, string, error)
+	FetchUserPostsPaginated(ctx context.Context, username string, limit int, cursor string) ([]Post, string, error)
 
 	// GetPlatformName returns the name of the social platform
 	GetPlatformName() string
+}
+
+// SocialClient defines the interface for social media platforms
+type SocialClient interface {
+	PostReader
 
 	// PrunePosts deletes posts according to specified criteria
-	PrunePosts(username string, options PruneOptions) (*PruneResult, error)
+	// If ctx is cancelled mid-run, the partial result is returned along with ctx.Err()
+	// Returns ErrNotOwnAccount if username isn't the authenticated account, unless
+	// options.AllowAccountMismatch is set
+	PrunePosts(ctx context.Context, username string, options PruneOptions) (*PruneResult, error)
 
 	// RequiresAuth returns true if the platform requires authentication for deletion
 	RequiresAuth() bool
 }
 
+// PostCounter is implemented by clients that can report an account's total post count
+type PostCounter interface {
+	// GetPostCount returns the number of posts the platform reports for the account
+	GetPostCount(ctx context.Context, username string) (int, error)
+}
+
+// AccountInfo describes the authenticated account, as the platform reports it
+type AccountInfo struct {
+	ID        string // The platform's own identifier: a DID on Bluesky, an account ID on Mastodon
+	Username  string
+	PostCount int
+	CreatedAt time.Time
+
+	// CredentialsUsername is the username the credentials were saved or set for, and
+	// MatchesCredentials whether it names the account they actually log in to
+	CredentialsUsername string
+	MatchesCredentials  bool
+}
+
+// AccountVerifier is implemented by clients that can check the saved credentials with a
+// live API call, rather than only that they exist locally
+type AccountVerifier interface {
+	// VerifyAccount authenticates with the platform and returns the account it logs in as
+	VerifyAccount(ctx context.Context) (*AccountInfo, error)
+}
+
+// InstanceRulesProvider is implemented by clients that can fetch the rules of the
+// instance an account lives on, so they can be reviewed before the first prune
+type InstanceRulesProvider interface {
+	// FetchInstanceRules returns the published rules for the username's instance
+	FetchInstanceRules(ctx context.Context, username string) (*InstanceRules, error)
+}
+
+// DefaultPostCountTolerance is how far the observed post count change may drift from
+// the expected change before it is flagged. Platform counters are eventually consistent
+// and the account may post while a prune is running, so small differences are normal.
+const DefaultPostCountTolerance = 5
+
+// PostCountCheck compares an account's post count before and after a prune run
+type PostCountCheck struct {
+	Before        int `json:"before"`
+	After         int `json:"after"`
+	ExpectedDelta int `json:"expected_delta"`
+}
+
+// ActualDelta returns how many posts disappeared from the account's post count
+func (pc PostCountCheck) ActualDelta() int {
+	return pc.Before - pc.After
+}
+
+// Discrepancy returns the difference between the observed and expected change.
+// A positive value means more posts vanished than cringesweeper removed.
+func (pc PostCountCheck) Discrepancy() int {
+	return pc.ActualDelta() - pc.ExpectedDelta
+}
+
+// IsSuspicious returns true if the discrepancy exceeds the given tolerance in either direction
+func (pc PostCountCheck) IsSuspicious(tolerance int) bool {
+	discrepancy := pc.Discrepancy()
+	if discrepancy < 0 {
+		discrepancy = -discrepancy
+	}
+	return discrepancy > tolerance
+}
+
+// ExpectedPostCountDelta returns how much a platform's post counter should drop after a prune.
+// Bluesky's postsCount only tracks posts, while Mastodon's statuses_count also includes reblogs.
+func ExpectedPostCountDelta(platform string, result *PruneResult) int {
+	if result == nil {
+		return 0
+	}
+
+	switch strings.ToLower(platform) {
+	case "mastodon", "gotosocial":
+		return result.DeletedCount + result.UnsharedCount
+	default:
+		return result.DeletedCount
+	}
+}
+
 // SupportedPlatforms maps platform names to their client constructors
 var SupportedPlatforms = map[string]func() SocialClient{
-	"bluesky":  func() SocialClient { return NewBlueskyClient() },
-	"mastodon": func() SocialClient { return NewMastodonClient() },
+	"bluesky":    func() SocialClient { return NewBlueskyClient() },
+	"mastodon":   func() SocialClient { return NewMastodonClient() },
+	"gotosocial": func() SocialClient { return NewGoToSocialClient() },
 }
 
 // GetClient returns a social client for the specified platform
@@ -116,6 +826,24 @@ func GetClient(platform string) (SocialClient, bool) {
 	return constructor(), true
 }
 
+// ReadOnlyPlatforms maps platforms that can only be read, such as offline archives,
+// to their reader constructors. They work with ls and analyze but never with prune.
+var ReadOnlyPlatforms = map[string]func() PostReader{
+	"twitter": func() PostReader { return NewTwitterArchiveClient() },
+}
+
+// GetReader returns a post reader for any supported or read-only platform
+func GetReader(platform string) (PostReader, bool) {
+	if client, exists := GetClient(platform); exists {
+		return client, true
+	}
+	constructor, exists := ReadOnlyPlatforms[platform]
+	if !exists {
+		return nil, false
+	}
+	return constructor(), true
+}
+
 // GetAllPlatformNames returns a slice of all supported platform names
 func GetAllPlatformNames() []string {
 	platforms := make([]string, 0, len(SupportedPlatforms))
@@ -127,8 +855,140 @@ func GetAllPlatformNames() []string {
 	return platforms
 }
 
+// GetAllReadablePlatformNames returns the supported platforms followed by the read-only ones
+func GetAllReadablePlatformNames() []string {
+	readOnly := make([]string, 0, len(ReadOnlyPlatforms))
+	for platform := range ReadOnlyPlatforms {
+		readOnly = append(readOnly, platform)
+	}
+	sort.Strings(readOnly)
+	return append(GetAllPlatformNames(), readOnly...)
+}
+
+// NormalizeHashtag lowercases a hashtag and strips any leading '#', since both
+// platforms match hashtags case-insensitively
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+}
+
+// ParseHashtags parses a comma-separated list of hashtags such as "#keep,#portfolio",
+// normalizing each one and dropping empties and duplicates
+func ParseHashtags(hashtagsStr string) []string {
+	return parseList(hashtagsStr, NormalizeHashtag)
+}
+
+// parseList splits a comma-separated list, normalizing each item and dropping empties
+// and duplicates. It returns nil for a list with nothing in it.
+func parseList(s string, normalize func(string) string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = normalize(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+// NormalizeLanguage lowercases a language tag and uses '-' as its separator, so "pt_BR"
+// and "pt-br" compare equal
+func NormalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// ParseLanguages parses a comma-separated list of language tags such as "en,de",
+// normalizing each one and dropping empties and duplicates
+func ParseLanguages(languagesStr string) []string {
+	return parseList(languagesStr, NormalizeLanguage)
+}
+
+// NormalizeDomain lowercases a domain and drops a leading "www.", along with the scheme,
+// path and port if it's given as a URL, so "https://www.Example.com/jobs" is "example.com"
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+len("://"):]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if host, _, found := strings.Cut(domain, ":"); found {
+		domain = host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(domain, "www."), ".")
+}
+
+// ParseDomains parses a comma-separated list of domains such as "example.com,old.job",
+// normalizing each one and dropping empties and duplicates
+func ParseDomains(domainsStr string) []string {
+	return parseList(domainsStr, NormalizeDomain)
+}
+
+// LinkDomain returns the normalized domain a link points at, or "" if it isn't a web
+// link
+func LinkDomain(link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+	return NormalizeDomain(parsed.Hostname())
+}
+
+// Post visibilities, named as Mastodon's API names them
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private" // Followers only
+	VisibilityDirect   = "direct"
+)
+
+// visibilityNames maps the names ParseVisibilities accepts to visibilities
+var visibilityNames = map[string]string{
+	"public":         VisibilityPublic,
+	"unlisted":       VisibilityUnlisted,
+	"private":        VisibilityPrivate,
+	"followers":      VisibilityPrivate,
+	"followers-only": VisibilityPrivate,
+	"direct":         VisibilityDirect,
+	"dm":             VisibilityDirect,
+}
+
+// ParseVisibilities parses a comma-separated list of visibilities such as
+// "public,unlisted", accepting "followers-only" for private and "dm" for direct
+func ParseVisibilities(visibilitiesStr string) ([]string, error) {
+	var visibilities []string
+	for _, name := range strings.Split(visibilitiesStr, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		visibility, ok := visibilityNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown visibility %q: use public, unlisted, followers-only or direct", name)
+		}
+		if !slices.Contains(visibilities, visibility) {
+			visibilities = append(visibilities, visibility)
+		}
+	}
+	return visibilities, nil
+}
+
 // ParsePlatforms parses a comma-separated list of platforms and validates them
 func ParsePlatforms(platformsStr string) ([]string, error) {
+	return parsePlatformList(platformsStr, GetAllPlatformNames())
+}
+
+// ParseReadablePlatforms is like ParsePlatforms but also accepts read-only platforms.
+// "all" still means only the live platforms, since read-only ones need an archive path.
+func ParseReadablePlatforms(platformsStr string) ([]string, error) {
+	return parsePlatformList(platformsStr, GetAllReadablePlatformNames())
+}
+
+// parsePlatformList validates a comma-separated platform list against the given names
+func parsePlatformList(platformsStr string, supported []string) ([]string, error) {
 	if platformsStr == "" {
 		return nil, fmt.Errorf("platforms cannot be empty")
 	}
@@ -141,19 +1001,19 @@ func ParsePlatforms(platformsStr string) ([]string, error) {
 	// Split by comma and trim whitespace
 	platformList := strings.Split(platformsStr, ",")
 	validPlatforms := make([]string, 0, len(platformList))
-	
+
 	for _, platform := range platformList {
 		platform = strings.TrimSpace(strings.ToLower(platform))
 		if platform == "" {
 			continue
 		}
-		
+
 		// Validate platform exists
-		if _, exists := SupportedPlatforms[platform]; !exists {
-			return nil, fmt.Errorf("unsupported platform '%s'. Supported platforms: %s", 
-				platform, strings.Join(GetAllPlatformNames(), ", "))
+		if !slices.Contains(supported, platform) {
+			return nil, fmt.Errorf("unsupported platform '%s'. Supported platforms: %s",
+				platform, strings.Join(supported, ", "))
 		}
-		
+
 		// Avoid duplicates
 		found := false
 		for _, existing := range validPlatforms {
@@ -166,27 +1026,92 @@ func ParsePlatforms(platformsStr string) ([]string, error) {
 			validPlatforms = append(validPlatforms, platform)
 		}
 	}
-	
+
 	if len(validPlatforms) == 0 {
 		return nil, fmt.Errorf("no valid platforms specified")
 	}
-	
+
 	return validPlatforms, nil
 }
 
 // HTTPClientConfig holds configuration for HTTP clients
 type HTTPClientConfig struct {
-	Timeout time.Duration
+	Timeout             time.Duration // Overall per-request timeout, including reading the body
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per host
+	MaxConnsPerHost     int           // Concurrent connections per host (0 means unlimited)
+	IdleConnTimeout     time.Duration // How long an idle connection is kept open
+}
+
+// DefaultHTTPClientConfig returns the HTTP settings used when none are configured
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:             30 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		MaxConnsPerHost:     0,
+		IdleConnTimeout:     90 * time.Second,
+	}
 }
 
 // CreateHTTPClient creates a standardized HTTP client with proper timeouts
 func CreateHTTPClient(config HTTPClientConfig) *http.Client {
+	defaults := DefaultHTTPClientConfig()
 	if config.Timeout == 0 {
-		config.Timeout = 30 * time.Second
+		config.Timeout = defaults.Timeout
 	}
+	if config.MaxIdleConns == 0 {
+		config.MaxIdleConns = defaults.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost == 0 {
+		config.MaxIdleConnsPerHost = defaults.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout == 0 {
+		config.IdleConnTimeout = defaults.IdleConnTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = config.MaxConnsPerHost
+	transport.IdleConnTimeout = config.IdleConnTimeout
+
 	return &http.Client{
-		Timeout: config.Timeout,
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}
+}
+
+var (
+	httpClientConfig = DefaultHTTPClientConfig()
+	sharedClient     *http.Client
+	httpClientMu     sync.Mutex
+)
+
+// SetHTTPClientConfig sets the timeout and connection limits used by all platform clients
+func SetHTTPClientConfig(config HTTPClientConfig) {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	httpClientConfig = config
+	sharedClient = nil // Rebuilt with the new settings on next use
+}
+
+// GetHTTPClientConfig returns the current HTTP client configuration
+func GetHTTPClientConfig() HTTPClientConfig {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	return httpClientConfig
+}
+
+// sharedHTTPClient returns the HTTP client used for all platform requests, so every call
+// gets a timeout and connections are pooled under the configured limits
+func sharedHTTPClient() *http.Client {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	if sharedClient == nil {
+		sharedClient = CreateHTTPClient(httpClientConfig)
 	}
+	return sharedClient
 }
 
 // SessionManager provides common session management functionality
@@ -196,18 +1121,20 @@ type SessionManager struct {
 	refreshToken  string
 	sessionExpiry time.Time
 	platform      string
+	clock         Clock
 }
 
 // NewSessionManager creates a new session manager
 func NewSessionManager(platform string) *SessionManager {
 	return &SessionManager{
 		platform: platform,
+		clock:    SystemClock,
 	}
 }
 
 // IsSessionValid checks if the current session is still valid
 func (sm *SessionManager) IsSessionValid() bool {
-	return sm.accessToken != "" && time.Now().Before(sm.sessionExpiry.Add(-5*time.Minute))
+	return sm.accessToken != "" && sm.clock.Now().Before(sm.sessionExpiry.Add(-5*time.Minute))
 }
 
 // HasCredentialsChanged checks if credentials have changed from the cached ones
@@ -215,10 +1142,11 @@ func (sm *SessionManager) HasCredentialsChanged(creds *Credentials) bool {
 	if sm.credentials == nil {
 		return true
 	}
-	
+
 	switch sm.platform {
 	case "bluesky":
-		return sm.credentials.Username != creds.Username || sm.credentials.AppPassword != creds.AppPassword
+		return sm.credentials.Username != creds.Username || sm.credentials.AppPassword != creds.AppPassword ||
+			sm.credentials.AccessToken != creds.AccessToken || sm.credentials.Instance != creds.Instance
 	case "mastodon":
 		return sm.credentials.AccessToken != creds.AccessToken || sm.credentials.Instance != creds.Instance
 	default:
@@ -264,68 +1192,59 @@ type AuthenticatedHTTPClient struct {
 	baseURL     string
 }
 
-// NewAuthenticatedHTTPClient creates a new authenticated HTTP client
+// NewAuthenticatedHTTPClient creates a new authenticated HTTP client sharing the
+// configured connection pool. A zero timeout uses the configured default.
 func NewAuthenticatedHTTPClient(accessToken, baseURL string, timeout time.Duration) *AuthenticatedHTTPClient {
+	shared := sharedHTTPClient()
 	if timeout == 0 {
-		timeout = 30 * time.Second
+		timeout = shared.Timeout
 	}
-	
+
 	return &AuthenticatedHTTPClient{
-		client:      &http.Client{Timeout: timeout},
+		client:      &http.Client{Timeout: timeout, Transport: shared.Transport},
 		accessToken: accessToken,
 		baseURL:     baseURL,
 	}
 }
 
 // CreateRequest creates an HTTP request with authentication headers
-func (ahc *AuthenticatedHTTPClient) CreateRequest(method, path string, body io.Reader) (*http.Request, error) {
+func (ahc *AuthenticatedHTTPClient) CreateRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
 	var url string
 	if strings.HasPrefix(path, "http") {
 		url = path
 	} else {
 		url = ahc.baseURL + path
 	}
-	
-	req, err := http.NewRequest(method, url, body)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if ahc.accessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+ahc.accessToken)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	return req, nil
 }
 
 // DoRequest executes an HTTP request and returns the response
 func (ahc *AuthenticatedHTTPClient) DoRequest(req *http.Request) (*http.Response, error) {
-	LogHTTPRequest(req.Method, req.URL.String())
-	
-	resp, err := ahc.client.Do(req)
-	if err != nil {
-		logger := WithHTTP(req.Method, req.URL.String())
-		logger.Error().Err(err).Msg("HTTP request failed")
-		return nil, err
-	}
-	
-	LogHTTPResponse(req.Method, req.URL.String(), resp.StatusCode, resp.Status)
-	
-	return resp, nil
+	return doRequest(ahc.client, req)
 }
 
 // ParseErrorResponse extracts error information from HTTP response
 func ParseErrorResponse(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
-	err := fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	
+	err := newAPIError("", "API request", resp.StatusCode, body)
+
 	logger := WithHTTP("RESPONSE", resp.Request.URL.String())
 	logger.Error().
 		Int("status_code", resp.StatusCode).
 		Str("response_body", string(body)).
 		Msg("API request failed")
-	
+
 	return err
 }
 
@@ -369,7 +1288,7 @@ type APIListRequest struct {
 }
 
 // ExecuteListRequest executes a paginated list request and returns the response body
-func ExecuteListRequest(client *AuthenticatedHTTPClient, request APIListRequest) ([]byte, error) {
+func ExecuteListRequest(ctx context.Context, client *AuthenticatedHTTPClient, request APIListRequest) ([]byte, error) {
 	logger := WithOperation("list_request")
 	logger.Debug().
 		Str("url", request.URL).
@@ -377,56 +1296,56 @@ func ExecuteListRequest(client *AuthenticatedHTTPClient, request APIListRequest)
 		Str("collection", request.Collection).
 		Str("repo", request.Repo).
 		Msg("Executing list request")
-	
+
 	params := url.Values{}
 	for k, v := range request.Params {
 		params[k] = v
 	}
-	
+
 	if request.Limit > 0 {
 		params.Add("limit", fmt.Sprintf("%d", request.Limit))
 	}
-	
+
 	if request.Collection != "" {
 		params.Add("collection", request.Collection)
 	}
-	
+
 	if request.Repo != "" {
 		params.Add("repo", request.Repo)
 	}
-	
+
 	fullURL := request.URL
 	if len(params) > 0 {
 		fullURL += "?" + params.Encode()
 	}
-	
-	req, err := client.CreateRequest("GET", fullURL, nil)
+
+	req, err := client.CreateRequest(ctx, "GET", fullURL, nil)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create list request")
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	resp, err := client.DoRequest(req)
 	if err != nil {
 		logger.Error().Err(err).Msg("List request failed")
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, ParseErrorResponse(resp)
 	}
-	
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to read list response body")
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-	
+
 	logger.Debug().
 		Int("response_size", len(body)).
 		Msg("List request completed successfully")
-	
+
 	return body, nil
 }
 
@@ -438,43 +1357,43 @@ type DeleteRecordRequest struct {
 }
 
 // ExecuteDeleteRequest executes a delete record request
-func ExecuteDeleteRequest(client *AuthenticatedHTTPClient, deleteURL string, request DeleteRecordRequest) error {
+func ExecuteDeleteRequest(ctx context.Context, client *AuthenticatedHTTPClient, deleteURL string, request DeleteRecordRequest) error {
 	logger := WithOperation("delete_request")
 	logger.Info().
 		Str("repo", request.Repo).
 		Str("collection", request.Collection).
 		Str("rkey", request.RKey).
 		Msg("Executing delete request")
-	
+
 	deleteData := map[string]string{
 		"repo":       request.Repo,
 		"collection": request.Collection,
 		"rkey":       request.RKey,
 	}
-	
+
 	jsonData, err := json.Marshal(deleteData)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to marshal delete data")
 		return fmt.Errorf("failed to marshal delete data: %w", err)
 	}
-	
-	req, err := client.CreateRequest("POST", deleteURL, strings.NewReader(string(jsonData)))
+
+	req, err := client.CreateRequest(ctx, "POST", deleteURL, strings.NewReader(string(jsonData)))
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create delete request")
 		return fmt.Errorf("failed to create delete request: %w", err)
 	}
-	
+
 	resp, err := client.DoRequest(req)
 	if err != nil {
 		logger.Error().Err(err).Msg("Delete request failed")
 		return fmt.Errorf("delete request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		return ParseErrorResponse(resp)
 	}
-	
+
 	logger.Info().Msg("Delete request completed successfully")
 	return nil
 }
@@ -498,11 +1417,11 @@ func ValidateURIOwnership(uri, ownerID string) error {
 	if len(parts) < 3 {
 		return fmt.Errorf("invalid URI format: %s", uri)
 	}
-	
+
 	uriOwner := parts[2]
 	if uriOwner != ownerID {
 		return fmt.Errorf("URI owner %s does not match expected owner %s", uriOwner, ownerID)
 	}
-	
+
 	return nil
 }
@@ -25,7 +25,7 @@ func TestParsePlatforms(t *testing.T) {
 		{
 			name:     "all platforms",
 			input:    "all",
-			expected: []string{"bluesky", "mastodon"},
+			expected: []string{"bluesky", "gotosocial", "mastodon"},
 		},
 		{
 			name:     "platforms with spaces",
@@ -2,6 +2,10 @@ package internal
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -67,10 +71,18 @@ func TestGetClient_PlatformName(t *testing.T) {
 	if client.GetPlatformName() != "Mastodon" {
 		t.Errorf("Mastodon client platform name should be 'Mastodon', got '%s'", client.GetPlatformName())
 	}
+
+	client, exists = GetClient("gotosocial")
+	if !exists {
+		t.Fatal("GoToSocial client should exist")
+	}
+	if client.GetPlatformName() != "GoToSocial" {
+		t.Errorf("GoToSocial client platform name should be 'GoToSocial', got '%s'", client.GetPlatformName())
+	}
 }
 
 func TestGetClient_RequiresAuth(t *testing.T) {
-	platforms := []string{"bluesky", "mastodon"}
+	platforms := []string{"bluesky", "mastodon", "gotosocial"}
 
 	for _, platform := range platforms {
 		client, exists := GetClient(platform)
@@ -364,6 +376,22 @@ func TestPruneOptions_EdgeCases(t *testing.T) {
 	}
 }
 
+func TestPruneResult_AddWarning(t *testing.T) {
+	result := &PruneResult{}
+	result.AddWarning("Failed to fetch liked posts: %v", "timeout")
+	result.AddWarning("plain warning")
+
+	if len(result.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d", len(result.Warnings))
+	}
+	if result.Warnings[0] != "Failed to fetch liked posts: timeout" {
+		t.Errorf("Unexpected first warning: %q", result.Warnings[0])
+	}
+	if result.ErrorsCount != 0 || len(result.Errors) != 0 {
+		t.Errorf("Warnings should not be counted as errors, got %d errors", result.ErrorsCount)
+	}
+}
+
 func TestPruneResult_EmptyResult(t *testing.T) {
 	result := &PruneResult{}
 
@@ -458,3 +486,950 @@ func TestPost_JSONSerialization(t *testing.T) {
 		t.Errorf("IsLikedByUser mismatch after JSON round-trip: expected %v, got %v", post.IsLikedByUser, unmarshaledPost.IsLikedByUser)
 	}
 }
+
+func TestPostCountCheck(t *testing.T) {
+	tests := []struct {
+		name                string
+		check               PostCountCheck
+		expectedActual      int
+		expectedDiscrepancy int
+		suspicious          bool
+	}{
+		{"exact match", PostCountCheck{Before: 100, After: 90, ExpectedDelta: 10}, 10, 0, false},
+		{"within tolerance", PostCountCheck{Before: 100, After: 87, ExpectedDelta: 10}, 13, 3, false},
+		{"more posts vanished than expected", PostCountCheck{Before: 100, After: 50, ExpectedDelta: 10}, 50, 40, true},
+		{"counter did not drop", PostCountCheck{Before: 100, After: 100, ExpectedDelta: 20}, 0, -20, true},
+		{"nothing pruned", PostCountCheck{Before: 100, After: 100, ExpectedDelta: 0}, 0, 0, false},
+		{"user posted during prune", PostCountCheck{Before: 100, After: 92, ExpectedDelta: 10}, 8, -2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.ActualDelta(); got != tt.expectedActual {
+				t.Errorf("Expected actual delta %d, got %d", tt.expectedActual, got)
+			}
+			if got := tt.check.Discrepancy(); got != tt.expectedDiscrepancy {
+				t.Errorf("Expected discrepancy %d, got %d", tt.expectedDiscrepancy, got)
+			}
+			if got := tt.check.IsSuspicious(DefaultPostCountTolerance); got != tt.suspicious {
+				t.Errorf("Expected suspicious=%v, got %v", tt.suspicious, got)
+			}
+		})
+	}
+}
+
+func TestExpectedPostCountDelta(t *testing.T) {
+	result := &PruneResult{
+		DeletedCount:  4,
+		UnlikedCount:  7,
+		UnsharedCount: 2,
+	}
+
+	tests := []struct {
+		platform string
+		expected int
+	}{
+		{"bluesky", 4},
+		{"mastodon", 6},
+		{"Mastodon", 6},
+		{"unknown", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			if got := ExpectedPostCountDelta(tt.platform, result); got != tt.expected {
+				t.Errorf("Expected delta %d for %s, got %d", tt.expected, tt.platform, got)
+			}
+		})
+	}
+
+	if got := ExpectedPostCountDelta("bluesky", nil); got != 0 {
+		t.Errorf("Expected delta 0 for nil result, got %d", got)
+	}
+}
+
+func TestClientsImplementPostCounter(t *testing.T) {
+	for _, platform := range GetAllPlatformNames() {
+		client, _ := GetClient(platform)
+		if _, ok := client.(PostCounter); !ok {
+			t.Errorf("%s client should implement PostCounter", platform)
+		}
+	}
+}
+
+func TestCheckOwnAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		own     bool
+		allow   bool
+		wantErr bool
+	}{
+		{"own account", true, false, false},
+		{"another account", false, false, true},
+		{"another account allowed", false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := checkOwnAccount("bluesky", "other.bsky.social", "me.bsky.social", tt.own, PruneOptions{AllowAccountMismatch: tt.allow})
+			if tt.wantErr != errors.Is(err, ErrNotOwnAccount) {
+				t.Errorf("checkOwnAccount() = %v, want ErrNotOwnAccount: %v", err, tt.wantErr)
+			}
+			if wantWarning := !tt.own && tt.allow; wantWarning != strings.Contains(warning, "--allow-mismatch") {
+				t.Errorf("checkOwnAccount() warning = %q, want one: %v", warning, wantWarning)
+			}
+
+			result := &PruneResult{}
+			result.addMismatchWarning(warning)
+			if (len(result.Warnings) == 1) != (warning != "") {
+				t.Errorf("Expected only a mismatch warning in the result, got %v", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestCreateHTTPClient(t *testing.T) {
+	t.Run("zero config uses defaults", func(t *testing.T) {
+		client := CreateHTTPClient(HTTPClientConfig{})
+		defaults := DefaultHTTPClientConfig()
+		if client.Timeout != defaults.Timeout {
+			t.Errorf("Expected timeout %v, got %v", defaults.Timeout, client.Timeout)
+		}
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("Expected *http.Transport, got %T", client.Transport)
+		}
+		if transport.MaxIdleConns != defaults.MaxIdleConns {
+			t.Errorf("Expected MaxIdleConns %d, got %d", defaults.MaxIdleConns, transport.MaxIdleConns)
+		}
+	})
+
+	t.Run("custom config is applied to the transport", func(t *testing.T) {
+		client := CreateHTTPClient(HTTPClientConfig{
+			Timeout:             5 * time.Second,
+			MaxIdleConns:        7,
+			MaxIdleConnsPerHost: 3,
+			MaxConnsPerHost:     4,
+			IdleConnTimeout:     time.Minute,
+		})
+		transport := client.Transport.(*http.Transport)
+		if client.Timeout != 5*time.Second {
+			t.Errorf("Expected timeout 5s, got %v", client.Timeout)
+		}
+		if transport.MaxIdleConns != 7 || transport.MaxIdleConnsPerHost != 3 || transport.MaxConnsPerHost != 4 {
+			t.Errorf("Unexpected connection limits: idle=%d idlePerHost=%d perHost=%d",
+				transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+		}
+		if transport.IdleConnTimeout != time.Minute {
+			t.Errorf("Expected idle timeout 1m, got %v", transport.IdleConnTimeout)
+		}
+	})
+}
+
+func TestSetHTTPClientConfig(t *testing.T) {
+	previous := GetHTTPClientConfig()
+	defer SetHTTPClientConfig(previous)
+
+	config := DefaultHTTPClientConfig()
+	config.Timeout = 3 * time.Second
+	SetHTTPClientConfig(config)
+
+	if sharedHTTPClient().Timeout != 3*time.Second {
+		t.Errorf("Shared client should pick up the new timeout, got %v", sharedHTTPClient().Timeout)
+	}
+
+	authClient := NewAuthenticatedHTTPClient("token", "https://example.com", 0)
+	if authClient.client.Timeout != 3*time.Second {
+		t.Errorf("Authenticated client should default to the configured timeout, got %v", authClient.client.Timeout)
+	}
+	if authClient.client.Transport != sharedHTTPClient().Transport {
+		t.Error("Authenticated client should share the configured transport")
+	}
+}
+
+func TestPruneOptions_ExceedsEngagementThreshold(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	post := Post{Type: PostTypeOriginal, LikeCount: 3, RepostCount: 1, ReplyCount: 0}
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		post     Post
+		expected bool
+	}{
+		{"no thresholds", PruneOptions{}, post, false},
+		{"likes at threshold", PruneOptions{MaxLikes: intPtr(3)}, post, false},
+		{"likes above threshold", PruneOptions{MaxLikes: intPtr(2)}, post, true},
+		{"reposts above threshold", PruneOptions{MaxReposts: intPtr(0)}, post, true},
+		{"replies within threshold", PruneOptions{MaxReplies: intPtr(0)}, post, false},
+		{"any threshold exceeded", PruneOptions{MaxLikes: intPtr(10), MaxReposts: intPtr(0)}, post, true},
+		{"reply posts are filtered", PruneOptions{MaxLikes: intPtr(0)}, Post{Type: PostTypeReply, LikeCount: 1}, true},
+		{"quote posts are filtered", PruneOptions{MaxLikes: intPtr(0)}, Post{Type: PostTypeQuote, LikeCount: 1}, true},
+		{"like records are never filtered", PruneOptions{MaxLikes: intPtr(0)}, Post{Type: PostTypeLike, LikeCount: 50}, false},
+		{"repost records are never filtered", PruneOptions{MaxLikes: intPtr(0)}, Post{Type: PostTypeRepost, LikeCount: 50}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.ExceedsEngagementThreshold(tt.post); got != tt.expected {
+				t.Errorf("ExceedsEngagementThreshold() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseHashtags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"#keep", []string{"keep"}},
+		{"#keep,#portfolio", []string{"keep", "portfolio"}},
+		{" #Keep , portfolio ,, ##keep", []string{"keep", "portfolio"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseHashtags(tt.input)
+		if len(got) != len(tt.expected) {
+			t.Errorf("ParseHashtags(%q) = %v, expected %v", tt.input, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("ParseHashtags(%q) = %v, expected %v", tt.input, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestPruneOptions_HasPreservedHashtag(t *testing.T) {
+	options := PruneOptions{PreserveHashtags: ParseHashtags("#keep,#portfolio")}
+
+	tests := []struct {
+		name     string
+		hashtags []string
+		expected bool
+	}{
+		{"no hashtags", nil, false},
+		{"unrelated hashtag", []string{"golang"}, false},
+		{"matching hashtag", []string{"golang", "keep"}, true},
+		{"matches case-insensitively", []string{"Portfolio"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := options.HasPreservedHashtag(Post{Hashtags: tt.hashtags}); got != tt.expected {
+				t.Errorf("HasPreservedHashtag(%v) = %v, expected %v", tt.hashtags, got, tt.expected)
+			}
+		})
+	}
+
+	if (PruneOptions{}).HasPreservedHashtag(Post{Hashtags: []string{"keep"}}) {
+		t.Error("Expected no preservation when no hashtags are configured")
+	}
+}
+
+func TestMastodonStatus_Tags(t *testing.T) {
+	statusJSON := `{"id": "1", "content": "<p>#Keep this</p>", "tags": [{"name": "keep", "url": "https://mastodon.social/tags/keep"}]}`
+
+	var status mastodonStatus
+	if err := json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+
+	tags := mastodonHashtags(status.Tags)
+	if len(tags) != 1 || tags[0] != "keep" {
+		t.Errorf("Expected [keep], got %v", tags)
+	}
+}
+
+func TestPruneOptions_MatchesHashtagFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		withHashtags string
+		hashtags     []string
+		expected     bool
+	}{
+		{"no filter matches untagged post", "", nil, true},
+		{"no filter matches tagged post", "", []string{"keep"}, true},
+		{"filter skips untagged post", "#conf2019", nil, false},
+		{"filter skips other hashtags", "#conf2019", []string{"conf2020"}, false},
+		{"filter matches tagged post", "#conf2019,#meetup", []string{"travel", "Conf2019"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := PruneOptions{WithHashtags: ParseHashtags(tt.withHashtags)}
+			if got := options.MatchesHashtagFilter(Post{Hashtags: tt.hashtags}); got != tt.expected {
+				t.Errorf("MatchesHashtagFilter(%v) = %v, expected %v", tt.hashtags, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLanguages(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"en", []string{"en"}},
+		{" EN , de,, en", []string{"en", "de"}},
+		{"pt_BR", []string{"pt-br"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseLanguages(tt.input)
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("ParseLanguages(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestPruneOptions_LanguageFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages string
+		postLangs []string
+		expected  bool
+	}{
+		{"no filter matches untagged post", "", nil, true},
+		{"filter skips untagged post", "en", nil, false},
+		{"filter skips other languages", "en,de", []string{"fr"}, false},
+		{"filter matches any of the post's languages", "de", []string{"en", "de"}, true},
+		{"language matches regional variant", "en", []string{"en-GB"}, true},
+		{"regional variant doesn't match other regions", "pt-br", []string{"pt-PT"}, false},
+		{"prefix alone isn't a match", "e", []string{"en"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := Post{Languages: tt.postLangs}
+			options := PruneOptions{WithLanguages: ParseLanguages(tt.languages)}
+			if got := options.MatchesLanguageFilter(post); got != tt.expected {
+				t.Errorf("MatchesLanguageFilter(%v) = %v, expected %v", tt.postLangs, got, tt.expected)
+			}
+
+			// Preservation uses the same matching, but nothing is preserved without languages
+			preserve := PruneOptions{PreserveLanguages: ParseLanguages(tt.languages)}
+			expected := tt.expected && tt.languages != ""
+			if got := preserve.HasPreservedLanguage(post); got != expected {
+				t.Errorf("HasPreservedLanguage(%v) = %v, expected %v", tt.postLangs, got, expected)
+			}
+		})
+	}
+}
+
+func TestMastodonStatus_Language(t *testing.T) {
+	var status mastodonStatus
+	if err := json.Unmarshal([]byte(`{"id": "1", "language": "de"}`), &status); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+	if langs := mastodonLanguages(status.Language); !slices.Equal(langs, []string{"de"}) {
+		t.Errorf("Expected [de], got %v", langs)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id": "2", "language": null}`), &status); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+	if langs := mastodonLanguages(status.Language); langs != nil {
+		t.Errorf("Expected no languages for a null language, got %v", langs)
+	}
+}
+
+func TestPruneOptions_MatchesMediaFilter(t *testing.T) {
+	textPost := Post{Content: "just words"}
+	mediaPost := Post{Content: "look", Attachments: []Attachment{{Type: "image"}}}
+
+	if !(PruneOptions{}).MatchesMediaFilter(textPost) || !(PruneOptions{}).MatchesMediaFilter(mediaPost) {
+		t.Error("Expected all posts to match when --media-only is not set")
+	}
+
+	mediaOnly := PruneOptions{MediaOnly: true}
+	if mediaOnly.MatchesMediaFilter(textPost) {
+		t.Error("Expected text post to be skipped with --media-only")
+	}
+	if !mediaOnly.MatchesMediaFilter(mediaPost) {
+		t.Error("Expected media post to match with --media-only")
+	}
+}
+
+func TestParseDomains(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"example.com", []string{"example.com"}},
+		{" Example.COM , old.job,, example.com", []string{"example.com", "old.job"}},
+		{"https://www.example.com/jobs?id=1", []string{"example.com"}},
+		{"example.com:8080", []string{"example.com"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseDomains(tt.input)
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("ParseDomains(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestPruneOptions_MatchesLinkFilter(t *testing.T) {
+	textPost := Post{Content: "just words"}
+	jobPost := Post{Links: []string{"https://careers.oldjob.com/openings"}}
+	otherPost := Post{Links: []string{"https://www.example.com/", "mailto:me@oldjob.com"}}
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		post     Post
+		expected bool
+	}{
+		{"no filter", PruneOptions{}, textPost, true},
+		{"with-links skips text", PruneOptions{WithLinks: true}, textPost, false},
+		{"with-links picks link", PruneOptions{WithLinks: true}, otherPost, true},
+		{"links-to picks subdomain", PruneOptions{LinksTo: []string{"oldjob.com"}}, jobPost, true},
+		{"links-to skips other domains", PruneOptions{LinksTo: []string{"oldjob.com"}}, otherPost, false},
+		{"links-to ignores www", PruneOptions{LinksTo: []string{"example.com"}}, otherPost, true},
+		{"links-to doesn't match a suffix", PruneOptions{LinksTo: []string{"job.com"}}, jobPost, false},
+		{"links-to skips text", PruneOptions{LinksTo: []string{"oldjob.com"}}, textPost, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.MatchesLinkFilter(tt.post); got != tt.expected {
+				t.Errorf("MatchesLinkFilter() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMastodonLinks(t *testing.T) {
+	content := `<p>New job! <a href="https://careers.example.com/?a=1&amp;b=2" target="_blank" rel="nofollow noopener noreferrer">careers.example.com</a> ` +
+		`thanks <span class="h-card"><a href="https://mastodon.social/@friend" class="u-url mention">@<span>friend</span></a></span> ` +
+		`<a href="https://mastodon.social/tags/jobs" class="mention hashtag" rel="tag">#<span>jobs</span></a> ` +
+		`<a href="https://careers.example.com/?a=1&amp;b=2">again</a></p>`
+
+	links := mastodonLinks(content)
+	if !slices.Equal(links, []string{"https://careers.example.com/?a=1&b=2"}) {
+		t.Errorf("Expected just the web link, got %v", links)
+	}
+}
+
+func TestPruneOptions_ReplyFilters(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+
+	original := Post{ID: "1", Type: PostTypeOriginal}
+	reply := Post{ID: "2", Type: PostTypeReply, InReplyToID: "theirs"}
+	threadReply := Post{ID: "3", Type: PostTypeReply, InReplyToID: "1", SelfReply: true}
+	repost := Post{ID: "4", Type: PostTypeRepost}
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		post     Post
+		selected bool
+		reason   string
+	}{
+		{"reply without filters", PruneOptions{}, reply, true, ""},
+		{"replies-only picks reply to others", PruneOptions{RepliesOnly: true}, reply, true, ""},
+		{"replies-only skips own thread", PruneOptions{RepliesOnly: true}, threadReply, false, ""},
+		{"replies-only skips original", PruneOptions{RepliesOnly: true}, original, false, ""},
+		{"replies-only skips repost", PruneOptions{RepliesOnly: true}, repost, false, ""},
+		{"skip-replies keeps reply", PruneOptions{SkipReplies: true}, reply, true, "reply"},
+		{"skip-replies keeps own thread", PruneOptions{SkipReplies: true}, threadReply, true, "reply"},
+		{"skip-replies leaves original", PruneOptions{SkipReplies: true}, original, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.MaxAge = &maxAge
+			tt.post.CreatedAt = old
+			selected, reason := tt.options.selectForPrune("mastodon", tt.post, now)
+			if selected != tt.selected || reason != tt.reason {
+				t.Errorf("selectForPrune() = (%v, %q), expected (%v, %q)", selected, reason, tt.selected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPruneOptions_PreserveWithReplies(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+	options := PruneOptions{MaxAge: &maxAge, PreserveWithReplies: true, UnlikePosts: true}
+
+	tests := []struct {
+		name     string
+		post     Post
+		selected bool
+		reason   string
+	}{
+		{"post with replies", Post{Type: PostTypeOriginal, ReplyCount: 2}, true, PreserveReasonHasReplies},
+		{"reply with replies", Post{Type: PostTypeReply, ReplyCount: 1}, true, PreserveReasonHasReplies},
+		{"post without replies", Post{Type: PostTypeOriginal}, true, ""},
+		{"repost of a discussed post", Post{Type: PostTypeRepost, ReplyCount: 5}, true, ""},
+		{"like of a discussed post", Post{Type: PostTypeLike, ReplyCount: 5}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.post.CreatedAt = old
+			selected, reason := options.selectForPrune("mastodon", tt.post, now)
+			if selected != tt.selected || reason != tt.reason {
+				t.Errorf("selectForPrune() = (%v, %q), expected (%v, %q)", selected, reason, tt.selected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPruneOptions_SensitiveFilters(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	maxAge := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		post     Post
+		selected bool
+		reason   string
+	}{
+		{"plain post without filters", PruneOptions{}, Post{ID: "1"}, true, ""},
+		{"only-sensitive skips plain post", PruneOptions{OnlySensitive: true}, Post{ID: "2"}, false, ""},
+		{"only-sensitive picks sensitive post", PruneOptions{OnlySensitive: true}, Post{ID: "3", Sensitive: true}, true, ""},
+		{"only-sensitive picks content warning", PruneOptions{OnlySensitive: true}, Post{ID: "4", ContentWarning: "spoilers"}, true, ""},
+		{"preserve-cw keeps content warning", PruneOptions{PreserveCW: true}, Post{ID: "5", ContentWarning: "spoilers"}, true, "content-warning"},
+		{"preserve-cw doesn't keep sensitive media", PruneOptions{PreserveCW: true}, Post{ID: "6", Sensitive: true}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.MaxAge = &maxAge
+			tt.post.CreatedAt = old
+			selected, reason := tt.options.selectForPrune("mastodon", tt.post, now)
+			if selected != tt.selected || reason != tt.reason {
+				t.Errorf("selectForPrune() = (%v, %q), expected (%v, %q)", selected, reason, tt.selected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseVisibilities(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{"", nil, false},
+		{"public", []string{VisibilityPublic}, false},
+		{" Public, followers-only ,private", []string{VisibilityPublic, VisibilityPrivate}, false},
+		{"dm,unlisted", []string{VisibilityDirect, VisibilityUnlisted}, false},
+		{"friends", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVisibilities(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVisibilities(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("ParseVisibilities(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestPruneOptions_VisibilityFilters(t *testing.T) {
+	now := time.Now()
+	maxAge := 24 * time.Hour
+	publicOnly := []string{VisibilityPublic}
+
+	tests := []struct {
+		name     string
+		options  PruneOptions
+		post     Post
+		selected bool
+		reason   string
+	}{
+		{"no filter picks any visibility", PruneOptions{}, Post{ID: "1", Visibility: VisibilityPrivate}, true, ""},
+		{"filter picks matching visibility", PruneOptions{Visibilities: publicOnly}, Post{ID: "2", Visibility: VisibilityPublic}, true, ""},
+		{"filter skips other visibilities", PruneOptions{Visibilities: publicOnly}, Post{ID: "3", Visibility: VisibilityUnlisted}, false, ""},
+		{"filter skips unknown visibility", PruneOptions{Visibilities: publicOnly}, Post{ID: "4"}, false, ""},
+		{"preserve-direct keeps direct messages", PruneOptions{PreserveDirect: true}, Post{ID: "5", Visibility: VisibilityDirect}, true, "direct"},
+		{"preserve-direct leaves other posts", PruneOptions{PreserveDirect: true}, Post{ID: "6", Visibility: VisibilityPublic}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.MaxAge = &maxAge
+			tt.post.CreatedAt = now.Add(-48 * time.Hour)
+			selected, reason := tt.options.selectForPrune("mastodon", tt.post, now)
+			if selected != tt.selected || reason != tt.reason {
+				t.Errorf("selectForPrune() = (%v, %q), expected (%v, %q)", selected, reason, tt.selected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPruneOptions_LikedPostAge(t *testing.T) {
+	now := time.Now()
+	maxAge := 30 * 24 * time.Hour
+	recent := now.Add(-time.Hour)
+	old := now.Add(-365 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		likedPostAge bool
+		post         Post
+		selected     bool
+	}{
+		{"recent like of an old post goes by the like", false, Post{ID: "1", Type: PostTypeLike, CreatedAt: recent, OriginalPost: &Post{ID: "a", CreatedAt: old}}, false},
+		{"recent like of an old post goes by the post", true, Post{ID: "2", Type: PostTypeLike, CreatedAt: recent, OriginalPost: &Post{ID: "a", CreatedAt: old}}, true},
+		{"old like of a recent post goes by the post", true, Post{ID: "3", Type: PostTypeLike, CreatedAt: old, OriginalPost: &Post{ID: "b", CreatedAt: recent}}, false},
+		{"unknown post date falls back to the like", true, Post{ID: "4", Type: PostTypeLike, CreatedAt: old, OriginalPost: &Post{ID: "c"}}, true},
+		{"originals ignore the option", true, Post{ID: "5", Type: PostTypeOriginal, CreatedAt: recent}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := PruneOptions{MaxAge: &maxAge, UnlikePosts: true, LikedPostAge: tt.likedPostAge}
+			if selected, _ := options.selectForPrune("bluesky", tt.post, now); selected != tt.selected {
+				t.Errorf("selectForPrune() selected = %v, expected %v", selected, tt.selected)
+			}
+		})
+	}
+}
+
+func TestPruneOptions_Redact(t *testing.T) {
+	now := time.Now()
+	maxAge := 24 * time.Hour
+	old := now.Add(-48 * time.Hour)
+	options := PruneOptions{MaxAge: &maxAge, Redact: true}
+
+	post := Post{ID: "1", Type: PostTypeOriginal, Content: "hot take", CreatedAt: old}
+	like := Post{ID: "like-1", Type: PostTypeLike, OriginalPost: &Post{ID: "1"}, CreatedAt: old}
+	if got := options.ActionFor(post); got != "redact" {
+		t.Errorf("ActionFor(original) = %q, expected redact", got)
+	}
+	if got := options.ActionFor(like); got != "unlike" {
+		t.Errorf("ActionFor(like) = %q, expected unlike", got)
+	}
+
+	// A redacted post stays up, so a like of it is still worth removing
+	if merged := options.mergeActionsByTarget("mastodon", []Post{post, like}, now); len(merged) != 2 {
+		t.Errorf("Expected the like of a redacted post to be kept, got %v", merged)
+	}
+
+	redacted := Post{ID: "2", Type: PostTypeOriginal, Content: RedactedContent + "\n", CreatedAt: old}
+	if selected, _ := options.selectForPrune("mastodon", redacted, now); selected {
+		t.Error("Expected an already redacted post not to be selected again")
+	}
+}
+
+func TestMastodonStatus_ContentWarning(t *testing.T) {
+	statusJSON := `{"id": "1", "content": "<p>hidden</p>", "spoiler_text": "politics", "sensitive": true}`
+
+	var status mastodonStatus
+	if err := json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+	if status.SpoilerText != "politics" || !status.Sensitive {
+		t.Errorf("Expected a sensitive status with content warning \"politics\", got %q, %v", status.SpoilerText, status.Sensitive)
+	}
+}
+
+func TestPruneOptions_DeadlineReached(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	if (PruneOptions{}).DeadlineReached(now) {
+		t.Error("Expected no deadline without --max-runtime")
+	}
+
+	options := PruneOptions{Deadline: now.Add(time.Minute)}
+	if options.DeadlineReached(now) {
+		t.Error("Expected deadline not reached a minute before it")
+	}
+	if !options.DeadlineReached(now.Add(time.Minute)) {
+		t.Error("Expected deadline reached at the deadline")
+	}
+	if !options.DeadlineReached(now.Add(time.Hour)) {
+		t.Error("Expected deadline reached after the deadline")
+	}
+}
+
+func TestPruneResult_StopEarly(t *testing.T) {
+	result := &PruneResult{}
+	result.stopEarly("bluesky", "--max-runtime")
+
+	if !result.StoppedEarly {
+		t.Error("Expected result to be marked as stopped early")
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "--max-runtime") {
+		t.Errorf("Expected a --max-runtime warning, got %v", result.Warnings)
+	}
+	if result.ErrorsCount != 0 {
+		t.Error("Running out of time shouldn't count as an error")
+	}
+}
+
+func TestPruneOptions_Confirm(t *testing.T) {
+	original := Post{ID: "1", Type: PostTypeOriginal}
+	like := Post{ID: "2", Type: PostTypeLike}
+	untyped := Post{ID: "3"}
+
+	t.Run("no prompt", func(t *testing.T) {
+		var options PruneOptions
+		if proceed, stop := options.confirm(original, &PruneResult{}, "bluesky"); !proceed || stop {
+			t.Errorf("Expected to proceed without a prompt, got %v, %v", proceed, stop)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		decision    PruneDecision
+		post        Post
+		wantAction  string
+		wantProceed bool
+		wantStop    bool
+		wantSkipped int
+		stillAsks   bool
+	}{
+		{"proceed", PruneProceed, original, "delete", true, false, 0, true},
+		{"skip", PruneSkip, original, "delete", false, false, 1, true},
+		{"all", PruneAll, like, "unlike", true, false, 0, false},
+		{"quit", PruneQuit, original, "delete", false, true, 0, true},
+		{"never asked about posts that aren't acted on", PruneSkip, untyped, "", true, false, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAction string
+			options := PruneOptions{Confirm: func(post Post, action string) PruneDecision {
+				gotAction = action
+				return tt.decision
+			}}
+			result := &PruneResult{}
+
+			proceed, stop := options.confirm(tt.post, result, "bluesky")
+			if proceed != tt.wantProceed || stop != tt.wantStop {
+				t.Errorf("confirm() = %v, %v, expected %v, %v", proceed, stop, tt.wantProceed, tt.wantStop)
+			}
+			if gotAction != tt.wantAction {
+				t.Errorf("Expected to be asked about %q, got %q", tt.wantAction, gotAction)
+			}
+			if result.SkippedCount != tt.wantSkipped {
+				t.Errorf("Expected %d skipped, got %d", tt.wantSkipped, result.SkippedCount)
+			}
+			if (options.Confirm != nil) != tt.stillAsks {
+				t.Errorf("Expected Confirm to be kept: %v", tt.stillAsks)
+			}
+			if result.StoppedEarly != tt.wantStop {
+				t.Errorf("Expected StoppedEarly %v", tt.wantStop)
+			}
+		})
+	}
+}
+
+func TestPruneOptions_SelfReposts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	old := now.Add(-60 * 24 * time.Hour)
+
+	kept := Post{ID: "1", Type: PostTypeOriginal, CreatedAt: old, IsPinned: true}
+	deleted := Post{ID: "2", Type: PostTypeOriginal, CreatedAt: old}
+	posts := []Post{
+		kept,
+		deleted,
+		{ID: "r1", Type: PostTypeRepost, CreatedAt: old, SelfRepost: true, OriginalPost: &Post{ID: "1"}},
+		{ID: "r2", Type: PostTypeRepost, CreatedAt: old, SelfRepost: true, OriginalPost: &Post{ID: "2"}},
+		{ID: "r3", Type: PostTypeRepost, CreatedAt: old, OriginalPost: &Post{ID: "elsewhere"}},
+		{ID: "q1", Type: PostTypeQuote, CreatedAt: old, SelfQuote: true, OriginalPost: &Post{ID: "1"}},
+		{ID: "q2", Type: PostTypeQuote, CreatedAt: old, OriginalPost: &Post{ID: "elsewhere"}},
+	}
+
+	tests := []struct {
+		name    string
+		options PruneOptions
+		want    map[string]string // Selected post IDs and their preserve reasons
+	}{
+		{
+			name: "self-reposts kept by default",
+			want: map[string]string{"1": "pinned", "2": "", "r1": "self-repost", "r3": "", "q1": "", "q2": ""},
+		},
+		{
+			name:    "self-reposts unshared when asked",
+			options: PruneOptions{UnshareSelfReposts: true},
+			want:    map[string]string{"1": "pinned", "2": "", "r1": "", "r3": "", "q1": "", "q2": ""},
+		},
+		{
+			name:    "self-reposts and self-quotes skipped",
+			options: PruneOptions{SkipSelfReposts: true},
+			want:    map[string]string{"1": "pinned", "2": "", "r1": "self-repost", "r3": "", "q1": "self-quote", "q2": ""},
+		},
+		{
+			// With the originals left alone, r2 no longer goes with its original
+			name:    "only self-reposts and self-quotes",
+			options: PruneOptions{OnlySelfReposts: true},
+			want:    map[string]string{"r1": "", "r2": "", "q1": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+			options.MaxAge = &maxAge
+			options.PreservePinned = true
+
+			got := make(map[string]string)
+			for _, post := range options.mergeActionsByTarget("bluesky", posts, now) {
+				if selected, reason := options.selectForPrune("bluesky", post, now); selected {
+					got[post.ID] = reason
+				}
+			}
+			// Otherwise r2 is never acted on: it goes when its original is deleted
+			if len(got) != len(tt.want) {
+				t.Fatalf("Selected %v, want %v", got, tt.want)
+			}
+			for id, reason := range tt.want {
+				if gotReason, ok := got[id]; !ok || gotReason != reason {
+					t.Errorf("Post %s: got reason %q (selected %v), want %q", id, gotReason, ok, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneOptions_MergeActionsByTarget(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	old := now.Add(-60 * 24 * time.Hour)
+	options := PruneOptions{MaxAge: &maxAge, PreservePinned: true, UnshareSelfReposts: true}
+
+	posts := []Post{
+		{ID: "own", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "pinned", Type: PostTypeOriginal, CreatedAt: old, IsPinned: true},
+		// Mastodon lists a favourite by the status ID itself
+		{ID: "own", Type: PostTypeLike, CreatedAt: old},
+		{ID: "like-1", Type: PostTypeLike, CreatedAt: old, OriginalPost: &Post{ID: "own"}},
+		{ID: "like-2", Type: PostTypeLike, CreatedAt: old, OriginalPost: &Post{ID: "pinned"}},
+		{ID: "repost-1", Type: PostTypeRepost, CreatedAt: old, SelfRepost: true, OriginalPost: &Post{ID: "own"}},
+		// Someone else's post, liked and reposted, and the repost listed twice
+		{ID: "like-3", Type: PostTypeLike, CreatedAt: old, OriginalPost: &Post{ID: "theirs"}},
+		{ID: "repost-2", Type: PostTypeRepost, CreatedAt: old, OriginalPost: &Post{ID: "theirs"}},
+		{ID: "repost-2", Type: PostTypeRepost, CreatedAt: old, OriginalPost: &Post{ID: "theirs"}},
+	}
+
+	var got []string
+	for _, post := range options.mergeActionsByTarget("bluesky", posts, now) {
+		got = append(got, post.ID)
+	}
+	want := []string{"own", "pinned", "like-2", "like-3", "repost-2"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("mergeActionsByTarget() kept %v, want %v", got, want)
+	}
+}
+
+func TestPruneOptions_WholeThreads(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	old := now.Add(-60 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+
+	posts := []Post{
+		{ID: "root", Type: PostTypeOriginal, CreatedAt: old},
+		{ID: "reply-1", Type: PostTypeReply, CreatedAt: recent, InReplyToID: "root"},
+		{ID: "reply-2", Type: PostTypeReply, CreatedAt: recent, InReplyToID: "reply-1"},
+		{ID: "reply-pinned", Type: PostTypeReply, CreatedAt: recent, InReplyToID: "reply-1", IsPinned: true},
+		// A thread whose root is pinned stays whole
+		{ID: "kept-root", Type: PostTypeOriginal, CreatedAt: old, IsPinned: true},
+		{ID: "kept-reply", Type: PostTypeReply, CreatedAt: recent, InReplyToID: "kept-root"},
+		// A recent reply to someone else's post isn't part of a self-thread
+		{ID: "elsewhere", Type: PostTypeReply, CreatedAt: recent, InReplyToID: "theirs"},
+	}
+
+	tests := []struct {
+		name  string
+		whole bool
+		want  map[string]string // Selected post IDs and their preserve reasons
+	}{
+		{
+			name: "replies judged on their own by default",
+			want: map[string]string{"root": "", "kept-root": "pinned"},
+		},
+		{
+			name:  "replies under a deleted root selected",
+			whole: true,
+			want:  map[string]string{"root": "", "reply-1": "", "reply-2": "", "reply-pinned": "pinned", "kept-root": "pinned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := PruneOptions{MaxAge: &maxAge, PreservePinned: true, DeleteWholeThreads: tt.whole}
+			options = options.withWholeThreads("mastodon", posts, now)
+
+			got := make(map[string]string)
+			for _, post := range posts {
+				if selected, reason := options.selectForPrune("mastodon", post, now); selected {
+					got[post.ID] = reason
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Selected %v, want %v", got, tt.want)
+			}
+			for id, reason := range tt.want {
+				if gotReason, ok := got[id]; !ok || gotReason != reason {
+					t.Errorf("Post %s: got reason %q (selected %v), want %q", id, gotReason, ok, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneOptions_AfterDate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	options := PruneOptions{BeforeDate: &before, AfterDate: &after}
+
+	tests := []struct {
+		created time.Time
+		want    bool
+	}{
+		{time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), true},
+		{after, true},
+		{time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		post := Post{ID: tt.created.String(), Type: PostTypeOriginal, CreatedAt: tt.created}
+		if selected, _ := options.selectForPrune("bluesky", post, now); selected != tt.want {
+			t.Errorf("Post from %s: selected = %v, want %v", tt.created.Format("2006-01-02"), selected, tt.want)
+		}
+	}
+
+	page := []Post{
+		{CreatedAt: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), IsPinned: true},
+		{CreatedAt: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if options.reachedAfterDate(page) {
+		t.Error("An old pinned post at the top of the page shouldn't end the scan")
+	}
+	page = append(page, Post{CreatedAt: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if !options.reachedAfterDate(page) {
+		t.Error("Expected a page reaching back before after-date to end the scan")
+	}
+
+	if err := options.CheckDateRange(); err != nil {
+		t.Errorf("Unexpected error for a valid window: %v", err)
+	}
+	options.AfterDate = &before
+	if err := options.CheckDateRange(); err == nil {
+		t.Error("Expected an error when after-date isn't before before-date")
+	}
+}
@@ -0,0 +1,240 @@
+// Package timespec parses the durations and dates accepted by flags such as
+// --max-post-age and --before-date, and by the config file that sets them.
+package timespec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar units are fixed lengths: a post's age doesn't need leap years
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+const durationHelp = `use a Go duration ("2h30m"), whole numbers of y, mo, w, d, h, min and s ("1y6mo", "90d"), or ISO 8601 ("P1Y6M")`
+
+// unit is one component of a duration; rank orders units from largest to smallest
+type unit struct {
+	size time.Duration
+	rank int
+}
+
+var units = map[string]unit{
+	"y":   {Year, 0},
+	"mo":  {Month, 1},
+	"w":   {Week, 2},
+	"d":   {Day, 3},
+	"h":   {time.Hour, 4},
+	"min": {time.Minute, 5},
+	"s":   {time.Second, 6},
+}
+
+// ParseDuration parses a non-negative duration in any of these forms:
+//
+//   - Go durations, such as "90m" or "2h30m". These keep their Go meaning, so "6m" is six minutes.
+//   - Whole numbers of calendar units, largest first: "30d", "2w", "1y6mo", "1d12h".
+//     Months are "mo" and minutes are "min". A bare "m" still means minutes after hours,
+//     but after a day or longer it is rejected rather than guessed at.
+//   - ISO 8601 durations, such as "P30D", "P1Y6M" or "PT12H".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("duration is empty")
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("invalid duration %q: negative durations are not allowed", s)
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+	if strings.HasPrefix(s, "P") {
+		d, err = parseISO8601(s)
+	} else {
+		d, err = parseUnits(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// parseUnits parses a sequence of whole numbers and units such as "1y6mo"
+func parseUnits(s string) (time.Duration, error) {
+	var total time.Duration
+	last := -1
+	for rest := s; rest != ""; {
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 {
+			return 0, fmt.Errorf("expected a number at %q; %s", rest, durationHelp)
+		}
+		value, err := strconv.ParseInt(rest[:digits], 10, 64)
+		if err != nil {
+			return 0, errors.New("value is too large")
+		}
+		rest = rest[digits:]
+		if strings.HasPrefix(rest, ".") {
+			return 0, errors.New(`fractions only work in Go durations such as "1.5h"`)
+		}
+
+		name := rest[:len(rest)-len(strings.TrimLeft(rest, "abcdefghijklmnopqrstuvwxyz"))]
+		if name == "" {
+			return 0, fmt.Errorf("%d has no unit; %s", value, durationHelp)
+		}
+		rest = rest[len(name):]
+
+		if name == "m" {
+			if last >= 0 && last < units["h"].rank {
+				return 0, fmt.Errorf(`"%dm" is ambiguous here; use "%dmo" for months or "%dmin" for minutes`, value, value, value)
+			}
+			name = "min"
+		}
+		u, ok := units[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q; %s", name, durationHelp)
+		}
+		if u.rank <= last {
+			return 0, fmt.Errorf("units must go from largest to smallest, each used once")
+		}
+		last = u.rank
+
+		if total, err = add(total, value, u.size); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// parseISO8601 parses an ISO 8601 duration such as "P1Y6M" or "P1DT12H". Only whole
+// numbers are accepted, and years and months use the same fixed lengths as elsewhere.
+func parseISO8601(s string) (time.Duration, error) {
+	body := strings.TrimPrefix(s, "P")
+	date, clock, hasTime := strings.Cut(body, "T")
+	if body == "" || (hasTime && clock == "") {
+		return 0, errors.New("ISO 8601 duration has no components")
+	}
+
+	var total time.Duration
+	parts := []struct {
+		text  string
+		units map[byte]time.Duration
+		order string
+	}{
+		{date, map[byte]time.Duration{'Y': Year, 'M': Month, 'W': Week, 'D': Day}, "YMWD"},
+		{clock, map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}, "HMS"},
+	}
+	for _, part := range parts {
+		last := -1
+		for rest := part.text; rest != ""; {
+			digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+			if digits == 0 || digits == len(rest) {
+				return 0, fmt.Errorf("malformed ISO 8601 duration; %s", durationHelp)
+			}
+			value, err := strconv.ParseInt(rest[:digits], 10, 64)
+			if err != nil {
+				return 0, errors.New("value is too large")
+			}
+			designator := rest[digits]
+			size, ok := part.units[designator]
+			rank := strings.IndexByte(part.order, designator)
+			if !ok || rank <= last {
+				return 0, fmt.Errorf("unexpected %q in ISO 8601 duration", designator)
+			}
+			last = rank
+			if total, err = add(total, value, size); err != nil {
+				return 0, err
+			}
+			rest = rest[digits+1:]
+		}
+	}
+	return total, nil
+}
+
+// add returns total + value*size, failing rather than overflowing
+func add(total time.Duration, value int64, size time.Duration) (time.Duration, error) {
+	if value > int64(math.MaxInt64-total)/int64(size) {
+		return 0, errors.New("value is too large")
+	}
+	return total + time.Duration(value)*size, nil
+}
+
+// dateFormats are tried in order by ParseDate
+var dateFormats = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"01/02/2006",
+	"01/02/2006 15:04:05",
+}
+
+// ParseDate parses a date or time: YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, an RFC 3339
+// timestamp, MM/DD/YYYY or MM/DD/YYYY HH:MM:SS. Times without a zone are UTC.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, errors.New("date is empty")
+	}
+
+	var rangeErr error
+	for _, format := range dateFormats {
+		t, err := time.Parse(format, s)
+		if err == nil {
+			return t, nil
+		}
+		// A date in the right format with an impossible day or month deserves a specific error
+		var parseErr *time.ParseError
+		if rangeErr == nil && errors.As(err, &parseErr) && parseErr.Message != "" {
+			rangeErr = errors.New(strings.TrimPrefix(parseErr.Message, ": "))
+		}
+	}
+
+	if rangeErr != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: %w", s, rangeErr)
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, an RFC 3339 timestamp or MM/DD/YYYY", s)
+}
+
+// Format writes a duration the way ParseDuration reads it most naturally: whole days as
+// "90d", with any remainder in Go form ("1d12h", "36m", or "1d30min" where a bare "m"
+// would be ambiguous). Sub-second durations are left
+// entirely in Go form, which has no day unit.
+func Format(d time.Duration) string {
+	if d <= 0 || d%time.Second != 0 {
+		return d.String()
+	}
+
+	var b strings.Builder
+	days := d / Day
+	if days > 0 {
+		fmt.Fprintf(&b, "%dd", days)
+		d -= days * Day
+	}
+	if d > 0 {
+		rest := d.String()
+		if strings.HasSuffix(rest, "m0s") {
+			rest = strings.TrimSuffix(rest, "0s")
+		}
+		if strings.HasSuffix(rest, "h0m") {
+			rest = strings.TrimSuffix(rest, "0m")
+		}
+		if days > 0 && !strings.Contains(rest, "h") {
+			// ParseDuration won't read a bare "m" straight after days, so spell out minutes
+			rest = strings.Replace(rest, "m", "min", 1)
+		}
+		b.WriteString(rest)
+	}
+	return b.String()
+}
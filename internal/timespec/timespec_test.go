@@ -0,0 +1,254 @@
+package timespec
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"hours", "24h", 24 * time.Hour, false},
+		{"days", "30d", 30 * Day, false},
+		{"weeks", "2w", 2 * Week, false},
+		{"go minutes", "6m", 6 * time.Minute, false},
+		{"months", "6mo", 6 * Month, false},
+		{"years", "1y", Year, false},
+		{"go duration", "2h30m", 2*time.Hour + 30*time.Minute, false},
+		{"complex go duration", "1h30m45s", time.Hour + 30*time.Minute + 45*time.Second, false},
+		{"go fraction", "1.5h", 90 * time.Minute, false},
+		{"zero value", "0d", 0, false},
+		{"zero hours", "0h", 0, false},
+		{"large number", "1000d", 1000 * Day, false},
+		{"surrounding whitespace", " 7d ", 7 * Day, false},
+
+		// Combined units
+		{"bare m after years", "1y6m", 0, true},
+		{"years and explicit months", "1y6mo", Year + 6*Month, false},
+		{"weeks and days", "2w3d", 2*Week + 3*Day, false},
+		{"days and hours", "1d12h", Day + 12*time.Hour, false},
+		{"bare m after days", "1d30m", 0, true},
+		{"bare m after weeks", "2w6m", 0, true},
+		{"bare m after months", "1mo6m", 0, true},
+		{"explicit minutes after days", "1d30min", Day + 30*time.Minute, false},
+		{"months then minutes", "1mo6min", Month + 6*time.Minute, false},
+		{"m after hours is minutes", "1d5h6m", Day + 5*time.Hour + 6*time.Minute, false},
+		{"everything", "1y2mo3w4d5h6m7s", Year + 2*Month + 3*Week + 4*Day + 5*time.Hour + 6*time.Minute + 7*time.Second, false},
+
+		// ISO 8601
+		{"iso days", "P30D", 30 * Day, false},
+		{"iso years and months", "P1Y6M", Year + 6*Month, false},
+		{"iso weeks", "P2W", 2 * Week, false},
+		{"iso time", "PT12H", 12 * time.Hour, false},
+		{"iso minutes", "PT30M", 30 * time.Minute, false},
+		{"iso date and time", "P1DT12H30M", Day + 12*time.Hour + 30*time.Minute, false},
+
+		{"invalid format", "abc", 0, true},
+		{"empty string", "", 0, true},
+		{"single char", "d", 0, true},
+		{"just unit", "d", 0, true},
+		{"negative value", "-5d", 0, true},
+		{"negative go duration", "-5h", 0, true},
+		{"decimal value", "1.5d", 0, true},
+		{"mixed case", "5D", 0, true},
+		{"unknown unit", "5x", 0, true},
+		{"number without unit", "1y6", 0, true},
+		{"units out of order", "6d1y", 0, true},
+		{"unit repeated", "1d2d", 0, true},
+		{"overflow", "999999999y", 0, true},
+		{"iso empty", "P", 0, true},
+		{"iso empty time", "P1DT", 0, true},
+		{"iso out of order", "P1D2Y", 0, true},
+		{"iso time unit in date", "P1H", 0, true},
+		{"iso missing designator", "P5", 0, true},
+		{"iso fraction", "P1.5D", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDuration(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for input %q, got %v", tt.input, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error for input %q: %v", tt.input, err)
+				return
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %v for input %q, got %v", tt.expected, tt.input, result)
+			}
+		})
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		contains string
+	}{
+		{"", "empty"},
+		{"-5d", "negative"},
+		{"5x", `unknown unit "x"`},
+		{"1.5d", "fractions"},
+		{"6d1y", "largest to smallest"},
+		{"1y6m", `use "6mo" for months or "6min" for minutes`},
+		{"999999999y", "too large"},
+		{"abc", `invalid duration "abc"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseDuration(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"ISO date", "2023-12-25", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), false},
+		{"ISO datetime", "2023-12-25 14:30:00", time.Date(2023, 12, 25, 14, 30, 0, 0, time.UTC), false},
+		{"ISO with timezone", "2023-12-25T14:30:00Z", time.Date(2023, 12, 25, 14, 30, 0, 0, time.UTC), false},
+		{"ISO with offset", "2023-12-25T14:30:00-05:00", time.Date(2023, 12, 25, 19, 30, 0, 0, time.UTC), false},
+		{"ISO with milliseconds", "2023-12-25T14:30:00.123Z", time.Date(2023, 12, 25, 14, 30, 0, 123e6, time.UTC), false},
+		{"US date", "12/25/2023", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), false},
+		{"US datetime", "12/25/2023 14:30:00", time.Date(2023, 12, 25, 14, 30, 0, 0, time.UTC), false},
+		{"leap year date", "2024-02-29", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"future date", "2030-12-31", time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"epoch date", "1970-01-01", time.Unix(0, 0).UTC(), false},
+		{"invalid format", "25-12-2023", time.Time{}, true},
+		{"invalid date", "2023-13-40", time.Time{}, true},
+		{"invalid leap year", "2023-02-29", time.Time{}, true},
+		{"empty string", "", time.Time{}, true},
+		{"partial date", "2023-12", time.Time{}, true},
+		{"just year", "2023", time.Time{}, true},
+		{"european format", "25/12/2023", time.Time{}, true}, // Not supported
+		{"time only", "15:30:00", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDate(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for input %q, got %v", tt.input, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error for input %q: %v", tt.input, err)
+				return
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v for input %q, got %v", tt.expected, tt.input, result)
+			}
+		})
+	}
+}
+
+func TestParseDateErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		contains string
+	}{
+		{"2023-02-29", "day out of range"},
+		{"2023-13-01", "month out of range"},
+		{"yesterday", "use YYYY-MM-DD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseDate(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{90 * Day, "90d"},
+		{Year, "365d"},
+		{Day + 12*time.Hour, "1d12h"},
+		{Day + 30*time.Minute, "1d30min"},
+		{Day + 30*time.Minute + 15*time.Second, "1d30min15s"},
+		{Day + 15*time.Second, "1d15s"},
+		{36 * time.Minute, "36m"},
+		{2*time.Hour + 30*time.Minute, "2h30m"},
+		{time.Hour + 45*time.Second, "1h0m45s"},
+		{1500 * time.Millisecond, "1.5s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := Format(tt.input); got != tt.expected {
+				t.Errorf("Format(%v) = %q, expected %q", tt.input, got, tt.expected)
+			}
+			if parsed, err := ParseDuration(Format(tt.input)); err != nil || parsed != tt.input {
+				t.Errorf("ParseDuration(%q) = %v, %v, expected %v", Format(tt.input), parsed, err, tt.input)
+			}
+		})
+	}
+}
+
+func FuzzParseDuration(f *testing.F) {
+	for _, seed := range []string{"30d", "6m", "1y6m", "2h30m", "1d30m", "P1Y6M", "P1DT12H", "PT", "-5d", "1.5d", "999999999y", "5D"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return
+		}
+		if d < 0 {
+			t.Errorf("ParseDuration(%q) = %v, which is negative", s, d)
+		}
+		// Whatever was accepted must survive a round trip through its Go form and Format
+		for _, formatted := range []string{d.String(), Format(d)} {
+			if again, err := ParseDuration(formatted); err != nil || again != d {
+				t.Errorf("ParseDuration(%q) = %v, but %q doesn't parse back to it: %v, %v", s, d, formatted, again, err)
+			}
+		}
+	})
+}
+
+func FuzzParseDate(f *testing.F) {
+	for _, seed := range []string{"2023-12-25", "2023-12-25 14:30:00", "2023-12-25T14:30:00-05:00", "12/25/2023", "2023-02-29", "2023"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		date, err := ParseDate(s)
+		if err != nil {
+			return
+		}
+		formatted := date.Format(time.RFC3339Nano)
+		if again, err := ParseDate(formatted); err != nil || !again.Equal(date) {
+			t.Errorf("ParseDate(%q) = %v, but %q doesn't parse back to it: %v, %v", s, date, formatted, again, err)
+		}
+	})
+}
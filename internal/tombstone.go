@@ -0,0 +1,222 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tombstone actions
+const (
+	TombstoneActionDeleted  = "deleted"
+	TombstoneActionUnliked  = "unliked"
+	TombstoneActionUnshared = "unshared"
+	TombstoneActionRedacted = "redacted" // Edited to RedactedContent, so still on the platform
+)
+
+// Tombstone records a post that cringesweeper removed from a platform
+type Tombstone struct {
+	Platform  string    `json:"platform"`
+	ID        string    `json:"id"`
+	Action    string    `json:"action"`
+	RemovedAt time.Time `json:"removed_at"`
+	Operator  string    `json:"operator,omitempty"` // Who ran the prune, empty for entries written before this was recorded
+}
+
+// TombstoneStore is an append-only, per-platform log of post IDs removed by cringesweeper.
+// It lets later runs tell "deleted by cringesweeper" apart from "missing for other reasons".
+//
+// Each platform gets its own file with one tab-separated line per removal:
+//
+//	<RFC3339 timestamp>\t<action>\t<post ID>\t<operator>
+//
+// Older files may lack the operator column.
+type TombstoneStore struct {
+	dir    string
+	mu     sync.Mutex
+	loaded map[string]map[string]Tombstone
+}
+
+// NewTombstoneStore creates a tombstone store in ~/.config/cringesweeper/tombstones
+func NewTombstoneStore() (*TombstoneStore, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return NewTombstoneStoreAt(filepath.Join(homeDir, ".config", "cringesweeper", "tombstones")), nil
+}
+
+// NewTombstoneStoreAt creates a tombstone store rooted at the given directory
+func NewTombstoneStoreAt(dir string) *TombstoneStore {
+	return &TombstoneStore{
+		dir:    dir,
+		loaded: make(map[string]map[string]Tombstone),
+	}
+}
+
+func (ts *TombstoneStore) path(platform string) string {
+	return filepath.Join(ts.dir, fmt.Sprintf("%s.tsv", strings.ToLower(platform)))
+}
+
+// Record appends a tombstone for a post removed by the given operator
+func (ts *TombstoneStore) Record(platform, action, id, operator string, removedAt time.Time) error {
+	if id == "" {
+		return fmt.Errorf("post ID is required")
+	}
+	if strings.ContainsAny(id, "\t\n") || strings.ContainsAny(action, "\t\n") || strings.ContainsAny(operator, "\t\n") {
+		return fmt.Errorf("tombstone fields must not contain tabs or newlines")
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if err := os.MkdirAll(ts.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create tombstone directory: %w", err)
+	}
+
+	f, err := os.OpenFile(ts.path(platform), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open tombstone file: %w", err)
+	}
+	defer f.Close()
+
+	line := fmt.Sprintf("%s\t%s\t%s\t%s\n", removedAt.UTC().Format(time.RFC3339), action, id, operator)
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write tombstone: %w", err)
+	}
+
+	// Keep the in-memory view in sync if this platform has already been loaded
+	if tombstones, ok := ts.loaded[strings.ToLower(platform)]; ok {
+		tombstones[id] = Tombstone{
+			Platform:  strings.ToLower(platform),
+			ID:        id,
+			Action:    action,
+			RemovedAt: removedAt.UTC(),
+			Operator:  operator,
+		}
+	}
+
+	return nil
+}
+
+// Load returns all tombstones for a platform keyed by post ID. Later entries win.
+func (ts *TombstoneStore) Load(platform string) (map[string]Tombstone, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.loadLocked(platform)
+}
+
+func (ts *TombstoneStore) loadLocked(platform string) (map[string]Tombstone, error) {
+	platform = strings.ToLower(platform)
+	if tombstones, ok := ts.loaded[platform]; ok {
+		return tombstones, nil
+	}
+
+	tombstones := make(map[string]Tombstone)
+
+	f, err := os.Open(ts.path(platform))
+	if err != nil {
+		if os.IsNotExist(err) {
+			ts.loaded[platform] = tombstones
+			return tombstones, nil
+		}
+		return nil, fmt.Errorf("failed to open tombstone file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(parts) < 3 {
+			continue // Skip partial writes and blank lines
+		}
+		removedAt, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil {
+			continue
+		}
+		tombstone := Tombstone{
+			Platform:  platform,
+			ID:        parts[2],
+			Action:    parts[1],
+			RemovedAt: removedAt,
+		}
+		if len(parts) == 4 {
+			tombstone.Operator = parts[3]
+		}
+		tombstones[tombstone.ID] = tombstone
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tombstone file: %w", err)
+	}
+
+	ts.loaded[platform] = tombstones
+	return tombstones, nil
+}
+
+// Contains returns true if the post ID was previously removed by cringesweeper
+func (ts *TombstoneStore) Contains(platform, id string) bool {
+	_, ok := ts.Get(platform, id)
+	return ok
+}
+
+// Get returns the tombstone for a post ID if one exists
+func (ts *TombstoneStore) Get(platform, id string) (Tombstone, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	tombstones, err := ts.loadLocked(platform)
+	if err != nil {
+		return Tombstone{}, false
+	}
+	tombstone, ok := tombstones[id]
+	return tombstone, ok
+}
+
+var (
+	defaultTombstones     *TombstoneStore
+	defaultTombstonesOnce sync.Once
+)
+
+// DefaultTombstoneStore returns the shared tombstone store, or nil if it can't be created
+func DefaultTombstoneStore() *TombstoneStore {
+	defaultTombstonesOnce.Do(func() {
+		store, err := NewTombstoneStore()
+		if err != nil {
+			Logger.Warn().Err(err).Msg("Tombstone index unavailable")
+			return
+		}
+		defaultTombstones = store
+	})
+	return defaultTombstones
+}
+
+// recordTombstone appends to the default tombstone store, logging rather than failing on errors
+func recordTombstone(platform, action, id string) {
+	store := DefaultTombstoneStore()
+	if store == nil {
+		return
+	}
+	if err := store.Record(platform, action, id, GetOperator(), time.Now()); err != nil {
+		WithPlatform(platform).Warn().Err(err).Str("post_id", id).Msg("Failed to record tombstone")
+	}
+}
+
+// wasDeleted returns true if the default tombstone store shows cringesweeper deleted the post,
+// under its current ID or any earlier one in the post ID map. Unlikes and unshares are not
+// considered, since those actions can be repeated on the same ID.
+func wasDeleted(platform, id string) bool {
+	store := DefaultTombstoneStore()
+	if store == nil {
+		return false
+	}
+	for _, alias := range postAliases(platform, id) {
+		if tombstone, ok := store.Get(platform, alias); ok && tombstone.Action == TombstoneActionDeleted {
+			return true
+		}
+	}
+	return false
+}
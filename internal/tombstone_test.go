@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTombstoneStore_RecordAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	store := NewTombstoneStoreAt(dir)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := store.Record("bluesky", TombstoneActionDeleted, "at://did:plc:abc/app.bsky.feed.post/1", "tester", now); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := store.Record("bluesky", TombstoneActionUnshared, "at://did:plc:abc/app.bsky.feed.repost/2", "tester", now); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := store.Record("mastodon", TombstoneActionDeleted, "109876", "tester", now); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// A fresh store must read back what was written
+	reloaded := NewTombstoneStoreAt(dir)
+	tombstones, err := reloaded.Load("bluesky")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tombstones) != 2 {
+		t.Fatalf("Expected 2 bluesky tombstones, got %d", len(tombstones))
+	}
+
+	tombstone, ok := reloaded.Get("bluesky", "at://did:plc:abc/app.bsky.feed.post/1")
+	if !ok {
+		t.Fatal("Expected tombstone for deleted post")
+	}
+	if tombstone.Action != TombstoneActionDeleted {
+		t.Errorf("Expected action %q, got %q", TombstoneActionDeleted, tombstone.Action)
+	}
+	if !tombstone.RemovedAt.Equal(now) {
+		t.Errorf("Expected removed at %v, got %v", now, tombstone.RemovedAt)
+	}
+	if tombstone.Operator != "tester" {
+		t.Errorf("Expected operator %q, got %q", "tester", tombstone.Operator)
+	}
+
+	if !reloaded.Contains("mastodon", "109876") {
+		t.Error("Expected mastodon tombstone to be present")
+	}
+	if reloaded.Contains("mastodon", "at://did:plc:abc/app.bsky.feed.post/1") {
+		t.Error("Tombstones should be scoped per platform")
+	}
+}
+
+func TestTombstoneStore_AppendOnly(t *testing.T) {
+	dir := t.TempDir()
+	store := NewTombstoneStoreAt(dir)
+
+	for i := 0; i < 3; i++ {
+		if err := store.Record("mastodon", TombstoneActionUnliked, "42", "tester", time.Now()); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "mastodon.tsv"))
+	if err != nil {
+		t.Fatalf("Failed to read tombstone file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Errorf("Expected 3 appended lines, got %d", len(lines))
+	}
+}
+
+func TestTombstoneStore_LaterEntriesWin(t *testing.T) {
+	store := NewTombstoneStoreAt(t.TempDir())
+
+	store.Record("mastodon", TombstoneActionUnliked, "42", "tester", time.Now())
+	store.Record("mastodon", TombstoneActionDeleted, "42", "tester", time.Now())
+
+	tombstone, ok := NewTombstoneStoreAt(store.dir).Get("mastodon", "42")
+	if !ok || tombstone.Action != TombstoneActionDeleted {
+		t.Errorf("Expected latest action %q, got %+v", TombstoneActionDeleted, tombstone)
+	}
+}
+
+func TestTombstoneStore_RecordUpdatesLoadedView(t *testing.T) {
+	store := NewTombstoneStoreAt(t.TempDir())
+
+	if store.Contains("bluesky", "abc") {
+		t.Fatal("Empty store should not contain any tombstones")
+	}
+	store.Record("bluesky", TombstoneActionDeleted, "abc", "tester", time.Now())
+	if !store.Contains("bluesky", "abc") {
+		t.Error("Recorded tombstone should be visible without reloading")
+	}
+}
+
+func TestTombstoneStore_InvalidInput(t *testing.T) {
+	store := NewTombstoneStoreAt(t.TempDir())
+
+	tests := []struct {
+		name     string
+		action   string
+		id       string
+		operator string
+	}{
+		{"empty id", TombstoneActionDeleted, "", "tester"},
+		{"tab in id", TombstoneActionDeleted, "a\tb", "tester"},
+		{"newline in id", TombstoneActionDeleted, "a\nb", "tester"},
+		{"tab in action", "dele\tted", "abc", "tester"},
+		{"newline in operator", TombstoneActionDeleted, "abc", "test\ner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Record("bluesky", tt.action, tt.id, tt.operator, time.Now()); err == nil {
+				t.Error("Expected error for invalid tombstone")
+			}
+		})
+	}
+}
+
+func TestTombstoneStore_SkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	content := "not a tombstone\n" +
+		"2025-06-01T12:00:00Z\tdeleted\tgood-id\n" +
+		"bad-time\tdeleted\tbad-id\n" +
+		"2025-06-01T12:00:00Z\tdeleted\n"
+	if err := os.WriteFile(filepath.Join(dir, "bluesky.tsv"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	tombstones, err := NewTombstoneStoreAt(dir).Load("bluesky")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tombstones) != 1 {
+		t.Errorf("Expected 1 valid tombstone, got %d", len(tombstones))
+	}
+	if _, ok := tombstones["good-id"]; !ok {
+		t.Error("Expected good-id to be loaded")
+	}
+}
+
+func TestTombstoneStore_LoadsEntriesWithoutOperator(t *testing.T) {
+	dir := t.TempDir()
+	legacy := "2025-06-01T12:00:00Z\tdeleted\t109876\n2025-06-02T12:00:00Z\tdeleted\t109877\tci-bot\n"
+	if err := os.WriteFile(filepath.Join(dir, "mastodon.tsv"), []byte(legacy), 0600); err != nil {
+		t.Fatalf("Failed to write tombstone file: %v", err)
+	}
+
+	store := NewTombstoneStoreAt(dir)
+	tombstone, ok := store.Get("mastodon", "109876")
+	if !ok {
+		t.Fatal("Expected tombstone written before operators were recorded to load")
+	}
+	if tombstone.Operator != "" {
+		t.Errorf("Expected empty operator for legacy entry, got %q", tombstone.Operator)
+	}
+	if tombstone, _ := store.Get("mastodon", "109877"); tombstone.Operator != "ci-bot" {
+		t.Errorf("Expected operator %q, got %q", "ci-bot", tombstone.Operator)
+	}
+}
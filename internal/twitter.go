@@ -0,0 +1,276 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// twitterTimeLayout is the created_at format used throughout Twitter archives
+const twitterTimeLayout = "Mon Jan 02 15:04:05 -0700 2006"
+
+// TwitterArchiveClient reads posts from a downloaded Twitter/X archive zip. The archive
+// path takes the place of the username. Deletion through the API is no longer possible,
+// so this is a read-only PostReader rather than a SocialClient.
+type TwitterArchiveClient struct {
+	mu     sync.Mutex
+	loaded map[string][]Post // Parsed archives keyed by path, newest post first
+}
+
+// NewTwitterArchiveClient creates a new Twitter archive reader
+func NewTwitterArchiveClient() *TwitterArchiveClient {
+	return &TwitterArchiveClient{
+		loaded: make(map[string][]Post),
+	}
+}
+
+// GetPlatformName returns the platform name
+func (c *TwitterArchiveClient) GetPlatformName() string {
+	return "Twitter archive"
+}
+
+// FetchUserPosts returns the most recent posts in the archive
+func (c *TwitterArchiveClient) FetchUserPosts(ctx context.Context, archivePath string, limit int) ([]Post, error) {
+	posts, _, err := c.FetchUserPostsPaginated(ctx, archivePath, limit, "")
+	return posts, err
+}
+
+// FetchUserPostsPaginated pages through the archive newest first. The cursor is the
+// offset of the next page, and is empty once the archive is exhausted.
+func (c *TwitterArchiveClient) FetchUserPostsPaginated(ctx context.Context, archivePath string, limit int, cursor string) ([]Post, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	posts, err := c.load(archivePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	offset := 0
+	if cursor != "" {
+		offset, err = strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+	if offset >= len(posts) {
+		return nil, "", nil
+	}
+
+	end := len(posts)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	nextCursor := ""
+	if end < len(posts) {
+		nextCursor = strconv.Itoa(end)
+	}
+	return posts[offset:end], nextCursor, nil
+}
+
+// load parses an archive once and caches the result
+func (c *TwitterArchiveClient) load(archivePath string) ([]Post, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if posts, ok := c.loaded[archivePath]; ok {
+		return posts, nil
+	}
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Twitter archive: %w", err)
+	}
+	defer archive.Close()
+
+	posts, err := parseTwitterArchive(&archive.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := WithPlatform("twitter")
+	logger.Debug().Str("archive", archivePath).Int("posts", len(posts)).Msg("Loaded Twitter archive")
+
+	c.loaded[archivePath] = posts
+	return posts, nil
+}
+
+// Twitter archive types
+type twitterAccount struct {
+	Account struct {
+		Username    string `json:"username"`
+		DisplayName string `json:"accountDisplayName"`
+	} `json:"account"`
+}
+
+type twitterTweetEntry struct {
+	Tweet twitterTweet `json:"tweet"`
+}
+
+type twitterTweet struct {
+	ID                  string `json:"id_str"`
+	FullText            string `json:"full_text"`
+	CreatedAt           string `json:"created_at"`
+	FavoriteCount       string `json:"favorite_count"` // Archives store counts as strings
+	RetweetCount        string `json:"retweet_count"`
+	InReplyToStatusID   string `json:"in_reply_to_status_id_str"`
+	InReplyToScreenName string `json:"in_reply_to_screen_name"`
+	Entities            struct {
+		Hashtags []struct {
+			Text string `json:"text"`
+		} `json:"hashtags"`
+		URLs []struct {
+			ExpandedURL string `json:"expanded_url"` // The link t.co shortened
+		} `json:"urls"`
+	} `json:"entities"`
+	ExtendedEntities struct {
+		Media []struct {
+			Type          string `json:"type"` // photo, video or animated_gif
+			MediaURLHTTPS string `json:"media_url_https"`
+		} `json:"media"`
+	} `json:"extended_entities"`
+}
+
+// parseTwitterArchive reads the account and every tweets part file from an archive
+func parseTwitterArchive(archive *zip.Reader) ([]Post, error) {
+	var account twitterAccount
+	var tweetFiles []*zip.File
+	for _, file := range archive.File {
+		name := path.Base(file.Name)
+		switch {
+		case name == "account.js":
+			var accounts []twitterAccount
+			if err := readTwitterDataFile(file, &accounts); err != nil {
+				return nil, err
+			}
+			if len(accounts) > 0 {
+				account = accounts[0]
+			}
+		// Newer archives use tweets.js, older ones tweet.js; large archives add -partN files
+		case strings.HasPrefix(name, "tweets") && strings.HasSuffix(name, ".js"),
+			strings.HasPrefix(name, "tweet.js"), strings.HasPrefix(name, "tweet-part"):
+			tweetFiles = append(tweetFiles, file)
+		}
+	}
+
+	if len(tweetFiles) == 0 {
+		return nil, fmt.Errorf("no tweets.js found in archive")
+	}
+
+	var posts []Post
+	for _, file := range tweetFiles {
+		var entries []twitterTweetEntry
+		if err := readTwitterDataFile(file, &entries); err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			post, err := entry.Tweet.toPost(account)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse tweet %s: %w", entry.Tweet.ID, err)
+			}
+			posts = append(posts, post)
+		}
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.After(posts[j].CreatedAt)
+	})
+	return posts, nil
+}
+
+// readTwitterDataFile decodes an archive data file, which is a JSON array assigned to a
+// JavaScript global such as "window.YTD.tweets.part0 = [...]"
+func readTwitterDataFile(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+
+	start := bytes.IndexByte(data, '[')
+	if start < 0 {
+		return fmt.Errorf("no data found in %s", file.Name)
+	}
+	if err := json.Unmarshal(data[start:], v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// toPost converts an archived tweet into the generic Post format
+func (t twitterTweet) toPost(account twitterAccount) (Post, error) {
+	createdAt, err := time.Parse(twitterTimeLayout, t.CreatedAt)
+	if err != nil {
+		return Post{}, fmt.Errorf("invalid created_at: %w", err)
+	}
+
+	handle := account.Account.Username
+	post := Post{
+		ID:        t.ID,
+		Author:    account.Account.DisplayName,
+		Handle:    handle,
+		Content:   t.FullText,
+		CreatedAt: createdAt,
+		Type:      PostTypeOriginal,
+		Platform:  "twitter",
+	}
+	if post.Author == "" {
+		post.Author = handle
+	}
+	if handle != "" {
+		post.URL = fmt.Sprintf("https://x.com/%s/status/%s", handle, t.ID)
+	}
+
+	post.LikeCount, _ = strconv.Atoi(t.FavoriteCount)
+	post.RepostCount, _ = strconv.Atoi(t.RetweetCount)
+
+	for _, hashtag := range t.Entities.Hashtags {
+		post.Hashtags = append(post.Hashtags, hashtag.Text)
+	}
+	for _, link := range t.Entities.URLs {
+		if link.ExpandedURL != "" {
+			post.Links = append(post.Links, link.ExpandedURL)
+		}
+	}
+	for _, media := range t.ExtendedEntities.Media {
+		mediaType := media.Type
+		switch mediaType {
+		case "photo":
+			mediaType = "image"
+		case "animated_gif":
+			mediaType = "gifv"
+		}
+		post.Attachments = append(post.Attachments, Attachment{Type: mediaType, URL: media.MediaURLHTTPS})
+	}
+
+	// Archives have no retweet flag; retweets are stored with an "RT @user:" prefix
+	if strings.HasPrefix(t.FullText, "RT @") {
+		post.Type = PostTypeRepost
+		if end := strings.Index(t.FullText, ":"); end > len("RT @") {
+			post.OriginalHandle = t.FullText[len("RT @"):end]
+			post.OriginalAuthor = post.OriginalHandle
+		}
+	} else if t.InReplyToStatusID != "" {
+		post.Type = PostTypeReply
+		post.InReplyToID = t.InReplyToStatusID
+		post.InReplyToAuthor = t.InReplyToScreenName
+		post.SelfReply = handle != "" && strings.EqualFold(t.InReplyToScreenName, handle)
+	}
+
+	return post, nil
+}
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testTwitterAccount = `window.YTD.account.part0 = [
+  {
+    "account" : {
+      "username" : "testuser",
+      "accountDisplayName" : "Test User"
+    }
+  }
+]`
+
+const testTwitterTweets = `window.YTD.tweets.part0 = [
+  {
+    "tweet" : {
+      "id_str" : "100",
+      "full_text" : "Oldest original post #Throwback https://t.co/abc",
+      "created_at" : "Mon Mar 01 10:00:00 +0000 2010",
+      "favorite_count" : "7",
+      "retweet_count" : "2",
+      "entities" : {
+        "hashtags" : [ { "text" : "Throwback" } ],
+        "urls" : [ { "url" : "https://t.co/abc", "expanded_url" : "https://example.com/2010" } ]
+      }
+    }
+  },
+  {
+    "tweet" : {
+      "id_str" : "300",
+      "full_text" : "RT @someone: something worth sharing",
+      "created_at" : "Wed Jun 15 14:30:00 +0000 2022",
+      "favorite_count" : "0",
+      "retweet_count" : "0"
+    }
+  },
+  {
+    "tweet" : {
+      "id_str" : "200",
+      "full_text" : "@friend agreed, with a photo",
+      "created_at" : "Sat Jan 02 08:00:00 +0000 2016",
+      "favorite_count" : "1",
+      "retweet_count" : "0",
+      "in_reply_to_status_id_str" : "150",
+      "in_reply_to_screen_name" : "friend",
+      "extended_entities" : {
+        "media" : [ { "type" : "photo", "media_url_https" : "https://pbs.twimg.com/media/abc.jpg" } ]
+      }
+    }
+  }
+]`
+
+// writeTestArchive builds a Twitter archive zip from the given data files
+func writeTestArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "twitter-archive.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to finish archive: %v", err)
+	}
+	return path
+}
+
+func TestTwitterArchiveClient_FetchUserPosts(t *testing.T) {
+	archive := writeTestArchive(t, map[string]string{
+		"data/account.js": testTwitterAccount,
+		"data/tweets.js":  testTwitterTweets,
+	})
+
+	client := NewTwitterArchiveClient()
+	posts, err := client.FetchUserPosts(context.Background(), archive, 10)
+	if err != nil {
+		t.Fatalf("FetchUserPosts failed: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("Expected 3 posts, got %d", len(posts))
+	}
+
+	// Newest first regardless of archive order
+	wantIDs := []string{"300", "200", "100"}
+	for i, id := range wantIDs {
+		if posts[i].ID != id {
+			t.Errorf("Post %d: expected ID %s, got %s", i, id, posts[i].ID)
+		}
+	}
+
+	repost := posts[0]
+	if repost.Type != PostTypeRepost || repost.OriginalHandle != "someone" {
+		t.Errorf("Expected repost of @someone, got type %s from %q", repost.Type, repost.OriginalHandle)
+	}
+
+	reply := posts[1]
+	if reply.Type != PostTypeReply || reply.InReplyToID != "150" || reply.InReplyToAuthor != "friend" {
+		t.Errorf("Unexpected reply metadata: %+v", reply)
+	}
+	if len(reply.Attachments) != 1 || reply.Attachments[0].Type != "image" {
+		t.Errorf("Expected one image attachment, got %+v", reply.Attachments)
+	}
+
+	original := posts[2]
+	if original.Type != PostTypeOriginal {
+		t.Errorf("Expected original post, got %s", original.Type)
+	}
+	if original.LikeCount != 7 || original.RepostCount != 2 {
+		t.Errorf("Expected 7 likes and 2 reposts, got %d and %d", original.LikeCount, original.RepostCount)
+	}
+	if len(original.Hashtags) != 1 || original.Hashtags[0] != "Throwback" {
+		t.Errorf("Expected Throwback hashtag, got %v", original.Hashtags)
+	}
+	if len(original.Links) != 1 || original.Links[0] != "https://example.com/2010" {
+		t.Errorf("Expected the expanded link, got %v", original.Links)
+	}
+	if !original.CreatedAt.Equal(time.Date(2010, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected created time %v", original.CreatedAt)
+	}
+	if original.Handle != "testuser" || original.Author != "Test User" {
+		t.Errorf("Unexpected author %q (@%s)", original.Author, original.Handle)
+	}
+	if original.URL != "https://x.com/testuser/status/100" {
+		t.Errorf("Unexpected URL %s", original.URL)
+	}
+	if original.Platform != "twitter" {
+		t.Errorf("Expected platform twitter, got %s", original.Platform)
+	}
+}
+
+func TestTwitterArchiveClient_Pagination(t *testing.T) {
+	archive := writeTestArchive(t, map[string]string{
+		"data/account.js": testTwitterAccount,
+		"data/tweets.js":  testTwitterTweets,
+	})
+
+	client := NewTwitterArchiveClient()
+	var ids []string
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		posts, next, err := client.FetchUserPostsPaginated(context.Background(), archive, 2, cursor)
+		if err != nil {
+			t.Fatalf("FetchUserPostsPaginated failed: %v", err)
+		}
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(ids) != 3 || ids[0] != "300" || ids[2] != "100" {
+		t.Errorf("Expected all 3 posts across pages, got %v", ids)
+	}
+
+	if _, _, err := client.FetchUserPostsPaginated(context.Background(), archive, 2, "bogus"); err == nil {
+		t.Error("Expected error for invalid cursor")
+	}
+}
+
+func TestTwitterArchiveClient_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "no tweets file",
+			files: map[string]string{"data/account.js": testTwitterAccount},
+		},
+		{
+			name:  "malformed tweets file",
+			files: map[string]string{"data/tweets.js": "window.YTD.tweets.part0 = [ { nope"},
+		},
+		{
+			name: "bad timestamp",
+			files: map[string]string{"data/tweets.js": `window.YTD.tweets.part0 = [
+  { "tweet" : { "id_str" : "1", "full_text" : "hi", "created_at" : "yesterday" } }
+]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := writeTestArchive(t, tt.files)
+			if _, err := NewTwitterArchiveClient().FetchUserPosts(context.Background(), archive, 10); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if _, err := NewTwitterArchiveClient().FetchUserPosts(context.Background(), filepath.Join(t.TempDir(), "missing.zip"), 10); err == nil {
+		t.Error("Expected error for missing archive")
+	}
+}
+
+func TestTwitterArchiveClient_MultipleParts(t *testing.T) {
+	archive := writeTestArchive(t, map[string]string{
+		"data/tweets.js": testTwitterTweets,
+		"data/tweets-part1.js": `window.YTD.tweets.part1 = [
+  { "tweet" : { "id_str" : "50", "full_text" : "very first", "created_at" : "Fri Jan 01 00:00:00 +0000 2010" } }
+]`,
+	})
+
+	posts, err := NewTwitterArchiveClient().FetchUserPosts(context.Background(), archive, 0)
+	if err != nil {
+		t.Fatalf("FetchUserPosts failed: %v", err)
+	}
+	if len(posts) != 4 {
+		t.Fatalf("Expected 4 posts across parts, got %d", len(posts))
+	}
+	if posts[3].ID != "50" {
+		t.Errorf("Expected oldest post from part1 last, got %s", posts[3].ID)
+	}
+	// Without account.js there's no handle to build URLs from
+	if posts[0].URL != "" {
+		t.Errorf("Expected no URL without account data, got %s", posts[0].URL)
+	}
+}
+
+func TestGetReader(t *testing.T) {
+	for _, platform := range []string{"bluesky", "mastodon", "twitter"} {
+		if _, ok := GetReader(platform); !ok {
+			t.Errorf("Expected reader for %s", platform)
+		}
+	}
+	if _, ok := GetReader("myspace"); ok {
+		t.Error("Expected no reader for unknown platform")
+	}
+	// Archives must never be handed to prune
+	if _, ok := GetClient("twitter"); ok {
+		t.Error("twitter must not be a prunable platform")
+	}
+}
+
+func TestParseReadablePlatforms(t *testing.T) {
+	platforms, err := ParseReadablePlatforms("twitter,bluesky")
+	if err != nil {
+		t.Fatalf("ParseReadablePlatforms failed: %v", err)
+	}
+	if len(platforms) != 2 || platforms[0] != "twitter" {
+		t.Errorf("Unexpected platforms %v", platforms)
+	}
+
+	all, _ := ParseReadablePlatforms("all")
+	for _, platform := range all {
+		if platform == "twitter" {
+			t.Error("'all' should not include archive platforms")
+		}
+	}
+
+	if _, err := ParsePlatforms("twitter"); err == nil {
+		t.Error("ParsePlatforms should reject read-only platforms")
+	}
+}
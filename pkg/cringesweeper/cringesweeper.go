@@ -0,0 +1,136 @@
+// Package cringesweeper lets other Go programs fetch and prune social media posts with
+// the same clients and prune engine the cringesweeper command uses.
+//
+// Clients authenticate the same way the command does: from credentials saved by
+// "cringesweeper auth" under ~/.config/cringesweeper, or from the platform's environment
+// variables such as BLUESKY_USER and BLUESKY_PASSWORD.
+//
+//	client, ok := cringesweeper.NewClient("bluesky")
+//	if !ok {
+//		return errors.New("unsupported platform")
+//	}
+//	maxAge := 90 * 24 * time.Hour
+//	result, err := client.PrunePosts(ctx, "me.bsky.social", cringesweeper.PruneOptions{
+//		MaxAge:         &maxAge,
+//		PreservePinned: true,
+//		DryRun:         true,
+//	})
+//
+// The types here are the ones the command itself uses, so they stay in step with it.
+// Fields and functions are only ever added to them, never renamed or removed.
+package cringesweeper
+
+import (
+	"github.com/gerrowadat/cringesweeper/internal"
+)
+
+// Post is a post, repost or like from any platform
+type Post = internal.Post
+
+// PostType is what kind of post a Post is
+type PostType = internal.PostType
+
+// Attachment describes a media item attached to a post
+type Attachment = internal.Attachment
+
+const (
+	PostTypeOriginal = internal.PostTypeOriginal // The account's own original content
+	PostTypeRepost   = internal.PostTypeRepost   // Repost of another post
+	PostTypeReply    = internal.PostTypeReply    // Reply to another post
+	PostTypeLike     = internal.PostTypeLike     // Post the account has liked
+	PostTypeQuote    = internal.PostTypeQuote    // Quote post
+)
+
+// Post visibilities, named as Mastodon's API names them
+const (
+	VisibilityPublic   = internal.VisibilityPublic
+	VisibilityUnlisted = internal.VisibilityUnlisted
+	VisibilityPrivate  = internal.VisibilityPrivate
+	VisibilityDirect   = internal.VisibilityDirect
+)
+
+// PostReader fetches an account's posts. It works against any public account.
+type PostReader = internal.PostReader
+
+// SocialClient is a PostReader that can also prune the authenticated account's posts
+type SocialClient = internal.SocialClient
+
+// PruneOptions selects which posts a prune acts on and how
+type PruneOptions = internal.PruneOptions
+
+// PruneResult reports what a prune did, or with DryRun would do
+type PruneResult = internal.PruneResult
+
+// PruneDecision is the answer to a ConfirmFunc about one post
+type PruneDecision = internal.PruneDecision
+
+// ConfirmFunc decides whether a prune acts on a post, when set as PruneOptions.Confirm
+type ConfirmFunc = internal.ConfirmFunc
+
+const (
+	PruneProceed = internal.PruneProceed // Act on this post
+	PruneSkip    = internal.PruneSkip    // Leave this post alone and ask about the next
+	PruneAll     = internal.PruneAll     // Act on this post and every remaining one without asking
+	PruneQuit    = internal.PruneQuit    // Leave this post and every remaining one alone
+)
+
+// RedactedContent replaces the text of posts redacted with PruneOptions.Redact
+const RedactedContent = internal.RedactedContent
+
+// Credentials are the saved login for one platform account
+type Credentials = internal.Credentials
+
+// APIError is a non-success HTTP response from a platform API
+type APIError = internal.APIError
+
+var (
+	// ErrNotOwnAccount is returned by PrunePosts when the target isn't the authenticated account
+	ErrNotOwnAccount = internal.ErrNotOwnAccount
+
+	// ErrRedactUnsupported is returned by PrunePosts when Redact is set for a platform
+	// whose posts can't be edited
+	ErrRedactUnsupported = internal.ErrRedactUnsupported
+
+	// ErrNoCredentials is wrapped by credential lookups that find nothing for a platform
+	ErrNoCredentials = internal.ErrNoCredentials
+)
+
+// NewClient returns a client for a platform that can be pruned, such as "bluesky",
+// "mastodon" or "gotosocial"
+func NewClient(platform string) (SocialClient, bool) {
+	return internal.GetClient(platform)
+}
+
+// NewReader returns a reader for any platform that can be listed, including read-only
+// ones such as a "twitter" archive
+func NewReader(platform string) (PostReader, bool) {
+	return internal.GetReader(platform)
+}
+
+// Platforms returns the names of the platforms NewClient supports, sorted
+func Platforms() []string {
+	return internal.GetAllPlatformNames()
+}
+
+// ReadablePlatforms returns the names of the platforms NewReader supports
+func ReadablePlatforms() []string {
+	return internal.GetAllReadablePlatformNames()
+}
+
+// ParseHashtags parses a comma-separated list of hashtags for PruneOptions'
+// PreserveHashtags and WithHashtags
+func ParseHashtags(hashtags string) []string {
+	return internal.ParseHashtags(hashtags)
+}
+
+// ParseLanguages parses a comma-separated list of language tags for PruneOptions'
+// PreserveLanguages and WithLanguages
+func ParseLanguages(languages string) []string {
+	return internal.ParseLanguages(languages)
+}
+
+// ParseVisibilities parses a comma-separated list of visibilities for
+// PruneOptions.Visibilities
+func ParseVisibilities(visibilities string) ([]string, error) {
+	return internal.ParseVisibilities(visibilities)
+}
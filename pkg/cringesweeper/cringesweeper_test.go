@@ -0,0 +1,27 @@
+package cringesweeper
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	for _, platform := range Platforms() {
+		client, ok := NewClient(platform)
+		if !ok || client == nil {
+			t.Errorf("NewClient(%q) returned no client", platform)
+		}
+	}
+	if _, ok := NewClient("twitter"); ok {
+		t.Error("Expected no prunable client for the read-only twitter platform")
+	}
+	if _, ok := NewReader("twitter"); !ok {
+		t.Error("Expected a reader for the twitter platform")
+	}
+}
+
+func ExampleParseHashtags() {
+	options := PruneOptions{PreserveHashtags: ParseHashtags("#Keep, portfolio,#keep")}
+	fmt.Println(options.PreserveHashtags)
+	// Output: [keep portfolio]
+}